@@ -38,7 +38,7 @@ func main() {
 	defer r.Stop(ctx)
 
 	// 3. Create the admin API handler.
-	handler := api.NewHandler(r.Store(), r.Catalog(), r.Endpoints(), r.DLQ(), logger)
+	handler := api.NewHandler(r.Store(), r.Catalog(), r.Endpoints(), r.DLQ(), logger, api.WithRelay(r))
 
 	// 4. Mount under /webhooks prefix.
 	mux := http.NewServeMux()
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/xraph/relay"
+	"github.com/xraph/relay/auth"
+	"github.com/xraph/relay/id"
+)
+
+// createAPIKeyResponse returns a new key together with its secret, which
+// cannot be read again.
+type createAPIKeyResponse struct {
+	*auth.APIKey
+	Key string `json:"key"`
+}
+
+func (h *Handler) createAPIKey(w http.ResponseWriter, r *http.Request) {
+	var req auth.KeyInput
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	key, secret, err := h.keySvc.CreateKey(r.Context(), req)
+	if err != nil {
+		writeAuthError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, createAPIKeyResponse{APIKey: key, Key: secret})
+}
+
+func (h *Handler) listAPIKeys(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := requestTenant(w, r, queryParam(r, "tenant_id"))
+	if !ok {
+		return
+	}
+
+	p, ok := readListParams(w, r, id.PrefixAPIKey)
+	if !ok {
+		return
+	}
+
+	opts := auth.ListOpts{
+		Cursor:   p.cursor,
+		Offset:   p.offset,
+		Limit:    p.limit + 1,
+		TenantID: tenantID,
+	}
+
+	keys, err := h.keySvc.ListKeys(r.Context(), opts)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writePage(w, p, keys, func(key *auth.APIKey) id.ID { return key.ID }, func() (int64, error) {
+		return h.keySvc.CountKeys(r.Context(), opts)
+	})
+}
+
+func (h *Handler) deleteAPIKey(w http.ResponseWriter, r *http.Request) {
+	keyID, err := id.ParseAPIKeyID(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid API key ID")
+		return
+	}
+
+	key, err := h.keySvc.GetKey(r.Context(), keyID)
+	if err == nil && !auth.CanAccess(r.Context(), key.TenantID) {
+		err = relay.ErrAPIKeyNotFound
+	}
+	if err == nil {
+		err = h.keySvc.DeleteKey(r.Context(), keyID)
+	}
+	if err != nil {
+		writeAuthError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeAuthError maps API key errors to HTTP statuses.
+func writeAuthError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, relay.ErrAPIKeyNotFound):
+		writeError(w, http.StatusNotFound, "API key not found")
+	case errors.Is(err, auth.ErrInvalidScope):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, auth.ErrForbidden):
+		writeError(w, http.StatusForbidden, err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/xraph/relay"
+	"github.com/xraph/relay/auth"
+	"github.com/xraph/relay/id"
+)
+
+// WithAuth requires every request to authenticate with a. Requests without
+// valid credentials are answered 401, and requests whose principal lacks
+// the scope a route needs 403. A principal bound to a tenant only sees and
+// changes that tenant's resources. Without WithAuth the API is open, so
+// mount it behind your own authentication.
+func WithAuth(a auth.Authenticator) HandlerOption {
+	return func(h *Handler) { h.authn = a }
+}
+
+// authenticate attaches the request's principal to its context.
+func (h *Handler) authenticate(next http.Handler) http.Handler {
+	if h.authn == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, err := h.authn.Authenticate(r)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="relay"`)
+			writeError(w, http.StatusUnauthorized, "authentication required")
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(auth.NewContext(r.Context(), p)))
+	})
+}
+
+// handle registers fn for pattern. With authentication enabled, callers
+// need scope.
+func (h *Handler) handle(pattern, scope string, fn http.HandlerFunc) {
+	if h.authn == nil {
+		h.mux.HandleFunc(pattern, fn)
+		return
+	}
+	h.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		if p, ok := auth.FromContext(r.Context()); !ok || !p.HasScope(scope) {
+			writeError(w, http.StatusForbidden, "missing scope "+scope)
+			return
+		}
+		fn(w, r)
+	})
+}
+
+// requestTenant resolves the tenant a request naming requested acts on
+// (see auth.TenantFor), answering 403 when the principal may not.
+func requestTenant(w http.ResponseWriter, r *http.Request, requested string) (string, bool) {
+	tenantID, err := auth.TenantFor(r.Context(), requested)
+	if err != nil {
+		writeError(w, http.StatusForbidden, "key is bound to another tenant")
+		return "", false
+	}
+	return tenantID, true
+}
+
+// requireUnbound answers 403 to tenant-bound principals, for operations
+// that span tenants.
+func requireUnbound(w http.ResponseWriter, r *http.Request) bool {
+	if !auth.Unbound(r.Context()) {
+		writeError(w, http.StatusForbidden, "operation spans tenants; key is bound to one")
+		return false
+	}
+	return true
+}
+
+// ownsEndpoint answers 404 unless the principal may access the endpoint,
+// so tenant-bound keys cannot probe for other tenants' endpoints.
+func (h *Handler) ownsEndpoint(w http.ResponseWriter, r *http.Request, epID id.ID) bool {
+	if auth.Unbound(r.Context()) {
+		return true
+	}
+	ep, err := h.endpointSvc.Get(r.Context(), epID)
+	if err != nil && !errors.Is(err, relay.ErrEndpointNotFound) {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return false
+	}
+	if err != nil || !auth.CanAccess(r.Context(), ep.TenantID) {
+		writeError(w, http.StatusNotFound, "endpoint not found")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,291 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/xraph/forge"
+
+	"github.com/xraph/relay"
+	"github.com/xraph/relay/auth"
+	"github.com/xraph/relay/delivery"
+	"github.com/xraph/relay/id"
+	"github.com/xraph/relay/job"
+	"github.com/xraph/relay/page"
+)
+
+// ForgeOption configures a ForgeAPI.
+type ForgeOption func(*ForgeAPI)
+
+// WithForgeAuth is the Forge counterpart of WithAuth: every route
+// authenticates with a and checks the scope it needs.
+func WithForgeAuth(a auth.Authenticator) ForgeOption {
+	return func(f *ForgeAPI) { f.authn = a }
+}
+
+// authMiddleware attaches the request's principal to its context.
+func (a *ForgeAPI) authMiddleware() forge.Middleware {
+	return func(next forge.Handler) forge.Handler {
+		if a.authn == nil {
+			return next
+		}
+		return func(ctx forge.Context) error {
+			p, err := a.authn.Authenticate(ctx.Request())
+			if err != nil {
+				ctx.SetHeader("WWW-Authenticate", `Bearer realm="relay"`)
+				return forge.Unauthorized("authentication required")
+			}
+			ctx.WithContext(auth.NewContext(ctx.Context(), p))
+			return next(ctx)
+		}
+	}
+}
+
+// require rejects principals without scope.
+func (a *ForgeAPI) require(scope string) forge.RouteOption {
+	return forge.WithMiddleware(func(next forge.Handler) forge.Handler {
+		if a.authn == nil {
+			return next
+		}
+		return func(ctx forge.Context) error {
+			if p, ok := auth.FromContext(ctx.Context()); !ok || !p.HasScope(scope) {
+				return forge.Forbidden("missing scope " + scope)
+			}
+			return next(ctx)
+		}
+	})
+}
+
+// groupMiddleware is the middleware every route group runs.
+func (a *ForgeAPI) groupMiddleware() forge.GroupOption {
+	return forge.WithGroupMiddleware(a.authMiddleware(), ForgeScopeMiddleware())
+}
+
+// forgeTenant resolves the tenant a request naming requested acts on.
+func forgeTenant(ctx context.Context, requested string) (string, error) {
+	tenantID, err := auth.TenantFor(ctx, requested)
+	if err != nil {
+		return "", forge.Forbidden("key is bound to another tenant")
+	}
+	return tenantID, nil
+}
+
+// forgeUnbound rejects tenant-bound principals, for operations that span
+// tenants.
+func forgeUnbound(ctx context.Context) error {
+	if !auth.Unbound(ctx) {
+		return forge.Forbidden("operation spans tenants; key is bound to one")
+	}
+	return nil
+}
+
+// ownsEndpoint reports the endpoint as missing unless the principal may
+// access it.
+func (a *ForgeAPI) ownsEndpoint(ctx context.Context, epID id.ID) error {
+	if auth.Unbound(ctx) {
+		return nil
+	}
+	ep, err := a.endpointSvc.Get(ctx, epID)
+	if err != nil {
+		return mapError(err)
+	}
+	if !auth.CanAccess(ctx, ep.TenantID) {
+		return forge.NotFound("endpoint not found")
+	}
+	return nil
+}
+
+// ownsTunnel reports the session as missing unless the principal may
+// access it.
+func (a *ForgeAPI) ownsTunnel(ctx context.Context, sessionID string) error {
+	if auth.Unbound(ctx) {
+		return nil
+	}
+	tenantID, err := a.relay.Tunnel().Tenant(sessionID)
+	if err != nil || !auth.CanAccess(ctx, tenantID) {
+		return forge.NotFound("tunnel session not found")
+	}
+	return nil
+}
+
+// ownsDLQEntry reports the entry as missing unless the principal may
+// access it.
+func (a *ForgeAPI) ownsDLQEntry(ctx context.Context, dlqID id.ID) error {
+	if auth.Unbound(ctx) {
+		return nil
+	}
+	entry, err := a.store.GetDLQ(ctx, dlqID)
+	if err != nil {
+		return mapError(err)
+	}
+	if !auth.CanAccess(ctx, entry.TenantID) {
+		return mapError(relay.ErrDLQNotFound)
+	}
+	return nil
+}
+
+// ownsDelivery returns the delivery, reported as missing unless the
+// principal may access its endpoint.
+func (a *ForgeAPI) ownsDelivery(ctx context.Context, delID id.ID) (*delivery.Delivery, error) {
+	d, err := a.store.GetDelivery(ctx, delID)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	if auth.Unbound(ctx) {
+		return d, nil
+	}
+	ep, err := a.endpointSvc.Get(ctx, d.EndpointID)
+	if err != nil || !auth.CanAccess(ctx, ep.TenantID) {
+		if err != nil && !errors.Is(err, relay.ErrEndpointNotFound) {
+			return nil, mapError(err)
+		}
+		return nil, mapError(relay.ErrDeliveryNotFound)
+	}
+	return d, nil
+}
+
+// ownsJob returns the job, reported as missing unless it is of kind and
+// the principal may access its tenant.
+func (a *ForgeAPI) ownsJob(ctx context.Context, rawID, kind string) (*job.Job, error) {
+	jobID, err := id.ParseJobID(rawID)
+	if err != nil {
+		return nil, forge.BadRequest("invalid job ID")
+	}
+	j, err := a.relay.Jobs().Get(jobID)
+	if err == nil && (j.Kind != kind || !auth.CanAccess(ctx, j.TenantID)) {
+		err = job.ErrNotFound
+	}
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return j, nil
+}
+
+// ---------------------------------------------------------------------------
+// API key routes
+// ---------------------------------------------------------------------------
+
+func (a *ForgeAPI) registerAPIKeyRoutes(router forge.Router) {
+	g := router.Group("/v1", forge.WithGroupTags("api-keys"), a.groupMiddleware())
+
+	if err := g.POST("/api-keys", a.createAPIKey,
+		forge.WithSummary("Create API key"),
+		forge.WithDescription("Creates an API key. The secret is only returned in this response."),
+		forge.WithOperationID("createAPIKey"),
+		a.require(auth.ScopeKeysAdmin),
+		forge.WithRequestSchema(CreateAPIKeyForgeRequest{}),
+		forge.WithCreatedResponse(createAPIKeyResponse{}),
+		forge.WithErrorResponses(),
+	); err != nil {
+		a.log.Error("Failed to register createAPIKey route", forge.Error(err))
+	}
+
+	if err := g.GET("/api-keys", a.listAPIKeys,
+		forge.WithSummary("List API keys"),
+		forge.WithDescription("Returns a paginated list of API keys, optionally filtered by tenant."),
+		forge.WithOperationID("listAPIKeys"),
+		a.require(auth.ScopeKeysAdmin),
+		forge.WithRequestSchema(ListAPIKeysForgeRequest{}),
+		forge.WithResponseSchema(http.StatusOK, "Page of API keys", page.Page[auth.APIKey]{}),
+		forge.WithErrorResponses(),
+	); err != nil {
+		a.log.Error("Failed to register listAPIKeys route", forge.Error(err))
+	}
+
+	if err := g.DELETE("/api-keys/:keyId", a.deleteAPIKey,
+		forge.WithSummary("Delete API key"),
+		forge.WithDescription("Revokes an API key."),
+		forge.WithOperationID("deleteAPIKey"),
+		a.require(auth.ScopeKeysAdmin),
+		forge.WithNoContentResponse(),
+		forge.WithErrorResponses(),
+	); err != nil {
+		a.log.Error("Failed to register deleteAPIKey route", forge.Error(err))
+	}
+}
+
+func (a *ForgeAPI) createAPIKey(ctx forge.Context, req *CreateAPIKeyForgeRequest) (*auth.APIKey, error) {
+	key, secret, err := a.keySvc.CreateKey(ctx.Context(), auth.KeyInput{
+		Name:      req.Name,
+		TenantID:  req.TenantID,
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	if err := ctx.JSON(http.StatusCreated, createAPIKeyResponse{APIKey: key, Key: secret}); err != nil {
+		return nil, mapError(err)
+	}
+
+	//nolint:nilnil // response already written via ctx.JSON.
+	return nil, nil
+}
+
+func (a *ForgeAPI) listAPIKeys(ctx forge.Context, req *ListAPIKeysForgeRequest) (*auth.APIKey, error) {
+	tenantID, err := forgeTenant(ctx.Context(), req.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = page.DefaultLimit
+	}
+	cursor, err := forgeCursor(req.Cursor, id.PrefixAPIKey)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := auth.ListOpts{
+		Cursor:   cursor,
+		Offset:   req.Offset,
+		Limit:    limit + 1,
+		TenantID: tenantID,
+	}
+
+	keys, err := a.keySvc.ListKeys(ctx.Context(), opts)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	result, err := forgePage(keys, limit, func(key *auth.APIKey) id.ID { return key.ID }, req.IncludeTotal == "true", func() (int64, error) {
+		return a.keySvc.CountKeys(ctx.Context(), opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.JSON(http.StatusOK, result); err != nil {
+		return nil, mapError(err)
+	}
+
+	//nolint:nilnil // response already written via ctx.JSON.
+	return nil, nil
+}
+
+func (a *ForgeAPI) deleteAPIKey(ctx forge.Context, req *DeleteAPIKeyForgeRequest) (*auth.APIKey, error) {
+	keyID, err := id.ParseAPIKeyID(req.KeyID)
+	if err != nil {
+		return nil, forge.BadRequest("invalid API key ID")
+	}
+
+	key, err := a.keySvc.GetKey(ctx.Context(), keyID)
+	if err == nil && !auth.CanAccess(ctx.Context(), key.TenantID) {
+		err = relay.ErrAPIKeyNotFound
+	}
+	if err == nil {
+		err = a.keySvc.DeleteKey(ctx.Context(), keyID)
+	}
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	if err := ctx.NoContent(http.StatusNoContent); err != nil {
+		return nil, mapError(err)
+	}
+
+	//nolint:nilnil // response already written via ctx.NoContent.
+	return nil, nil
+}
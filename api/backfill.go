@@ -0,0 +1,141 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/xraph/relay"
+	"github.com/xraph/relay/auth"
+	"github.com/xraph/relay/id"
+	"github.com/xraph/relay/job"
+)
+
+type backfillRequest struct {
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+	Type string `json:"type,omitempty"`
+	Rate int    `json:"rate,omitempty"`
+}
+
+// options converts the request to BackfillOptions, rejecting malformed
+// times.
+func (req backfillRequest) options() (relay.BackfillOptions, error) {
+	opts := relay.BackfillOptions{Type: req.Type, Rate: req.Rate}
+	var err error
+	if opts.From, err = optionalTime(req.From); err != nil {
+		return opts, err
+	}
+	opts.To, err = optionalTime(req.To)
+	return opts, err
+}
+
+// backfillEndpoint starts a job delivering past events to an endpoint.
+func (h *Handler) backfillEndpoint(w http.ResponseWriter, r *http.Request) {
+	if h.relay == nil {
+		writeError(w, http.StatusNotImplemented, "backfill requires a relay instance")
+		return
+	}
+
+	epID, err := id.ParseEndpointID(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid endpoint ID")
+		return
+	}
+	if !h.ownsEndpoint(w, r, epID) {
+		return
+	}
+
+	var req backfillRequest
+	if decodeErr := decodeJSON(r, &req); decodeErr != nil && !errors.Is(decodeErr, io.EOF) {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	opts, err := req.options()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid time format (use RFC3339)")
+		return
+	}
+
+	j, err := h.relay.Backfill(r.Context(), epID, opts)
+	if err != nil {
+		switch {
+		case errors.Is(err, relay.ErrEndpointNotFound):
+			writeError(w, http.StatusNotFound, "endpoint not found")
+		case errors.Is(err, relay.ErrEndpointDisabled):
+			writeError(w, http.StatusConflict, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, j)
+}
+
+func (h *Handler) getBackfill(w http.ResponseWriter, r *http.Request) {
+	j, ok := h.loadJob(w, r, job.KindBackfill)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, j)
+}
+
+func (h *Handler) cancelBackfill(w http.ResponseWriter, r *http.Request) {
+	h.cancelJob(w, r, job.KindBackfill)
+}
+
+// cancelJob cancels the job of kind named in the path and answers with
+// its latest snapshot.
+func (h *Handler) cancelJob(w http.ResponseWriter, r *http.Request, kind string) {
+	j, ok := h.loadJob(w, r, kind)
+	if !ok {
+		return
+	}
+
+	if err := h.relay.Jobs().Cancel(j.ID); err != nil {
+		writeJobError(w, err)
+		return
+	}
+	if latest, err := h.relay.Jobs().Get(j.ID); err == nil {
+		j = latest
+	}
+	writeJSON(w, http.StatusAccepted, j)
+}
+
+// loadJob fetches the job of kind named in the path, answering 404 unless
+// the principal may access its tenant.
+func (h *Handler) loadJob(w http.ResponseWriter, r *http.Request, kind string) (*job.Job, bool) {
+	if h.relay == nil {
+		writeError(w, http.StatusNotImplemented, "jobs require a relay instance")
+		return nil, false
+	}
+
+	jobID, err := id.ParseJobID(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid job ID")
+		return nil, false
+	}
+
+	j, err := h.relay.Jobs().Get(jobID)
+	if err == nil && (j.Kind != kind || !auth.CanAccess(r.Context(), j.TenantID)) {
+		err = job.ErrNotFound
+	}
+	if err != nil {
+		writeJobError(w, err)
+		return nil, false
+	}
+	return j, true
+}
+
+// writeJobError maps errors from the job runner to HTTP statuses.
+func writeJobError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, job.ErrNotFound):
+		writeError(w, http.StatusNotFound, "job not found")
+	case errors.Is(err, job.ErrNotRunning):
+		writeError(w, http.StatusConflict, err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	}
+}
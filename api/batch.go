@@ -0,0 +1,200 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/xraph/relay"
+	"github.com/xraph/relay/auth"
+	"github.com/xraph/relay/event"
+)
+
+// maxBatchEvents caps the number of events accepted by POST /events/batch.
+// Larger imports should be split across requests.
+const maxBatchEvents = 10000
+
+var errBatchTooLarge = fmt.Errorf("batch exceeds %d events", maxBatchEvents)
+
+type eventBatchItem struct {
+	Index     int      `json:"index"`
+	EventID   string   `json:"event_id,omitempty"`
+	Duplicate bool     `json:"duplicate,omitempty"`
+	Error     string   `json:"error,omitempty"`
+	Warnings  []string `json:"warnings,omitempty"`
+}
+
+type eventBatchResponse struct {
+	Accepted   int              `json:"accepted"`
+	Duplicates int              `json:"duplicates"`
+	Rejected   int              `json:"rejected"`
+	Results    []eventBatchItem `json:"results"`
+
+	// Error is the store failure that stopped the batch. Items it left
+	// unwritten carry it too; the other results stand.
+	Error string `json:"error,omitempty"`
+}
+
+// decodeEventBatch reads events from a JSON array or a stream of
+// newline-delimited JSON objects (NDJSON); the format is detected from the
+// first non-whitespace byte.
+func decodeEventBatch(body io.Reader) ([]createEventRequest, error) {
+	br := bufio.NewReader(body)
+	first, err := peekNonSpace(br)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("empty batch")
+		}
+		return nil, err
+	}
+
+	dec := json.NewDecoder(br)
+
+	if first == '[' {
+		// Decode element by element so an oversized array is rejected
+		// without being buffered.
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		var items []createEventRequest
+		for dec.More() {
+			var item createEventRequest
+			if err := dec.Decode(&item); err != nil {
+				return nil, fmt.Errorf("item %d: %w", len(items), err)
+			}
+			items = append(items, item)
+			if len(items) > maxBatchEvents {
+				return nil, errBatchTooLarge
+			}
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return items, nil
+	}
+
+	var items []createEventRequest
+	for {
+		var item createEventRequest
+		if err := dec.Decode(&item); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("line %d: %w", len(items)+1, err)
+		}
+		items = append(items, item)
+		if len(items) > maxBatchEvents {
+			return nil, errBatchTooLarge
+		}
+	}
+	return items, nil
+}
+
+// peekNonSpace skips leading whitespace and returns the next byte without
+// consuming it.
+func peekNonSpace(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		switch b {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		return b, br.UnreadByte()
+	}
+}
+
+// sendBatch runs decoded batch items through Relay.SendBatch and builds the
+// per-item response. Items missing required fields are rejected without
+// being sent. On a store failure the response is returned along with the
+// error, so callers can report which items were persisted.
+func sendBatch(ctx context.Context, rl *relay.Relay, items []createEventRequest) (*eventBatchResponse, error) {
+	resp := &eventBatchResponse{
+		Results: make([]eventBatchItem, len(items)),
+	}
+
+	evts := make([]*event.Event, 0, len(items))
+	positions := make([]int, 0, len(items))
+	for i, item := range items {
+		resp.Results[i].Index = i
+		tenantID, tenantErr := auth.TenantFor(ctx, item.TenantID)
+		switch {
+		case tenantErr != nil:
+			resp.Results[i].Error = "key is bound to another tenant"
+		case item.Type == "":
+			resp.Results[i].Error = "type is required"
+		case tenantID == "":
+			resp.Results[i].Error = "tenant_id is required"
+		default:
+			evts = append(evts, &event.Event{
+				Type:           item.Type,
+				TenantID:       tenantID,
+				Data:           item.Data,
+				Version:        item.Version,
+				IdempotencyKey: item.IdempotencyKey,
+			})
+			positions = append(positions, i)
+		}
+	}
+
+	results, err := rl.SendBatch(ctx, evts)
+	for j, res := range results {
+		out := &resp.Results[positions[j]]
+		switch {
+		case res.Err != nil:
+			out.Error = res.Err.Error()
+		case res.Duplicate:
+			out.Duplicate = true
+		default:
+			out.EventID = res.EventID.String()
+		}
+		out.Warnings = res.Warnings
+	}
+	if err != nil {
+		resp.Error = err.Error()
+	}
+
+	for _, res := range resp.Results {
+		switch {
+		case res.Error != "":
+			resp.Rejected++
+		case res.Duplicate:
+			resp.Duplicates++
+		default:
+			resp.Accepted++
+		}
+	}
+	return resp, err
+}
+
+func (h *Handler) sendEventBatch(w http.ResponseWriter, r *http.Request) {
+	if h.relay == nil {
+		writeError(w, http.StatusNotImplemented, "batch send requires a relay instance")
+		return
+	}
+
+	defer r.Body.Close()
+	items, err := decodeEventBatch(r.Body)
+	if err != nil {
+		if errors.Is(err, errBatchTooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, err.Error())
+			return
+		}
+		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	resp, err := sendBatch(r.Context(), h.relay, items)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, resp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
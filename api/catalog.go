@@ -0,0 +1,36 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/xraph/relay/catalog"
+)
+
+// docInfo reads the optional title and version query parameters of the
+// catalog export routes.
+func docInfo(r *http.Request) catalog.DocInfo {
+	return catalog.DocInfo{
+		Title:   queryParam(r, "title"),
+		Version: queryParam(r, "version"),
+	}
+}
+
+func (h *Handler) getAsyncAPI(w http.ResponseWriter, r *http.Request) {
+	doc, err := h.catalog.ExportAsyncAPI(r.Context(), docInfo(r))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, doc)
+}
+
+func (h *Handler) getOpenAPIWebhooks(w http.ResponseWriter, r *http.Request) {
+	doc, err := h.catalog.ExportOpenAPI(r.Context(), docInfo(r))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, doc)
+}
@@ -1,22 +1,116 @@
 package api
 
 import (
+	"errors"
 	"net/http"
+	"time"
 
+	"github.com/xraph/relay"
+	"github.com/xraph/relay/auth"
 	"github.com/xraph/relay/delivery"
 	"github.com/xraph/relay/id"
 )
 
+func (h *Handler) listAllDeliveries(w http.ResponseWriter, r *http.Request) {
+	p, ok := readListParams(w, r, id.PrefixDelivery)
+	if !ok {
+		return
+	}
+	opts, ok := readDeliveryFilters(w, r)
+	if !ok {
+		return
+	}
+	opts.Cursor = p.cursor
+	opts.Offset = p.offset
+	opts.Limit = p.limit + 1
+
+	tenantID, ok := requestTenant(w, r, queryParam(r, "tenant_id"))
+	if !ok {
+		return
+	}
+	opts.TenantID = tenantID
+
+	deliveries, err := h.store.ListDeliveries(r.Context(), opts)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writePage(w, p, deliveries, func(d *delivery.Delivery) id.ID { return d.ID }, func() (int64, error) {
+		return h.store.CountDeliveries(r.Context(), opts)
+	})
+}
+
+// readDeliveryFilters parses the endpoint_id, event_id, state, from and to
+// query parameters, writing a 400 and returning false if one is invalid.
+func readDeliveryFilters(w http.ResponseWriter, r *http.Request) (delivery.ListOpts, bool) {
+	var opts delivery.ListOpts
+
+	if s := queryParam(r, "endpoint_id"); s != "" {
+		epID, err := id.ParseEndpointID(s)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid endpoint ID")
+			return opts, false
+		}
+		opts.EndpointID = &epID
+	}
+	if s := queryParam(r, "event_id"); s != "" {
+		evtID, err := id.ParseEventID(s)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid event ID")
+			return opts, false
+		}
+		opts.EventID = &evtID
+	}
+	if s := queryParam(r, "state"); s != "" {
+		state := delivery.State(s)
+		opts.State = &state
+	}
+	var err error
+	if opts.From, err = optionalTime(queryParam(r, "from")); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid 'from' time format (use RFC3339)")
+		return opts, false
+	}
+	if opts.To, err = optionalTime(queryParam(r, "to")); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid 'to' time format (use RFC3339)")
+		return opts, false
+	}
+
+	return opts, true
+}
+
+// optionalTime parses an RFC 3339 time, or returns nil for an empty
+// string.
+func optionalTime(v string) (*time.Time, error) {
+	if v == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
 func (h *Handler) listDeliveries(w http.ResponseWriter, r *http.Request) {
 	epID, err := id.ParseEndpointID(r.PathValue("id"))
 	if err != nil {
 		writeError(w, http.StatusBadRequest, "invalid endpoint ID")
 		return
 	}
+	if !h.ownsEndpoint(w, r, epID) {
+		return
+	}
+
+	p, ok := readListParams(w, r, id.PrefixDelivery)
+	if !ok {
+		return
+	}
 
 	opts := delivery.ListOpts{
-		Offset: queryInt(r, "offset", 0),
-		Limit:  queryInt(r, "limit", 50),
+		Cursor: p.cursor,
+		Offset: p.offset,
+		Limit:  p.limit + 1,
 	}
 
 	stateStr := queryParam(r, "state")
@@ -31,5 +125,137 @@ func (h *Handler) listDeliveries(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	writeJSON(w, http.StatusOK, deliveries)
+	writePage(w, p, deliveries, func(d *delivery.Delivery) id.ID { return d.ID }, func() (int64, error) {
+		return h.store.CountByEndpoint(r.Context(), epID, opts)
+	})
+}
+
+func (h *Handler) listEventDeliveries(w http.ResponseWriter, r *http.Request) {
+	evtID, err := id.ParseEventID(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+
+	evt, getErr := h.store.GetEvent(r.Context(), evtID)
+	if getErr == nil && !auth.CanAccess(r.Context(), evt.TenantID) {
+		getErr = relay.ErrEventNotFound
+	}
+	if getErr != nil {
+		if errors.Is(getErr, relay.ErrEventNotFound) {
+			writeError(w, http.StatusNotFound, "event not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, getErr.Error())
+		return
+	}
+
+	p, ok := readListParams(w, r, id.PrefixDelivery)
+	if !ok {
+		return
+	}
+
+	opts := delivery.ListOpts{
+		Cursor:  p.cursor,
+		Offset:  p.offset,
+		Limit:   p.limit + 1,
+		EventID: &evtID,
+	}
+
+	deliveries, listErr := h.store.ListDeliveries(r.Context(), opts)
+	if listErr != nil {
+		writeError(w, http.StatusInternalServerError, listErr.Error())
+		return
+	}
+
+	writePage(w, p, deliveries, func(d *delivery.Delivery) id.ID { return d.ID }, func() (int64, error) {
+		return h.store.CountDeliveries(r.Context(), opts)
+	})
+}
+
+func (h *Handler) getDelivery(w http.ResponseWriter, r *http.Request) {
+	d, ok := h.loadDelivery(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, d)
+}
+
+func (h *Handler) retryDelivery(w http.ResponseWriter, r *http.Request) {
+	h.changeDelivery(w, r, func(delID id.ID) error {
+		if h.relay != nil {
+			return h.relay.RetryDelivery(r.Context(), delID)
+		}
+		return h.store.RetryDelivery(r.Context(), delID)
+	})
+}
+
+func (h *Handler) cancelDelivery(w http.ResponseWriter, r *http.Request) {
+	h.changeDelivery(w, r, func(delID id.ID) error {
+		if h.relay != nil {
+			return h.relay.CancelDelivery(r.Context(), delID)
+		}
+		return h.store.CancelDelivery(r.Context(), delID)
+	})
+}
+
+// changeDelivery applies change to the delivery named in the path and
+// writes the delivery as it is afterwards.
+func (h *Handler) changeDelivery(w http.ResponseWriter, r *http.Request, change func(id.ID) error) {
+	d, ok := h.loadDelivery(w, r)
+	if !ok {
+		return
+	}
+
+	if err := change(d.ID); err != nil {
+		writeDeliveryError(w, err)
+		return
+	}
+
+	d, err := h.store.GetDelivery(r.Context(), d.ID)
+	if err != nil {
+		writeDeliveryError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, d)
+}
+
+// loadDelivery fetches the delivery named in the path, answering 404
+// unless the principal may access its endpoint.
+func (h *Handler) loadDelivery(w http.ResponseWriter, r *http.Request) (*delivery.Delivery, bool) {
+	delID, err := id.ParseDeliveryID(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid delivery ID")
+		return nil, false
+	}
+
+	d, err := h.store.GetDelivery(r.Context(), delID)
+	if err != nil {
+		writeDeliveryError(w, err)
+		return nil, false
+	}
+	if !auth.Unbound(r.Context()) {
+		ep, epErr := h.endpointSvc.Get(r.Context(), d.EndpointID)
+		if epErr != nil && !errors.Is(epErr, relay.ErrEndpointNotFound) {
+			writeError(w, http.StatusInternalServerError, epErr.Error())
+			return nil, false
+		}
+		if epErr != nil || !auth.CanAccess(r.Context(), ep.TenantID) {
+			writeError(w, http.StatusNotFound, "delivery not found")
+			return nil, false
+		}
+	}
+	return d, true
+}
+
+// writeDeliveryError maps errors from delivery operations to HTTP statuses.
+func writeDeliveryError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, relay.ErrDeliveryNotFound):
+		writeError(w, http.StatusNotFound, "delivery not found")
+	case errors.Is(err, relay.ErrDeliveryNotPending):
+		writeError(w, http.StatusConflict, err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	}
 }
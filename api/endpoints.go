@@ -1,6 +1,7 @@
 package api
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 
@@ -10,12 +11,15 @@ import (
 )
 
 type createEndpointRequest struct {
-	TenantID   string            `json:"tenant_id"`
-	URL        string            `json:"url"`
-	EventTypes []string          `json:"event_types"`
-	Headers    map[string]string `json:"headers,omitempty"`
-	RateLimit  int               `json:"rate_limit,omitempty"`
-	Metadata   map[string]string `json:"metadata,omitempty"`
+	TenantID    string            `json:"tenant_id"`
+	URL         string            `json:"url"`
+	Description string            `json:"description,omitempty"`
+	Secret      string            `json:"secret,omitempty"`
+	EventTypes  []string          `json:"event_types"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	RateLimit   int               `json:"rate_limit,omitempty"`
+	Version     string            `json:"version,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
 }
 
 type updateEndpointRequest struct {
@@ -23,6 +27,7 @@ type updateEndpointRequest struct {
 	EventTypes []string          `json:"event_types"`
 	Headers    map[string]string `json:"headers,omitempty"`
 	RateLimit  int               `json:"rate_limit,omitempty"`
+	Version    *string           `json:"version,omitempty"`
 	Metadata   map[string]string `json:"metadata,omitempty"`
 }
 
@@ -33,13 +38,21 @@ func (h *Handler) createEndpoint(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	tenantID, ok := requestTenant(w, r, req.TenantID)
+	if !ok {
+		return
+	}
+
 	input := endpoint.Input{
-		TenantID:   req.TenantID,
-		URL:        req.URL,
-		EventTypes: req.EventTypes,
-		Headers:    req.Headers,
-		RateLimit:  req.RateLimit,
-		Metadata:   req.Metadata,
+		TenantID:    tenantID,
+		URL:         req.URL,
+		Description: req.Description,
+		Secret:      req.Secret,
+		EventTypes:  req.EventTypes,
+		Headers:     req.Headers,
+		RateLimit:   req.RateLimit,
+		Version:     &req.Version,
+		Metadata:    req.Metadata,
 	}
 
 	ep, err := h.endpointSvc.Create(r.Context(), input)
@@ -52,15 +65,26 @@ func (h *Handler) createEndpoint(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) listEndpoints(w http.ResponseWriter, r *http.Request) {
-	tenantID := queryParam(r, "tenant_id")
+	tenantID, ok := requestTenant(w, r, queryParam(r, "tenant_id"))
+	if !ok {
+		return
+	}
 	if tenantID == "" {
 		writeError(w, http.StatusBadRequest, "tenant_id query parameter is required")
 		return
 	}
 
+	p, ok := readListParams(w, r, id.PrefixEndpoint)
+	if !ok {
+		return
+	}
+
 	opts := endpoint.ListOpts{
-		Offset: queryInt(r, "offset", 0),
-		Limit:  queryInt(r, "limit", 50),
+		Cursor:     p.cursor,
+		Offset:     p.offset,
+		Limit:      p.limit + 1,
+		ScopeAppID: queryParam(r, "scope_app_id"),
+		ScopeOrgID: queryParam(r, "scope_org_id"),
 	}
 
 	eps, err := h.endpointSvc.List(r.Context(), tenantID, opts)
@@ -69,7 +93,9 @@ func (h *Handler) listEndpoints(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	writeJSON(w, http.StatusOK, eps)
+	writePage(w, p, eps, func(ep *endpoint.Endpoint) id.ID { return ep.ID }, func() (int64, error) {
+		return h.endpointSvc.Count(r.Context(), tenantID, opts)
+	})
 }
 
 func (h *Handler) getEndpoint(w http.ResponseWriter, r *http.Request) {
@@ -78,6 +104,9 @@ func (h *Handler) getEndpoint(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "invalid endpoint ID")
 		return
 	}
+	if !h.ownsEndpoint(w, r, epID) {
+		return
+	}
 
 	ep, getErr := h.endpointSvc.Get(r.Context(), epID)
 	if getErr != nil {
@@ -98,6 +127,9 @@ func (h *Handler) updateEndpoint(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "invalid endpoint ID")
 		return
 	}
+	if !h.ownsEndpoint(w, r, epID) {
+		return
+	}
 
 	var req updateEndpointRequest
 	if err := decodeJSON(r, &req); err != nil {
@@ -110,6 +142,7 @@ func (h *Handler) updateEndpoint(w http.ResponseWriter, r *http.Request) {
 		EventTypes: req.EventTypes,
 		Headers:    req.Headers,
 		RateLimit:  req.RateLimit,
+		Version:    req.Version,
 		Metadata:   req.Metadata,
 	}
 
@@ -132,6 +165,9 @@ func (h *Handler) deleteEndpoint(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "invalid endpoint ID")
 		return
 	}
+	if !h.ownsEndpoint(w, r, epID) {
+		return
+	}
 
 	if deleteErr := h.endpointSvc.Delete(r.Context(), epID); deleteErr != nil {
 		if errors.Is(deleteErr, relay.ErrEndpointNotFound) {
@@ -151,12 +187,20 @@ func (h *Handler) enableEndpoint(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "invalid endpoint ID")
 		return
 	}
+	if !h.ownsEndpoint(w, r, epID) {
+		return
+	}
 
 	if setErr := h.endpointSvc.SetEnabled(r.Context(), epID, true); setErr != nil {
 		if errors.Is(setErr, relay.ErrEndpointNotFound) {
 			writeError(w, http.StatusNotFound, "endpoint not found")
 			return
 		}
+		var ve *endpoint.ValidationError
+		if errors.As(setErr, &ve) {
+			writeError(w, http.StatusConflict, setErr.Error())
+			return
+		}
 		writeError(w, http.StatusInternalServerError, setErr.Error())
 		return
 	}
@@ -170,6 +214,9 @@ func (h *Handler) disableEndpoint(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "invalid endpoint ID")
 		return
 	}
+	if !h.ownsEndpoint(w, r, epID) {
+		return
+	}
 
 	if setErr := h.endpointSvc.SetEnabled(r.Context(), epID, false); setErr != nil {
 		if errors.Is(setErr, relay.ErrEndpointNotFound) {
@@ -189,6 +236,9 @@ func (h *Handler) rotateSecret(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "invalid endpoint ID")
 		return
 	}
+	if !h.ownsEndpoint(w, r, epID) {
+		return
+	}
 
 	newSecret, rotateErr := h.endpointSvc.RotateSecret(r.Context(), epID)
 	if rotateErr != nil {
@@ -202,3 +252,109 @@ func (h *Handler) rotateSecret(w http.ResponseWriter, r *http.Request) {
 
 	writeJSON(w, http.StatusOK, map[string]string{"secret": newSecret})
 }
+
+// verifyEndpoint runs the URL verification handshake and returns the
+// endpoint, enabled if it was pending verification.
+func (h *Handler) verifyEndpoint(w http.ResponseWriter, r *http.Request) {
+	epID, err := id.ParseEndpointID(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid endpoint ID")
+		return
+	}
+	if !h.ownsEndpoint(w, r, epID) {
+		return
+	}
+
+	ep, err := h.endpointSvc.Verify(r.Context(), epID)
+	if err != nil {
+		switch {
+		case errors.Is(err, relay.ErrEndpointNotFound):
+			writeError(w, http.StatusNotFound, "endpoint not found")
+		case errors.Is(err, endpoint.ErrVerificationFailed):
+			writeError(w, http.StatusUnprocessableEntity, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ep)
+}
+
+type testEndpointRequest struct {
+	EventType string          `json:"event_type"`
+	Data      json.RawMessage `json:"data,omitempty"`
+}
+
+// testEndpoint sends a test event to one endpoint and returns its response.
+// The response is 200 whether or not the endpoint accepted it; see success.
+func (h *Handler) testEndpoint(w http.ResponseWriter, r *http.Request) {
+	if h.relay == nil {
+		writeError(w, http.StatusNotImplemented, "test sends require a relay instance")
+		return
+	}
+
+	epID, err := id.ParseEndpointID(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid endpoint ID")
+		return
+	}
+	if !h.ownsEndpoint(w, r, epID) {
+		return
+	}
+
+	var req testEndpointRequest
+	if decodeErr := decodeJSON(r, &req); decodeErr != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.EventType == "" {
+		writeError(w, http.StatusBadRequest, "event_type is required")
+		return
+	}
+
+	res, err := h.relay.SendTest(r.Context(), epID, req.EventType, req.Data)
+	if err != nil {
+		switch {
+		case errors.Is(err, relay.ErrEndpointNotFound):
+			writeError(w, http.StatusNotFound, "endpoint not found")
+		case errors.Is(err, relay.ErrNoTestPayload):
+			writeError(w, http.StatusBadRequest, err.Error())
+		default:
+			writeSendError(w, err)
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+// getEndpointHealth returns an endpoint's delivery health and, if the
+// engine disabled it, why.
+func (h *Handler) getEndpointHealth(w http.ResponseWriter, r *http.Request) {
+	if h.relay == nil {
+		writeError(w, http.StatusNotImplemented, "endpoint health requires a relay instance")
+		return
+	}
+
+	epID, err := id.ParseEndpointID(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid endpoint ID")
+		return
+	}
+	if !h.ownsEndpoint(w, r, epID) {
+		return
+	}
+
+	eh, err := h.relay.EndpointHealth(r.Context(), epID)
+	if err != nil {
+		if errors.Is(err, relay.ErrEndpointNotFound) {
+			writeError(w, http.StatusNotFound, "endpoint not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, eh)
+}
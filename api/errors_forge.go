@@ -7,6 +7,11 @@ import (
 	"github.com/xraph/forge"
 
 	"github.com/xraph/relay"
+	"github.com/xraph/relay/auth"
+	"github.com/xraph/relay/dlq"
+	"github.com/xraph/relay/endpoint"
+	"github.com/xraph/relay/job"
+	"github.com/xraph/relay/tunnel"
 )
 
 // mapError converts relay sentinel errors to Forge HTTP errors.
@@ -16,19 +21,45 @@ func mapError(err error) error {
 		return forge.NotFound(err.Error())
 	case errors.Is(err, relay.ErrEventTypeNotFound):
 		return forge.NotFound(err.Error())
+	case errors.Is(err, relay.ErrEventVersionNotFound):
+		return forge.BadRequest(err.Error())
 	case errors.Is(err, relay.ErrEventNotFound):
 		return forge.NotFound(err.Error())
 	case errors.Is(err, relay.ErrDeliveryNotFound):
 		return forge.NotFound(err.Error())
+	case errors.Is(err, relay.ErrDeliveryNotPending):
+		return forge.NewHTTPError(http.StatusConflict, err.Error())
 	case errors.Is(err, relay.ErrDLQNotFound):
 		return forge.NotFound(err.Error())
+	case errors.Is(err, dlq.ErrAlreadyReplayed), errors.Is(err, dlq.ErrReplayLimit):
+		return forge.NewHTTPError(http.StatusConflict, err.Error())
 	case errors.Is(err, relay.ErrEventTypeDeprecated):
 		return forge.NewHTTPError(http.StatusConflict, err.Error())
 	case errors.Is(err, relay.ErrPayloadValidationFailed):
 		return forge.BadRequest(err.Error())
+	case errors.Is(err, relay.ErrHookRejected):
+		return forge.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, endpoint.ErrVerificationFailed):
+		return forge.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, relay.ErrNoTestPayload):
+		return forge.BadRequest(err.Error())
 	case errors.Is(err, relay.ErrDuplicateIdempotencyKey):
 		return forge.NewHTTPError(http.StatusConflict, err.Error())
-	case errors.Is(err, relay.ErrEndpointDisabled):
+	case errors.Is(err, relay.ErrEndpointDisabled), errors.Is(err, relay.ErrTestEvent):
+		return forge.NewHTTPError(http.StatusConflict, err.Error())
+	case errors.Is(err, relay.ErrAPIKeyNotFound):
+		return forge.NotFound(err.Error())
+	case errors.Is(err, auth.ErrInvalidScope):
+		return forge.BadRequest(err.Error())
+	case errors.Is(err, auth.ErrForbidden):
+		return forge.Forbidden(err.Error())
+	case errors.Is(err, job.ErrNotFound):
+		return forge.NotFound(err.Error())
+	case errors.Is(err, job.ErrNotRunning):
+		return forge.NewHTTPError(http.StatusConflict, err.Error())
+	case errors.Is(err, tunnel.ErrSessionNotFound):
+		return forge.NotFound(err.Error())
+	case errors.Is(err, tunnel.ErrUnknownRequest):
 		return forge.NewHTTPError(http.StatusConflict, err.Error())
 	case errors.Is(err, relay.ErrNoStore):
 		return forge.InternalError(err)
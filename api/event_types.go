@@ -4,9 +4,14 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"slices"
+	"time"
 
 	"github.com/xraph/relay"
+	"github.com/xraph/relay/auth"
 	"github.com/xraph/relay/catalog"
+	"github.com/xraph/relay/endpoint"
+	"github.com/xraph/relay/id"
 )
 
 type createEventTypeRequest struct {
@@ -21,6 +26,10 @@ type createEventTypeRequest struct {
 }
 
 func (h *Handler) createEventType(w http.ResponseWriter, r *http.Request) {
+	if !requireUnbound(w, r) {
+		return
+	}
+
 	var req createEventTypeRequest
 	if err := decodeJSON(r, &req); err != nil {
 		writeError(w, http.StatusBadRequest, "invalid request body")
@@ -48,9 +57,20 @@ func (h *Handler) createEventType(w http.ResponseWriter, r *http.Request) {
 	if req.Metadata != nil {
 		opts = append(opts, catalog.WithMetadata(req.Metadata))
 	}
+	if queryParam(r, "force") == "true" {
+		opts = append(opts, catalog.WithCompatPolicy(catalog.CompatPolicyAllow))
+	}
 
 	et, err := h.catalog.RegisterType(r.Context(), def, opts...)
 	if err != nil {
+		var incompatible *catalog.IncompatibleSchemaError
+		if errors.As(err, &incompatible) {
+			writeJSON(w, http.StatusConflict, incompatibleSchemaResponse{
+				Error:  err.Error(),
+				Report: incompatible.Report,
+			})
+			return
+		}
 		writeError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -58,12 +78,56 @@ func (h *Handler) createEventType(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusCreated, et)
 }
 
+// incompatibleSchemaResponse is the 409 body returned when the reject
+// policy refuses a schema change. Retry with ?force=true to register it
+// anyway.
+type incompatibleSchemaResponse struct {
+	Error  string                `json:"error"`
+	Report *catalog.CompatReport `json:"report"`
+}
+
+type checkCompatibilityRequest struct {
+	Version string          `json:"version,omitempty"`
+	Schema  json.RawMessage `json:"schema,omitempty"`
+}
+
+func (h *Handler) checkEventTypeCompatibility(w http.ResponseWriter, r *http.Request) {
+	var req checkCompatibilityRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if len(req.Schema) > 0 && !json.Valid(req.Schema) {
+		writeError(w, http.StatusBadRequest, "schema must be valid JSON")
+		return
+	}
+
+	report, err := h.catalog.CheckCompatibility(r.Context(), catalog.WebhookDefinition{
+		Name:    r.PathValue("name"),
+		Version: req.Version,
+		Schema:  req.Schema,
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
+
 func (h *Handler) listEventTypes(w http.ResponseWriter, r *http.Request) {
+	p, ok := readListParams(w, r, id.PrefixEventType)
+	if !ok {
+		return
+	}
+
 	opts := catalog.ListOpts{
-		Offset:            queryInt(r, "offset", 0),
-		Limit:             queryInt(r, "limit", 50),
+		Cursor:            p.cursor,
+		Offset:            p.offset,
+		Limit:             p.limit + 1,
 		Group:             queryParam(r, "group"),
 		IncludeDeprecated: queryParam(r, "include_deprecated") == "true",
+		ScopeAppID:        queryParam(r, "scope_app_id"),
 	}
 
 	types, err := h.catalog.ListTypes(r.Context(), opts)
@@ -72,7 +136,9 @@ func (h *Handler) listEventTypes(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	writeJSON(w, http.StatusOK, types)
+	writePage(w, p, types, func(et *catalog.EventType) id.ID { return et.ID }, func() (int64, error) {
+		return h.catalog.CountTypes(r.Context(), opts)
+	})
 }
 
 func (h *Handler) getEventType(w http.ResponseWriter, r *http.Request) {
@@ -92,6 +158,10 @@ func (h *Handler) getEventType(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) deleteEventType(w http.ResponseWriter, r *http.Request) {
+	if !requireUnbound(w, r) {
+		return
+	}
+
 	name := r.PathValue("name")
 
 	err := h.catalog.DeleteType(r.Context(), name)
@@ -106,3 +176,79 @@ func (h *Handler) deleteEventType(w http.ResponseWriter, r *http.Request) {
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+type deprecateEventTypeRequest struct {
+	SunsetAt string `json:"sunset_at"`
+}
+
+func (h *Handler) deprecateEventType(w http.ResponseWriter, r *http.Request) {
+	if !requireUnbound(w, r) {
+		return
+	}
+
+	var req deprecateEventTypeRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	sunsetAt, err := time.Parse(time.RFC3339, req.SunsetAt)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "sunset_at must be an RFC3339 time")
+		return
+	}
+
+	et, err := h.catalog.Deprecate(r.Context(), r.PathValue("name"), sunsetAt)
+	if err != nil {
+		if errors.Is(err, relay.ErrEventTypeNotFound) {
+			writeError(w, http.StatusNotFound, "event type not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, et)
+}
+
+func (h *Handler) undeprecateEventType(w http.ResponseWriter, r *http.Request) {
+	if !requireUnbound(w, r) {
+		return
+	}
+
+	et, err := h.catalog.Undeprecate(r.Context(), r.PathValue("name"))
+	if err != nil {
+		if errors.Is(err, relay.ErrEventTypeNotFound) {
+			writeError(w, http.StatusNotFound, "event type not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, et)
+}
+
+func (h *Handler) listEventTypeSubscribers(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+
+	if _, err := h.catalog.GetType(r.Context(), name); err != nil {
+		if errors.Is(err, relay.ErrEventTypeNotFound) {
+			writeError(w, http.StatusNotFound, "event type not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	eps, err := h.endpointSvc.Subscribers(r.Context(), name)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	eps = slices.DeleteFunc(eps, func(ep *endpoint.Endpoint) bool {
+		return !auth.CanAccess(r.Context(), ep.TenantID)
+	})
+
+	writeJSON(w, http.StatusOK, eps)
+}
@@ -3,9 +3,12 @@ package api
 import (
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/xraph/relay"
+	"github.com/xraph/relay/auth"
 	"github.com/xraph/relay/event"
 	"github.com/xraph/relay/id"
 )
@@ -14,6 +17,7 @@ type createEventRequest struct {
 	Type           string          `json:"type"`
 	TenantID       string          `json:"tenant_id"`
 	Data           json.RawMessage `json:"data"`
+	Version        string          `json:"version,omitempty"`
 	IdempotencyKey string          `json:"idempotency_key,omitempty"`
 }
 
@@ -28,16 +32,31 @@ func (h *Handler) createEvent(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "type is required")
 		return
 	}
+	tenantID, ok := requestTenant(w, r, req.TenantID)
+	if !ok {
+		return
+	}
+	req.TenantID = tenantID
 	if req.TenantID == "" {
 		writeError(w, http.StatusBadRequest, "tenant_id is required")
 		return
 	}
 
+	if queryParam(r, "dry_run") == "true" {
+		h.dryRunEvent(w, r, req)
+		return
+	}
+	if h.relay != nil {
+		h.sendEvent(w, r, req)
+		return
+	}
+
 	evt := &event.Event{
 		ID:             id.NewEventID(),
 		Type:           req.Type,
 		TenantID:       req.TenantID,
 		Data:           req.Data,
+		Version:        req.Version,
 		IdempotencyKey: req.IdempotencyKey,
 	}
 
@@ -49,20 +68,114 @@ func (h *Handler) createEvent(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusAccepted, evt)
 }
 
+// sendEvent sends an event through the relay, so it is validated against
+// the catalog and fanned out to its endpoints.
+func (h *Handler) sendEvent(w http.ResponseWriter, r *http.Request, req createEventRequest) {
+	res, err := h.relay.SendWithResult(r.Context(), &event.Event{
+		Type:           req.Type,
+		TenantID:       req.TenantID,
+		Data:           req.Data,
+		Version:        req.Version,
+		IdempotencyKey: req.IdempotencyKey,
+	})
+	if err != nil {
+		writeSendError(w, err)
+		return
+	}
+
+	addWarnings(w.Header(), res.Warnings)
+	writeJSON(w, http.StatusAccepted, res)
+}
+
+// dryRunEvent validates an event and resolves its endpoints without
+// persisting anything.
+func (h *Handler) dryRunEvent(w http.ResponseWriter, r *http.Request, req createEventRequest) {
+	if h.relay == nil {
+		writeError(w, http.StatusNotImplemented, "dry run requires a relay instance")
+		return
+	}
+
+	res, err := h.relay.SendWithResult(r.Context(), &event.Event{
+		Type:           req.Type,
+		TenantID:       req.TenantID,
+		Data:           req.Data,
+		Version:        req.Version,
+		IdempotencyKey: req.IdempotencyKey,
+	}, relay.DryRun())
+	if err != nil {
+		writeSendError(w, err)
+		return
+	}
+
+	addWarnings(w.Header(), res.Warnings)
+	writeJSON(w, http.StatusOK, res)
+}
+
+// addWarnings reports send warnings as Warning headers (RFC 9111 code 299,
+// "miscellaneous persistent warning") so producers see them without parsing
+// the body.
+func addWarnings(h http.Header, warnings []string) {
+	for _, w := range warnings {
+		h.Add("Warning", "299 relay "+strconv.Quote(w))
+	}
+}
+
+// writeSendError maps errors from the send pipeline to HTTP statuses.
+func writeSendError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, relay.ErrEventTypeNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, relay.ErrEventVersionNotFound):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, relay.ErrEventTypeDeprecated):
+		writeError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, relay.ErrPayloadValidationFailed):
+		writeError(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, relay.ErrHookRejected):
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+	default:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
 func (h *Handler) listEvents(w http.ResponseWriter, r *http.Request) {
+	p, ok := readListParams(w, r, id.PrefixEvent)
+	if !ok {
+		return
+	}
+
 	opts := event.ListOpts{
-		Offset: queryInt(r, "offset", 0),
-		Limit:  queryInt(r, "limit", 50),
-		Type:   queryParam(r, "type"),
+		Cursor:     p.cursor,
+		Offset:     p.offset,
+		Limit:      p.limit + 1,
+		Type:       queryParam(r, "type"),
+		ScopeAppID: queryParam(r, "scope_app_id"),
+		ScopeOrgID: queryParam(r, "scope_org_id"),
+	}
+
+	tenantID, ok := requestTenant(w, r, queryParam(r, "tenant_id"))
+	if !ok {
+		return
 	}
 
-	events, err := h.store.ListEvents(r.Context(), opts)
+	var events []*event.Event
+	var err error
+	if tenantID != "" {
+		events, err = h.store.ListEventsByTenant(r.Context(), tenantID, opts)
+	} else {
+		events, err = h.store.ListEvents(r.Context(), opts)
+	}
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	writeJSON(w, http.StatusOK, events)
+	writePage(w, p, events, func(evt *event.Event) id.ID { return evt.ID }, func() (int64, error) {
+		if tenantID != "" {
+			return h.store.CountEventsByTenant(r.Context(), tenantID, opts)
+		}
+		return h.store.CountEvents(r.Context(), opts)
+	})
 }
 
 func (h *Handler) getEvent(w http.ResponseWriter, r *http.Request) {
@@ -73,6 +186,9 @@ func (h *Handler) getEvent(w http.ResponseWriter, r *http.Request) {
 	}
 
 	evt, getErr := h.store.GetEvent(r.Context(), evtID)
+	if getErr == nil && !auth.CanAccess(r.Context(), evt.TenantID) {
+		getErr = relay.ErrEventNotFound
+	}
 	if getErr != nil {
 		if errors.Is(getErr, relay.ErrEventNotFound) {
 			writeError(w, http.StatusNotFound, "event not found")
@@ -84,3 +200,65 @@ func (h *Handler) getEvent(w http.ResponseWriter, r *http.Request) {
 
 	writeJSON(w, http.StatusOK, evt)
 }
+
+type resendEventRequest struct {
+	EndpointID string `json:"endpoint_id,omitempty"`
+}
+
+// resendEvent delivers a stored event again, to its currently subscribed
+// endpoints or to the endpoint named in the body.
+func (h *Handler) resendEvent(w http.ResponseWriter, r *http.Request) {
+	if h.relay == nil {
+		writeError(w, http.StatusNotImplemented, "resend requires a relay instance")
+		return
+	}
+
+	evtID, err := id.ParseEventID(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid event ID")
+		return
+	}
+
+	var req resendEventRequest
+	if decodeErr := decodeJSON(r, &req); decodeErr != nil && !errors.Is(decodeErr, io.EOF) {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	var endpointIDs []id.ID
+	if req.EndpointID != "" {
+		epID, parseErr := id.ParseEndpointID(req.EndpointID)
+		if parseErr != nil {
+			writeError(w, http.StatusBadRequest, "invalid endpoint ID")
+			return
+		}
+		endpointIDs = append(endpointIDs, epID)
+	}
+
+	evt, getErr := h.store.GetEvent(r.Context(), evtID)
+	if getErr == nil && !auth.CanAccess(r.Context(), evt.TenantID) {
+		getErr = relay.ErrEventNotFound
+	}
+	if getErr != nil {
+		if errors.Is(getErr, relay.ErrEventNotFound) {
+			writeError(w, http.StatusNotFound, "event not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, getErr.Error())
+		return
+	}
+
+	res, err := h.relay.Resend(r.Context(), evtID, endpointIDs...)
+	if err != nil {
+		switch {
+		case errors.Is(err, relay.ErrEndpointNotFound):
+			writeError(w, http.StatusNotFound, "endpoint not found")
+		case errors.Is(err, relay.ErrEndpointDisabled), errors.Is(err, relay.ErrTestEvent):
+			writeError(w, http.StatusConflict, err.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, res)
+}
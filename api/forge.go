@@ -1,19 +1,26 @@
 package api
 
 import (
+	"encoding/json"
+	"errors"
 	"net/http"
+	"slices"
 	"time"
 
 	"github.com/xraph/forge"
 
 	"github.com/xraph/relay"
+	"github.com/xraph/relay/auth"
 	"github.com/xraph/relay/catalog"
 	"github.com/xraph/relay/delivery"
 	"github.com/xraph/relay/dlq"
 	"github.com/xraph/relay/endpoint"
 	"github.com/xraph/relay/event"
 	"github.com/xraph/relay/id"
+	"github.com/xraph/relay/job"
+	"github.com/xraph/relay/page"
 	"github.com/xraph/relay/store"
+	"github.com/xraph/relay/tunnel"
 )
 
 // ForgeAPI wires all Forge-style HTTP handlers together.
@@ -23,6 +30,8 @@ type ForgeAPI struct {
 	endpointSvc *endpoint.Service
 	dlqSvc      *dlq.Service
 	relay       *relay.Relay
+	keySvc      *auth.Service
+	authn       auth.Authenticator
 	log         forge.Logger
 }
 
@@ -34,26 +43,37 @@ func NewForgeAPI(
 	dlqSvc *dlq.Service,
 	r *relay.Relay,
 	log forge.Logger,
+	opts ...ForgeOption,
 ) *ForgeAPI {
-	return &ForgeAPI{
+	a := &ForgeAPI{
 		store:       s,
 		catalog:     cat,
 		endpointSvc: epSvc,
 		dlqSvc:      dlqSvc,
 		relay:       r,
+		keySvc:      auth.NewService(s, log),
 		log:         log,
 	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
 }
 
 // RegisterRoutes registers all Relay admin API routes into the given Forge router
-// with full OpenAPI metadata.
+// with full OpenAPI metadata. The routes run ForgeScopeMiddleware, so what
+// they create is stamped with the request's forge.Scope. With WithForgeAuth
+// they also authenticate and check scopes like Handler does.
 func (a *ForgeAPI) RegisterRoutes(router forge.Router) {
 	a.registerEventTypeRoutes(router)
+	a.registerCatalogRoutes(router)
 	a.registerEndpointRoutes(router)
 	a.registerEventRoutes(router)
 	a.registerDeliveryRoutes(router)
 	a.registerDLQRoutes(router)
 	a.registerStatsRoutes(router)
+	a.registerTunnelRoutes(router)
+	a.registerAPIKeyRoutes(router)
 }
 
 // ---------------------------------------------------------------------------
@@ -61,12 +81,13 @@ func (a *ForgeAPI) RegisterRoutes(router forge.Router) {
 // ---------------------------------------------------------------------------
 
 func (a *ForgeAPI) registerEventTypeRoutes(router forge.Router) {
-	g := router.Group("/v1", forge.WithGroupTags("event-types"))
+	g := router.Group("/v1", forge.WithGroupTags("event-types"), a.groupMiddleware())
 
 	if err := g.POST("/event-types", a.createEventType,
 		forge.WithSummary("Register event type"),
 		forge.WithDescription("Registers a new webhook event type in the catalog."),
 		forge.WithOperationID("createEventType"),
+		a.require(auth.ScopeCatalogAdmin),
 		forge.WithRequestSchema(CreateEventTypeForgeRequest{}),
 		forge.WithCreatedResponse(catalog.EventType{}),
 		forge.WithErrorResponses(),
@@ -81,8 +102,9 @@ func (a *ForgeAPI) registerEventTypeRoutes(router forge.Router) {
 		forge.WithSummary("List event types"),
 		forge.WithDescription("Returns a paginated list of registered event types."),
 		forge.WithOperationID("listEventTypes"),
+		a.require(auth.ScopeCatalogRead),
 		forge.WithRequestSchema(ListEventTypesForgeRequest{}),
-		forge.WithListResponse(catalog.EventType{}, http.StatusOK),
+		forge.WithResponseSchema(http.StatusOK, "Page of event types", page.Page[catalog.EventType]{}),
 		forge.WithErrorResponses(),
 	); err != nil {
 		a.log.Error("Failed to register listEventTypes route", forge.Error(err))
@@ -92,6 +114,7 @@ func (a *ForgeAPI) registerEventTypeRoutes(router forge.Router) {
 		forge.WithSummary("Get event type"),
 		forge.WithDescription("Returns details of a specific event type."),
 		forge.WithOperationID("getEventType"),
+		a.require(auth.ScopeCatalogRead),
 		forge.WithRequestSchema(GetEventTypeForgeRequest{}),
 		forge.WithResponseSchema(http.StatusOK, "Event type details", catalog.EventType{}),
 		forge.WithErrorResponses(),
@@ -103,15 +126,67 @@ func (a *ForgeAPI) registerEventTypeRoutes(router forge.Router) {
 		forge.WithSummary("Deprecate event type"),
 		forge.WithDescription("Soft-deletes an event type. Sending events of this type will fail."),
 		forge.WithOperationID("deleteEventType"),
+		a.require(auth.ScopeCatalogAdmin),
 		forge.WithRequestSchema(DeleteEventTypeForgeRequest{}),
 		forge.WithNoContentResponse(),
 		forge.WithErrorResponses(),
 	); err != nil {
 		a.log.Error("Failed to register deleteEventType route", forge.Error(err))
 	}
+
+	if err := g.POST("/event-types/:name/deprecate", a.deprecateEventType,
+		forge.WithSummary("Deprecate event type with a sunset date"),
+		forge.WithDescription("Deprecates an event type. Events are still accepted, with a warning, and delivered with Deprecation and Sunset headers until sunset_at."),
+		forge.WithOperationID("deprecateEventType"),
+		a.require(auth.ScopeCatalogAdmin),
+		forge.WithRequestSchema(DeprecateEventTypeForgeRequest{}),
+		forge.WithResponseSchema(http.StatusOK, "Deprecated event type", catalog.EventType{}),
+		forge.WithErrorResponses(),
+	); err != nil {
+		a.log.Error("Failed to register deprecateEventType route", forge.Error(err))
+	}
+
+	if err := g.POST("/event-types/:name/undeprecate", a.undeprecateEventType,
+		forge.WithSummary("Undeprecate event type"),
+		forge.WithDescription("Clears an event type's deprecation, including one made by DELETE."),
+		forge.WithOperationID("undeprecateEventType"),
+		a.require(auth.ScopeCatalogAdmin),
+		forge.WithRequestSchema(EventTypeActionForgeRequest{}),
+		forge.WithResponseSchema(http.StatusOK, "Event type details", catalog.EventType{}),
+		forge.WithErrorResponses(),
+	); err != nil {
+		a.log.Error("Failed to register undeprecateEventType route", forge.Error(err))
+	}
+
+	if err := g.GET("/event-types/:name/subscribers", a.listEventTypeSubscribers,
+		forge.WithSummary("List event type subscribers"),
+		forge.WithDescription("Returns the endpoints of all tenants subscribed to an event type."),
+		forge.WithOperationID("listEventTypeSubscribers"),
+		a.require(auth.ScopeEndpointsRead),
+		forge.WithRequestSchema(EventTypeActionForgeRequest{}),
+		forge.WithListResponse(endpoint.Endpoint{}, http.StatusOK),
+		forge.WithErrorResponses(),
+	); err != nil {
+		a.log.Error("Failed to register listEventTypeSubscribers route", forge.Error(err))
+	}
+
+	if err := g.POST("/event-types/:name/compatibility", a.checkEventTypeCompatibility,
+		forge.WithSummary("Check schema compatibility"),
+		forge.WithDescription("Compares a proposed schema with the registered one and reports each change as backward, forward or breaking. Nothing is registered."),
+		forge.WithOperationID("checkEventTypeCompatibility"),
+		a.require(auth.ScopeCatalogRead),
+		forge.WithRequestSchema(CheckCompatibilityForgeRequest{}),
+		forge.WithResponseSchema(http.StatusOK, "Compatibility report", catalog.CompatReport{}),
+		forge.WithErrorResponses(),
+	); err != nil {
+		a.log.Error("Failed to register checkEventTypeCompatibility route", forge.Error(err))
+	}
 }
 
 func (a *ForgeAPI) createEventType(ctx forge.Context, req *CreateEventTypeForgeRequest) (*catalog.EventType, error) {
+	if err := forgeUnbound(ctx.Context()); err != nil {
+		return nil, err
+	}
 	if req.Name == "" {
 		return nil, forge.BadRequest("name is required")
 	}
@@ -132,9 +207,23 @@ func (a *ForgeAPI) createEventType(ctx forge.Context, req *CreateEventTypeForgeR
 	if req.Metadata != nil {
 		opts = append(opts, catalog.WithMetadata(req.Metadata))
 	}
+	if req.Force == "true" {
+		opts = append(opts, catalog.WithCompatPolicy(catalog.CompatPolicyAllow))
+	}
 
 	et, err := a.catalog.RegisterType(ctx.Context(), def, opts...)
 	if err != nil {
+		var incompatible *catalog.IncompatibleSchemaError
+		if errors.As(err, &incompatible) {
+			if err := ctx.JSON(http.StatusConflict, incompatibleSchemaResponse{
+				Error:  err.Error(),
+				Report: incompatible.Report,
+			}); err != nil {
+				return nil, mapError(err)
+			}
+			//nolint:nilnil // response already written via ctx.JSON.
+			return nil, nil
+		}
 		return nil, mapError(err)
 	}
 
@@ -147,17 +236,46 @@ func (a *ForgeAPI) createEventType(ctx forge.Context, req *CreateEventTypeForgeR
 	return nil, nil
 }
 
+func (a *ForgeAPI) checkEventTypeCompatibility(ctx forge.Context, req *CheckCompatibilityForgeRequest) (*catalog.CompatReport, error) {
+	if len(req.Schema) > 0 && !json.Valid(req.Schema) {
+		return nil, forge.BadRequest("schema must be valid JSON")
+	}
+
+	report, err := a.catalog.CheckCompatibility(ctx.Context(), catalog.WebhookDefinition{
+		Name:    req.Name,
+		Version: req.Version,
+		Schema:  req.Schema,
+	})
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	err = ctx.JSON(http.StatusOK, report)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	//nolint:nilnil // response already written via ctx.JSON.
+	return nil, nil
+}
+
 func (a *ForgeAPI) listEventTypes(ctx forge.Context, req *ListEventTypesForgeRequest) (*catalog.EventType, error) {
 	limit := req.Limit
-	if limit == 0 {
-		limit = 50
+	if limit <= 0 {
+		limit = page.DefaultLimit
+	}
+	cursor, err := forgeCursor(req.Cursor, id.PrefixEventType)
+	if err != nil {
+		return nil, err
 	}
 
 	opts := catalog.ListOpts{
+		Cursor:            cursor,
 		Offset:            req.Offset,
-		Limit:             limit,
+		Limit:             limit + 1,
 		Group:             req.Group,
 		IncludeDeprecated: req.IncludeDeprecated == "true",
+		ScopeAppID:        req.ScopeAppID,
 	}
 
 	types, err := a.catalog.ListTypes(ctx.Context(), opts)
@@ -165,7 +283,13 @@ func (a *ForgeAPI) listEventTypes(ctx forge.Context, req *ListEventTypesForgeReq
 		return nil, mapError(err)
 	}
 
-	if err := ctx.JSON(http.StatusOK, types); err != nil {
+	result, err := forgePage(types, limit, func(et *catalog.EventType) id.ID { return et.ID }, req.IncludeTotal == "true", func() (int64, error) {
+		return a.catalog.CountTypes(ctx.Context(), opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.JSON(http.StatusOK, result); err != nil {
 		return nil, mapError(err)
 	}
 
@@ -183,6 +307,9 @@ func (a *ForgeAPI) getEventType(ctx forge.Context, req *GetEventTypeForgeRequest
 }
 
 func (a *ForgeAPI) deleteEventType(ctx forge.Context, req *DeleteEventTypeForgeRequest) (*catalog.EventType, error) {
+	if err := forgeUnbound(ctx.Context()); err != nil {
+		return nil, err
+	}
 	if err := a.catalog.DeleteType(ctx.Context(), req.Name); err != nil {
 		return nil, mapError(err)
 	}
@@ -196,17 +323,116 @@ func (a *ForgeAPI) deleteEventType(ctx forge.Context, req *DeleteEventTypeForgeR
 	return nil, nil
 }
 
+func (a *ForgeAPI) deprecateEventType(ctx forge.Context, req *DeprecateEventTypeForgeRequest) (*catalog.EventType, error) {
+	if err := forgeUnbound(ctx.Context()); err != nil {
+		return nil, err
+	}
+	sunsetAt, err := time.Parse(time.RFC3339, req.SunsetAt)
+	if err != nil {
+		return nil, forge.BadRequest("sunset_at must be an RFC3339 time")
+	}
+
+	et, err := a.catalog.Deprecate(ctx.Context(), req.Name, sunsetAt)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return et, nil
+}
+
+func (a *ForgeAPI) undeprecateEventType(ctx forge.Context, req *EventTypeActionForgeRequest) (*catalog.EventType, error) {
+	if err := forgeUnbound(ctx.Context()); err != nil {
+		return nil, err
+	}
+	et, err := a.catalog.Undeprecate(ctx.Context(), req.Name)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return et, nil
+}
+
+func (a *ForgeAPI) listEventTypeSubscribers(ctx forge.Context, req *EventTypeActionForgeRequest) (*endpoint.Endpoint, error) {
+	if _, err := a.catalog.GetType(ctx.Context(), req.Name); err != nil {
+		return nil, mapError(err)
+	}
+
+	eps, err := a.endpointSvc.Subscribers(ctx.Context(), req.Name)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	eps = slices.DeleteFunc(eps, func(ep *endpoint.Endpoint) bool {
+		return !auth.CanAccess(ctx.Context(), ep.TenantID)
+	})
+
+	if err := ctx.JSON(http.StatusOK, eps); err != nil {
+		return nil, mapError(err)
+	}
+
+	//nolint:nilnil // response already written via ctx.JSON.
+	return nil, nil
+}
+
+// ---------------------------------------------------------------------------
+// Catalog export routes
+// ---------------------------------------------------------------------------
+
+func (a *ForgeAPI) registerCatalogRoutes(router forge.Router) {
+	g := router.Group("/v1", forge.WithGroupTags("catalog"), a.groupMiddleware())
+
+	if err := g.GET("/catalog/asyncapi.json", a.getAsyncAPI,
+		forge.WithSummary("Export AsyncAPI document"),
+		forge.WithDescription("Returns an AsyncAPI 3.0 document describing every event type that can be sent, with the headers Relay signs deliveries with."),
+		forge.WithOperationID("getAsyncAPI"),
+		a.require(auth.ScopeCatalogRead),
+		forge.WithRequestSchema(CatalogExportForgeRequest{}),
+		forge.WithResponseSchema(http.StatusOK, "AsyncAPI document", catalog.AsyncAPIDocument{}),
+		forge.WithErrorResponses(),
+	); err != nil {
+		a.log.Error("Failed to register getAsyncAPI route", forge.Error(err))
+	}
+
+	if err := g.GET("/catalog/openapi.json", a.getOpenAPIWebhooks,
+		forge.WithSummary("Export OpenAPI webhooks"),
+		forge.WithDescription("Returns an OpenAPI 3.1 document whose webhooks section describes every event type that can be sent."),
+		forge.WithOperationID("getOpenAPIWebhooks"),
+		a.require(auth.ScopeCatalogRead),
+		forge.WithRequestSchema(CatalogExportForgeRequest{}),
+		forge.WithResponseSchema(http.StatusOK, "OpenAPI document", catalog.OpenAPIDocument{}),
+		forge.WithErrorResponses(),
+	); err != nil {
+		a.log.Error("Failed to register getOpenAPIWebhooks route", forge.Error(err))
+	}
+}
+
+func (a *ForgeAPI) getAsyncAPI(ctx forge.Context, req *CatalogExportForgeRequest) (*catalog.AsyncAPIDocument, error) {
+	doc, err := a.catalog.ExportAsyncAPI(ctx.Context(), catalog.DocInfo{Title: req.Title, Version: req.Version})
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return doc, nil
+}
+
+func (a *ForgeAPI) getOpenAPIWebhooks(ctx forge.Context, req *CatalogExportForgeRequest) (*catalog.OpenAPIDocument, error) {
+	doc, err := a.catalog.ExportOpenAPI(ctx.Context(), catalog.DocInfo{Title: req.Title, Version: req.Version})
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return doc, nil
+}
+
 // ---------------------------------------------------------------------------
 // Endpoint routes
 // ---------------------------------------------------------------------------
 
 func (a *ForgeAPI) registerEndpointRoutes(router forge.Router) {
-	g := router.Group("/v1", forge.WithGroupTags("endpoints"))
+	g := router.Group("/v1", forge.WithGroupTags("endpoints"), a.groupMiddleware())
 
 	if err := g.POST("/endpoints", a.createEndpoint,
 		forge.WithSummary("Create endpoint"),
 		forge.WithDescription("Creates a new webhook endpoint for a tenant."),
 		forge.WithOperationID("createEndpoint"),
+		a.require(auth.ScopeEndpointsAdmin),
 		forge.WithRequestSchema(CreateEndpointForgeRequest{}),
 		forge.WithCreatedResponse(endpoint.Endpoint{}),
 		forge.WithErrorResponses(),
@@ -218,8 +444,9 @@ func (a *ForgeAPI) registerEndpointRoutes(router forge.Router) {
 		forge.WithSummary("List endpoints"),
 		forge.WithDescription("Returns a paginated list of endpoints for a tenant."),
 		forge.WithOperationID("listEndpoints"),
+		a.require(auth.ScopeEndpointsRead),
 		forge.WithRequestSchema(ListEndpointsForgeRequest{}),
-		forge.WithListResponse(endpoint.Endpoint{}, http.StatusOK),
+		forge.WithResponseSchema(http.StatusOK, "Page of endpoints", page.Page[endpoint.Endpoint]{}),
 		forge.WithErrorResponses(),
 	); err != nil {
 		a.log.Error("Failed to register listEndpoints route", forge.Error(err))
@@ -229,6 +456,7 @@ func (a *ForgeAPI) registerEndpointRoutes(router forge.Router) {
 		forge.WithSummary("Get endpoint"),
 		forge.WithDescription("Returns details of a specific endpoint."),
 		forge.WithOperationID("getEndpoint"),
+		a.require(auth.ScopeEndpointsRead),
 		forge.WithRequestSchema(GetEndpointForgeRequest{}),
 		forge.WithResponseSchema(http.StatusOK, "Endpoint details", endpoint.Endpoint{}),
 		forge.WithErrorResponses(),
@@ -240,6 +468,7 @@ func (a *ForgeAPI) registerEndpointRoutes(router forge.Router) {
 		forge.WithSummary("Update endpoint"),
 		forge.WithDescription("Updates mutable fields of an endpoint."),
 		forge.WithOperationID("updateEndpoint"),
+		a.require(auth.ScopeEndpointsAdmin),
 		forge.WithRequestSchema(UpdateEndpointForgeRequest{}),
 		forge.WithResponseSchema(http.StatusOK, "Updated endpoint", endpoint.Endpoint{}),
 		forge.WithErrorResponses(),
@@ -251,6 +480,7 @@ func (a *ForgeAPI) registerEndpointRoutes(router forge.Router) {
 		forge.WithSummary("Delete endpoint"),
 		forge.WithDescription("Permanently deletes an endpoint."),
 		forge.WithOperationID("deleteEndpoint"),
+		a.require(auth.ScopeEndpointsAdmin),
 		forge.WithRequestSchema(DeleteEndpointForgeRequest{}),
 		forge.WithNoContentResponse(),
 		forge.WithErrorResponses(),
@@ -262,6 +492,7 @@ func (a *ForgeAPI) registerEndpointRoutes(router forge.Router) {
 		forge.WithSummary("Enable endpoint"),
 		forge.WithDescription("Re-enables a disabled endpoint."),
 		forge.WithOperationID("enableEndpoint"),
+		a.require(auth.ScopeEndpointsAdmin),
 		forge.WithRequestSchema(EndpointActionForgeRequest{}),
 		forge.WithNoContentResponse(),
 		forge.WithErrorResponses(),
@@ -273,6 +504,7 @@ func (a *ForgeAPI) registerEndpointRoutes(router forge.Router) {
 		forge.WithSummary("Disable endpoint"),
 		forge.WithDescription("Disables an endpoint, pausing all deliveries."),
 		forge.WithOperationID("disableEndpoint"),
+		a.require(auth.ScopeEndpointsAdmin),
 		forge.WithRequestSchema(EndpointActionForgeRequest{}),
 		forge.WithNoContentResponse(),
 		forge.WithErrorResponses(),
@@ -284,22 +516,103 @@ func (a *ForgeAPI) registerEndpointRoutes(router forge.Router) {
 		forge.WithSummary("Rotate secret"),
 		forge.WithDescription("Generates a new signing secret for the endpoint."),
 		forge.WithOperationID("rotateEndpointSecret"),
+		a.require(auth.ScopeEndpointsAdmin),
 		forge.WithRequestSchema(EndpointActionForgeRequest{}),
 		forge.WithResponseSchema(http.StatusOK, "New signing secret", SecretForgeResponse{}),
 		forge.WithErrorResponses(),
 	); err != nil {
 		a.log.Error("Failed to register rotateSecret route", forge.Error(err))
 	}
+
+	if err := g.POST("/endpoints/:endpointId/verify", a.verifyEndpoint,
+		forge.WithSummary("Verify endpoint"),
+		forge.WithDescription("Sends a signed challenge the endpoint must echo back. On success the endpoint is marked verified and, if it was pending verification, enabled."),
+		forge.WithOperationID("verifyEndpoint"),
+		a.require(auth.ScopeEndpointsAdmin),
+		forge.WithRequestSchema(EndpointActionForgeRequest{}),
+		forge.WithResponseSchema(http.StatusOK, "Verified endpoint", endpoint.Endpoint{}),
+		forge.WithErrorResponses(),
+	); err != nil {
+		a.log.Error("Failed to register verifyEndpoint route", forge.Error(err))
+	}
+
+	if err := g.POST("/endpoints/:endpointId/test", a.testEndpoint,
+		forge.WithSummary("Send test event"),
+		forge.WithDescription("Sends a signed test event of the given type to the endpoint and returns its response. The payload defaults to the event type's example. The delivery is recorded as a test and never retried or dead-lettered."),
+		forge.WithOperationID("testEndpoint"),
+		a.require(auth.ScopeEndpointsAdmin),
+		forge.WithRequestSchema(TestEndpointForgeRequest{}),
+		forge.WithResponseSchema(http.StatusOK, "Test result", relay.TestResult{}),
+		forge.WithErrorResponses(),
+	); err != nil {
+		a.log.Error("Failed to register testEndpoint route", forge.Error(err))
+	}
+
+	if err := g.POST("/endpoints/:endpointId/backfill", a.backfillEndpoint,
+		forge.WithSummary("Backfill endpoint"),
+		forge.WithDescription("Starts a background job delivering the tenant's past events the endpoint is subscribed to, optionally limited to a time range and event type, at a throttled rate."),
+		forge.WithOperationID("backfillEndpoint"),
+		a.require(auth.ScopeEndpointsAdmin),
+		forge.WithRequestSchema(BackfillEndpointForgeRequest{}),
+		forge.WithResponseSchema(http.StatusAccepted, "Backfill job", job.Job{}),
+		forge.WithErrorResponses(),
+	); err != nil {
+		a.log.Error("Failed to register backfillEndpoint route", forge.Error(err))
+	}
+
+	if err := g.GET("/backfills/:jobId", a.getBackfill,
+		forge.WithSummary("Get backfill"),
+		forge.WithDescription("Returns the state and progress of a backfill job. Jobs are only known to the instance that started them."),
+		forge.WithOperationID("getBackfill"),
+		a.require(auth.ScopeEndpointsRead),
+		forge.WithRequestSchema(BackfillJobForgeRequest{}),
+		forge.WithResponseSchema(http.StatusOK, "Backfill job", job.Job{}),
+		forge.WithErrorResponses(),
+	); err != nil {
+		a.log.Error("Failed to register getBackfill route", forge.Error(err))
+	}
+
+	if err := g.POST("/backfills/:jobId/cancel", a.cancelBackfill,
+		forge.WithSummary("Cancel backfill"),
+		forge.WithDescription("Stops a running backfill job. Deliveries it already enqueued are kept."),
+		forge.WithOperationID("cancelBackfill"),
+		a.require(auth.ScopeEndpointsAdmin),
+		forge.WithRequestSchema(BackfillJobForgeRequest{}),
+		forge.WithResponseSchema(http.StatusAccepted, "Backfill job", job.Job{}),
+		forge.WithErrorResponses(),
+	); err != nil {
+		a.log.Error("Failed to register cancelBackfill route", forge.Error(err))
+	}
+
+	if err := g.GET("/endpoints/:endpointId/health", a.getEndpointHealth,
+		forge.WithSummary("Get endpoint health"),
+		forge.WithDescription("Returns the endpoint's rolling delivery health (success rate, consecutive failures, last success) and, if it was disabled automatically, the reason."),
+		forge.WithOperationID("getEndpointHealth"),
+		a.require(auth.ScopeEndpointsRead),
+		forge.WithRequestSchema(EndpointActionForgeRequest{}),
+		forge.WithResponseSchema(http.StatusOK, "Endpoint health", relay.EndpointHealth{}),
+		forge.WithErrorResponses(),
+	); err != nil {
+		a.log.Error("Failed to register getEndpointHealth route", forge.Error(err))
+	}
 }
 
 func (a *ForgeAPI) createEndpoint(ctx forge.Context, req *CreateEndpointForgeRequest) (*endpoint.Endpoint, error) {
+	tenantID, err := forgeTenant(ctx.Context(), req.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
 	input := endpoint.Input{
-		TenantID:   req.TenantID,
-		URL:        req.URL,
-		EventTypes: req.EventTypes,
-		Headers:    req.Headers,
-		RateLimit:  req.RateLimit,
-		Metadata:   req.Metadata,
+		TenantID:    tenantID,
+		URL:         req.URL,
+		Description: req.Description,
+		Secret:      req.Secret,
+		EventTypes:  req.EventTypes,
+		Headers:     req.Headers,
+		RateLimit:   req.RateLimit,
+		Version:     &req.Version,
+		Metadata:    req.Metadata,
 	}
 
 	ep, err := a.endpointSvc.Create(ctx.Context(), input)
@@ -317,26 +630,43 @@ func (a *ForgeAPI) createEndpoint(ctx forge.Context, req *CreateEndpointForgeReq
 }
 
 func (a *ForgeAPI) listEndpoints(ctx forge.Context, req *ListEndpointsForgeRequest) (*endpoint.Endpoint, error) {
-	if req.TenantID == "" {
+	tenantID, err := forgeTenant(ctx.Context(), req.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	if tenantID == "" {
 		return nil, forge.BadRequest("tenant_id query parameter is required")
 	}
 
 	limit := req.Limit
-	if limit == 0 {
-		limit = 50
+	if limit <= 0 {
+		limit = page.DefaultLimit
+	}
+	cursor, err := forgeCursor(req.Cursor, id.PrefixEndpoint)
+	if err != nil {
+		return nil, err
 	}
 
 	opts := endpoint.ListOpts{
-		Offset: req.Offset,
-		Limit:  limit,
+		Cursor:     cursor,
+		Offset:     req.Offset,
+		Limit:      limit + 1,
+		ScopeAppID: req.ScopeAppID,
+		ScopeOrgID: req.ScopeOrgID,
 	}
 
-	eps, err := a.endpointSvc.List(ctx.Context(), req.TenantID, opts)
+	eps, err := a.endpointSvc.List(ctx.Context(), tenantID, opts)
 	if err != nil {
 		return nil, mapError(err)
 	}
 
-	if err := ctx.JSON(http.StatusOK, eps); err != nil {
+	result, err := forgePage(eps, limit, func(ep *endpoint.Endpoint) id.ID { return ep.ID }, req.IncludeTotal == "true", func() (int64, error) {
+		return a.endpointSvc.Count(ctx.Context(), tenantID, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.JSON(http.StatusOK, result); err != nil {
 		return nil, mapError(err)
 	}
 
@@ -349,6 +679,9 @@ func (a *ForgeAPI) getEndpoint(ctx forge.Context, req *GetEndpointForgeRequest)
 	if err != nil {
 		return nil, forge.BadRequest("invalid endpoint ID")
 	}
+	if err := a.ownsEndpoint(ctx.Context(), epID); err != nil {
+		return nil, err
+	}
 
 	ep, getErr := a.endpointSvc.Get(ctx.Context(), epID)
 	if getErr != nil {
@@ -363,12 +696,16 @@ func (a *ForgeAPI) updateEndpoint(ctx forge.Context, req *UpdateEndpointForgeReq
 	if err != nil {
 		return nil, forge.BadRequest("invalid endpoint ID")
 	}
+	if err := a.ownsEndpoint(ctx.Context(), epID); err != nil {
+		return nil, err
+	}
 
 	input := endpoint.Input{
 		URL:        req.URL,
 		EventTypes: req.EventTypes,
 		Headers:    req.Headers,
 		RateLimit:  req.RateLimit,
+		Version:    req.Version,
 		Metadata:   req.Metadata,
 	}
 
@@ -385,6 +722,9 @@ func (a *ForgeAPI) deleteEndpoint(ctx forge.Context, req *DeleteEndpointForgeReq
 	if err != nil {
 		return nil, forge.BadRequest("invalid endpoint ID")
 	}
+	if err := a.ownsEndpoint(ctx.Context(), epID); err != nil {
+		return nil, err
+	}
 
 	if deleteErr := a.endpointSvc.Delete(ctx.Context(), epID); deleteErr != nil {
 		return nil, mapError(deleteErr)
@@ -404,8 +744,15 @@ func (a *ForgeAPI) enableEndpoint(ctx forge.Context, req *EndpointActionForgeReq
 	if err != nil {
 		return nil, forge.BadRequest("invalid endpoint ID")
 	}
+	if err := a.ownsEndpoint(ctx.Context(), epID); err != nil {
+		return nil, err
+	}
 
 	if setErr := a.endpointSvc.SetEnabled(ctx.Context(), epID, true); setErr != nil {
+		var ve *endpoint.ValidationError
+		if errors.As(setErr, &ve) {
+			return nil, forge.NewHTTPError(http.StatusConflict, setErr.Error())
+		}
 		return nil, mapError(setErr)
 	}
 
@@ -423,6 +770,9 @@ func (a *ForgeAPI) disableEndpoint(ctx forge.Context, req *EndpointActionForgeRe
 	if err != nil {
 		return nil, forge.BadRequest("invalid endpoint ID")
 	}
+	if err := a.ownsEndpoint(ctx.Context(), epID); err != nil {
+		return nil, err
+	}
 
 	if setErr := a.endpointSvc.SetEnabled(ctx.Context(), epID, false); setErr != nil {
 		return nil, mapError(setErr)
@@ -442,6 +792,9 @@ func (a *ForgeAPI) rotateSecret(ctx forge.Context, req *EndpointActionForgeReque
 	if err != nil {
 		return nil, forge.BadRequest("invalid endpoint ID")
 	}
+	if err := a.ownsEndpoint(ctx.Context(), epID); err != nil {
+		return nil, err
+	}
 
 	newSecret, rotateErr := a.endpointSvc.RotateSecret(ctx.Context(), epID)
 	if rotateErr != nil {
@@ -451,30 +804,152 @@ func (a *ForgeAPI) rotateSecret(ctx forge.Context, req *EndpointActionForgeReque
 	return &SecretForgeResponse{Secret: newSecret}, nil
 }
 
+func (a *ForgeAPI) verifyEndpoint(ctx forge.Context, req *EndpointActionForgeRequest) (*endpoint.Endpoint, error) {
+	epID, err := id.ParseEndpointID(req.EndpointID)
+	if err != nil {
+		return nil, forge.BadRequest("invalid endpoint ID")
+	}
+	if err := a.ownsEndpoint(ctx.Context(), epID); err != nil {
+		return nil, err
+	}
+
+	ep, err := a.endpointSvc.Verify(ctx.Context(), epID)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return ep, nil
+}
+
+func (a *ForgeAPI) testEndpoint(ctx forge.Context, req *TestEndpointForgeRequest) (*relay.TestResult, error) {
+	epID, err := id.ParseEndpointID(req.EndpointID)
+	if err != nil {
+		return nil, forge.BadRequest("invalid endpoint ID")
+	}
+	if err := a.ownsEndpoint(ctx.Context(), epID); err != nil {
+		return nil, err
+	}
+	if req.EventType == "" {
+		return nil, forge.BadRequest("event_type is required")
+	}
+
+	res, err := a.relay.SendTest(ctx.Context(), epID, req.EventType, req.Data)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return res, nil
+}
+
+func (a *ForgeAPI) getEndpointHealth(ctx forge.Context, req *EndpointActionForgeRequest) (*relay.EndpointHealth, error) {
+	epID, err := id.ParseEndpointID(req.EndpointID)
+	if err != nil {
+		return nil, forge.BadRequest("invalid endpoint ID")
+	}
+	if err := a.ownsEndpoint(ctx.Context(), epID); err != nil {
+		return nil, err
+	}
+
+	eh, err := a.relay.EndpointHealth(ctx.Context(), epID)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return eh, nil
+}
+
+func (a *ForgeAPI) backfillEndpoint(ctx forge.Context, req *BackfillEndpointForgeRequest) (*job.Job, error) {
+	epID, err := id.ParseEndpointID(req.EndpointID)
+	if err != nil {
+		return nil, forge.BadRequest("invalid endpoint ID")
+	}
+	if err := a.ownsEndpoint(ctx.Context(), epID); err != nil {
+		return nil, err
+	}
+
+	opts, err := backfillRequest{From: req.From, To: req.To, Type: req.Type, Rate: req.Rate}.options()
+	if err != nil {
+		return nil, forge.BadRequest("invalid time format (use RFC3339)")
+	}
+
+	j, err := a.relay.Backfill(ctx.Context(), epID, opts)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	if err := ctx.JSON(http.StatusAccepted, j); err != nil {
+		return nil, mapError(err)
+	}
+
+	//nolint:nilnil // response already written via ctx.JSON.
+	return nil, nil
+}
+
+func (a *ForgeAPI) getBackfill(ctx forge.Context, req *BackfillJobForgeRequest) (*job.Job, error) {
+	return a.ownsJob(ctx.Context(), req.JobID, job.KindBackfill)
+}
+
+func (a *ForgeAPI) cancelBackfill(ctx forge.Context, req *BackfillJobForgeRequest) (*job.Job, error) {
+	return a.cancelJob(ctx, req.JobID, job.KindBackfill)
+}
+
+// cancelJob cancels the job of kind with rawID and answers with its latest
+// snapshot.
+func (a *ForgeAPI) cancelJob(ctx forge.Context, rawID, kind string) (*job.Job, error) {
+	j, err := a.ownsJob(ctx.Context(), rawID, kind)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.relay.Jobs().Cancel(j.ID); err != nil {
+		return nil, mapError(err)
+	}
+	if latest, err := a.relay.Jobs().Get(j.ID); err == nil {
+		j = latest
+	}
+	if err := ctx.JSON(http.StatusAccepted, j); err != nil {
+		return nil, mapError(err)
+	}
+
+	//nolint:nilnil // response already written via ctx.JSON.
+	return nil, nil
+}
+
 // ---------------------------------------------------------------------------
 // Event routes
 // ---------------------------------------------------------------------------
 
 func (a *ForgeAPI) registerEventRoutes(router forge.Router) {
-	g := router.Group("/v1", forge.WithGroupTags("events"))
+	g := router.Group("/v1", forge.WithGroupTags("events"), a.groupMiddleware())
 
 	if err := g.POST("/events", a.sendEvent,
 		forge.WithSummary("Send event"),
-		forge.WithDescription("Validates an event, persists it, and fans out deliveries to matching endpoints."),
+		forge.WithDescription("Validates an event, persists it, and fans out deliveries to matching endpoints. With dry_run=true the event is validated and resolved but nothing is persisted."),
 		forge.WithOperationID("sendEvent"),
+		a.require(auth.ScopeEventsWrite),
 		forge.WithRequestSchema(CreateEventForgeRequest{}),
-		forge.WithCreatedResponse(event.Event{}),
+		forge.WithResponseSchema(http.StatusAccepted, "Send result", relay.SendResult{}),
+		forge.WithResponseSchema(http.StatusOK, "Dry-run result", relay.SendResult{}),
 		forge.WithErrorResponses(),
 	); err != nil {
 		a.log.Error("Failed to register sendEvent route", forge.Error(err))
 	}
 
+	if err := g.POST("/events/batch", a.sendEventBatch,
+		forge.WithSummary("Send event batch"),
+		forge.WithDescription("Sends up to 10000 events given as a JSON array or newline-delimited JSON. Each event is validated individually; the response reports the outcome per event."),
+		forge.WithOperationID("sendEventBatch"),
+		a.require(auth.ScopeEventsWrite),
+		forge.WithRequestBodySchema([]CreateEventForgeRequest{}),
+		forge.WithRequestContentTypes("application/json", "application/x-ndjson"),
+		forge.WithResponseSchema(http.StatusOK, "Per-event results", EventBatchForgeResponse{}),
+		forge.WithErrorResponses(),
+	); err != nil {
+		a.log.Error("Failed to register sendEventBatch route", forge.Error(err))
+	}
+
 	if err := g.GET("/events", a.listEvents,
 		forge.WithSummary("List events"),
 		forge.WithDescription("Returns a paginated list of events."),
 		forge.WithOperationID("relayListEvents"),
+		a.require(auth.ScopeEventsRead),
 		forge.WithRequestSchema(ListEventsForgeRequest{}),
-		forge.WithListResponse(event.Event{}, http.StatusOK),
+		forge.WithResponseSchema(http.StatusOK, "Page of events", page.Page[event.Event]{}),
 		forge.WithErrorResponses(),
 	); err != nil {
 		a.log.Error("Failed to register listEvents route", forge.Error(err))
@@ -484,35 +959,94 @@ func (a *ForgeAPI) registerEventRoutes(router forge.Router) {
 		forge.WithSummary("Get event"),
 		forge.WithDescription("Returns details of a specific event."),
 		forge.WithOperationID("relayGetEvent"),
+		a.require(auth.ScopeEventsRead),
 		forge.WithRequestSchema(GetEventForgeRequest{}),
 		forge.WithResponseSchema(http.StatusOK, "Event details", event.Event{}),
 		forge.WithErrorResponses(),
 	); err != nil {
 		a.log.Error("Failed to register getEvent route", forge.Error(err))
 	}
+
+	if err := g.POST("/events/:eventId/resend", a.resendEvent,
+		forge.WithSummary("Resend event"),
+		forge.WithDescription("Delivers a stored event again: to the endpoints currently subscribed to its type, or to the given endpoint of its tenant."),
+		forge.WithOperationID("resendEvent"),
+		a.require(auth.ScopeDeliveriesWrite),
+		forge.WithRequestSchema(ResendEventForgeRequest{}),
+		forge.WithResponseSchema(http.StatusAccepted, "Resend result", relay.SendResult{}),
+		forge.WithErrorResponses(),
+	); err != nil {
+		a.log.Error("Failed to register resendEvent route", forge.Error(err))
+	}
 }
 
-func (a *ForgeAPI) sendEvent(ctx forge.Context, req *CreateEventForgeRequest) (*event.Event, error) {
+func (a *ForgeAPI) sendEvent(ctx forge.Context, req *CreateEventForgeRequest) (*relay.SendResult, error) {
 	if req.Type == "" {
 		return nil, forge.BadRequest("type is required")
 	}
-	if req.TenantID == "" {
+	tenantID, err := forgeTenant(ctx.Context(), req.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	if tenantID == "" {
 		return nil, forge.BadRequest("tenant_id is required")
 	}
 
 	evt := &event.Event{
 		Type:           req.Type,
-		TenantID:       req.TenantID,
+		TenantID:       tenantID,
 		Data:           req.Data,
+		Version:        req.Version,
 		IdempotencyKey: req.IdempotencyKey,
 	}
 
-	if err := a.relay.Send(ctx.Context(), evt); err != nil {
+	if req.DryRun {
+		res, err := a.relay.SendWithResult(ctx.Context(), evt, relay.DryRun())
+		if err != nil {
+			return nil, mapError(err)
+		}
+		addWarnings(ctx.Response().Header(), res.Warnings)
+		if err := ctx.JSON(http.StatusOK, res); err != nil {
+			return nil, mapError(err)
+		}
+		//nolint:nilnil // response already written via ctx.JSON.
+		return nil, nil
+	}
+
+	res, err := a.relay.SendWithResult(ctx.Context(), evt)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	addWarnings(ctx.Response().Header(), res.Warnings)
+
+	err = ctx.JSON(http.StatusAccepted, res)
+	if err != nil {
 		return nil, mapError(err)
 	}
 
-	err := ctx.JSON(http.StatusCreated, evt)
+	//nolint:nilnil // response already written via ctx.JSON.
+	return nil, nil
+}
+
+func (a *ForgeAPI) sendEventBatch(ctx forge.Context, _ *SendEventBatchForgeRequest) (*EventBatchForgeResponse, error) {
+	body := ctx.Request().Body
+	defer body.Close()
+
+	items, err := decodeEventBatch(body)
+	if err != nil {
+		if errors.Is(err, errBatchTooLarge) {
+			return nil, forge.NewHTTPError(http.StatusRequestEntityTooLarge, err.Error())
+		}
+		return nil, forge.BadRequest("invalid request body: " + err.Error())
+	}
+
+	status := http.StatusOK
+	resp, err := sendBatch(ctx.Context(), a.relay, items)
 	if err != nil {
+		status = http.StatusInternalServerError
+	}
+
+	if err := ctx.JSON(status, resp); err != nil {
 		return nil, mapError(err)
 	}
 
@@ -521,23 +1055,49 @@ func (a *ForgeAPI) sendEvent(ctx forge.Context, req *CreateEventForgeRequest) (*
 }
 
 func (a *ForgeAPI) listEvents(ctx forge.Context, req *ListEventsForgeRequest) (*event.Event, error) {
+	tenantID, err := forgeTenant(ctx.Context(), req.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
 	limit := req.Limit
-	if limit == 0 {
-		limit = 50
+	if limit <= 0 {
+		limit = page.DefaultLimit
+	}
+	cursor, err := forgeCursor(req.Cursor, id.PrefixEvent)
+	if err != nil {
+		return nil, err
 	}
 
 	opts := event.ListOpts{
-		Offset: req.Offset,
-		Limit:  limit,
-		Type:   req.Type,
+		Cursor:     cursor,
+		Offset:     req.Offset,
+		Limit:      limit + 1,
+		Type:       req.Type,
+		ScopeAppID: req.ScopeAppID,
+		ScopeOrgID: req.ScopeOrgID,
 	}
 
-	events, err := a.store.ListEvents(ctx.Context(), opts)
+	var events []*event.Event
+	if tenantID != "" {
+		events, err = a.store.ListEventsByTenant(ctx.Context(), tenantID, opts)
+	} else {
+		events, err = a.store.ListEvents(ctx.Context(), opts)
+	}
 	if err != nil {
 		return nil, mapError(err)
 	}
 
-	if err := ctx.JSON(http.StatusOK, events); err != nil {
+	result, err := forgePage(events, limit, func(evt *event.Event) id.ID { return evt.ID }, req.IncludeTotal == "true", func() (int64, error) {
+		if tenantID != "" {
+			return a.store.CountEventsByTenant(ctx.Context(), tenantID, opts)
+		}
+		return a.store.CountEvents(ctx.Context(), opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.JSON(http.StatusOK, result); err != nil {
 		return nil, mapError(err)
 	}
 
@@ -552,6 +1112,9 @@ func (a *ForgeAPI) getEvent(ctx forge.Context, req *GetEventForgeRequest) (*even
 	}
 
 	evt, getErr := a.store.GetEvent(ctx.Context(), evtID)
+	if getErr == nil && !auth.CanAccess(ctx.Context(), evt.TenantID) {
+		getErr = relay.ErrEventNotFound
+	}
 	if getErr != nil {
 		return nil, mapError(getErr)
 	}
@@ -559,23 +1122,118 @@ func (a *ForgeAPI) getEvent(ctx forge.Context, req *GetEventForgeRequest) (*even
 	return evt, nil
 }
 
-// ---------------------------------------------------------------------------
-// Delivery routes
-// ---------------------------------------------------------------------------
+func (a *ForgeAPI) resendEvent(ctx forge.Context, req *ResendEventForgeRequest) (*relay.SendResult, error) {
+	evtID, err := id.ParseEventID(req.EventID)
+	if err != nil {
+		return nil, forge.BadRequest("invalid event ID")
+	}
+	var endpointIDs []id.ID
+	if req.EndpointID != "" {
+		epID, parseErr := id.ParseEndpointID(req.EndpointID)
+		if parseErr != nil {
+			return nil, forge.BadRequest("invalid endpoint ID")
+		}
+		endpointIDs = append(endpointIDs, epID)
+	}
+
+	evt, getErr := a.store.GetEvent(ctx.Context(), evtID)
+	if getErr == nil && !auth.CanAccess(ctx.Context(), evt.TenantID) {
+		getErr = relay.ErrEventNotFound
+	}
+	if getErr != nil {
+		return nil, mapError(getErr)
+	}
+
+	res, err := a.relay.Resend(ctx.Context(), evtID, endpointIDs...)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	if err := ctx.JSON(http.StatusAccepted, res); err != nil {
+		return nil, mapError(err)
+	}
+
+	//nolint:nilnil // response already written via ctx.JSON.
+	return nil, nil
+}
+
+// ---------------------------------------------------------------------------
+// Delivery routes
+// ---------------------------------------------------------------------------
 
 func (a *ForgeAPI) registerDeliveryRoutes(router forge.Router) {
-	g := router.Group("/v1", forge.WithGroupTags("deliveries"))
+	g := router.Group("/v1", forge.WithGroupTags("deliveries"), a.groupMiddleware())
 
 	if err := g.GET("/endpoints/:endpointId/deliveries", a.listDeliveries,
 		forge.WithSummary("List deliveries"),
 		forge.WithDescription("Returns deliveries for a specific endpoint."),
 		forge.WithOperationID("listDeliveries"),
+		a.require(auth.ScopeDeliveriesRead),
 		forge.WithRequestSchema(ListDeliveriesForgeRequest{}),
-		forge.WithListResponse(delivery.Delivery{}, http.StatusOK),
+		forge.WithResponseSchema(http.StatusOK, "Page of deliveries", page.Page[delivery.Delivery]{}),
 		forge.WithErrorResponses(),
 	); err != nil {
 		a.log.Error("Failed to register listDeliveries route", forge.Error(err))
 	}
+
+	if err := g.GET("/deliveries", a.listAllDeliveries,
+		forge.WithSummary("List all deliveries"),
+		forge.WithDescription("Returns deliveries across endpoints, filtered by tenant, endpoint, event, state and creation time."),
+		forge.WithOperationID("listAllDeliveries"),
+		a.require(auth.ScopeDeliveriesRead),
+		forge.WithRequestSchema(ListAllDeliveriesForgeRequest{}),
+		forge.WithResponseSchema(http.StatusOK, "Page of deliveries", page.Page[delivery.Delivery]{}),
+		forge.WithErrorResponses(),
+	); err != nil {
+		a.log.Error("Failed to register listAllDeliveries route", forge.Error(err))
+	}
+
+	if err := g.GET("/events/:eventId/deliveries", a.listEventDeliveries,
+		forge.WithSummary("List event deliveries"),
+		forge.WithDescription("Returns the deliveries an event fanned out to."),
+		forge.WithOperationID("listEventDeliveries"),
+		a.require(auth.ScopeDeliveriesRead),
+		forge.WithRequestSchema(ListEventDeliveriesForgeRequest{}),
+		forge.WithResponseSchema(http.StatusOK, "Page of deliveries", page.Page[delivery.Delivery]{}),
+		forge.WithErrorResponses(),
+	); err != nil {
+		a.log.Error("Failed to register listEventDeliveries route", forge.Error(err))
+	}
+
+	if err := g.GET("/deliveries/:deliveryId", a.getDelivery,
+		forge.WithSummary("Get delivery"),
+		forge.WithDescription("Returns a delivery with its attempt state."),
+		forge.WithOperationID("getDelivery"),
+		a.require(auth.ScopeDeliveriesRead),
+		forge.WithRequestSchema(GetDeliveryForgeRequest{}),
+		forge.WithResponseSchema(http.StatusOK, "Delivery details", delivery.Delivery{}),
+		forge.WithErrorResponses(),
+	); err != nil {
+		a.log.Error("Failed to register getDelivery route", forge.Error(err))
+	}
+
+	if err := g.POST("/deliveries/:deliveryId/retry", a.retryDelivery,
+		forge.WithSummary("Retry delivery"),
+		forge.WithDescription("Makes a pending delivery due immediately, skipping the rest of its backoff."),
+		forge.WithOperationID("retryDelivery"),
+		a.require(auth.ScopeDeliveriesWrite),
+		forge.WithRequestSchema(RetryDeliveryForgeRequest{}),
+		forge.WithResponseSchema(http.StatusOK, "Retried delivery", delivery.Delivery{}),
+		forge.WithErrorResponses(),
+	); err != nil {
+		a.log.Error("Failed to register retryDelivery route", forge.Error(err))
+	}
+
+	if err := g.POST("/deliveries/:deliveryId/cancel", a.cancelDelivery,
+		forge.WithSummary("Cancel delivery"),
+		forge.WithDescription("Stops a pending delivery from being attempted again."),
+		forge.WithOperationID("cancelDelivery"),
+		a.require(auth.ScopeDeliveriesWrite),
+		forge.WithRequestSchema(CancelDeliveryForgeRequest{}),
+		forge.WithResponseSchema(http.StatusOK, "Cancelled delivery", delivery.Delivery{}),
+		forge.WithErrorResponses(),
+	); err != nil {
+		a.log.Error("Failed to register cancelDelivery route", forge.Error(err))
+	}
 }
 
 func (a *ForgeAPI) listDeliveries(ctx forge.Context, req *ListDeliveriesForgeRequest) (*delivery.Delivery, error) {
@@ -583,15 +1241,23 @@ func (a *ForgeAPI) listDeliveries(ctx forge.Context, req *ListDeliveriesForgeReq
 	if err != nil {
 		return nil, forge.BadRequest("invalid endpoint ID")
 	}
+	if err := a.ownsEndpoint(ctx.Context(), epID); err != nil {
+		return nil, err
+	}
 
 	limit := req.Limit
-	if limit == 0 {
-		limit = 50
+	if limit <= 0 {
+		limit = page.DefaultLimit
+	}
+	cursor, err := forgeCursor(req.Cursor, id.PrefixDelivery)
+	if err != nil {
+		return nil, err
 	}
 
 	opts := delivery.ListOpts{
+		Cursor: cursor,
 		Offset: req.Offset,
-		Limit:  limit,
+		Limit:  limit + 1,
 	}
 
 	if req.State != "" {
@@ -604,7 +1270,13 @@ func (a *ForgeAPI) listDeliveries(ctx forge.Context, req *ListDeliveriesForgeReq
 		return nil, mapError(listErr)
 	}
 
-	if err := ctx.JSON(http.StatusOK, deliveries); err != nil {
+	result, err := forgePage(deliveries, limit, func(d *delivery.Delivery) id.ID { return d.ID }, req.IncludeTotal == "true", func() (int64, error) {
+		return a.store.CountByEndpoint(ctx.Context(), epID, opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.JSON(http.StatusOK, result); err != nil {
 		return nil, mapError(err)
 	}
 
@@ -612,19 +1284,216 @@ func (a *ForgeAPI) listDeliveries(ctx forge.Context, req *ListDeliveriesForgeReq
 	return nil, nil
 }
 
+func (a *ForgeAPI) listAllDeliveries(ctx forge.Context, req *ListAllDeliveriesForgeRequest) (*delivery.Delivery, error) {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = page.DefaultLimit
+	}
+	cursor, err := forgeCursor(req.Cursor, id.PrefixDelivery)
+	if err != nil {
+		return nil, err
+	}
+	tenantID, err := forgeTenant(ctx.Context(), req.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := delivery.ListOpts{
+		Cursor:   cursor,
+		Offset:   req.Offset,
+		Limit:    limit + 1,
+		TenantID: tenantID,
+	}
+	if req.EndpointID != "" {
+		epID, parseErr := id.ParseEndpointID(req.EndpointID)
+		if parseErr != nil {
+			return nil, forge.BadRequest("invalid endpoint ID")
+		}
+		opts.EndpointID = &epID
+	}
+	if req.EventID != "" {
+		evtID, parseErr := id.ParseEventID(req.EventID)
+		if parseErr != nil {
+			return nil, forge.BadRequest("invalid event ID")
+		}
+		opts.EventID = &evtID
+	}
+	if req.State != "" {
+		state := delivery.State(req.State)
+		opts.State = &state
+	}
+	if req.From != "" {
+		from, parseErr := time.Parse(time.RFC3339, req.From)
+		if parseErr != nil {
+			return nil, forge.BadRequest("invalid 'from' time format (use RFC3339)")
+		}
+		opts.From = &from
+	}
+	if req.To != "" {
+		to, parseErr := time.Parse(time.RFC3339, req.To)
+		if parseErr != nil {
+			return nil, forge.BadRequest("invalid 'to' time format (use RFC3339)")
+		}
+		opts.To = &to
+	}
+
+	deliveries, listErr := a.store.ListDeliveries(ctx.Context(), opts)
+	if listErr != nil {
+		return nil, mapError(listErr)
+	}
+
+	result, err := forgePage(deliveries, limit, func(d *delivery.Delivery) id.ID { return d.ID }, req.IncludeTotal == "true", func() (int64, error) {
+		return a.store.CountDeliveries(ctx.Context(), opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.JSON(http.StatusOK, result); err != nil {
+		return nil, mapError(err)
+	}
+
+	//nolint:nilnil // response already written via ctx.JSON.
+	return nil, nil
+}
+
+func (a *ForgeAPI) listEventDeliveries(ctx forge.Context, req *ListEventDeliveriesForgeRequest) (*delivery.Delivery, error) {
+	evtID, err := id.ParseEventID(req.EventID)
+	if err != nil {
+		return nil, forge.BadRequest("invalid event ID")
+	}
+
+	evt, getErr := a.store.GetEvent(ctx.Context(), evtID)
+	if getErr == nil && !auth.CanAccess(ctx.Context(), evt.TenantID) {
+		getErr = relay.ErrEventNotFound
+	}
+	if getErr != nil {
+		return nil, mapError(getErr)
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = page.DefaultLimit
+	}
+	cursor, err := forgeCursor(req.Cursor, id.PrefixDelivery)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := delivery.ListOpts{
+		Cursor:  cursor,
+		Offset:  req.Offset,
+		Limit:   limit + 1,
+		EventID: &evtID,
+	}
+
+	deliveries, listErr := a.store.ListDeliveries(ctx.Context(), opts)
+	if listErr != nil {
+		return nil, mapError(listErr)
+	}
+
+	result, err := forgePage(deliveries, limit, func(d *delivery.Delivery) id.ID { return d.ID }, req.IncludeTotal == "true", func() (int64, error) {
+		return a.store.CountDeliveries(ctx.Context(), opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.JSON(http.StatusOK, result); err != nil {
+		return nil, mapError(err)
+	}
+
+	//nolint:nilnil // response already written via ctx.JSON.
+	return nil, nil
+}
+
+func (a *ForgeAPI) getDelivery(ctx forge.Context, req *GetDeliveryForgeRequest) (*delivery.Delivery, error) {
+	delID, err := id.ParseDeliveryID(req.DeliveryID)
+	if err != nil {
+		return nil, forge.BadRequest("invalid delivery ID")
+	}
+	return a.ownsDelivery(ctx.Context(), delID)
+}
+
+func (a *ForgeAPI) retryDelivery(ctx forge.Context, req *RetryDeliveryForgeRequest) (*delivery.Delivery, error) {
+	return a.changeDelivery(ctx, req.DeliveryID, func(delID id.ID) error {
+		if a.relay != nil {
+			return a.relay.RetryDelivery(ctx.Context(), delID)
+		}
+		return a.store.RetryDelivery(ctx.Context(), delID)
+	})
+}
+
+func (a *ForgeAPI) cancelDelivery(ctx forge.Context, req *CancelDeliveryForgeRequest) (*delivery.Delivery, error) {
+	return a.changeDelivery(ctx, req.DeliveryID, func(delID id.ID) error {
+		if a.relay != nil {
+			return a.relay.CancelDelivery(ctx.Context(), delID)
+		}
+		return a.store.CancelDelivery(ctx.Context(), delID)
+	})
+}
+
+// changeDelivery applies change to the delivery with the given ID and
+// returns the delivery as it is afterwards.
+func (a *ForgeAPI) changeDelivery(ctx forge.Context, rawID string, change func(id.ID) error) (*delivery.Delivery, error) {
+	delID, err := id.ParseDeliveryID(rawID)
+	if err != nil {
+		return nil, forge.BadRequest("invalid delivery ID")
+	}
+	if _, err := a.ownsDelivery(ctx.Context(), delID); err != nil {
+		return nil, err
+	}
+
+	if err := change(delID); err != nil {
+		return nil, mapError(err)
+	}
+
+	d, err := a.store.GetDelivery(ctx.Context(), delID)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	return d, nil
+}
+
+// forgeCursor parses a list request's cursor, which must be an ID with
+// prefix. An empty cursor starts at the beginning of the list.
+func forgeCursor(cursor string, prefix id.Prefix) (id.ID, error) {
+	if cursor == "" {
+		return id.ID{}, nil
+	}
+	c, err := id.ParseWithPrefix(cursor, prefix)
+	if err != nil {
+		return id.ID{}, forge.BadRequest("invalid cursor")
+	}
+	return c, nil
+}
+
+// forgePage builds the page of items, listed with a limit of limit+1.
+// count is only called when includeTotal is set.
+func forgePage[T any](items []T, limit int, key func(T) id.ID, includeTotal bool, count func() (int64, error)) (*page.Page[T], error) {
+	result := page.New(items, limit, key)
+	if includeTotal {
+		total, err := count()
+		if err != nil {
+			return nil, mapError(err)
+		}
+		result.Total = &total
+	}
+	return result, nil
+}
+
 // ---------------------------------------------------------------------------
 // DLQ routes
 // ---------------------------------------------------------------------------
 
 func (a *ForgeAPI) registerDLQRoutes(router forge.Router) {
-	g := router.Group("/v1", forge.WithGroupTags("dlq"))
+	g := router.Group("/v1", forge.WithGroupTags("dlq"), a.groupMiddleware())
 
 	if err := g.GET("/dlq", a.listDLQ,
 		forge.WithSummary("List DLQ entries"),
 		forge.WithDescription("Returns dead letter queue entries, optionally filtered by tenant."),
 		forge.WithOperationID("listDLQ"),
+		a.require(auth.ScopeDLQRead),
 		forge.WithRequestSchema(ListDLQForgeRequest{}),
-		forge.WithListResponse(dlq.Entry{}, http.StatusOK),
+		forge.WithResponseSchema(http.StatusOK, "Page of DLQ entries", page.Page[dlq.Entry]{}),
 		forge.WithErrorResponses(),
 	); err != nil {
 		a.log.Error("Failed to register listDLQ route", forge.Error(err))
@@ -634,6 +1503,7 @@ func (a *ForgeAPI) registerDLQRoutes(router forge.Router) {
 		forge.WithSummary("Replay DLQ entry"),
 		forge.WithDescription("Re-enqueues a single DLQ entry for delivery."),
 		forge.WithOperationID("relayReplayDLQ"),
+		a.require(auth.ScopeDLQReplay),
 		forge.WithRequestSchema(ReplayDLQForgeRequest{}),
 		forge.WithNoContentResponse(),
 		forge.WithErrorResponses(),
@@ -645,24 +1515,84 @@ func (a *ForgeAPI) registerDLQRoutes(router forge.Router) {
 		forge.WithSummary("Bulk replay DLQ"),
 		forge.WithDescription("Re-enqueues DLQ entries within a time range."),
 		forge.WithOperationID("replayBulkDLQ"),
+		a.require(auth.ScopeDLQReplay),
 		forge.WithRequestSchema(ReplayBulkDLQForgeRequest{}),
 		forge.WithResponseSchema(http.StatusOK, "Replay result", ReplayBulkForgeResponse{}),
 		forge.WithErrorResponses(),
 	); err != nil {
 		a.log.Error("Failed to register replayBulkDLQ route", forge.Error(err))
 	}
+
+	if err := g.POST("/dlq/replay-jobs", a.startDLQReplay,
+		forge.WithSummary("Start DLQ replay job"),
+		forge.WithDescription("Starts a background job replaying the DLQ entries not yet replayed that match the filters, at a throttled rate. With dry_run it only returns how many entries match."),
+		forge.WithOperationID("startDLQReplay"),
+		a.require(auth.ScopeDLQReplay),
+		forge.WithRequestSchema(StartDLQReplayForgeRequest{}),
+		forge.WithResponseSchema(http.StatusAccepted, "Replay job", job.Job{}),
+		forge.WithResponseSchema(http.StatusOK, "Dry run count", DLQReplayCountForgeResponse{}),
+		forge.WithErrorResponses(),
+	); err != nil {
+		a.log.Error("Failed to register startDLQReplay route", forge.Error(err))
+	}
+
+	if err := g.GET("/dlq/replay-jobs/:jobId", a.getDLQReplay,
+		forge.WithSummary("Get DLQ replay job"),
+		forge.WithDescription("Returns the state and progress of a DLQ replay job. Jobs are only known to the instance that started them."),
+		forge.WithOperationID("getDLQReplay"),
+		a.require(auth.ScopeDLQRead),
+		forge.WithRequestSchema(DLQReplayJobForgeRequest{}),
+		forge.WithResponseSchema(http.StatusOK, "Replay job", job.Job{}),
+		forge.WithErrorResponses(),
+	); err != nil {
+		a.log.Error("Failed to register getDLQReplay route", forge.Error(err))
+	}
+
+	if err := g.POST("/dlq/replay-jobs/:jobId/cancel", a.cancelDLQReplay,
+		forge.WithSummary("Cancel DLQ replay job"),
+		forge.WithDescription("Stops a running DLQ replay job. Entries it already replayed stay replayed."),
+		forge.WithOperationID("cancelDLQReplay"),
+		a.require(auth.ScopeDLQReplay),
+		forge.WithRequestSchema(DLQReplayJobForgeRequest{}),
+		forge.WithResponseSchema(http.StatusAccepted, "Replay job", job.Job{}),
+		forge.WithErrorResponses(),
+	); err != nil {
+		a.log.Error("Failed to register cancelDLQReplay route", forge.Error(err))
+	}
+
+	if err := g.DELETE("/dlq", a.purgeDLQ,
+		forge.WithSummary("Purge DLQ"),
+		forge.WithDescription("Deletes DLQ entries that failed before a time."),
+		forge.WithOperationID("purgeDLQ"),
+		a.require(auth.ScopeDLQAdmin),
+		forge.WithRequestSchema(PurgeDLQForgeRequest{}),
+		forge.WithResponseSchema(http.StatusOK, "Purge result", PurgeDLQForgeResponse{}),
+		forge.WithErrorResponses(),
+	); err != nil {
+		a.log.Error("Failed to register purgeDLQ route", forge.Error(err))
+	}
 }
 
 func (a *ForgeAPI) listDLQ(ctx forge.Context, req *ListDLQForgeRequest) (*dlq.Entry, error) {
 	limit := req.Limit
-	if limit == 0 {
-		limit = 50
+	if limit <= 0 {
+		limit = page.DefaultLimit
+	}
+	cursor, err := forgeCursor(req.Cursor, id.PrefixDLQ)
+	if err != nil {
+		return nil, err
+	}
+
+	tenantID, err := forgeTenant(ctx.Context(), req.TenantID)
+	if err != nil {
+		return nil, err
 	}
 
 	opts := dlq.ListOpts{
+		Cursor:   cursor,
 		Offset:   req.Offset,
-		Limit:    limit,
-		TenantID: req.TenantID,
+		Limit:    limit + 1,
+		TenantID: tenantID,
 	}
 
 	entries, err := a.dlqSvc.List(ctx.Context(), opts)
@@ -670,7 +1600,13 @@ func (a *ForgeAPI) listDLQ(ctx forge.Context, req *ListDLQForgeRequest) (*dlq.En
 		return nil, mapError(err)
 	}
 
-	if err := ctx.JSON(http.StatusOK, entries); err != nil {
+	result, err := forgePage(entries, limit, func(e *dlq.Entry) id.ID { return e.ID }, req.IncludeTotal == "true", func() (int64, error) {
+		return a.dlqSvc.Count(ctx.Context(), opts)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.JSON(http.StatusOK, result); err != nil {
 		return nil, mapError(err)
 	}
 
@@ -683,6 +1619,9 @@ func (a *ForgeAPI) replayDLQ(ctx forge.Context, req *ReplayDLQForgeRequest) (*dl
 	if err != nil {
 		return nil, forge.BadRequest("invalid DLQ ID")
 	}
+	if err := a.ownsDLQEntry(ctx.Context(), dlqID); err != nil {
+		return nil, err
+	}
 
 	if replayErr := a.dlqSvc.Replay(ctx.Context(), dlqID); replayErr != nil {
 		return nil, mapError(replayErr)
@@ -698,6 +1637,10 @@ func (a *ForgeAPI) replayDLQ(ctx forge.Context, req *ReplayDLQForgeRequest) (*dl
 }
 
 func (a *ForgeAPI) replayBulkDLQ(ctx forge.Context, req *ReplayBulkDLQForgeRequest) (*ReplayBulkForgeResponse, error) {
+	if err := forgeUnbound(ctx.Context()); err != nil {
+		return nil, err
+	}
+
 	from, err := time.Parse(time.RFC3339, req.From)
 	if err != nil {
 		return nil, forge.BadRequest("invalid 'from' time format (use RFC3339)")
@@ -715,17 +1658,79 @@ func (a *ForgeAPI) replayBulkDLQ(ctx forge.Context, req *ReplayBulkDLQForgeReque
 	return &ReplayBulkForgeResponse{Replayed: count}, nil
 }
 
+func (a *ForgeAPI) startDLQReplay(ctx forge.Context, req *StartDLQReplayForgeRequest) (*job.Job, error) {
+	tenantID, err := forgeTenant(ctx.Context(), req.TenantID)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := dlqReplayRequest{
+		EndpointID:    req.EndpointID,
+		EventType:     req.EventType,
+		StatusCode:    req.StatusCode,
+		ErrorContains: req.ErrorContains,
+		From:          req.From,
+		To:            req.To,
+		Rate:          req.Rate,
+	}.options(tenantID)
+	if err != nil {
+		return nil, forge.BadRequest(err.Error())
+	}
+
+	if req.DryRun {
+		count, countErr := a.relay.CountDLQReplay(ctx.Context(), opts)
+		if countErr != nil {
+			return nil, mapError(countErr)
+		}
+		err = ctx.JSON(http.StatusOK, DLQReplayCountForgeResponse{Count: count})
+	} else {
+		err = ctx.JSON(http.StatusAccepted, a.relay.ReplayDLQ(ctx.Context(), opts))
+	}
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	//nolint:nilnil // response already written via ctx.JSON.
+	return nil, nil
+}
+
+func (a *ForgeAPI) getDLQReplay(ctx forge.Context, req *DLQReplayJobForgeRequest) (*job.Job, error) {
+	return a.ownsJob(ctx.Context(), req.JobID, job.KindDLQReplay)
+}
+
+func (a *ForgeAPI) cancelDLQReplay(ctx forge.Context, req *DLQReplayJobForgeRequest) (*job.Job, error) {
+	return a.cancelJob(ctx, req.JobID, job.KindDLQReplay)
+}
+
+func (a *ForgeAPI) purgeDLQ(ctx forge.Context, req *PurgeDLQForgeRequest) (*PurgeDLQForgeResponse, error) {
+	if err := forgeUnbound(ctx.Context()); err != nil {
+		return nil, err
+	}
+
+	before, err := time.Parse(time.RFC3339, req.Before)
+	if err != nil {
+		return nil, forge.BadRequest("invalid 'before' time format (use RFC3339)")
+	}
+
+	count, purgeErr := a.dlqSvc.Purge(ctx.Context(), before)
+	if purgeErr != nil {
+		return nil, mapError(purgeErr)
+	}
+
+	return &PurgeDLQForgeResponse{Purged: count}, nil
+}
+
 // ---------------------------------------------------------------------------
 // Stats routes
 // ---------------------------------------------------------------------------
 
 func (a *ForgeAPI) registerStatsRoutes(router forge.Router) {
-	g := router.Group("/v1", forge.WithGroupTags("stats"))
+	g := router.Group("/v1", forge.WithGroupTags("stats"), a.groupMiddleware())
 
 	if err := g.GET("/stats", a.getStats,
 		forge.WithSummary("System statistics"),
 		forge.WithDescription("Returns aggregate counts of pending deliveries and DLQ entries."),
 		forge.WithOperationID("getStats"),
+		a.require(auth.ScopeStatsRead),
 		forge.WithResponseSchema(http.StatusOK, "System statistics", StatsForgeResponse{}),
 		forge.WithErrorResponses(),
 	); err != nil {
@@ -734,12 +1739,16 @@ func (a *ForgeAPI) registerStatsRoutes(router forge.Router) {
 }
 
 func (a *ForgeAPI) getStats(ctx forge.Context, _ *StatsForgeRequest) (*StatsForgeResponse, error) {
+	if err := forgeUnbound(ctx.Context()); err != nil {
+		return nil, err
+	}
+
 	pending, err := a.store.CountPending(ctx.Context())
 	if err != nil {
 		return nil, mapError(err)
 	}
 
-	dlqCount, err := a.store.CountDLQ(ctx.Context())
+	dlqCount, err := a.store.CountDLQ(ctx.Context(), dlq.ListOpts{Unreplayed: true})
 	if err != nil {
 		return nil, mapError(err)
 	}
@@ -749,3 +1758,146 @@ func (a *ForgeAPI) getStats(ctx forge.Context, _ *StatsForgeRequest) (*StatsForg
 		DLQSize:           dlqCount,
 	}, nil
 }
+
+// ---------------------------------------------------------------------------
+// Tunnel routes
+// ---------------------------------------------------------------------------
+
+func (a *ForgeAPI) registerTunnelRoutes(router forge.Router) {
+	if a.relay == nil || a.relay.Tunnel() == nil {
+		return
+	}
+
+	g := router.Group("/v1", forge.WithGroupTags("tunnel"), a.groupMiddleware())
+
+	if err := g.POST("/tunnel/sessions", a.openTunnel,
+		forge.WithSummary("Open tunnel session"),
+		forge.WithDescription("Registers a temporary endpoint whose deliveries are streamed to a development listener. The response includes the signing secret."),
+		forge.WithOperationID("openTunnel"),
+		a.require(auth.ScopeEndpointsAdmin),
+		forge.WithRequestSchema(OpenTunnelForgeRequest{}),
+		forge.WithCreatedResponse(tunnel.Session{}),
+		forge.WithErrorResponses(),
+	); err != nil {
+		a.log.Error("Failed to register openTunnel route", forge.Error(err))
+	}
+
+	if err := g.GET("/tunnel/sessions/:sessionId/stream", a.streamTunnel,
+		forge.WithSummary("Stream tunnel deliveries"),
+		forge.WithDescription("Server-sent event stream of the session's delivery requests."),
+		forge.WithOperationID("streamTunnel"),
+		a.require(auth.ScopeEndpointsAdmin),
+		forge.WithRequestSchema(TunnelSessionForgeRequest{}),
+		forge.WithErrorResponses(),
+	); err != nil {
+		a.log.Error("Failed to register streamTunnel route", forge.Error(err))
+	}
+
+	if err := g.POST("/tunnel/sessions/:sessionId/responses", a.respondTunnel,
+		forge.WithSummary("Report tunnel delivery result"),
+		forge.WithDescription("Reports the local server's response to a streamed delivery request."),
+		forge.WithOperationID("respondTunnel"),
+		a.require(auth.ScopeEndpointsAdmin),
+		forge.WithRequestSchema(TunnelResponseForgeRequest{}),
+		forge.WithNoContentResponse(),
+		forge.WithErrorResponses(),
+	); err != nil {
+		a.log.Error("Failed to register respondTunnel route", forge.Error(err))
+	}
+
+	if err := g.DELETE("/tunnel/sessions/:sessionId", a.closeTunnel,
+		forge.WithSummary("Close tunnel session"),
+		forge.WithDescription("Closes the session and deletes its endpoint."),
+		forge.WithOperationID("closeTunnel"),
+		a.require(auth.ScopeEndpointsAdmin),
+		forge.WithRequestSchema(TunnelSessionForgeRequest{}),
+		forge.WithNoContentResponse(),
+		forge.WithErrorResponses(),
+	); err != nil {
+		a.log.Error("Failed to register closeTunnel route", forge.Error(err))
+	}
+}
+
+func (a *ForgeAPI) openTunnel(ctx forge.Context, req *OpenTunnelForgeRequest) (*tunnel.Session, error) {
+	eventTypes := req.EventTypes
+	if len(eventTypes) == 0 {
+		eventTypes = []string{"*"}
+	}
+
+	tenantID, err := forgeTenant(ctx.Context(), req.TenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := a.relay.Tunnel().Open(ctx.Context(), tenantID, eventTypes)
+	if err != nil {
+		var ve *endpoint.ValidationError
+		if errors.As(err, &ve) {
+			return nil, forge.BadRequest(err.Error())
+		}
+		return nil, mapError(err)
+	}
+
+	err = ctx.JSON(http.StatusCreated, s)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	//nolint:nilnil // response already written via ctx.JSON.
+	return nil, nil
+}
+
+func (a *ForgeAPI) streamTunnel(ctx forge.Context, req *TunnelSessionForgeRequest) (*tunnel.Session, error) {
+	if err := a.ownsTunnel(ctx.Context(), req.SessionID); err != nil {
+		return nil, err
+	}
+	if err := a.relay.Tunnel().Stream(ctx.Context(), req.SessionID, ctx.Response()); err != nil {
+		if errors.Is(err, tunnel.ErrSessionNotFound) {
+			return nil, mapError(err)
+		}
+		a.log.Warn("Tunnel stream ended", forge.String("session_id", req.SessionID), forge.Error(err))
+	}
+
+	//nolint:nilnil // response already written to the stream.
+	return nil, nil
+}
+
+func (a *ForgeAPI) respondTunnel(ctx forge.Context, req *TunnelResponseForgeRequest) (*tunnel.Session, error) {
+	if err := a.ownsTunnel(ctx.Context(), req.SessionID); err != nil {
+		return nil, err
+	}
+	if err := a.relay.Tunnel().Respond(req.SessionID, tunnel.Response{
+		ID:         req.ID,
+		StatusCode: req.StatusCode,
+		Header:     req.Header,
+		Body:       req.Body,
+		Error:      req.Error,
+	}); err != nil {
+		return nil, mapError(err)
+	}
+
+	err := ctx.NoContent(http.StatusNoContent)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	//nolint:nilnil // response already written via ctx.NoContent.
+	return nil, nil
+}
+
+func (a *ForgeAPI) closeTunnel(ctx forge.Context, req *TunnelSessionForgeRequest) (*tunnel.Session, error) {
+	if err := a.ownsTunnel(ctx.Context(), req.SessionID); err != nil {
+		return nil, err
+	}
+	if err := a.relay.Tunnel().Close(ctx.Context(), req.SessionID); err != nil {
+		return nil, mapError(err)
+	}
+
+	err := ctx.NoContent(http.StatusNoContent)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	//nolint:nilnil // response already written via ctx.NoContent.
+	return nil, nil
+}
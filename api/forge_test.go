@@ -0,0 +1,76 @@
+package api_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/xraph/forge"
+	log "github.com/xraph/go-utils/log"
+
+	"github.com/xraph/relay"
+	"github.com/xraph/relay/api"
+	"github.com/xraph/relay/catalog"
+	"github.com/xraph/relay/store/memory"
+)
+
+// forgeServer registers the Forge routes for a full Relay instance on a
+// bare router and returns the test server.
+func forgeServer(t *testing.T) (*httptest.Server, *relay.Relay) {
+	t.Helper()
+
+	s := memory.New()
+	r, err := relay.New(relay.WithStore(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := forge.NewRouter(forge.WithContainer(forge.NewContainer()))
+	api.NewForgeAPI(s, r.Catalog(), r.Endpoints(), r.DLQ(), r, log.NewNoopLogger()).RegisterRoutes(router)
+	return httptest.NewServer(router), r
+}
+
+func TestForgeSendEvent_Duplicate(t *testing.T) {
+	srv, r := forgeServer(t)
+	defer srv.Close()
+
+	if _, err := r.RegisterEventType(context.Background(), catalog.WebhookDefinition{
+		Name: "order.created",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	body := map[string]any{
+		"type":            "order.created",
+		"tenant_id":       "tenant-1",
+		"data":            map[string]any{"id": "ord_1"},
+		"idempotency_key": "ord_1",
+	}
+
+	resp := doJSON(t, "POST", srv.URL+"/v1/events", body)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("first send: expected 202, got %d", resp.StatusCode)
+	}
+	var first map[string]any
+	decodeBody(t, resp, &first)
+	if first["event_id"] == nil || first["duplicate"] != nil {
+		t.Fatalf("first send: unexpected result %v", first)
+	}
+
+	resp = doJSON(t, "POST", srv.URL+"/v1/events", body)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("second send: expected 202, got %d", resp.StatusCode)
+	}
+	var second map[string]any
+	decodeBody(t, resp, &second)
+	if second["duplicate"] != true {
+		t.Fatalf("second send: expected duplicate, got %v", second)
+	}
+	if second["original_event_id"] != first["event_id"] {
+		t.Fatalf("second send: expected original_event_id %v, got %v", first["event_id"], second["original_event_id"])
+	}
+	if second["event_id"] != nil {
+		t.Fatalf("second send: expected no event_id, got %v", second["event_id"])
+	}
+}
@@ -11,9 +11,14 @@ import (
 
 	log "github.com/xraph/go-utils/log"
 
+	"github.com/xraph/relay"
+	"github.com/xraph/relay/auth"
 	"github.com/xraph/relay/catalog"
 	"github.com/xraph/relay/dlq"
 	"github.com/xraph/relay/endpoint"
+	"github.com/xraph/relay/id"
+	"github.com/xraph/relay/page"
+	"github.com/xraph/relay/scope"
 	"github.com/xraph/relay/store"
 )
 
@@ -23,8 +28,34 @@ type Handler struct {
 	catalog     *catalog.Catalog
 	endpointSvc *endpoint.Service
 	dlqSvc      *dlq.Service
+	keySvc      *auth.Service
+	relay       *relay.Relay
 	logger      log.Logger
 	mux         *http.ServeMux
+
+	// scopeOf resolves the app and org a request acts for; see WithScope.
+	scopeOf func(*http.Request) (appID, orgID string)
+
+	// authn authenticates requests; nil leaves the API open. See WithAuth.
+	authn auth.Authenticator
+}
+
+// HandlerOption configures optional Handler dependencies.
+type HandlerOption func(*Handler)
+
+// WithRelay gives the handler access to the Relay instance. Routes that go
+// through the full send pipeline (such as POST /events/batch) respond with
+// 501 Not Implemented without it.
+func WithRelay(r *relay.Relay) HandlerOption {
+	return func(h *Handler) { h.relay = r }
+}
+
+// WithScope resolves the app and org each request acts for, e.g. from its
+// authenticated principal (see auth.FromContext). The scope is carried in
+// the request context (see package scope), so the events, endpoints and
+// event types it creates are stamped with it.
+func WithScope(fn func(r *http.Request) (appID, orgID string)) HandlerOption {
+	return func(h *Handler) { h.scopeOf = fn }
 }
 
 // NewHandler creates a new admin API handler.
@@ -34,6 +65,20 @@ func NewHandler(
 	epSvc *endpoint.Service,
 	dlqSvc *dlq.Service,
 	logger log.Logger,
+	opts ...HandlerOption,
+) *Handler {
+	h := newHandler(s, cat, epSvc, dlqSvc, logger, opts...)
+	h.registerRoutes()
+	return h
+}
+
+func newHandler(
+	s store.Store,
+	cat *catalog.Catalog,
+	epSvc *endpoint.Service,
+	dlqSvc *dlq.Service,
+	logger log.Logger,
+	opts ...HandlerOption,
 ) *Handler {
 	if logger == nil {
 		logger = log.NewNoopLogger()
@@ -44,46 +89,86 @@ func NewHandler(
 		catalog:     cat,
 		endpointSvc: epSvc,
 		dlqSvc:      dlqSvc,
+		keySvc:      auth.NewService(s, logger),
 		logger:      logger,
 		mux:         http.NewServeMux(),
 	}
-
-	h.registerRoutes()
+	for _, opt := range opts {
+		opt(h)
+	}
 	return h
 }
 
 func (h *Handler) registerRoutes() {
 	// Event types
-	h.mux.HandleFunc("POST /event-types", h.createEventType)
-	h.mux.HandleFunc("GET /event-types", h.listEventTypes)
-	h.mux.HandleFunc("GET /event-types/{name}", h.getEventType)
-	h.mux.HandleFunc("DELETE /event-types/{name}", h.deleteEventType)
+	h.handle("POST /event-types", auth.ScopeCatalogAdmin, h.createEventType)
+	h.handle("GET /event-types", auth.ScopeCatalogRead, h.listEventTypes)
+	h.handle("GET /event-types/{name}", auth.ScopeCatalogRead, h.getEventType)
+	h.handle("DELETE /event-types/{name}", auth.ScopeCatalogAdmin, h.deleteEventType)
+	h.handle("POST /event-types/{name}/deprecate", auth.ScopeCatalogAdmin, h.deprecateEventType)
+	h.handle("POST /event-types/{name}/undeprecate", auth.ScopeCatalogAdmin, h.undeprecateEventType)
+	h.handle("GET /event-types/{name}/subscribers", auth.ScopeEndpointsRead, h.listEventTypeSubscribers)
+	h.handle("POST /event-types/{name}/compatibility", auth.ScopeCatalogRead, h.checkEventTypeCompatibility)
+
+	// Catalog export
+	h.handle("GET /catalog/asyncapi.json", auth.ScopeCatalogRead, h.getAsyncAPI)
+	h.handle("GET /catalog/openapi.json", auth.ScopeCatalogRead, h.getOpenAPIWebhooks)
 
 	// Endpoints
-	h.mux.HandleFunc("POST /endpoints", h.createEndpoint)
-	h.mux.HandleFunc("GET /endpoints", h.listEndpoints)
-	h.mux.HandleFunc("GET /endpoints/{id}", h.getEndpoint)
-	h.mux.HandleFunc("PUT /endpoints/{id}", h.updateEndpoint)
-	h.mux.HandleFunc("DELETE /endpoints/{id}", h.deleteEndpoint)
-	h.mux.HandleFunc("PATCH /endpoints/{id}/enable", h.enableEndpoint)
-	h.mux.HandleFunc("PATCH /endpoints/{id}/disable", h.disableEndpoint)
-	h.mux.HandleFunc("POST /endpoints/{id}/rotate-secret", h.rotateSecret)
+	h.handle("POST /endpoints", auth.ScopeEndpointsAdmin, h.createEndpoint)
+	h.handle("GET /endpoints", auth.ScopeEndpointsRead, h.listEndpoints)
+	h.handle("GET /endpoints/{id}", auth.ScopeEndpointsRead, h.getEndpoint)
+	h.handle("PUT /endpoints/{id}", auth.ScopeEndpointsAdmin, h.updateEndpoint)
+	h.handle("DELETE /endpoints/{id}", auth.ScopeEndpointsAdmin, h.deleteEndpoint)
+	h.handle("PATCH /endpoints/{id}/enable", auth.ScopeEndpointsAdmin, h.enableEndpoint)
+	h.handle("PATCH /endpoints/{id}/disable", auth.ScopeEndpointsAdmin, h.disableEndpoint)
+	h.handle("POST /endpoints/{id}/rotate-secret", auth.ScopeEndpointsAdmin, h.rotateSecret)
+	h.handle("POST /endpoints/{id}/verify", auth.ScopeEndpointsAdmin, h.verifyEndpoint)
+	h.handle("POST /endpoints/{id}/test", auth.ScopeEndpointsAdmin, h.testEndpoint)
+	h.handle("GET /endpoints/{id}/health", auth.ScopeEndpointsRead, h.getEndpointHealth)
+	h.handle("POST /endpoints/{id}/backfill", auth.ScopeEndpointsAdmin, h.backfillEndpoint)
+	h.handle("GET /backfills/{id}", auth.ScopeEndpointsRead, h.getBackfill)
+	h.handle("POST /backfills/{id}/cancel", auth.ScopeEndpointsAdmin, h.cancelBackfill)
 
 	// Events
-	h.mux.HandleFunc("POST /events", h.createEvent)
-	h.mux.HandleFunc("GET /events", h.listEvents)
-	h.mux.HandleFunc("GET /events/{id}", h.getEvent)
+	h.handle("POST /events", auth.ScopeEventsWrite, h.createEvent)
+	h.handle("POST /events/batch", auth.ScopeEventsWrite, h.sendEventBatch)
+	h.handle("GET /events", auth.ScopeEventsRead, h.listEvents)
+	h.handle("GET /events/{id}", auth.ScopeEventsRead, h.getEvent)
+	h.handle("POST /events/{id}/resend", auth.ScopeDeliveriesWrite, h.resendEvent)
 
 	// Deliveries
-	h.mux.HandleFunc("GET /endpoints/{id}/deliveries", h.listDeliveries)
+	h.handle("GET /deliveries", auth.ScopeDeliveriesRead, h.listAllDeliveries)
+	h.handle("GET /deliveries/{id}", auth.ScopeDeliveriesRead, h.getDelivery)
+	h.handle("POST /deliveries/{id}/retry", auth.ScopeDeliveriesWrite, h.retryDelivery)
+	h.handle("POST /deliveries/{id}/cancel", auth.ScopeDeliveriesWrite, h.cancelDelivery)
+	h.handle("GET /endpoints/{id}/deliveries", auth.ScopeDeliveriesRead, h.listDeliveries)
+	h.handle("GET /events/{id}/deliveries", auth.ScopeDeliveriesRead, h.listEventDeliveries)
 
 	// DLQ
-	h.mux.HandleFunc("GET /dlq", h.listDLQ)
-	h.mux.HandleFunc("POST /dlq/{id}/replay", h.replayDLQ)
-	h.mux.HandleFunc("POST /dlq/replay", h.replayBulkDLQ)
+	h.handle("GET /dlq", auth.ScopeDLQRead, h.listDLQ)
+	h.handle("POST /dlq/{id}/replay", auth.ScopeDLQReplay, h.replayDLQ)
+	h.handle("POST /dlq/replay", auth.ScopeDLQReplay, h.replayBulkDLQ)
+	h.handle("POST /dlq/replay-jobs", auth.ScopeDLQReplay, h.startDLQReplay)
+	h.handle("GET /dlq/replay-jobs/{id}", auth.ScopeDLQRead, h.getDLQReplay)
+	h.handle("POST /dlq/replay-jobs/{id}/cancel", auth.ScopeDLQReplay, h.cancelDLQReplay)
+	h.handle("DELETE /dlq", auth.ScopeDLQAdmin, h.purgeDLQ)
 
 	// Stats
-	h.mux.HandleFunc("GET /stats", h.getStats)
+	h.handle("GET /stats", auth.ScopeStatsRead, h.getStats)
+
+	// API keys
+	h.handle("POST /api-keys", auth.ScopeKeysAdmin, h.createAPIKey)
+	h.handle("GET /api-keys", auth.ScopeKeysAdmin, h.listAPIKeys)
+	h.handle("DELETE /api-keys/{id}", auth.ScopeKeysAdmin, h.deleteAPIKey)
+
+	// Development tunnel (only when the relay was created WithTunnel)
+	if h.relay != nil && h.relay.Tunnel() != nil {
+		h.handle("POST /tunnel/sessions", auth.ScopeEndpointsAdmin, h.openTunnel)
+		h.handle("GET /tunnel/sessions/{id}/stream", auth.ScopeEndpointsAdmin, h.streamTunnel)
+		h.handle("POST /tunnel/sessions/{id}/responses", auth.ScopeEndpointsAdmin, h.respondTunnel)
+		h.handle("DELETE /tunnel/sessions/{id}", auth.ScopeEndpointsAdmin, h.closeTunnel)
+	}
 }
 
 // ServeHTTP implements http.Handler.
@@ -92,7 +177,18 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *Handler) withMiddleware(next http.Handler) http.Handler {
-	return h.panicRecovery(h.logging(next))
+	return h.panicRecovery(h.logging(h.authenticate(h.scoped(next))))
+}
+
+// scoped restores the request's scope into its context.
+func (h *Handler) scoped(next http.Handler) http.Handler {
+	if h.scopeOf == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		appID, orgID := h.scopeOf(r)
+		next.ServeHTTP(w, r.WithContext(scope.Restore(r.Context(), appID, orgID)))
+	})
 }
 
 func (h *Handler) logging(next http.Handler) http.Handler {
@@ -135,6 +231,13 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 }
 
+// Flush passes flushes through for streaming responses.
+func (rw *responseWriter) Flush() {
+	if f, ok := rw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
 // JSON helpers.
 
 func writeJSON(w http.ResponseWriter, status int, v any) {
@@ -172,3 +275,49 @@ func queryInt(r *http.Request, key string, defaultVal int) int {
 	}
 	return n
 }
+
+// listParams holds the pagination query parameters of a list request.
+type listParams struct {
+	cursor       id.ID
+	offset       int
+	limit        int
+	includeTotal bool
+}
+
+// readListParams parses limit, offset, cursor and include_total. cursor
+// must be an ID with prefix; if it is not, readListParams writes a 400 and
+// returns false.
+func readListParams(w http.ResponseWriter, r *http.Request, prefix id.Prefix) (listParams, bool) {
+	p := listParams{
+		offset:       queryInt(r, "offset", 0),
+		limit:        queryInt(r, "limit", page.DefaultLimit),
+		includeTotal: queryParam(r, "include_total") == "true",
+	}
+	if p.limit <= 0 {
+		p.limit = page.DefaultLimit
+	}
+	if c := queryParam(r, "cursor"); c != "" {
+		cursor, err := id.ParseWithPrefix(c, prefix)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid cursor")
+			return p, false
+		}
+		p.cursor = cursor
+	}
+	return p, true
+}
+
+// writePage writes items, listed with a limit of p.limit+1, as a page.
+// count is only called when the total was asked for.
+func writePage[T any](w http.ResponseWriter, p listParams, items []T, key func(T) id.ID, count func() (int64, error)) {
+	result := page.New(items, p.limit, key)
+	if p.includeTotal {
+		total, err := count()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		result.Total = &total
+	}
+	writeJSON(w, http.StatusOK, result)
+}
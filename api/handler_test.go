@@ -4,17 +4,28 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	log "github.com/xraph/go-utils/log"
 
+	"github.com/xraph/relay"
 	"github.com/xraph/relay/api"
+	"github.com/xraph/relay/auth"
 	"github.com/xraph/relay/catalog"
 	"github.com/xraph/relay/dlq"
 	"github.com/xraph/relay/endpoint"
+	"github.com/xraph/relay/event"
+	"github.com/xraph/relay/id"
+	"github.com/xraph/relay/page"
+	"github.com/xraph/relay/portal"
 	"github.com/xraph/relay/store/memory"
 )
 
@@ -32,6 +43,21 @@ func testServer(t *testing.T) *httptest.Server {
 	return httptest.NewServer(h)
 }
 
+// relayServer creates a Handler wired to a full Relay instance, for routes
+// that go through the send pipeline.
+func relayServer(t *testing.T) (*httptest.Server, *relay.Relay) {
+	t.Helper()
+
+	s := memory.New()
+	r, err := relay.New(relay.WithStore(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := api.NewHandler(s, r.Catalog(), r.Endpoints(), r.DLQ(), nil, api.WithRelay(r))
+	return httptest.NewServer(h), r
+}
+
 func doJSON(t *testing.T, method, url string, body any) *http.Response {
 	t.Helper()
 	var r io.Reader
@@ -98,10 +124,10 @@ func TestEventTypes_CRUD(t *testing.T) {
 	if resp.StatusCode != http.StatusOK {
 		t.Fatalf("list: expected 200, got %d", resp.StatusCode)
 	}
-	var list []map[string]any
+	var list page.Page[map[string]any]
 	decodeBody(t, resp, &list)
-	if len(list) != 1 {
-		t.Fatalf("expected 1 event type, got %d", len(list))
+	if len(list.Data) != 1 {
+		t.Fatalf("expected 1 event type, got %d", len(list.Data))
 	}
 
 	// Delete (soft-delete marks as deprecated)
@@ -123,6 +149,64 @@ func TestEventTypes_CRUD(t *testing.T) {
 	}
 }
 
+func TestEventTypes_Deprecation(t *testing.T) {
+	srv := testServer(t)
+	defer srv.Close()
+
+	resp := doJSON(t, "POST", srv.URL+"/event-types", map[string]any{"name": "order.created"})
+	resp.Body.Close()
+	resp = doJSON(t, "POST", srv.URL+"/endpoints", map[string]any{
+		"tenant_id":   "t1",
+		"url":         "https://example.com/hook",
+		"event_types": []string{"order.*"},
+	})
+	resp.Body.Close()
+
+	// Deprecate requires a sunset date.
+	resp = doJSON(t, "POST", srv.URL+"/event-types/order.created/deprecate", map[string]any{})
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("deprecate without sunset: expected 400, got %d", resp.StatusCode)
+	}
+	resp.Body.Close()
+
+	resp = doJSON(t, "POST", srv.URL+"/event-types/order.created/deprecate", map[string]any{
+		"sunset_at": "2099-01-01T00:00:00Z",
+	})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("deprecate: expected 200, got %d", resp.StatusCode)
+	}
+	var et map[string]any
+	decodeBody(t, resp, &et)
+	if et["deprecated"] != true || et["sunset_at"] != "2099-01-01T00:00:00Z" {
+		t.Fatalf("expected deprecated with sunset, got %v", et)
+	}
+
+	resp = doJSON(t, "GET", srv.URL+"/event-types/order.created/subscribers", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("subscribers: expected 200, got %d", resp.StatusCode)
+	}
+	var subs []map[string]any
+	decodeBody(t, resp, &subs)
+	if len(subs) != 1 {
+		t.Fatalf("expected 1 subscriber, got %d", len(subs))
+	}
+
+	resp = doJSON(t, "POST", srv.URL+"/event-types/order.created/undeprecate", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("undeprecate: expected 200, got %d", resp.StatusCode)
+	}
+	decodeBody(t, resp, &et)
+	if et["deprecated"] != false {
+		t.Fatalf("expected deprecated=false, got %v", et["deprecated"])
+	}
+
+	resp = doJSON(t, "GET", srv.URL+"/event-types/missing.type/subscribers", nil)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("subscribers of unknown type: expected 404, got %d", resp.StatusCode)
+	}
+	resp.Body.Close()
+}
+
 func TestEventTypes_CreateMissingName(t *testing.T) {
 	srv := testServer(t)
 	defer srv.Close()
@@ -170,10 +254,10 @@ func TestEndpoints_CRUD(t *testing.T) {
 	if resp.StatusCode != http.StatusOK {
 		t.Fatalf("list: expected 200, got %d", resp.StatusCode)
 	}
-	var eps []map[string]any
+	var eps page.Page[map[string]any]
 	decodeBody(t, resp, &eps)
-	if len(eps) != 1 {
-		t.Fatalf("expected 1 endpoint, got %d", len(eps))
+	if len(eps.Data) != 1 {
+		t.Fatalf("expected 1 endpoint, got %d", len(eps.Data))
 	}
 
 	// Update
@@ -230,6 +314,50 @@ func TestEndpoints_CRUD(t *testing.T) {
 	resp.Body.Close()
 }
 
+func TestEndpoints_CursorPagination(t *testing.T) {
+	srv := testServer(t)
+	defer srv.Close()
+
+	var created []string
+	for i := range 3 {
+		resp := doJSON(t, "POST", srv.URL+"/endpoints", map[string]any{
+			"tenant_id":   "tenant-1",
+			"url":         fmt.Sprintf("https://example.com/webhook/%d", i),
+			"event_types": []string{"order.*"},
+		})
+		var ep map[string]any
+		decodeBody(t, resp, &ep)
+		created = append(created, ep["id"].(string))
+	}
+	slices.Sort(created)
+
+	resp := doJSON(t, "GET", srv.URL+"/endpoints?tenant_id=tenant-1&limit=2&include_total=true", nil)
+	var first page.Page[map[string]any]
+	decodeBody(t, resp, &first)
+	if len(first.Data) != 2 || !first.HasMore || first.NextCursor != created[1] {
+		t.Fatalf("first page: got %d items, has_more %v, cursor %q", len(first.Data), first.HasMore, first.NextCursor)
+	}
+	if first.Total == nil || *first.Total != 3 {
+		t.Fatalf("expected total 3, got %v", first.Total)
+	}
+
+	resp = doJSON(t, "GET", srv.URL+"/endpoints?tenant_id=tenant-1&limit=2&cursor="+first.NextCursor, nil)
+	var second page.Page[map[string]any]
+	decodeBody(t, resp, &second)
+	if len(second.Data) != 1 || second.Data[0]["id"] != created[2] || second.HasMore || second.NextCursor != "" {
+		t.Fatalf("second page: got %v", second)
+	}
+	if second.Total != nil {
+		t.Fatal("expected no total unless requested")
+	}
+
+	resp = doJSON(t, "GET", srv.URL+"/endpoints?tenant_id=tenant-1&cursor=not-an-id", nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("invalid cursor: expected 400, got %d", resp.StatusCode)
+	}
+}
+
 func TestEndpoints_ListRequiresTenantID(t *testing.T) {
 	srv := testServer(t)
 	defer srv.Close()
@@ -243,6 +371,138 @@ func TestEndpoints_ListRequiresTenantID(t *testing.T) {
 
 // --- Events ---
 
+func TestEndpoints_Verify(t *testing.T) {
+	s := memory.New()
+	r, err := relay.New(relay.WithStore(s), relay.WithEndpointVerification())
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(api.NewHandler(s, r.Catalog(), r.Endpoints(), r.DLQ(), nil, api.WithRelay(r)))
+	defer srv.Close()
+
+	var echo atomic.Bool
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var body struct {
+			Challenge string `json:"challenge"`
+		}
+		json.NewDecoder(req.Body).Decode(&body) //nolint:errcheck // test server
+		if echo.Load() {
+			io.WriteString(w, body.Challenge) //nolint:errcheck // test server
+		}
+	}))
+	defer target.Close()
+
+	resp := doJSON(t, "POST", srv.URL+"/endpoints", map[string]any{
+		"tenant_id":   "tenant-1",
+		"url":         target.URL,
+		"event_types": []string{"*"},
+	})
+	var ep map[string]any
+	decodeBody(t, resp, &ep)
+	if ep["status"] != "pending_verification" || ep["enabled"] != false {
+		t.Fatalf("expected a pending endpoint, got %v", ep)
+	}
+	epURL := srv.URL + "/endpoints/" + ep["id"].(string)
+
+	resp = doJSON(t, "PATCH", epURL+"/enable", nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusConflict {
+		t.Fatalf("expected 409 enabling a pending endpoint, got %d", resp.StatusCode)
+	}
+
+	resp = doJSON(t, "POST", epURL+"/verify", nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422 without an echo, got %d", resp.StatusCode)
+	}
+
+	echo.Store(true)
+	resp = doJSON(t, "POST", epURL+"/verify", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	decodeBody(t, resp, &ep)
+	if ep["status"] != "active" || ep["enabled"] != true || ep["verified_at"] == nil {
+		t.Fatalf("expected a verified endpoint, got %v", ep)
+	}
+}
+
+func TestEndpoints_Test(t *testing.T) {
+	srv, r := relayServer(t)
+	defer srv.Close()
+
+	if _, err := r.RegisterEventType(context.Background(), catalog.WebhookDefinition{
+		Name:    "order.created",
+		Example: json.RawMessage(`{"id":"ord_1"}`),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer target.Close()
+
+	ep, err := r.Endpoints().Create(context.Background(), endpoint.Input{
+		TenantID:   "tenant-1",
+		URL:        target.URL,
+		EventTypes: []string{"order.*"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp := doJSON(t, "POST", srv.URL+"/endpoints/"+ep.ID.String()+"/test", map[string]any{
+		"event_type": "order.created",
+	})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	var got map[string]any
+	decodeBody(t, resp, &got)
+	if got["success"] != true || got["status_code"] != float64(http.StatusNoContent) {
+		t.Fatalf("unexpected result %v", got)
+	}
+
+	resp = doJSON(t, "POST", srv.URL+"/endpoints/"+ep.ID.String()+"/test", map[string]any{
+		"event_type": "unknown.type",
+	})
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown type, got %d", resp.StatusCode)
+	}
+}
+
+func TestEndpoints_Health(t *testing.T) {
+	srv, r := relayServer(t)
+	defer srv.Close()
+
+	ep, err := r.Endpoints().Create(context.Background(), endpoint.Input{
+		TenantID:   "tenant-1",
+		URL:        "https://example.com/hook",
+		EventTypes: []string{"*"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp := doJSON(t, "GET", srv.URL+"/endpoints/"+ep.ID.String()+"/health", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	var got map[string]any
+	decodeBody(t, resp, &got)
+	if got["status"] != "unknown" || got["score"] != float64(100) || got["enabled"] != true {
+		t.Fatalf("unexpected health %v", got)
+	}
+
+	resp = doJSON(t, "GET", srv.URL+"/endpoints/"+id.NewEndpointID().String()+"/health", nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", resp.StatusCode)
+	}
+}
+
 func TestEvents_CreateAndGet(t *testing.T) {
 	srv := testServer(t)
 	defer srv.Close()
@@ -275,10 +535,10 @@ func TestEvents_CreateAndGet(t *testing.T) {
 	if resp.StatusCode != http.StatusOK {
 		t.Fatalf("list: expected 200, got %d", resp.StatusCode)
 	}
-	var events []map[string]any
+	var events page.Page[map[string]any]
 	decodeBody(t, resp, &events)
-	if len(events) != 1 {
-		t.Fatalf("expected 1 event, got %d", len(events))
+	if len(events.Data) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events.Data))
 	}
 }
 
@@ -305,6 +565,175 @@ func TestEvents_CreateMissingFields(t *testing.T) {
 	resp.Body.Close()
 }
 
+func TestEvents_CreateWithRelay(t *testing.T) {
+	srv, r := relayServer(t)
+	defer srv.Close()
+
+	if _, err := r.RegisterEventType(context.Background(), catalog.WebhookDefinition{Name: "order.created"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Endpoints().Create(context.Background(), endpoint.Input{
+		TenantID:   "tenant-1",
+		URL:        "https://example.com/webhook",
+		EventTypes: []string{"order.*"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp := doJSON(t, "POST", srv.URL+"/events", map[string]any{
+		"type":      "order.created",
+		"tenant_id": "tenant-1",
+	})
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", resp.StatusCode)
+	}
+	var got relay.SendResult
+	decodeBody(t, resp, &got)
+	if got.EventID.IsNil() || len(got.DeliveryIDs) != 1 {
+		t.Fatalf("expected an event with one delivery, got %+v", got)
+	}
+
+	// The relay validates against the catalog.
+	resp = doJSON(t, "POST", srv.URL+"/events", map[string]any{
+		"type":      "unknown.type",
+		"tenant_id": "tenant-1",
+	})
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown type, got %d", resp.StatusCode)
+	}
+}
+
+func TestEvents_DryRun(t *testing.T) {
+	srv, r := relayServer(t)
+	defer srv.Close()
+
+	if _, err := r.RegisterEventType(context.Background(), catalog.WebhookDefinition{Name: "order.created"}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp := doJSON(t, "POST", srv.URL+"/events?dry_run=true", map[string]any{
+		"type":      "order.created",
+		"tenant_id": "tenant-1",
+	})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	var got map[string]any
+	decodeBody(t, resp, &got)
+	if got["dry_run"] != true {
+		t.Fatalf("expected dry_run in response, got %v", got)
+	}
+
+	resp = doJSON(t, "GET", srv.URL+"/events", nil)
+	var events page.Page[map[string]any]
+	decodeBody(t, resp, &events)
+	if len(events.Data) != 0 {
+		t.Fatalf("dry run persisted %d events", len(events.Data))
+	}
+
+	resp = doJSON(t, "POST", srv.URL+"/events?dry_run=true", map[string]any{
+		"type":      "unknown.type",
+		"tenant_id": "tenant-1",
+	})
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown type, got %d", resp.StatusCode)
+	}
+}
+
+func TestEvents_Batch(t *testing.T) {
+	srv, r := relayServer(t)
+	defer srv.Close()
+
+	if _, err := r.RegisterEventType(context.Background(), catalog.WebhookDefinition{Name: "order.created"}); err != nil {
+		t.Fatal(err)
+	}
+
+	type batchResponse struct {
+		Accepted   int `json:"accepted"`
+		Duplicates int `json:"duplicates"`
+		Rejected   int `json:"rejected"`
+		Results    []struct {
+			Index     int    `json:"index"`
+			EventID   string `json:"event_id"`
+			Duplicate bool   `json:"duplicate"`
+			Error     string `json:"error"`
+		} `json:"results"`
+	}
+
+	// JSON array.
+	resp := doJSON(t, "POST", srv.URL+"/events/batch", []map[string]any{
+		{"type": "order.created", "tenant_id": "tenant-1", "idempotency_key": "a"},
+		{"type": "order.created"},
+		{"type": "order.created", "tenant_id": "tenant-1", "idempotency_key": "a"},
+	})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("array: expected 200, got %d", resp.StatusCode)
+	}
+	var got batchResponse
+	decodeBody(t, resp, &got)
+	if got.Accepted != 1 || got.Rejected != 1 || got.Duplicates != 1 {
+		t.Fatalf("array: unexpected counts %+v", got)
+	}
+	if got.Results[0].EventID == "" || got.Results[1].Error == "" || !got.Results[2].Duplicate {
+		t.Fatalf("array: unexpected results %+v", got.Results)
+	}
+
+	// NDJSON.
+	body := strings.NewReader(`{"type":"order.created","tenant_id":"tenant-1"}
+{"type":"unknown.type","tenant_id":"tenant-1"}
+`)
+	req, err := http.NewRequestWithContext(context.Background(), "POST", srv.URL+"/events/batch", body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("ndjson: expected 200, got %d", resp.StatusCode)
+	}
+	got = batchResponse{}
+	decodeBody(t, resp, &got)
+	if got.Accepted != 1 || got.Rejected != 1 {
+		t.Fatalf("ndjson: unexpected counts %+v", got)
+	}
+	if got.Results[1].Index != 1 || got.Results[1].Error == "" {
+		t.Fatalf("ndjson: unexpected results %+v", got.Results)
+	}
+}
+
+func TestEvents_BatchTooLarge(t *testing.T) {
+	srv, _ := relayServer(t)
+	defer srv.Close()
+
+	items := make([]map[string]any, 10001)
+	for i := range items {
+		items[i] = map[string]any{"type": "order.created", "tenant_id": "tenant-1"}
+	}
+	resp := doJSON(t, "POST", srv.URL+"/events/batch", items)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413, got %d", resp.StatusCode)
+	}
+}
+
+func TestEvents_BatchWithoutRelay(t *testing.T) {
+	srv := testServer(t)
+	defer srv.Close()
+
+	resp := doJSON(t, "POST", srv.URL+"/events/batch", []map[string]any{
+		{"type": "order.created", "tenant_id": "tenant-1"},
+	})
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNotImplemented {
+		t.Fatalf("expected 501, got %d", resp.StatusCode)
+	}
+}
+
 // --- Stats ---
 
 func TestStats(t *testing.T) {
@@ -326,6 +755,44 @@ func TestStats(t *testing.T) {
 	}
 }
 
+func TestStats_DLQSizeLeavesOutReplayed(t *testing.T) {
+	srv, r := relayServer(t)
+	defer srv.Close()
+
+	entry := &dlq.Entry{
+		ID:         id.NewDLQID(),
+		DeliveryID: id.NewDeliveryID(),
+		EventID:    id.NewEventID(),
+		EndpointID: id.NewEndpointID(),
+		TenantID:   "tenant-1",
+		FailedAt:   time.Now().UTC(),
+	}
+	if err := r.Store().Push(context.Background(), entry); err != nil {
+		t.Fatal(err)
+	}
+
+	dlqSize := func() float64 {
+		t.Helper()
+		resp := doJSON(t, "GET", srv.URL+"/stats", nil)
+		var stats map[string]any
+		decodeBody(t, resp, &stats)
+		size, _ := stats["dlq_size"].(float64)
+		return size
+	}
+	if got := dlqSize(); got != 1 {
+		t.Fatalf("expected dlq_size 1, got %v", got)
+	}
+
+	resp := doJSON(t, "POST", srv.URL+"/dlq/"+entry.ID.String()+"/replay", nil)
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("replay: expected 204, got %d", resp.StatusCode)
+	}
+	resp.Body.Close()
+	if got := dlqSize(); got != 0 {
+		t.Fatalf("expected dlq_size 0 after replay, got %v", got)
+	}
+}
+
 // --- DLQ ---
 
 func TestDLQ_ListEmpty(t *testing.T) {
@@ -336,10 +803,10 @@ func TestDLQ_ListEmpty(t *testing.T) {
 	if resp.StatusCode != http.StatusOK {
 		t.Fatalf("list dlq: expected 200, got %d", resp.StatusCode)
 	}
-	var entries []map[string]any
+	var entries page.Page[map[string]any]
 	decodeBody(t, resp, &entries)
-	if len(entries) != 0 {
-		t.Fatalf("expected 0 entries, got %d", len(entries))
+	if len(entries.Data) != 0 {
+		t.Fatalf("expected 0 entries, got %d", len(entries.Data))
 	}
 }
 
@@ -369,6 +836,92 @@ func TestDLQ_BulkReplayBadBody(t *testing.T) {
 	resp.Body.Close()
 }
 
+func TestDLQ_Purge(t *testing.T) {
+	srv := testServer(t)
+	defer srv.Close()
+
+	resp := doJSON(t, "DELETE", srv.URL+"/dlq?before=2025-01-01T00:00:00Z", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("purge: expected 200, got %d", resp.StatusCode)
+	}
+	var result map[string]int64
+	decodeBody(t, resp, &result)
+	if result["purged"] != 0 {
+		t.Fatalf("expected 0 purged, got %d", result["purged"])
+	}
+
+	resp = doJSON(t, "DELETE", srv.URL+"/dlq", nil)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("purge without before: expected 400, got %d", resp.StatusCode)
+	}
+	resp.Body.Close()
+}
+
+func TestDLQ_ReplayJobs(t *testing.T) {
+	srv, r := relayServer(t)
+	defer srv.Close()
+
+	for _, status := range []int{502, 502, 404} {
+		err := r.Store().Push(context.Background(), &dlq.Entry{
+			ID:             id.NewDLQID(),
+			DeliveryID:     id.NewDeliveryID(),
+			EventID:        id.NewEventID(),
+			EndpointID:     id.NewEndpointID(),
+			EventType:      "order.created",
+			TenantID:       "tenant-1",
+			Error:          fmt.Sprintf("unexpected status %d", status),
+			LastStatusCode: status,
+			FailedAt:       time.Now().UTC(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	filters := map[string]any{"tenant_id": "tenant-1", "error_contains": "502", "dry_run": true}
+	resp := doJSON(t, "POST", srv.URL+"/dlq/replay-jobs", filters)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("dry run: expected 200, got %d", resp.StatusCode)
+	}
+	var dry map[string]int64
+	decodeBody(t, resp, &dry)
+	if dry["count"] != 2 {
+		t.Fatalf("dry run: expected 2 matching entries, got %d", dry["count"])
+	}
+
+	filters["dry_run"] = false
+	filters["rate"] = 1000
+	resp = doJSON(t, "POST", srv.URL+"/dlq/replay-jobs", filters)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("replay: expected 202, got %d", resp.StatusCode)
+	}
+	var j map[string]any
+	decodeBody(t, resp, &j)
+	jobID := j["id"].(string)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for j["state"] == "running" && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+		resp = doJSON(t, "GET", srv.URL+"/dlq/replay-jobs/"+jobID, nil)
+		decodeBody(t, resp, &j)
+	}
+	if j["state"] != "completed" || j["processed"] != float64(2) {
+		t.Fatalf("expected 2 entries replayed, got %v", j)
+	}
+
+	resp = doJSON(t, "POST", srv.URL+"/dlq/replay-jobs/"+jobID+"/cancel", nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusConflict {
+		t.Fatalf("cancel finished job: expected 409, got %d", resp.StatusCode)
+	}
+
+	resp = doJSON(t, "POST", srv.URL+"/dlq/replay-jobs", map[string]any{"endpoint_id": "nope"})
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("invalid endpoint ID: expected 400, got %d", resp.StatusCode)
+	}
+}
+
 // --- Deliveries ---
 
 func TestDeliveries_ListEmpty(t *testing.T) {
@@ -392,21 +945,191 @@ func TestDeliveries_ListEmpty(t *testing.T) {
 	if resp.StatusCode != http.StatusOK {
 		t.Fatalf("list deliveries: expected 200, got %d", resp.StatusCode)
 	}
-	var deliveries []map[string]any
+	var deliveries page.Page[map[string]any]
 	decodeBody(t, resp, &deliveries)
-	if len(deliveries) != 0 {
-		t.Fatalf("expected 0 deliveries, got %d", len(deliveries))
+	if len(deliveries.Data) != 0 {
+		t.Fatalf("expected 0 deliveries, got %d", len(deliveries.Data))
 	}
 }
 
-// --- Invalid IDs ---
-
-func TestEndpoint_InvalidID(t *testing.T) {
-	srv := testServer(t)
+func TestDeliveries_Manage(t *testing.T) {
+	srv, r := relayServer(t)
 	defer srv.Close()
+	ctx := context.Background()
 
-	resp := doJSON(t, "GET", srv.URL+"/endpoints/not-a-valid-id", nil)
-	if resp.StatusCode != http.StatusBadRequest {
+	if _, err := r.RegisterEventType(ctx, catalog.WebhookDefinition{Name: "order.created"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, tenantID := range []string{"tenant-1", "tenant-2"} {
+		if _, err := r.Endpoints().Create(ctx, endpoint.Input{
+			TenantID:   tenantID,
+			URL:        "https://example.com/hook",
+			EventTypes: []string{"*"},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	evt := &event.Event{Type: "order.created", TenantID: "tenant-1", Data: json.RawMessage(`{}`)}
+	if err := r.Send(ctx, evt); err != nil {
+		t.Fatal(err)
+	}
+
+	resp := doJSON(t, "GET", srv.URL+"/deliveries?tenant_id=tenant-1&include_total=true", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("list deliveries: expected 200, got %d", resp.StatusCode)
+	}
+	var list page.Page[map[string]any]
+	decodeBody(t, resp, &list)
+	if len(list.Data) != 1 || list.Total == nil || *list.Total != 1 {
+		t.Fatalf("expected 1 delivery for tenant-1, got %d", len(list.Data))
+	}
+	delID := list.Data[0]["id"].(string)
+
+	resp = doJSON(t, "GET", srv.URL+"/events/"+evt.ID.String()+"/deliveries", nil)
+	decodeBody(t, resp, &list)
+	if len(list.Data) != 1 || list.Data[0]["id"] != delID {
+		t.Fatalf("expected the event's delivery, got %v", list.Data)
+	}
+
+	resp = doJSON(t, "POST", srv.URL+"/deliveries/"+delID+"/retry", nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("retry: expected 200, got %d", resp.StatusCode)
+	}
+
+	resp = doJSON(t, "POST", srv.URL+"/deliveries/"+delID+"/cancel", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("cancel: expected 200, got %d", resp.StatusCode)
+	}
+	var got map[string]any
+	decodeBody(t, resp, &got)
+	if got["state"] != "cancelled" {
+		t.Fatalf("expected cancelled, got %v", got["state"])
+	}
+
+	resp = doJSON(t, "POST", srv.URL+"/deliveries/"+delID+"/retry", nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusConflict {
+		t.Fatalf("retry cancelled: expected 409, got %d", resp.StatusCode)
+	}
+
+	resp = doJSON(t, "GET", srv.URL+"/deliveries/"+id.NewDeliveryID().String(), nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("get unknown: expected 404, got %d", resp.StatusCode)
+	}
+
+	resp = doJSON(t, "GET", srv.URL+"/deliveries?from=yesterday", nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("bad from: expected 400, got %d", resp.StatusCode)
+	}
+}
+
+func TestEvents_Resend(t *testing.T) {
+	srv, r := relayServer(t)
+	defer srv.Close()
+	ctx := context.Background()
+
+	if _, err := r.RegisterEventType(ctx, catalog.WebhookDefinition{Name: "order.created"}); err != nil {
+		t.Fatal(err)
+	}
+	evt := &event.Event{Type: "order.created", TenantID: "tenant-1", Data: json.RawMessage(`{}`)}
+	if err := r.Send(ctx, evt); err != nil {
+		t.Fatal(err)
+	}
+	ep, err := r.Endpoints().Create(ctx, endpoint.Input{
+		TenantID:   "tenant-1",
+		URL:        "https://example.com/hook",
+		EventTypes: []string{"order.*"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp := doJSON(t, "POST", srv.URL+"/events/"+evt.ID.String()+"/resend", nil)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("resend: expected 202, got %d", resp.StatusCode)
+	}
+	var res relay.SendResult
+	decodeBody(t, resp, &res)
+	if len(res.DeliveryIDs) != 1 || res.EndpointIDs[0] != ep.ID {
+		t.Fatalf("expected one delivery to the new endpoint, got %+v", res)
+	}
+
+	resp = doJSON(t, "POST", srv.URL+"/events/"+evt.ID.String()+"/resend", map[string]any{
+		"endpoint_id": id.NewEndpointID().String(),
+	})
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("resend to unknown endpoint: expected 404, got %d", resp.StatusCode)
+	}
+}
+
+func TestEndpoints_Backfill(t *testing.T) {
+	srv, r := relayServer(t)
+	defer srv.Close()
+	ctx := context.Background()
+
+	if _, err := r.RegisterEventType(ctx, catalog.WebhookDefinition{Name: "order.created"}); err != nil {
+		t.Fatal(err)
+	}
+	for range 3 {
+		if err := r.Send(ctx, &event.Event{Type: "order.created", TenantID: "tenant-1", Data: json.RawMessage(`{}`)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ep, err := r.Endpoints().Create(ctx, endpoint.Input{
+		TenantID:   "tenant-1",
+		URL:        "https://example.com/hook",
+		EventTypes: []string{"*"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp := doJSON(t, "POST", srv.URL+"/endpoints/"+ep.ID.String()+"/backfill", map[string]any{
+		"from": time.Now().Add(-time.Hour).UTC().Format(time.RFC3339),
+		"rate": 1000,
+	})
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("backfill: expected 202, got %d", resp.StatusCode)
+	}
+	var j map[string]any
+	decodeBody(t, resp, &j)
+	jobID := j["id"].(string)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for j["state"] == "running" && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+		resp = doJSON(t, "GET", srv.URL+"/backfills/"+jobID, nil)
+		decodeBody(t, resp, &j)
+	}
+	if j["state"] != "completed" || j["processed"] != float64(3) {
+		t.Fatalf("expected 3 events backfilled, got %v", j)
+	}
+
+	resp = doJSON(t, "POST", srv.URL+"/backfills/"+jobID+"/cancel", nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusConflict {
+		t.Fatalf("cancel finished job: expected 409, got %d", resp.StatusCode)
+	}
+
+	resp = doJSON(t, "GET", srv.URL+"/backfills/"+id.NewJobID().String(), nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("unknown job: expected 404, got %d", resp.StatusCode)
+	}
+}
+
+// --- Invalid IDs ---
+
+func TestEndpoint_InvalidID(t *testing.T) {
+	srv := testServer(t)
+	defer srv.Close()
+
+	resp := doJSON(t, "GET", srv.URL+"/endpoints/not-a-valid-id", nil)
+	if resp.StatusCode != http.StatusBadRequest {
 		t.Fatalf("expected 400, got %d", resp.StatusCode)
 	}
 	resp.Body.Close()
@@ -422,3 +1145,367 @@ func TestEvent_InvalidID(t *testing.T) {
 	}
 	resp.Body.Close()
 }
+
+func TestEventTypes_Compatibility(t *testing.T) {
+	s := memory.New()
+	logger := log.NewNoopLogger()
+	cat := catalog.NewCatalog(s, catalog.Config{CompatPolicy: catalog.CompatPolicyReject}, logger)
+	srv := httptest.NewServer(api.NewHandler(s, cat, endpoint.NewService(s, logger), dlq.NewService(s, logger), logger))
+	defer srv.Close()
+
+	schema := map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"id": map[string]any{"type": "string"}},
+	}
+	resp := doJSON(t, "POST", srv.URL+"/event-types", map[string]any{"name": "order.created", "schema": schema})
+	resp.Body.Close()
+
+	breaking := map[string]any{
+		"type":       "object",
+		"required":   []string{"total"},
+		"properties": map[string]any{"total": map[string]any{"type": "number"}},
+	}
+
+	resp = doJSON(t, "POST", srv.URL+"/event-types/order.created/compatibility", map[string]any{"schema": breaking})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("compatibility: expected 200, got %d", resp.StatusCode)
+	}
+	var report catalog.CompatReport
+	decodeBody(t, resp, &report)
+	if report.Compatibility != catalog.CompatBreaking || len(report.Changes) != 2 {
+		t.Fatalf("expected 2 breaking changes, got %+v", report)
+	}
+
+	resp = doJSON(t, "POST", srv.URL+"/event-types", map[string]any{"name": "order.created", "schema": breaking})
+	if resp.StatusCode != http.StatusConflict {
+		t.Fatalf("breaking register: expected 409, got %d", resp.StatusCode)
+	}
+	var conflict struct {
+		Error  string               `json:"error"`
+		Report catalog.CompatReport `json:"report"`
+	}
+	decodeBody(t, resp, &conflict)
+	if conflict.Error == "" || conflict.Report.Compatibility != catalog.CompatBreaking {
+		t.Fatalf("expected error with report, got %+v", conflict)
+	}
+
+	resp = doJSON(t, "POST", srv.URL+"/event-types?force=true", map[string]any{"name": "order.created", "schema": breaking})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("forced register: expected 201, got %d", resp.StatusCode)
+	}
+	resp.Body.Close()
+}
+
+func TestCatalog_Export(t *testing.T) {
+	srv := testServer(t)
+	defer srv.Close()
+
+	resp := doJSON(t, "POST", srv.URL+"/event-types", map[string]any{
+		"name":   "order.created",
+		"schema": map[string]any{"type": "object"},
+	})
+	resp.Body.Close()
+
+	resp = doJSON(t, "GET", srv.URL+"/catalog/asyncapi.json?title=Orders", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("asyncapi: expected 200, got %d", resp.StatusCode)
+	}
+	var asyncDoc catalog.AsyncAPIDocument
+	decodeBody(t, resp, &asyncDoc)
+	if asyncDoc.Info.Title != "Orders" || asyncDoc.Channels["order.created"].Address != "order.created" {
+		t.Fatalf("unexpected asyncapi document: %+v", asyncDoc)
+	}
+
+	resp = doJSON(t, "GET", srv.URL+"/catalog/openapi.json", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("openapi: expected 200, got %d", resp.StatusCode)
+	}
+	var openDoc catalog.OpenAPIDocument
+	decodeBody(t, resp, &openDoc)
+	if openDoc.Webhooks["order.created"].Post == nil {
+		t.Fatalf("expected order.created webhook, got %+v", openDoc.Webhooks)
+	}
+}
+
+// --- Authentication ---
+
+// authServer creates a Handler that requires API keys and returns it with
+// an unrestricted admin key.
+func authServer(t *testing.T) (*httptest.Server, *relay.Relay, string) {
+	t.Helper()
+
+	s := memory.New()
+	r, err := relay.New(relay.WithStore(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, admin, err := r.APIKeys().CreateKey(context.Background(), auth.KeyInput{
+		Name:   "admin",
+		Scopes: []string{auth.ScopeAll},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := api.NewHandler(s, r.Catalog(), r.Endpoints(), r.DLQ(), nil,
+		api.WithRelay(r), api.WithAuth(auth.APIKeys(r.APIKeys())))
+	return httptest.NewServer(h), r, admin
+}
+
+func doAuth(t *testing.T, key, method, url string, body any) *http.Response {
+	t.Helper()
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		r = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(context.Background(), method, url, r)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	if key != "" {
+		req.Header.Set("Authorization", "Bearer "+key)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("do: %v", err)
+	}
+	return resp
+}
+
+func TestAuth_RequiresCredentials(t *testing.T) {
+	srv, _, admin := authServer(t)
+	defer srv.Close()
+
+	resp := doAuth(t, "", "GET", srv.URL+"/stats", nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without a key, got %d", resp.StatusCode)
+	}
+	if resp.Header.Get("WWW-Authenticate") == "" {
+		t.Fatal("expected a WWW-Authenticate challenge")
+	}
+
+	resp = doAuth(t, auth.KeyPrefix+"unknown", "GET", srv.URL+"/stats", nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for an unknown key, got %d", resp.StatusCode)
+	}
+
+	resp = doAuth(t, admin, "GET", srv.URL+"/stats", nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 for the admin key, got %d", resp.StatusCode)
+	}
+}
+
+func TestAuth_Scopes(t *testing.T) {
+	srv, _, admin := authServer(t)
+	defer srv.Close()
+
+	var created struct {
+		ID  string `json:"id"`
+		Key string `json:"key"`
+	}
+	resp := doAuth(t, admin, "POST", srv.URL+"/api-keys", map[string]any{
+		"name":   "reader",
+		"scopes": []string{auth.ScopeEndpointsRead},
+	})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("create key: expected 201, got %d", resp.StatusCode)
+	}
+	decodeBody(t, resp, &created)
+	if created.Key == "" {
+		t.Fatal("expected the key secret in the response")
+	}
+
+	resp = doAuth(t, created.Key, "GET", srv.URL+"/endpoints?tenant_id=t1", nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("read with endpoints:read: expected 200, got %d", resp.StatusCode)
+	}
+
+	resp = doAuth(t, created.Key, "POST", srv.URL+"/endpoints", map[string]any{
+		"tenant_id": "t1",
+		"url":       "https://example.com/hook",
+	})
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("create without endpoints:admin: expected 403, got %d", resp.StatusCode)
+	}
+
+	// A key cannot mint keys beyond its own scopes.
+	resp = doAuth(t, created.Key, "POST", srv.URL+"/api-keys", map[string]any{
+		"scopes": []string{auth.ScopeAll},
+	})
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("create key without keys:admin: expected 403, got %d", resp.StatusCode)
+	}
+
+	// Deleting the key revokes it.
+	resp = doAuth(t, admin, "DELETE", srv.URL+"/api-keys/"+created.ID, nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("delete key: expected 204, got %d", resp.StatusCode)
+	}
+	resp = doAuth(t, created.Key, "GET", srv.URL+"/endpoints?tenant_id=t1", nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("revoked key: expected 401, got %d", resp.StatusCode)
+	}
+}
+
+func TestAuth_TenantBoundKey(t *testing.T) {
+	srv, r, admin := authServer(t)
+	defer srv.Close()
+	ctx := context.Background()
+
+	other, err := r.Endpoints().Create(ctx, endpoint.Input{
+		TenantID:   "tenant-2",
+		URL:        "https://example.com/other",
+		EventTypes: []string{"*"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, key, err := r.APIKeys().CreateKey(ctx, auth.KeyInput{
+		TenantID: "tenant-1",
+		Scopes:   []string{auth.ScopeEndpointsAdmin, auth.ScopeCatalogAdmin},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The bound tenant is applied when none is given.
+	var ep endpoint.Endpoint
+	resp := doAuth(t, key, "POST", srv.URL+"/endpoints", map[string]any{
+		"url":         "https://example.com/mine",
+		"event_types": []string{"*"},
+	})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("create endpoint: expected 201, got %d", resp.StatusCode)
+	}
+	decodeBody(t, resp, &ep)
+	if ep.TenantID != "tenant-1" {
+		t.Fatalf("expected tenant-1, got %q", ep.TenantID)
+	}
+
+	resp = doAuth(t, key, "GET", srv.URL+"/endpoints?tenant_id=tenant-2", nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("list another tenant: expected 403, got %d", resp.StatusCode)
+	}
+
+	var eps page.Page[*endpoint.Endpoint]
+	resp = doAuth(t, key, "GET", srv.URL+"/endpoints", nil)
+	decodeBody(t, resp, &eps)
+	if len(eps.Data) != 1 || eps.Data[0].TenantID != "tenant-1" {
+		t.Fatalf("expected only tenant-1's endpoint, got %d", len(eps.Data))
+	}
+
+	// Other tenants' endpoints look missing.
+	resp = doAuth(t, key, "GET", srv.URL+"/endpoints/"+other.ID.String(), nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("get another tenant's endpoint: expected 404, got %d", resp.StatusCode)
+	}
+	resp = doAuth(t, key, "DELETE", srv.URL+"/endpoints/"+other.ID.String(), nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("delete another tenant's endpoint: expected 404, got %d", resp.StatusCode)
+	}
+
+	// Operations spanning tenants are refused.
+	resp = doAuth(t, key, "POST", srv.URL+"/event-types", map[string]any{"name": "order.created"})
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("register event type: expected 403, got %d", resp.StatusCode)
+	}
+
+	// The admin key still sees everything.
+	resp = doAuth(t, admin, "GET", srv.URL+"/endpoints/"+other.ID.String(), nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("admin get: expected 200, got %d", resp.StatusCode)
+	}
+}
+
+func TestPortal(t *testing.T) {
+	s := memory.New()
+	r, err := relay.New(relay.WithStore(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := portal.NewSigner([]byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(api.NewPortalHandler(r, signer, nil))
+	defer srv.Close()
+	ctx := context.Background()
+
+	other, err := r.Endpoints().Create(ctx, endpoint.Input{
+		TenantID:   "tenant-2",
+		URL:        "https://example.com/other",
+		EventTypes: []string{"*"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := signer.Issue("tenant-1", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp := doAuth(t, "", "GET", srv.URL+"/endpoints", nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("without a token: expected 401, got %d", resp.StatusCode)
+	}
+
+	var ep endpoint.Endpoint
+	resp = doAuth(t, token, "POST", srv.URL+"/endpoints", map[string]any{
+		"url":         "https://example.com/mine",
+		"event_types": []string{"*"},
+	})
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("create endpoint: expected 201, got %d", resp.StatusCode)
+	}
+	decodeBody(t, resp, &ep)
+	if ep.TenantID != "tenant-1" {
+		t.Fatalf("expected tenant-1, got %q", ep.TenantID)
+	}
+
+	var eps page.Page[*endpoint.Endpoint]
+	resp = doAuth(t, token, "GET", srv.URL+"/endpoints", nil)
+	decodeBody(t, resp, &eps)
+	if len(eps.Data) != 1 || eps.Data[0].ID != ep.ID {
+		t.Fatalf("expected only tenant-1's endpoint, got %d", len(eps.Data))
+	}
+
+	resp = doAuth(t, token, "POST", srv.URL+"/endpoints/"+ep.ID.String()+"/rotate-secret", nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("rotate secret: expected 200, got %d", resp.StatusCode)
+	}
+
+	resp = doAuth(t, token, "GET", srv.URL+"/endpoints/"+other.ID.String(), nil)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("get another tenant's endpoint: expected 404, got %d", resp.StatusCode)
+	}
+
+	// Admin routes are not part of the portal.
+	for _, path := range []string{"/stats", "/api-keys"} {
+		resp = doAuth(t, token, "GET", srv.URL+path, nil)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Fatalf("GET %s: expected 404, got %d", path, resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	log "github.com/xraph/go-utils/log"
+
+	"github.com/xraph/relay"
+	"github.com/xraph/relay/auth"
+	"github.com/xraph/relay/portal"
+)
+
+// NewPortalHandler creates the tenant portal API: the routes a tenant needs
+// to manage its own webhooks, for embedding in your product. Every request
+// needs a portal token issued by signer and acts for the token's tenant
+// only; see package portal.
+//
+// The portal serves the catalog's event types, the tenant's endpoints with
+// their test sends, secret rotation, health, backfills and delivery logs,
+// its events, and its DLQ entries with single-entry and filtered replay.
+// It uses the same request and response formats as the admin API.
+func NewPortalHandler(r *relay.Relay, signer *portal.Signer, logger log.Logger, opts ...HandlerOption) *Handler {
+	opts = append([]HandlerOption{WithRelay(r)}, opts...)
+	h := newHandler(r.Store(), r.Catalog(), r.Endpoints(), r.DLQ(), logger, opts...)
+	h.authn = portal.Authenticator(signer)
+	h.registerPortalRoutes()
+	return h
+}
+
+func (h *Handler) registerPortalRoutes() {
+	// Event types the tenant can subscribe to
+	h.handle("GET /event-types", auth.ScopeCatalogRead, h.listEventTypes)
+	h.handle("GET /event-types/{name}", auth.ScopeCatalogRead, h.getEventType)
+
+	// Endpoints
+	h.handle("POST /endpoints", auth.ScopeEndpointsAdmin, h.createEndpoint)
+	h.handle("GET /endpoints", auth.ScopeEndpointsRead, h.listEndpoints)
+	h.handle("GET /endpoints/{id}", auth.ScopeEndpointsRead, h.getEndpoint)
+	h.handle("PUT /endpoints/{id}", auth.ScopeEndpointsAdmin, h.updateEndpoint)
+	h.handle("DELETE /endpoints/{id}", auth.ScopeEndpointsAdmin, h.deleteEndpoint)
+	h.handle("PATCH /endpoints/{id}/enable", auth.ScopeEndpointsAdmin, h.enableEndpoint)
+	h.handle("PATCH /endpoints/{id}/disable", auth.ScopeEndpointsAdmin, h.disableEndpoint)
+	h.handle("POST /endpoints/{id}/rotate-secret", auth.ScopeEndpointsAdmin, h.rotateSecret)
+	h.handle("POST /endpoints/{id}/verify", auth.ScopeEndpointsAdmin, h.verifyEndpoint)
+	h.handle("POST /endpoints/{id}/test", auth.ScopeEndpointsAdmin, h.testEndpoint)
+	h.handle("GET /endpoints/{id}/health", auth.ScopeEndpointsRead, h.getEndpointHealth)
+	h.handle("POST /endpoints/{id}/backfill", auth.ScopeEndpointsAdmin, h.backfillEndpoint)
+	h.handle("GET /backfills/{id}", auth.ScopeEndpointsRead, h.getBackfill)
+	h.handle("POST /backfills/{id}/cancel", auth.ScopeEndpointsAdmin, h.cancelBackfill)
+
+	// Events and delivery logs
+	h.handle("GET /events", auth.ScopeEventsRead, h.listEvents)
+	h.handle("GET /events/{id}", auth.ScopeEventsRead, h.getEvent)
+	h.handle("GET /endpoints/{id}/deliveries", auth.ScopeDeliveriesRead, h.listDeliveries)
+
+	// DLQ
+	h.handle("GET /dlq", auth.ScopeDLQRead, h.listDLQ)
+	h.handle("POST /dlq/{id}/replay", auth.ScopeDLQReplay, h.replayDLQ)
+	h.handle("POST /dlq/replay-jobs", auth.ScopeDLQReplay, h.startDLQReplay)
+	h.handle("GET /dlq/replay-jobs/{id}", auth.ScopeDLQRead, h.getDLQReplay)
+	h.handle("POST /dlq/replay-jobs/{id}/cancel", auth.ScopeDLQReplay, h.cancelDLQReplay)
+}
@@ -2,19 +2,33 @@ package api
 
 import (
 	"errors"
+	"io"
 	"net/http"
 	"time"
 
 	"github.com/xraph/relay"
+	"github.com/xraph/relay/auth"
 	"github.com/xraph/relay/dlq"
 	"github.com/xraph/relay/id"
+	"github.com/xraph/relay/job"
 )
 
 func (h *Handler) listDLQ(w http.ResponseWriter, r *http.Request) {
+	tenantID, ok := requestTenant(w, r, queryParam(r, "tenant_id"))
+	if !ok {
+		return
+	}
+
+	p, ok := readListParams(w, r, id.PrefixDLQ)
+	if !ok {
+		return
+	}
+
 	opts := dlq.ListOpts{
-		Offset:   queryInt(r, "offset", 0),
-		Limit:    queryInt(r, "limit", 50),
-		TenantID: queryParam(r, "tenant_id"),
+		Cursor:   p.cursor,
+		Offset:   p.offset,
+		Limit:    p.limit + 1,
+		TenantID: tenantID,
 	}
 
 	entries, err := h.dlqSvc.List(r.Context(), opts)
@@ -23,7 +37,9 @@ func (h *Handler) listDLQ(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	writeJSON(w, http.StatusOK, entries)
+	writePage(w, p, entries, func(e *dlq.Entry) id.ID { return e.ID }, func() (int64, error) {
+		return h.dlqSvc.Count(r.Context(), opts)
+	})
 }
 
 func (h *Handler) replayDLQ(w http.ResponseWriter, r *http.Request) {
@@ -33,12 +49,19 @@ func (h *Handler) replayDLQ(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if replayErr := h.dlqSvc.Replay(r.Context(), dlqID); replayErr != nil {
-		if errors.Is(replayErr, relay.ErrDLQNotFound) {
+	replayErr := h.ownsDLQEntry(r, dlqID)
+	if replayErr == nil {
+		replayErr = h.dlqSvc.Replay(r.Context(), dlqID)
+	}
+	if replayErr != nil {
+		switch {
+		case errors.Is(replayErr, relay.ErrDLQNotFound):
 			writeError(w, http.StatusNotFound, "DLQ entry not found")
-			return
+		case errors.Is(replayErr, dlq.ErrAlreadyReplayed), errors.Is(replayErr, dlq.ErrReplayLimit):
+			writeError(w, http.StatusConflict, replayErr.Error())
+		default:
+			writeError(w, http.StatusInternalServerError, replayErr.Error())
 		}
-		writeError(w, http.StatusInternalServerError, replayErr.Error())
 		return
 	}
 
@@ -51,6 +74,10 @@ type replayBulkRequest struct {
 }
 
 func (h *Handler) replayBulkDLQ(w http.ResponseWriter, r *http.Request) {
+	if !requireUnbound(w, r) {
+		return
+	}
+
 	var req replayBulkRequest
 	if err := decodeJSON(r, &req); err != nil {
 		writeError(w, http.StatusBadRequest, "invalid request body")
@@ -76,3 +103,127 @@ func (h *Handler) replayBulkDLQ(w http.ResponseWriter, r *http.Request) {
 
 	writeJSON(w, http.StatusOK, map[string]int64{"replayed": count})
 }
+
+func (h *Handler) purgeDLQ(w http.ResponseWriter, r *http.Request) {
+	if !requireUnbound(w, r) {
+		return
+	}
+
+	before, err := time.Parse(time.RFC3339, queryParam(r, "before"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid 'before' time format (use RFC3339)")
+		return
+	}
+
+	count, purgeErr := h.dlqSvc.Purge(r.Context(), before)
+	if purgeErr != nil {
+		writeError(w, http.StatusInternalServerError, purgeErr.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]int64{"purged": count})
+}
+
+// ownsDLQEntry returns relay.ErrDLQNotFound unless the principal may access
+// the entry.
+func (h *Handler) ownsDLQEntry(r *http.Request, dlqID id.ID) error {
+	if auth.Unbound(r.Context()) {
+		return nil
+	}
+	entry, err := h.store.GetDLQ(r.Context(), dlqID)
+	if err != nil {
+		return err
+	}
+	if !auth.CanAccess(r.Context(), entry.TenantID) {
+		return relay.ErrDLQNotFound
+	}
+	return nil
+}
+
+type dlqReplayRequest struct {
+	TenantID      string `json:"tenant_id,omitempty"`
+	EndpointID    string `json:"endpoint_id,omitempty"`
+	EventType     string `json:"event_type,omitempty"`
+	StatusCode    int    `json:"status_code,omitempty"`
+	ErrorContains string `json:"error_contains,omitempty"`
+	From          string `json:"from,omitempty"`
+	To            string `json:"to,omitempty"`
+	Rate          int    `json:"rate,omitempty"`
+	DryRun        bool   `json:"dry_run,omitempty"`
+}
+
+// options converts the request to DLQReplayOptions for tenantID. Its
+// errors are fit for a 400 response.
+func (req dlqReplayRequest) options(tenantID string) (relay.DLQReplayOptions, error) {
+	opts := relay.DLQReplayOptions{
+		TenantID:      tenantID,
+		EventType:     req.EventType,
+		StatusCode:    req.StatusCode,
+		ErrorContains: req.ErrorContains,
+		Rate:          req.Rate,
+	}
+	if req.EndpointID != "" {
+		epID, err := id.ParseEndpointID(req.EndpointID)
+		if err != nil {
+			return opts, errors.New("invalid endpoint ID")
+		}
+		opts.EndpointID = &epID
+	}
+	var err error
+	if opts.From, err = optionalTime(req.From); err == nil {
+		opts.To, err = optionalTime(req.To)
+	}
+	if err != nil {
+		return opts, errors.New("invalid time format (use RFC3339)")
+	}
+	return opts, nil
+}
+
+// startDLQReplay starts a job replaying the DLQ entries matching the body's
+// filters, or with dry_run only counts them.
+func (h *Handler) startDLQReplay(w http.ResponseWriter, r *http.Request) {
+	if h.relay == nil {
+		writeError(w, http.StatusNotImplemented, "replay jobs require a relay instance")
+		return
+	}
+
+	var req dlqReplayRequest
+	if err := decodeJSON(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	tenantID, ok := requestTenant(w, r, req.TenantID)
+	if !ok {
+		return
+	}
+	opts, err := req.options(tenantID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if req.DryRun {
+		count, countErr := h.relay.CountDLQReplay(r.Context(), opts)
+		if countErr != nil {
+			writeError(w, http.StatusInternalServerError, countErr.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]int64{"count": count})
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, h.relay.ReplayDLQ(r.Context(), opts))
+}
+
+func (h *Handler) getDLQReplay(w http.ResponseWriter, r *http.Request) {
+	j, ok := h.loadJob(w, r, job.KindDLQReplay)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, j)
+}
+
+func (h *Handler) cancelDLQReplay(w http.ResponseWriter, r *http.Request) {
+	h.cancelJob(w, r, job.KindDLQReplay)
+}
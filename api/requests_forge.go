@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/xraph/relay/id"
 )
@@ -20,14 +21,18 @@ type CreateEventTypeForgeRequest struct {
 	Version       string            `description:"Event type version"                   json:"version,omitempty"`
 	ScopeAppID    string            `description:"Scope to specific app"                json:"scope_app_id,omitempty"`
 	Metadata      map[string]string `description:"Arbitrary key-value metadata"         json:"metadata,omitempty"`
+	Force         string            `description:"Register breaking schema changes"     query:"force"`
 }
 
 // ListEventTypesForgeRequest binds query parameters for GET /event-types.
 type ListEventTypesForgeRequest struct {
 	Group             string `description:"Filter by group"             query:"group"`
 	IncludeDeprecated string `description:"Include deprecated types"    query:"include_deprecated"`
+	ScopeAppID        string `description:"Filter by app scope"        query:"scope_app_id"`
 	Offset            int    `description:"Pagination offset"           query:"offset"`
 	Limit             int    `description:"Page size (default 50)"      query:"limit"`
+	Cursor            string `description:"next_cursor of the previous page" query:"cursor"`
+	IncludeTotal      string `description:"Include the total count"  query:"include_total"`
 }
 
 // GetEventTypeForgeRequest binds the path for GET /event-types/:name.
@@ -40,6 +45,24 @@ type DeleteEventTypeForgeRequest struct {
 	Name string `description:"Event type name" path:"name"`
 }
 
+// DeprecateEventTypeForgeRequest binds path + body for POST /event-types/:name/deprecate.
+type DeprecateEventTypeForgeRequest struct {
+	Name     string `description:"Event type name"                           path:"name"`
+	SunsetAt string `description:"When new events are rejected (RFC3339)"    json:"sunset_at"`
+}
+
+// CheckCompatibilityForgeRequest binds path + body for POST /event-types/:name/compatibility.
+type CheckCompatibilityForgeRequest struct {
+	Name    string          `description:"Event type name"                          path:"name"`
+	Version string          `description:"Version to compare against (default latest)" json:"version,omitempty"`
+	Schema  json.RawMessage `description:"Proposed JSON Schema"                     json:"schema,omitempty"`
+}
+
+// EventTypeActionForgeRequest binds the path for undeprecate and subscribers.
+type EventTypeActionForgeRequest struct {
+	Name string `description:"Event type name" path:"name"`
+}
+
 // ---------------------------------------------------------------------------
 // Endpoint requests
 // ---------------------------------------------------------------------------
@@ -49,17 +72,23 @@ type CreateEndpointForgeRequest struct {
 	TenantID    string            `description:"Tenant identifier"          json:"tenant_id"`
 	URL         string            `description:"Webhook delivery URL"       json:"url"`
 	Description string            `description:"Endpoint description"       json:"description,omitempty"`
+	Secret      string            `description:"Signing secret (generated if empty)" json:"secret,omitempty"`
 	EventTypes  []string          `description:"Subscribed event patterns"  json:"event_types"`
 	Headers     map[string]string `description:"Custom HTTP headers"        json:"headers,omitempty"`
 	RateLimit   int               `description:"Requests per second limit"  json:"rate_limit,omitempty"`
+	Version     string            `description:"Pinned event type version"  json:"version,omitempty"`
 	Metadata    map[string]string `description:"Arbitrary key-value metadata" json:"metadata,omitempty"`
 }
 
 // ListEndpointsForgeRequest binds query parameters for GET /endpoints.
 type ListEndpointsForgeRequest struct {
-	TenantID string `description:"Filter by tenant"     query:"tenant_id"`
-	Offset   int    `description:"Pagination offset"    query:"offset"`
-	Limit    int    `description:"Page size (default 50)" query:"limit"`
+	TenantID     string `description:"Filter by tenant"     query:"tenant_id"`
+	ScopeAppID   string `description:"Filter by app scope"  query:"scope_app_id"`
+	ScopeOrgID   string `description:"Filter by org scope"  query:"scope_org_id"`
+	Offset       int    `description:"Pagination offset"    query:"offset"`
+	Limit        int    `description:"Page size (default 50)" query:"limit"`
+	Cursor       string `description:"next_cursor of the previous page" query:"cursor"`
+	IncludeTotal string `description:"Include the total count"  query:"include_total"`
 }
 
 // GetEndpointForgeRequest binds the path for GET /endpoints/:endpointId.
@@ -75,6 +104,7 @@ type UpdateEndpointForgeRequest struct {
 	EventTypes  []string          `description:"Subscribed event patterns"  json:"event_types,omitempty"`
 	Headers     map[string]string `description:"Custom HTTP headers"        json:"headers,omitempty"`
 	RateLimit   int               `description:"Requests per second limit"  json:"rate_limit,omitempty"`
+	Version     *string           `description:"Pinned event type version; empty clears it" json:"version,omitempty"`
 	Metadata    map[string]string `description:"Arbitrary key-value metadata" json:"metadata,omitempty"`
 }
 
@@ -83,11 +113,32 @@ type DeleteEndpointForgeRequest struct {
 	EndpointID string `description:"Endpoint identifier" path:"endpointId"`
 }
 
-// EndpointActionForgeRequest binds the path for enable/disable/rotate-secret.
+// EndpointActionForgeRequest binds the path for enable/disable/rotate-secret/verify/health.
 type EndpointActionForgeRequest struct {
 	EndpointID string `description:"Endpoint identifier" path:"endpointId"`
 }
 
+// TestEndpointForgeRequest binds POST /endpoints/:endpointId/test.
+type TestEndpointForgeRequest struct {
+	EndpointID string          `description:"Endpoint identifier"                  path:"endpointId"`
+	EventType  string          `description:"Event type to send"                   json:"event_type"`
+	Data       json.RawMessage `description:"Payload (default the type's example)" json:"data,omitempty"`
+}
+
+// BackfillEndpointForgeRequest binds path + body for POST /endpoints/:endpointId/backfill.
+type BackfillEndpointForgeRequest struct {
+	EndpointID string `description:"Endpoint identifier"                   path:"endpointId"`
+	From       string `description:"Created at or after (RFC3339)"         json:"from,omitempty"`
+	To         string `description:"Created at or before (RFC3339)"        json:"to,omitempty"`
+	Type       string `description:"Only backfill events of this type"     json:"type,omitempty"`
+	Rate       int    `description:"Events per second (default 50)"        json:"rate,omitempty"`
+}
+
+// BackfillJobForgeRequest binds the path for /backfills/:jobId routes.
+type BackfillJobForgeRequest struct {
+	JobID string `description:"Backfill job identifier" path:"jobId"`
+}
+
 // ---------------------------------------------------------------------------
 // Event requests
 // ---------------------------------------------------------------------------
@@ -97,14 +148,25 @@ type CreateEventForgeRequest struct {
 	Type           string          `description:"Event type name"       json:"type"`
 	TenantID       string          `description:"Tenant identifier"    json:"tenant_id"`
 	Data           json.RawMessage `description:"Event payload"        json:"data"`
+	Version        string          `description:"Event type version of the payload (default latest)" json:"version,omitempty"`
 	IdempotencyKey string          `description:"Idempotency key"      json:"idempotency_key,omitempty"`
+	DryRun         bool            `description:"Validate and resolve endpoints without persisting" optional:"true" query:"dry_run"`
 }
 
+// SendEventBatchForgeRequest is empty — POST /events/batch reads its body
+// directly so it can accept both a JSON array and NDJSON.
+type SendEventBatchForgeRequest struct{}
+
 // ListEventsForgeRequest binds query parameters for GET /events.
 type ListEventsForgeRequest struct {
-	Type   string `description:"Filter by event type"  query:"type"`
-	Offset int    `description:"Pagination offset"     query:"offset"`
-	Limit  int    `description:"Page size (default 50)" query:"limit"`
+	TenantID     string `description:"Filter by tenant"      query:"tenant_id"`
+	Type         string `description:"Filter by event type"  query:"type"`
+	ScopeAppID   string `description:"Filter by app scope"   query:"scope_app_id"`
+	ScopeOrgID   string `description:"Filter by org scope"   query:"scope_org_id"`
+	Offset       int    `description:"Pagination offset"     query:"offset"`
+	Limit        int    `description:"Page size (default 50)" query:"limit"`
+	Cursor       string `description:"next_cursor of the previous page" query:"cursor"`
+	IncludeTotal string `description:"Include the total count"  query:"include_total"`
 }
 
 // GetEventForgeRequest binds the path for GET /events/:eventId.
@@ -112,16 +174,62 @@ type GetEventForgeRequest struct {
 	EventID string `description:"Event identifier" path:"eventId"`
 }
 
+// ResendEventForgeRequest binds path + body for POST /events/:eventId/resend.
+type ResendEventForgeRequest struct {
+	EventID    string `description:"Event identifier"                               path:"eventId"`
+	EndpointID string `description:"Endpoint to resend to (default all subscribed)" json:"endpoint_id,omitempty"`
+}
+
 // ---------------------------------------------------------------------------
 // Delivery requests
 // ---------------------------------------------------------------------------
 
 // ListDeliveriesForgeRequest binds path + query for GET /endpoints/:endpointId/deliveries.
 type ListDeliveriesForgeRequest struct {
-	EndpointID string `description:"Endpoint identifier"  path:"endpointId"`
-	State      string `description:"Filter by state"      query:"state"`
-	Offset     int    `description:"Pagination offset"    query:"offset"`
-	Limit      int    `description:"Page size (default 50)" query:"limit"`
+	EndpointID   string `description:"Endpoint identifier"  path:"endpointId"`
+	State        string `description:"Filter by state"      query:"state"`
+	Offset       int    `description:"Pagination offset"    query:"offset"`
+	Limit        int    `description:"Page size (default 50)" query:"limit"`
+	Cursor       string `description:"next_cursor of the previous page" query:"cursor"`
+	IncludeTotal string `description:"Include the total count"  query:"include_total"`
+}
+
+// ListAllDeliveriesForgeRequest binds query parameters for GET /deliveries.
+type ListAllDeliveriesForgeRequest struct {
+	TenantID     string `description:"Filter by tenant"            query:"tenant_id"`
+	EndpointID   string `description:"Filter by endpoint"          query:"endpoint_id"`
+	EventID      string `description:"Filter by event"             query:"event_id"`
+	State        string `description:"Filter by state"             query:"state"`
+	From         string `description:"Created at or after (RFC3339)" query:"from"`
+	To           string `description:"Created at or before (RFC3339)" query:"to"`
+	Offset       int    `description:"Pagination offset"           query:"offset"`
+	Limit        int    `description:"Page size (default 50)"      query:"limit"`
+	Cursor       string `description:"next_cursor of the previous page" query:"cursor"`
+	IncludeTotal string `description:"Include the total count"     query:"include_total"`
+}
+
+// ListEventDeliveriesForgeRequest binds path + query for GET /events/:eventId/deliveries.
+type ListEventDeliveriesForgeRequest struct {
+	EventID      string `description:"Event identifier"       path:"eventId"`
+	Offset       int    `description:"Pagination offset"      query:"offset"`
+	Limit        int    `description:"Page size (default 50)" query:"limit"`
+	Cursor       string `description:"next_cursor of the previous page" query:"cursor"`
+	IncludeTotal string `description:"Include the total count" query:"include_total"`
+}
+
+// GetDeliveryForgeRequest binds the path for GET /deliveries/:deliveryId.
+type GetDeliveryForgeRequest struct {
+	DeliveryID string `description:"Delivery identifier" path:"deliveryId"`
+}
+
+// RetryDeliveryForgeRequest binds the path for POST /deliveries/:deliveryId/retry.
+type RetryDeliveryForgeRequest struct {
+	DeliveryID string `description:"Delivery identifier" path:"deliveryId"`
+}
+
+// CancelDeliveryForgeRequest binds the path for POST /deliveries/:deliveryId/cancel.
+type CancelDeliveryForgeRequest struct {
+	DeliveryID string `description:"Delivery identifier" path:"deliveryId"`
 }
 
 // ---------------------------------------------------------------------------
@@ -130,9 +238,11 @@ type ListDeliveriesForgeRequest struct {
 
 // ListDLQForgeRequest binds query parameters for GET /dlq.
 type ListDLQForgeRequest struct {
-	TenantID string `description:"Filter by tenant"     query:"tenant_id"`
-	Offset   int    `description:"Pagination offset"    query:"offset"`
-	Limit    int    `description:"Page size (default 50)" query:"limit"`
+	TenantID     string `description:"Filter by tenant"     query:"tenant_id"`
+	Offset       int    `description:"Pagination offset"    query:"offset"`
+	Limit        int    `description:"Page size (default 50)" query:"limit"`
+	Cursor       string `description:"next_cursor of the previous page" query:"cursor"`
+	IncludeTotal string `description:"Include the total count"  query:"include_total"`
 }
 
 // ReplayDLQForgeRequest binds the path for POST /dlq/:dlqId/replay.
@@ -146,6 +256,90 @@ type ReplayBulkDLQForgeRequest struct {
 	To   string `description:"End time (RFC3339)"   json:"to"`
 }
 
+// StartDLQReplayForgeRequest binds the body for POST /dlq/replay-jobs.
+type StartDLQReplayForgeRequest struct {
+	TenantID      string `description:"Only replay this tenant's entries"                           json:"tenant_id,omitempty"`
+	EndpointID    string `description:"Only replay this endpoint's entries"                         json:"endpoint_id,omitempty"`
+	EventType     string `description:"Only replay entries of this event type"                      json:"event_type,omitempty"`
+	StatusCode    int    `description:"Only replay entries whose last attempt returned this status" json:"status_code,omitempty"`
+	ErrorContains string `description:"Only replay entries whose error contains this"               json:"error_contains,omitempty"`
+	From          string `description:"Failed at or after (RFC3339)"                                json:"from,omitempty"`
+	To            string `description:"Failed at or before (RFC3339)"                               json:"to,omitempty"`
+	Rate          int    `description:"Entries per second (default 10)"                             json:"rate,omitempty"`
+	DryRun        bool   `description:"Only count the matching entries"                             json:"dry_run,omitempty"`
+}
+
+// DLQReplayJobForgeRequest binds the path for /dlq/replay-jobs/:jobId routes.
+type DLQReplayJobForgeRequest struct {
+	JobID string `description:"Replay job identifier" path:"jobId"`
+}
+
+// PurgeDLQForgeRequest binds query parameters for DELETE /dlq.
+type PurgeDLQForgeRequest struct {
+	Before string `description:"Delete entries that failed before this time (RFC3339)" query:"before"`
+}
+
+// ---------------------------------------------------------------------------
+// Catalog requests
+// ---------------------------------------------------------------------------
+
+// CatalogExportForgeRequest binds query parameters for the catalog export routes.
+type CatalogExportForgeRequest struct {
+	Title   string `description:"Document title (default Webhooks)"  query:"title"`
+	Version string `description:"Document version (default 1.0.0)"  query:"version"`
+}
+
+// ---------------------------------------------------------------------------
+// Tunnel requests
+// ---------------------------------------------------------------------------
+
+// OpenTunnelForgeRequest binds the body for POST /tunnel/sessions.
+type OpenTunnelForgeRequest struct {
+	TenantID   string   `description:"Tenant whose deliveries are tunneled"       json:"tenant_id"`
+	EventTypes []string `description:"Event type patterns (default all)"          json:"event_types,omitempty"`
+}
+
+// TunnelSessionForgeRequest binds the path for tunnel session routes.
+type TunnelSessionForgeRequest struct {
+	SessionID string `description:"Tunnel session identifier" path:"sessionId"`
+}
+
+// TunnelResponseForgeRequest binds POST /tunnel/sessions/:sessionId/responses.
+type TunnelResponseForgeRequest struct {
+	SessionID  string              `description:"Tunnel session identifier"                path:"sessionId"`
+	ID         string              `description:"Forwarded request identifier"             json:"id"`
+	StatusCode int                 `description:"Status code of the local server"          json:"status_code,omitempty"`
+	Header     map[string][]string `description:"Response headers of the local server"     json:"header,omitempty"`
+	Body       string              `description:"Response body of the local server"        json:"body,omitempty"`
+	Error      string              `description:"Why the local server could not be reached" json:"error,omitempty"`
+}
+
+// ---------------------------------------------------------------------------
+// API key requests
+// ---------------------------------------------------------------------------
+
+// CreateAPIKeyForgeRequest binds the body for POST /api-keys.
+type CreateAPIKeyForgeRequest struct {
+	Name      string     `description:"Human-readable key name"              json:"name"`
+	TenantID  string     `description:"Tenant the key is bound to (optional)" json:"tenant_id,omitempty"`
+	Scopes    []string   `description:"Granted scopes"                       json:"scopes"`
+	ExpiresAt *time.Time `description:"Expiry time (optional)"               json:"expires_at,omitempty"`
+}
+
+// ListAPIKeysForgeRequest binds query parameters for GET /api-keys.
+type ListAPIKeysForgeRequest struct {
+	TenantID     string `description:"Filter by tenant"       query:"tenant_id"`
+	Offset       int    `description:"Pagination offset"      query:"offset"`
+	Limit        int    `description:"Page size (default 50)" query:"limit"`
+	Cursor       string `description:"next_cursor of the previous page" query:"cursor"`
+	IncludeTotal string `description:"Include the total count"  query:"include_total"`
+}
+
+// DeleteAPIKeyForgeRequest binds the path for DELETE /api-keys/:keyId.
+type DeleteAPIKeyForgeRequest struct {
+	KeyID string `description:"API key identifier" path:"keyId"`
+}
+
 // ---------------------------------------------------------------------------
 // Stats requests
 // ---------------------------------------------------------------------------
@@ -164,11 +358,39 @@ type SecretForgeResponse struct {
 	Secret string `json:"secret"`
 }
 
+// EventBatchItemForgeResponse is the outcome of one event in POST /events/batch.
+type EventBatchItemForgeResponse struct {
+	Index     int      `description:"Position in the request"           json:"index"`
+	EventID   string   `description:"Assigned event ID when accepted"   json:"event_id,omitempty"`
+	Duplicate bool     `description:"Idempotency key was already used"  json:"duplicate,omitempty"`
+	Error     string   `description:"Rejection reason"                  json:"error,omitempty"`
+	Warnings  []string `description:"Non-fatal problems such as a deprecated event type" json:"warnings,omitempty"`
+}
+
+// EventBatchForgeResponse is the response for POST /events/batch.
+type EventBatchForgeResponse struct {
+	Accepted   int                           `json:"accepted"`
+	Duplicates int                           `json:"duplicates"`
+	Rejected   int                           `json:"rejected"`
+	Results    []EventBatchItemForgeResponse `json:"results"`
+	Error      string                        `json:"error,omitempty"`
+}
+
 // ReplayBulkForgeResponse is the response for POST /dlq/replay.
 type ReplayBulkForgeResponse struct {
 	Replayed int64 `json:"replayed"`
 }
 
+// DLQReplayCountForgeResponse is the dry-run response for POST /dlq/replay-jobs.
+type DLQReplayCountForgeResponse struct {
+	Count int64 `json:"count"`
+}
+
+// PurgeDLQForgeResponse is the response for DELETE /dlq.
+type PurgeDLQForgeResponse struct {
+	Purged int64 `json:"purged"`
+}
+
 // ---------------------------------------------------------------------------
 // Helper -- compile-time check that id.ID is used (keep import alive).
 // ---------------------------------------------------------------------------
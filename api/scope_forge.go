@@ -0,0 +1,22 @@
+package api
+
+import (
+	"github.com/xraph/forge"
+
+	"github.com/xraph/relay/scope"
+)
+
+// ForgeScopeMiddleware copies the request's forge.Scope, set by the
+// application's auth middleware, into the context for package scope. Relay
+// routes use it; install it on application routes that call Relay directly
+// so what they send and create is scoped too.
+func ForgeScopeMiddleware() forge.Middleware {
+	return func(next forge.Handler) forge.Handler {
+		return func(ctx forge.Context) error {
+			if s, ok := forge.GetScope(ctx); ok {
+				ctx.WithContext(scope.Restore(ctx.Context(), s.AppID(), s.OrgID()))
+			}
+			return next(ctx)
+		}
+	}
+}
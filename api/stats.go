@@ -2,6 +2,8 @@ package api
 
 import (
 	"net/http"
+
+	"github.com/xraph/relay/dlq"
 )
 
 type statsResponse struct {
@@ -10,6 +12,10 @@ type statsResponse struct {
 }
 
 func (h *Handler) getStats(w http.ResponseWriter, r *http.Request) {
+	if !requireUnbound(w, r) {
+		return
+	}
+
 	ctx := r.Context()
 
 	pending, err := h.store.CountPending(ctx)
@@ -18,7 +24,7 @@ func (h *Handler) getStats(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	dlqCount, err := h.store.CountDLQ(ctx)
+	dlqCount, err := h.store.CountDLQ(ctx, dlq.ListOpts{Unreplayed: true})
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, err.Error())
 		return
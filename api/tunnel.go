@@ -0,0 +1,111 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	log "github.com/xraph/go-utils/log"
+
+	"github.com/xraph/relay/auth"
+	"github.com/xraph/relay/tunnel"
+)
+
+type openTunnelRequest struct {
+	TenantID   string   `json:"tenant_id"`
+	EventTypes []string `json:"event_types"`
+}
+
+func (h *Handler) openTunnel(w http.ResponseWriter, r *http.Request) {
+	var req openTunnelRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if len(req.EventTypes) == 0 {
+		req.EventTypes = []string{"*"}
+	}
+	tenantID, ok := requestTenant(w, r, req.TenantID)
+	if !ok {
+		return
+	}
+
+	s, err := h.relay.Tunnel().Open(r.Context(), tenantID, req.EventTypes)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, s)
+}
+
+func (h *Handler) streamTunnel(w http.ResponseWriter, r *http.Request) {
+	if !h.ownsTunnel(w, r) {
+		return
+	}
+
+	err := h.relay.Tunnel().Stream(r.Context(), r.PathValue("id"), w)
+	if errors.Is(err, tunnel.ErrSessionNotFound) {
+		writeError(w, http.StatusNotFound, "tunnel session not found")
+		return
+	}
+	if err != nil {
+		h.logger.Warn("tunnel stream ended",
+			log.String("session_id", r.PathValue("id")),
+			log.Any("error", err),
+		)
+	}
+}
+
+func (h *Handler) respondTunnel(w http.ResponseWriter, r *http.Request) {
+	if !h.ownsTunnel(w, r) {
+		return
+	}
+
+	var resp tunnel.Response
+	if err := decodeJSON(r, &resp); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := h.relay.Tunnel().Respond(r.PathValue("id"), resp); err != nil {
+		status := http.StatusConflict
+		if errors.Is(err, tunnel.ErrSessionNotFound) {
+			status = http.StatusNotFound
+		}
+		writeError(w, status, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) closeTunnel(w http.ResponseWriter, r *http.Request) {
+	if !h.ownsTunnel(w, r) {
+		return
+	}
+
+	if err := h.relay.Tunnel().Close(r.Context(), r.PathValue("id")); err != nil {
+		if errors.Is(err, tunnel.ErrSessionNotFound) {
+			writeError(w, http.StatusNotFound, "tunnel session not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ownsTunnel answers 404 unless the principal may access the request's
+// tunnel session.
+func (h *Handler) ownsTunnel(w http.ResponseWriter, r *http.Request) bool {
+	if auth.Unbound(r.Context()) {
+		return true
+	}
+	tenantID, err := h.relay.Tunnel().Tenant(r.PathValue("id"))
+	if err != nil || !auth.CanAccess(r.Context(), tenantID) {
+		writeError(w, http.StatusNotFound, "tunnel session not found")
+		return false
+	}
+	return true
+}
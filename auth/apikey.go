@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"time"
+
+	"github.com/xraph/relay/id"
+	"github.com/xraph/relay/internal/entity"
+)
+
+// APIKey is a stored admin API key. Only a hash of the key is kept; the
+// key itself is returned once, when it is created.
+type APIKey struct {
+	entity.Entity
+
+	// ID is the unique TypeID for this key.
+	ID id.ID `json:"id"`
+
+	// Name describes what the key is for.
+	Name string `json:"name"`
+
+	// Prefix is the start of the key, enough to recognize it in a list.
+	Prefix string `json:"prefix"`
+
+	// Hash is the hex-encoded SHA-256 of the key.
+	Hash string `json:"-"`
+
+	// TenantID binds the key to one tenant. Empty keys act for all tenants.
+	TenantID string `json:"tenant_id,omitempty"`
+
+	// Scopes are the operations the key grants.
+	Scopes []string `json:"scopes"`
+
+	// ExpiresAt is when the key stops working. Nil keys never expire.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// Principal returns the principal the key authenticates as.
+func (k *APIKey) Principal() *Principal {
+	return &Principal{Subject: k.ID.String(), TenantID: k.TenantID, Scopes: k.Scopes}
+}
+
+// KeyInput is the input for creating an API key.
+type KeyInput struct {
+	Name      string     `json:"name"`
+	TenantID  string     `json:"tenant_id,omitempty"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// ListOpts configures filtering and pagination for API key listing. Lists are
+// ordered by ID, oldest first.
+type ListOpts struct {
+	// Cursor, when set, starts the list after the item with this ID; see
+	// package page.
+	Cursor   id.ID
+	Offset   int
+	Limit    int
+	TenantID string
+}
@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Authenticator authenticates an admin API request. It returns
+// ErrNoCredentials when the request carries nothing it understands, and
+// another error, usually ErrUnauthenticated, when its credentials are bad.
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+// AuthenticatorFunc adapts a function to Authenticator.
+type AuthenticatorFunc func(r *http.Request) (*Principal, error)
+
+// Authenticate implements Authenticator.
+func (f AuthenticatorFunc) Authenticate(r *http.Request) (*Principal, error) { return f(r) }
+
+// APIKeys authenticates requests whose bearer token is an API key created
+// by svc.
+func APIKeys(svc *Service) Authenticator {
+	return AuthenticatorFunc(func(r *http.Request) (*Principal, error) {
+		token := BearerToken(r)
+		if !strings.HasPrefix(token, KeyPrefix) {
+			return nil, ErrNoCredentials
+		}
+		return svc.Authenticate(r.Context(), token)
+	})
+}
+
+// Bearer authenticates requests by passing their bearer token to verify,
+// typically to validate a JWT and map its claims to a Principal. Put it
+// after APIKeys in a Chain to accept both.
+func Bearer(verify func(ctx context.Context, token string) (*Principal, error)) Authenticator {
+	return AuthenticatorFunc(func(r *http.Request) (*Principal, error) {
+		token := BearerToken(r)
+		if token == "" {
+			return nil, ErrNoCredentials
+		}
+		p, err := verify(r.Context(), token)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrUnauthenticated, err.Error())
+		}
+		return p, nil
+	})
+}
+
+// Chain tries each authenticator in turn until one finds credentials it
+// understands, and returns its result.
+func Chain(authenticators ...Authenticator) Authenticator {
+	return AuthenticatorFunc(func(r *http.Request) (*Principal, error) {
+		for _, a := range authenticators {
+			p, err := a.Authenticate(r)
+			if errors.Is(err, ErrNoCredentials) {
+				continue
+			}
+			return p, err
+		}
+		return nil, ErrNoCredentials
+	})
+}
+
+// BearerToken returns the token of an "Authorization: Bearer" header, or
+// "" if there is none.
+func BearerToken(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
@@ -0,0 +1,8 @@
+// Package auth authenticates and authorizes callers of the admin API.
+//
+// A request is authenticated by an Authenticator, which turns its
+// credentials into a Principal: who is calling, the scopes they were
+// granted, and optionally the one tenant they are bound to. Relay stores
+// API keys hashed (see Service and APIKeys); Bearer plugs in any other
+// token validation, such as an identity provider's JWTs.
+package auth
@@ -0,0 +1,20 @@
+package auth
+
+import "errors"
+
+var (
+	// ErrNoCredentials is returned by an Authenticator when the request
+	// carries no credentials it understands, so Chain tries the next one.
+	ErrNoCredentials = errors.New("auth: no credentials")
+
+	// ErrUnauthenticated is returned for credentials that are invalid,
+	// unknown or expired.
+	ErrUnauthenticated = errors.New("auth: invalid credentials")
+
+	// ErrForbidden is returned when a principal lacks a scope, or acts
+	// outside the tenant it is bound to.
+	ErrForbidden = errors.New("auth: forbidden")
+
+	// ErrInvalidScope is returned when creating a key with an unknown scope.
+	ErrInvalidScope = errors.New("auth: invalid scope")
+)
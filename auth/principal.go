@@ -0,0 +1,133 @@
+package auth
+
+import (
+	"context"
+	"slices"
+	"strings"
+)
+
+// Scopes granted to principals. A "<resource>:admin" scope grants every
+// other scope of its resource, and ScopeAll grants everything.
+const (
+	ScopeAll = "*"
+
+	ScopeCatalogRead  = "catalog:read"
+	ScopeCatalogAdmin = "catalog:admin"
+
+	ScopeEndpointsRead  = "endpoints:read"
+	ScopeEndpointsAdmin = "endpoints:admin"
+
+	ScopeEventsRead  = "events:read"
+	ScopeEventsWrite = "events:write"
+
+	ScopeDeliveriesRead  = "deliveries:read"
+	ScopeDeliveriesWrite = "deliveries:write"
+
+	ScopeDLQRead   = "dlq:read"
+	ScopeDLQReplay = "dlq:replay"
+	ScopeDLQAdmin  = "dlq:admin"
+
+	ScopeStatsRead = "stats:read"
+
+	ScopeKeysAdmin = "keys:admin"
+)
+
+// Scopes lists every scope, ScopeAll included.
+var Scopes = []string{
+	ScopeAll,
+	ScopeCatalogRead, ScopeCatalogAdmin,
+	ScopeEndpointsRead, ScopeEndpointsAdmin,
+	ScopeEventsRead, ScopeEventsWrite,
+	ScopeDeliveriesRead, ScopeDeliveriesWrite,
+	ScopeDLQRead, ScopeDLQReplay, ScopeDLQAdmin,
+	ScopeStatsRead,
+	ScopeKeysAdmin,
+}
+
+// Principal is an authenticated caller.
+type Principal struct {
+	// Subject identifies the caller: the API key ID, or the subject of an
+	// external token.
+	Subject string
+
+	// TenantID binds the principal to one tenant. Every query and mutation
+	// it makes is confined to that tenant, and tenant-wide operations such
+	// as changing the shared catalog are refused. Empty means all tenants.
+	TenantID string
+
+	// Scopes are the operations the principal may perform.
+	Scopes []string
+}
+
+// HasScope reports whether p was granted scope, directly or through
+// ScopeAll or the "<resource>:admin" scope of its resource.
+func (p *Principal) HasScope(scope string) bool {
+	resource, _, _ := strings.Cut(scope, ":")
+	for _, s := range p.Scopes {
+		if s == scope || s == ScopeAll || s == resource+":admin" {
+			return true
+		}
+	}
+	return false
+}
+
+// Tenant returns the tenant a request naming requested acts on. For a
+// bound principal it is its own tenant, and naming another is
+// ErrForbidden.
+func (p *Principal) Tenant(requested string) (string, error) {
+	if p.TenantID == "" {
+		return requested, nil
+	}
+	if requested != "" && requested != p.TenantID {
+		return "", ErrForbidden
+	}
+	return p.TenantID, nil
+}
+
+// Owns reports whether p may access a resource of tenantID.
+func (p *Principal) Owns(tenantID string) bool {
+	return p.TenantID == "" || p.TenantID == tenantID
+}
+
+// grants reports whether p holds every one of scopes, so it can hand them
+// to a key it creates.
+func (p *Principal) grants(scopes []string) bool {
+	return !slices.ContainsFunc(scopes, func(s string) bool { return !p.HasScope(s) })
+}
+
+type ctxKey struct{}
+
+// NewContext returns a context carrying p.
+func NewContext(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, ctxKey{}, p)
+}
+
+// FromContext returns the principal ctx carries, if any.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(ctxKey{}).(*Principal)
+	return p, ok && p != nil
+}
+
+// TenantFor is Principal.Tenant for the principal ctx carries. Without
+// one, as when the API runs without authentication, requested is returned
+// as is.
+func TenantFor(ctx context.Context, requested string) (string, error) {
+	if p, ok := FromContext(ctx); ok {
+		return p.Tenant(requested)
+	}
+	return requested, nil
+}
+
+// CanAccess is Principal.Owns for the principal ctx carries; it is true
+// without one.
+func CanAccess(ctx context.Context, tenantID string) bool {
+	p, ok := FromContext(ctx)
+	return !ok || p.Owns(tenantID)
+}
+
+// Unbound reports whether ctx may make tenant-wide changes: it carries no
+// principal, or one that is not bound to a tenant.
+func Unbound(ctx context.Context) bool {
+	p, ok := FromContext(ctx)
+	return !ok || p.TenantID == ""
+}
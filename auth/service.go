@@ -0,0 +1,134 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	log "github.com/xraph/go-utils/log"
+
+	"github.com/xraph/relay/id"
+	"github.com/xraph/relay/internal/entity"
+)
+
+// KeyPrefix starts every API key, so they are recognizable in a config
+// file or a secret scanner, and told apart from other bearer tokens.
+const KeyPrefix = "rk_"
+
+// Service manages API keys.
+type Service struct {
+	store  Store
+	logger log.Logger
+}
+
+// NewService creates a new API key service.
+func NewService(store Store, logger log.Logger) *Service {
+	if logger == nil {
+		logger = log.NewNoopLogger()
+	}
+	return &Service{store: store, logger: logger}
+}
+
+// CreateKey creates an API key and returns it with the key itself, which is
+// not stored and cannot be read again. When ctx carries a principal, the new
+// key cannot reach beyond it: it is bound to the principal's tenant, and may
+// only hold scopes the principal has.
+func (svc *Service) CreateKey(ctx context.Context, in KeyInput) (*APIKey, string, error) {
+	if len(in.Scopes) == 0 {
+		return nil, "", fmt.Errorf("%w: at least one scope is required", ErrInvalidScope)
+	}
+	for _, s := range in.Scopes {
+		if !slices.Contains(Scopes, s) {
+			return nil, "", fmt.Errorf("%w: %q", ErrInvalidScope, s)
+		}
+	}
+
+	tenantID, err := TenantFor(ctx, in.TenantID)
+	if err != nil {
+		return nil, "", err
+	}
+	if p, ok := FromContext(ctx); ok && !p.grants(in.Scopes) {
+		return nil, "", fmt.Errorf("%w: a key cannot be granted scopes its creator lacks", ErrForbidden)
+	}
+
+	secret, err := generateKey()
+	if err != nil {
+		return nil, "", err
+	}
+
+	key := &APIKey{
+		Entity:    entity.New(),
+		ID:        id.NewAPIKeyID(),
+		Name:      in.Name,
+		Prefix:    secret[:len(KeyPrefix)+8],
+		Hash:      hashKey(secret),
+		TenantID:  tenantID,
+		Scopes:    in.Scopes,
+		ExpiresAt: in.ExpiresAt,
+	}
+	if err := svc.store.CreateAPIKey(ctx, key); err != nil {
+		return nil, "", err
+	}
+	return key, secret, nil
+}
+
+// GetKey returns an API key by ID.
+func (svc *Service) GetKey(ctx context.Context, keyID id.ID) (*APIKey, error) {
+	return svc.store.GetAPIKey(ctx, keyID)
+}
+
+// ListKeys returns API keys.
+func (svc *Service) ListKeys(ctx context.Context, opts ListOpts) ([]*APIKey, error) {
+	return svc.store.ListAPIKeys(ctx, opts)
+}
+
+// CountKeys returns the number of API keys ListKeys lists for opts,
+// ignoring its pagination.
+func (svc *Service) CountKeys(ctx context.Context, opts ListOpts) (int64, error) {
+	return svc.store.CountAPIKeys(ctx, opts)
+}
+
+// DeleteKey revokes an API key.
+func (svc *Service) DeleteKey(ctx context.Context, keyID id.ID) error {
+	return svc.store.DeleteAPIKey(ctx, keyID)
+}
+
+// Authenticate returns the principal of an API key. Unknown and expired
+// keys are ErrUnauthenticated.
+func (svc *Service) Authenticate(ctx context.Context, secret string) (*Principal, error) {
+	if !strings.HasPrefix(secret, KeyPrefix) {
+		return nil, ErrUnauthenticated
+	}
+	key, err := svc.store.GetAPIKeyByHash(ctx, hashKey(secret))
+	if err != nil {
+		svc.logger.Debug("api key lookup failed", log.Any("error", err))
+		return nil, ErrUnauthenticated
+	}
+	if key.ExpiresAt != nil && !time.Now().Before(*key.ExpiresAt) {
+		return nil, ErrUnauthenticated
+	}
+	return key.Principal(), nil
+}
+
+// generateKey returns a new random API key.
+func generateKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("auth: generate key: %w", err)
+	}
+	return KeyPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashKey returns the hash a key is stored and looked up by. Keys are
+// random and long, so a plain SHA-256 is as strong as a password hash and
+// can be looked up directly.
+func hashKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
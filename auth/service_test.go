@@ -0,0 +1,176 @@
+package auth_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xraph/relay/auth"
+	"github.com/xraph/relay/store/memory"
+)
+
+func TestCreateAndAuthenticate(t *testing.T) {
+	ctx := context.Background()
+	svc := auth.NewService(memory.New(), nil)
+
+	key, secret, err := svc.CreateKey(ctx, auth.KeyInput{
+		Name:     "ci",
+		TenantID: "tenant-1",
+		Scopes:   []string{auth.ScopeEventsWrite},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(secret, auth.KeyPrefix) || !strings.HasPrefix(secret, key.Prefix) {
+		t.Fatalf("unexpected key %q with prefix %q", secret, key.Prefix)
+	}
+	if key.Hash == "" || strings.Contains(key.Hash, secret) {
+		t.Fatal("expected the key to be stored hashed")
+	}
+
+	p, err := svc.Authenticate(ctx, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Subject != key.ID.String() || p.TenantID != "tenant-1" || !p.HasScope(auth.ScopeEventsWrite) {
+		t.Fatalf("unexpected principal %+v", p)
+	}
+
+	if _, err := svc.Authenticate(ctx, secret+"x"); !errors.Is(err, auth.ErrUnauthenticated) {
+		t.Fatalf("expected ErrUnauthenticated for an unknown key, got %v", err)
+	}
+
+	if err := svc.DeleteKey(ctx, key.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Authenticate(ctx, secret); !errors.Is(err, auth.ErrUnauthenticated) {
+		t.Fatalf("expected ErrUnauthenticated for a deleted key, got %v", err)
+	}
+}
+
+func TestExpiredKey(t *testing.T) {
+	ctx := context.Background()
+	svc := auth.NewService(memory.New(), nil)
+
+	past := time.Now().Add(-time.Minute)
+	_, secret, err := svc.CreateKey(ctx, auth.KeyInput{Scopes: []string{auth.ScopeAll}, ExpiresAt: &past})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Authenticate(ctx, secret); !errors.Is(err, auth.ErrUnauthenticated) {
+		t.Fatalf("expected ErrUnauthenticated, got %v", err)
+	}
+}
+
+func TestCreateKeyLimits(t *testing.T) {
+	svc := auth.NewService(memory.New(), nil)
+
+	if _, _, err := svc.CreateKey(context.Background(), auth.KeyInput{}); !errors.Is(err, auth.ErrInvalidScope) {
+		t.Fatalf("expected ErrInvalidScope without scopes, got %v", err)
+	}
+	if _, _, err := svc.CreateKey(context.Background(), auth.KeyInput{Scopes: []string{"events:delete"}}); !errors.Is(err, auth.ErrInvalidScope) {
+		t.Fatalf("expected ErrInvalidScope for an unknown scope, got %v", err)
+	}
+
+	ctx := auth.NewContext(context.Background(), &auth.Principal{
+		TenantID: "tenant-1",
+		Scopes:   []string{auth.ScopeKeysAdmin, auth.ScopeEventsWrite},
+	})
+
+	// Keys created by a bound principal inherit its tenant.
+	key, _, err := svc.CreateKey(ctx, auth.KeyInput{Scopes: []string{auth.ScopeEventsWrite}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key.TenantID != "tenant-1" {
+		t.Fatalf("expected tenant-1, got %q", key.TenantID)
+	}
+
+	if _, _, err := svc.CreateKey(ctx, auth.KeyInput{TenantID: "tenant-2", Scopes: []string{auth.ScopeEventsWrite}}); !errors.Is(err, auth.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden for another tenant, got %v", err)
+	}
+	if _, _, err := svc.CreateKey(ctx, auth.KeyInput{Scopes: []string{auth.ScopeDLQReplay}}); !errors.Is(err, auth.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden for a scope the creator lacks, got %v", err)
+	}
+}
+
+func TestHasScope(t *testing.T) {
+	tests := []struct {
+		scopes []string
+		scope  string
+		want   bool
+	}{
+		{[]string{auth.ScopeEventsWrite}, auth.ScopeEventsWrite, true},
+		{[]string{auth.ScopeEventsWrite}, auth.ScopeEventsRead, false},
+		{[]string{auth.ScopeEndpointsAdmin}, auth.ScopeEndpointsRead, true},
+		{[]string{auth.ScopeDLQAdmin}, auth.ScopeDLQReplay, true},
+		{[]string{auth.ScopeDLQReplay}, auth.ScopeDLQAdmin, false},
+		{[]string{auth.ScopeAll}, auth.ScopeKeysAdmin, true},
+		{nil, auth.ScopeStatsRead, false},
+	}
+	for _, tt := range tests {
+		p := &auth.Principal{Scopes: tt.scopes}
+		if got := p.HasScope(tt.scope); got != tt.want {
+			t.Errorf("%v.HasScope(%q) = %v, want %v", tt.scopes, tt.scope, got, tt.want)
+		}
+	}
+}
+
+func TestTenantFor(t *testing.T) {
+	ctx := context.Background()
+	if got, err := auth.TenantFor(ctx, "tenant-2"); err != nil || got != "tenant-2" {
+		t.Fatalf("without a principal the requested tenant applies, got %q, %v", got, err)
+	}
+
+	ctx = auth.NewContext(ctx, &auth.Principal{TenantID: "tenant-1"})
+	if got, err := auth.TenantFor(ctx, ""); err != nil || got != "tenant-1" {
+		t.Fatalf("expected the bound tenant, got %q, %v", got, err)
+	}
+	if _, err := auth.TenantFor(ctx, "tenant-2"); !errors.Is(err, auth.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden, got %v", err)
+	}
+	if auth.CanAccess(ctx, "tenant-2") || !auth.CanAccess(ctx, "tenant-1") || auth.Unbound(ctx) {
+		t.Fatal("bound principal should only access its own tenant")
+	}
+}
+
+func TestChain(t *testing.T) {
+	svc := auth.NewService(memory.New(), nil)
+	_, secret, err := svc.CreateKey(context.Background(), auth.KeyInput{Scopes: []string{auth.ScopeStatsRead}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jwt := auth.Bearer(func(_ context.Context, token string) (*auth.Principal, error) {
+		if token != "valid-jwt" {
+			return nil, errors.New("bad signature")
+		}
+		return &auth.Principal{Subject: "user-1", Scopes: []string{auth.ScopeAll}}, nil
+	})
+	a := auth.Chain(auth.APIKeys(svc), jwt)
+
+	request := func(header string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		if header != "" {
+			r.Header.Set("Authorization", header)
+		}
+		return r
+	}
+
+	if p, err := a.Authenticate(request("Bearer " + secret)); err != nil || !p.HasScope(auth.ScopeStatsRead) {
+		t.Fatalf("expected the API key to authenticate, got %+v, %v", p, err)
+	}
+	if p, err := a.Authenticate(request("Bearer valid-jwt")); err != nil || p.Subject != "user-1" {
+		t.Fatalf("expected the JWT to authenticate, got %+v, %v", p, err)
+	}
+	if _, err := a.Authenticate(request("Bearer forged-jwt")); !errors.Is(err, auth.ErrUnauthenticated) {
+		t.Fatalf("expected ErrUnauthenticated, got %v", err)
+	}
+	if _, err := a.Authenticate(request("")); !errors.Is(err, auth.ErrNoCredentials) {
+		t.Fatalf("expected ErrNoCredentials, got %v", err)
+	}
+}
@@ -0,0 +1,29 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/xraph/relay/id"
+)
+
+// Store defines the persistence contract for API keys.
+type Store interface {
+	// CreateAPIKey persists a new API key.
+	CreateAPIKey(ctx context.Context, key *APIKey) error
+
+	// GetAPIKey returns an API key by ID.
+	GetAPIKey(ctx context.Context, keyID id.ID) (*APIKey, error)
+
+	// GetAPIKeyByHash returns the API key with the given hash.
+	GetAPIKeyByHash(ctx context.Context, hash string) (*APIKey, error)
+
+	// ListAPIKeys returns API keys, optionally filtered by tenant.
+	ListAPIKeys(ctx context.Context, opts ListOpts) ([]*APIKey, error)
+
+	// CountAPIKeys returns the number of API keys ListAPIKeys lists for
+	// opts, ignoring its pagination.
+	CountAPIKeys(ctx context.Context, opts ListOpts) (int64, error)
+
+	// DeleteAPIKey removes an API key; it stops working immediately.
+	DeleteAPIKey(ctx context.Context, keyID id.ID) error
+}
@@ -0,0 +1,243 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	log "github.com/xraph/go-utils/log"
+
+	"github.com/xraph/relay/delivery"
+	"github.com/xraph/relay/endpoint"
+	"github.com/xraph/relay/event"
+	"github.com/xraph/relay/id"
+	"github.com/xraph/relay/store"
+)
+
+// sendBatchChunk bounds how many events SendBatch hands to the store in one
+// write, keeping transactions and multi-row inserts a reasonable size.
+const sendBatchChunk = 500
+
+// BatchResult is the outcome of one event passed to SendBatch.
+type BatchResult struct {
+	// EventID is the ID assigned to the event. It is id.Nil when the event
+	// was rejected or was a duplicate.
+	EventID id.ID
+
+	// Duplicate reports that the event's idempotency key had already been
+	// used, so nothing was persisted for it.
+	Duplicate bool
+
+	// Err is the reason the event was rejected, nil if it was accepted.
+	Err error
+
+	// Warnings are non-fatal problems with an accepted event, such as its
+	// type being deprecated.
+	Warnings []string
+}
+
+// SendBatch is the bulk form of Send for high-volume producers such as
+// backfills and imports. Every event is validated before anything is
+// written; invalid events are reported in their BatchResult and the rest
+// are still sent. Endpoints are resolved once per (tenant, type) pair, and
+// stores implementing store.BatchOutbox persist events and deliveries with
+// multi-row inserts.
+//
+// Results are returned in input order. The returned error is only non-nil
+// for store failures. Accepted events left unwritten by the failure get it
+// as their Err and no EventID; the results of the others remain valid.
+//
+// Send hooks are called per event: BeforeSend before it is validated and
+// AfterSend once the whole batch is done.
+func (r *Relay) SendBatch(ctx context.Context, evts []*event.Event) (_ []BatchResult, err error) {
+	results := make([]BatchResult, len(evts))
+
+	// hooked marks the events that passed BeforeSend; sent holds their
+	// results once persisted.
+	var hooked []bool
+	var sent []*SendResult
+	if !r.hooks.empty() {
+		hooked = make([]bool, len(evts))
+		sent = make([]*SendResult, len(evts))
+		defer func() { r.afterSendBatch(ctx, evts, results, hooked, sent, err) }()
+	}
+
+	// 1. Validate everything first.
+	accepted := make([]int, 0, len(evts))
+	for i, evt := range evts {
+		if evt == nil {
+			results[i].Err = errors.New("relay: nil event")
+			continue
+		}
+		if hooked != nil {
+			if hookErr := r.hooks.beforeSend(ctx, evt); hookErr != nil {
+				results[i].Err = hookErr
+				continue
+			}
+			hooked[i] = true
+		}
+		warnings, err := r.prepare(ctx, evt)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		results[i].Warnings = warnings
+		results[i].EventID = evt.ID
+		accepted = append(accepted, i)
+	}
+
+	// 2. Resolve once per (tenant, type) and fan out.
+	type route struct{ tenantID, eventType string }
+	resolved := make(map[route][]*endpoint.Endpoint)
+	fanned := make([][]*delivery.Delivery, len(evts))
+	now := time.Now().UTC()
+	for _, i := range accepted {
+		evt := evts[i]
+		key := route{evt.TenantID, evt.Type}
+		endpoints, ok := resolved[key]
+		if !ok {
+			var err error
+			endpoints, err = r.store.Resolve(ctx, evt.TenantID, evt.Type)
+			if err != nil {
+				err = fmt.Errorf("relay: resolve endpoints: %w", err)
+				failUnwritten(results, accepted, err)
+				return results, err
+			}
+			resolved[key] = endpoints
+		}
+		fanned[i] = r.fanOut(evt, endpoints, now)
+	}
+
+	// 3. Persist chunk by chunk. Chunks written before a failure are still
+	// woken and counted.
+	var sentEvents, pending int
+	var persistErr error
+	for start := 0; start < len(accepted); start += sendBatchChunk {
+		chunk := accepted[start:min(start+sendBatchChunk, len(accepted))]
+
+		duplicates, written, err := r.persistBatch(ctx, evts, fanned, chunk)
+		for _, i := range chunk[:written] {
+			if sent != nil {
+				sent[i] = batchSendResult(evts[i], fanned[i], &results[i], duplicates[evts[i].ID])
+			}
+			if duplicates[evts[i].ID] {
+				results[i].EventID = id.Nil
+				results[i].Duplicate = true
+				continue
+			}
+			if n := len(fanned[i]); n > 0 {
+				sentEvents++
+				pending += n
+			}
+		}
+		if err != nil {
+			failUnwritten(results, accepted[start+written:], err)
+			persistErr = err
+			break
+		}
+	}
+
+	if pending > 0 {
+		r.engine.Wake()
+	}
+
+	if r.metrics != nil {
+		r.metrics.EventsSentTotal.Add(float64(sentEvents))
+		r.metrics.PendingDeliveries.Add(float64(pending))
+	}
+
+	if persistErr != nil {
+		return results, persistErr
+	}
+
+	r.logger.Debug("event batch sent",
+		log.Int("events", len(evts)),
+		log.Int("accepted", len(accepted)),
+		log.Int("deliveries", pending),
+	)
+
+	return results, nil
+}
+
+// batchSendResult builds the SendResult AfterSend gets for a batch event.
+func batchSendResult(evt *event.Event, deliveries []*delivery.Delivery, br *BatchResult, duplicate bool) *SendResult {
+	res := &SendResult{
+		EndpointIDs: make([]id.ID, len(deliveries)),
+		DeliveryIDs: []id.ID{},
+		Duplicate:   duplicate,
+		Warnings:    br.Warnings,
+	}
+	for j, d := range deliveries {
+		res.EndpointIDs[j] = d.EndpointID
+	}
+	if !duplicate {
+		res.EventID = evt.ID
+		for _, d := range deliveries {
+			res.DeliveryIDs = append(res.DeliveryIDs, d.ID)
+		}
+	}
+	return res
+}
+
+// afterSendBatch calls AfterSend for every event of a batch that passed
+// BeforeSend. Events left unwritten by a store failure get batchErr.
+func (r *Relay) afterSendBatch(ctx context.Context, evts []*event.Event, results []BatchResult, hooked []bool, sent []*SendResult, batchErr error) {
+	for i, evt := range evts {
+		switch {
+		case !hooked[i]:
+		case results[i].Err != nil:
+			r.hooks.afterSend(ctx, evt, nil, results[i].Err)
+		case sent[i] != nil:
+			r.hooks.afterSend(ctx, evt, sent[i], nil)
+		default:
+			r.hooks.afterSend(ctx, evt, nil, batchErr)
+		}
+	}
+}
+
+// failUnwritten marks the accepted events at the given indexes as not
+// persisted because of err.
+func failUnwritten(results []BatchResult, indexes []int, err error) {
+	for _, i := range indexes {
+		results[i].EventID = id.Nil
+		results[i].Err = err
+	}
+}
+
+// persistBatch writes the events at the given indexes and their deliveries,
+// returning the IDs of events skipped as idempotency duplicates and how many
+// of the events, from the start of chunk, were handled before an error.
+func (r *Relay) persistBatch(ctx context.Context, evts []*event.Event, fanned [][]*delivery.Delivery, chunk []int) (map[id.ID]bool, int, error) {
+	duplicates := make(map[id.ID]bool)
+
+	bo, ok := r.store.(store.BatchOutbox)
+	if !ok {
+		for j, i := range chunk {
+			if err := r.persist(ctx, nil, evts[i], fanned[i]); err != nil {
+				if errors.Is(err, ErrDuplicateIdempotencyKey) {
+					duplicates[evts[i].ID] = true
+					continue
+				}
+				return duplicates, j, err
+			}
+		}
+		return duplicates, len(chunk), nil
+	}
+
+	batch := make([]*event.Event, len(chunk))
+	var deliveries []*delivery.Delivery
+	for j, i := range chunk {
+		batch[j] = evts[i]
+		deliveries = append(deliveries, fanned[i]...)
+	}
+
+	dupIDs, err := bo.CreateEventBatch(ctx, batch, deliveries)
+	if err != nil {
+		return nil, 0, fmt.Errorf("relay: persist event batch: %w", err)
+	}
+	for _, dupID := range dupIDs {
+		duplicates[dupID] = true
+	}
+	return duplicates, len(chunk), nil
+}
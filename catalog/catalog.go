@@ -2,6 +2,7 @@ package catalog
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 
 	"github.com/xraph/relay/id"
 	"github.com/xraph/relay/internal/entity"
+	"github.com/xraph/relay/scope"
 )
 
 // Catalog is the in-memory cached service for managing webhook event types.
@@ -19,11 +21,18 @@ type Catalog struct {
 	lastLoad time.Time
 	mu       sync.RWMutex
 	logger   log.Logger
+	policy   CompatPolicy
+
+	converters map[converterKey]ConverterFunc
 }
 
 // Config configures the catalog service.
 type Config struct {
 	CacheTTL time.Duration
+
+	// CompatPolicy decides what RegisterType does with breaking schema
+	// changes. Defaults to CompatPolicyWarn.
+	CompatPolicy CompatPolicy
 }
 
 // NewCatalog creates a new Catalog backed by the given store.
@@ -31,20 +40,45 @@ func NewCatalog(store Store, cfg Config, logger log.Logger) *Catalog {
 	if logger == nil {
 		logger = log.NewNoopLogger()
 	}
+	if cfg.CompatPolicy == "" {
+		cfg.CompatPolicy = CompatPolicyWarn
+	}
 	return &Catalog{
 		store:    store,
 		cache:    make(map[string]*EventType),
 		cacheTTL: cfg.CacheTTL,
 		logger:   logger,
+		policy:   cfg.CompatPolicy,
 	}
 }
 
-// RegisterType registers or updates an event type definition.
+// RegisterType registers or updates an event type definition. Registering
+// a definition whose Version differs from the ones already stored adds a new
+// live version; the previous versions stay available to pinned endpoints.
+// The Definition always reflects the latest version.
+//
+// Replacing the schema of an existing version is checked with
+// CompareSchemas and a breaking change is handled according to the compat
+// policy. Breaking changes belong in a new version.
 func (c *Catalog) RegisterType(ctx context.Context, def WebhookDefinition, opts ...RegisterOption) (*EventType, error) {
-	ro := registerOptions{}
+	ro := registerOptions{policy: c.policy}
 	for _, o := range opts {
 		o(&ro)
 	}
+	if ro.scopeAppID == "" {
+		ro.scopeAppID, _ = scope.Capture(ctx)
+	}
+
+	existing, err := c.findType(ctx, def.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing != nil && ro.policy != CompatPolicyAllow {
+		if err := c.enforceCompat(existing, def, ro.policy); err != nil {
+			return nil, err
+		}
+	}
 
 	et := &EventType{
 		Entity:     entity.New(),
@@ -54,6 +88,25 @@ func (c *Catalog) RegisterType(ctx context.Context, def WebhookDefinition, opts
 		Metadata:   ro.metadata,
 	}
 
+	var prior []Version
+	if existing != nil {
+		et.ID = existing.ID
+		et.CreatedAt = existing.CreatedAt
+		et.IsDeprecated = existing.IsDeprecated
+		et.DeprecatedAt = existing.DeprecatedAt
+		et.SunsetAt = existing.SunsetAt
+		prior = existing.allVersions()
+	}
+	et.Versions = mergeVersion(prior, def, et.UpdatedAt)
+
+	latest := et.Versions[len(et.Versions)-1]
+	et.Definition.Version = latest.Version
+	et.Definition.Description = latest.Description
+	et.Definition.Group = latest.Group
+	et.Definition.Schema = latest.Schema
+	et.Definition.SchemaVersion = latest.SchemaVersion
+	et.Definition.Example = latest.Example
+
 	if err := c.store.RegisterType(ctx, et); err != nil {
 		return nil, err
 	}
@@ -65,15 +118,31 @@ func (c *Catalog) RegisterType(ctx context.Context, def WebhookDefinition, opts
 	return et, nil
 }
 
+// findType returns the stored event type with the given name, or nil when
+// it is not registered. Other store errors are returned so that a failed
+// lookup is not mistaken for a new type, which would drop stored versions.
+func (c *Catalog) findType(ctx context.Context, name string) (*EventType, error) {
+	et, err := c.store.GetType(ctx, name)
+	if errors.Is(err, ErrEventTypeNotFound) {
+		return nil, nil //nolint:nilnil // not registered yet.
+	}
+	if err != nil {
+		return nil, err
+	}
+	return et, nil
+}
+
 // RegisterOption configures RegisterType behavior.
 type RegisterOption func(*registerOptions)
 
 type registerOptions struct {
 	scopeAppID string
 	metadata   map[string]string
+	policy     CompatPolicy
 }
 
-// WithScopeAppID sets the app scope on a registered event type.
+// WithScopeAppID sets the app scope on a registered event type. It
+// defaults to the app scope carried by the context (see package scope).
 func WithScopeAppID(appID string) RegisterOption {
 	return func(o *registerOptions) { o.scopeAppID = appID }
 }
@@ -83,6 +152,12 @@ func WithMetadata(m map[string]string) RegisterOption {
 	return func(o *registerOptions) { o.metadata = m }
 }
 
+// WithCompatPolicy overrides the catalog's compat policy for one
+// registration, e.g. CompatPolicyAllow to force a breaking change through.
+func WithCompatPolicy(p CompatPolicy) RegisterOption {
+	return func(o *registerOptions) { o.policy = p }
+}
+
 // GetType returns an event type by name, using the cache when available.
 func (c *Catalog) GetType(ctx context.Context, name string) (*EventType, error) {
 	c.mu.RLock()
@@ -109,12 +184,20 @@ func (c *Catalog) ListTypes(ctx context.Context, opts ListOpts) ([]*EventType, e
 	return c.store.ListTypes(ctx, opts)
 }
 
+// CountTypes returns the number of event types ListTypes lists for opts,
+// ignoring its pagination.
+func (c *Catalog) CountTypes(ctx context.Context, opts ListOpts) (int64, error) {
+	return c.store.CountTypes(ctx, opts)
+}
+
 // MatchTypesForEvent returns all non-deprecated event types matching a given event type name.
 func (c *Catalog) MatchTypesForEvent(ctx context.Context, eventType string) ([]*EventType, error) {
 	return c.store.MatchTypes(ctx, eventType)
 }
 
-// DeleteType soft-deletes (deprecates) an event type and removes it from cache.
+// DeleteType soft-deletes an event type and removes it from cache. The type
+// is deprecated with an immediate sunset, so Send rejects it straight away;
+// use Deprecate for a warning period.
 func (c *Catalog) DeleteType(ctx context.Context, name string) error {
 	if err := c.store.DeleteType(ctx, name); err != nil {
 		return err
@@ -0,0 +1,459 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	log "github.com/xraph/go-utils/log"
+)
+
+// Compatibility classifies a schema change by which payloads keep
+// validating.
+type Compatibility string
+
+const (
+	// CompatFull means nothing that affects payloads changed.
+	CompatFull Compatibility = "full"
+
+	// CompatBackward means payloads valid under the old schema are still
+	// valid under the new one: the schema only widened.
+	CompatBackward Compatibility = "backward"
+
+	// CompatForward means payloads valid under the new schema were already
+	// valid under the old one: the schema only tightened in ways consumers
+	// written against the old schema still accept.
+	CompatForward Compatibility = "forward"
+
+	// CompatBreaking means consumers written against the old schema may
+	// fail to parse new payloads.
+	CompatBreaking Compatibility = "breaking"
+)
+
+// ChangeKind identifies a single difference between two schemas.
+type ChangeKind string
+
+// Schema change kinds reported by CompareSchemas.
+const (
+	ChangeSchemaAdded     ChangeKind = "schema_added"
+	ChangeSchemaRemoved   ChangeKind = "schema_removed"
+	ChangePropertyAdded   ChangeKind = "property_added"
+	ChangePropertyRemoved ChangeKind = "property_removed"
+	ChangeRequiredAdded   ChangeKind = "required_added"
+	ChangeRequiredRemoved ChangeKind = "required_removed"
+	ChangeTypeNarrowed    ChangeKind = "type_narrowed"
+	ChangeTypeWidened     ChangeKind = "type_widened"
+	ChangeTypeChanged     ChangeKind = "type_changed"
+	ChangeEnumAdded       ChangeKind = "enum_added"
+	ChangeEnumRemoved     ChangeKind = "enum_removed"
+)
+
+// SchemaChange is one difference found by CompareSchemas.
+type SchemaChange struct {
+	// Path locates the change in the payload, e.g. "customer.email" or
+	// "items[]". It is empty for the payload root.
+	Path string `json:"path"`
+
+	Kind          ChangeKind    `json:"kind"`
+	Compatibility Compatibility `json:"compatibility"`
+	Detail        string        `json:"detail"`
+}
+
+// CompatReport is the result of comparing two schemas.
+type CompatReport struct {
+	EventType   string `json:"event_type,omitempty"`
+	FromVersion string `json:"from_version,omitempty"`
+	ToVersion   string `json:"to_version,omitempty"`
+
+	// Compatibility is the overall classification: the weakest of the
+	// changes, and breaking when both backward-only and forward-only
+	// changes are present.
+	Compatibility Compatibility  `json:"compatibility"`
+	Changes       []SchemaChange `json:"changes"`
+}
+
+// Breaking returns the changes classified as breaking.
+func (r *CompatReport) Breaking() []SchemaChange {
+	var out []SchemaChange
+	for _, c := range r.Changes {
+		if c.Compatibility == CompatBreaking {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// CompatPolicy controls what RegisterType does when a definition replaces a
+// schema with a breaking one.
+type CompatPolicy string
+
+const (
+	// CompatPolicyWarn registers the schema and logs the breaking changes.
+	// It is the default.
+	CompatPolicyWarn CompatPolicy = "warn"
+
+	// CompatPolicyReject refuses breaking schema changes with an
+	// *IncompatibleSchemaError.
+	CompatPolicyReject CompatPolicy = "reject"
+
+	// CompatPolicyAllow registers any schema without checking it.
+	CompatPolicyAllow CompatPolicy = "allow"
+)
+
+// IncompatibleSchemaError is returned by RegisterType when the reject
+// policy refuses a breaking schema change.
+type IncompatibleSchemaError struct {
+	Report *CompatReport
+}
+
+func (e *IncompatibleSchemaError) Error() string {
+	breaking := e.Report.Breaking()
+	details := make([]string, len(breaking))
+	for i, c := range breaking {
+		details[i] = c.Detail
+	}
+	return fmt.Sprintf("catalog: breaking schema change for %s@%s: %s",
+		e.Report.EventType, e.Report.ToVersion, strings.Join(details, "; "))
+}
+
+// CompareSchemas classifies the change from schema old to schema new. It
+// understands the JSON Schema keywords that shape payloads — type,
+// properties, required, enum and items — and ignores annotations such as
+// description. An empty schema accepts any payload.
+func CompareSchemas(old, new json.RawMessage) (*CompatReport, error) {
+	report := &CompatReport{Changes: []SchemaChange{}}
+
+	switch {
+	case len(old) == 0 && len(new) == 0:
+	case len(old) == 0:
+		report.add("", ChangeSchemaAdded, CompatForward, "schema added")
+	case len(new) == 0:
+		report.add("", ChangeSchemaRemoved, CompatBackward, "schema removed")
+	default:
+		var o, n map[string]any
+		if err := json.Unmarshal(old, &o); err != nil {
+			return nil, fmt.Errorf("catalog: parse old schema: %w", err)
+		}
+		if err := json.Unmarshal(new, &n); err != nil {
+			return nil, fmt.Errorf("catalog: parse new schema: %w", err)
+		}
+		report.compare("", o, n)
+	}
+
+	report.Compatibility = overall(report.Changes)
+	return report, nil
+}
+
+func (r *CompatReport) add(path string, kind ChangeKind, c Compatibility, detail string) {
+	r.Changes = append(r.Changes, SchemaChange{Path: path, Kind: kind, Compatibility: c, Detail: detail})
+}
+
+// compare walks two schema nodes in parallel.
+func (r *CompatReport) compare(path string, o, n map[string]any) {
+	r.compareType(path, o, n)
+	r.compareEnum(path, o, n)
+
+	oProps, _ := o["properties"].(map[string]any)
+	nProps, _ := n["properties"].(map[string]any)
+	oReq := stringSet(o["required"])
+	nReq := stringSet(n["required"])
+
+	for _, name := range sortedKeys(oProps) {
+		if _, ok := nProps[name]; !ok {
+			r.add(join(path, name), ChangePropertyRemoved, CompatBreaking,
+				fmt.Sprintf("property %s removed", join(path, name)))
+		}
+	}
+	for _, name := range sortedKeys(nProps) {
+		p := join(path, name)
+		oSub, existed := oProps[name]
+		if !existed {
+			if nReq[name] {
+				r.add(p, ChangeRequiredAdded, CompatBreaking, fmt.Sprintf("new required property %s", p))
+			} else {
+				r.add(p, ChangePropertyAdded, CompatFull, fmt.Sprintf("optional property %s added", p))
+			}
+			continue
+		}
+		if oMap, ok := oSub.(map[string]any); ok {
+			if nMap, ok := nProps[name].(map[string]any); ok {
+				r.compare(p, oMap, nMap)
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(nReq) {
+		if !oReq[name] {
+			if _, existed := oProps[name]; existed || nProps[name] == nil {
+				r.add(join(path, name), ChangeRequiredAdded, CompatBreaking,
+					fmt.Sprintf("property %s made required", join(path, name)))
+			}
+		}
+	}
+	for _, name := range sortedKeys(oReq) {
+		if !nReq[name] {
+			if _, kept := nProps[name]; kept || nProps == nil {
+				r.add(join(path, name), ChangeRequiredRemoved, CompatBackward,
+					fmt.Sprintf("property %s made optional", join(path, name)))
+			}
+		}
+	}
+
+	oItems, _ := o["items"].(map[string]any)
+	nItems, _ := n["items"].(map[string]any)
+	if oItems != nil && nItems != nil {
+		r.compare(path+"[]", oItems, nItems)
+	}
+}
+
+func (r *CompatReport) compareType(path string, o, n map[string]any) {
+	oTypes := typeSet(o["type"])
+	nTypes := typeSet(n["type"])
+	if sameSet(oTypes, nTypes) {
+		return
+	}
+
+	at := path
+	if at == "" {
+		at = "payload"
+	}
+	detail := fmt.Sprintf("type of %s changed from %s to %s", at, describeTypes(oTypes), describeTypes(nTypes))
+
+	switch {
+	case accepts(oTypes, nTypes):
+		r.add(path, ChangeTypeNarrowed, CompatBreaking, detail)
+	case accepts(nTypes, oTypes):
+		r.add(path, ChangeTypeWidened, CompatBackward, detail)
+	default:
+		r.add(path, ChangeTypeChanged, CompatBreaking, detail)
+	}
+}
+
+func (r *CompatReport) compareEnum(path string, o, n map[string]any) {
+	oEnum, oOK := o["enum"].([]any)
+	nEnum, nOK := n["enum"].([]any)
+	if !oOK && !nOK {
+		return
+	}
+
+	at := path
+	if at == "" {
+		at = "payload"
+	}
+	if !nOK {
+		r.add(path, ChangeEnumRemoved, CompatBackward, fmt.Sprintf("enum constraint on %s removed", at))
+		return
+	}
+	if !oOK {
+		r.add(path, ChangeEnumAdded, CompatBreaking, fmt.Sprintf("enum constraint added to %s", at))
+		return
+	}
+
+	oVals := enumSet(oEnum)
+	nVals := enumSet(nEnum)
+	var removed, added []string
+	for v := range oVals {
+		if !nVals[v] {
+			removed = append(removed, v)
+		}
+	}
+	for v := range nVals {
+		if !oVals[v] {
+			added = append(added, v)
+		}
+	}
+	sort.Strings(removed)
+	sort.Strings(added)
+	if len(removed) > 0 {
+		r.add(path, ChangeEnumRemoved, CompatBreaking,
+			fmt.Sprintf("enum values %s removed from %s", strings.Join(removed, ", "), at))
+	}
+	if len(added) > 0 {
+		r.add(path, ChangeEnumAdded, CompatBackward,
+			fmt.Sprintf("enum values %s added to %s", strings.Join(added, ", "), at))
+	}
+}
+
+// overall folds change classifications into one.
+func overall(changes []SchemaChange) Compatibility {
+	backward, forward := false, false
+	for _, c := range changes {
+		switch c.Compatibility {
+		case CompatBreaking:
+			return CompatBreaking
+		case CompatBackward:
+			backward = true
+		case CompatForward:
+			forward = true
+		}
+	}
+	switch {
+	case backward && forward:
+		return CompatBreaking
+	case backward:
+		return CompatBackward
+	case forward:
+		return CompatForward
+	default:
+		return CompatFull
+	}
+}
+
+// typeSet returns the JSON types a "type" keyword allows, or nil for any.
+func typeSet(v any) map[string]bool {
+	switch t := v.(type) {
+	case string:
+		return map[string]bool{t: true}
+	case []any:
+		set := make(map[string]bool, len(t))
+		for _, s := range t {
+			if str, ok := s.(string); ok {
+				set[str] = true
+			}
+		}
+		return set
+	default:
+		return nil
+	}
+}
+
+// accepts reports whether every type in inner is allowed by outer. A nil
+// set allows any type, and "number" includes "integer".
+func accepts(outer, inner map[string]bool) bool {
+	if outer == nil {
+		return true
+	}
+	if inner == nil {
+		return false
+	}
+	for t := range inner {
+		if !outer[t] && (t != "integer" || !outer["number"]) {
+			return false
+		}
+	}
+	return true
+}
+
+func sameSet(a, b map[string]bool) bool {
+	if (a == nil) != (b == nil) || len(a) != len(b) {
+		return false
+	}
+	for k := range a {
+		if !b[k] {
+			return false
+		}
+	}
+	return true
+}
+
+func describeTypes(set map[string]bool) string {
+	if set == nil {
+		return "any"
+	}
+	types := sortedKeys(set)
+	return strings.Join(types, "|")
+}
+
+func stringSet(v any) map[string]bool {
+	list, _ := v.([]any)
+	set := make(map[string]bool, len(list))
+	for _, s := range list {
+		if str, ok := s.(string); ok {
+			set[str] = true
+		}
+	}
+	return set
+}
+
+// enumSet keys enum values by their JSON encoding so that values of any
+// type compare correctly.
+func enumSet(values []any) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		b, _ := json.Marshal(v)
+		set[string(b)] = true
+	}
+	return set
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// CheckCompatibility compares def against the registered event type without
+// changing anything. The baseline is the stored schema of def's version, or
+// the latest version when def introduces a new one. An unregistered type
+// yields a report with no changes.
+func (c *Catalog) CheckCompatibility(ctx context.Context, def WebhookDefinition) (*CompatReport, error) {
+	existing, err := c.findType(ctx, def.Name)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return &CompatReport{
+			EventType:     def.Name,
+			ToVersion:     def.Version,
+			Compatibility: CompatFull,
+			Changes:       []SchemaChange{},
+		}, nil
+	}
+
+	base, ok := existing.LookupVersion(def.Version)
+	if !ok {
+		versions := existing.allVersions()
+		base = versions[len(versions)-1]
+	}
+	return compareVersion(def, base)
+}
+
+// enforceCompat applies policy to a registration that replaces the schema
+// of an existing version. New versions are not checked.
+func (c *Catalog) enforceCompat(existing *EventType, def WebhookDefinition, policy CompatPolicy) error {
+	base, ok := existing.LookupVersion(def.Version)
+	if !ok {
+		return nil
+	}
+	report, err := compareVersion(def, base)
+	if err != nil {
+		return err
+	}
+	if report.Compatibility != CompatBreaking {
+		return nil
+	}
+
+	if policy == CompatPolicyReject {
+		return &IncompatibleSchemaError{Report: report}
+	}
+	for _, change := range report.Breaking() {
+		c.logger.Warn("breaking schema change registered",
+			log.String("event_type", def.Name), log.String("version", def.Version),
+			log.String("path", change.Path), log.String("change", change.Detail))
+	}
+	return nil
+}
+
+func compareVersion(def WebhookDefinition, base Version) (*CompatReport, error) {
+	report, err := CompareSchemas(base.Schema, def.Schema)
+	if err != nil {
+		return nil, err
+	}
+	report.EventType = def.Name
+	report.FromVersion = base.Version
+	report.ToVersion = def.Version
+	return report, nil
+}
@@ -0,0 +1,226 @@
+package catalog_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/xraph/relay/catalog"
+	"github.com/xraph/relay/store/memory"
+)
+
+func TestCompareSchemas(t *testing.T) {
+	base := `{"type":"object","required":["id"],"properties":{
+		"id":{"type":"string"},
+		"amount":{"type":"number"},
+		"status":{"type":"string","enum":["open","paid"]},
+		"customer":{"type":"object","properties":{"email":{"type":"string"}}}
+	}}`
+
+	tests := []struct {
+		name   string
+		schema string
+		want   catalog.Compatibility
+		kind   catalog.ChangeKind
+		path   string
+	}{
+		{
+			name:   "unchanged",
+			schema: base,
+			want:   catalog.CompatFull,
+		},
+		{
+			name: "optional property added",
+			schema: `{"type":"object","required":["id"],"properties":{
+				"id":{"type":"string"},"amount":{"type":"number"},
+				"status":{"type":"string","enum":["open","paid"]},
+				"customer":{"type":"object","properties":{"email":{"type":"string"},"name":{"type":"string"}}}
+			}}`,
+			want: catalog.CompatFull,
+			kind: catalog.ChangePropertyAdded,
+			path: "customer.name",
+		},
+		{
+			name: "property removed",
+			schema: `{"type":"object","required":["id"],"properties":{
+				"id":{"type":"string"},"amount":{"type":"number"},
+				"status":{"type":"string","enum":["open","paid"]}
+			}}`,
+			want: catalog.CompatBreaking,
+			kind: catalog.ChangePropertyRemoved,
+			path: "customer",
+		},
+		{
+			name: "new required property",
+			schema: `{"type":"object","required":["id","currency"],"properties":{
+				"id":{"type":"string"},"amount":{"type":"number"},"currency":{"type":"string"},
+				"status":{"type":"string","enum":["open","paid"]},
+				"customer":{"type":"object","properties":{"email":{"type":"string"}}}
+			}}`,
+			want: catalog.CompatBreaking,
+			kind: catalog.ChangeRequiredAdded,
+			path: "currency",
+		},
+		{
+			name: "type narrowed",
+			schema: `{"type":"object","required":["id"],"properties":{
+				"id":{"type":"string"},"amount":{"type":"integer"},
+				"status":{"type":"string","enum":["open","paid"]},
+				"customer":{"type":"object","properties":{"email":{"type":"string"}}}
+			}}`,
+			want: catalog.CompatBreaking,
+			kind: catalog.ChangeTypeNarrowed,
+			path: "amount",
+		},
+		{
+			name: "type widened",
+			schema: `{"type":"object","required":["id"],"properties":{
+				"id":{"type":["string","integer"]},"amount":{"type":"number"},
+				"status":{"type":"string","enum":["open","paid"]},
+				"customer":{"type":"object","properties":{"email":{"type":"string"}}}
+			}}`,
+			want: catalog.CompatBackward,
+			kind: catalog.ChangeTypeWidened,
+			path: "id",
+		},
+		{
+			name: "enum shrunk",
+			schema: `{"type":"object","required":["id"],"properties":{
+				"id":{"type":"string"},"amount":{"type":"number"},
+				"status":{"type":"string","enum":["open"]},
+				"customer":{"type":"object","properties":{"email":{"type":"string"}}}
+			}}`,
+			want: catalog.CompatBreaking,
+			kind: catalog.ChangeEnumRemoved,
+			path: "status",
+		},
+		{
+			name: "enum grown",
+			schema: `{"type":"object","required":["id"],"properties":{
+				"id":{"type":"string"},"amount":{"type":"number"},
+				"status":{"type":"string","enum":["open","paid","void"]},
+				"customer":{"type":"object","properties":{"email":{"type":"string"}}}
+			}}`,
+			want: catalog.CompatBackward,
+			kind: catalog.ChangeEnumAdded,
+			path: "status",
+		},
+		{
+			name: "existing property made required",
+			schema: `{"type":"object","required":["id","amount"],"properties":{
+				"id":{"type":"string"},"amount":{"type":"number"},
+				"status":{"type":"string","enum":["open","paid"]},
+				"customer":{"type":"object","properties":{"email":{"type":"string"}}}
+			}}`,
+			want: catalog.CompatBreaking,
+			kind: catalog.ChangeRequiredAdded,
+			path: "amount",
+		},
+		{
+			name: "enum constraint removed",
+			schema: `{"type":"object","required":["id"],"properties":{
+				"id":{"type":"string"},"amount":{"type":"number"},
+				"status":{"type":"string"},
+				"customer":{"type":"object","properties":{"email":{"type":"string"}}}
+			}}`,
+			want: catalog.CompatBackward,
+			kind: catalog.ChangeEnumRemoved,
+			path: "status",
+		},
+		{
+			name: "enum constraint added",
+			schema: `{"type":"object","required":["id"],"properties":{
+				"id":{"type":"string"},"amount":{"type":"number","enum":[1,2]},
+				"status":{"type":"string","enum":["open","paid"]},
+				"customer":{"type":"object","properties":{"email":{"type":"string"}}}
+			}}`,
+			want: catalog.CompatBreaking,
+			kind: catalog.ChangeEnumAdded,
+			path: "amount",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := catalog.CompareSchemas(json.RawMessage(base), json.RawMessage(tt.schema))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.Compatibility != tt.want {
+				t.Fatalf("expected %s, got %s: %+v", tt.want, report.Compatibility, report.Changes)
+			}
+			if tt.kind == "" {
+				if len(report.Changes) != 0 {
+					t.Fatalf("expected no changes, got %+v", report.Changes)
+				}
+				return
+			}
+			if len(report.Changes) != 1 || report.Changes[0].Kind != tt.kind || report.Changes[0].Path != tt.path {
+				t.Fatalf("expected one %s change at %q, got %+v", tt.kind, tt.path, report.Changes)
+			}
+		})
+	}
+}
+
+func TestCatalogCompatPolicy(t *testing.T) {
+	c := catalog.NewCatalog(memory.New(), catalog.Config{CompatPolicy: catalog.CompatPolicyReject}, nil)
+
+	def := catalog.WebhookDefinition{
+		Name:    "invoice.created",
+		Version: "2025-01-01",
+		Schema:  json.RawMessage(`{"type":"object","properties":{"total":{"type":"number"}}}`),
+	}
+	if _, err := c.RegisterType(ctx(), def); err != nil {
+		t.Fatal(err)
+	}
+
+	// Compatible changes to the same version are accepted.
+	def.Schema = json.RawMessage(`{"type":"object","properties":{"total":{"type":"number"},"memo":{"type":"string"}}}`)
+	if _, err := c.RegisterType(ctx(), def); err != nil {
+		t.Fatalf("expected compatible change to register, got %v", err)
+	}
+
+	// Breaking changes are rejected with a report.
+	breaking := def
+	breaking.Schema = json.RawMessage(`{"type":"object","properties":{"memo":{"type":"string"}}}`)
+	_, err := c.RegisterType(ctx(), breaking)
+	var incompatible *catalog.IncompatibleSchemaError
+	if !errors.As(err, &incompatible) {
+		t.Fatalf("expected IncompatibleSchemaError, got %v", err)
+	}
+	if len(incompatible.Report.Breaking()) != 1 || incompatible.Report.Breaking()[0].Path != "total" {
+		t.Fatalf("unexpected report: %+v", incompatible.Report)
+	}
+
+	// A new version may break the previous one.
+	next := breaking
+	next.Version = "2025-06-01"
+	if _, err := c.RegisterType(ctx(), next); err != nil {
+		t.Fatalf("expected new version to register, got %v", err)
+	}
+
+	// The policy can be overridden per registration.
+	if _, err := c.RegisterType(ctx(), breaking, catalog.WithCompatPolicy(catalog.CompatPolicyAllow)); err != nil {
+		t.Fatalf("expected allow override to register, got %v", err)
+	}
+
+	// CheckCompatibility reports against the latest version without registering.
+	report, err := c.CheckCompatibility(ctx(), catalog.WebhookDefinition{
+		Name:    "invoice.created",
+		Version: "2026-01-01",
+		Schema:  json.RawMessage(`{"type":"object","properties":{"memo":{"type":"integer"}}}`),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.FromVersion != "2025-06-01" || report.Compatibility != catalog.CompatBreaking {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	et, err := c.GetType(ctx(), "invoice.created")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(et.Versions) != 2 {
+		t.Fatalf("expected CheckCompatibility not to register, got %d versions", len(et.Versions))
+	}
+}
@@ -0,0 +1,56 @@
+package catalog
+
+import (
+	"context"
+	"time"
+)
+
+// IsSunset reports whether the event type no longer accepts new events at
+// now: it is deprecated and its sunset date has passed. Types deprecated
+// without a sunset date, such as those deleted before sunset dates existed,
+// are treated as already sunset.
+func (et *EventType) IsSunset(now time.Time) bool {
+	if !et.IsDeprecated {
+		return false
+	}
+	return et.SunsetAt == nil || !now.Before(*et.SunsetAt)
+}
+
+// Deprecate deprecates an event type with a sunset date. Until sunsetAt,
+// events of the type are still accepted, with a warning to the producer,
+// and delivered with Deprecation and Sunset headers; afterwards they are
+// rejected. Deprecate returns the updated event type.
+func (c *Catalog) Deprecate(ctx context.Context, name string, sunsetAt time.Time) (*EventType, error) {
+	if err := c.store.DeprecateType(ctx, name, sunsetAt.UTC()); err != nil {
+		return nil, err
+	}
+	return c.reload(ctx, name)
+}
+
+// Undeprecate reverses Deprecate or DeleteType, making the event type
+// fully active again. It returns the updated event type.
+func (c *Catalog) Undeprecate(ctx context.Context, name string) (*EventType, error) {
+	if err := c.store.UndeprecateType(ctx, name); err != nil {
+		return nil, err
+	}
+	return c.reload(ctx, name)
+}
+
+// Deprecation returns when an event type was deprecated and when it sunsets,
+// both nil for active or unknown types.
+func (c *Catalog) Deprecation(ctx context.Context, name string) (deprecatedAt, sunsetAt *time.Time) {
+	et, err := c.GetType(ctx, name)
+	if err != nil || !et.IsDeprecated {
+		return nil, nil
+	}
+	return et.DeprecatedAt, et.SunsetAt
+}
+
+// reload drops name from the cache and reads it back from the store.
+func (c *Catalog) reload(ctx context.Context, name string) (*EventType, error) {
+	c.mu.Lock()
+	delete(c.cache, name)
+	c.mu.Unlock()
+
+	return c.GetType(ctx, name)
+}
@@ -15,15 +15,24 @@ type EventType struct {
 	// ID is the unique TypeID for this event type.
 	ID id.ID `json:"id"`
 
-	// Definition contains the webhook event type descriptor.
+	// Definition contains the webhook event type descriptor. Its Version,
+	// Schema and Example describe the latest version.
 	Definition WebhookDefinition `json:"definition"`
 
-	// IsDeprecated indicates whether this event type has been soft-deleted.
+	// Versions lists every live version, oldest first.
+	Versions []Version `json:"versions,omitempty"`
+
+	// IsDeprecated indicates whether this event type has been deprecated.
 	IsDeprecated bool `json:"deprecated"`
 
 	// DeprecatedAt is when the event type was deprecated.
 	DeprecatedAt *time.Time `json:"deprecated_at,omitempty"`
 
+	// SunsetAt is when a deprecated event type stops accepting new events.
+	// Until then events are still accepted and delivered with Deprecation
+	// and Sunset headers.
+	SunsetAt *time.Time `json:"sunset_at,omitempty"`
+
 	// ScopeAppID scopes the event type to a specific app (platform-level).
 	ScopeAppID string `json:"scope_app_id,omitempty"`
 
@@ -31,10 +40,16 @@ type EventType struct {
 	Metadata map[string]string `json:"metadata,omitempty"`
 }
 
-// ListOpts configures filtering and pagination for event type listing.
+// ListOpts configures filtering and pagination for event type listing. Lists are
+// ordered by ID, oldest first.
 type ListOpts struct {
+	// Cursor, when set, starts the list after the item with this ID; see
+	// package page.
+	Cursor            id.ID
 	Offset            int
 	Limit             int
 	Group             string
 	IncludeDeprecated bool
+	// ScopeAppID, when set, only lists event types scoped to that app.
+	ScopeAppID string
 }
@@ -0,0 +1,352 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// DocInfo is the info block of an exported catalog document.
+type DocInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// DefaultDocInfo is used for any DocInfo field left empty.
+var DefaultDocInfo = DocInfo{
+	Title:   "Webhooks",
+	Version: "1.0.0",
+}
+
+// DocRef is a JSON Reference ("$ref") to another part of a document.
+type DocRef struct {
+	Ref string `json:"$ref"`
+}
+
+// DocTag groups messages and operations; event type groups become tags.
+type DocTag struct {
+	Name string `json:"name"`
+}
+
+// webhookHeader describes a header delivery.Sender sets on every request.
+type webhookHeader struct {
+	name        string
+	description string
+	required    bool
+}
+
+var webhookHeaders = []webhookHeader{
+	{"X-Relay-Event-ID", "Unique event ID. Retries of the same event share it, so use it to deduplicate.", true},
+	{"X-Relay-Event-Type", "Event type name.", true},
+	{"X-Relay-Delivery-ID", "Unique ID of the delivery to this endpoint.", true},
+	{"X-Relay-Event-Version", "Payload version. Set when the event type is versioned.", false},
+	{"X-Relay-Signature", `HMAC-SHA256 of "{timestamp}.{body}" with the endpoint secret, formatted "v1=<hex>".`, true},
+	{"X-Relay-Timestamp", "Unix timestamp, in seconds, included in the signature.", true},
+	{"Deprecation", "Set while the event type is deprecated: when it was deprecated, as @<unix seconds>.", false},
+	{"Sunset", "Set while the event type is deprecated: when it stops being sent, as an HTTP date.", false},
+}
+
+// ---------------------------------------------------------------------------
+// AsyncAPI 3.0
+// ---------------------------------------------------------------------------
+
+// AsyncAPIDocument is an AsyncAPI 3.0 document with one channel, operation
+// and message per event type.
+type AsyncAPIDocument struct {
+	AsyncAPI           string                       `json:"asyncapi"`
+	Info               DocInfo                      `json:"info"`
+	DefaultContentType string                       `json:"defaultContentType"`
+	Channels           map[string]AsyncAPIChannel   `json:"channels"`
+	Operations         map[string]AsyncAPIOperation `json:"operations"`
+	Components         AsyncAPIComponents           `json:"components"`
+}
+
+// AsyncAPIChannel is the channel of one event type; its address is the
+// event type name.
+type AsyncAPIChannel struct {
+	Address     string            `json:"address"`
+	Description string            `json:"description,omitempty"`
+	Messages    map[string]DocRef `json:"messages"`
+}
+
+// AsyncAPIOperation is Relay sending one event type.
+type AsyncAPIOperation struct {
+	Action   string   `json:"action"`
+	Channel  DocRef   `json:"channel"`
+	Summary  string   `json:"summary,omitempty"`
+	Tags     []DocTag `json:"tags,omitempty"`
+	Messages []DocRef `json:"messages"`
+}
+
+// AsyncAPIComponents holds the messages and the shared header schema.
+type AsyncAPIComponents struct {
+	Messages map[string]AsyncAPIMessage `json:"messages"`
+	Schemas  map[string]json.RawMessage `json:"schemas"`
+}
+
+// AsyncAPIMessage describes the payload of one event type.
+type AsyncAPIMessage struct {
+	Name        string            `json:"name"`
+	Title       string            `json:"title"`
+	Summary     string            `json:"summary,omitempty"`
+	ContentType string            `json:"contentType"`
+	Tags        []DocTag          `json:"tags,omitempty"`
+	Headers     DocRef            `json:"headers"`
+	Payload     json.RawMessage   `json:"payload,omitempty"`
+	Examples    []AsyncAPIExample `json:"examples,omitempty"`
+
+	Version    string     `json:"x-relay-version,omitempty"`
+	Deprecated bool       `json:"x-relay-deprecated,omitempty"`
+	SunsetAt   *time.Time `json:"x-relay-sunset-at,omitempty"`
+}
+
+// AsyncAPIExample is an example payload.
+type AsyncAPIExample struct {
+	Payload json.RawMessage `json:"payload"`
+}
+
+const headersSchemaName = "RelayHeaders"
+
+// NewAsyncAPIDocument builds an AsyncAPI 3.0 document from event types.
+// Each type is described by its latest version.
+func NewAsyncAPIDocument(types []*EventType, info DocInfo) *AsyncAPIDocument {
+	doc := &AsyncAPIDocument{
+		AsyncAPI:           "3.0.0",
+		Info:               withDefaults(info),
+		DefaultContentType: "application/json",
+		Channels:           make(map[string]AsyncAPIChannel, len(types)),
+		Operations:         make(map[string]AsyncAPIOperation, len(types)),
+		Components: AsyncAPIComponents{
+			Messages: make(map[string]AsyncAPIMessage, len(types)),
+			Schemas:  map[string]json.RawMessage{headersSchemaName: headersSchema()},
+		},
+	}
+
+	for _, et := range sortedTypes(types) {
+		def := et.Definition
+		tags := groupTags(def.Group)
+
+		msg := AsyncAPIMessage{
+			Name:        def.Name,
+			Title:       def.Name,
+			Summary:     def.Description,
+			ContentType: "application/json",
+			Tags:        tags,
+			Headers:     DocRef{Ref: "#/components/schemas/" + headersSchemaName},
+			Payload:     def.Schema,
+			Version:     def.Version,
+			Deprecated:  et.IsDeprecated,
+			SunsetAt:    et.SunsetAt,
+		}
+		if len(def.Example) > 0 {
+			msg.Examples = []AsyncAPIExample{{Payload: def.Example}}
+		}
+		doc.Components.Messages[def.Name] = msg
+
+		doc.Channels[def.Name] = AsyncAPIChannel{
+			Address:     def.Name,
+			Description: def.Description,
+			Messages:    map[string]DocRef{def.Name: {Ref: "#/components/messages/" + def.Name}},
+		}
+		doc.Operations[def.Name] = AsyncAPIOperation{
+			Action:   "send",
+			Channel:  DocRef{Ref: "#/channels/" + def.Name},
+			Summary:  def.Description,
+			Tags:     tags,
+			Messages: []DocRef{{Ref: "#/channels/" + def.Name + "/messages/" + def.Name}},
+		}
+	}
+	return doc
+}
+
+// headersSchema is the JSON Schema of the headers on every delivery.
+func headersSchema() json.RawMessage {
+	props := make(map[string]any, len(webhookHeaders))
+	var required []string
+	for _, h := range webhookHeaders {
+		props[h.name] = map[string]any{"type": "string", "description": h.description}
+		if h.required {
+			required = append(required, h.name)
+		}
+	}
+	b, _ := json.Marshal(map[string]any{
+		"type":       "object",
+		"properties": props,
+		"required":   required,
+	})
+	return b
+}
+
+// ---------------------------------------------------------------------------
+// OpenAPI 3.1 webhooks
+// ---------------------------------------------------------------------------
+
+// OpenAPIDocument is an OpenAPI 3.1 document that only has a webhooks
+// section, one entry per event type. Merge Webhooks into an existing
+// document to publish them alongside an API.
+type OpenAPIDocument struct {
+	OpenAPI  string                     `json:"openapi"`
+	Info     DocInfo                    `json:"info"`
+	Webhooks map[string]OpenAPIPathItem `json:"webhooks"`
+}
+
+// OpenAPIPathItem is a webhook; Relay delivers with POST.
+type OpenAPIPathItem struct {
+	Post *OpenAPIOperation `json:"post"`
+}
+
+// OpenAPIOperation describes the request Relay sends for one event type.
+type OpenAPIOperation struct {
+	OperationID string                     `json:"operationId"`
+	Summary     string                     `json:"summary,omitempty"`
+	Tags        []string                   `json:"tags,omitempty"`
+	Deprecated  bool                       `json:"deprecated,omitempty"`
+	Parameters  []OpenAPIParameter         `json:"parameters"`
+	RequestBody OpenAPIRequestBody         `json:"requestBody"`
+	Responses   map[string]OpenAPIResponse `json:"responses"`
+
+	Version  string     `json:"x-relay-version,omitempty"`
+	SunsetAt *time.Time `json:"x-relay-sunset-at,omitempty"`
+}
+
+// OpenAPIParameter is a request header.
+type OpenAPIParameter struct {
+	Name        string          `json:"name"`
+	In          string          `json:"in"`
+	Description string          `json:"description,omitempty"`
+	Required    bool            `json:"required"`
+	Schema      json.RawMessage `json:"schema"`
+}
+
+// OpenAPIRequestBody is the webhook payload.
+type OpenAPIRequestBody struct {
+	Required bool                        `json:"required"`
+	Content  map[string]OpenAPIMediaType `json:"content"`
+}
+
+// OpenAPIMediaType holds the payload schema and example.
+type OpenAPIMediaType struct {
+	Schema  json.RawMessage `json:"schema,omitempty"`
+	Example json.RawMessage `json:"example,omitempty"`
+}
+
+// OpenAPIResponse is an expected response from the receiver.
+type OpenAPIResponse struct {
+	Description string `json:"description"`
+}
+
+// NewOpenAPIDocument builds an OpenAPI 3.1 webhooks document from event
+// types. Each type is described by its latest version.
+func NewOpenAPIDocument(types []*EventType, info DocInfo) *OpenAPIDocument {
+	doc := &OpenAPIDocument{
+		OpenAPI:  "3.1.0",
+		Info:     withDefaults(info),
+		Webhooks: make(map[string]OpenAPIPathItem, len(types)),
+	}
+
+	params := make([]OpenAPIParameter, len(webhookHeaders))
+	for i, h := range webhookHeaders {
+		params[i] = OpenAPIParameter{
+			Name:        h.name,
+			In:          "header",
+			Description: h.description,
+			Required:    h.required,
+			Schema:      json.RawMessage(`{"type":"string"}`),
+		}
+	}
+
+	for _, et := range sortedTypes(types) {
+		def := et.Definition
+		op := &OpenAPIOperation{
+			OperationID: def.Name,
+			Summary:     def.Description,
+			Deprecated:  et.IsDeprecated,
+			Parameters:  params,
+			RequestBody: OpenAPIRequestBody{
+				Required: true,
+				Content: map[string]OpenAPIMediaType{
+					"application/json": {Schema: def.Schema, Example: def.Example},
+				},
+			},
+			Responses: map[string]OpenAPIResponse{
+				"2XX": {Description: "Delivery acknowledged. Any other status is retried."},
+				"410": {Description: "The endpoint is gone and is disabled."},
+			},
+			Version:  def.Version,
+			SunsetAt: et.SunsetAt,
+		}
+		if def.Group != "" {
+			op.Tags = []string{def.Group}
+		}
+		doc.Webhooks[def.Name] = OpenAPIPathItem{Post: op}
+	}
+	return doc
+}
+
+// ---------------------------------------------------------------------------
+// Catalog export
+// ---------------------------------------------------------------------------
+
+// ExportAsyncAPI returns an AsyncAPI 3.0 document of the registered event
+// types. Deprecated types are included, marked with their sunset date,
+// until they are sunset.
+func (c *Catalog) ExportAsyncAPI(ctx context.Context, info DocInfo) (*AsyncAPIDocument, error) {
+	types, err := c.exportTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return NewAsyncAPIDocument(types, info), nil
+}
+
+// ExportOpenAPI returns an OpenAPI 3.1 webhooks document of the registered
+// event types, with the same selection as ExportAsyncAPI.
+func (c *Catalog) ExportOpenAPI(ctx context.Context, info DocInfo) (*OpenAPIDocument, error) {
+	types, err := c.exportTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return NewOpenAPIDocument(types, info), nil
+}
+
+// exportTypes lists the event types that can still be sent.
+func (c *Catalog) exportTypes(ctx context.Context) ([]*EventType, error) {
+	types, err := c.store.ListTypes(ctx, ListOpts{IncludeDeprecated: true})
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	live := make([]*EventType, 0, len(types))
+	for _, et := range types {
+		if !et.IsSunset(now) {
+			live = append(live, et)
+		}
+	}
+	return live, nil
+}
+
+func withDefaults(info DocInfo) DocInfo {
+	if info.Title == "" {
+		info.Title = DefaultDocInfo.Title
+	}
+	if info.Version == "" {
+		info.Version = DefaultDocInfo.Version
+	}
+	return info
+}
+
+func groupTags(group string) []DocTag {
+	if group == "" {
+		return nil
+	}
+	return []DocTag{{Name: group}}
+}
+
+func sortedTypes(types []*EventType) []*EventType {
+	sorted := append([]*EventType(nil), types...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Definition.Name < sorted[j].Definition.Name
+	})
+	return sorted
+}
@@ -0,0 +1,104 @@
+package catalog_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/xraph/relay/catalog"
+)
+
+func TestExportAsyncAPI(t *testing.T) {
+	c := newCatalog()
+	for _, def := range []catalog.WebhookDefinition{
+		{
+			Name:        "invoice.created",
+			Description: "An invoice was created",
+			Group:       "billing",
+			Version:     "2025-01-01",
+			Schema:      json.RawMessage(`{"type":"object","required":["total"]}`),
+			Example:     json.RawMessage(`{"total":5}`),
+		},
+		{Name: "user.deleted", Version: "2025-01-01"},
+		{Name: "legacy.event", Version: "2024-01-01"},
+	} {
+		if _, err := c.RegisterType(ctx(), def); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := c.Deprecate(ctx(), "user.deleted", time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.DeleteType(ctx(), "legacy.event"); err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := c.ExportAsyncAPI(ctx(), catalog.DocInfo{Title: "Acme"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.AsyncAPI != "3.0.0" || doc.Info.Title != "Acme" || doc.Info.Version != catalog.DefaultDocInfo.Version {
+		t.Fatalf("unexpected header: %+v", doc)
+	}
+	if len(doc.Channels) != 2 || len(doc.Operations) != 2 {
+		t.Fatalf("expected sunset type to be left out, got channels %v", doc.Channels)
+	}
+
+	msg := doc.Components.Messages["invoice.created"]
+	if msg.Summary != "An invoice was created" || msg.Version != "2025-01-01" {
+		t.Fatalf("unexpected message: %+v", msg)
+	}
+	if len(msg.Tags) != 1 || msg.Tags[0].Name != "billing" {
+		t.Fatalf("expected billing tag, got %+v", msg.Tags)
+	}
+	if string(msg.Payload) != `{"type":"object","required":["total"]}` || len(msg.Examples) != 1 {
+		t.Fatalf("expected schema and example, got %+v", msg)
+	}
+	if !doc.Components.Messages["user.deleted"].Deprecated {
+		t.Fatal("expected deprecated message to be marked")
+	}
+
+	var headers struct {
+		Required []string `json:"required"`
+	}
+	if err := json.Unmarshal(doc.Components.Schemas["RelayHeaders"], &headers); err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, h := range headers.Required {
+		found = found || h == "X-Relay-Signature"
+	}
+	if !found {
+		t.Fatalf("expected signature header to be required, got %v", headers.Required)
+	}
+}
+
+func TestExportOpenAPI(t *testing.T) {
+	c := newCatalog()
+	if _, err := c.RegisterType(ctx(), catalog.WebhookDefinition{
+		Name:    "invoice.created",
+		Group:   "billing",
+		Version: "2025-01-01",
+		Schema:  json.RawMessage(`{"type":"object"}`),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := c.ExportOpenAPI(ctx(), catalog.DocInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.OpenAPI != "3.1.0" {
+		t.Fatalf("expected OpenAPI 3.1.0, got %q", doc.OpenAPI)
+	}
+	op := doc.Webhooks["invoice.created"].Post
+	if op == nil {
+		t.Fatal("expected invoice.created webhook")
+	}
+	if string(op.RequestBody.Content["application/json"].Schema) != `{"type":"object"}` {
+		t.Fatalf("unexpected request body: %+v", op.RequestBody)
+	}
+	if len(op.Tags) != 1 || op.Tags[0] != "billing" || len(op.Parameters) == 0 {
+		t.Fatalf("unexpected operation: %+v", op)
+	}
+}
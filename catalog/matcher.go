@@ -8,9 +8,10 @@ import "strings"
 //
 //	"invoice.created"  → exact match
 //	"invoice.*"        → matches invoice.created, invoice.paid, etc. (single segment wildcard)
+//	"invoice.**"       → matches invoice, invoice.created, invoice.payment.failed, etc. (zero or more segments)
 //	"*"                → matches everything
 func Match(pattern, eventType string) bool {
-	if pattern == "*" {
+	if pattern == "*" || pattern == "**" {
 		return true
 	}
 
@@ -21,18 +22,33 @@ func Match(pattern, eventType string) bool {
 	patternParts := strings.Split(pattern, ".")
 	eventParts := strings.Split(eventType, ".")
 
-	if len(patternParts) != len(eventParts) {
+	if !strings.Contains(pattern, "**") && len(patternParts) != len(eventParts) {
 		return false
 	}
 
-	for i, pp := range patternParts {
-		if pp == "*" {
-			continue
+	return matchSegments(patternParts, eventParts)
+}
+
+// matchSegments matches dotted segments, letting "**" absorb any number of
+// event segments (including none).
+func matchSegments(pattern, event []string) bool {
+	for i, pp := range pattern {
+		if pp == "**" {
+			rest := pattern[i+1:]
+			for j := i; j <= len(event); j++ {
+				if matchSegments(rest, event[j:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if i >= len(event) {
+			return false
 		}
-		if pp != eventParts[i] {
+		if pp != "*" && pp != event[i] {
 			return false
 		}
 	}
 
-	return true
+	return len(pattern) == len(event)
 }
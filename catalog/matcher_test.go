@@ -40,6 +40,21 @@ func TestMatch(t *testing.T) {
 		{"invoice.*.completed", "invoice.paid", false},
 		{"invoice", "invoice.created", false},
 
+		// Multi-segment wildcard.
+		{"**", "invoice.payment.completed", true},
+		{"invoice.**", "invoice", true},
+		{"invoice.**", "invoice.created", true},
+		{"invoice.**", "invoice.payment.completed", true},
+		{"invoice.**", "user.created", false},
+		{"**.completed", "invoice.payment.completed", true},
+		{"**.completed", "completed", true},
+		{"**.completed", "invoice.payment.failed", false},
+		{"invoice.**.completed", "invoice.completed", true},
+		{"invoice.**.completed", "invoice.payment.refund.completed", true},
+		{"invoice.**.completed", "invoice.payment.failed", false},
+		{"*.**.failed", "invoice.failed", true},
+		{"*.**.failed", "failed", false},
+
 		// Edge cases.
 		{"", "", true},
 		{"a", "a", true},
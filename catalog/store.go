@@ -2,16 +2,23 @@ package catalog
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/xraph/relay/id"
 )
 
+// ErrEventTypeNotFound is returned by Store.GetType when no event type has
+// the given name. relay.ErrEventTypeNotFound is the same error.
+var ErrEventTypeNotFound = errors.New("relay: event type not found")
+
 // Store defines the persistence contract for the event type catalog.
 type Store interface {
 	// RegisterType creates or updates an event type definition.
 	RegisterType(ctx context.Context, et *EventType) error
 
-	// GetType returns an event type by name (e.g. "invoice.created").
+	// GetType returns an event type by name (e.g. "invoice.created"), or
+	// ErrEventTypeNotFound when none is registered.
 	GetType(ctx context.Context, name string) (*EventType, error)
 
 	// GetTypeByID returns an event type by its TypeID.
@@ -20,9 +27,21 @@ type Store interface {
 	// ListTypes returns all registered event types, optionally filtered.
 	ListTypes(ctx context.Context, opts ListOpts) ([]*EventType, error)
 
-	// DeleteType soft-deletes (deprecates) an event type.
+	// CountTypes returns the number of event types ListTypes lists for
+	// opts, ignoring its pagination.
+	CountTypes(ctx context.Context, opts ListOpts) (int64, error)
+
+	// DeleteType soft-deletes an event type by deprecating it with an
+	// immediate sunset.
 	DeleteType(ctx context.Context, name string) error
 
+	// DeprecateType deprecates an event type, keeping it usable until
+	// sunsetAt. Deprecating an already deprecated type moves its sunset date.
+	DeprecateType(ctx context.Context, name string, sunsetAt time.Time) error
+
+	// UndeprecateType clears an event type's deprecation.
+	UndeprecateType(ctx context.Context, name string) error
+
 	// MatchTypes returns event types matching a glob pattern (e.g. "invoice.*").
 	MatchTypes(ctx context.Context, pattern string) ([]*EventType, error)
 }
@@ -1,6 +1,7 @@
 package catalog
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -23,6 +24,7 @@ func NewValidator() *Validator {
 }
 
 // Validate checks the given data against the schema. If schema is nil, validation is skipped.
+// Data may be any value that marshals to JSON, such as a generated payload struct.
 func (v *Validator) Validate(schema, data any) error {
 	if schema == nil {
 		return nil
@@ -33,7 +35,28 @@ func (v *Validator) Validate(schema, data any) error {
 		return fmt.Errorf("schema compilation error: %w", err)
 	}
 
-	return compiled.Validate(data)
+	doc, err := jsonValue(data)
+	if err != nil {
+		return err
+	}
+	return compiled.Validate(doc)
+}
+
+// jsonValue converts data to the generic values the schema validator
+// understands by round-tripping it through JSON. Numbers are kept as
+// json.Number so large integers are not rounded.
+func jsonValue(data any) (any, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("marshal payload: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("unmarshal payload: %w", err)
+	}
+	return doc, nil
 }
 
 // compile returns a compiled schema, using the cache for previously-seen schemas.
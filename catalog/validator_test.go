@@ -97,3 +97,26 @@ func TestValidatorCaching(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestValidatorStructPayload(t *testing.T) {
+	v := catalog.NewValidator()
+
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"amount": map[string]any{"type": "integer", "maximum": 100},
+		},
+		"required": []any{"amount"},
+	}
+
+	type payload struct {
+		Amount int `json:"amount"`
+	}
+
+	if err := v.Validate(schema, payload{Amount: 50}); err != nil {
+		t.Fatal("struct payload should pass, got:", err)
+	}
+	if err := v.Validate(schema, payload{Amount: 500}); err == nil {
+		t.Fatal("expected maximum to be enforced on struct payload")
+	}
+}
@@ -0,0 +1,194 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Version is one live version of an event type's payload contract. Several
+// versions of a type can be live at once; endpoints pinned to an older
+// version receive payloads converted by registered ConverterFuncs.
+type Version struct {
+	// Version is the version identifier. Versions are ordered by plain
+	// string comparison, so use sortable values such as "2025-01-01".
+	Version string `json:"version"`
+
+	// Description and Group describe the event type as of this version.
+	Description string `json:"description,omitempty"`
+	Group       string `json:"group,omitempty"`
+
+	// Schema is the JSON Schema payloads of this version must satisfy.
+	Schema json.RawMessage `json:"schema,omitempty"`
+
+	// SchemaVersion tracks changes to Schema within this version.
+	SchemaVersion string `json:"schema_version,omitempty"`
+
+	// Example is an optional example payload for this version.
+	Example json.RawMessage `json:"example,omitempty"`
+
+	// CreatedAt is when this version was first registered.
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ConverterFunc rewrites a payload from one version of an event type to an
+// adjacent one.
+type ConverterFunc func(ctx context.Context, data json.RawMessage) (json.RawMessage, error)
+
+// LookupVersion returns the live version v of the event type. Types
+// registered before versioning have no Versions and expose their
+// Definition as their only version.
+func (et *EventType) LookupVersion(v string) (Version, bool) {
+	for _, ver := range et.allVersions() {
+		if ver.Version == v {
+			return ver, true
+		}
+	}
+	return Version{}, false
+}
+
+// ResolveVersion picks the version delivered to an endpoint pinned to pin:
+// the newest version not after pin, or the oldest version when all are
+// newer. Like Stripe API versions, a pin is a point in time rather than a
+// per-type version, so one pin works across every event type.
+func (et *EventType) ResolveVersion(pin string) string {
+	versions := et.allVersions()
+	if len(versions) == 0 {
+		return ""
+	}
+	resolved := versions[0].Version
+	for _, ver := range versions {
+		if ver.Version <= pin {
+			resolved = ver.Version
+		}
+	}
+	return resolved
+}
+
+// allVersions returns Versions, or the Definition as a single version for
+// types registered before versioning.
+func (et *EventType) allVersions() []Version {
+	if len(et.Versions) > 0 {
+		return et.Versions
+	}
+	return []Version{versionOf(et.Definition, et.CreatedAt)}
+}
+
+func versionOf(def WebhookDefinition, createdAt time.Time) Version {
+	return Version{
+		Version:       def.Version,
+		Description:   def.Description,
+		Group:         def.Group,
+		Schema:        def.Schema,
+		SchemaVersion: def.SchemaVersion,
+		Example:       def.Example,
+		CreatedAt:     createdAt,
+	}
+}
+
+// mergeVersion adds or replaces def's version in existing and returns the
+// versions sorted oldest first.
+func mergeVersion(existing []Version, def WebhookDefinition, now time.Time) []Version {
+	merged := make([]Version, 0, len(existing)+1)
+	added := versionOf(def, now)
+	for _, ver := range existing {
+		if ver.Version == def.Version {
+			added.CreatedAt = ver.CreatedAt
+			continue
+		}
+		merged = append(merged, ver)
+	}
+	merged = append(merged, added)
+
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].Version < merged[j].Version
+	})
+	return merged
+}
+
+type converterKey struct {
+	eventType string
+	from      string
+	to        string
+}
+
+// RegisterConverter registers fn to convert eventType payloads from version
+// from to version to. Converters chain: registering 2025-06-01→2025-01-01
+// and 2025-01-01→2024-06-01 lets Convert go from 2025-06-01 to 2024-06-01.
+// Converters live in memory and must be registered on every instance that
+// runs the delivery engine.
+func (c *Catalog) RegisterConverter(eventType, from, to string, fn ConverterFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.converters == nil {
+		c.converters = make(map[converterKey]ConverterFunc)
+	}
+	c.converters[converterKey{eventType, from, to}] = fn
+}
+
+// Convert rewrites data of eventType from version from to version to,
+// following the shortest chain of registered converters.
+func (c *Catalog) Convert(ctx context.Context, eventType string, data json.RawMessage, from, to string) (json.RawMessage, error) {
+	if from == to {
+		return data, nil
+	}
+
+	path := c.converterPath(eventType, from, to)
+	if path == nil {
+		return nil, fmt.Errorf("catalog: no converter for %s from %s to %s", eventType, from, to)
+	}
+
+	for _, step := range path {
+		out, err := step.fn(ctx, data)
+		if err != nil {
+			return nil, fmt.Errorf("catalog: convert %s from %s to %s: %w", eventType, step.from, step.to, err)
+		}
+		data = out
+	}
+	return data, nil
+}
+
+type converterStep struct {
+	from, to string
+	fn       ConverterFunc
+}
+
+// converterPath finds the shortest chain of converters from one version to
+// another with a breadth-first search, or nil if there is none.
+func (c *Catalog) converterPath(eventType, from, to string) []converterStep {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	edges := make(map[string][]converterStep)
+	for key, fn := range c.converters {
+		if key.eventType == eventType {
+			edges[key.from] = append(edges[key.from], converterStep{key.from, key.to, fn})
+		}
+	}
+
+	prev := map[string]converterStep{from: {}}
+	queue := []string{from}
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		if v == to {
+			var path []converterStep
+			for v != from {
+				step := prev[v]
+				path = append([]converterStep{step}, path...)
+				v = step.from
+			}
+			return path
+		}
+		for _, step := range edges[v] {
+			if _, seen := prev[step.to]; !seen {
+				prev[step.to] = step
+				queue = append(queue, step.to)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package catalog_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/xraph/relay/catalog"
+	"github.com/xraph/relay/store/memory"
+)
+
+func TestCatalogRegisterVersions(t *testing.T) {
+	c := newCatalog()
+
+	v1, err := c.RegisterType(ctx(), catalog.WebhookDefinition{
+		Name:    "invoice.created",
+		Version: "2024-06-01",
+		Schema:  json.RawMessage(`{"type":"object","required":["amount"]}`),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v2, err := c.RegisterType(ctx(), catalog.WebhookDefinition{
+		Name:    "invoice.created",
+		Version: "2025-01-01",
+		Schema:  json.RawMessage(`{"type":"object","required":["total"]}`),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v2.ID != v1.ID {
+		t.Fatal("expected a new version to keep the event type ID")
+	}
+	if len(v2.Versions) != 2 {
+		t.Fatalf("expected 2 versions, got %d", len(v2.Versions))
+	}
+	if v2.Definition.Version != "2025-01-01" {
+		t.Fatalf("expected latest version in definition, got %q", v2.Definition.Version)
+	}
+
+	// Re-registering an older version updates it without changing the latest.
+	got, err := c.RegisterType(ctx(), catalog.WebhookDefinition{
+		Name:    "invoice.created",
+		Version: "2024-06-01",
+		Schema:  json.RawMessage(`{"type":"object"}`),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Definition.Version != "2025-01-01" || len(got.Versions) != 2 {
+		t.Fatalf("unexpected versions after re-register: %q, %d", got.Definition.Version, len(got.Versions))
+	}
+	old, ok := got.LookupVersion("2024-06-01")
+	if !ok || string(old.Schema) != `{"type":"object"}` {
+		t.Fatalf("expected updated old version, got %+v", old)
+	}
+}
+
+// failingGetStore fails every GetType call with a store error.
+type failingGetStore struct {
+	*memory.Store
+}
+
+func (failingGetStore) GetType(context.Context, string) (*catalog.EventType, error) {
+	return nil, errors.New("connection reset")
+}
+
+func TestCatalogRegisterStoreFailure(t *testing.T) {
+	s := memory.New()
+	if _, err := catalog.NewCatalog(s, catalog.Config{}, nil).RegisterType(ctx(), catalog.WebhookDefinition{
+		Name:    "invoice.created",
+		Version: "2024-06-01",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A failed lookup must not be taken for an unregistered type, which
+	// would overwrite the stored versions.
+	c := catalog.NewCatalog(failingGetStore{s}, catalog.Config{}, nil)
+	if _, err := c.RegisterType(ctx(), catalog.WebhookDefinition{
+		Name:    "invoice.created",
+		Version: "2025-01-01",
+	}); err == nil {
+		t.Fatal("expected the store error")
+	}
+
+	et, err := s.GetType(ctx(), "invoice.created")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := et.LookupVersion("2024-06-01"); !ok || len(et.Versions) != 1 {
+		t.Fatalf("stored versions changed: %+v", et.Versions)
+	}
+}
+
+func TestEventTypeResolveVersion(t *testing.T) {
+	et := &catalog.EventType{Versions: []catalog.Version{
+		{Version: "2024-06-01"},
+		{Version: "2025-01-01"},
+		{Version: "2025-06-01"},
+	}}
+
+	tests := []struct{ pin, want string }{
+		{"2025-01-01", "2025-01-01"},
+		{"2025-03-15", "2025-01-01"},
+		{"2026-01-01", "2025-06-01"},
+		{"2020-01-01", "2024-06-01"},
+	}
+	for _, tt := range tests {
+		if got := et.ResolveVersion(tt.pin); got != tt.want {
+			t.Errorf("ResolveVersion(%q) = %q, want %q", tt.pin, got, tt.want)
+		}
+	}
+
+	// Types registered before versioning expose their definition.
+	legacy := &catalog.EventType{Definition: catalog.WebhookDefinition{Version: "v1"}}
+	if _, ok := legacy.LookupVersion("v1"); !ok {
+		t.Fatal("expected legacy definition to be its only version")
+	}
+}
+
+func TestCatalogConvertChain(t *testing.T) {
+	c := newCatalog()
+
+	rename := func(from, to string) catalog.ConverterFunc {
+		return func(_ context.Context, data json.RawMessage) (json.RawMessage, error) {
+			var m map[string]any
+			if err := json.Unmarshal(data, &m); err != nil {
+				return nil, err
+			}
+			m[to] = m[from]
+			delete(m, from)
+			return json.Marshal(m)
+		}
+	}
+	c.RegisterConverter("invoice.created", "v3", "v2", rename("grand_total", "total"))
+	c.RegisterConverter("invoice.created", "v2", "v1", rename("total", "amount"))
+
+	out, err := c.Convert(ctx(), "invoice.created", json.RawMessage(`{"grand_total":5}`), "v3", "v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != `{"amount":5}` {
+		t.Fatalf("unexpected conversion result %s", out)
+	}
+
+	if _, err := c.Convert(ctx(), "invoice.created", json.RawMessage(`{}`), "v1", "v3"); err == nil {
+		t.Fatal("expected error without an upgrade path")
+	}
+}
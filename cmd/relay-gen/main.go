@@ -0,0 +1,101 @@
+// Command relay-gen generates typed payload code from catalog definitions:
+// Go structs with typed Send helpers for producers, and TypeScript types
+// for consumers.
+//
+// Definitions are read from a manifest directory (see package manifest) or
+// from a JSON file saved from the admin API's GET /event-types. Use it with
+// go generate:
+//
+//	//go:generate go run github.com/xraph/relay/cmd/relay-gen -manifest ../webhooks -go events_gen.go -package events
+//	//go:generate go run github.com/xraph/relay/cmd/relay-gen -manifest ../webhooks -ts ../sdk/src/events.ts
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/xraph/relay/catalog"
+	"github.com/xraph/relay/codegen"
+	"github.com/xraph/relay/manifest"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "relay-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	fs := flag.NewFlagSet("relay-gen", flag.ContinueOnError)
+	manifestDir := fs.String("manifest", "", "directory of definition files")
+	catalogFile := fs.String("catalog", "", "JSON file with the output of GET /event-types")
+	goOut := fs.String("go", "", "write Go code to this file")
+	pkg := fs.String("package", "events", "package name of the Go code")
+	noSender := fs.Bool("no-sender", false, "omit the Sender and its Send helpers")
+	tsOut := fs.String("ts", "", "write TypeScript types to this file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if (*manifestDir == "") == (*catalogFile == "") {
+		return errors.New("set exactly one of -manifest and -catalog")
+	}
+	if *goOut == "" && *tsOut == "" {
+		return errors.New("set -go, -ts or both")
+	}
+
+	defs, err := definitions(*manifestDir, *catalogFile)
+	if err != nil {
+		return err
+	}
+
+	if *goOut != "" {
+		src, err := codegen.Go(defs, codegen.GoOptions{Package: *pkg, NoSender: *noSender})
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(*goOut, src, 0o644); err != nil { //nolint:gosec // generated source is not secret
+			return err
+		}
+	}
+	if *tsOut != "" {
+		src, err := codegen.TypeScript(defs)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(*tsOut, src, 0o644); err != nil { //nolint:gosec // generated source is not secret
+			return err
+		}
+	}
+	return nil
+}
+
+func definitions(manifestDir, catalogFile string) ([]catalog.WebhookDefinition, error) {
+	if manifestDir != "" {
+		m, err := manifest.LoadDir(manifestDir)
+		if err != nil {
+			return nil, err
+		}
+		return m.Definitions(), nil
+	}
+
+	data, err := os.ReadFile(catalogFile)
+	if err != nil {
+		return nil, err
+	}
+	var types []struct {
+		Definition catalog.WebhookDefinition `json:"definition"`
+	}
+	if err := json.Unmarshal(data, &types); err != nil {
+		return nil, fmt.Errorf("%s: %w", catalogFile, err)
+	}
+	defs := make([]catalog.WebhookDefinition, len(types))
+	for i, et := range types {
+		defs[i] = et.Definition
+	}
+	return defs, nil
+}
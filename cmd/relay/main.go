@@ -0,0 +1,90 @@
+// Command relay is an operator tool for Relay. It talks to the admin API
+// of a running instance, or directly to a store:
+//
+//	relay -api https://app.example.com/webhooks endpoints list -tenant acme
+//	relay -store postgres://localhost/app dlq replay -from 24h
+//	relay -store sqlite://relay.db migrate
+//
+// RELAY_API_URL, RELAY_STORE and RELAY_API_KEY set the defaults. Run
+// "relay -h" for the list of commands.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/xraph/grove"
+	"github.com/xraph/grove/drivers/mongodriver"
+	"github.com/xraph/grove/drivers/pgdriver"
+	"github.com/xraph/grove/drivers/sqlitedriver"
+	"github.com/xraph/grove/kv"
+	"github.com/xraph/grove/kv/drivers/redisdriver"
+
+	"github.com/xraph/relay/internal/cli"
+	"github.com/xraph/relay/store"
+	mongostore "github.com/xraph/relay/store/mongo"
+	pgstore "github.com/xraph/relay/store/postgres"
+	redisstore "github.com/xraph/relay/store/redis"
+	sqlitestore "github.com/xraph/relay/store/sqlite"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	code := cli.Run(ctx, os.Args[1:], cli.Options{OpenStore: openStore})
+	stop()
+	os.Exit(code)
+}
+
+// openStore opens a store from a DSN. The scheme picks the backend:
+// postgres://, sqlite://<path>, mongodb:// or redis://.
+func openStore(ctx context.Context, dsn string) (store.Store, error) {
+	scheme, rest, _ := strings.Cut(dsn, "://")
+	switch scheme {
+	case "postgres", "postgresql":
+		drv := pgdriver.New()
+		if err := drv.Open(ctx, dsn); err != nil {
+			return nil, err
+		}
+		db, err := grove.Open(drv)
+		if err != nil {
+			return nil, err
+		}
+		return pgstore.New(db), nil
+	case "sqlite", "sqlite3":
+		drv := sqlitedriver.New()
+		if err := drv.Open(ctx, rest); err != nil {
+			return nil, err
+		}
+		db, err := grove.Open(drv)
+		if err != nil {
+			return nil, err
+		}
+		return sqlitestore.New(db), nil
+	case "mongodb", "mongodb+srv":
+		drv := mongodriver.New()
+		if err := drv.Open(ctx, dsn); err != nil {
+			return nil, err
+		}
+		db, err := grove.Open(drv)
+		if err != nil {
+			return nil, err
+		}
+		return mongostore.New(db), nil
+	case "redis", "rediss":
+		rdb := redisdriver.New()
+		if err := rdb.Open(ctx, dsn); err != nil {
+			return nil, err
+		}
+		kvs, err := kv.Open(rdb)
+		if err != nil {
+			return nil, err
+		}
+		return redisstore.New(kvs), nil
+	default:
+		return nil, fmt.Errorf("unsupported store scheme %q: use postgres://, sqlite://, mongodb:// or redis://", scheme)
+	}
+}
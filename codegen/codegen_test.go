@@ -0,0 +1,131 @@
+package codegen_test
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+
+	"github.com/xraph/relay/catalog"
+	"github.com/xraph/relay/codegen"
+)
+
+var defs = []catalog.WebhookDefinition{
+	{
+		Name:        "invoice.created",
+		Description: "An invoice was created.",
+		Version:     "2024-01-01",
+		Schema:      json.RawMessage(`{"type":"object","properties":{"id":{"type":"string"}}}`),
+	},
+	{
+		Name:        "invoice.created",
+		Description: "An invoice was created.",
+		Version:     "2025-01-01",
+		Schema: json.RawMessage(`{
+			"type": "object",
+			"required": ["id", "amount", "status", "customer"],
+			"properties": {
+				"id": {"type": "string", "description": "Invoice ID."},
+				"amount": {"type": "integer"},
+				"status": {"type": "string", "enum": ["open", "paid"]},
+				"customer": {
+					"type": "object",
+					"required": ["id"],
+					"properties": {
+						"id": {"type": "string"},
+						"email": {"type": ["string", "null"]}
+					}
+				},
+				"shipping": {"type": "object", "properties": {"city": {"type": "string"}}},
+				"lines": {"type": "array", "items": {"type": "object", "properties": {"sku": {"type": "string"}}}},
+				"due_at": {"type": "string", "format": "date-time"},
+				"metadata": {"type": "object", "additionalProperties": {"type": "string"}},
+				"extra": {}
+			}
+		}`),
+	},
+	{Name: "user.deleted", Version: "2025-01-01"},
+}
+
+func TestGo(t *testing.T) {
+	src, err := codegen.Go(defs, codegen.GoOptions{Package: "events"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Ignore gofmt alignment.
+	out := strings.Join(strings.Fields(string(src)), " ")
+
+	for _, want := range []string{
+		"// Code generated by relay-gen. DO NOT EDIT.",
+		`EventInvoiceCreated = "invoice.created"`,
+		"type InvoiceCreated struct {",
+		"ID string `json:\"id\"`",
+		"Amount int64 `json:\"amount\"`",
+		"Status InvoiceCreatedStatus `json:\"status\"`",
+		"Customer InvoiceCreatedCustomer `json:\"customer\"`",
+		"Email *string `json:\"email,omitempty\"`",
+		"Shipping *InvoiceCreatedShipping `json:\"shipping,omitempty\"`",
+		"Lines []InvoiceCreatedLinesItem `json:\"lines,omitempty\"`",
+		"DueAt *time.Time `json:\"due_at,omitempty\"`",
+		"Metadata map[string]string `json:\"metadata,omitempty\"`",
+		"Extra any `json:\"extra,omitempty\"`",
+		`InvoiceCreatedStatusPaid InvoiceCreatedStatus = "paid"`,
+		"type UserDeleted struct{}",
+		"func (s *Sender) SendInvoiceCreated(ctx context.Context, tenantID string, data InvoiceCreated, opts ...func(*event.Event)) error {",
+		`Version: "2025-01-01",`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, src)
+		}
+	}
+}
+
+// TestGoCompiles type-checks the generated payload types. The Sender is
+// left out since it imports this module.
+func TestGoCompiles(t *testing.T) {
+	src, err := codegen.Go(defs, codegen.GoOptions{Package: "events", NoSender: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "events_gen.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatalf("parse: %v\n%s", err, src)
+	}
+	conf := types.Config{Importer: importer.Default()}
+	if _, err := conf.Check("events", fset, []*ast.File{f}, nil); err != nil {
+		t.Fatalf("type check: %v\n%s", err, src)
+	}
+}
+
+func TestTypeScript(t *testing.T) {
+	src, err := codegen.TypeScript(defs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(src)
+
+	for _, want := range []string{
+		"export interface InvoiceCreated {",
+		"  /** Invoice ID. */\n  id: string;",
+		"  status: InvoiceCreatedStatus;",
+		"  email?: string | null;",
+		"  lines?: InvoiceCreatedLinesItem[];",
+		"  metadata?: Record<string, string>;",
+		"  extra?: unknown;",
+		`export type InvoiceCreatedStatus = "open" | "paid";`,
+		"export interface UserDeleted {\n}",
+		"export type EventType =\n  | \"invoice.created\"\n  | \"user.deleted\";",
+		`  "invoice.created": InvoiceCreated;`,
+		`  "invoice.created": "2025-01-01",`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %q in:\n%s", want, out)
+		}
+	}
+}
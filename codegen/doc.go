@@ -0,0 +1,4 @@
+// Package codegen generates typed payload code from catalog definitions: Go
+// structs with typed Send helpers for producers, and TypeScript types for
+// consumers. The relay-gen command wraps it for go generate.
+package codegen
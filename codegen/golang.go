@@ -0,0 +1,274 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+
+	"github.com/xraph/relay/catalog"
+)
+
+// GoOptions configures Go generation.
+type GoOptions struct {
+	// Package is the package name of the generated file. Defaults to "events".
+	Package string
+
+	// NoSender omits the Sender type and its Send helpers, leaving only the
+	// payload types. Use it for packages that must not import relay.
+	NoSender bool
+}
+
+// Go generates a Go source file with a payload struct for the latest
+// version of each event type, and unless NoSender is set, a Sender with a
+// typed Send helper per event type. The output is gofmt-ed.
+func Go(defs []catalog.WebhookDefinition, opts GoOptions) ([]byte, error) {
+	m, err := buildModel(defs)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Package == "" {
+		opts.Package = "events"
+	}
+
+	g := &goWriter{}
+	g.line("// Code generated by relay-gen. DO NOT EDIT.")
+	g.line("")
+	g.line("package %s", opts.Package)
+	g.line("")
+	g.imports(m, opts)
+
+	if len(m.payloads) > 0 {
+		g.line("// Event type names.")
+		g.line("const (")
+		for _, p := range m.payloads {
+			g.line("Event%s = %s", p.typeName, strconv.Quote(p.eventType))
+		}
+		g.line(")")
+		g.line("")
+	}
+
+	for _, p := range m.payloads {
+		for i, nt := range p.types {
+			if i == 0 {
+				g.rootDoc(p)
+			} else {
+				g.doc(nt.name, nt.doc)
+			}
+			if nt.enum != nil {
+				g.enum(nt)
+			} else {
+				g.structType(nt)
+			}
+		}
+	}
+
+	if !opts.NoSender {
+		g.sender(m)
+	}
+
+	src, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("codegen: format: %w", err)
+	}
+	return src, nil
+}
+
+type goWriter struct {
+	buf bytes.Buffer
+}
+
+func (g *goWriter) line(format string, args ...any) {
+	fmt.Fprintf(&g.buf, format, args...)
+	g.buf.WriteByte('\n')
+}
+
+func (g *goWriter) imports(m *model, opts GoOptions) {
+	var std, ext []string
+	if m.usesTime() {
+		std = append(std, "time")
+	}
+	if !opts.NoSender && len(m.payloads) > 0 {
+		std = append([]string{"context"}, std...)
+		ext = []string{"github.com/xraph/relay", "github.com/xraph/relay/event"}
+	}
+	if len(std)+len(ext) == 0 {
+		return
+	}
+
+	g.line("import (")
+	for _, p := range std {
+		g.line("%s", strconv.Quote(p))
+	}
+	if len(std) > 0 && len(ext) > 0 {
+		g.line("")
+	}
+	for _, p := range ext {
+		g.line("%s", strconv.Quote(p))
+	}
+	g.line(")")
+	g.line("")
+}
+
+func (g *goWriter) rootDoc(p *payload) {
+	g.line("// %s is the payload of %s events, version %s.", p.typeName, p.eventType, p.version)
+	if p.description != "" {
+		g.line("//")
+		g.comment(p.description)
+	}
+}
+
+func (g *goWriter) doc(name, doc string) {
+	if doc == "" {
+		return
+	}
+	g.comment(name + ": " + doc)
+}
+
+func (g *goWriter) comment(text string) {
+	for _, l := range strings.Split(strings.TrimSpace(text), "\n") {
+		g.line("// %s", strings.TrimSpace(l))
+	}
+}
+
+func (g *goWriter) enum(nt *namedType) {
+	g.line("type %s string", nt.name)
+	g.line("")
+	g.line("// %s values.", nt.name)
+	g.line("const (")
+	seen := make(map[string]bool)
+	for _, v := range nt.enum {
+		name := nt.name + exportName(v)
+		if v == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		g.line("%s %s = %s", name, nt.name, strconv.Quote(v))
+	}
+	g.line(")")
+	g.line("")
+}
+
+func (g *goWriter) structType(nt *namedType) {
+	if len(nt.fields) == 0 {
+		g.line("type %s struct{}", nt.name)
+		g.line("")
+		return
+	}
+	g.line("type %s struct {", nt.name)
+	for i, f := range nt.fields {
+		if f.doc != "" {
+			if i > 0 {
+				g.line("")
+			}
+			g.comment(f.doc)
+		}
+		tag := f.jsonName
+		if !f.required {
+			tag += ",omitempty"
+		}
+		g.line("%s %s `json:%s`", f.name, goFieldType(f), strconv.Quote(tag))
+	}
+	g.line("}")
+	g.line("")
+}
+
+func (g *goWriter) sender(m *model) {
+	if len(m.payloads) == 0 {
+		return
+	}
+
+	g.line("// Sender sends the generated event types through a Relay.")
+	g.line("type Sender struct {")
+	g.line("relay *relay.Relay")
+	g.line("}")
+	g.line("")
+	g.line("// NewSender returns a Sender that sends through r.")
+	g.line("func NewSender(r *relay.Relay) *Sender {")
+	g.line("return &Sender{relay: r}")
+	g.line("}")
+	g.line("")
+
+	for _, p := range m.payloads {
+		g.line("// Send%s sends %s, version %s, for tenantID.", p.typeName, p.eventType, p.version)
+		g.line("// Options such as an idempotency key or scope are set with opts.")
+		g.line("func (s *Sender) Send%s(ctx context.Context, tenantID string, data %s, opts ...func(*event.Event)) error {", p.typeName, p.typeName)
+		g.line("evt := &event.Event{")
+		g.line("Type: Event%s,", p.typeName)
+		g.line("TenantID: tenantID,")
+		g.line("Version: %s,", strconv.Quote(p.version))
+		g.line("Data: data,")
+		g.line("}")
+		g.line("for _, opt := range opts {")
+		g.line("opt(evt)")
+		g.line("}")
+		g.line("return s.relay.Send(ctx, evt)")
+		g.line("}")
+		g.line("")
+	}
+}
+
+// goFieldType is the Go type of a struct field. Nullable fields and
+// optional structs are pointers, so that absent and zero differ.
+func goFieldType(f field) string {
+	t := goType(f.ref)
+	switch {
+	case f.ref.kind == kindAny, f.ref.kind == kindArray, f.ref.kind == kindMap:
+		return t
+	case f.ref.nullable, f.ref.kind == kindNamed && !f.required && !f.ref.enum, f.ref.kind == kindTime && !f.required:
+		return "*" + t
+	default:
+		return t
+	}
+}
+
+func goType(r typeRef) string {
+	switch r.kind {
+	case kindString:
+		return "string"
+	case kindTime:
+		return "time.Time"
+	case kindInteger:
+		return "int64"
+	case kindNumber:
+		return "float64"
+	case kindBool:
+		return "bool"
+	case kindArray:
+		return "[]" + goElemType(*r.elem)
+	case kindMap:
+		return "map[string]" + goElemType(*r.elem)
+	case kindNamed:
+		return r.name
+	default:
+		return "any"
+	}
+}
+
+func goElemType(r typeRef) string {
+	if r.nullable && r.kind != kindAny && r.kind != kindArray && r.kind != kindMap {
+		return "*" + goType(r)
+	}
+	return goType(r)
+}
+
+func (m *model) usesTime() bool {
+	var uses func(r typeRef) bool
+	uses = func(r typeRef) bool {
+		if r.kind == kindTime {
+			return true
+		}
+		return r.elem != nil && uses(*r.elem)
+	}
+	for _, p := range m.payloads {
+		for _, nt := range p.types {
+			for _, f := range nt.fields {
+				if uses(f.ref) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
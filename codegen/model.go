@@ -0,0 +1,287 @@
+package codegen
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/xraph/relay/catalog"
+)
+
+// kind is the shape of a typeRef.
+type kind int
+
+const (
+	kindAny kind = iota
+	kindString
+	kindTime
+	kindInteger
+	kindNumber
+	kindBool
+	kindArray
+	kindMap
+	kindNamed
+)
+
+// typeRef is a use of a type in a field, array or map.
+type typeRef struct {
+	kind     kind
+	elem     *typeRef // kindArray, kindMap
+	name     string   // kindNamed
+	enum     bool     // kindNamed string enum
+	nullable bool
+}
+
+// namedType is a declared type: a struct, or a string enum.
+type namedType struct {
+	name   string
+	doc    string
+	fields []field  // structs
+	enum   []string // enums
+}
+
+type field struct {
+	jsonName string
+	name     string
+	doc      string
+	ref      typeRef
+	required bool
+}
+
+// payload is the generated description of one event type.
+type payload struct {
+	eventType   string
+	version     string
+	description string
+	typeName    string // root payload type
+	types       []*namedType
+}
+
+// model is everything generated from a set of definitions.
+type model struct {
+	payloads []*payload
+	names    map[string]bool
+}
+
+// buildModel converts the latest version of each definition into types.
+func buildModel(defs []catalog.WebhookDefinition) (*model, error) {
+	m := &model{names: make(map[string]bool)}
+
+	for _, def := range latest(defs) {
+		p := &payload{
+			eventType:   def.Name,
+			version:     def.Version,
+			description: def.Description,
+			typeName:    m.unique(exportName(def.Name)),
+		}
+
+		var schema map[string]any
+		if len(def.Schema) > 0 {
+			if err := json.Unmarshal(def.Schema, &schema); err != nil {
+				return nil, fmt.Errorf("codegen: %s schema: %w", def.Name, err)
+			}
+		}
+
+		root := &namedType{name: p.typeName, doc: def.Description}
+		p.types = append(p.types, root)
+		if props, ok := schema["properties"].(map[string]any); ok {
+			root.fields = m.fields(p, p.typeName, props, schema["required"])
+		}
+		m.payloads = append(m.payloads, p)
+	}
+	return m, nil
+}
+
+// latest picks the highest version of each event type, sorted by name.
+func latest(defs []catalog.WebhookDefinition) []catalog.WebhookDefinition {
+	byName := make(map[string]catalog.WebhookDefinition, len(defs))
+	for _, def := range defs {
+		if cur, ok := byName[def.Name]; !ok || def.Version > cur.Version {
+			byName[def.Name] = def
+		}
+	}
+	out := make([]catalog.WebhookDefinition, 0, len(byName))
+	for _, def := range byName {
+		out = append(out, def)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func (m *model) fields(p *payload, owner string, props map[string]any, required any) []field {
+	req := make(map[string]bool)
+	if list, ok := required.([]any); ok {
+		for _, r := range list {
+			if s, ok := r.(string); ok {
+				req[s] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fields := make([]field, 0, len(names))
+	for _, name := range names {
+		schema, _ := props[name].(map[string]any)
+		goName := exportName(name)
+		f := field{
+			jsonName: name,
+			name:     goName,
+			required: req[name],
+			ref:      m.ref(p, owner+goName, schema),
+		}
+		f.doc, _ = schema["description"].(string)
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// ref maps a schema to a type, declaring named types for nested objects
+// and string enums. name is the name such a type gets.
+func (m *model) ref(p *payload, name string, schema map[string]any) typeRef {
+	if schema == nil {
+		return typeRef{kind: kindAny}
+	}
+
+	types, nullable := schemaTypes(schema["type"])
+	if len(types) != 1 {
+		return typeRef{kind: kindAny}
+	}
+	doc, _ := schema["description"].(string)
+
+	var r typeRef
+	switch types[0] {
+	case "string":
+		r.kind = kindString
+		if format, _ := schema["format"].(string); format == "date-time" {
+			r.kind = kindTime
+		}
+		if values := stringEnum(schema["enum"]); len(values) > 0 {
+			nt := &namedType{name: m.unique(name), doc: doc, enum: values}
+			p.types = append(p.types, nt)
+			r = typeRef{kind: kindNamed, name: nt.name, enum: true}
+		}
+	case "integer":
+		r.kind = kindInteger
+	case "number":
+		r.kind = kindNumber
+	case "boolean":
+		r.kind = kindBool
+	case "array":
+		items, _ := schema["items"].(map[string]any)
+		elem := m.ref(p, name+"Item", items)
+		r = typeRef{kind: kindArray, elem: &elem}
+	case "object":
+		if props, ok := schema["properties"].(map[string]any); ok && len(props) > 0 {
+			nt := &namedType{name: m.unique(name), doc: doc}
+			p.types = append(p.types, nt)
+			nt.fields = m.fields(p, nt.name, props, schema["required"])
+			r = typeRef{kind: kindNamed, name: nt.name}
+			break
+		}
+		elem := typeRef{kind: kindAny}
+		if extra, ok := schema["additionalProperties"].(map[string]any); ok {
+			elem = m.ref(p, name+"Value", extra)
+		}
+		r = typeRef{kind: kindMap, elem: &elem}
+	default:
+		r.kind = kindAny
+	}
+	r.nullable = nullable
+	return r
+}
+
+// schemaTypes returns the non-null types of a "type" keyword and whether
+// null is allowed.
+func schemaTypes(v any) ([]string, bool) {
+	var all []string
+	switch t := v.(type) {
+	case string:
+		all = []string{t}
+	case []any:
+		for _, s := range t {
+			if str, ok := s.(string); ok {
+				all = append(all, str)
+			}
+		}
+	}
+
+	var types []string
+	nullable := false
+	for _, t := range all {
+		if t == "null" {
+			nullable = true
+			continue
+		}
+		types = append(types, t)
+	}
+	return types, nullable
+}
+
+func stringEnum(v any) []string {
+	list, ok := v.([]any)
+	if !ok {
+		return nil
+	}
+	values := make([]string, 0, len(list))
+	for _, e := range list {
+		s, ok := e.(string)
+		if !ok {
+			return nil
+		}
+		values = append(values, s)
+	}
+	return values
+}
+
+// unique returns name, or name with a numeric suffix if it is taken.
+func (m *model) unique(name string) string {
+	candidate := name
+	for i := 2; m.names[candidate]; i++ {
+		candidate = name + strconv.Itoa(i)
+	}
+	m.names[candidate] = true
+	return candidate
+}
+
+// initialisms are written in upper case in Go names, as golint expects.
+var initialisms = map[string]bool{
+	"API": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true,
+	"IP": true, "JSON": true, "SQL": true, "TTL": true, "URI": true,
+	"URL": true, "UUID": true, "XML": true,
+}
+
+// exportName converts an event type or property name such as
+// "invoice.created" or "customer_id" to an exported identifier.
+func exportName(s string) string {
+	parts := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var b strings.Builder
+	for _, part := range parts {
+		if upper := strings.ToUpper(part); initialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+
+	name := b.String()
+	if name == "" {
+		return "Field"
+	}
+	if unicode.IsDigit([]rune(name)[0]) {
+		return "X" + name
+	}
+	return name
+}
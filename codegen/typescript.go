@@ -0,0 +1,152 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/xraph/relay/catalog"
+)
+
+// TypeScript generates a TypeScript module with an interface for the latest
+// version of each event type, an EventType union of the names, and an
+// EventPayloads map from name to payload, so that consumers can write
+// handlers as (payload: EventPayloads[T]) for a delivered type T.
+func TypeScript(defs []catalog.WebhookDefinition) ([]byte, error) {
+	m, err := buildModel(defs)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	line := func(format string, args ...any) {
+		fmt.Fprintf(&b, format, args...)
+		b.WriteByte('\n')
+	}
+	comment := func(indent, text string) {
+		lines := strings.Split(strings.TrimSpace(text), "\n")
+		if len(lines) == 1 {
+			line("%s/** %s */", indent, strings.TrimSpace(lines[0]))
+			return
+		}
+		line("%s/**", indent)
+		for _, l := range lines {
+			line("%s", strings.TrimRight(indent+" * "+strings.TrimSpace(l), " "))
+		}
+		line("%s */", indent)
+	}
+
+	line("// Code generated by relay-gen. DO NOT EDIT.")
+
+	for _, p := range m.payloads {
+		for i, nt := range p.types {
+			line("")
+			doc := nt.doc
+			if i == 0 {
+				doc = fmt.Sprintf("Payload of %s events, version %s.", p.eventType, p.version)
+				if p.description != "" {
+					doc += "\n\n" + p.description
+				}
+			}
+			if doc != "" {
+				comment("", doc)
+			}
+
+			if nt.enum != nil {
+				values := make([]string, len(nt.enum))
+				for i, v := range nt.enum {
+					values[i] = strconv.Quote(v)
+				}
+				line("export type %s = %s;", nt.name, strings.Join(values, " | "))
+				continue
+			}
+
+			line("export interface %s {", nt.name)
+			for _, f := range nt.fields {
+				if f.doc != "" {
+					comment("  ", f.doc)
+				}
+				optional := ""
+				if !f.required {
+					optional = "?"
+				}
+				line("  %s%s: %s;", tsKey(f.jsonName), optional, tsType(f.ref))
+			}
+			line("}")
+		}
+	}
+
+	line("")
+	if len(m.payloads) == 0 {
+		line("export type EventType = never;")
+		line("")
+		line("export type EventPayloads = Record<never, never>;")
+		return b.Bytes(), nil
+	}
+
+	line("export type EventType =")
+	for i, p := range m.payloads {
+		end := ""
+		if i == len(m.payloads)-1 {
+			end = ";"
+		}
+		line("  | %s%s", strconv.Quote(p.eventType), end)
+	}
+	line("")
+	line("export interface EventPayloads {")
+	for _, p := range m.payloads {
+		line("  %s: %s;", strconv.Quote(p.eventType), p.typeName)
+	}
+	line("}")
+	line("")
+	line("/** Versions the types were generated from. */")
+	line("export const EventVersions: Record<EventType, string> = {")
+	for _, p := range m.payloads {
+		line("  %s: %s,", strconv.Quote(p.eventType), strconv.Quote(p.version))
+	}
+	line("};")
+	return b.Bytes(), nil
+}
+
+func tsType(r typeRef) string {
+	var t string
+	switch r.kind {
+	case kindString, kindTime:
+		t = "string"
+	case kindInteger, kindNumber:
+		t = "number"
+	case kindBool:
+		t = "boolean"
+	case kindArray:
+		elem := tsType(*r.elem)
+		if strings.Contains(elem, " ") {
+			elem = "(" + elem + ")"
+		}
+		t = elem + "[]"
+	case kindMap:
+		t = "Record<string, " + tsType(*r.elem) + ">"
+	case kindNamed:
+		t = r.name
+	default:
+		t = "unknown"
+	}
+	if r.nullable && r.kind != kindAny {
+		t += " | null"
+	}
+	return t
+}
+
+// tsKey quotes property names that are not valid identifiers.
+func tsKey(name string) string {
+	for i, r := range name {
+		ok := r == '_' || r == '$' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || i > 0 && r >= '0' && r <= '9'
+		if !ok {
+			return strconv.Quote(name)
+		}
+	}
+	if name == "" {
+		return `""`
+	}
+	return name
+}
@@ -1,6 +1,11 @@
 package relay
 
-import "time"
+import (
+	"time"
+
+	"github.com/xraph/relay/catalog"
+	"github.com/xraph/relay/health"
+)
 
 // Config holds the configuration for a Relay instance.
 type Config struct {
@@ -24,6 +29,11 @@ type Config struct {
 	// MaxRetries is the global default for maximum delivery attempts.
 	MaxRetries int
 
+	// MaxReplays is how many times a failed delivery can be replayed from
+	// the DLQ, counting replays of its replays. Zero or less removes the
+	// limit.
+	MaxReplays int
+
 	// RetrySchedule defines the backoff intervals between retry attempts.
 	RetrySchedule []time.Duration
 
@@ -33,6 +43,33 @@ type Config struct {
 	// CacheTTL is the TTL for the catalog's in-memory event type cache.
 	// Set to 0 to disable caching.
 	CacheTTL time.Duration
+
+	// SchemaCompatibility decides what RegisterEventType does when a
+	// definition makes a breaking change to an existing version's schema:
+	// "warn" (the default), "reject" or "allow".
+	SchemaCompatibility catalog.CompatPolicy
+
+	// Tunnel enables the development tunnel, which forwards deliveries of
+	// tunnel:// endpoints to a connected "relay listen". Leave it off in
+	// production.
+	Tunnel bool
+
+	// VerifyEndpoints requires endpoints to echo a signed challenge before
+	// they are enabled, on creation and whenever their URL changes.
+	VerifyEndpoints bool
+
+	// HealthPolicy automatically disables endpoints that keep failing, with
+	// the reason recorded on the endpoint. The zero policy never disables.
+	HealthPolicy health.Policy
+
+	// ProbeInterval is how often endpoints disabled by HealthPolicy are
+	// probed; one that answers with a 2xx is re-enabled.
+	ProbeInterval time.Duration
+
+	// SystemEvents makes Relay emit relay.endpoint.disabled,
+	// relay.endpoint.failing and relay.dlq.threshold_exceeded events to the
+	// tenant concerned.
+	SystemEvents SystemEventsConfig
 }
 
 // DefaultRetrySchedule defines the default exponential backoff intervals.
@@ -53,8 +90,10 @@ func DefaultConfig() Config {
 		BatchSize:       50,
 		RequestTimeout:  30 * time.Second,
 		MaxRetries:      5,
+		MaxReplays:      3,
 		RetrySchedule:   DefaultRetrySchedule,
 		ShutdownTimeout: 30 * time.Second,
 		CacheTTL:        30 * time.Second,
+		ProbeInterval:   5 * time.Minute,
 	}
 }
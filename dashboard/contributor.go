@@ -169,12 +169,36 @@ func (c *Contributor) renderEventTypeDetail(ctx context.Context, params contribu
 		return nil, contributor.ErrPageNotFound
 	}
 
+	// Handle actions.
+	switch params.QueryParams["action"] {
+	case "deprecate":
+		sunset, parseErr := time.Parse(time.DateOnly, params.QueryParams["sunset"])
+		if parseErr != nil {
+			return nil, fmt.Errorf("dashboard: deprecate event type: invalid sunset date: %w", parseErr)
+		}
+		if _, depErr := c.r.Catalog().Deprecate(ctx, name, sunset); depErr != nil {
+			return nil, fmt.Errorf("dashboard: deprecate event type: %w", depErr)
+		}
+	case "undeprecate":
+		if _, undepErr := c.r.Catalog().Undeprecate(ctx, name); undepErr != nil {
+			return nil, fmt.Errorf("dashboard: undeprecate event type: %w", undepErr)
+		}
+	}
+
 	et, err := c.r.Catalog().GetType(ctx, name)
 	if err != nil {
 		return nil, fmt.Errorf("dashboard: resolve event type: %w", err)
 	}
 
-	return pages.EventTypeDetailPage(et), nil
+	subscribers, err := c.r.Endpoints().Subscribers(ctx, name)
+	if err != nil {
+		subscribers = nil
+	}
+
+	return pages.EventTypeDetailPage(pages.EventTypeDetailData{
+		EventType:   et,
+		Subscribers: subscribers,
+	}), nil
 }
 
 func (c *Contributor) renderEndpoints(ctx context.Context, params contributor.Params) (templ.Component, error) {
@@ -289,8 +313,19 @@ func (c *Contributor) renderEndpointDetail(ctx context.Context, params contribut
 	}
 
 	// Handle actions.
+	var data pages.EndpointDetailData
 	if action := params.QueryParams["action"]; action != "" {
 		switch action {
+		case "send_test":
+			res, testErr := c.r.SendTest(ctx, epID, params.QueryParams["event_type"], nil)
+			if testErr != nil {
+				data.TestError = testErr.Error()
+			}
+			data.TestResult = res
+		case "verify":
+			if _, verifyErr := c.r.Endpoints().Verify(ctx, epID); verifyErr != nil {
+				data.VerifyError = verifyErr.Error()
+			}
 		case "enable":
 			if setErr := c.r.Endpoints().SetEnabled(ctx, epID, true); setErr != nil {
 				return nil, fmt.Errorf("dashboard: enable endpoint: %w", setErr)
@@ -316,10 +351,14 @@ func (c *Contributor) renderEndpointDetail(ctx context.Context, params contribut
 		deliveries = nil
 	}
 
-	return pages.EndpointDetailPage(pages.EndpointDetailData{
-		Endpoint:   ep,
-		Deliveries: deliveries,
-	}), nil
+	data.Endpoint = ep
+	data.Deliveries = deliveries
+	data.TestEventTypes = subscribedEventTypes(ctx, c.r, ep)
+	if eh, healthErr := c.r.EndpointHealth(ctx, epID); healthErr == nil {
+		data.Health = eh
+	}
+
+	return pages.EndpointDetailPage(data), nil
 }
 
 func (c *Contributor) renderEvents(ctx context.Context, params contributor.Params) (templ.Component, error) {
@@ -428,13 +467,9 @@ func (c *Contributor) renderDeliveryDetail(ctx context.Context, params contribut
 }
 
 func (c *Contributor) renderDLQ(ctx context.Context, params contributor.Params) (templ.Component, error) {
-	// Handle bulk replay action.
+	// Replay every entry not yet replayed in the background.
 	if params.QueryParams["action"] == "replay_all" {
-		now := time.Now()
-		past := now.Add(-365 * 24 * time.Hour)
-		if _, err := c.r.DLQ().ReplayBulk(ctx, past, now); err != nil {
-			return nil, fmt.Errorf("dashboard: replay bulk: %w", err)
-		}
+		c.r.ReplayDLQ(ctx, relay.DLQReplayOptions{})
 	}
 
 	entries, err := fetchDLQEntries(ctx, c.r, dlq.ListOpts{Limit: 50})
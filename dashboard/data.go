@@ -30,9 +30,9 @@ func fetchPendingCount(ctx context.Context, r *relay.Relay) int64 {
 	return count
 }
 
-// fetchDLQCount returns the total number of DLQ entries.
+// fetchDLQCount returns the number of DLQ entries not replayed yet.
 func fetchDLQCount(ctx context.Context, r *relay.Relay) int64 {
-	count, err := r.Store().CountDLQ(ctx)
+	count, err := r.Store().CountDLQ(ctx, dlq.ListOpts{Unreplayed: true})
 	if err != nil {
 		return 0
 	}
@@ -74,3 +74,26 @@ func fetchDeliveriesByEvent(ctx context.Context, r *relay.Relay, evtID id.ID) ([
 func fetchDLQEntries(ctx context.Context, r *relay.Relay, opts dlq.ListOpts) ([]*dlq.Entry, error) {
 	return r.Store().ListDLQ(ctx, opts)
 }
+
+// subscribedEventTypes returns the names of the event types ep subscribes
+// to that have an example payload, for the test send form.
+func subscribedEventTypes(ctx context.Context, r *relay.Relay, ep *endpoint.Endpoint) []string {
+	types, err := r.Catalog().ListTypes(ctx, catalog.ListOpts{Limit: 1000})
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	for _, et := range types {
+		if len(et.Definition.Example) == 0 {
+			continue
+		}
+		for _, pattern := range ep.EventTypes {
+			if catalog.Match(pattern, et.Definition.Name) {
+				names = append(names, et.Definition.Name)
+				break
+			}
+		}
+	}
+	return names
+}
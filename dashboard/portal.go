@@ -0,0 +1,395 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/a-h/templ"
+
+	"github.com/xraph/relay"
+	"github.com/xraph/relay/auth"
+	"github.com/xraph/relay/catalog"
+	"github.com/xraph/relay/dashboard/pages"
+	"github.com/xraph/relay/delivery"
+	"github.com/xraph/relay/dlq"
+	"github.com/xraph/relay/endpoint"
+	"github.com/xraph/relay/event"
+	"github.com/xraph/relay/id"
+	"github.com/xraph/relay/portal"
+)
+
+// PortalCookie is the cookie the portal page keeps its token in.
+const PortalCookie = "relay_portal"
+
+// errPortalNotFound is returned for pages and records outside the tenant.
+var errPortalNotFound = errors.New("dashboard: not found")
+
+// Portal is a ready-made tenant portal page built from the dashboard
+// components. It shows a single tenant its endpoints, events, deliveries
+// and failed deliveries, and lets it add endpoints, send tests, rotate
+// secrets and replay failures.
+//
+// Link users to the portal with a token from portal.Signer.Issue in the
+// token query parameter, e.g. "/webhooks?token=rpt_...". The portal keeps
+// the token in a cookie until it expires. Mount the portal with
+// http.StripPrefix so it can work out the path it is served at.
+type Portal struct {
+	r      *relay.Relay
+	signer *portal.Signer
+}
+
+// NewPortal creates a portal page for r accepting tokens from signer.
+func NewPortal(r *relay.Relay, signer *portal.Signer) *Portal {
+	return &Portal{r: r, signer: signer}
+}
+
+// ServeHTTP implements http.Handler.
+func (p *Portal) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	base := portalBase(req)
+
+	// Exchange a link's token for a cookie, keeping it out of the history.
+	if token := req.URL.Query().Get("token"); token != "" {
+		claims, err := p.signer.Verify(token)
+		if err != nil {
+			p.write(w, req, base, http.StatusUnauthorized, pages.PortalError("This link is invalid or has expired."))
+			return
+		}
+		http.SetCookie(w, &http.Cookie{
+			Name:     PortalCookie,
+			Value:    token,
+			Path:     base + "/",
+			Expires:  claims.ExpiresAt,
+			HttpOnly: true,
+			Secure:   req.TLS != nil,
+			SameSite: http.SameSiteLaxMode,
+		})
+		http.Redirect(w, req, base+"/endpoints", http.StatusSeeOther)
+		return
+	}
+
+	claims, err := p.signer.Verify(portalToken(req))
+	if err != nil {
+		p.write(w, req, base, http.StatusUnauthorized, pages.PortalError("Your session has expired. Open the portal again from the application."))
+		return
+	}
+	ctx := auth.NewContext(req.Context(), claims.Principal())
+
+	page, err := p.render(ctx, req, base, claims.TenantID)
+	switch {
+	case errors.Is(err, errPortalNotFound):
+		p.write(w, req, base, http.StatusNotFound, pages.PortalError("Not found."))
+	case err != nil:
+		p.write(w, req, base, http.StatusInternalServerError, pages.PortalError(err.Error()))
+	case page == nil:
+		http.Redirect(w, req, base+"/endpoints", http.StatusSeeOther)
+	default:
+		p.write(w, req, base, http.StatusOK, page)
+	}
+}
+
+// write renders c, wrapped in the portal document unless htmx asked for a
+// fragment.
+func (p *Portal) write(w http.ResponseWriter, req *http.Request, base string, status int, c templ.Component) {
+	if !isHTMX(req) {
+		c = pages.PortalShell(base, c)
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	_ = c.Render(req.Context(), w) //nolint:errcheck // headers are already sent.
+}
+
+// render returns the page for req, or nil to redirect to the endpoints.
+func (p *Portal) render(ctx context.Context, req *http.Request, base, tenantID string) (templ.Component, error) {
+	route := strings.TrimSuffix(req.URL.Path, "/")
+
+	// The shared list pages filter and act through "." links, which
+	// resolve to the portal root; serve the page the browser is on.
+	if route == "" && isHTMX(req) {
+		if u, err := url.Parse(req.Header.Get("HX-Current-URL")); err == nil {
+			route = strings.TrimPrefix(u.Path, base)
+		}
+	}
+
+	// Only run actions for htmx requests: they carry a header a cross-site
+	// link or form cannot set.
+	act := ""
+	if isHTMX(req) {
+		act = req.URL.Query().Get("action")
+	}
+
+	switch route {
+	case "":
+		return nil, nil
+	case "/endpoints":
+		if req.Method == http.MethodPost && isHTMX(req) {
+			return p.createEndpoint(ctx, req, base, tenantID)
+		}
+		return p.renderEndpoints(ctx, base, tenantID, "")
+	case "/endpoints/detail":
+		return p.renderEndpointDetail(ctx, req, act)
+	case "/events":
+		return p.renderEvents(ctx, req, tenantID)
+	case "/events/detail":
+		return p.renderEventDetail(ctx, req)
+	case "/deliveries":
+		return p.renderDeliveries(ctx, req, tenantID)
+	case "/deliveries/detail":
+		return p.renderDeliveryDetail(ctx, req)
+	case "/dlq":
+		return p.renderDLQ(ctx, tenantID, act)
+	case "/dlq/detail":
+		return p.renderDLQDetail(ctx, req, act)
+	default:
+		return nil, errPortalNotFound
+	}
+}
+
+func (p *Portal) renderEndpoints(ctx context.Context, base, tenantID, formErr string) (templ.Component, error) {
+	eps, err := fetchEndpoints(ctx, p.r, tenantID, endpoint.ListOpts{Limit: 100})
+	if err != nil {
+		return nil, err
+	}
+
+	eventTypes, err := fetchEventTypes(ctx, p.r, catalog.ListOpts{Limit: 200})
+	if err != nil {
+		eventTypes = nil
+	}
+
+	return pages.PortalEndpointsPage(pages.PortalEndpointsData{
+		Base:       base,
+		Endpoints:  eps,
+		EventTypes: eventTypes,
+		Error:      formErr,
+	}), nil
+}
+
+func (p *Portal) createEndpoint(ctx context.Context, req *http.Request, base, tenantID string) (templ.Component, error) {
+	if err := req.ParseForm(); err != nil {
+		return p.renderEndpoints(ctx, base, tenantID, err.Error())
+	}
+
+	_, err := p.r.Endpoints().Create(ctx, endpoint.Input{
+		TenantID:    tenantID,
+		URL:         strings.TrimSpace(req.PostForm.Get("url")),
+		Description: strings.TrimSpace(req.PostForm.Get("description")),
+		EventTypes:  req.PostForm["event_types"],
+	})
+	if err != nil {
+		return p.renderEndpoints(ctx, base, tenantID, err.Error())
+	}
+
+	return p.renderEndpoints(ctx, base, tenantID, "")
+}
+
+func (p *Portal) renderEndpointDetail(ctx context.Context, req *http.Request, act string) (templ.Component, error) {
+	epID, err := queryID(req)
+	if err != nil {
+		return nil, err
+	}
+	ep, err := p.r.Endpoints().Get(ctx, epID)
+	if err != nil || !auth.CanAccess(ctx, ep.TenantID) {
+		return nil, errPortalNotFound
+	}
+
+	var data pages.EndpointDetailData
+	switch act {
+	case "send_test":
+		res, testErr := p.r.SendTest(ctx, epID, req.URL.Query().Get("event_type"), nil)
+		if testErr != nil {
+			data.TestError = testErr.Error()
+		}
+		data.TestResult = res
+	case "verify":
+		if _, verifyErr := p.r.Endpoints().Verify(ctx, epID); verifyErr != nil {
+			data.VerifyError = verifyErr.Error()
+		}
+	case "enable", "disable":
+		if err := p.r.Endpoints().SetEnabled(ctx, epID, act == "enable"); err != nil {
+			return nil, err
+		}
+	case "rotate_secret":
+		if _, err := p.r.Endpoints().RotateSecret(ctx, epID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Actions change the endpoint; show it as it is now.
+	if act != "" {
+		if ep, err = p.r.Endpoints().Get(ctx, epID); err != nil {
+			return nil, err
+		}
+	}
+
+	deliveries, err := fetchDeliveriesByEndpoint(ctx, p.r, epID, delivery.ListOpts{Limit: 20})
+	if err != nil {
+		deliveries = nil
+	}
+
+	data.Endpoint = ep
+	data.Deliveries = deliveries
+	data.TestEventTypes = subscribedEventTypes(ctx, p.r, ep)
+	if eh, healthErr := p.r.EndpointHealth(ctx, epID); healthErr == nil {
+		data.Health = eh
+	}
+
+	return templ.Join(pages.PortalSecret(ep.Secret), pages.EndpointDetailPage(data)), nil
+}
+
+func (p *Portal) renderEvents(ctx context.Context, req *http.Request, tenantID string) (templ.Component, error) {
+	typeFilter := req.URL.Query().Get("type")
+
+	events, err := p.r.Store().ListEventsByTenant(ctx, tenantID, event.ListOpts{Limit: 50, Type: typeFilter})
+	if err != nil {
+		return nil, err
+	}
+
+	return pages.EventsPage(pages.EventsPageData{
+		Events:     events,
+		TypeFilter: typeFilter,
+	}), nil
+}
+
+func (p *Portal) renderEventDetail(ctx context.Context, req *http.Request) (templ.Component, error) {
+	evtID, err := queryID(req)
+	if err != nil {
+		return nil, err
+	}
+	evt, err := p.r.Store().GetEvent(ctx, evtID)
+	if err != nil || !auth.CanAccess(ctx, evt.TenantID) {
+		return nil, errPortalNotFound
+	}
+
+	deliveries, err := fetchDeliveriesByEvent(ctx, p.r, evtID)
+	if err != nil {
+		deliveries = nil
+	}
+
+	return pages.EventDetailPage(pages.EventDetailData{
+		Event:      evt,
+		Deliveries: deliveries,
+	}), nil
+}
+
+func (p *Portal) renderDeliveries(ctx context.Context, req *http.Request, tenantID string) (templ.Component, error) {
+	stateFilter := req.URL.Query().Get("state")
+
+	eps, err := fetchEndpoints(ctx, p.r, tenantID, endpoint.ListOpts{Limit: 100})
+	if err != nil {
+		return nil, err
+	}
+
+	opts := delivery.ListOpts{Limit: 50}
+	if stateFilter != "" {
+		state := delivery.State(stateFilter)
+		opts.State = &state
+	}
+
+	var deliveries []*delivery.Delivery
+	for _, ep := range eps {
+		dels, err := fetchDeliveriesByEndpoint(ctx, p.r, ep.ID, opts)
+		if err == nil {
+			deliveries = append(deliveries, dels...)
+		}
+		if len(deliveries) >= 50 {
+			deliveries = deliveries[:50]
+			break
+		}
+	}
+
+	return pages.DeliveriesPage(pages.DeliveriesPageData{
+		Deliveries:  deliveries,
+		StateFilter: stateFilter,
+	}), nil
+}
+
+func (p *Portal) renderDeliveryDetail(ctx context.Context, req *http.Request) (templ.Component, error) {
+	delID, err := queryID(req)
+	if err != nil {
+		return nil, err
+	}
+	d, err := p.r.Store().GetDelivery(ctx, delID)
+	if err != nil {
+		return nil, errPortalNotFound
+	}
+	ep, err := p.r.Endpoints().Get(ctx, d.EndpointID)
+	if err != nil || !auth.CanAccess(ctx, ep.TenantID) {
+		return nil, errPortalNotFound
+	}
+
+	return pages.DeliveryDetailPage(d), nil
+}
+
+func (p *Portal) renderDLQ(ctx context.Context, tenantID, act string) (templ.Component, error) {
+	opts := dlq.ListOpts{Limit: 50, TenantID: tenantID}
+
+	// Replay the tenant's entries not yet replayed in the background.
+	if act == "replay_all" {
+		p.r.ReplayDLQ(ctx, relay.DLQReplayOptions{TenantID: tenantID})
+	}
+
+	entries, err := fetchDLQEntries(ctx, p.r, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return pages.DLQPage(entries), nil
+}
+
+func (p *Portal) renderDLQDetail(ctx context.Context, req *http.Request, act string) (templ.Component, error) {
+	dlqID, err := queryID(req)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := p.r.DLQ().Get(ctx, dlqID)
+	if err != nil || !auth.CanAccess(ctx, entry.TenantID) {
+		return nil, errPortalNotFound
+	}
+
+	if act == "replay" {
+		if err := p.r.DLQ().Replay(ctx, dlqID); err != nil {
+			return nil, err
+		}
+		if entry, err = p.r.DLQ().Get(ctx, dlqID); err != nil {
+			return nil, err
+		}
+	}
+
+	return pages.DLQDetailPage(entry), nil
+}
+
+// portalBase returns the path the portal is mounted at: the part of the
+// request path that http.StripPrefix removed.
+func portalBase(req *http.Request) string {
+	full := req.URL.Path
+	if u, err := url.ParseRequestURI(req.RequestURI); err == nil {
+		full = u.Path
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(full, req.URL.Path), "/")
+}
+
+// portalToken returns the request's portal token, from a bearer header or
+// the portal cookie.
+func portalToken(req *http.Request) string {
+	if token := auth.BearerToken(req); strings.HasPrefix(token, portal.TokenPrefix) {
+		return token
+	}
+	if c, err := req.Cookie(PortalCookie); err == nil {
+		return c.Value
+	}
+	return ""
+}
+
+func isHTMX(req *http.Request) bool {
+	return req.Header.Get("HX-Request") == "true"
+}
+
+func queryID(req *http.Request) (id.ID, error) {
+	v, err := id.Parse(req.URL.Query().Get("id"))
+	if err != nil {
+		return id.ID{}, errPortalNotFound
+	}
+	return v, nil
+}
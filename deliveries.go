@@ -0,0 +1,30 @@
+package relay
+
+import (
+	"context"
+
+	"github.com/xraph/relay/id"
+)
+
+// RetryDelivery makes a pending delivery due immediately instead of
+// waiting out its backoff. It returns ErrDeliveryNotPending when the
+// delivery is being attempted or has already completed.
+func (r *Relay) RetryDelivery(ctx context.Context, delID id.ID) error {
+	if err := r.store.RetryDelivery(ctx, delID); err != nil {
+		return err
+	}
+	r.engine.Wake()
+	return nil
+}
+
+// CancelDelivery stops a pending delivery from being attempted again. The
+// delivery is kept, in StateCancelled, and is not moved to the DLQ.
+func (r *Relay) CancelDelivery(ctx context.Context, delID id.ID) error {
+	if err := r.store.CancelDelivery(ctx, delID); err != nil {
+		return err
+	}
+	if r.metrics != nil {
+		r.metrics.PendingDeliveries.Dec()
+	}
+	return nil
+}
@@ -19,6 +19,9 @@ const (
 
 	// StateFailed indicates the delivery permanently failed and was moved to the DLQ.
 	StateFailed State = "failed"
+
+	// StateCancelled indicates the delivery was cancelled before it was sent.
+	StateCancelled State = "cancelled"
 )
 
 // Delivery represents a single webhook delivery attempt to an endpoint.
@@ -60,11 +63,41 @@ type Delivery struct {
 
 	// CompletedAt is when the delivery was completed (delivered or failed).
 	CompletedAt *time.Time `json:"completed_at,omitempty"`
+
+	// Test marks a one-off test delivery (see Engine.SendTest). Test
+	// deliveries are recorded completed, are never retried or
+	// dead-lettered and are left out of metrics and stats.
+	Test bool `json:"test,omitempty"`
+
+	// OriginalDeliveryID is the failed delivery whose DLQ entry was
+	// replayed to create this delivery, nil if it is not a replay.
+	OriginalDeliveryID *id.ID `json:"original_delivery_id,omitempty"`
+
+	// ReplayCount is the number of DLQ replays between the event's first
+	// delivery to the endpoint and this one.
+	ReplayCount int `json:"replay_count,omitempty"`
 }
 
-// ListOpts configures filtering and pagination for delivery listing.
+// ListOpts configures filtering and pagination for delivery listing. Lists are
+// ordered by ID, newest first.
 type ListOpts struct {
+	// Cursor, when set, starts the list after the item with this ID; see
+	// package page.
+	Cursor id.ID
 	Offset int
 	Limit  int
 	State  *State
+
+	// TenantID limits the list to deliveries to the tenant's endpoints.
+	TenantID string
+
+	// EndpointID limits the list to deliveries to one endpoint.
+	EndpointID *id.ID
+
+	// EventID limits the list to deliveries of one event.
+	EventID *id.ID
+
+	// From and To bound the time the deliveries were created.
+	From *time.Time
+	To   *time.Time
 }
@@ -2,6 +2,8 @@ package delivery
 
 import (
 	"context"
+	"net/http"
+	"strconv"
 	"sync"
 	"time"
 
@@ -10,8 +12,10 @@ import (
 
 	"github.com/xraph/relay/endpoint"
 	"github.com/xraph/relay/event"
+	"github.com/xraph/relay/health"
 	"github.com/xraph/relay/id"
 	"github.com/xraph/relay/observability"
+	"github.com/xraph/relay/scope"
 )
 
 // EngineStore is the interface the engine needs for delivery operations.
@@ -19,8 +23,10 @@ type EngineStore interface {
 	Dequeue(ctx context.Context, limit int) ([]*Delivery, error)
 	UpdateDelivery(ctx context.Context, d *Delivery) error
 	GetEndpoint(ctx context.Context, epID id.ID) (*endpoint.Endpoint, error)
-	GetEvent(ctx context.Context, evtID id.ID) (*event.Event, error)
 	SetEnabled(ctx context.Context, epID id.ID, enabled bool) error
+	DisableEndpoint(ctx context.Context, epID id.ID, reason string, at time.Time) error
+	ListAutoDisabled(ctx context.Context) ([]*endpoint.Endpoint, error)
+	GetEvent(ctx context.Context, evtID id.ID) (*event.Event, error)
 }
 
 // DLQPusher pushes permanently failed deliveries to the dead letter queue.
@@ -28,6 +34,41 @@ type DLQPusher interface {
 	PushFailed(ctx context.Context, d *Delivery, ep *endpoint.Endpoint, evt *event.Event, lastError string, lastStatusCode int) error
 }
 
+// PayloadConverter rewrites an event into the version an endpoint is pinned
+// to. It returns evt unchanged when no conversion is needed.
+type PayloadConverter interface {
+	ConvertFor(ctx context.Context, evt *event.Event, ep *endpoint.Endpoint) (*event.Event, error)
+}
+
+// DeprecationLookup reports when an event type was deprecated and when it
+// sunsets, both nil for active types.
+type DeprecationLookup interface {
+	Deprecation(ctx context.Context, eventType string) (deprecatedAt, sunsetAt *time.Time)
+}
+
+// EndpointNotifier is told when the engine disables an endpoint or when an
+// endpoint starts failing. Calls are made from delivery workers and should
+// not block for long.
+type EndpointNotifier interface {
+	// EndpointDisabled is called after ep was disabled for reason.
+	EndpointDisabled(ctx context.Context, ep *endpoint.Endpoint, reason string)
+
+	// EndpointFailing is called when ep's run of consecutive failures
+	// reaches EngineConfig.FailingAfter; last is the latest failed attempt.
+	EndpointFailing(ctx context.Context, ep *endpoint.Endpoint, h health.Health, last Result)
+}
+
+// AttemptHooks observes and shapes delivery attempts.
+type AttemptHooks interface {
+	// BeforeAttempt is called with the signed request just before it is
+	// sent, and may modify it. An error fails the attempt without sending
+	// it; the delivery is retried as after any failed attempt.
+	BeforeAttempt(ctx context.Context, req *http.Request, d *Delivery, ep *endpoint.Endpoint, evt *event.Event) error
+
+	// AfterAttempt is called with the outcome of every attempt.
+	AfterAttempt(ctx context.Context, d *Delivery, ep *endpoint.Endpoint, evt *event.Event, result Result)
+}
+
 // EngineConfig holds engine configuration.
 type EngineConfig struct {
 	Concurrency  int
@@ -41,6 +82,35 @@ type EngineConfig struct {
 	RetrySchedule   []time.Duration
 	Metrics         *observability.Metrics
 	Tracer          *observability.Tracer
+	// Converter, when set, converts payloads for version-pinned endpoints.
+	Converter PayloadConverter
+	// Deprecations, when set, adds Deprecation and Sunset headers to
+	// deliveries of deprecated event types.
+	Deprecations DeprecationLookup
+	// Transport, when set, carries delivery requests instead of
+	// http.DefaultTransport, e.g. to route tunnel:// endpoints to a
+	// development tunnel.
+	Transport http.RoundTripper
+	// Health, when set, records the outcome of every delivery attempt.
+	Health *health.Tracker
+	// HealthPolicy, with Health, disables endpoints whose health breaches
+	// it. Disabled endpoints are probed every ProbeInterval and re-enabled
+	// once they answer a probe with a 2xx; their deliveries wait until then
+	// instead of being attempted.
+	HealthPolicy health.Policy
+	// ProbeInterval is how often automatically disabled endpoints are
+	// probed. Defaults to 5m.
+	ProbeInterval time.Duration
+	// Notifier, when set, is told about endpoints the engine disables and,
+	// with Health and FailingAfter, endpoints that start failing.
+	Notifier EndpointNotifier
+	// FailingAfter is the run of consecutive failures at which Notifier's
+	// EndpointFailing is called, once per run. 0 disables it.
+	FailingAfter int
+	// Hooks, when set, is called around delivery and test attempts.
+	// BeforeAttempt also runs for recovery probes, so headers it adds reach
+	// every request the engine makes.
+	Hooks AttemptHooks
 }
 
 // Engine is the delivery worker pool that dequeues and processes deliveries.
@@ -55,6 +125,11 @@ type Engine struct {
 	wakeCh chan struct{}
 	cancel context.CancelFunc
 	wg     sync.WaitGroup
+
+	// probing holds the endpoints this engine disabled and is probing for
+	// recovery, keyed by ID string.
+	probeMu sync.Mutex
+	probing map[string]id.ID
 }
 
 // NewEngine creates a delivery engine.
@@ -68,14 +143,18 @@ func NewEngine(store EngineStore, dlq DLQPusher, cfg EngineConfig, logger log.Lo
 	if cfg.MaxPollInterval < cfg.PollInterval {
 		cfg.MaxPollInterval = cfg.PollInterval
 	}
+	if cfg.ProbeInterval <= 0 {
+		cfg.ProbeInterval = 5 * time.Minute
+	}
 	return &Engine{
 		store:   store,
-		sender:  NewSender(cfg.RequestTimeout),
+		sender:  &Sender{client: &http.Client{Timeout: cfg.RequestTimeout, Transport: cfg.Transport}},
 		retrier: NewRetrier(cfg.RetrySchedule),
 		dlq:     dlq,
 		config:  cfg,
 		logger:  logger,
 		wakeCh:  make(chan struct{}, 1),
+		probing: make(map[string]id.ID),
 	}
 }
 
@@ -88,6 +167,14 @@ func (e *Engine) Start(ctx context.Context) {
 		defer e.wg.Done()
 		e.pollLoop(ctx)
 	}()
+
+	if e.probes() {
+		e.wg.Add(1)
+		go func() {
+			defer e.wg.Done()
+			e.probeLoop(ctx)
+		}()
+	}
 }
 
 // Stop cancels the poll loop and waits for in-flight deliveries to complete.
@@ -184,6 +271,13 @@ func (e *Engine) process(ctx context.Context, d *Delivery) {
 		}
 		return
 	}
+	if e.probes() && probeable(ep) {
+		e.hold(ctx, d, ep)
+		if span != nil {
+			e.config.Tracer.EndDeliverySpan(span, 0, 0, "endpoint disabled")
+		}
+		return
+	}
 
 	evt, err := e.store.GetEvent(ctx, d.EventID)
 	if err != nil {
@@ -195,9 +289,25 @@ func (e *Engine) process(ctx context.Context, d *Delivery) {
 		return
 	}
 
-	// Perform the HTTP delivery.
+	// Deliver in the scope the event was sent in, so converters, hooks and
+	// notifiers see it.
+	ctx = scope.Restore(ctx, evt.ScopeAppID, evt.ScopeOrgID)
+
+	// Perform the HTTP delivery. A failed conversion counts as a failed
+	// attempt so it is retried (e.g. after a converter is deployed) and
+	// eventually dead-lettered.
 	d.AttemptCount++
-	result := e.sender.Send(ctx, ep, evt, d)
+	var result Result
+	if payload, convErr := e.convert(ctx, evt, ep); convErr != nil {
+		result = Result{Error: "convert payload: " + convErr.Error()}
+	} else {
+		var sent bool
+		result, sent = e.attempt(ctx, ep, evt, payload, d, e.deprecationHeaders(ctx, evt.Type))
+		if sent {
+			e.recordHealth(ctx, ep, result)
+		}
+	}
+	e.afterAttempt(ctx, d, ep, evt, result)
 
 	// Record result on delivery.
 	d.LastError = result.Error
@@ -223,6 +333,9 @@ func (e *Engine) process(ctx context.Context, d *Delivery) {
 			log.String("delivery_id", d.ID.String()), log.Int("status", result.StatusCode), log.Int("latency_ms", result.LatencyMs))
 
 	case Retry:
+		// Stores mark dequeued deliveries as claimed; put it back in the
+		// queue.
+		d.State = StatePending
 		d.NextAttemptAt = e.retrier.ComputeNextAttempt(d.AttemptCount)
 		if e.config.Metrics != nil {
 			e.config.Metrics.RecordDelivery("retried", latencySeconds)
@@ -252,9 +365,8 @@ func (e *Engine) process(ctx context.Context, d *Delivery) {
 		now := time.Now().UTC()
 		d.State = StateFailed
 		d.CompletedAt = &now
-		if disableErr := e.store.SetEnabled(ctx, d.EndpointID, false); disableErr != nil {
-			e.logger.Error("disable endpoint failed",
-				log.String("endpoint_id", d.EndpointID.String()), log.Any("error", disableErr))
+		if ep.Enabled {
+			e.disable(ctx, ep, DisabledGoneReason)
 		}
 		if e.dlq != nil {
 			if dlqErr := e.dlq.PushFailed(ctx, d, ep, evt, result.Error, result.StatusCode); dlqErr != nil {
@@ -267,8 +379,6 @@ func (e *Engine) process(ctx context.Context, d *Delivery) {
 			e.config.Metrics.PendingDeliveries.Dec()
 			e.config.Metrics.DLQSize.Inc()
 		}
-		e.logger.Warn("endpoint disabled (410 Gone)",
-			log.String("endpoint_id", d.EndpointID.String()), log.String("delivery_id", d.ID.String()))
 	}
 
 	// End the tracing span with the final result.
@@ -281,3 +391,86 @@ func (e *Engine) process(ctx context.Context, d *Delivery) {
 			log.String("delivery_id", d.ID.String()), log.Any("error", updateErr))
 	}
 }
+
+// SendTest makes a single attempt of d to ep through the same conversion,
+// signing and sender path as regular deliveries, and records the result on
+// d. It does not retry, dead-letter, disable the endpoint or record
+// metrics; d ends up delivered on a 2xx response and failed otherwise.
+// Persisting d is left to the caller.
+func (e *Engine) SendTest(ctx context.Context, ep *endpoint.Endpoint, evt *event.Event, d *Delivery) Result {
+	d.Test = true
+	d.AttemptCount++
+	ctx = scope.Restore(ctx, evt.ScopeAppID, evt.ScopeOrgID)
+
+	var result Result
+	if payload, convErr := e.convert(ctx, evt, ep); convErr != nil {
+		result = Result{Error: "convert payload: " + convErr.Error()}
+	} else {
+		result, _ = e.attempt(ctx, ep, evt, payload, d, e.deprecationHeaders(ctx, evt.Type))
+	}
+	e.afterAttempt(ctx, d, ep, evt, result)
+
+	now := time.Now().UTC()
+	d.LastError = result.Error
+	d.LastStatusCode = result.StatusCode
+	d.LastResponse = result.Response
+	d.LastLatencyMs = result.LatencyMs
+	d.CompletedAt = &now
+	d.State = StateFailed
+	if succeeded(result) {
+		d.State = StateDelivered
+	}
+	return result
+}
+
+// attempt sends payload, evt in ep's version, to ep through the
+// BeforeAttempt hook. It reports whether the request was sent, false when
+// the hook failed the attempt.
+func (e *Engine) attempt(ctx context.Context, ep *endpoint.Endpoint, evt, payload *event.Event, d *Delivery, extra http.Header) (Result, bool) {
+	if e.config.Hooks == nil {
+		return e.sender.send(ctx, ep, payload, d, extra, nil), true
+	}
+	sent := true
+	result := e.sender.send(ctx, ep, payload, d, extra, func(req *http.Request) error {
+		if err := e.config.Hooks.BeforeAttempt(ctx, req, d, ep, evt); err != nil {
+			sent = false
+			return err
+		}
+		return nil
+	})
+	return result, sent
+}
+
+// afterAttempt calls the AfterAttempt hook, if any.
+func (e *Engine) afterAttempt(ctx context.Context, d *Delivery, ep *endpoint.Endpoint, evt *event.Event, result Result) {
+	if e.config.Hooks != nil {
+		e.config.Hooks.AfterAttempt(ctx, d, ep, evt, result)
+	}
+}
+
+// deprecationHeaders returns the Deprecation (RFC 9745) and Sunset (RFC 8594)
+// headers for deliveries of eventType, or nil when it is not deprecated.
+func (e *Engine) deprecationHeaders(ctx context.Context, eventType string) http.Header {
+	if e.config.Deprecations == nil {
+		return nil
+	}
+	deprecatedAt, sunsetAt := e.config.Deprecations.Deprecation(ctx, eventType)
+	if deprecatedAt == nil {
+		return nil
+	}
+
+	h := make(http.Header, 2)
+	h.Set("Deprecation", "@"+strconv.FormatInt(deprecatedAt.Unix(), 10))
+	if sunsetAt != nil {
+		h.Set("Sunset", sunsetAt.UTC().Format(http.TimeFormat))
+	}
+	return h
+}
+
+// convert returns evt in the version ep is pinned to.
+func (e *Engine) convert(ctx context.Context, evt *event.Event, ep *endpoint.Endpoint) (*event.Event, error) {
+	if e.config.Converter == nil || ep.Version == "" {
+		return evt, nil
+	}
+	return e.config.Converter.ConvertFor(ctx, evt, ep)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/xraph/relay/delivery"
 	"github.com/xraph/relay/endpoint"
 	"github.com/xraph/relay/event"
+	"github.com/xraph/relay/health"
 	"github.com/xraph/relay/id"
 	"github.com/xraph/relay/internal/entity"
 	"github.com/xraph/relay/store/memory"
@@ -271,6 +272,10 @@ func TestEngine410DisablesEndpoint(t *testing.T) {
 		t.Fatal("expected endpoint to be disabled after 410")
 	}
 
+	if epGot.DisabledReason == "" || epGot.DisabledAt == nil {
+		t.Fatalf("expected the disable reason to be recorded, got %+v", epGot)
+	}
+
 	if dlqPusher.count.Load() != 1 {
 		t.Fatalf("expected 1 DLQ push for 410, got %d", dlqPusher.count.Load())
 	}
@@ -354,6 +359,67 @@ func (c *countingStore) Dequeue(ctx context.Context, limit int) ([]*delivery.Del
 	return c.EngineStore.Dequeue(ctx, limit)
 }
 
+// claimingStore wraps an EngineStore and marks dequeued deliveries as
+// claimed, like the SQL stores, which set their state to "delivering".
+type claimingStore struct {
+	delivery.EngineStore
+}
+
+func (c *claimingStore) Dequeue(ctx context.Context, limit int) ([]*delivery.Delivery, error) {
+	ds, err := c.EngineStore.Dequeue(ctx, limit)
+	for _, d := range ds {
+		d.State = "delivering"
+	}
+	return ds, err
+}
+
+func TestEngineRetriesClaimedDelivery(t *testing.T) {
+	var attempts atomic.Int32
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	store := memory.New()
+	cfg := delivery.EngineConfig{
+		Concurrency:    1,
+		PollInterval:   10 * time.Millisecond,
+		BatchSize:      10,
+		RequestTimeout: time.Second,
+		RetrySchedule:  []time.Duration{10 * time.Millisecond},
+	}
+	engine := delivery.NewEngine(&claimingStore{EngineStore: store}, &stubDLQ{}, cfg, nil)
+
+	_, del := createTestData(t, store, srv.URL)
+	ctx := context.Background()
+	engine.Start(ctx)
+	defer engine.Stop(ctx)
+
+	deadline := time.After(2 * time.Second)
+	for {
+		got, err := store.GetDelivery(ctx, del.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.State == delivery.StateDelivered {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatalf("timeout waiting for the retry, got %+v", got)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	if n := attempts.Load(); n != 2 {
+		t.Fatalf("expected 2 attempts, got %d", n)
+	}
+}
+
 func TestEngineIdleBackoff(t *testing.T) {
 	cs := &countingStore{EngineStore: memory.New()}
 	cfg := delivery.EngineConfig{
@@ -420,3 +486,255 @@ func TestEngineWakeDeliversPromptly(t *testing.T) {
 		}
 	}
 }
+
+func TestEngineHealthPolicyDisablesAndProbeRecovers(t *testing.T) {
+	var healthy atomic.Bool
+	var probes atomic.Int32
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Relay-Event-Type") == delivery.ProbeEventType {
+			probes.Add(1)
+		}
+		if healthy.Load() {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	store := memory.New()
+	tracker := health.NewTracker(10)
+	cfg := delivery.EngineConfig{
+		Concurrency:    1,
+		PollInterval:   10 * time.Millisecond,
+		BatchSize:      10,
+		RequestTimeout: time.Second,
+		RetrySchedule:  []time.Duration{10 * time.Millisecond, 10 * time.Millisecond},
+		Health:         tracker,
+		HealthPolicy:   health.Policy{ConsecutiveFailures: 2},
+		ProbeInterval:  30 * time.Millisecond,
+	}
+	engine := delivery.NewEngine(store, &stubDLQ{}, cfg, nil)
+
+	ep, _ := createTestData(t, store, srv.URL)
+
+	ctx := context.Background()
+	engine.Start(ctx)
+	defer engine.Stop(ctx)
+
+	waitEndpoint := func(what string, cond func(*endpoint.Endpoint) bool) *endpoint.Endpoint {
+		t.Helper()
+		deadline := time.After(2 * time.Second)
+		for {
+			got, err := store.GetEndpoint(ctx, ep.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cond(got) {
+				return got
+			}
+			select {
+			case <-deadline:
+				t.Fatalf("timeout waiting for endpoint to be %s, got %+v", what, got)
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+	}
+
+	got := waitEndpoint("disabled", func(ep *endpoint.Endpoint) bool { return !ep.Enabled })
+	if got.DisabledReason == "" || got.DisabledAt == nil {
+		t.Fatalf("expected a disable reason, got %+v", got)
+	}
+	if h := tracker.Get(ep.ID); h.ConsecutiveFailures < 2 || h.Status == health.StatusHealthy {
+		t.Fatalf("unexpected health %+v", h)
+	}
+
+	// Probes keep failing until the endpoint recovers.
+	time.Sleep(100 * time.Millisecond)
+	if probes.Load() == 0 {
+		t.Fatal("expected the disabled endpoint to be probed")
+	}
+	if got, _ := store.GetEndpoint(ctx, ep.ID); got.Enabled {
+		t.Fatal("endpoint re-enabled while still failing")
+	}
+
+	healthy.Store(true)
+	got = waitEndpoint("re-enabled", func(ep *endpoint.Endpoint) bool { return ep.Enabled })
+	if got.DisabledReason != "" || got.DisabledAt != nil {
+		t.Fatalf("expected the disable reason to be cleared, got %+v", got)
+	}
+	if h := tracker.Get(ep.ID); h.Status != health.StatusUnknown {
+		t.Fatalf("expected health to be reset, got %+v", h)
+	}
+}
+
+func TestEngineHoldsDeliveriesToDisabledEndpoint(t *testing.T) {
+	var requests atomic.Int32
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	srv := httptest.NewServer(handler)
+	defer srv.Close()
+
+	store := memory.New()
+	cfg := delivery.EngineConfig{
+		Concurrency:    1,
+		PollInterval:   10 * time.Millisecond,
+		BatchSize:      10,
+		RequestTimeout: time.Second,
+		RetrySchedule:  []time.Duration{10 * time.Millisecond},
+		Health:         health.NewTracker(10),
+		HealthPolicy:   health.Policy{ConsecutiveFailures: 2},
+		ProbeInterval:  time.Hour,
+	}
+	dlqPusher := &stubDLQ{}
+	engine := delivery.NewEngine(&claimingStore{EngineStore: store}, dlqPusher, cfg, nil)
+
+	ep, del := createTestData(t, store, srv.URL)
+	ctx := context.Background()
+	disabled := *ep
+	disabled.Enabled = false
+	disabled.DisabledReason = "2 consecutive failed deliveries"
+	if err := store.UpdateEndpoint(ctx, &disabled); err != nil {
+		t.Fatal(err)
+	}
+
+	engine.Start(ctx)
+	deadline := time.After(2 * time.Second)
+	for {
+		got, err := store.GetDelivery(ctx, del.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.NextAttemptAt.After(time.Now().Add(time.Minute)) {
+			if got.State != delivery.StatePending || got.AttemptCount != 0 {
+				t.Fatalf("expected a pending delivery with no attempts, got %+v", got)
+			}
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatal("timeout waiting for the delivery to be held")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	engine.Stop(ctx)
+
+	if n := requests.Load(); n != 0 {
+		t.Fatalf("expected no requests to the disabled endpoint, got %d", n)
+	}
+	if n := dlqPusher.count.Load(); n != 0 {
+		t.Fatalf("expected nothing dead-lettered, got %d", n)
+	}
+}
+
+func TestEngineProbesEndpointsDisabledBeforeStart(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	store := memory.New()
+	cfg := delivery.EngineConfig{
+		Concurrency:    1,
+		PollInterval:   10 * time.Millisecond,
+		BatchSize:      10,
+		RequestTimeout: time.Second,
+		Health:         health.NewTracker(10),
+		HealthPolicy:   health.Policy{ConsecutiveFailures: 2},
+		ProbeInterval:  20 * time.Millisecond,
+	}
+	engine := delivery.NewEngine(store, &stubDLQ{}, cfg, nil)
+
+	// An endpoint disabled by an earlier run, with nothing queued for it.
+	ctx := context.Background()
+	now := time.Now().UTC()
+	ep := &endpoint.Endpoint{
+		Entity:         entity.New(),
+		ID:             id.NewEndpointID(),
+		TenantID:       "tenant-1",
+		URL:            srv.URL,
+		Secret:         "whsec_test_secret_1234567890abcdef1234567890abcdef",
+		EventTypes:     []string{"test.event"},
+		DisabledReason: "2 consecutive failed deliveries",
+		DisabledAt:     &now,
+	}
+	if err := store.CreateEndpoint(ctx, ep); err != nil {
+		t.Fatal(err)
+	}
+
+	engine.Start(ctx)
+	defer engine.Stop(ctx)
+
+	deadline := time.After(2 * time.Second)
+	for {
+		got, err := store.GetEndpoint(ctx, ep.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Enabled {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatal("timeout waiting for the endpoint to be probed and re-enabled")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestEngineDoesNotProbeGoneEndpoints(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	store := memory.New()
+	cfg := delivery.EngineConfig{
+		Concurrency:    1,
+		PollInterval:   10 * time.Millisecond,
+		BatchSize:      10,
+		RequestTimeout: time.Second,
+		Health:         health.NewTracker(10),
+		HealthPolicy:   health.Policy{ConsecutiveFailures: 2},
+		ProbeInterval:  20 * time.Millisecond,
+	}
+	engine := delivery.NewEngine(store, &stubDLQ{}, cfg, nil)
+
+	// An endpoint that answered 410 Gone stays disabled until its owner
+	// re-enables it, even if it answers 2xx again.
+	ctx := context.Background()
+	now := time.Now().UTC()
+	ep := &endpoint.Endpoint{
+		Entity:         entity.New(),
+		ID:             id.NewEndpointID(),
+		TenantID:       "tenant-1",
+		URL:            srv.URL,
+		Secret:         "whsec_test_secret_1234567890abcdef1234567890abcdef",
+		EventTypes:     []string{"test.event"},
+		DisabledReason: delivery.DisabledGoneReason,
+		DisabledAt:     &now,
+	}
+	if err := store.CreateEndpoint(ctx, ep); err != nil {
+		t.Fatal(err)
+	}
+
+	engine.Start(ctx)
+	time.Sleep(150 * time.Millisecond)
+	engine.Stop(ctx)
+
+	if n := requests.Load(); n != 0 {
+		t.Fatalf("expected no probes to a gone endpoint, got %d", n)
+	}
+	got, err := store.GetEndpoint(ctx, ep.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Enabled {
+		t.Fatal("expected the gone endpoint to stay disabled")
+	}
+}
@@ -0,0 +1,196 @@
+package delivery
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	log "github.com/xraph/go-utils/log"
+
+	"github.com/xraph/relay/endpoint"
+	"github.com/xraph/relay/event"
+	"github.com/xraph/relay/id"
+)
+
+// ProbeEventType is the X-Relay-Event-Type of the probes sent to endpoints
+// disabled by the health policy, and the "type" field of their body.
+const ProbeEventType = "relay.endpoint.probe"
+
+// DisabledGoneReason is the DisabledReason of endpoints disabled because
+// they answered 410 Gone. They are gone for good: the engine neither holds
+// their deliveries nor probes them, and only their owner can re-enable
+// them.
+const DisabledGoneReason = "endpoint answered 410 Gone"
+
+// probes reports whether the engine disables failing endpoints and probes
+// them for recovery.
+func (e *Engine) probes() bool {
+	return e.config.Health != nil && e.config.HealthPolicy.Enabled()
+}
+
+// probeable reports whether ep was disabled by the health policy, so its
+// deliveries are held and it is probed for recovery.
+func probeable(ep *endpoint.Endpoint) bool {
+	return !ep.Enabled && ep.DisabledReason != "" && ep.DisabledReason != DisabledGoneReason
+}
+
+// hold puts off d, a delivery to ep, which the engine disabled, until
+// after the next probe instead of attempting it, so the endpoint's backlog
+// does not fail its way into the DLQ while the endpoint is down. The
+// attempt is not counted.
+func (e *Engine) hold(ctx context.Context, d *Delivery, ep *endpoint.Endpoint) {
+	// Endpoints disabled by another instance, or before a restart, are
+	// probed too.
+	e.startProbing(ep.ID)
+
+	d.State = StatePending
+	d.NextAttemptAt = time.Now().UTC().Add(e.config.ProbeInterval)
+	if err := e.store.UpdateDelivery(ctx, d); err != nil {
+		e.logger.Error("hold delivery failed",
+			log.String("delivery_id", d.ID.String()), log.Any("error", err))
+		return
+	}
+	e.logger.Debug("delivery held for disabled endpoint",
+		log.String("delivery_id", d.ID.String()), log.String("endpoint_id", ep.ID.String()), log.Any("next_at", d.NextAttemptAt))
+}
+
+// recordHealth records a delivery attempt on the health tracker and
+// disables ep if its health breaches the policy.
+func (e *Engine) recordHealth(ctx context.Context, ep *endpoint.Endpoint, result Result) {
+	if e.config.Health == nil {
+		return
+	}
+	now := time.Now().UTC()
+	h := e.config.Health.Record(ep.ID, succeeded(result), now)
+
+	if e.config.Notifier != nil && e.config.FailingAfter > 0 && h.ConsecutiveFailures == e.config.FailingAfter {
+		e.config.Notifier.EndpointFailing(ctx, ep, h, result)
+	}
+
+	if !e.config.HealthPolicy.Enabled() || !ep.Enabled || ep.DisabledReason != "" {
+		return
+	}
+	reason, disable := e.config.HealthPolicy.Evaluate(h, e.config.Health.Window(), now)
+	if !disable {
+		return
+	}
+	if e.disable(ctx, ep, reason) {
+		e.startProbing(ep.ID)
+	}
+}
+
+// disable disables ep and records why. It reports whether the endpoint was
+// updated. Only the enabled state and the reason are written, so edits
+// made since ep was loaded are kept.
+func (e *Engine) disable(ctx context.Context, ep *endpoint.Endpoint, reason string) bool {
+	// Notify with a copy: stores may hand out shared endpoint values.
+	now := time.Now().UTC()
+	upd := *ep
+	upd.Enabled = false
+	upd.DisabledReason = reason
+	upd.DisabledAt = &now
+	if err := e.store.DisableEndpoint(ctx, ep.ID, reason, now); err != nil {
+		e.logger.Error("disable endpoint failed",
+			log.String("endpoint_id", ep.ID.String()), log.Any("error", err))
+		return false
+	}
+	e.logger.Warn("endpoint disabled",
+		log.String("endpoint_id", ep.ID.String()), log.String("reason", reason))
+	if e.config.Notifier != nil {
+		e.config.Notifier.EndpointDisabled(ctx, &upd, reason)
+	}
+	return true
+}
+
+func (e *Engine) startProbing(epID id.ID) {
+	e.probeMu.Lock()
+	defer e.probeMu.Unlock()
+	e.probing[epID.String()] = epID
+}
+
+func (e *Engine) stopProbing(epID id.ID) {
+	e.probeMu.Lock()
+	defer e.probeMu.Unlock()
+	delete(e.probing, epID.String())
+}
+
+func (e *Engine) probeTargets() []id.ID {
+	e.probeMu.Lock()
+	defer e.probeMu.Unlock()
+	targets := make([]id.ID, 0, len(e.probing))
+	for _, epID := range e.probing {
+		targets = append(targets, epID)
+	}
+	return targets
+}
+
+// probeLoop probes the endpoints the engine disabled every ProbeInterval,
+// starting with those already disabled in the store, for instance before
+// a restart.
+func (e *Engine) probeLoop(ctx context.Context) {
+	eps, err := e.store.ListAutoDisabled(ctx)
+	if err != nil {
+		e.logger.Warn("probe: list disabled endpoints failed", log.Any("error", err))
+	}
+	for _, ep := range eps {
+		if probeable(ep) {
+			e.startProbing(ep.ID)
+		}
+	}
+
+	ticker := time.NewTicker(e.config.ProbeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, epID := range e.probeTargets() {
+				e.probe(ctx, epID)
+			}
+		}
+	}
+}
+
+// probe sends a signed probe to an endpoint disabled by the health policy
+// and re-enables it on a 2xx response. Endpoints that were deleted or
+// enabled by hand in the meantime are no longer probed.
+func (e *Engine) probe(ctx context.Context, epID id.ID) {
+	ep, err := e.store.GetEndpoint(ctx, epID)
+	if err != nil {
+		e.logger.Warn("probe: get endpoint failed, no longer probing",
+			log.String("endpoint_id", epID.String()), log.Any("error", err))
+		e.stopProbing(epID)
+		return
+	}
+	if !probeable(ep) {
+		e.stopProbing(epID)
+		return
+	}
+
+	evt := &event.Event{
+		ID:   id.NewEventID(),
+		Type: ProbeEventType,
+		Data: json.RawMessage(`{"type":"` + ProbeEventType + `"}`),
+	}
+	result, _ := e.attempt(ctx, ep, evt, evt, &Delivery{ID: id.NewDeliveryID()}, nil)
+	if !succeeded(result) {
+		e.logger.Debug("probe failed",
+			log.String("endpoint_id", epID.String()), log.Int("status", result.StatusCode), log.String("error", result.Error))
+		return
+	}
+
+	if err := e.store.SetEnabled(ctx, epID, true); err != nil {
+		e.logger.Error("re-enable endpoint failed",
+			log.String("endpoint_id", epID.String()), log.Any("error", err))
+		return
+	}
+	e.config.Health.Reset(epID)
+	e.stopProbing(epID)
+	e.logger.Info("endpoint recovered and re-enabled", log.String("endpoint_id", epID.String()))
+}
+
+func succeeded(result Result) bool {
+	return result.Error == "" && result.StatusCode >= 200 && result.StatusCode < 300
+}
@@ -31,6 +31,13 @@ func NewSender(timeout time.Duration) *Sender {
 
 // Send delivers an event to an endpoint and returns the result.
 func (s *Sender) Send(ctx context.Context, ep *endpoint.Endpoint, evt *event.Event, d *Delivery) Result {
+	return s.send(ctx, ep, evt, d, nil, nil)
+}
+
+// send is Send with extra headers set before the endpoint's custom headers.
+// before, if not nil, is called with the finished request; an error fails
+// the attempt without sending it.
+func (s *Sender) send(ctx context.Context, ep *endpoint.Endpoint, evt *event.Event, d *Delivery, extra http.Header, before func(*http.Request) error) Result {
 	body, err := json.Marshal(evt.Data)
 	if err != nil {
 		return Result{Error: fmt.Sprintf("marshal payload: %v", err)}
@@ -47,6 +54,9 @@ func (s *Sender) Send(ctx context.Context, ep *endpoint.Endpoint, evt *event.Eve
 	req.Header.Set("X-Relay-Event-ID", evt.ID.String())
 	req.Header.Set("X-Relay-Event-Type", evt.Type)
 	req.Header.Set("X-Relay-Delivery-ID", d.ID.String())
+	if evt.Version != "" {
+		req.Header.Set("X-Relay-Event-Version", evt.Version)
+	}
 
 	// HMAC signature.
 	ts := time.Now().Unix()
@@ -54,11 +64,21 @@ func (s *Sender) Send(ctx context.Context, ep *endpoint.Endpoint, evt *event.Eve
 	req.Header.Set("X-Relay-Signature", sig)
 	req.Header.Set("X-Relay-Timestamp", strconv.FormatInt(ts, 10))
 
+	for k, v := range extra {
+		req.Header[k] = v
+	}
+
 	// Custom endpoint headers.
 	for k, v := range ep.Headers {
 		req.Header.Set(k, v)
 	}
 
+	if before != nil {
+		if err := before(req); err != nil {
+			return Result{Error: fmt.Sprintf("before attempt: %v", err)}
+		}
+	}
+
 	start := time.Now()
 	resp, err := s.client.Do(req)
 	latency := time.Since(start).Milliseconds()
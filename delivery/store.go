@@ -24,9 +24,30 @@ type Store interface {
 	// GetDelivery returns a delivery by ID.
 	GetDelivery(ctx context.Context, delID id.ID) (*Delivery, error)
 
+	// RetryDelivery makes a pending delivery due now, skipping the rest of
+	// its backoff. It returns relay.ErrDeliveryNotPending if the delivery is
+	// being attempted or has completed.
+	RetryDelivery(ctx context.Context, delID id.ID) error
+
+	// CancelDelivery moves a pending delivery to StateCancelled so it is
+	// never attempted again. It returns relay.ErrDeliveryNotPending if the
+	// delivery is being attempted or has completed.
+	CancelDelivery(ctx context.Context, delID id.ID) error
+
+	// ListDeliveries returns deliveries matching opts.
+	ListDeliveries(ctx context.Context, opts ListOpts) ([]*Delivery, error)
+
+	// CountDeliveries returns the number of deliveries ListDeliveries lists
+	// for opts, ignoring its pagination.
+	CountDeliveries(ctx context.Context, opts ListOpts) (int64, error)
+
 	// ListByEndpoint returns delivery history for an endpoint.
 	ListByEndpoint(ctx context.Context, epID id.ID, opts ListOpts) ([]*Delivery, error)
 
+	// CountByEndpoint returns the number of deliveries ListByEndpoint lists
+	// for epID and opts, ignoring its pagination.
+	CountByEndpoint(ctx context.Context, epID id.ID, opts ListOpts) (int64, error)
+
 	// ListByEvent returns all deliveries for a specific event.
 	ListByEvent(ctx context.Context, evtID id.ID) ([]*Delivery, error)
 
@@ -0,0 +1,117 @@
+package relay_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xraph/relay"
+	"github.com/xraph/relay/endpoint"
+	"github.com/xraph/relay/event"
+	"github.com/xraph/relay/store/memory"
+)
+
+func TestSendDeprecationLifecycle(t *testing.T) {
+	r, _ := setup(t)
+	registerType(t, r, "old.event")
+	createEndpoint(t, r, "t1", []string{"old.*"})
+
+	send := func() (*relay.SendResult, error) {
+		return r.SendWithResult(ctx(), &event.Event{Type: "old.event", TenantID: "t1", Data: map[string]any{}})
+	}
+
+	// Before the sunset: accepted with a warning.
+	sunset := time.Now().Add(24 * time.Hour)
+	et, err := r.Catalog().Deprecate(ctx(), "old.event", sunset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !et.IsDeprecated || et.SunsetAt == nil || et.DeprecatedAt == nil {
+		t.Fatalf("expected deprecation with sunset, got %+v", et)
+	}
+	res, err := send()
+	if err != nil {
+		t.Fatalf("expected deprecated type to be accepted before sunset, got %v", err)
+	}
+	if len(res.Warnings) != 1 || !strings.Contains(res.Warnings[0], "deprecated") {
+		t.Fatalf("expected deprecation warning, got %v", res.Warnings)
+	}
+	if len(res.DeliveryIDs) != 1 {
+		t.Fatalf("expected 1 delivery, got %d", len(res.DeliveryIDs))
+	}
+
+	// After the sunset: rejected.
+	if _, err := r.Catalog().Deprecate(ctx(), "old.event", time.Now().Add(-time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := send(); !errors.Is(err, relay.ErrEventTypeDeprecated) {
+		t.Fatalf("expected ErrEventTypeDeprecated after sunset, got %v", err)
+	}
+
+	// Re-registering, as a restart does, keeps the deprecation.
+	registerType(t, r, "old.event")
+	if _, err := send(); !errors.Is(err, relay.ErrEventTypeDeprecated) {
+		t.Fatalf("expected ErrEventTypeDeprecated after re-register, got %v", err)
+	}
+
+	// Undeprecated: accepted without warnings.
+	if _, err := r.Catalog().Undeprecate(ctx(), "old.event"); err != nil {
+		t.Fatal(err)
+	}
+	res, err = send()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Warnings) != 0 {
+		t.Fatalf("expected no warnings, got %v", res.Warnings)
+	}
+}
+
+func TestDeliveryDeprecationHeaders(t *testing.T) {
+	got := make(chan http.Header, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		got <- req.Header.Clone()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	r, err := relay.New(relay.WithStore(memory.New()), relay.WithPollInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	registerType(t, r, "old.event")
+	if _, err := r.Endpoints().Create(ctx(), endpoint.Input{
+		TenantID:   "t1",
+		URL:        srv.URL,
+		EventTypes: []string{"old.event"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	sunset := time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := r.Catalog().Deprecate(ctx(), "old.event", sunset); err != nil {
+		t.Fatal(err)
+	}
+
+	r.Start(ctx())
+	defer r.Stop(ctx())
+
+	if err := r.Send(ctx(), &event.Event{Type: "old.event", TenantID: "t1", Data: map[string]any{}}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case h := <-got:
+		if !strings.HasPrefix(h.Get("Deprecation"), "@") {
+			t.Fatalf("expected Deprecation header, got %q", h.Get("Deprecation"))
+		}
+		if h.Get("Sunset") != "Thu, 01 Jan 2099 00:00:00 GMT" {
+			t.Fatalf("expected Sunset header, got %q", h.Get("Sunset"))
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("delivery not received")
+	}
+}
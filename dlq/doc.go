@@ -1,2 +1,11 @@
 // Package dlq defines the dead letter queue for permanently failed deliveries.
+//
+// Replaying an entry enqueues a new delivery of its event to its endpoint,
+// with OriginalDeliveryID pointing at the failed delivery and a fresh set
+// of attempts. The entry stays in the queue, marked with ReplayedAt and the
+// new delivery's ID, and cannot be replayed again. If the new delivery
+// fails too it gets an entry of its own, carrying the delivery's replay
+// count; a Service refuses to replay it once the count reaches the limit
+// set with WithMaxReplays, so failures cannot cycle through the queue
+// forever.
 package dlq
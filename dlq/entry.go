@@ -44,19 +44,45 @@ type Entry struct {
 	// LastStatusCode is the HTTP status code from the final attempt.
 	LastStatusCode int `json:"last_status_code,omitempty"`
 
-	// ReplayedAt is set when the entry has been replayed.
+	// ReplayCount is the failed delivery's delivery.Delivery.ReplayCount.
+	ReplayCount int `json:"replay_count,omitempty"`
+
+	// ReplayedAt is set when the entry has been replayed. Replayed entries
+	// stay in the DLQ until purged.
 	ReplayedAt *time.Time `json:"replayed_at,omitempty"`
 
+	// ReplayDeliveryID is the delivery the replay created.
+	ReplayDeliveryID *id.ID `json:"replay_delivery_id,omitempty"`
+
 	// FailedAt is when the delivery permanently failed.
 	FailedAt time.Time `json:"failed_at"`
 }
 
-// ListOpts configures filtering and pagination for DLQ listing.
+// ListOpts configures filtering and pagination for DLQ listing. Lists are
+// ordered by ID, newest first.
 type ListOpts struct {
+	// Cursor, when set, starts the list after the item with this ID; see
+	// package page.
+	Cursor     id.ID
 	Offset     int
 	Limit      int
 	TenantID   string
 	EndpointID *id.ID
 	From       *time.Time
 	To         *time.Time
+
+	// EventType, when set, only matches entries of this event type.
+	EventType string
+
+	// StatusCode, when set, only matches entries whose final attempt
+	// returned this HTTP status code.
+	StatusCode int
+
+	// ErrorContains, when set, only matches entries whose error contains
+	// this substring, case-sensitively.
+	ErrorContains string
+
+	// Unreplayed, when set, only matches entries that have not been
+	// replayed.
+	Unreplayed bool
 }
@@ -3,6 +3,7 @@ package dlq
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -15,21 +16,75 @@ import (
 	"github.com/xraph/relay/internal/entity"
 )
 
+// Defaults for the Service options.
+const (
+	DefaultMaxAttempts = 5
+	DefaultMaxReplays  = 3
+)
+
+// replayPageSize is the number of entries ReplayBulk lists at a time.
+const replayPageSize = 100
+
+// Errors returned by Replay.
+var (
+	ErrAlreadyReplayed = errors.New("dlq: entry already replayed")
+	ErrReplayLimit     = errors.New("dlq: replay limit reached")
+)
+
 // Service manages the dead letter queue.
 type Service struct {
 	store  Store
 	logger log.Logger
+
+	maxAttempts int
+	maxReplays  int
+
+	// onPush is called with every entry PushFailed stored.
+	onPush func(context.Context, *Entry)
+}
+
+// ServiceOption configures a Service.
+type ServiceOption func(*Service)
+
+// WithOnPush makes PushFailed call fn with each entry once it is stored.
+func WithOnPush(fn func(context.Context, *Entry)) ServiceOption {
+	return func(svc *Service) {
+		svc.onPush = fn
+	}
+}
+
+// WithMaxAttempts sets the attempts a replayed delivery gets,
+// DefaultMaxAttempts by default.
+func WithMaxAttempts(n int) ServiceOption {
+	return func(svc *Service) {
+		svc.maxAttempts = n
+	}
+}
+
+// WithMaxReplays sets how many times a delivery can be replayed from the
+// DLQ, counting the replays of the deliveries it replays, DefaultMaxReplays
+// by default. Zero or less removes the limit.
+func WithMaxReplays(n int) ServiceOption {
+	return func(svc *Service) {
+		svc.maxReplays = n
+	}
 }
 
 // NewService creates a new DLQ service.
-func NewService(store Store, logger log.Logger) *Service {
+func NewService(store Store, logger log.Logger, opts ...ServiceOption) *Service {
 	if logger == nil {
 		logger = log.NewNoopLogger()
 	}
-	return &Service{
-		store:  store,
-		logger: logger,
+	svc := &Service{
+		store:       store,
+		logger:      logger,
+		maxAttempts: DefaultMaxAttempts,
+		maxReplays:  DefaultMaxReplays,
+	}
+	for _, opt := range opts {
+		opt(svc)
 	}
+	return svc
 }
 
 // PushFailed creates a DLQ entry from a failed delivery. Implements delivery.DLQPusher.
@@ -52,10 +107,17 @@ func (svc *Service) PushFailed(ctx context.Context, d *delivery.Delivery, ep *en
 		Error:          lastError,
 		AttemptCount:   d.AttemptCount,
 		LastStatusCode: lastStatusCode,
+		ReplayCount:    d.ReplayCount,
 		FailedAt:       time.Now().UTC(),
 	}
 
-	return svc.store.Push(ctx, entry)
+	if err := svc.store.Push(ctx, entry); err != nil {
+		return err
+	}
+	if svc.onPush != nil {
+		svc.onPush(ctx, entry)
+	}
+	return nil
 }
 
 // List returns DLQ entries matching the given options.
@@ -68,14 +130,69 @@ func (svc *Service) Get(ctx context.Context, dlqID id.ID) (*Entry, error) {
 	return svc.store.GetDLQ(ctx, dlqID)
 }
 
-// Replay re-enqueues a single DLQ entry for redelivery.
+// Replay enqueues a new delivery of a DLQ entry's event to its endpoint
+// and marks the entry replayed; see the package documentation. It returns
+// ErrAlreadyReplayed for entries that were replayed before and
+// ErrReplayLimit when the failed delivery already is the last replay
+// WithMaxReplays allows.
 func (svc *Service) Replay(ctx context.Context, dlqID id.ID) error {
-	return svc.store.Replay(ctx, dlqID)
+	e, err := svc.store.GetDLQ(ctx, dlqID)
+	if err != nil {
+		return err
+	}
+	return svc.replay(ctx, e)
 }
 
-// ReplayBulk re-enqueues all DLQ entries within a time range.
+func (svc *Service) replay(ctx context.Context, e *Entry) error {
+	if e.ReplayedAt != nil {
+		return ErrAlreadyReplayed
+	}
+	if svc.maxReplays > 0 && e.ReplayCount >= svc.maxReplays {
+		return ErrReplayLimit
+	}
+
+	now := time.Now().UTC()
+	original := e.DeliveryID
+	d := &delivery.Delivery{
+		Entity:             entity.New(),
+		ID:                 id.NewDeliveryID(),
+		EventID:            e.EventID,
+		EndpointID:         e.EndpointID,
+		State:              delivery.StatePending,
+		MaxAttempts:        svc.maxAttempts,
+		NextAttemptAt:      now,
+		OriginalDeliveryID: &original,
+		ReplayCount:        e.ReplayCount + 1,
+	}
+	return svc.store.Replay(ctx, e.ID, d)
+}
+
+// ReplayBulk replays the entries that failed within a time range and were
+// not replayed yet, skipping those past the replay limit, and returns how
+// many it replayed.
 func (svc *Service) ReplayBulk(ctx context.Context, from, to time.Time) (int64, error) {
-	return svc.store.ReplayBulk(ctx, from, to)
+	opts := ListOpts{From: &from, To: &to, Unreplayed: true, Limit: replayPageSize}
+	var count int64
+	for {
+		entries, err := svc.store.ListDLQ(ctx, opts)
+		if err != nil {
+			return count, err
+		}
+		for _, e := range entries {
+			switch err := svc.replay(ctx, e); {
+			case err == nil:
+				count++
+			case errors.Is(err, ErrAlreadyReplayed), errors.Is(err, ErrReplayLimit):
+				// Replayed concurrently or past the limit; leave it.
+			default:
+				return count, err
+			}
+		}
+		if len(entries) < replayPageSize {
+			return count, nil
+		}
+		opts.Cursor = entries[len(entries)-1].ID
+	}
 }
 
 // Purge removes old DLQ entries.
@@ -83,7 +200,8 @@ func (svc *Service) Purge(ctx context.Context, before time.Time) (int64, error)
 	return svc.store.Purge(ctx, before)
 }
 
-// Count returns the total number of DLQ entries.
-func (svc *Service) Count(ctx context.Context) (int64, error) {
-	return svc.store.CountDLQ(ctx)
+// Count returns the number of DLQ entries List lists for opts, ignoring
+// its pagination.
+func (svc *Service) Count(ctx context.Context, opts ListOpts) (int64, error) {
+	return svc.store.CountDLQ(ctx, opts)
 }
@@ -3,6 +3,7 @@ package dlq_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"testing"
 	"time"
 
@@ -150,7 +151,7 @@ func TestGetDLQEntry(t *testing.T) {
 func TestCount(t *testing.T) {
 	svc, _ := newService()
 
-	count, err := svc.Count(ctx())
+	count, err := svc.Count(ctx(), dlq.ListOpts{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -170,7 +171,7 @@ func TestCount(t *testing.T) {
 		svc.PushFailed(ctx(), d, ep, evt, "err", 500)
 	}
 
-	count, err = svc.Count(ctx())
+	count, err = svc.Count(ctx(), dlq.ListOpts{})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -209,6 +210,103 @@ func TestReplay(t *testing.T) {
 	if got.ReplayedAt == nil {
 		t.Fatal("expected replayed_at to be set")
 	}
+	if got.ReplayDeliveryID == nil {
+		t.Fatal("expected replay_delivery_id to be set")
+	}
+
+	// The new delivery links back to the failed one with fresh attempts.
+	replayed, err := store.GetDelivery(ctx(), *got.ReplayDeliveryID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayed.OriginalDeliveryID == nil || replayed.OriginalDeliveryID.String() != d.ID.String() {
+		t.Fatalf("expected original_delivery_id %s, got %v", d.ID, replayed.OriginalDeliveryID)
+	}
+	if replayed.MaxAttempts != dlq.DefaultMaxAttempts {
+		t.Fatalf("expected %d max attempts, got %d", dlq.DefaultMaxAttempts, replayed.MaxAttempts)
+	}
+	if replayed.ReplayCount != 1 {
+		t.Fatalf("expected replay count 1, got %d", replayed.ReplayCount)
+	}
+
+	// A replayed entry cannot be replayed again.
+	if err := svc.Replay(ctx(), entries[0].ID); !errors.Is(err, dlq.ErrAlreadyReplayed) {
+		t.Fatalf("expected ErrAlreadyReplayed, got %v", err)
+	}
+}
+
+func TestReplayLimit(t *testing.T) {
+	store := memory.New()
+	svc := dlq.NewService(store, nil, dlq.WithMaxReplays(1))
+
+	d := &delivery.Delivery{
+		Entity:     entity.New(),
+		ID:         id.NewDeliveryID(),
+		EventID:    id.NewEventID(),
+		EndpointID: id.NewEndpointID(),
+	}
+	ep := &endpoint.Endpoint{ID: d.EndpointID, TenantID: "t1", URL: "https://example.com"}
+	evt := &event.Event{ID: d.EventID, Type: "test.event", Data: json.RawMessage(`{}`)}
+
+	svc.PushFailed(ctx(), d, ep, evt, "err", 500)
+	entries, _ := svc.List(ctx(), dlq.ListOpts{Limit: 1})
+	if err := svc.Replay(ctx(), entries[0].ID); err != nil {
+		t.Fatal(err)
+	}
+
+	// The replayed delivery fails as well.
+	got, _ := store.GetDLQ(ctx(), entries[0].ID)
+	replayed, _ := store.GetDelivery(ctx(), *got.ReplayDeliveryID)
+	svc.PushFailed(ctx(), replayed, ep, evt, "err", 500)
+
+	entries, _ = svc.List(ctx(), dlq.ListOpts{Unreplayed: true})
+	if len(entries) != 1 || entries[0].ReplayCount != 1 {
+		t.Fatalf("expected one unreplayed entry with replay count 1, got %d", len(entries))
+	}
+	if err := svc.Replay(ctx(), entries[0].ID); !errors.Is(err, dlq.ErrReplayLimit) {
+		t.Fatalf("expected ErrReplayLimit, got %v", err)
+	}
+}
+
+func TestReplayBulk(t *testing.T) {
+	svc, store := newService()
+
+	for range 2 {
+		d := &delivery.Delivery{
+			Entity:     entity.New(),
+			ID:         id.NewDeliveryID(),
+			EventID:    id.NewEventID(),
+			EndpointID: id.NewEndpointID(),
+		}
+		ep := &endpoint.Endpoint{ID: d.EndpointID, TenantID: "t1", URL: "https://example.com"}
+		evt := &event.Event{ID: d.EventID, Type: "test.event", Data: json.RawMessage(`{}`)}
+		svc.PushFailed(ctx(), d, ep, evt, "err", 500)
+	}
+
+	from := time.Now().Add(-time.Hour)
+	to := time.Now().Add(time.Hour)
+
+	count, err := svc.ReplayBulk(ctx(), from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Fatalf("expected 2, got %d", count)
+	}
+
+	// Entries are kept but not replayed twice.
+	total, _ := svc.Count(ctx(), dlq.ListOpts{})
+	if total != 2 {
+		t.Fatalf("expected 2 entries kept, got %d", total)
+	}
+	count, _ = svc.ReplayBulk(ctx(), from, to)
+	if count != 0 {
+		t.Fatalf("expected 0 on second replay, got %d", count)
+	}
+	pending, _ := store.CountPending(ctx())
+	if pending != 2 {
+		t.Fatalf("expected 2 pending deliveries, got %d", pending)
+	}
 }
 
 func TestPurge(t *testing.T) {
@@ -235,7 +333,7 @@ func TestPurge(t *testing.T) {
 		t.Fatalf("expected 3 purged, got %d", purged)
 	}
 
-	count, _ := svc.Count(ctx())
+	count, _ := svc.Count(ctx(), dlq.ListOpts{})
 	if count != 0 {
 		t.Fatalf("expected 0 after purge, got %d", count)
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/xraph/relay/delivery"
 	"github.com/xraph/relay/id"
 )
 
@@ -18,15 +19,17 @@ type Store interface {
 	// GetDLQ returns a DLQ entry by ID.
 	GetDLQ(ctx context.Context, dlqID id.ID) (*Entry, error)
 
-	// Replay marks a DLQ entry for redelivery (re-enqueues the delivery).
-	Replay(ctx context.Context, dlqID id.ID) error
-
-	// ReplayBulk replays all DLQ entries in a time window.
-	ReplayBulk(ctx context.Context, from, to time.Time) (int64, error)
+	// Replay enqueues d, the redelivery of a DLQ entry built by Service,
+	// and marks the entry replayed by setting ReplayedAt and
+	// ReplayDeliveryID to d.ID. The entry is kept. It returns
+	// relay.ErrDLQNotFound for unknown entries and ErrAlreadyReplayed,
+	// enqueuing nothing, for entries that were already replayed.
+	Replay(ctx context.Context, dlqID id.ID, d *delivery.Delivery) error
 
 	// Purge deletes DLQ entries older than a threshold.
 	Purge(ctx context.Context, before time.Time) (int64, error)
 
-	// CountDLQ returns the total number of DLQ entries.
-	CountDLQ(ctx context.Context) (int64, error)
+	// CountDLQ returns the number of DLQ entries ListDLQ lists for opts,
+	// ignoring its pagination. Zero opts count every entry.
+	CountDLQ(ctx context.Context, opts ListOpts) (int64, error)
 }
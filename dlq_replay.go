@@ -0,0 +1,130 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	log "github.com/xraph/go-utils/log"
+
+	"github.com/xraph/relay/dlq"
+	"github.com/xraph/relay/id"
+	"github.com/xraph/relay/job"
+	"github.com/xraph/relay/ratelimit"
+)
+
+// DefaultDLQReplayRate is the number of DLQ entries per second a replay
+// job replays when DLQReplayOptions.Rate is not set. It is kept low because
+// the entries often target endpoints that have only just recovered.
+const DefaultDLQReplayRate = 10
+
+// dlqReplayPageSize is the number of DLQ entries a replay job lists at a
+// time.
+const dlqReplayPageSize = 100
+
+// DLQReplayOptions selects the DLQ entries a replay job replays. Unset
+// filters match every entry.
+type DLQReplayOptions struct {
+	TenantID   string `json:"tenant_id,omitempty"`
+	EndpointID *id.ID `json:"endpoint_id,omitempty"`
+	EventType  string `json:"event_type,omitempty"`
+
+	// StatusCode matches the HTTP status code of the entry's final attempt.
+	StatusCode int `json:"status_code,omitempty"`
+
+	// ErrorContains matches a substring of the entry's error.
+	ErrorContains string `json:"error_contains,omitempty"`
+
+	// From and To, when set, bound the time the entry failed.
+	From *time.Time `json:"from,omitempty"`
+	To   *time.Time `json:"to,omitempty"`
+
+	// Rate is the number of entries replayed per second,
+	// DefaultDLQReplayRate if not positive.
+	Rate int `json:"rate"`
+}
+
+// listOpts returns the DLQ filter of opts. Entries that were already
+// replayed are left out.
+func (opts DLQReplayOptions) listOpts() dlq.ListOpts {
+	return dlq.ListOpts{
+		TenantID:      opts.TenantID,
+		EndpointID:    opts.EndpointID,
+		EventType:     opts.EventType,
+		StatusCode:    opts.StatusCode,
+		ErrorContains: opts.ErrorContains,
+		From:          opts.From,
+		To:            opts.To,
+		Unreplayed:    true,
+	}
+}
+
+// CountDLQReplay returns the number of DLQ entries ReplayDLQ would replay
+// for opts, without replaying them.
+func (r *Relay) CountDLQReplay(ctx context.Context, opts DLQReplayOptions) (int64, error) {
+	return r.dlqSvc.Count(ctx, opts.listOpts())
+}
+
+// ReplayDLQ starts a job that replays the DLQ entries matching opts that
+// have not been replayed yet, newest first. Entries that disappear or are
+// replayed elsewhere while the job runs, and entries past the replay limit,
+// are skipped; entries that fail to replay are counted as failed and the
+// job moves on.
+//
+// The job runs on this instance at opts.Rate; follow it with Jobs().Get.
+func (r *Relay) ReplayDLQ(ctx context.Context, opts DLQReplayOptions) *job.Job {
+	if opts.Rate <= 0 {
+		opts.Rate = DefaultDLQReplayRate
+	}
+	return r.jobs.Start(ctx, job.KindDLQReplay, opts.TenantID, opts, func(ctx context.Context, p *job.Progress) error {
+		return r.replayDLQ(ctx, p, opts)
+	})
+}
+
+// replayDLQ runs a DLQ replay job.
+func (r *Relay) replayDLQ(ctx context.Context, p *job.Progress, opts DLQReplayOptions) error {
+	listOpts := opts.listOpts()
+	total, err := r.dlqSvc.Count(ctx, listOpts)
+	if err != nil {
+		return fmt.Errorf("relay: count dlq: %w", err)
+	}
+	p.SetTotal(total)
+
+	limiter := ratelimit.New()
+	listOpts.Limit = dlqReplayPageSize
+	for {
+		entries, err := r.dlqSvc.List(ctx, listOpts)
+		if err != nil {
+			return fmt.Errorf("relay: list dlq: %w", err)
+		}
+
+		for _, e := range entries {
+			if err := limiter.Wait(ctx, job.KindDLQReplay, opts.Rate); err != nil {
+				return err
+			}
+			switch err := r.dlqSvc.Replay(ctx, e.ID); {
+			case err == nil:
+				p.Add(1)
+			case errors.Is(err, ErrDLQNotFound), errors.Is(err, dlq.ErrAlreadyReplayed), errors.Is(err, dlq.ErrReplayLimit):
+				p.Skip(1)
+			case ctx.Err() != nil:
+				return ctx.Err()
+			default:
+				r.logger.Warn("dlq replay failed",
+					log.String("dlq_id", e.ID.String()),
+					log.Error(err),
+				)
+				p.Fail(1)
+			}
+		}
+		if len(entries) > 0 {
+			r.engine.Wake()
+		}
+
+		if len(entries) < dlqReplayPageSize {
+			return nil
+		}
+		listOpts.Cursor = entries[len(entries)-1].ID
+	}
+}
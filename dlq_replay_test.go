@@ -0,0 +1,86 @@
+package relay_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/xraph/relay"
+	"github.com/xraph/relay/dlq"
+	"github.com/xraph/relay/id"
+	"github.com/xraph/relay/internal/entity"
+	"github.com/xraph/relay/job"
+)
+
+func pushDLQ(t *testing.T, r *relay.Relay, tenantID string, status int, errMsg string) *dlq.Entry {
+	t.Helper()
+	e := &dlq.Entry{
+		Entity:         entity.New(),
+		ID:             id.NewDLQID(),
+		DeliveryID:     id.NewDeliveryID(),
+		EventID:        id.NewEventID(),
+		EndpointID:     id.NewEndpointID(),
+		EventType:      "order.created",
+		TenantID:       tenantID,
+		Error:          errMsg,
+		LastStatusCode: status,
+		FailedAt:       time.Now().UTC(),
+	}
+	if err := r.Store().Push(ctx(), e); err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+// waitJob polls a job until it stops.
+func waitJob(t *testing.T, r *relay.Relay, j *job.Job) *job.Job {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !j.Done() && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+		var err error
+		if j, err = r.Jobs().Get(j.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return j
+}
+
+func TestReplayDLQ(t *testing.T) {
+	r, s := setup(t)
+
+	pushDLQ(t, r, "acme", 503, "unexpected status 503")
+	pushDLQ(t, r, "acme", 503, "unexpected status 503")
+	pushDLQ(t, r, "acme", 400, "unexpected status 400")
+	pushDLQ(t, r, "globex", 503, "unexpected status 503")
+
+	opts := relay.DLQReplayOptions{TenantID: "acme", StatusCode: 503, Rate: 1000}
+	count, err := r.CountDLQReplay(ctx(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Fatalf("expected a dry run to count 2 entries, got %d", count)
+	}
+
+	j := r.ReplayDLQ(ctx(), opts)
+	if j.Kind != job.KindDLQReplay || j.TenantID != "acme" {
+		t.Fatalf("unexpected job %+v", j)
+	}
+	j = waitJob(t, r, j)
+	if j.State != job.StateCompleted {
+		t.Fatalf("expected completed, got %s (%s)", j.State, j.Error)
+	}
+	if j.Total != 2 || j.Processed != 2 || j.Failed != 0 {
+		t.Fatalf("expected 2 entries replayed, got %+v", j)
+	}
+
+	pending, _ := s.CountPending(ctx())
+	if pending != 2 {
+		t.Fatalf("expected 2 pending deliveries, got %d", pending)
+	}
+
+	// Replayed entries are left out of later runs.
+	if count, _ = r.CountDLQReplay(ctx(), opts); count != 0 {
+		t.Fatalf("expected nothing left to replay, got %d", count)
+	}
+}
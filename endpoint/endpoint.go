@@ -1,6 +1,8 @@
 package endpoint
 
 import (
+	"time"
+
 	"github.com/xraph/relay/id"
 	"github.com/xraph/relay/internal/entity"
 )
@@ -33,9 +35,33 @@ type Endpoint struct {
 	// Enabled indicates whether the endpoint is active for deliveries.
 	Enabled bool `json:"enabled"`
 
+	// Status is the endpoint's verification status. Endpoints pending
+	// verification stay disabled until they complete the handshake.
+	Status Status `json:"status"`
+
+	// VerifiedAt is when the endpoint last completed the verification
+	// handshake, nil if it never has.
+	VerifiedAt *time.Time `json:"verified_at,omitempty"`
+
+	// KeepDisabled keeps an endpoint pending verification disabled once it
+	// completes the handshake, because its owner had disabled it.
+	KeepDisabled bool `json:"keep_disabled,omitempty"`
+
+	// DisabledReason records why the delivery engine disabled the endpoint,
+	// e.g. after repeated failures. It is empty for endpoints disabled by
+	// hand and is cleared when the endpoint is re-enabled.
+	DisabledReason string `json:"disabled_reason,omitempty"`
+
+	// DisabledAt is when the delivery engine disabled the endpoint.
+	DisabledAt *time.Time `json:"disabled_at,omitempty"`
+
 	// RateLimit is the maximum deliveries per second. 0 means unlimited.
 	RateLimit int `json:"rate_limit"`
 
+	// Version pins the event type version this endpoint receives. Payloads
+	// are converted at delivery time; empty receives events as sent.
+	Version string `json:"version,omitempty"`
+
 	// ScopeAppID scopes the endpoint to a specific app.
 	ScopeAppID string `json:"scope_app_id,omitempty"`
 
@@ -45,3 +71,17 @@ type Endpoint struct {
 	// Metadata holds user-defined key-value pairs.
 	Metadata map[string]string `json:"metadata,omitempty"`
 }
+
+// Status is an endpoint's verification status.
+type Status string
+
+const (
+	// StatusActive endpoints are verified, or were created without
+	// verification. Endpoints stored before statuses existed have an empty
+	// status and count as active.
+	StatusActive Status = "active"
+
+	// StatusPendingVerification endpoints have not yet echoed the
+	// verification challenge for their current URL.
+	StatusPendingVerification Status = "pending_verification"
+)
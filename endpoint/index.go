@@ -0,0 +1,232 @@
+package endpoint
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultIndexTTL bounds how long a tenant's subscription index is trusted
+// by stores that may be shared by several processes. Local changes
+// invalidate the index immediately; the TTL only limits how long changes
+// made by other processes can go unnoticed.
+const DefaultIndexTTL = 5 * time.Second
+
+// LoadFunc loads every endpoint (enabled or not) owned by a tenant.
+type LoadFunc func(ctx context.Context, tenantID string) ([]*Endpoint, error)
+
+// Index is an in-memory subscription index used by stores to answer
+// Resolve without scanning and glob-matching every endpoint of a tenant.
+//
+// Subscriptions are kept in a trie keyed by dotted event type segments, so
+// a lookup only walks the branches that can match: literal segments, "*"
+// (exactly one segment) and "**" (zero or more segments). A pattern of "*"
+// on its own subscribes to everything, as in catalog.Match.
+//
+// Tenants are loaded lazily on first Resolve and dropped by Invalidate or
+// InvalidateEndpoint, which stores call whenever an endpoint changes.
+// Endpoints returned by Resolve are shared between callers and must be
+// treated as read-only.
+type Index struct {
+	load LoadFunc
+	ttl  time.Duration
+
+	mu      sync.RWMutex
+	tenants map[string]*tenantIndex
+	owners  map[string]string // endpoint ID → tenant ID
+	gens    map[string]uint64 // bumped on every tenant invalidation
+}
+
+type tenantIndex struct {
+	root     *trieNode
+	loadedAt time.Time
+}
+
+type trieNode struct {
+	children map[string]*trieNode
+	star     *trieNode // "*": exactly one segment
+	globstar *trieNode // "**": zero or more segments
+	subs     []subscription
+}
+
+type subscription struct {
+	seq int // load order, used to keep results deterministic
+	ep  *Endpoint
+}
+
+// NewIndex creates an index that loads tenants with load. A zero ttl keeps
+// loaded tenants until they are invalidated.
+func NewIndex(load LoadFunc, ttl time.Duration) *Index {
+	return &Index{
+		load:    load,
+		ttl:     ttl,
+		tenants: make(map[string]*tenantIndex),
+		owners:  make(map[string]string),
+		gens:    make(map[string]uint64),
+	}
+}
+
+// Resolve returns the enabled endpoints of a tenant subscribed to eventType,
+// in the order the loader returned them.
+func (x *Index) Resolve(ctx context.Context, tenantID, eventType string) ([]*Endpoint, error) {
+	ti, err := x.tenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[int]*Endpoint)
+	ti.root.match(strings.Split(eventType, "."), seen)
+	if len(seen) == 0 {
+		return nil, nil
+	}
+
+	seqs := make([]int, 0, len(seen))
+	for seq := range seen {
+		seqs = append(seqs, seq)
+	}
+	sort.Ints(seqs)
+
+	result := make([]*Endpoint, len(seqs))
+	for i, seq := range seqs {
+		result[i] = seen[seq]
+	}
+	return result, nil
+}
+
+// Invalidate drops the cached index of a tenant.
+func (x *Index) Invalidate(tenantID string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.dropLocked(tenantID)
+}
+
+// InvalidateEndpoint drops the cached index of whichever tenant owns the
+// endpoint. It is a no-op when the owning tenant is not loaded.
+func (x *Index) InvalidateEndpoint(epID string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if tenantID, ok := x.owners[epID]; ok {
+		x.dropLocked(tenantID)
+	}
+}
+
+// Reset drops every cached tenant.
+func (x *Index) Reset() {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	for tenantID := range x.tenants {
+		x.gens[tenantID]++
+	}
+	x.tenants = make(map[string]*tenantIndex)
+	x.owners = make(map[string]string)
+}
+
+func (x *Index) dropLocked(tenantID string) {
+	delete(x.tenants, tenantID)
+	x.gens[tenantID]++
+	for epID, owner := range x.owners {
+		if owner == tenantID {
+			delete(x.owners, epID)
+		}
+	}
+}
+
+// tenant returns the loaded index of a tenant, loading it if needed.
+func (x *Index) tenant(ctx context.Context, tenantID string) (*tenantIndex, error) {
+	x.mu.RLock()
+	ti, ok := x.tenants[tenantID]
+	gen := x.gens[tenantID]
+	x.mu.RUnlock()
+
+	if ok && (x.ttl <= 0 || time.Since(ti.loadedAt) < x.ttl) {
+		return ti, nil
+	}
+
+	eps, err := x.load(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	ti = buildTenantIndex(eps)
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	// Only publish the result if nothing changed while we were loading;
+	// otherwise the next Resolve loads again.
+	if x.gens[tenantID] == gen {
+		x.tenants[tenantID] = ti
+		for _, ep := range eps {
+			x.owners[ep.ID.String()] = tenantID
+		}
+	}
+	return ti, nil
+}
+
+func buildTenantIndex(eps []*Endpoint) *tenantIndex {
+	root := &trieNode{}
+	for seq, ep := range eps {
+		if !ep.Enabled {
+			continue
+		}
+		for _, pattern := range ep.EventTypes {
+			root.insert(pattern, subscription{seq: seq, ep: ep})
+		}
+	}
+	return &tenantIndex{root: root, loadedAt: time.Now()}
+}
+
+func (n *trieNode) insert(pattern string, sub subscription) {
+	if pattern == "*" {
+		pattern = "**"
+	}
+
+	node := n
+	for _, seg := range strings.Split(pattern, ".") {
+		switch seg {
+		case "*":
+			if node.star == nil {
+				node.star = &trieNode{}
+			}
+			node = node.star
+		case "**":
+			if node.globstar == nil {
+				node.globstar = &trieNode{}
+			}
+			node = node.globstar
+		default:
+			if node.children == nil {
+				node.children = make(map[string]*trieNode)
+			}
+			child, ok := node.children[seg]
+			if !ok {
+				child = &trieNode{}
+				node.children[seg] = child
+			}
+			node = child
+		}
+	}
+	node.subs = append(node.subs, sub)
+}
+
+func (n *trieNode) match(segs []string, out map[int]*Endpoint) {
+	if n.globstar != nil {
+		for i := 0; i <= len(segs); i++ {
+			n.globstar.match(segs[i:], out)
+		}
+	}
+
+	if len(segs) == 0 {
+		for _, sub := range n.subs {
+			out[sub.seq] = sub.ep
+		}
+		return
+	}
+
+	if child, ok := n.children[segs[0]]; ok {
+		child.match(segs[1:], out)
+	}
+	if n.star != nil {
+		n.star.match(segs[1:], out)
+	}
+}
@@ -0,0 +1,242 @@
+package endpoint_test
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/xraph/relay/catalog"
+	"github.com/xraph/relay/endpoint"
+	"github.com/xraph/relay/id"
+)
+
+func staticLoader(eps []*endpoint.Endpoint) (endpoint.LoadFunc, *int) {
+	calls := 0
+	return func(_ context.Context, tenantID string) ([]*endpoint.Endpoint, error) {
+		calls++
+		var out []*endpoint.Endpoint
+		for _, ep := range eps {
+			if ep.TenantID == tenantID {
+				out = append(out, ep)
+			}
+		}
+		return out, nil
+	}, &calls
+}
+
+func indexEndpoint(tenantID string, patterns ...string) *endpoint.Endpoint {
+	return &endpoint.Endpoint{
+		ID:         id.NewEndpointID(),
+		TenantID:   tenantID,
+		EventTypes: patterns,
+		Enabled:    true,
+	}
+}
+
+func TestIndexResolve(t *testing.T) {
+	exact := indexEndpoint("t1", "invoice.created")
+	single := indexEndpoint("t1", "invoice.*")
+	multi := indexEndpoint("t1", "invoice.**")
+	all := indexEndpoint("t1", "*")
+	both := indexEndpoint("t1", "invoice.*", "*.created") // must not be returned twice
+	disabled := indexEndpoint("t1", "*")
+	disabled.Enabled = false
+	other := indexEndpoint("t2", "*")
+
+	load, _ := staticLoader([]*endpoint.Endpoint{exact, single, multi, all, both, disabled, other})
+	idx := endpoint.NewIndex(load, 0)
+
+	tests := []struct {
+		eventType string
+		want      []*endpoint.Endpoint
+	}{
+		{"invoice.created", []*endpoint.Endpoint{exact, single, multi, all, both}},
+		{"invoice.paid", []*endpoint.Endpoint{single, multi, all, both}},
+		{"invoice.payment.failed", []*endpoint.Endpoint{multi, all}},
+		{"invoice", []*endpoint.Endpoint{multi, all}},
+		{"user.created", []*endpoint.Endpoint{all, both}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.eventType, func(t *testing.T) {
+			got, err := idx.Resolve(context.Background(), "t1", tt.eventType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %d endpoints, got %d", len(tt.want), len(got))
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("endpoint %d: expected %s, got %s", i, tt.want[i].EventTypes, got[i].EventTypes)
+				}
+			}
+		})
+	}
+}
+
+// TestIndexAgreesWithMatch checks the trie against catalog.Match, which is
+// the reference definition of subscription patterns.
+func TestIndexAgreesWithMatch(t *testing.T) {
+	segments := []string{"a", "b", "c", "*", "**"}
+	rng := rand.New(rand.NewSource(1))
+
+	randomName := func(wildcards bool) string {
+		n := 1 + rng.Intn(4)
+		name := ""
+		for i := range n {
+			choices := segments[:3]
+			if wildcards {
+				choices = segments
+			}
+			if i > 0 {
+				name += "."
+			}
+			name += choices[rng.Intn(len(choices))]
+		}
+		return name
+	}
+
+	eps := make([]*endpoint.Endpoint, 200)
+	for i := range eps {
+		eps[i] = indexEndpoint("t1", randomName(true))
+	}
+	load, _ := staticLoader(eps)
+	idx := endpoint.NewIndex(load, 0)
+
+	for range 500 {
+		eventType := randomName(false)
+
+		got, err := idx.Resolve(context.Background(), "t1", eventType)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var want []*endpoint.Endpoint
+		for _, ep := range eps {
+			if catalog.Match(ep.EventTypes[0], eventType) {
+				want = append(want, ep)
+			}
+		}
+
+		if len(got) != len(want) {
+			t.Fatalf("%s: index resolved %d endpoints, Match resolved %d", eventType, len(got), len(want))
+		}
+		for i := range got {
+			if got[i] != want[i] {
+				t.Fatalf("%s: endpoint %d differs (%s vs %s)", eventType, i, got[i].EventTypes, want[i].EventTypes)
+			}
+		}
+	}
+}
+
+func TestIndexInvalidate(t *testing.T) {
+	ep := indexEndpoint("t1", "invoice.*")
+	load, calls := staticLoader([]*endpoint.Endpoint{ep})
+	idx := endpoint.NewIndex(load, 0)
+
+	_, _ = idx.Resolve(context.Background(), "t1", "invoice.created")
+	_, _ = idx.Resolve(context.Background(), "t1", "invoice.created")
+	if *calls != 1 {
+		t.Fatalf("expected 1 load, got %d", *calls)
+	}
+
+	idx.InvalidateEndpoint(ep.ID.String())
+	_, _ = idx.Resolve(context.Background(), "t1", "invoice.created")
+	if *calls != 2 {
+		t.Fatalf("expected reload after InvalidateEndpoint, got %d loads", *calls)
+	}
+
+	idx.Invalidate("t1")
+	_, _ = idx.Resolve(context.Background(), "t1", "invoice.created")
+	if *calls != 3 {
+		t.Fatalf("expected reload after Invalidate, got %d loads", *calls)
+	}
+
+	// Unknown endpoints are ignored.
+	idx.InvalidateEndpoint(id.NewEndpointID().String())
+	_, _ = idx.Resolve(context.Background(), "t1", "invoice.created")
+	if *calls != 3 {
+		t.Fatalf("expected cached index, got %d loads", *calls)
+	}
+}
+
+func TestIndexTTL(t *testing.T) {
+	load, calls := staticLoader([]*endpoint.Endpoint{indexEndpoint("t1", "*")})
+	idx := endpoint.NewIndex(load, 10*time.Millisecond)
+
+	_, _ = idx.Resolve(context.Background(), "t1", "x")
+	time.Sleep(20 * time.Millisecond)
+	_, _ = idx.Resolve(context.Background(), "t1", "x")
+
+	if *calls != 2 {
+		t.Fatalf("expected reload after TTL, got %d loads", *calls)
+	}
+}
+
+// ──────────────────────────────────────────────────
+// Benchmarks
+// ──────────────────────────────────────────────────
+
+// benchEndpoints builds n endpoints spread over 50 resources with a mix of
+// exact, single-segment and multi-segment subscriptions.
+func benchEndpoints(n int) []*endpoint.Endpoint {
+	eps := make([]*endpoint.Endpoint, n)
+	for i := range eps {
+		resource := fmt.Sprintf("resource%d", i%50)
+		var pattern string
+		switch i % 3 {
+		case 0:
+			pattern = resource + ".created"
+		case 1:
+			pattern = resource + ".*"
+		default:
+			pattern = resource + ".**"
+		}
+		eps[i] = indexEndpoint("t1", pattern)
+	}
+	return eps
+}
+
+func BenchmarkIndexResolve(b *testing.B) {
+	for _, n := range []int{1000, 5000, 20000} {
+		b.Run(fmt.Sprintf("endpoints=%d", n), func(b *testing.B) {
+			load, _ := staticLoader(benchEndpoints(n))
+			idx := endpoint.NewIndex(load, 0)
+			ctx := context.Background()
+
+			b.ResetTimer()
+			for range b.N {
+				if _, err := idx.Resolve(ctx, "t1", "resource7.created"); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkScanResolve is the linear scan the index replaces, kept as a
+// baseline for comparison.
+func BenchmarkScanResolve(b *testing.B) {
+	for _, n := range []int{1000, 5000, 20000} {
+		b.Run(fmt.Sprintf("endpoints=%d", n), func(b *testing.B) {
+			eps := benchEndpoints(n)
+
+			b.ResetTimer()
+			for range b.N {
+				var result []*endpoint.Endpoint
+				for _, ep := range eps {
+					for _, pattern := range ep.EventTypes {
+						if catalog.Match(pattern, "resource7.created") {
+							result = append(result, ep)
+							break
+						}
+					}
+				}
+				_ = result
+			}
+		})
+	}
+}
@@ -1,5 +1,7 @@
 package endpoint
 
+import "github.com/xraph/relay/id"
+
 // Input is the creation/update payload for endpoints.
 type Input struct {
 	// TenantID identifies the tenant that owns this endpoint.
@@ -23,13 +25,25 @@ type Input struct {
 	// RateLimit is the maximum deliveries per second. 0 means unlimited.
 	RateLimit int `json:"rate_limit"`
 
+	// Version pins the event type version the endpoint receives. On update,
+	// nil leaves it unchanged and an empty string clears the pin.
+	Version *string `json:"version,omitempty"`
+
 	// Metadata holds user-defined key-value pairs.
 	Metadata map[string]string `json:"metadata,omitempty"`
 }
 
-// ListOpts configures filtering and pagination for endpoint listing.
+// ListOpts configures filtering and pagination for endpoint listing. Lists are
+// ordered by ID, oldest first.
 type ListOpts struct {
+	// Cursor, when set, starts the list after the item with this ID; see
+	// package page.
+	Cursor  id.ID
 	Offset  int
 	Limit   int
 	Enabled *bool
+	// ScopeAppID and ScopeOrgID, when set, only list endpoints with that
+	// scope.
+	ScopeAppID string
+	ScopeOrgID string
 }
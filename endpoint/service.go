@@ -2,33 +2,66 @@ package endpoint
 
 import (
 	"context"
+	"fmt"
 	"net/url"
+	"time"
 
 	log "github.com/xraph/go-utils/log"
 
 	"github.com/xraph/relay/id"
 	"github.com/xraph/relay/internal/entity"
+	"github.com/xraph/relay/scope"
 	"github.com/xraph/relay/signature"
 )
 
+// verifyTimeout bounds the background handshake of new and changed
+// endpoints.
+const verifyTimeout = 30 * time.Second
+
 // Service provides endpoint management operations.
 type Service struct {
 	store  Store
 	logger log.Logger
+
+	// verifier runs the URL verification handshake. When requireVerify is
+	// set, new endpoints and URL changes wait for it before being enabled.
+	verifier      Verifier
+	requireVerify bool
+}
+
+// ServiceOption configures a Service.
+type ServiceOption func(*Service)
+
+// WithVerification makes new endpoints, and endpoints whose URL changes,
+// start in StatusPendingVerification, disabled, until they complete the
+// handshake run by v (an HTTPVerifier if nil).
+func WithVerification(v Verifier) ServiceOption {
+	return func(svc *Service) {
+		if v != nil {
+			svc.verifier = v
+		}
+		svc.requireVerify = true
+	}
 }
 
 // NewService creates a new endpoint service.
-func NewService(store Store, logger log.Logger) *Service {
+func NewService(store Store, logger log.Logger, opts ...ServiceOption) *Service {
 	if logger == nil {
 		logger = log.NewNoopLogger()
 	}
-	return &Service{
-		store:  store,
-		logger: logger,
+	svc := &Service{
+		store:    store,
+		logger:   logger,
+		verifier: &HTTPVerifier{},
+	}
+	for _, opt := range opts {
+		opt(svc)
 	}
+	return svc
 }
 
-// Create registers a new webhook endpoint.
+// Create registers a new webhook endpoint, scoped to the app and org ctx
+// carries (see package scope).
 func (svc *Service) Create(ctx context.Context, in Input) (*Endpoint, error) {
 	if _, err := url.ParseRequestURI(in.URL); err != nil {
 		return nil, &ValidationError{Field: "url", Message: "invalid URL"}
@@ -57,14 +90,27 @@ func (svc *Service) Create(ctx context.Context, in Input) (*Endpoint, error) {
 		EventTypes:  in.EventTypes,
 		Headers:     in.Headers,
 		Enabled:     true,
+		Status:      StatusActive,
 		RateLimit:   in.RateLimit,
 		Metadata:    in.Metadata,
 	}
+	if in.Version != nil {
+		ep.Version = *in.Version
+	}
+	ep.ScopeAppID, ep.ScopeOrgID = scope.Capture(ctx)
+	if svc.requireVerify {
+		ep.Enabled = false
+		ep.Status = StatusPendingVerification
+	}
 
 	if err := svc.store.CreateEndpoint(ctx, ep); err != nil {
 		return nil, err
 	}
 
+	if svc.requireVerify {
+		svc.tryVerify(ctx, ep)
+	}
+
 	return ep, nil
 }
 
@@ -75,15 +121,21 @@ func (svc *Service) Get(ctx context.Context, epID id.ID) (*Endpoint, error) {
 
 // Update modifies an existing endpoint.
 func (svc *Service) Update(ctx context.Context, epID id.ID, in Input) (*Endpoint, error) {
-	ep, err := svc.store.GetEndpoint(ctx, epID)
+	cur, err := svc.store.GetEndpoint(ctx, epID)
 	if err != nil {
 		return nil, err
 	}
+	// Update a copy: stores may hand out shared endpoint values, which a
+	// background handshake may be reading.
+	upd := *cur
+	ep := &upd
 
+	urlChanged := false
 	if in.URL != "" {
 		if _, err := url.ParseRequestURI(in.URL); err != nil {
 			return nil, &ValidationError{Field: "url", Message: "invalid URL"}
 		}
+		urlChanged = in.URL != ep.URL
 		ep.URL = in.URL
 	}
 	if in.Description != "" {
@@ -98,14 +150,33 @@ func (svc *Service) Update(ctx context.Context, epID id.ID, in Input) (*Endpoint
 	if in.RateLimit >= 0 {
 		ep.RateLimit = in.RateLimit
 	}
+	if in.Version != nil {
+		ep.Version = *in.Version
+	}
 	if in.Metadata != nil {
 		ep.Metadata = in.Metadata
 	}
 
+	reverify := urlChanged && svc.requireVerify
+	if reverify {
+		// Verification re-enables the endpoint only if it was enabled, or
+		// disabled by the delivery engine rather than its owner.
+		if cur.Status != StatusPendingVerification {
+			ep.KeepDisabled = !cur.Enabled && cur.DisabledReason == ""
+		}
+		ep.Enabled = false
+		ep.Status = StatusPendingVerification
+		ep.VerifiedAt = nil
+	}
+
 	if err := svc.store.UpdateEndpoint(ctx, ep); err != nil {
 		return nil, err
 	}
 
+	if reverify {
+		svc.tryVerify(ctx, ep)
+	}
+
 	return ep, nil
 }
 
@@ -119,11 +190,102 @@ func (svc *Service) List(ctx context.Context, tenantID string, opts ListOpts) ([
 	return svc.store.ListEndpoints(ctx, tenantID, opts)
 }
 
-// SetEnabled enables or disables an endpoint.
+// Count returns the number of endpoints List lists for a tenant, ignoring
+// its pagination.
+func (svc *Service) Count(ctx context.Context, tenantID string, opts ListOpts) (int64, error) {
+	return svc.store.CountEndpoints(ctx, tenantID, opts)
+}
+
+// Subscribers returns the endpoints of all tenants subscribed to an event
+// type, for example to find who still depends on a deprecated type.
+func (svc *Service) Subscribers(ctx context.Context, eventType string) ([]*Endpoint, error) {
+	return svc.store.ListSubscribers(ctx, eventType)
+}
+
+// SetEnabled enables or disables an endpoint. Endpoints pending
+// verification can only be enabled by completing it; disabling one keeps
+// it disabled once it does. Either way, the reason recorded when the
+// delivery engine disabled the endpoint is cleared: the endpoint is now in
+// the state its owner chose.
 func (svc *Service) SetEnabled(ctx context.Context, epID id.ID, enabled bool) error {
+	ep, err := svc.store.GetEndpoint(ctx, epID)
+	if err != nil {
+		return err
+	}
+	if ep.Status == StatusPendingVerification {
+		if enabled {
+			return &ValidationError{Field: "status", Message: "endpoint is pending verification"}
+		}
+		if !ep.KeepDisabled {
+			// Update a copy: stores may hand out shared endpoint values.
+			upd := *ep
+			upd.KeepDisabled = true
+			if err := svc.store.UpdateEndpoint(ctx, &upd); err != nil {
+				return err
+			}
+		}
+	}
 	return svc.store.SetEnabled(ctx, epID, enabled)
 }
 
+// Verify runs the verification handshake with an endpoint. On success the
+// endpoint is marked active and VerifiedAt is set; one pending verification
+// is also enabled, unless KeepDisabled is set. On failure it returns an error wrapping
+// ErrVerificationFailed and leaves the endpoint unchanged.
+func (svc *Service) Verify(ctx context.Context, epID id.ID) (*Endpoint, error) {
+	ep, err := svc.store.GetEndpoint(ctx, epID)
+	if err != nil {
+		return nil, err
+	}
+	if err := svc.verify(ctx, ep); err != nil {
+		return nil, err
+	}
+	return ep, nil
+}
+
+func (svc *Service) verify(ctx context.Context, ep *Endpoint) error {
+	if err := svc.verifier.Verify(ctx, ep); err != nil {
+		return fmt.Errorf("%w: %s", ErrVerificationFailed, err.Error())
+	}
+
+	now := time.Now().UTC()
+	if ep.Status == StatusPendingVerification {
+		ep.Enabled = !ep.KeepDisabled
+	}
+	ep.KeepDisabled = false
+	ep.Status = StatusActive
+	ep.VerifiedAt = &now
+	return svc.store.UpdateEndpoint(ctx, ep)
+}
+
+// tryVerify attempts the handshake for a new or changed endpoint in the
+// background, so Create and Update do not wait on the receiver. It gets
+// its own context, bounded by verifyTimeout, as the caller's usually ends
+// with the request. A failure leaves the endpoint pending for a later
+// Verify.
+func (svc *Service) tryVerify(ctx context.Context, ep *Endpoint) {
+	epID, epURL := ep.ID, ep.URL
+	go func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), verifyTimeout)
+		defer cancel()
+
+		// Skip endpoints that were verified, moved or deleted meanwhile.
+		cur, err := svc.store.GetEndpoint(ctx, epID)
+		if err != nil || cur.URL != epURL || cur.Status != StatusPendingVerification {
+			return
+		}
+		// Verify a copy: stores may hand out shared endpoint values.
+		upd := *cur
+		if err := svc.verify(ctx, &upd); err != nil {
+			svc.logger.Warn("endpoint verification failed",
+				log.String("endpoint_id", epID.String()),
+				log.String("url", epURL),
+				log.Any("error", err),
+			)
+		}
+	}()
+}
+
 // RotateSecret generates a new signing secret for an endpoint.
 func (svc *Service) RotateSecret(ctx context.Context, epID id.ID) (string, error) {
 	ep, err := svc.store.GetEndpoint(ctx, epID)
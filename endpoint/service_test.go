@@ -5,6 +5,7 @@ import (
 	"errors"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/xraph/relay"
 	"github.com/xraph/relay/endpoint"
@@ -102,6 +103,18 @@ func TestEndpointServiceGetUpdateDelete(t *testing.T) {
 		t.Fatalf("expected updated description, got %q", updated.Description)
 	}
 
+	// A nil version leaves the pin alone; an empty one clears it.
+	pinned, empty := "2025-01-01", ""
+	if updated, err = svc.Update(ctx(), ep.ID, endpoint.Input{Version: &pinned}); err != nil || updated.Version != pinned {
+		t.Fatalf("expected version %q, got %q (%v)", pinned, updated.Version, err)
+	}
+	if updated, err = svc.Update(ctx(), ep.ID, endpoint.Input{Description: "Again"}); err != nil || updated.Version != pinned {
+		t.Fatalf("expected version %q to be kept, got %q (%v)", pinned, updated.Version, err)
+	}
+	if updated, err = svc.Update(ctx(), ep.ID, endpoint.Input{Version: &empty}); err != nil || updated.Version != "" {
+		t.Fatalf("expected the version to be cleared, got %q (%v)", updated.Version, err)
+	}
+
 	// Delete
 	err = svc.Delete(ctx(), ep.ID)
 	if err != nil {
@@ -158,6 +171,34 @@ func TestEndpointServiceSetEnabled(t *testing.T) {
 	}
 }
 
+func TestEndpointServiceEnableClearsDisabledReason(t *testing.T) {
+	s := memory.New()
+	svc := endpoint.NewService(s, nil)
+
+	ep, _ := svc.Create(ctx(), endpoint.Input{
+		TenantID:   "t1",
+		URL:        "https://example.com/webhook",
+		EventTypes: []string{"*"},
+	})
+
+	// As left by the delivery engine's health policy.
+	now := time.Now().UTC()
+	ep.Enabled = false
+	ep.DisabledReason = "50 consecutive failed deliveries"
+	ep.DisabledAt = &now
+	if err := s.UpdateEndpoint(ctx(), ep); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := svc.SetEnabled(ctx(), ep.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := svc.Get(ctx(), ep.ID)
+	if !got.Enabled || got.DisabledReason != "" || got.DisabledAt != nil {
+		t.Fatalf("expected enabled with the reason cleared, got %+v", got)
+	}
+}
+
 func TestEndpointServiceRotateSecret(t *testing.T) {
 	svc := newService()
 
@@ -194,3 +235,118 @@ func TestEndpointServiceRotateSecretNotFound(t *testing.T) {
 		t.Fatalf("expected ErrEndpointNotFound, got %v", err)
 	}
 }
+
+func TestEndpointServiceVerification(t *testing.T) {
+	reachable := map[string]bool{"https://good.example.com/hook": true}
+	svc := endpoint.NewService(memory.New(), nil, endpoint.WithVerification(endpoint.VerifierFunc(
+		func(_ context.Context, ep *endpoint.Endpoint) error {
+			if !reachable[ep.URL] {
+				return errors.New("no echo")
+			}
+			return nil
+		},
+	)))
+
+	// A URL that does not answer stays pending and disabled.
+	ep, err := svc.Create(ctx(), endpoint.Input{
+		TenantID:   "t1",
+		URL:        "https://typo.example.com/hook",
+		EventTypes: []string{"*"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ep.Enabled || ep.Status != endpoint.StatusPendingVerification {
+		t.Fatalf("expected a disabled pending endpoint, got enabled=%v status=%q", ep.Enabled, ep.Status)
+	}
+	var ve *endpoint.ValidationError
+	if err := svc.SetEnabled(ctx(), ep.ID, true); !errors.As(err, &ve) {
+		t.Fatalf("expected enabling a pending endpoint to fail, got %v", err)
+	}
+	if _, err := svc.Verify(ctx(), ep.ID); !errors.Is(err, endpoint.ErrVerificationFailed) {
+		t.Fatalf("expected ErrVerificationFailed, got %v", err)
+	}
+
+	// Fixing the URL re-runs the handshake in the background, which
+	// enables it.
+	ep, err = svc.Update(ctx(), ep.ID, endpoint.Input{URL: "https://good.example.com/hook", RateLimit: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ep.Status != endpoint.StatusPendingVerification {
+		t.Fatalf("expected Update to return before the handshake, got status %q", ep.Status)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if ep, err = svc.Get(ctx(), ep.ID); err != nil {
+			t.Fatal(err)
+		}
+		if ep.Status == endpoint.StatusActive {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for the handshake, got %+v", ep)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !ep.Enabled || ep.VerifiedAt == nil {
+		t.Fatalf("expected a verified, enabled endpoint, got %+v", ep)
+	}
+
+	// Moving it to an unreachable URL sends it back to pending.
+	ep, err = svc.Update(ctx(), ep.ID, endpoint.Input{URL: "https://gone.example.com/hook", RateLimit: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ep.Enabled || ep.Status != endpoint.StatusPendingVerification || ep.VerifiedAt != nil {
+		t.Fatalf("expected the URL change to require verification, got %+v", ep)
+	}
+}
+
+func TestEndpointServiceVerificationKeepsDisabled(t *testing.T) {
+	svc := endpoint.NewService(memory.New(), nil, endpoint.WithVerification(endpoint.VerifierFunc(
+		func(context.Context, *endpoint.Endpoint) error { return nil },
+	)))
+
+	ep, err := svc.Create(ctx(), endpoint.Input{
+		TenantID:   "t1",
+		URL:        "https://old.example.com/hook",
+		EventTypes: []string{"*"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitActive := func() *endpoint.Endpoint {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			got, err := svc.Get(ctx(), ep.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Status == endpoint.StatusActive {
+				return got
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("timeout waiting for the handshake, got %+v", got)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	if got := waitActive(); !got.Enabled {
+		t.Fatalf("expected a new endpoint to be enabled by the handshake, got %+v", got)
+	}
+
+	// An endpoint its owner disabled stays disabled after a URL change
+	// passes verification.
+	if err := svc.SetEnabled(ctx(), ep.ID, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Update(ctx(), ep.ID, endpoint.Input{URL: "https://new.example.com/hook", RateLimit: -1}); err != nil {
+		t.Fatal(err)
+	}
+	got := waitActive()
+	if got.Enabled || got.KeepDisabled || got.URL != "https://new.example.com/hook" {
+		t.Fatalf("expected a verified, still disabled endpoint, got %+v", got)
+	}
+}
@@ -2,6 +2,7 @@ package endpoint
 
 import (
 	"context"
+	"time"
 
 	"github.com/xraph/relay/id"
 )
@@ -23,10 +24,30 @@ type Store interface {
 	// ListEndpoints returns endpoints for a tenant, optionally filtered.
 	ListEndpoints(ctx context.Context, tenantID string, opts ListOpts) ([]*Endpoint, error)
 
+	// CountEndpoints returns the number of endpoints ListEndpoints lists
+	// for tenantID and opts, ignoring its pagination.
+	CountEndpoints(ctx context.Context, tenantID string, opts ListOpts) (int64, error)
+
 	// Resolve finds all active endpoints matching an event type for a tenant.
 	// This is the hot path — called on every relay.Send().
 	Resolve(ctx context.Context, tenantID string, eventType string) ([]*Endpoint, error)
 
-	// SetEnabled enables or disables an endpoint without deleting it.
+	// SetEnabled enables or disables an endpoint without deleting it and
+	// clears DisabledReason and DisabledAt. Other fields are left alone.
 	SetEnabled(ctx context.Context, epID id.ID, enabled bool) error
+
+	// DisableEndpoint disables an endpoint on behalf of the delivery
+	// engine, recording reason and at as its DisabledReason and DisabledAt.
+	// Other fields are left alone, so concurrent edits are kept.
+	DisableEndpoint(ctx context.Context, epID id.ID, reason string, at time.Time) error
+
+	// ListSubscribers returns the endpoints of every tenant, enabled or
+	// not, with a pattern matching eventType. It scans all endpoints and is
+	// meant for admin views, not the send path.
+	ListSubscribers(ctx context.Context, eventType string) ([]*Endpoint, error)
+
+	// ListAutoDisabled returns the endpoints of every tenant that the
+	// delivery engine disabled: disabled ones with a DisabledReason. The
+	// engine loads them on start to resume probing them.
+	ListAutoDisabled(ctx context.Context) ([]*Endpoint, error)
 }
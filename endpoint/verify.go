@@ -0,0 +1,120 @@
+package endpoint
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xraph/relay/signature"
+)
+
+// VerificationEventType is the X-Relay-Event-Type of verification
+// challenges, and the "type" field of their body.
+const VerificationEventType = "relay.endpoint.verification"
+
+// ErrVerificationFailed is returned when an endpoint does not complete the
+// verification handshake. The cause is wrapped in the message.
+var ErrVerificationFailed = errors.New("endpoint: verification failed")
+
+// Verifier performs the URL verification handshake with an endpoint. It
+// returns nil when the endpoint proved it accepts deliveries.
+type Verifier interface {
+	Verify(ctx context.Context, ep *Endpoint) error
+}
+
+// VerifierFunc adapts a function to a Verifier.
+type VerifierFunc func(ctx context.Context, ep *Endpoint) error
+
+// Verify calls f.
+func (f VerifierFunc) Verify(ctx context.Context, ep *Endpoint) error {
+	return f(ctx, ep)
+}
+
+// HTTPVerifier is the default Verifier. It POSTs a challenge, signed with
+// the endpoint's secret like a delivery:
+//
+//	{"type": "relay.endpoint.verification", "challenge": "3f9c..."}
+//
+// and expects a 2xx response whose body is the challenge, either as-is or
+// as {"challenge": "3f9c..."}.
+type HTTPVerifier struct {
+	// Client sends the challenge. Defaults to a client with a 10 second
+	// timeout.
+	Client *http.Client
+}
+
+// Verify implements Verifier.
+func (v *HTTPVerifier) Verify(ctx context.Context, ep *Endpoint) error {
+	challenge, err := newChallenge()
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(map[string]string{"type": VerificationEventType, "challenge": challenge})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ep.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	ts := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Relay/1.0")
+	req.Header.Set("X-Relay-Event-Type", VerificationEventType)
+	req.Header.Set("X-Relay-Signature", signature.Sign(body, ep.Secret, ts))
+	req.Header.Set("X-Relay-Timestamp", strconv.FormatInt(ts, 10))
+	for k, val := range ep.Headers {
+		req.Header.Set(k, val)
+	}
+
+	client := v.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+	if echoed(data) != challenge {
+		return errors.New("response did not echo the challenge")
+	}
+	return nil
+}
+
+// echoed returns the challenge in a verification response body.
+func echoed(data []byte) string {
+	var r struct {
+		Challenge string `json:"challenge"`
+	}
+	if json.Unmarshal(data, &r) == nil && r.Challenge != "" {
+		return r.Challenge
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func newChallenge() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate challenge: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,59 @@
+package endpoint_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/xraph/relay/endpoint"
+	"github.com/xraph/relay/signature"
+)
+
+func TestHTTPVerifier(t *testing.T) {
+	const secret = "whsec_test"
+
+	tests := []struct {
+		name    string
+		respond func(w http.ResponseWriter, challenge string)
+		wantErr bool
+	}{
+		{"raw echo", func(w http.ResponseWriter, c string) { io.WriteString(w, c) }, false},                        //nolint:errcheck // test server
+		{"json echo", func(w http.ResponseWriter, c string) { io.WriteString(w, `{"challenge":"`+c+`"}`) }, false}, //nolint:errcheck // test server
+		{"wrong echo", func(w http.ResponseWriter, _ string) { io.WriteString(w, "ok") }, true},                    //nolint:errcheck // test server
+		{"error status", func(w http.ResponseWriter, c string) {
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, c) //nolint:errcheck // test server
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				ts, _ := strconv.ParseInt(r.Header.Get("X-Relay-Timestamp"), 10, 64)
+				if !signature.Verify(body, secret, ts, r.Header.Get("X-Relay-Signature")) {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				var req struct {
+					Type      string `json:"type"`
+					Challenge string `json:"challenge"`
+				}
+				if err := json.Unmarshal(body, &req); err != nil || req.Type != endpoint.VerificationEventType {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				tt.respond(w, req.Challenge)
+			}))
+			defer srv.Close()
+
+			err := (&endpoint.HTTPVerifier{}).Verify(ctx(), &endpoint.Endpoint{URL: srv.URL, Secret: secret})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package relay
+
+import (
+	"context"
+	"time"
+
+	"github.com/xraph/relay/health"
+	"github.com/xraph/relay/id"
+)
+
+// EndpointHealth is an endpoint's delivery health along with whether, and
+// why, it is disabled.
+type EndpointHealth struct {
+	health.Health
+
+	Enabled bool `json:"enabled"`
+
+	// DisabledReason and DisabledAt are set when the health policy, or a
+	// 410 Gone response, disabled the endpoint.
+	DisabledReason string     `json:"disabled_reason,omitempty"`
+	DisabledAt     *time.Time `json:"disabled_at,omitempty"`
+}
+
+// EndpointHealth returns an endpoint's delivery health. The rolling
+// statistics cover the attempts this instance made since it started; they
+// are not shared between instances.
+func (r *Relay) EndpointHealth(ctx context.Context, epID id.ID) (*EndpointHealth, error) {
+	ep, err := r.store.GetEndpoint(ctx, epID)
+	if err != nil {
+		return nil, err
+	}
+	return &EndpointHealth{
+		Health:         r.health.Get(epID),
+		Enabled:        ep.Enabled,
+		DisabledReason: ep.DisabledReason,
+		DisabledAt:     ep.DisabledAt,
+	}, nil
+}
@@ -1,6 +1,10 @@
 package relay
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/xraph/relay/catalog"
+)
 
 // Sentinel errors returned by Relay operations.
 var (
@@ -11,14 +15,24 @@ var (
 	ErrEndpointNotFound = errors.New("relay: endpoint not found")
 
 	// ErrEventTypeNotFound is returned when an event type is not registered in the catalog.
-	ErrEventTypeNotFound = errors.New("relay: event type not found")
+	ErrEventTypeNotFound = catalog.ErrEventTypeNotFound
 
 	// ErrEventTypeDeprecated is returned when sending an event with a deprecated type.
 	ErrEventTypeDeprecated = errors.New("relay: event type is deprecated")
 
+	// ErrEventVersionNotFound is returned when sending an event with a version its type does not have.
+	ErrEventVersionNotFound = errors.New("relay: event type version not found")
+
 	// ErrPayloadValidationFailed is returned when event data fails JSON Schema validation.
 	ErrPayloadValidationFailed = errors.New("relay: payload validation failed")
 
+	// ErrNoTestPayload is returned by SendTest when no payload is given and
+	// the event type has no example.
+	ErrNoTestPayload = errors.New("relay: no test payload and the event type has no example")
+
+	// ErrTestEvent is returned by Resend for events recorded by SendTest.
+	ErrTestEvent = errors.New("relay: test events cannot be resent")
+
 	// ErrDuplicateIdempotencyKey is returned when an event with the same idempotency key already exists.
 	ErrDuplicateIdempotencyKey = errors.New("relay: duplicate idempotency key")
 
@@ -37,6 +51,20 @@ var (
 	// ErrDeliveryNotFound is returned when a delivery cannot be found.
 	ErrDeliveryNotFound = errors.New("relay: delivery not found")
 
+	// ErrDeliveryNotPending is returned when retrying or cancelling a
+	// delivery that is being attempted or has completed.
+	ErrDeliveryNotPending = errors.New("relay: delivery is not pending")
+
 	// ErrEventNotFound is returned when an event cannot be found.
 	ErrEventNotFound = errors.New("relay: event not found")
+
+	// ErrAPIKeyNotFound is returned when an API key cannot be found.
+	ErrAPIKeyNotFound = errors.New("relay: api key not found")
+
+	// ErrTxUnsupported is returned by SendTx when the store cannot join a caller
+	// transaction, or the transaction belongs to a different driver.
+	ErrTxUnsupported = errors.New("relay: store does not support caller transactions")
+
+	// ErrHookRejected is returned when a BeforeSend hook rejects an event.
+	ErrHookRejected = errors.New("relay: rejected by hook")
 )
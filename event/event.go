@@ -23,6 +23,10 @@ type Event struct {
 	// Data is the event payload. Validated against JSON Schema if configured.
 	Data any `json:"data"`
 
+	// Version is the event type version Data conforms to. Defaults to the
+	// type's latest version when sent.
+	Version string `json:"version,omitempty"`
+
 	// ScopeAppID scopes the event to a specific app.
 	ScopeAppID string `json:"scope_app_id,omitempty"`
 
@@ -31,13 +35,29 @@ type Event struct {
 
 	// IdempotencyKey prevents duplicate event processing.
 	IdempotencyKey string `json:"idempotency_key,omitempty"`
+
+	// Test marks a synthetic event recorded by Relay.SendTest. Test events
+	// are not resent or backfilled.
+	Test bool `json:"test,omitempty"`
 }
 
-// ListOpts configures filtering and pagination for event listing.
+// ListOpts configures filtering and pagination for event listing. Lists are
+// ordered by ID, newest first unless Ascending is set.
 type ListOpts struct {
+	// Cursor, when set, starts the list after the item with this ID; see
+	// package page.
+	Cursor id.ID
 	Offset int
 	Limit  int
 	Type   string
 	From   *time.Time
 	To     *time.Time
+	// ScopeAppID and ScopeOrgID, when set, only list events with that
+	// scope.
+	ScopeAppID string
+	ScopeOrgID string
+	// Ascending lists events oldest first.
+	Ascending bool
+	// ExcludeTest leaves out events recorded by Relay.SendTest.
+	ExcludeTest bool
 }
@@ -14,9 +14,20 @@ type Store interface {
 	// GetEvent returns an event by ID.
 	GetEvent(ctx context.Context, evtID id.ID) (*Event, error)
 
+	// GetEventByIdempotencyKey returns the event stored under an idempotency
+	// key, or relay.ErrEventNotFound.
+	GetEventByIdempotencyKey(ctx context.Context, key string) (*Event, error)
+
 	// ListEvents returns events, optionally filtered by type, tenant, or time range.
 	ListEvents(ctx context.Context, opts ListOpts) ([]*Event, error)
 
 	// ListEventsByTenant returns events for a specific tenant.
 	ListEventsByTenant(ctx context.Context, tenantID string, opts ListOpts) ([]*Event, error)
+
+	// CountEvents returns the number of events ListEvents lists for opts,
+	// ignoring its pagination.
+	CountEvents(ctx context.Context, opts ListOpts) (int64, error)
+
+	// CountEventsByTenant is CountEvents for ListEventsByTenant.
+	CountEventsByTenant(ctx context.Context, tenantID string, opts ListOpts) (int64, error)
 }
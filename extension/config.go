@@ -34,6 +34,26 @@ type Config struct {
 	// (unnamed) kv.Store is used.
 	GroveKV string `json:"grove_kv" mapstructure:"grove_kv" yaml:"grove_kv"`
 
+	// ManifestDir is a directory of event type and seed endpoint definition
+	// files (see package manifest) that is synced into the store on Init,
+	// after migrations.
+	ManifestDir string `json:"manifest_dir" mapstructure:"manifest_dir" yaml:"manifest_dir"`
+
+	// ManifestPrune deprecates registered event types that no file in
+	// ManifestDir declares.
+	ManifestPrune bool `json:"manifest_prune" mapstructure:"manifest_prune" yaml:"manifest_prune"`
+
+	// ManifestDryRun logs the sync plan for ManifestDir without applying it.
+	ManifestDryRun bool `json:"manifest_dry_run" mapstructure:"manifest_dry_run" yaml:"manifest_dry_run"`
+
+	// APIKeyAuth requires admin API callers to present an API key issued
+	// by Relay.APIKeys. Use WithAuthenticator to plug in other credentials.
+	APIKeyAuth bool `json:"api_key_auth" mapstructure:"api_key_auth" yaml:"api_key_auth"`
+
+	// PortalSecret enables the tenant portal under BasePath+"/portal" and
+	// signs its tokens; see Extension.Portal. It must be at least 32 bytes.
+	PortalSecret string `json:"portal_secret" mapstructure:"portal_secret" yaml:"portal_secret"`
+
 	// RequireConfig requires config to be present in YAML files.
 	// If true and no config is found, Register returns an error.
 	RequireConfig bool `json:"-" yaml:"-"`
@@ -69,6 +89,9 @@ func (c Config) ToRelayOptions() []relay.Option {
 	if c.MaxRetries > 0 {
 		opts = append(opts, relay.WithMaxRetries(c.MaxRetries))
 	}
+	if c.MaxReplays != 0 {
+		opts = append(opts, relay.WithMaxReplays(c.MaxReplays))
+	}
 	if len(c.RetrySchedule) > 0 {
 		opts = append(opts, relay.WithRetrySchedule(c.RetrySchedule))
 	}
@@ -78,6 +101,24 @@ func (c Config) ToRelayOptions() []relay.Option {
 	if c.CacheTTL > time.Duration(0) {
 		opts = append(opts, relay.WithCacheTTL(c.CacheTTL))
 	}
+	if c.SchemaCompatibility != "" {
+		opts = append(opts, relay.WithSchemaCompatibility(c.SchemaCompatibility))
+	}
+	if c.Tunnel {
+		opts = append(opts, relay.WithTunnel())
+	}
+	if c.VerifyEndpoints {
+		opts = append(opts, relay.WithEndpointVerification())
+	}
+	if c.HealthPolicy.Enabled() {
+		opts = append(opts, relay.WithHealthPolicy(c.HealthPolicy))
+	}
+	if c.ProbeInterval > 0 {
+		opts = append(opts, relay.WithProbeInterval(c.ProbeInterval))
+	}
+	if c.SystemEvents.Enabled {
+		opts = append(opts, relay.WithSystemEvents(c.SystemEvents))
+	}
 
 	return opts
 }
@@ -15,11 +15,14 @@ import (
 
 	"github.com/xraph/relay"
 	"github.com/xraph/relay/api"
+	"github.com/xraph/relay/auth"
 	"github.com/xraph/relay/catalog"
 	relaydash "github.com/xraph/relay/dashboard"
 	"github.com/xraph/relay/dlq"
 	"github.com/xraph/relay/endpoint"
+	"github.com/xraph/relay/manifest"
 	"github.com/xraph/relay/observability"
+	"github.com/xraph/relay/portal"
 	"github.com/xraph/relay/store"
 	mongostore "github.com/xraph/relay/store/mongo"
 	pgstore "github.com/xraph/relay/store/postgres"
@@ -52,6 +55,8 @@ type Extension struct {
 	r          *relay.Relay
 	api        *api.ForgeAPI
 	opts       []relay.Option
+	authn      auth.Authenticator
+	signer     *portal.Signer
 	useGrove   bool
 	useGroveKV bool
 }
@@ -155,19 +160,67 @@ func (e *Extension) Init(fapp forge.App) error {
 		}
 	}
 
+	// Sync declarative definitions.
+	if e.config.ManifestDir != "" {
+		if err := e.syncManifest(context.Background()); err != nil {
+			return err
+		}
+	}
+
+	if e.config.PortalSecret != "" {
+		e.signer, err = portal.NewSigner([]byte(e.config.PortalSecret))
+		if err != nil {
+			return fmt.Errorf("relay: %w", err)
+		}
+	}
+
 	// Set up Forge API.
-	e.api = api.NewForgeAPI(e.r.Store(), e.r.Catalog(), e.r.Endpoints(), e.r.DLQ(), e.r, fapp.Logger())
+	e.api = api.NewForgeAPI(e.r.Store(), e.r.Catalog(), e.r.Endpoints(), e.r.DLQ(), e.r, fapp.Logger(),
+		api.WithForgeAuth(e.authenticator()))
 	if !e.config.DisableRoutes {
 		basePath := e.config.BasePath
 		if basePath == "" {
 			basePath = "/relay"
 		}
 		e.api.RegisterRoutes(fapp.Router().Group(basePath))
+
+		if e.signer != nil {
+			portalPath := basePath + "/portal"
+			if err := fapp.Router().Handle(portalPath, http.StripPrefix(portalPath, e.PortalHandler())); err != nil {
+				return fmt.Errorf("relay: mount portal: %w", err)
+			}
+		}
 	}
 
 	return nil
 }
 
+// syncManifest reconciles the store with the definition files in
+// ManifestDir and logs the plan.
+func (e *Extension) syncManifest(ctx context.Context) error {
+	m, err := manifest.LoadDir(e.config.ManifestDir)
+	if err != nil {
+		return fmt.Errorf("relay: %w", err)
+	}
+
+	syncer := manifest.NewSyncer(e.r.Catalog(), e.r.Endpoints(), nil)
+	plan, err := syncer.Sync(ctx, m, manifest.Options{
+		Prune:  e.config.ManifestPrune,
+		DryRun: e.config.ManifestDryRun,
+	})
+	if err != nil {
+		return fmt.Errorf("relay: sync %s: %w", e.config.ManifestDir, err)
+	}
+
+	e.Logger().Info("relay: manifest synced",
+		forge.F("dir", e.config.ManifestDir),
+		forge.F("changes", len(plan.Changes)),
+		forge.F("dry_run", e.config.ManifestDryRun),
+		forge.F("plan", plan.String()),
+	)
+	return nil
+}
+
 // Start begins the delivery engine.
 func (e *Extension) Start(ctx context.Context) error {
 	e.MarkStarted()
@@ -198,7 +251,42 @@ func (e *Extension) Handler(
 	epSvc *endpoint.Service,
 	dlqSvc *dlq.Service,
 ) http.Handler {
-	return api.NewHandler(s, cat, epSvc, dlqSvc, nil)
+	return api.NewHandler(s, cat, epSvc, dlqSvc, nil, api.WithRelay(e.r), api.WithAuth(e.authenticator()))
+}
+
+// Portal returns the signer for tenant portal tokens, or nil when no
+// PortalSecret is configured. Issue a token for a tenant and link it to
+// BasePath+"/portal/ui?token=..." or use it as a bearer token on the portal
+// API under BasePath+"/portal".
+func (e *Extension) Portal() *portal.Signer {
+	return e.signer
+}
+
+// PortalHandler returns the tenant portal: the portal API, with the portal
+// page under "/ui". Mount it with http.StripPrefix. It is nil when no
+// PortalSecret is configured.
+func (e *Extension) PortalHandler() http.Handler {
+	if e.signer == nil {
+		return nil
+	}
+	page := relaydash.NewPortal(e.r, e.signer)
+	mux := http.NewServeMux()
+	mux.Handle("/ui", http.StripPrefix("/ui", page))
+	mux.Handle("/ui/", http.StripPrefix("/ui", page))
+	mux.Handle("/", api.NewPortalHandler(e.r, e.signer, nil))
+	return mux
+}
+
+// authenticator returns the admin API authenticator, or nil when the API
+// is open.
+func (e *Extension) authenticator() auth.Authenticator {
+	if e.authn != nil {
+		return e.authn
+	}
+	if e.config.APIKeyAuth {
+		return auth.APIKeys(e.r.APIKeys())
+	}
+	return nil
 }
 
 // RegisterRoutes registers all Relay API routes into a Forge router
@@ -270,6 +358,9 @@ func (e *Extension) loadConfiguration() error {
 		forge.F("base_path", e.config.BasePath),
 		forge.F("grove_database", e.config.GroveDatabase),
 		forge.F("grove_kv", e.config.GroveKV),
+		forge.F("manifest_dir", e.config.ManifestDir),
+		forge.F("api_key_auth", e.config.APIKeyAuth),
+		forge.F("portal", e.config.PortalSecret != ""),
 	)
 
 	return nil
@@ -333,6 +424,9 @@ func (e *Extension) mergeWithDefaults(cfg Config) Config {
 	if cfg.MaxRetries == 0 {
 		cfg.MaxRetries = defaults.MaxRetries
 	}
+	if cfg.MaxReplays == 0 {
+		cfg.MaxReplays = defaults.MaxReplays
+	}
 	if len(cfg.RetrySchedule) == 0 {
 		cfg.RetrySchedule = defaults.RetrySchedule
 	}
@@ -342,6 +436,9 @@ func (e *Extension) mergeWithDefaults(cfg Config) Config {
 	if cfg.CacheTTL == 0 {
 		cfg.CacheTTL = defaults.CacheTTL
 	}
+	if cfg.ProbeInterval == 0 {
+		cfg.ProbeInterval = defaults.ProbeInterval
+	}
 	return cfg
 }
 
@@ -355,6 +452,21 @@ func (e *Extension) mergeConfigurations(yamlConfig, programmaticConfig Config) C
 	if programmaticConfig.DisableMigrate {
 		yamlConfig.DisableMigrate = true
 	}
+	if programmaticConfig.ManifestPrune {
+		yamlConfig.ManifestPrune = true
+	}
+	if programmaticConfig.ManifestDryRun {
+		yamlConfig.ManifestDryRun = true
+	}
+	if programmaticConfig.Tunnel {
+		yamlConfig.Tunnel = true
+	}
+	if programmaticConfig.VerifyEndpoints {
+		yamlConfig.VerifyEndpoints = true
+	}
+	if programmaticConfig.APIKeyAuth {
+		yamlConfig.APIKeyAuth = true
+	}
 
 	// String fields: YAML takes precedence.
 	if yamlConfig.BasePath == "" && programmaticConfig.BasePath != "" {
@@ -366,6 +478,15 @@ func (e *Extension) mergeConfigurations(yamlConfig, programmaticConfig Config) C
 	if yamlConfig.GroveKV == "" && programmaticConfig.GroveKV != "" {
 		yamlConfig.GroveKV = programmaticConfig.GroveKV
 	}
+	if yamlConfig.ManifestDir == "" && programmaticConfig.ManifestDir != "" {
+		yamlConfig.ManifestDir = programmaticConfig.ManifestDir
+	}
+	if yamlConfig.PortalSecret == "" && programmaticConfig.PortalSecret != "" {
+		yamlConfig.PortalSecret = programmaticConfig.PortalSecret
+	}
+	if yamlConfig.SchemaCompatibility == "" && programmaticConfig.SchemaCompatibility != "" {
+		yamlConfig.SchemaCompatibility = programmaticConfig.SchemaCompatibility
+	}
 
 	// Duration/int fields: YAML takes precedence, programmatic fills gaps.
 	if yamlConfig.Concurrency == 0 && programmaticConfig.Concurrency != 0 {
@@ -386,6 +507,9 @@ func (e *Extension) mergeConfigurations(yamlConfig, programmaticConfig Config) C
 	if yamlConfig.MaxRetries == 0 && programmaticConfig.MaxRetries != 0 {
 		yamlConfig.MaxRetries = programmaticConfig.MaxRetries
 	}
+	if yamlConfig.MaxReplays == 0 && programmaticConfig.MaxReplays != 0 {
+		yamlConfig.MaxReplays = programmaticConfig.MaxReplays
+	}
 	if len(yamlConfig.RetrySchedule) == 0 && len(programmaticConfig.RetrySchedule) > 0 {
 		yamlConfig.RetrySchedule = programmaticConfig.RetrySchedule
 	}
@@ -395,6 +519,15 @@ func (e *Extension) mergeConfigurations(yamlConfig, programmaticConfig Config) C
 	if yamlConfig.CacheTTL == 0 && programmaticConfig.CacheTTL != 0 {
 		yamlConfig.CacheTTL = programmaticConfig.CacheTTL
 	}
+	if !yamlConfig.HealthPolicy.Enabled() && programmaticConfig.HealthPolicy.Enabled() {
+		yamlConfig.HealthPolicy = programmaticConfig.HealthPolicy
+	}
+	if yamlConfig.ProbeInterval == 0 && programmaticConfig.ProbeInterval != 0 {
+		yamlConfig.ProbeInterval = programmaticConfig.ProbeInterval
+	}
+	if !yamlConfig.SystemEvents.Enabled && programmaticConfig.SystemEvents.Enabled {
+		yamlConfig.SystemEvents = programmaticConfig.SystemEvents
+	}
 
 	// Fill remaining zeros with defaults.
 	return e.mergeWithDefaults(yamlConfig)
@@ -2,6 +2,7 @@ package extension
 
 import (
 	"github.com/xraph/relay"
+	"github.com/xraph/relay/auth"
 	"github.com/xraph/relay/store"
 )
 
@@ -91,3 +92,34 @@ func WithGroveKV(name string) ExtOption {
 		e.useGroveKV = true
 	}
 }
+
+// WithAuthenticator requires admin API callers to authenticate with a, for
+// example auth.Chain(auth.APIKeys(svc), auth.Bearer(verifyJWT)).
+func WithAuthenticator(a auth.Authenticator) ExtOption {
+	return func(e *Extension) {
+		e.authn = a
+	}
+}
+
+// WithAPIKeyAuth requires admin API callers to present an API key.
+func WithAPIKeyAuth() ExtOption {
+	return func(e *Extension) {
+		e.config.APIKeyAuth = true
+	}
+}
+
+// WithPortalSecret enables the tenant portal, signing its tokens with
+// secret.
+func WithPortalSecret(secret string) ExtOption {
+	return func(e *Extension) {
+		e.config.PortalSecret = secret
+	}
+}
+
+// WithManifestDir syncs the definition files in dir into the store on Init.
+// See package manifest for the file format.
+func WithManifestDir(dir string) ExtOption {
+	return func(e *Extension) {
+		e.config.ManifestDir = dir
+	}
+}
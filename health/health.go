@@ -0,0 +1,140 @@
+// Package health tracks the delivery health of endpoints.
+//
+// A Tracker keeps a rolling window of each endpoint's recent delivery
+// attempts, fed by the delivery engine, and summarizes it as a Health
+// snapshot. A Policy decides from that snapshot when an endpoint has failed
+// for long enough to be disabled.
+//
+// Tracking is in memory and per instance: each instance sees the attempts
+// its own engine makes, and history starts over on restart.
+package health
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/xraph/relay/id"
+)
+
+// Status summarizes an endpoint's health.
+type Status string
+
+const (
+	// StatusUnknown endpoints have no recorded attempts.
+	StatusUnknown Status = "unknown"
+
+	// StatusHealthy endpoints succeed at least 90% of the time and are not
+	// currently failing.
+	StatusHealthy Status = "healthy"
+
+	// StatusDegraded endpoints succeed less than 90% of the time.
+	StatusDegraded Status = "degraded"
+
+	// StatusFailing endpoints have failed their last five or more attempts.
+	StatusFailing Status = "failing"
+)
+
+const (
+	degradedBelow = 0.9
+	failingStreak = 5
+)
+
+// Health is a snapshot of an endpoint's recent delivery attempts.
+type Health struct {
+	EndpointID id.ID  `json:"endpoint_id"`
+	Status     Status `json:"status"`
+
+	// Score is the success rate as a percentage, 0-100. It is 100 for
+	// endpoints with no recorded attempts.
+	Score int `json:"score"`
+
+	// Attempts and Successes count the attempts in the rolling window.
+	Attempts    int     `json:"attempts"`
+	Successes   int     `json:"successes"`
+	SuccessRate float64 `json:"success_rate"`
+
+	// ConsecutiveFailures counts failed attempts since the last success.
+	ConsecutiveFailures int `json:"consecutive_failures"`
+
+	LastSuccessAt *time.Time `json:"last_success_at,omitempty"`
+	LastFailureAt *time.Time `json:"last_failure_at,omitempty"`
+
+	// Since is when the first attempt was recorded.
+	Since *time.Time `json:"since,omitempty"`
+}
+
+// FailingFor returns how long the endpoint has gone without a success while
+// failing: since its last success, or since tracking started if it never
+// succeeded. It is zero when the last attempt succeeded.
+func (h Health) FailingFor(now time.Time) time.Duration {
+	if h.ConsecutiveFailures == 0 {
+		return 0
+	}
+	switch {
+	case h.LastSuccessAt != nil:
+		return now.Sub(*h.LastSuccessAt)
+	case h.Since != nil:
+		return now.Sub(*h.Since)
+	default:
+		return 0
+	}
+}
+
+func (h *Health) summarize() {
+	h.Score = 100
+	h.Status = StatusUnknown
+	if h.Attempts == 0 {
+		return
+	}
+
+	h.SuccessRate = float64(h.Successes) / float64(h.Attempts)
+	h.Score = int(math.Round(h.SuccessRate * 100))
+	switch {
+	case h.ConsecutiveFailures >= failingStreak:
+		h.Status = StatusFailing
+	case h.SuccessRate < degradedBelow:
+		h.Status = StatusDegraded
+	default:
+		h.Status = StatusHealthy
+	}
+}
+
+// Policy decides when a failing endpoint is disabled. Each rule is off at
+// its zero value; an endpoint is disabled when any enabled rule matches.
+type Policy struct {
+	// ConsecutiveFailures disables an endpoint after this many failed
+	// attempts in a row.
+	ConsecutiveFailures int
+
+	// FailingFor, with ConsecutiveFailures, also requires the endpoint to
+	// have gone this long without a success, so a short burst of failures
+	// on a busy endpoint does not disable it.
+	FailingFor time.Duration
+
+	// MinSuccessRate disables an endpoint whose success rate over a full
+	// window falls below it, e.g. 0.05 for 5%.
+	MinSuccessRate float64
+}
+
+// Enabled reports whether any rule is set.
+func (p Policy) Enabled() bool {
+	return p.ConsecutiveFailures > 0 || p.MinSuccessRate > 0
+}
+
+// Evaluate reports whether h warrants disabling the endpoint, and why.
+// window is the Tracker's window size.
+func (p Policy) Evaluate(h Health, window int, now time.Time) (reason string, disable bool) {
+	if p.ConsecutiveFailures > 0 && h.ConsecutiveFailures >= p.ConsecutiveFailures {
+		failingFor := h.FailingFor(now)
+		if failingFor >= p.FailingFor {
+			return fmt.Sprintf("%d consecutive failed deliveries, none successful for %s",
+				h.ConsecutiveFailures, failingFor.Round(time.Second)), true
+		}
+	}
+	if p.MinSuccessRate > 0 && h.Attempts >= window && h.SuccessRate < p.MinSuccessRate {
+		return fmt.Sprintf("success rate %.0f%% over the last %d deliveries is below %.0f%%",
+			h.SuccessRate*100, h.Attempts, p.MinSuccessRate*100), true
+	}
+	return "", false
+}
@@ -0,0 +1,111 @@
+package health_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/xraph/relay/health"
+	"github.com/xraph/relay/id"
+)
+
+func TestTracker(t *testing.T) {
+	tr := health.NewTracker(4)
+	epID := id.NewEndpointID()
+	start := time.Now().UTC()
+
+	if h := tr.Get(epID); h.Status != health.StatusUnknown || h.Score != 100 {
+		t.Fatalf("expected unknown health, got %+v", h)
+	}
+
+	tr.Record(epID, true, start)
+	h := tr.Record(epID, true, start.Add(time.Second))
+	if h.Status != health.StatusHealthy || h.Score != 100 || h.Attempts != 2 {
+		t.Fatalf("expected healthy, got %+v", h)
+	}
+
+	h = tr.Record(epID, false, start.Add(2*time.Second))
+	if h.Status != health.StatusDegraded || h.Score != 67 || h.ConsecutiveFailures != 1 {
+		t.Fatalf("expected degraded at 67, got %+v", h)
+	}
+	if !h.LastSuccessAt.Equal(start.Add(time.Second)) || !h.Since.Equal(start) {
+		t.Fatalf("unexpected timestamps %+v", h)
+	}
+
+	// The window rolls over: the two successes fall out.
+	for i := range 4 {
+		h = tr.Record(epID, false, start.Add(time.Duration(3+i)*time.Second))
+	}
+	if h.Attempts != 4 || h.Successes != 0 || h.Score != 0 || h.ConsecutiveFailures != 5 {
+		t.Fatalf("expected a full window of failures, got %+v", h)
+	}
+	if h.Status != health.StatusFailing {
+		t.Fatalf("expected failing, got %s", h.Status)
+	}
+
+	h = tr.Record(epID, true, start.Add(10*time.Second))
+	if h.ConsecutiveFailures != 0 || h.Successes != 1 {
+		t.Fatalf("expected a success to reset the streak, got %+v", h)
+	}
+
+	tr.Reset(epID)
+	if h := tr.Get(epID); h.Attempts != 0 {
+		t.Fatalf("expected reset history, got %+v", h)
+	}
+}
+
+func TestTrackerDropsIdleEndpoints(t *testing.T) {
+	tr := health.NewTracker(4)
+	idle, active := id.NewEndpointID(), id.NewEndpointID()
+	start := time.Now().UTC()
+
+	tr.Record(idle, false, start)
+	tr.Record(active, true, start)
+	tr.Record(active, true, start.Add(health.IdleTTL-time.Minute))
+
+	// The next attempt after the TTL sweeps out the idle endpoint only.
+	tr.Record(active, true, start.Add(health.IdleTTL+time.Hour))
+	if h := tr.Get(idle); h.Attempts != 0 {
+		t.Fatalf("expected idle endpoint to be dropped, got %+v", h)
+	}
+	if h := tr.Get(active); h.Attempts != 3 {
+		t.Fatalf("expected active endpoint to be kept, got %+v", h)
+	}
+}
+
+func TestPolicyEvaluate(t *testing.T) {
+	now := time.Now().UTC()
+	ago := func(d time.Duration) *time.Time {
+		t := now.Add(-d)
+		return &t
+	}
+
+	tests := []struct {
+		name    string
+		policy  health.Policy
+		health  health.Health
+		disable bool
+	}{
+		{"disabled policy", health.Policy{}, health.Health{Attempts: 10, ConsecutiveFailures: 10}, false},
+		{"streak below threshold", health.Policy{ConsecutiveFailures: 5}, health.Health{ConsecutiveFailures: 4}, false},
+		{"streak at threshold", health.Policy{ConsecutiveFailures: 5}, health.Health{ConsecutiveFailures: 5, Since: ago(time.Minute)}, true},
+		{"streak too recent", health.Policy{ConsecutiveFailures: 5, FailingFor: time.Hour},
+			health.Health{ConsecutiveFailures: 50, LastSuccessAt: ago(time.Minute)}, false},
+		{"streak long enough", health.Policy{ConsecutiveFailures: 5, FailingFor: time.Hour},
+			health.Health{ConsecutiveFailures: 50, LastSuccessAt: ago(2 * time.Hour)}, true},
+		{"low rate, partial window", health.Policy{MinSuccessRate: 0.5}, health.Health{Attempts: 5, SuccessRate: 0.2}, false},
+		{"low rate, full window", health.Policy{MinSuccessRate: 0.5}, health.Health{Attempts: 10, SuccessRate: 0.2}, true},
+		{"rate above minimum", health.Policy{MinSuccessRate: 0.5}, health.Health{Attempts: 10, SuccessRate: 0.8}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, disable := tt.policy.Evaluate(tt.health, 10, now)
+			if disable != tt.disable {
+				t.Fatalf("disable = %v, want %v", disable, tt.disable)
+			}
+			if disable && reason == "" {
+				t.Fatal("expected a reason")
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package health
+
+import (
+	"sync"
+	"time"
+
+	"github.com/xraph/relay/id"
+)
+
+// DefaultWindow is the number of recent attempts a Tracker keeps per
+// endpoint.
+const DefaultWindow = 100
+
+// IdleTTL is how long a Tracker keeps an endpoint with no recorded
+// attempts. Endpoints that were deleted, possibly by another instance, or
+// stopped receiving events are dropped after it, so the tracker does not
+// grow without bound.
+const IdleTTL = 24 * time.Hour
+
+// sweepInterval is how often Record looks for idle endpoints.
+const sweepInterval = time.Hour
+
+// Tracker keeps a rolling window of delivery outcomes per endpoint. It is
+// safe for concurrent use.
+type Tracker struct {
+	window int
+
+	mu        sync.Mutex
+	endpoints map[string]*record
+	lastSweep time.Time
+}
+
+// record is the ring buffer of one endpoint's recent outcomes.
+type record struct {
+	results   []bool
+	next      int
+	successes int
+
+	consecutiveFailures int
+	lastSuccess         time.Time
+	lastFailure         time.Time
+	since               time.Time
+}
+
+// NewTracker creates a Tracker keeping the last window attempts per
+// endpoint, DefaultWindow if window is not positive.
+func NewTracker(window int) *Tracker {
+	if window <= 0 {
+		window = DefaultWindow
+	}
+	return &Tracker{
+		window:    window,
+		endpoints: make(map[string]*record),
+	}
+}
+
+// Window returns the number of attempts kept per endpoint.
+func (t *Tracker) Window() int {
+	return t.window
+}
+
+// Record adds the outcome of a delivery attempt made at at and returns the
+// endpoint's updated health.
+func (t *Tracker) Record(epID id.ID, success bool, at time.Time) Health {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if at.Sub(t.lastSweep) >= sweepInterval {
+		t.sweep(at)
+	}
+
+	r, ok := t.endpoints[epID.String()]
+	if !ok {
+		r = &record{results: make([]bool, 0, t.window), since: at}
+		t.endpoints[epID.String()] = r
+	}
+
+	if len(r.results) < t.window {
+		r.results = append(r.results, success)
+	} else {
+		if r.results[r.next] {
+			r.successes--
+		}
+		r.results[r.next] = success
+		r.next = (r.next + 1) % t.window
+	}
+
+	if success {
+		r.successes++
+		r.consecutiveFailures = 0
+		r.lastSuccess = at
+	} else {
+		r.consecutiveFailures++
+		r.lastFailure = at
+	}
+
+	return r.health(epID)
+}
+
+// Get returns an endpoint's health. Endpoints with no recorded attempts
+// are StatusUnknown.
+func (t *Tracker) Get(epID id.ID) Health {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	r, ok := t.endpoints[epID.String()]
+	if !ok {
+		h := Health{EndpointID: epID}
+		h.summarize()
+		return h
+	}
+	return r.health(epID)
+}
+
+// Reset forgets an endpoint's history, e.g. once it recovers.
+func (t *Tracker) Reset(epID id.ID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.endpoints, epID.String())
+}
+
+// sweep drops the endpoints with no attempt recorded in the IdleTTL
+// before now. t.mu must be held.
+func (t *Tracker) sweep(now time.Time) {
+	t.lastSweep = now
+	cutoff := now.Add(-IdleTTL)
+	for key, r := range t.endpoints {
+		if r.lastSuccess.Before(cutoff) && r.lastFailure.Before(cutoff) {
+			delete(t.endpoints, key)
+		}
+	}
+}
+
+func (r *record) health(epID id.ID) Health {
+	h := Health{
+		EndpointID:          epID,
+		Attempts:            len(r.results),
+		Successes:           r.successes,
+		ConsecutiveFailures: r.consecutiveFailures,
+		Since:               timePtr(r.since),
+		LastSuccessAt:       timePtr(r.lastSuccess),
+		LastFailureAt:       timePtr(r.lastFailure),
+	}
+	h.summarize()
+	return h
+}
+
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
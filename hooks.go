@@ -0,0 +1,185 @@
+package relay
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	log "github.com/xraph/go-utils/log"
+
+	"github.com/xraph/relay/delivery"
+	"github.com/xraph/relay/dlq"
+	"github.com/xraph/relay/endpoint"
+	"github.com/xraph/relay/event"
+	"github.com/xraph/relay/health"
+)
+
+// Hooks are called at the points of an event's life: when it is sent, at
+// each delivery attempt, when a delivery is dead-lettered and when the
+// delivery engine disables an endpoint. They are the place for audit logs,
+// usage metering or custom alerting. Embed NopHooks to implement only some
+// of the methods.
+//
+// Hooks registered with WithHooks are called in registration order. The
+// first Before hook to return an error stops the chain; the hooks after it
+// are not called. A panicking hook is recovered and logged, and counts as
+// an error for Before hooks. Hooks run on the caller's goroutine for
+// sends and on delivery workers for the rest, so slow hooks slow Relay down.
+type Hooks interface {
+	// BeforeSend is called before evt is validated, and may modify it. An
+	// error rejects the send, which returns it wrapped in ErrHookRejected.
+	BeforeSend(ctx context.Context, evt *event.Event) error
+
+	// AfterSend is called once a send that passed BeforeSend returns, with
+	// its result or error; res is nil when err is not. For SendTx it is
+	// called before the caller's transaction commits.
+	AfterSend(ctx context.Context, evt *event.Event, res *SendResult, err error)
+
+	// BeforeAttempt is called with the signed request just before it is
+	// sent to the endpoint, and may modify it; changing the body breaks
+	// the signature. An error fails the attempt without sending it, and the
+	// delivery is retried as after any failed attempt. It also runs for
+	// test sends and recovery probes, so it can add headers every request
+	// needs.
+	BeforeAttempt(ctx context.Context, req *http.Request, d *delivery.Delivery, ep *endpoint.Endpoint, evt *event.Event) error
+
+	// AfterAttempt is called with the outcome of every delivery attempt,
+	// test sends included (d.Test is set).
+	AfterAttempt(ctx context.Context, d *delivery.Delivery, ep *endpoint.Endpoint, evt *event.Event, result delivery.Result)
+
+	// OnDLQ is called after a failed delivery was moved to the DLQ.
+	OnDLQ(ctx context.Context, entry *dlq.Entry)
+
+	// OnEndpointDisabled is called after the delivery engine disabled ep,
+	// after a 410 Gone or by the health policy.
+	OnEndpointDisabled(ctx context.Context, ep *endpoint.Endpoint, reason string)
+}
+
+// NopHooks implements Hooks with methods that do nothing.
+type NopHooks struct{}
+
+var _ Hooks = NopHooks{}
+
+// BeforeSend implements Hooks.
+func (NopHooks) BeforeSend(context.Context, *event.Event) error { return nil }
+
+// AfterSend implements Hooks.
+func (NopHooks) AfterSend(context.Context, *event.Event, *SendResult, error) {}
+
+// BeforeAttempt implements Hooks.
+func (NopHooks) BeforeAttempt(context.Context, *http.Request, *delivery.Delivery, *endpoint.Endpoint, *event.Event) error {
+	return nil
+}
+
+// AfterAttempt implements Hooks.
+func (NopHooks) AfterAttempt(context.Context, *delivery.Delivery, *endpoint.Endpoint, *event.Event, delivery.Result) {
+}
+
+// OnDLQ implements Hooks.
+func (NopHooks) OnDLQ(context.Context, *dlq.Entry) {}
+
+// OnEndpointDisabled implements Hooks.
+func (NopHooks) OnEndpointDisabled(context.Context, *endpoint.Endpoint, string) {}
+
+// hookChain calls the registered hooks in order, isolating their panics.
+// It implements delivery.AttemptHooks and delivery.EndpointNotifier for
+// the engine.
+type hookChain struct {
+	hooks  []Hooks
+	logger log.Logger
+}
+
+func (c *hookChain) empty() bool { return len(c.hooks) == 0 }
+
+// call runs one hook, turning a panic into an error.
+func (c *hookChain) call(name string, fn func() error) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			c.logger.Error("hook panicked",
+				log.String("hook", name),
+				log.Any("panic", p),
+				log.String("stack", string(debug.Stack())),
+			)
+			err = fmt.Errorf("%s hook panicked: %v", name, p)
+		}
+	}()
+	return fn()
+}
+
+func (c *hookChain) beforeSend(ctx context.Context, evt *event.Event) error {
+	for _, h := range c.hooks {
+		if err := c.call("BeforeSend", func() error { return h.BeforeSend(ctx, evt) }); err != nil {
+			return fmt.Errorf("%w: %s", ErrHookRejected, err.Error())
+		}
+	}
+	return nil
+}
+
+func (c *hookChain) afterSend(ctx context.Context, evt *event.Event, res *SendResult, sendErr error) {
+	for _, h := range c.hooks {
+		_ = c.call("AfterSend", func() error {
+			h.AfterSend(ctx, evt, res, sendErr)
+			return nil
+		})
+	}
+}
+
+// BeforeAttempt implements delivery.AttemptHooks.
+func (c *hookChain) BeforeAttempt(ctx context.Context, req *http.Request, d *delivery.Delivery, ep *endpoint.Endpoint, evt *event.Event) error {
+	for _, h := range c.hooks {
+		if err := c.call("BeforeAttempt", func() error { return h.BeforeAttempt(ctx, req, d, ep, evt) }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AfterAttempt implements delivery.AttemptHooks.
+func (c *hookChain) AfterAttempt(ctx context.Context, d *delivery.Delivery, ep *endpoint.Endpoint, evt *event.Event, result delivery.Result) {
+	for _, h := range c.hooks {
+		_ = c.call("AfterAttempt", func() error {
+			h.AfterAttempt(ctx, d, ep, evt, result)
+			return nil
+		})
+	}
+}
+
+func (c *hookChain) onDLQ(ctx context.Context, entry *dlq.Entry) {
+	for _, h := range c.hooks {
+		_ = c.call("OnDLQ", func() error {
+			h.OnDLQ(ctx, entry)
+			return nil
+		})
+	}
+}
+
+// EndpointDisabled implements delivery.EndpointNotifier.
+func (c *hookChain) EndpointDisabled(ctx context.Context, ep *endpoint.Endpoint, reason string) {
+	for _, h := range c.hooks {
+		_ = c.call("OnEndpointDisabled", func() error {
+			h.OnEndpointDisabled(ctx, ep, reason)
+			return nil
+		})
+	}
+}
+
+// EndpointFailing implements delivery.EndpointNotifier. Hooks have no
+// counterpart; system events report failing endpoints.
+func (c *hookChain) EndpointFailing(context.Context, *endpoint.Endpoint, health.Health, delivery.Result) {
+}
+
+// endpointNotifiers tells several notifiers, in order.
+type endpointNotifiers []delivery.EndpointNotifier
+
+func (ns endpointNotifiers) EndpointDisabled(ctx context.Context, ep *endpoint.Endpoint, reason string) {
+	for _, n := range ns {
+		n.EndpointDisabled(ctx, ep, reason)
+	}
+}
+
+func (ns endpointNotifiers) EndpointFailing(ctx context.Context, ep *endpoint.Endpoint, h health.Health, last delivery.Result) {
+	for _, n := range ns {
+		n.EndpointFailing(ctx, ep, h, last)
+	}
+}
@@ -0,0 +1,270 @@
+package relay_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xraph/relay"
+	"github.com/xraph/relay/delivery"
+	"github.com/xraph/relay/dlq"
+	"github.com/xraph/relay/endpoint"
+	"github.com/xraph/relay/event"
+	"github.com/xraph/relay/store/memory"
+)
+
+// recordingHooks records the hook calls it sees, in order.
+type recordingHooks struct {
+	relay.NopHooks
+	name string
+
+	mu    *sync.Mutex
+	calls *[]string
+
+	beforeSend    func(*event.Event) error
+	beforeAttempt func(*http.Request) error
+	dlq           chan *dlq.Entry
+	disabled      chan string
+}
+
+func (h *recordingHooks) record(call string) {
+	h.mu.Lock()
+	*h.calls = append(*h.calls, h.name+"."+call)
+	h.mu.Unlock()
+}
+
+func (h *recordingHooks) BeforeSend(_ context.Context, evt *event.Event) error {
+	h.record("BeforeSend")
+	if h.beforeSend != nil {
+		return h.beforeSend(evt)
+	}
+	return nil
+}
+
+func (h *recordingHooks) AfterSend(_ context.Context, _ *event.Event, _ *relay.SendResult, _ error) {
+	h.record("AfterSend")
+}
+
+func (h *recordingHooks) BeforeAttempt(_ context.Context, req *http.Request, _ *delivery.Delivery, _ *endpoint.Endpoint, _ *event.Event) error {
+	if h.beforeAttempt != nil {
+		return h.beforeAttempt(req)
+	}
+	return nil
+}
+
+func (h *recordingHooks) OnDLQ(_ context.Context, entry *dlq.Entry) {
+	if h.dlq != nil {
+		h.dlq <- entry
+	}
+}
+
+func (h *recordingHooks) OnEndpointDisabled(_ context.Context, _ *endpoint.Endpoint, reason string) {
+	if h.disabled != nil {
+		h.disabled <- reason
+	}
+}
+
+func newHooks(mu *sync.Mutex, calls *[]string, names ...string) []*recordingHooks {
+	hs := make([]*recordingHooks, len(names))
+	for i, name := range names {
+		hs[i] = &recordingHooks{name: name, mu: mu, calls: calls}
+	}
+	return hs
+}
+
+func TestHooksSendOrderAndRejection(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	hs := newHooks(&mu, &calls, "a", "b")
+	hs[0].beforeSend = func(evt *event.Event) error {
+		evt.IdempotencyKey = "set-by-a"
+		return nil
+	}
+	hs[1].beforeSend = func(evt *event.Event) error {
+		if evt.IdempotencyKey != "set-by-a" {
+			t.Errorf("b did not see a's change: %q", evt.IdempotencyKey)
+		}
+		if evt.TenantID == "blocked" {
+			return errors.New("tenant is blocked")
+		}
+		return nil
+	}
+
+	r, err := relay.New(relay.WithStore(memory.New()), relay.WithHooks(hs[0]), relay.WithHooks(hs[1]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	registerType(t, r, "order.created")
+
+	if err := r.Send(ctx(), &event.Event{Type: "order.created", TenantID: "acme", Data: mustJSON(map[string]any{})}); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"a.BeforeSend", "b.BeforeSend", "a.AfterSend", "b.AfterSend"}
+	if len(calls) != len(want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Fatalf("calls = %v, want %v", calls, want)
+		}
+	}
+
+	calls = nil
+	err = r.Send(ctx(), &event.Event{Type: "order.created", TenantID: "blocked", Data: mustJSON(map[string]any{})})
+	if !errors.Is(err, relay.ErrHookRejected) {
+		t.Fatalf("expected ErrHookRejected, got %v", err)
+	}
+	if len(calls) != 2 {
+		t.Fatalf("a rejected send must not call AfterSend, got %v", calls)
+	}
+
+	// Dry runs skip the send hooks.
+	calls = nil
+	if _, err := r.SendWithResult(ctx(), &event.Event{Type: "order.created", TenantID: "blocked", Data: mustJSON(map[string]any{})}, relay.DryRun()); err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 0 {
+		t.Fatalf("a dry run must not run the send hooks, got %v", calls)
+	}
+
+	// Batches run the hooks per event.
+	calls = nil
+	results, err := r.SendBatch(ctx(), []*event.Event{
+		{Type: "order.created", TenantID: "blocked", Data: mustJSON(map[string]any{})},
+		{Type: "order.created", TenantID: "acme", IdempotencyKey: "batch", Data: mustJSON(map[string]any{})},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !errors.Is(results[0].Err, relay.ErrHookRejected) || results[1].Err != nil {
+		t.Fatalf("unexpected batch results %+v", results)
+	}
+	if len(calls) != 6 {
+		t.Fatalf("expected 4 BeforeSend and 2 AfterSend calls, got %v", calls)
+	}
+}
+
+// panickingHooks panics in every hook it implements.
+type panickingHooks struct{ relay.NopHooks }
+
+func (panickingHooks) BeforeSend(context.Context, *event.Event) error { panic("boom") }
+
+func (panickingHooks) AfterSend(context.Context, *event.Event, *relay.SendResult, error) {
+	panic("boom")
+}
+
+func TestHooksPanicIsolation(t *testing.T) {
+	var afterSends int
+	counter := &recordingHooks{name: "c", mu: &sync.Mutex{}, calls: new([]string)}
+
+	r, err := relay.New(relay.WithStore(memory.New()), relay.WithHooks(panickingHooks{}, counter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	registerType(t, r, "order.created")
+
+	err = r.Send(ctx(), &event.Event{Type: "order.created", TenantID: "acme", Data: mustJSON(map[string]any{})})
+	if !errors.Is(err, relay.ErrHookRejected) {
+		t.Fatalf("expected a panicking BeforeSend to reject, got %v", err)
+	}
+	if len(*counter.calls) != 0 {
+		t.Fatalf("hooks after a failed one must not run, got %v", *counter.calls)
+	}
+
+	r, err = relay.New(relay.WithStore(memory.New()), relay.WithHooks(counter, afterPanic{}, counter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	registerType(t, r, "order.created")
+	if err := r.Send(ctx(), &event.Event{Type: "order.created", TenantID: "acme", Data: mustJSON(map[string]any{})}); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range *counter.calls {
+		if c == "c.AfterSend" {
+			afterSends++
+		}
+	}
+	if afterSends != 2 {
+		t.Fatalf("a panicking AfterSend must not stop the others, got %v", *counter.calls)
+	}
+}
+
+// afterPanic panics in AfterSend only.
+type afterPanic struct{ relay.NopHooks }
+
+func (afterPanic) AfterSend(context.Context, *event.Event, *relay.SendResult, error) { panic("boom") }
+
+func TestHooksDelivery(t *testing.T) {
+	auth := make(chan string, 1)
+	ok := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		auth <- r.Header.Get("Authorization")
+	}))
+	defer ok.Close()
+	gone := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer gone.Close()
+
+	h := &recordingHooks{
+		name:  "h",
+		mu:    &sync.Mutex{},
+		calls: new([]string),
+		beforeAttempt: func(req *http.Request) error {
+			req.Header.Set("Authorization", "Bearer token")
+			return nil
+		},
+		dlq:      make(chan *dlq.Entry, 1),
+		disabled: make(chan string, 1),
+	}
+	r, err := relay.New(
+		relay.WithStore(memory.New()),
+		relay.WithPollInterval(10*time.Millisecond),
+		relay.WithHooks(h),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	registerType(t, r, "order.created")
+	for _, url := range []string{ok.URL, gone.URL} {
+		if _, err := r.Endpoints().Create(ctx(), endpoint.Input{TenantID: "acme", URL: url, EventTypes: []string{"order.*"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r.Start(ctx())
+	defer r.Stop(ctx())
+
+	if err := r.Send(ctx(), &event.Event{Type: "order.created", TenantID: "acme", Data: mustJSON(map[string]any{})}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case reason := <-h.disabled:
+		if reason == "" {
+			t.Fatal("expected a disable reason")
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("OnEndpointDisabled not called")
+	}
+	select {
+	case entry := <-h.dlq:
+		if entry.URL != gone.URL || entry.LastStatusCode != http.StatusGone {
+			t.Fatalf("unexpected DLQ entry %+v", entry)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("OnDLQ not called")
+	}
+
+	select {
+	case got := <-auth:
+		if got != "Bearer token" {
+			t.Fatalf("BeforeAttempt header not sent, got %q", got)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("no delivery received")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"database/sql/driver"
 	"encoding/binary"
 	"fmt"
+	"time"
 
 	"go.jetify.com/typeid/v2"
 )
@@ -30,6 +31,9 @@ const (
 	PrefixDelivery  Prefix = "del"
 	PrefixDLQ       Prefix = "dlq"
 	PrefixSecret    Prefix = "whsec"
+	PrefixTunnel    Prefix = "tun"
+	PrefixAPIKey    Prefix = "key"
+	PrefixJob       Prefix = "job"
 )
 
 // ID is the primary identifier type for all Relay entities.
@@ -128,6 +132,15 @@ func NewDLQID() ID { return New(PrefixDLQ) }
 // NewSecretID generates a new unique secret ID.
 func NewSecretID() ID { return New(PrefixSecret) }
 
+// NewTunnelID generates a new unique dev tunnel session ID.
+func NewTunnelID() ID { return New(PrefixTunnel) }
+
+// NewAPIKeyID generates a new unique API key ID.
+func NewAPIKeyID() ID { return New(PrefixAPIKey) }
+
+// NewJobID generates a new unique background job ID.
+func NewJobID() ID { return New(PrefixJob) }
+
 // ──────────────────────────────────────────────────
 // Convenience parsers
 // ──────────────────────────────────────────────────
@@ -147,6 +160,15 @@ func ParseDeliveryID(s string) (ID, error) { return ParseWithPrefix(s, PrefixDel
 // ParseDLQID parses a string and validates the "dlq" prefix.
 func ParseDLQID(s string) (ID, error) { return ParseWithPrefix(s, PrefixDLQ) }
 
+// ParseTunnelID parses a string and validates the "tun" prefix.
+func ParseTunnelID(s string) (ID, error) { return ParseWithPrefix(s, PrefixTunnel) }
+
+// ParseAPIKeyID parses a string and validates the "key" prefix.
+func ParseAPIKeyID(s string) (ID, error) { return ParseWithPrefix(s, PrefixAPIKey) }
+
+// ParseJobID parses a string and validates the "job" prefix.
+func ParseJobID(s string) (ID, error) { return ParseWithPrefix(s, PrefixJob) }
+
 // ParseAny parses a string into an ID without type checking the prefix.
 func ParseAny(s string) (ID, error) { return Parse(s) }
 
@@ -178,6 +200,19 @@ func (i ID) IsNil() bool {
 	return !i.valid
 }
 
+// Time returns the creation time encoded in the ID's UUIDv7 suffix, to the
+// millisecond. Returns the zero time for the Nil ID.
+func (i ID) Time() time.Time {
+	if !i.valid {
+		return time.Time{}
+	}
+
+	b := i.inner.Bytes()
+	ms := int64(binary.BigEndian.Uint64(append([]byte{0, 0}, b[:6]...))) //nolint:gosec // 48-bit timestamp; no overflow
+
+	return time.UnixMilli(ms).UTC()
+}
+
 // MarshalText implements encoding.TextMarshaler.
 func (i ID) MarshalText() ([]byte, error) {
 	if !i.valid {
@@ -3,6 +3,7 @@ package id_test
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/xraph/relay/id"
 )
@@ -19,6 +20,9 @@ func TestConstructors(t *testing.T) {
 		{"DeliveryID", id.NewDeliveryID, "del_"},
 		{"DLQID", id.NewDLQID, "dlq_"},
 		{"SecretID", id.NewSecretID, "whsec_"},
+		{"TunnelID", id.NewTunnelID, "tun_"},
+		{"APIKeyID", id.NewAPIKeyID, "key_"},
+		{"JobID", id.NewJobID, "job_"},
 	}
 
 	for _, tt := range tests {
@@ -170,6 +174,20 @@ func TestNilID(t *testing.T) {
 	}
 }
 
+func TestTime(t *testing.T) {
+	before := time.Now().Truncate(time.Millisecond)
+	i := id.NewEventID()
+	after := time.Now()
+
+	got := i.Time()
+	if got.Before(before) || got.After(after) {
+		t.Errorf("expected time between %v and %v, got %v", before, after, got)
+	}
+	if !id.Nil.Time().IsZero() {
+		t.Errorf("expected zero time for Nil, got %v", id.Nil.Time())
+	}
+}
+
 func TestMarshalUnmarshalText(t *testing.T) {
 	original := id.NewEventID()
 	data, err := original.MarshalText()
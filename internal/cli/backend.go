@@ -0,0 +1,58 @@
+// Package cli implements the relay command-line tool. Commands run against
+// a Backend: the admin API of a running instance, or a store opened
+// directly.
+package cli
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/xraph/relay"
+	"github.com/xraph/relay/catalog"
+	"github.com/xraph/relay/delivery"
+	"github.com/xraph/relay/dlq"
+	"github.com/xraph/relay/endpoint"
+	"github.com/xraph/relay/event"
+	"github.com/xraph/relay/id"
+	"github.com/xraph/relay/tunnel"
+)
+
+// ErrUnsupported is returned by backends for operations they cannot run,
+// such as migrations over the admin API.
+var ErrUnsupported = errors.New("not supported by this backend")
+
+// Backend is what the commands operate on.
+type Backend interface {
+	ListEndpoints(ctx context.Context, tenantID string, opts endpoint.ListOpts) ([]*endpoint.Endpoint, error)
+	CreateEndpoint(ctx context.Context, in endpoint.Input) (*endpoint.Endpoint, error)
+	SetEndpointEnabled(ctx context.Context, epID id.ID, enabled bool) error
+	RotateSecret(ctx context.Context, epID id.ID) (string, error)
+	VerifyEndpoint(ctx context.Context, epID id.ID) (*endpoint.Endpoint, error)
+	EndpointHealth(ctx context.Context, epID id.ID) (*relay.EndpointHealth, error)
+
+	ListEventTypes(ctx context.Context, opts catalog.ListOpts) ([]*catalog.EventType, error)
+	GetEventType(ctx context.Context, name string) (*catalog.EventType, error)
+
+	// Send sends an event and returns it as stored.
+	Send(ctx context.Context, evt *event.Event) (*event.Event, error)
+
+	ListDeliveries(ctx context.Context, epID id.ID, opts delivery.ListOpts) ([]*delivery.Delivery, error)
+
+	ListDLQ(ctx context.Context, opts dlq.ListOpts) ([]*dlq.Entry, error)
+	ReplayDLQ(ctx context.Context, dlqID id.ID) error
+	ReplayDLQRange(ctx context.Context, from, to time.Time) (int64, error)
+	PurgeDLQ(ctx context.Context, before time.Time) (int64, error)
+
+	Stats(ctx context.Context) (*Stats, error)
+	Migrate(ctx context.Context) error
+
+	// Tunnel returns a client for the development tunnel API.
+	Tunnel() (*tunnel.Client, error)
+}
+
+// Stats are queue depths.
+type Stats struct {
+	PendingDeliveries int64 `json:"pending_deliveries"`
+	DLQSize           int64 `json:"dlq_size"`
+}
@@ -0,0 +1,219 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/xraph/relay/store"
+)
+
+// Options configures Run.
+type Options struct {
+	// OpenStore opens the store named by -store; Run closes it. Without
+	// it, only the admin API can be used.
+	OpenStore func(ctx context.Context, dsn string) (store.Store, error)
+
+	// HTTPClient is used for the admin API. Defaults to a client with a
+	// 30 second timeout.
+	HTTPClient *http.Client
+
+	Stdout io.Writer
+	Stderr io.Writer
+
+	// Getenv reads defaults for global flags. Defaults to os.Getenv.
+	Getenv func(string) string
+}
+
+// command is a "<group> <action>" or top-level command.
+type command struct {
+	name  string
+	args  string
+	short string
+	run   func(ctx context.Context, e *env, args []string) error
+}
+
+// env is what commands run with.
+type env struct {
+	cmd     *command
+	backend Backend
+	out     *printer
+	stderr  io.Writer
+}
+
+// errUsage reports a usage error; Run prints the command's usage with it.
+var errUsage = errors.New("usage")
+
+func usageError(format string, args ...any) error {
+	return fmt.Errorf("%w: "+format, append([]any{errUsage}, args...)...)
+}
+
+// Run runs the relay command with args (without the program name) and
+// returns the process exit code.
+func Run(ctx context.Context, args []string, opts Options) int {
+	if opts.Stdout == nil {
+		opts.Stdout = os.Stdout
+	}
+	if opts.Stderr == nil {
+		opts.Stderr = os.Stderr
+	}
+	if opts.Getenv == nil {
+		opts.Getenv = os.Getenv
+	}
+
+	fs := flag.NewFlagSet("relay", flag.ContinueOnError)
+	fs.SetOutput(opts.Stderr)
+	apiURL := fs.String("api", opts.Getenv("RELAY_API_URL"), "admin API base URL (env RELAY_API_URL)")
+	dsn := fs.String("store", opts.Getenv("RELAY_STORE"), "store DSN, e.g. postgres://..., sqlite://relay.db (env RELAY_STORE)")
+	format := fs.String("o", FormatTable, "output format: table or json")
+	var headers headerFlag
+	if token := opts.Getenv("RELAY_API_KEY"); token != "" {
+		headers = append(headers, "Authorization: Bearer "+token)
+	}
+	fs.Var(&headers, "H", `header for admin API requests, "Name: value" (repeatable; env RELAY_API_KEY sets a bearer token)`)
+	fs.Usage = func() { usage(opts.Stderr, fs) }
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if *format != FormatTable && *format != FormatJSON {
+		fmt.Fprintf(opts.Stderr, "relay: unknown output format %q\n", *format)
+		return 2
+	}
+
+	cmd, rest := lookup(fs.Args())
+	if cmd == nil {
+		if len(fs.Args()) > 0 {
+			fmt.Fprintf(opts.Stderr, "relay: unknown command %q\n\n", strings.Join(fs.Args(), " "))
+		}
+		usage(opts.Stderr, fs)
+		return 2
+	}
+
+	backend, closeFn, err := openBackend(ctx, *apiURL, *dsn, headers, opts)
+	if err != nil {
+		fmt.Fprintln(opts.Stderr, "relay:", err)
+		return 1
+	}
+	defer closeFn() //nolint:errcheck // best effort on exit
+
+	e := &env{
+		cmd:     cmd,
+		backend: backend,
+		out:     &printer{w: opts.Stdout, format: *format},
+		stderr:  opts.Stderr,
+	}
+	if err := cmd.run(ctx, e, rest); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		fmt.Fprintf(opts.Stderr, "relay %s: %v\n", cmd.name, err)
+		if errors.Is(err, errUsage) {
+			fmt.Fprintf(opts.Stderr, "usage: relay %s %s\n", cmd.name, cmd.args)
+			return 2
+		}
+		return 1
+	}
+	return 0
+}
+
+func openBackend(ctx context.Context, apiURL, dsn string, headers headerFlag, opts Options) (Backend, func() error, error) {
+	noop := func() error { return nil }
+	switch {
+	case apiURL != "" && dsn != "":
+		return nil, nil, errors.New("set either -api or -store, not both")
+	case apiURL != "":
+		return NewHTTPBackend(apiURL, opts.HTTPClient, headers.header()), noop, nil
+	case dsn != "":
+		if opts.OpenStore == nil {
+			return nil, nil, errors.New("-store is not supported by this build")
+		}
+		s, err := opts.OpenStore(ctx, dsn)
+		if err != nil {
+			return nil, nil, err
+		}
+		b, err := NewStoreBackend(s)
+		if err != nil {
+			s.Close() //nolint:errcheck // already failing
+			return nil, nil, err
+		}
+		return b, s.Close, nil
+	default:
+		return nil, nil, errors.New("set -api (RELAY_API_URL) or -store (RELAY_STORE)")
+	}
+}
+
+// lookup finds the longest command matching the start of args.
+func lookup(args []string) (*command, []string) {
+	for n := 2; n >= 1; n-- {
+		if len(args) < n {
+			continue
+		}
+		name := strings.Join(args[:n], " ")
+		for i := range commands {
+			if commands[i].name == name {
+				return &commands[i], args[n:]
+			}
+		}
+	}
+	return nil, nil
+}
+
+func usage(w io.Writer, fs *flag.FlagSet) {
+	fmt.Fprintln(w, "usage: relay [flags] <command> [args]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	sorted := append([]command(nil), commands...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].name < sorted[j].name })
+	for _, c := range sorted {
+		fmt.Fprintf(w, "  %-24s %s\n", c.name, c.short)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Flags:")
+	fs.PrintDefaults()
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Run "relay <command> -h" for a command's flags.`)
+}
+
+// newFlags returns the flag set of the running command.
+func newFlags(e *env) *flag.FlagSet {
+	fs := flag.NewFlagSet("relay "+e.cmd.name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "usage: relay %s %s\n", e.cmd.name, e.cmd.args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// headerFlag collects repeated -H flags.
+type headerFlag []string
+
+func (h *headerFlag) String() string { return strings.Join(*h, ", ") }
+
+func (h *headerFlag) Set(v string) error {
+	name, _, ok := strings.Cut(v, ":")
+	if !ok || strings.TrimSpace(name) == "" {
+		return fmt.Errorf("header %q must be \"Name: value\"", v)
+	}
+	*h = append(*h, v)
+	return nil
+}
+
+func (h headerFlag) header() http.Header {
+	header := http.Header{}
+	for _, v := range h {
+		name, value, _ := strings.Cut(v, ":")
+		header.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+	return header
+}
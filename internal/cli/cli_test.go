@@ -0,0 +1,194 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/xraph/relay"
+	"github.com/xraph/relay/api"
+	"github.com/xraph/relay/catalog"
+	"github.com/xraph/relay/internal/cli"
+	"github.com/xraph/relay/store"
+	"github.com/xraph/relay/store/memory"
+)
+
+// runner runs relay commands with the given global flags.
+type runner struct {
+	t     *testing.T
+	flags []string
+	opts  cli.Options
+}
+
+func (r *runner) run(args ...string) (stdout, stderr string, code int) {
+	r.t.Helper()
+	var out, errOut bytes.Buffer
+	opts := r.opts
+	opts.Stdout, opts.Stderr = &out, &errOut
+	opts.Getenv = func(string) string { return "" }
+	code = cli.Run(context.Background(), append(append([]string(nil), r.flags...), args...), opts)
+	return out.String(), errOut.String(), code
+}
+
+func (r *runner) ok(args ...string) string {
+	r.t.Helper()
+	out, errOut, code := r.run(args...)
+	if code != 0 {
+		r.t.Fatalf("relay %s: exit %d: %s", strings.Join(args, " "), code, errOut)
+	}
+	return out
+}
+
+func (r *runner) json(v any, args ...string) {
+	r.t.Helper()
+	out := r.ok(append([]string{"-o", "json"}, args...)...)
+	if err := json.Unmarshal([]byte(out), v); err != nil {
+		r.t.Fatalf("relay %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+}
+
+func newStore(t *testing.T) store.Store {
+	t.Helper()
+	s := memory.New()
+	cat := catalog.NewCatalog(s, catalog.Config{}, nil)
+	if _, err := cat.RegisterType(context.Background(), catalog.WebhookDefinition{
+		Name:        "invoice.created",
+		Description: "An invoice was created",
+		Version:     "2025-01-01",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestCommands(t *testing.T) {
+	t.Run("api", func(t *testing.T) {
+		s := newStore(t)
+		r, err := relay.New(relay.WithStore(s))
+		if err != nil {
+			t.Fatal(err)
+		}
+		srv := httptest.NewServer(api.NewHandler(s, r.Catalog(), r.Endpoints(), r.DLQ(), nil, api.WithRelay(r)))
+		defer srv.Close()
+
+		testCommands(t, &runner{t: t, flags: []string{"-api", srv.URL}}, false)
+	})
+
+	t.Run("store", func(t *testing.T) {
+		s := newStore(t)
+		testCommands(t, &runner{
+			t:     t,
+			flags: []string{"-store", "memory://"},
+			opts: cli.Options{
+				OpenStore: func(context.Context, string) (store.Store, error) { return s, nil },
+			},
+		}, true)
+	})
+}
+
+func testCommands(t *testing.T, r *runner, direct bool) {
+	var created struct {
+		ID     string `json:"id"`
+		URL    string `json:"url"`
+		Secret string `json:"secret"`
+	}
+	r.json(&created, "endpoints", "create", "-tenant", "acme", "-url", "https://example.com/hooks", "-events", "invoice.*, user.*")
+	if created.ID == "" || !strings.HasPrefix(created.Secret, "whsec_") {
+		t.Fatalf("expected endpoint with secret, got %+v", created)
+	}
+
+	if out := r.ok("endpoints", "list", "-tenant", "acme"); !strings.Contains(out, "https://example.com/hooks") || !strings.Contains(out, "invoice.*,user.*") {
+		t.Fatalf("unexpected endpoints table:\n%s", out)
+	}
+
+	r.ok("endpoints", "disable", created.ID)
+	var eps []map[string]any
+	r.json(&eps, "endpoints", "list", "-tenant", "acme", "-enabled", "true")
+	if len(eps) != 0 {
+		t.Fatalf("expected no enabled endpoints, got %v", eps)
+	}
+
+	var rotated struct {
+		Secret string `json:"secret"`
+	}
+	r.json(&rotated, "endpoints", "rotate-secret", created.ID)
+	if rotated.Secret == "" || rotated.Secret == created.Secret {
+		t.Fatalf("expected a new secret, got %q", rotated.Secret)
+	}
+
+	if out := r.ok("event-types", "list"); !strings.Contains(out, "invoice.created") || !strings.Contains(out, "2025-01-01") {
+		t.Fatalf("unexpected event types table:\n%s", out)
+	}
+	if out := r.ok("event-types", "get", "invoice.created"); !strings.Contains(out, "An invoice was created") {
+		t.Fatalf("unexpected event type:\n%s", out)
+	}
+
+	var evt struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+	}
+	r.json(&evt, "send", "-tenant", "acme", "-type", "invoice.created", "-data", `{"total":5}`)
+	if evt.ID == "" || evt.Type != "invoice.created" {
+		t.Fatalf("unexpected sent event: %+v", evt)
+	}
+
+	if out := r.ok("deliveries", "list", "-endpoint", created.ID); !strings.HasPrefix(out, "ID") {
+		t.Fatalf("unexpected deliveries table:\n%s", out)
+	}
+
+	var st cli.Stats
+	r.json(&st, "stats")
+	if st.DLQSize != 0 {
+		t.Fatalf("unexpected stats: %+v", st)
+	}
+
+	if out := r.ok("dlq", "purge", "-older-than", "720h"); out != "purged 0 entries\n" {
+		t.Fatalf("unexpected purge output: %q", out)
+	}
+	if out := r.ok("dlq", "replay", "-from", "24h"); out != "replayed 0 entries\n" {
+		t.Fatalf("unexpected replay output: %q", out)
+	}
+
+	_, errOut, code := r.run("listen", "-tenant", "acme", "-forward", "3000")
+	if direct && (code != 1 || !strings.Contains(errOut, "use -api")) {
+		t.Fatalf("expected listen to need the admin API, got exit %d: %s", code, errOut)
+	}
+	if !direct && (code != 1 || !strings.Contains(errOut, "not enabled")) {
+		t.Fatalf("expected listen to report tunnels disabled, got exit %d: %s", code, errOut)
+	}
+
+	_, errOut, code = r.run("migrate")
+	if direct && code != 0 {
+		t.Fatalf("migrate: exit %d: %s", code, errOut)
+	}
+	if !direct && (code != 1 || !strings.Contains(errOut, "not supported")) {
+		t.Fatalf("expected migrate to be unsupported over the API, got exit %d: %s", code, errOut)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	r := &runner{t: t, flags: []string{"-api", "http://127.0.0.1:0"}}
+
+	_, errOut, code := r.run("endpoints", "list")
+	if code != 2 || !strings.Contains(errOut, "-tenant is required") || !strings.Contains(errOut, "usage: relay endpoints list") {
+		t.Fatalf("expected usage error, got exit %d: %s", code, errOut)
+	}
+
+	_, errOut, code = r.run("listen", "-tenant", "acme")
+	if code != 2 || !strings.Contains(errOut, "-tenant and -forward are required") {
+		t.Fatalf("expected listen usage error, got exit %d: %s", code, errOut)
+	}
+
+	_, errOut, code = r.run("bogus")
+	if code != 2 || !strings.Contains(errOut, `unknown command "bogus"`) {
+		t.Fatalf("expected unknown command, got exit %d: %s", code, errOut)
+	}
+
+	_, errOut, code = (&runner{t: t}).run("stats")
+	if code != 1 || !strings.Contains(errOut, "set -api") {
+		t.Fatalf("expected missing backend error, got exit %d: %s", code, errOut)
+	}
+}
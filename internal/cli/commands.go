@@ -0,0 +1,647 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xraph/relay/catalog"
+	"github.com/xraph/relay/delivery"
+	"github.com/xraph/relay/dlq"
+	"github.com/xraph/relay/endpoint"
+	"github.com/xraph/relay/event"
+	"github.com/xraph/relay/id"
+	"github.com/xraph/relay/signature"
+	"github.com/xraph/relay/tunnel"
+)
+
+var commands = []command{
+	{"endpoints list", "-tenant ID [-enabled true|false] [-limit N] [-offset N]", "List a tenant's endpoints", endpointsList},
+	{"endpoints create", "-tenant ID -url URL -events PATTERNS [flags]", "Create an endpoint and print its secret", endpointsCreate},
+	{"endpoints enable", "ID", "Enable an endpoint", endpointsSetEnabled(true)},
+	{"endpoints disable", "ID", "Disable an endpoint", endpointsSetEnabled(false)},
+	{"endpoints rotate-secret", "ID", "Generate a new signing secret", endpointsRotateSecret},
+	{"endpoints verify", "ID", "Run the URL verification handshake", endpointsVerify},
+	{"endpoints health", "ID", "Show an endpoint's delivery health", endpointsHealth},
+	{"event-types list", "[-deprecated] [-group NAME] [-limit N] [-offset N]", "List event types", eventTypesList},
+	{"event-types get", "NAME", "Show an event type and its versions", eventTypesGet},
+	{"send", "-tenant ID -type NAME [-data JSON|@FILE|-] [-version V] [-idempotency-key KEY]", "Send an event", send},
+	{"deliveries list", "-endpoint ID [-state pending|delivered|failed] [-limit N] [-offset N]", "List an endpoint's deliveries", deliveriesList},
+	{"dlq list", "[-tenant ID] [-limit N] [-offset N]", "List dead-lettered deliveries", dlqList},
+	{"dlq replay", "ID... | -from TIME [-to TIME]", "Re-enqueue DLQ entries", dlqReplay},
+	{"dlq purge", "-before TIME | -older-than DURATION", "Delete old DLQ entries", dlqPurge},
+	{"migrate", "", "Run store migrations (requires -store)", migrate},
+	{"stats", "", "Show queue depths", stats},
+	{"listen", "-tenant ID -forward [HOST:]PORT[/PATH] [-events PATTERNS]", "Forward a tenant's deliveries to a local server", listen},
+}
+
+// ---------------------------------------------------------------------------
+// Endpoints
+// ---------------------------------------------------------------------------
+
+func endpointsList(ctx context.Context, e *env, args []string) error {
+	fs := newFlags(e)
+	tenantID := fs.String("tenant", "", "tenant ID (required)")
+	enabled := fs.String("enabled", "", "only enabled (true) or disabled (false) endpoints")
+	limit := fs.Int("limit", 50, "page size")
+	offset := fs.Int("offset", 0, "page offset")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *tenantID == "" {
+		return usageError("-tenant is required")
+	}
+
+	opts := endpoint.ListOpts{Offset: *offset, Limit: *limit}
+	if *enabled != "" {
+		b, err := strconv.ParseBool(*enabled)
+		if err != nil {
+			return usageError("-enabled must be true or false")
+		}
+		opts.Enabled = &b
+	}
+
+	eps, err := e.backend.ListEndpoints(ctx, *tenantID, opts)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, len(eps))
+	for i, ep := range eps {
+		rows[i] = []string{
+			ep.ID.String(),
+			ep.URL,
+			strconv.FormatBool(ep.Enabled),
+			strings.Join(ep.EventTypes, ","),
+			orDash(ep.Version),
+			truncate(ep.Description, 40),
+		}
+	}
+	return e.out.table(eps, []string{"ID", "URL", "ENABLED", "EVENT TYPES", "VERSION", "DESCRIPTION"}, rows)
+}
+
+// createdEndpoint adds the secret, which Endpoint never encodes, to the
+// output of endpoints create.
+type createdEndpoint struct {
+	*endpoint.Endpoint
+	Secret string `json:"secret"`
+}
+
+func endpointsCreate(ctx context.Context, e *env, args []string) error {
+	fs := newFlags(e)
+	in := endpoint.Input{}
+	fs.StringVar(&in.TenantID, "tenant", "", "tenant ID (required)")
+	fs.StringVar(&in.URL, "url", "", "delivery URL (required)")
+	events := fs.String("events", "", "comma-separated event type patterns (required)")
+	fs.StringVar(&in.Description, "description", "", "description")
+	fs.StringVar(&in.Secret, "secret", "", "signing secret (generated if empty)")
+	fs.IntVar(&in.RateLimit, "rate-limit", 0, "deliveries per second, 0 for unlimited")
+	version := fs.String("version", "", "pin payloads to this event type version")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *version != "" {
+		in.Version = version
+	}
+	if in.TenantID == "" || in.URL == "" || *events == "" {
+		return usageError("-tenant, -url and -events are required")
+	}
+	in.EventTypes = splitList(*events)
+
+	// Generate the secret here so that it can be shown: endpoints never
+	// return it once created.
+	if in.Secret == "" {
+		in.Secret = signature.GenerateSecret()
+	}
+
+	ep, err := e.backend.CreateEndpoint(ctx, in)
+	if err != nil {
+		return err
+	}
+	return e.out.message(createdEndpoint{Endpoint: ep, Secret: in.Secret},
+		"created endpoint %s\nsecret: %s", ep.ID, in.Secret)
+}
+
+func endpointsSetEnabled(enabled bool) func(context.Context, *env, []string) error {
+	action := "disabled"
+	if enabled {
+		action = "enabled"
+	}
+	return func(ctx context.Context, e *env, args []string) error {
+		epID, err := endpointArg(args)
+		if err != nil {
+			return err
+		}
+		if err := e.backend.SetEndpointEnabled(ctx, epID, enabled); err != nil {
+			return err
+		}
+		return e.out.message(map[string]any{"id": epID, "enabled": enabled}, "endpoint %s %s", epID, action)
+	}
+}
+
+func endpointsRotateSecret(ctx context.Context, e *env, args []string) error {
+	epID, err := endpointArg(args)
+	if err != nil {
+		return err
+	}
+	secret, err := e.backend.RotateSecret(ctx, epID)
+	if err != nil {
+		return err
+	}
+	return e.out.message(map[string]string{"id": epID.String(), "secret": secret}, "secret: %s", secret)
+}
+
+func endpointsVerify(ctx context.Context, e *env, args []string) error {
+	epID, err := endpointArg(args)
+	if err != nil {
+		return err
+	}
+	ep, err := e.backend.VerifyEndpoint(ctx, epID)
+	if err != nil {
+		return err
+	}
+	return e.out.message(ep, "endpoint %s verified", epID)
+}
+
+func endpointsHealth(ctx context.Context, e *env, args []string) error {
+	epID, err := endpointArg(args)
+	if err != nil {
+		return err
+	}
+	eh, err := e.backend.EndpointHealth(ctx, epID)
+	if err != nil {
+		return err
+	}
+	lastSuccess := "-"
+	if eh.LastSuccessAt != nil {
+		lastSuccess = eh.LastSuccessAt.Format(time.RFC3339)
+	}
+	return e.out.table(eh, []string{"STATUS", "SCORE", "ATTEMPTS", "CONSECUTIVE FAILURES", "LAST SUCCESS", "ENABLED", "DISABLED REASON"}, [][]string{{
+		string(eh.Status),
+		strconv.Itoa(eh.Score),
+		strconv.Itoa(eh.Attempts),
+		strconv.Itoa(eh.ConsecutiveFailures),
+		lastSuccess,
+		strconv.FormatBool(eh.Enabled),
+		orDash(eh.DisabledReason),
+	}})
+}
+
+func endpointArg(args []string) (id.ID, error) {
+	if len(args) != 1 {
+		return id.Nil, usageError("expected one endpoint ID")
+	}
+	epID, err := id.ParseEndpointID(args[0])
+	if err != nil {
+		return id.Nil, fmt.Errorf("invalid endpoint ID %q: %w", args[0], err)
+	}
+	return epID, nil
+}
+
+// ---------------------------------------------------------------------------
+// Event types
+// ---------------------------------------------------------------------------
+
+func eventTypesList(ctx context.Context, e *env, args []string) error {
+	fs := newFlags(e)
+	deprecated := fs.Bool("deprecated", false, "include deprecated types")
+	group := fs.String("group", "", "only types in this group")
+	limit := fs.Int("limit", 50, "page size")
+	offset := fs.Int("offset", 0, "page offset")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	types, err := e.backend.ListEventTypes(ctx, catalog.ListOpts{
+		Offset:            *offset,
+		Limit:             *limit,
+		Group:             *group,
+		IncludeDeprecated: *deprecated,
+	})
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, len(types))
+	for i, et := range types {
+		rows[i] = []string{
+			et.Definition.Name,
+			orDash(et.Definition.Version),
+			orDash(et.Definition.Group),
+			deprecation(et),
+			truncate(et.Definition.Description, 50),
+		}
+	}
+	return e.out.table(types, []string{"NAME", "VERSION", "GROUP", "DEPRECATED", "DESCRIPTION"}, rows)
+}
+
+func eventTypesGet(ctx context.Context, e *env, args []string) error {
+	if len(args) != 1 {
+		return usageError("expected one event type name")
+	}
+	et, err := e.backend.GetEventType(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	def := et.Definition
+	rows := [][]string{
+		{"name", def.Name},
+		{"description", orDash(def.Description)},
+		{"group", orDash(def.Group)},
+		{"deprecated", deprecation(et)},
+	}
+	if len(et.Versions) == 0 {
+		rows = append(rows, []string{"version", orDash(def.Version)})
+	}
+	for _, v := range et.Versions {
+		rows = append(rows, []string{"version", v.Version})
+	}
+	if len(def.Schema) > 0 {
+		rows = append(rows, []string{"schema", truncate(string(def.Schema), 80)})
+	}
+	return e.out.table(et, []string{"FIELD", "VALUE"}, rows)
+}
+
+func deprecation(et *catalog.EventType) string {
+	switch {
+	case !et.IsDeprecated:
+		return "no"
+	case et.SunsetAt != nil:
+		return "sunset " + formatTime(*et.SunsetAt)
+	default:
+		return "yes"
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Events
+// ---------------------------------------------------------------------------
+
+func send(ctx context.Context, e *env, args []string) error {
+	fs := newFlags(e)
+	tenantID := fs.String("tenant", "", "tenant ID (required)")
+	typ := fs.String("type", "", "event type (required)")
+	data := fs.String("data", "{}", "payload: inline JSON, @file, or - for stdin")
+	version := fs.String("version", "", "event type version (default latest)")
+	key := fs.String("idempotency-key", "", "idempotency key")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *tenantID == "" || *typ == "" {
+		return usageError("-tenant and -type are required")
+	}
+
+	payload, err := readPayload(*data)
+	if err != nil {
+		return err
+	}
+
+	evt, err := e.backend.Send(ctx, &event.Event{
+		Type:           *typ,
+		TenantID:       *tenantID,
+		Data:           payload,
+		Version:        *version,
+		IdempotencyKey: *key,
+	})
+	if err != nil {
+		return err
+	}
+	return e.out.message(evt, "sent event %s", evt.ID)
+}
+
+// readPayload reads inline JSON, "@file" or "-" for stdin.
+func readPayload(arg string) (json.RawMessage, error) {
+	var data []byte
+	switch {
+	case arg == "-":
+		b, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, err
+		}
+		data = b
+	case strings.HasPrefix(arg, "@"):
+		b, err := os.ReadFile(arg[1:])
+		if err != nil {
+			return nil, err
+		}
+		data = b
+	default:
+		data = []byte(arg)
+	}
+	if !json.Valid(data) {
+		return nil, errors.New("payload is not valid JSON")
+	}
+	return data, nil
+}
+
+// ---------------------------------------------------------------------------
+// Deliveries
+// ---------------------------------------------------------------------------
+
+func deliveriesList(ctx context.Context, e *env, args []string) error {
+	fs := newFlags(e)
+	endpointID := fs.String("endpoint", "", "endpoint ID (required)")
+	state := fs.String("state", "", "only deliveries in this state")
+	limit := fs.Int("limit", 50, "page size")
+	offset := fs.Int("offset", 0, "page offset")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *endpointID == "" {
+		return usageError("-endpoint is required")
+	}
+	epID, err := endpointArg([]string{*endpointID})
+	if err != nil {
+		return err
+	}
+
+	opts := delivery.ListOpts{Offset: *offset, Limit: *limit}
+	if *state != "" {
+		s := delivery.State(*state)
+		opts.State = &s
+	}
+
+	ds, err := e.backend.ListDeliveries(ctx, epID, opts)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, len(ds))
+	for i, d := range ds {
+		next := "-"
+		if d.State == delivery.StatePending {
+			next = formatTime(d.NextAttemptAt)
+		}
+		rows[i] = []string{
+			d.ID.String(),
+			d.EventID.String(),
+			string(d.State),
+			fmt.Sprintf("%d/%d", d.AttemptCount, d.MaxAttempts),
+			formatInt(d.LastStatusCode),
+			next,
+			truncate(d.LastError, 40),
+		}
+	}
+	return e.out.table(ds, []string{"ID", "EVENT", "STATE", "ATTEMPTS", "STATUS", "NEXT ATTEMPT", "ERROR"}, rows)
+}
+
+// ---------------------------------------------------------------------------
+// DLQ
+// ---------------------------------------------------------------------------
+
+func dlqList(ctx context.Context, e *env, args []string) error {
+	fs := newFlags(e)
+	tenantID := fs.String("tenant", "", "only entries of this tenant")
+	limit := fs.Int("limit", 50, "page size")
+	offset := fs.Int("offset", 0, "page offset")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	entries, err := e.backend.ListDLQ(ctx, dlq.ListOpts{Offset: *offset, Limit: *limit, TenantID: *tenantID})
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]string, len(entries))
+	for i, entry := range entries {
+		rows[i] = []string{
+			entry.ID.String(),
+			entry.EventType,
+			entry.TenantID,
+			entry.URL,
+			strconv.Itoa(entry.AttemptCount),
+			formatInt(entry.LastStatusCode),
+			formatTime(entry.FailedAt),
+			truncate(entry.Error, 40),
+		}
+	}
+	return e.out.table(entries, []string{"ID", "EVENT TYPE", "TENANT", "URL", "ATTEMPTS", "STATUS", "FAILED AT", "ERROR"}, rows)
+}
+
+func dlqReplay(ctx context.Context, e *env, args []string) error {
+	fs := newFlags(e)
+	from := fs.String("from", "", "replay entries that failed from this time (RFC3339, or a duration ago like 24h)")
+	to := fs.String("to", "", "until this time (default now)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *from == "" {
+		if fs.NArg() == 0 {
+			return usageError("pass DLQ entry IDs or -from")
+		}
+		for _, arg := range fs.Args() {
+			dlqID, err := id.ParseDLQID(arg)
+			if err != nil {
+				return fmt.Errorf("invalid DLQ ID %q: %w", arg, err)
+			}
+			if err := e.backend.ReplayDLQ(ctx, dlqID); err != nil {
+				return fmt.Errorf("replay %s: %w", arg, err)
+			}
+		}
+		return e.out.message(map[string]int{"replayed": fs.NArg()}, "replayed %d entries", fs.NArg())
+	}
+
+	if fs.NArg() > 0 {
+		return usageError("pass either DLQ entry IDs or -from, not both")
+	}
+	now := time.Now()
+	start, err := parseTime(*from, now)
+	if err != nil {
+		return usageError("-from: %v", err)
+	}
+	end := now
+	if *to != "" {
+		if end, err = parseTime(*to, now); err != nil {
+			return usageError("-to: %v", err)
+		}
+	}
+
+	n, err := e.backend.ReplayDLQRange(ctx, start, end)
+	if err != nil {
+		return err
+	}
+	return e.out.message(map[string]int64{"replayed": n}, "replayed %d entries", n)
+}
+
+func dlqPurge(ctx context.Context, e *env, args []string) error {
+	fs := newFlags(e)
+	before := fs.String("before", "", "delete entries that failed before this time (RFC3339)")
+	olderThan := fs.Duration("older-than", 0, "delete entries older than this, e.g. 720h")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var cutoff time.Time
+	switch {
+	case *before != "" && *olderThan != 0:
+		return usageError("set either -before or -older-than, not both")
+	case *before != "":
+		t, err := time.Parse(time.RFC3339, *before)
+		if err != nil {
+			return usageError("-before: %v", err)
+		}
+		cutoff = t
+	case *olderThan > 0:
+		cutoff = time.Now().Add(-*olderThan)
+	default:
+		return usageError("-before or -older-than is required")
+	}
+
+	n, err := e.backend.PurgeDLQ(ctx, cutoff)
+	if err != nil {
+		return err
+	}
+	return e.out.message(map[string]int64{"purged": n}, "purged %d entries", n)
+}
+
+// parseTime parses an RFC3339 time, or a duration before now.
+func parseTime(s string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// ---------------------------------------------------------------------------
+// Operations
+// ---------------------------------------------------------------------------
+
+func migrate(ctx context.Context, e *env, args []string) error {
+	if len(args) > 0 {
+		return usageError("migrate takes no arguments")
+	}
+	if err := e.backend.Migrate(ctx); err != nil {
+		return err
+	}
+	return e.out.message(map[string]bool{"migrated": true}, "migrations applied")
+}
+
+func stats(ctx context.Context, e *env, args []string) error {
+	if len(args) > 0 {
+		return usageError("stats takes no arguments")
+	}
+	s, err := e.backend.Stats(ctx)
+	if err != nil {
+		return err
+	}
+	return e.out.table(s, []string{"PENDING DELIVERIES", "DLQ SIZE"}, [][]string{{
+		strconv.FormatInt(s.PendingDeliveries, 10),
+		strconv.FormatInt(s.DLQSize, 10),
+	}})
+}
+
+// ---------------------------------------------------------------------------
+// Development
+// ---------------------------------------------------------------------------
+
+// forwarded is a line of "listen" output.
+type forwarded struct {
+	Time       time.Time `json:"time"`
+	EventType  string    `json:"event_type"`
+	DeliveryID string    `json:"delivery_id"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	LatencyMs  int64     `json:"latency_ms"`
+}
+
+func listen(ctx context.Context, e *env, args []string) error {
+	fs := newFlags(e)
+	tenantID := fs.String("tenant", "", "tenant ID (required)")
+	forward := fs.String("forward", "", "local URL, e.g. 3000 or localhost:3000/webhooks (required)")
+	events := fs.String("events", "*", "comma-separated event type patterns")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *tenantID == "" || *forward == "" {
+		return usageError("-tenant and -forward are required")
+	}
+	target, err := forwardURL(*forward)
+	if err != nil {
+		return usageError("%v", err)
+	}
+
+	client, err := e.backend.Tunnel()
+	if err != nil {
+		return err
+	}
+	client.Forward = target
+
+	var mu sync.Mutex
+	client.OnForward = func(f tunnel.Forwarded) {
+		line := forwarded{
+			Time:       time.Now(),
+			EventType:  f.Request.Header.Get("X-Relay-Event-Type"),
+			DeliveryID: f.Request.Header.Get("X-Relay-Delivery-ID"),
+			StatusCode: f.Response.StatusCode,
+			Error:      f.Response.Error,
+			LatencyMs:  f.Latency.Milliseconds(),
+		}
+		result := strconv.Itoa(line.StatusCode)
+		if line.Error != "" {
+			result = "error: " + line.Error
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		e.out.message(line, "%s  %s  %s  %s  %dms", //nolint:errcheck // nothing to do on a failed write
+			formatTime(line.Time), line.EventType, line.DeliveryID, result, line.LatencyMs)
+	}
+
+	session, err := client.Open(ctx, *tenantID, splitList(*events))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+		if err := client.Close(closeCtx, session.ID); err != nil && !errors.Is(err, tunnel.ErrSessionNotFound) {
+			fmt.Fprintf(e.stderr, "relay listen: close session: %v\n", err)
+		}
+	}()
+
+	mu.Lock()
+	err = e.out.message(session, "forwarding %s deliveries (%s) to %s\nendpoint %s, signing secret: %s\npress Ctrl-C to stop",
+		session.TenantID, strings.Join(session.EventTypes, ","), target, session.EndpointID, session.Secret)
+	mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return client.Listen(ctx, session.ID)
+}
+
+// forwardURL expands a "listen -forward" value to a URL.
+func forwardURL(s string) (string, error) {
+	if _, err := strconv.Atoi(s); err == nil {
+		s = "localhost:" + s
+	}
+	if !strings.Contains(s, "://") {
+		s = "http://" + s
+	}
+	u, err := url.Parse(s)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("invalid -forward URL %q", s)
+	}
+	return u.String(), nil
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
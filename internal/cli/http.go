@@ -0,0 +1,333 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xraph/relay"
+	"github.com/xraph/relay/catalog"
+	"github.com/xraph/relay/delivery"
+	"github.com/xraph/relay/dlq"
+	"github.com/xraph/relay/endpoint"
+	"github.com/xraph/relay/event"
+	"github.com/xraph/relay/id"
+	"github.com/xraph/relay/page"
+	"github.com/xraph/relay/tunnel"
+)
+
+// APIError is a non-2xx response from the admin API.
+type APIError struct {
+	Status  int
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("admin API: %d %s", e.Status, e.Message)
+}
+
+// HTTPBackend talks to the admin API (api.Handler) of a running instance.
+type HTTPBackend struct {
+	baseURL string
+	client  *http.Client
+	header  http.Header
+}
+
+// NewHTTPBackend returns a backend for the admin API mounted at baseURL.
+// header is sent with every request, e.g. for authentication.
+func NewHTTPBackend(baseURL string, client *http.Client, header http.Header) *HTTPBackend {
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &HTTPBackend{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  client,
+		header:  header,
+	}
+}
+
+// do sends a request and decodes a JSON response into out, if non-nil.
+func (b *HTTPBackend) do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	u := b.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return err
+	}
+	for k, v := range b.header {
+		req.Header[k] = v
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var e struct {
+			Error string `json:"error"`
+		}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		if json.Unmarshal(data, &e) != nil || e.Error == "" {
+			e.Error = strings.TrimSpace(string(data))
+		}
+		return &APIError{Status: resp.StatusCode, Message: e.Error}
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func pageQuery(cursor id.ID, offset, limit int) url.Values {
+	q := url.Values{}
+	if !cursor.IsNil() {
+		q.Set("cursor", cursor.String())
+	}
+	if offset > 0 {
+		q.Set("offset", strconv.Itoa(offset))
+	}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	return q
+}
+
+// ListEndpoints implements Backend.
+func (b *HTTPBackend) ListEndpoints(ctx context.Context, tenantID string, opts endpoint.ListOpts) ([]*endpoint.Endpoint, error) {
+	q := pageQuery(opts.Cursor, opts.Offset, opts.Limit)
+	q.Set("tenant_id", tenantID)
+	var p page.Page[*endpoint.Endpoint]
+	if err := b.do(ctx, http.MethodGet, "/endpoints", q, nil, &p); err != nil {
+		return nil, err
+	}
+	eps := p.Data
+	if opts.Enabled != nil {
+		filtered := eps[:0]
+		for _, ep := range eps {
+			if ep.Enabled == *opts.Enabled {
+				filtered = append(filtered, ep)
+			}
+		}
+		eps = filtered
+	}
+	return eps, nil
+}
+
+// CreateEndpoint implements Backend.
+func (b *HTTPBackend) CreateEndpoint(ctx context.Context, in endpoint.Input) (*endpoint.Endpoint, error) {
+	var ep endpoint.Endpoint
+	if err := b.do(ctx, http.MethodPost, "/endpoints", nil, in, &ep); err != nil {
+		return nil, err
+	}
+	return &ep, nil
+}
+
+// SetEndpointEnabled implements Backend.
+func (b *HTTPBackend) SetEndpointEnabled(ctx context.Context, epID id.ID, enabled bool) error {
+	action := "disable"
+	if enabled {
+		action = "enable"
+	}
+	return b.do(ctx, http.MethodPatch, "/endpoints/"+epID.String()+"/"+action, nil, nil, nil)
+}
+
+// RotateSecret implements Backend.
+func (b *HTTPBackend) RotateSecret(ctx context.Context, epID id.ID) (string, error) {
+	var resp struct {
+		Secret string `json:"secret"`
+	}
+	if err := b.do(ctx, http.MethodPost, "/endpoints/"+epID.String()+"/rotate-secret", nil, nil, &resp); err != nil {
+		return "", err
+	}
+	return resp.Secret, nil
+}
+
+// VerifyEndpoint implements Backend.
+func (b *HTTPBackend) VerifyEndpoint(ctx context.Context, epID id.ID) (*endpoint.Endpoint, error) {
+	var ep endpoint.Endpoint
+	if err := b.do(ctx, http.MethodPost, "/endpoints/"+epID.String()+"/verify", nil, nil, &ep); err != nil {
+		return nil, err
+	}
+	return &ep, nil
+}
+
+// EndpointHealth implements Backend.
+func (b *HTTPBackend) EndpointHealth(ctx context.Context, epID id.ID) (*relay.EndpointHealth, error) {
+	var eh relay.EndpointHealth
+	if err := b.do(ctx, http.MethodGet, "/endpoints/"+epID.String()+"/health", nil, nil, &eh); err != nil {
+		return nil, err
+	}
+	return &eh, nil
+}
+
+// ListEventTypes implements Backend.
+func (b *HTTPBackend) ListEventTypes(ctx context.Context, opts catalog.ListOpts) ([]*catalog.EventType, error) {
+	q := pageQuery(opts.Cursor, opts.Offset, opts.Limit)
+	if opts.Group != "" {
+		q.Set("group", opts.Group)
+	}
+	if opts.IncludeDeprecated {
+		q.Set("include_deprecated", "true")
+	}
+	var p page.Page[*catalog.EventType]
+	if err := b.do(ctx, http.MethodGet, "/event-types", q, nil, &p); err != nil {
+		return nil, err
+	}
+	return p.Data, nil
+}
+
+// GetEventType implements Backend.
+func (b *HTTPBackend) GetEventType(ctx context.Context, name string) (*catalog.EventType, error) {
+	var et catalog.EventType
+	if err := b.do(ctx, http.MethodGet, "/event-types/"+url.PathEscape(name), nil, nil, &et); err != nil {
+		return nil, err
+	}
+	return &et, nil
+}
+
+// Send implements Backend.
+func (b *HTTPBackend) Send(ctx context.Context, evt *event.Event) (*event.Event, error) {
+	data, err := json.Marshal(evt.Data)
+	if err != nil {
+		return nil, err
+	}
+	req := map[string]any{
+		"type":      evt.Type,
+		"tenant_id": evt.TenantID,
+		"data":      json.RawMessage(data),
+	}
+	if evt.Version != "" {
+		req["version"] = evt.Version
+	}
+	if evt.IdempotencyKey != "" {
+		req["idempotency_key"] = evt.IdempotencyKey
+	}
+
+	var raw json.RawMessage
+	if err := b.do(ctx, http.MethodPost, "/events", nil, req, &raw); err != nil {
+		return nil, err
+	}
+
+	// Servers with a relay answer with the send result; those without one
+	// with the stored event.
+	var res relay.SendResult
+	if err := json.Unmarshal(raw, &res); err != nil {
+		return nil, err
+	}
+	evtID := res.EventID
+	if res.Duplicate {
+		evtID = res.OriginalEventID
+	}
+	var sent event.Event
+	if evtID.IsNil() {
+		if err := json.Unmarshal(raw, &sent); err != nil {
+			return nil, err
+		}
+		return &sent, nil
+	}
+	if err := b.do(ctx, http.MethodGet, "/events/"+evtID.String(), nil, nil, &sent); err != nil {
+		return nil, err
+	}
+	return &sent, nil
+}
+
+// ListDeliveries implements Backend.
+func (b *HTTPBackend) ListDeliveries(ctx context.Context, epID id.ID, opts delivery.ListOpts) ([]*delivery.Delivery, error) {
+	q := pageQuery(opts.Cursor, opts.Offset, opts.Limit)
+	if opts.State != nil {
+		q.Set("state", string(*opts.State))
+	}
+	var p page.Page[*delivery.Delivery]
+	if err := b.do(ctx, http.MethodGet, "/endpoints/"+epID.String()+"/deliveries", q, nil, &p); err != nil {
+		return nil, err
+	}
+	return p.Data, nil
+}
+
+// ListDLQ implements Backend.
+func (b *HTTPBackend) ListDLQ(ctx context.Context, opts dlq.ListOpts) ([]*dlq.Entry, error) {
+	q := pageQuery(opts.Cursor, opts.Offset, opts.Limit)
+	if opts.TenantID != "" {
+		q.Set("tenant_id", opts.TenantID)
+	}
+	var p page.Page[*dlq.Entry]
+	if err := b.do(ctx, http.MethodGet, "/dlq", q, nil, &p); err != nil {
+		return nil, err
+	}
+	return p.Data, nil
+}
+
+// ReplayDLQ implements Backend.
+func (b *HTTPBackend) ReplayDLQ(ctx context.Context, dlqID id.ID) error {
+	return b.do(ctx, http.MethodPost, "/dlq/"+dlqID.String()+"/replay", nil, nil, nil)
+}
+
+// ReplayDLQRange implements Backend.
+func (b *HTTPBackend) ReplayDLQRange(ctx context.Context, from, to time.Time) (int64, error) {
+	req := map[string]string{
+		"from": from.Format(time.RFC3339),
+		"to":   to.Format(time.RFC3339),
+	}
+	var resp struct {
+		Replayed int64 `json:"replayed"`
+	}
+	if err := b.do(ctx, http.MethodPost, "/dlq/replay", nil, req, &resp); err != nil {
+		return 0, err
+	}
+	return resp.Replayed, nil
+}
+
+// PurgeDLQ implements Backend.
+func (b *HTTPBackend) PurgeDLQ(ctx context.Context, before time.Time) (int64, error) {
+	q := url.Values{"before": {before.Format(time.RFC3339)}}
+	var resp struct {
+		Purged int64 `json:"purged"`
+	}
+	if err := b.do(ctx, http.MethodDelete, "/dlq", q, nil, &resp); err != nil {
+		return 0, err
+	}
+	return resp.Purged, nil
+}
+
+// Stats implements Backend.
+func (b *HTTPBackend) Stats(ctx context.Context) (*Stats, error) {
+	var s Stats
+	if err := b.do(ctx, http.MethodGet, "/stats", nil, nil, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Migrate implements Backend. Migrations need direct store access.
+func (b *HTTPBackend) Migrate(context.Context) error {
+	return fmt.Errorf("migrate: %w; use -store", ErrUnsupported)
+}
+
+// Tunnel implements Backend.
+func (b *HTTPBackend) Tunnel() (*tunnel.Client, error) {
+	return &tunnel.Client{BaseURL: b.baseURL, Header: b.header, HTTPClient: b.client}, nil
+}
@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/xraph/relay"
+	"github.com/xraph/relay/catalog"
+	"github.com/xraph/relay/delivery"
+	"github.com/xraph/relay/dlq"
+	"github.com/xraph/relay/endpoint"
+	"github.com/xraph/relay/event"
+	"github.com/xraph/relay/id"
+	"github.com/xraph/relay/store"
+	"github.com/xraph/relay/tunnel"
+)
+
+// StoreBackend operates on a store directly, through a Relay instance
+// whose delivery engine is never started. Sent events are fanned out and
+// delivered by whichever instance runs the engine.
+type StoreBackend struct {
+	relay *relay.Relay
+	store store.Store
+}
+
+// NewStoreBackend returns a backend over s.
+func NewStoreBackend(s store.Store) (*StoreBackend, error) {
+	r, err := relay.New(relay.WithStore(s))
+	if err != nil {
+		return nil, err
+	}
+	return &StoreBackend{relay: r, store: s}, nil
+}
+
+// ListEndpoints implements Backend.
+func (b *StoreBackend) ListEndpoints(ctx context.Context, tenantID string, opts endpoint.ListOpts) ([]*endpoint.Endpoint, error) {
+	return b.relay.Endpoints().List(ctx, tenantID, opts)
+}
+
+// CreateEndpoint implements Backend.
+func (b *StoreBackend) CreateEndpoint(ctx context.Context, in endpoint.Input) (*endpoint.Endpoint, error) {
+	return b.relay.Endpoints().Create(ctx, in)
+}
+
+// SetEndpointEnabled implements Backend.
+func (b *StoreBackend) SetEndpointEnabled(ctx context.Context, epID id.ID, enabled bool) error {
+	return b.relay.Endpoints().SetEnabled(ctx, epID, enabled)
+}
+
+// RotateSecret implements Backend.
+func (b *StoreBackend) RotateSecret(ctx context.Context, epID id.ID) (string, error) {
+	return b.relay.Endpoints().RotateSecret(ctx, epID)
+}
+
+// VerifyEndpoint implements Backend.
+func (b *StoreBackend) VerifyEndpoint(ctx context.Context, epID id.ID) (*endpoint.Endpoint, error) {
+	return b.relay.Endpoints().Verify(ctx, epID)
+}
+
+// EndpointHealth implements Backend. The rolling statistics live in the
+// delivering instances, so only the disabled state is meaningful here.
+func (b *StoreBackend) EndpointHealth(ctx context.Context, epID id.ID) (*relay.EndpointHealth, error) {
+	return b.relay.EndpointHealth(ctx, epID)
+}
+
+// ListEventTypes implements Backend.
+func (b *StoreBackend) ListEventTypes(ctx context.Context, opts catalog.ListOpts) ([]*catalog.EventType, error) {
+	return b.relay.Catalog().ListTypes(ctx, opts)
+}
+
+// GetEventType implements Backend.
+func (b *StoreBackend) GetEventType(ctx context.Context, name string) (*catalog.EventType, error) {
+	return b.relay.Catalog().GetType(ctx, name)
+}
+
+// Send implements Backend.
+func (b *StoreBackend) Send(ctx context.Context, evt *event.Event) (*event.Event, error) {
+	if err := b.relay.Send(ctx, evt); err != nil {
+		return nil, err
+	}
+	return evt, nil
+}
+
+// ListDeliveries implements Backend.
+func (b *StoreBackend) ListDeliveries(ctx context.Context, epID id.ID, opts delivery.ListOpts) ([]*delivery.Delivery, error) {
+	return b.store.ListByEndpoint(ctx, epID, opts)
+}
+
+// ListDLQ implements Backend.
+func (b *StoreBackend) ListDLQ(ctx context.Context, opts dlq.ListOpts) ([]*dlq.Entry, error) {
+	return b.relay.DLQ().List(ctx, opts)
+}
+
+// ReplayDLQ implements Backend.
+func (b *StoreBackend) ReplayDLQ(ctx context.Context, dlqID id.ID) error {
+	return b.relay.DLQ().Replay(ctx, dlqID)
+}
+
+// ReplayDLQRange implements Backend.
+func (b *StoreBackend) ReplayDLQRange(ctx context.Context, from, to time.Time) (int64, error) {
+	return b.relay.DLQ().ReplayBulk(ctx, from, to)
+}
+
+// PurgeDLQ implements Backend.
+func (b *StoreBackend) PurgeDLQ(ctx context.Context, before time.Time) (int64, error) {
+	return b.relay.DLQ().Purge(ctx, before)
+}
+
+// Stats implements Backend.
+func (b *StoreBackend) Stats(ctx context.Context) (*Stats, error) {
+	pending, err := b.store.CountPending(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dlqSize, err := b.store.CountDLQ(ctx, dlq.ListOpts{Unreplayed: true})
+	if err != nil {
+		return nil, err
+	}
+	return &Stats{PendingDeliveries: pending, DLQSize: dlqSize}, nil
+}
+
+// Migrate implements Backend.
+func (b *StoreBackend) Migrate(ctx context.Context) error {
+	return b.store.Migrate(ctx)
+}
+
+// Tunnel implements Backend. Tunnel sessions live in a running instance.
+func (b *StoreBackend) Tunnel() (*tunnel.Client, error) {
+	return nil, fmt.Errorf("tunnel: %w; use -api", ErrUnsupported)
+}
@@ -0,0 +1,47 @@
+// Package storetest holds behaviour checks shared by the store test suites,
+// so every backend is held to the same contract.
+package storetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/xraph/relay/catalog"
+	"github.com/xraph/relay/id"
+	"github.com/xraph/relay/internal/entity"
+)
+
+// RegisterKeepsDeprecation checks that re-registering a deprecated event
+// type, as services do with their catalog at startup, leaves it deprecated.
+func RegisterKeepsDeprecation(t *testing.T, s catalog.Store) {
+	t.Helper()
+	ctx := context.Background()
+
+	register := func() {
+		t.Helper()
+		if err := s.RegisterType(ctx, &catalog.EventType{
+			Entity:     entity.New(),
+			ID:         id.NewEventTypeID(),
+			Definition: catalog.WebhookDefinition{Name: "invoice.created", Version: "2025-01-01"},
+		}); err != nil {
+			t.Fatalf("register type: %v", err)
+		}
+	}
+
+	register()
+	sunset := time.Now().Add(time.Hour).UTC().Truncate(time.Millisecond)
+	if err := s.DeprecateType(ctx, "invoice.created", sunset); err != nil {
+		t.Fatalf("deprecate type: %v", err)
+	}
+	register()
+
+	got, err := s.GetType(ctx, "invoice.created")
+	if err != nil {
+		t.Fatalf("get type: %v", err)
+	}
+	if !got.IsDeprecated || got.DeprecatedAt == nil || got.SunsetAt == nil || !got.SunsetAt.Equal(sunset) {
+		t.Fatalf("expected re-register to keep the deprecation with sunset %v, got deprecated=%v sunset=%v",
+			sunset, got.IsDeprecated, got.SunsetAt)
+	}
+}
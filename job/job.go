@@ -0,0 +1,77 @@
+// Package job runs long operations, such as backfilling an endpoint, in
+// the background and tracks their progress.
+//
+// A Runner starts each job in its own goroutine and keeps a snapshot of
+// its counters that callers poll through Get. Jobs are in memory and per
+// instance: their progress can only be read on the instance that started
+// them, and a restart stops them. Finished jobs are forgotten after
+// Retention.
+package job
+
+import (
+	"errors"
+	"time"
+
+	"github.com/xraph/relay/id"
+)
+
+// Retention is how long a finished job stays readable.
+const Retention = 24 * time.Hour
+
+// Kinds of the jobs Relay starts.
+const (
+	KindBackfill  = "backfill"
+	KindDLQReplay = "dlq.replay"
+)
+
+// Errors returned by the Runner.
+var (
+	ErrNotFound   = errors.New("job: not found")
+	ErrNotRunning = errors.New("job: not running")
+)
+
+// State is the lifecycle state of a job.
+type State string
+
+const (
+	// StateRunning indicates the job is in progress.
+	StateRunning State = "running"
+	// StateCompleted indicates the job processed everything it was given.
+	StateCompleted State = "completed"
+	// StateFailed indicates the job stopped on an error, recorded in Error.
+	StateFailed State = "failed"
+	// StateCancelled indicates the job was cancelled before it completed.
+	StateCancelled State = "cancelled"
+)
+
+// Job is a snapshot of a background job.
+type Job struct {
+	ID   id.ID  `json:"id"`
+	Kind string `json:"kind"`
+
+	// TenantID is the tenant the job acts for, empty if it spans tenants.
+	TenantID string `json:"tenant_id,omitempty"`
+
+	// Params are the parameters the job was started with.
+	Params any `json:"params,omitempty"`
+
+	State State `json:"state"`
+
+	// Total is the number of items the job expects to process, zero if
+	// unknown. Processed counts the items handled so far, of which Skipped
+	// were left out and Failed could not be processed.
+	Total     int64 `json:"total"`
+	Processed int64 `json:"processed"`
+	Skipped   int64 `json:"skipped"`
+	Failed    int64 `json:"failed"`
+
+	Error string `json:"error,omitempty"`
+
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// Done reports whether the job has stopped.
+func (j *Job) Done() bool {
+	return j.State != StateRunning
+}
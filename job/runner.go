@@ -0,0 +1,198 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	log "github.com/xraph/go-utils/log"
+
+	"github.com/xraph/relay/id"
+)
+
+// Func is the body of a job. It reports progress through p and should
+// return promptly, with ctx's error, once ctx is cancelled.
+type Func func(ctx context.Context, p *Progress) error
+
+// Runner runs jobs and keeps their snapshots. It is safe for concurrent
+// use.
+type Runner struct {
+	logger log.Logger
+	wg     sync.WaitGroup
+
+	mu   sync.Mutex
+	jobs map[string]*entry
+}
+
+type entry struct {
+	job    Job
+	cancel context.CancelFunc
+}
+
+// NewRunner creates a Runner.
+func NewRunner(logger log.Logger) *Runner {
+	if logger == nil {
+		logger = log.NewNoopLogger()
+	}
+	return &Runner{
+		logger: logger,
+		jobs:   make(map[string]*entry),
+	}
+}
+
+// Start runs fn in the background as a job of kind and returns its
+// initial snapshot. The job's context carries ctx's values but not its
+// cancellation, so a job started from a request outlives the request.
+func (r *Runner) Start(ctx context.Context, kind, tenantID string, params any, fn Func) *Job {
+	jobCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	e := &entry{
+		job: Job{
+			ID:        id.NewJobID(),
+			Kind:      kind,
+			TenantID:  tenantID,
+			Params:    params,
+			State:     StateRunning,
+			CreatedAt: time.Now().UTC(),
+		},
+		cancel: cancel,
+	}
+
+	r.mu.Lock()
+	r.prune(e.job.CreatedAt)
+	r.jobs[e.job.ID.String()] = e
+	snapshot := e.job
+	r.mu.Unlock()
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		defer cancel()
+		err := fn(jobCtx, &Progress{runner: r, entry: e})
+		r.finish(jobCtx, e, err)
+	}()
+
+	return &snapshot
+}
+
+// finish records how a job ended.
+func (r *Runner) finish(ctx context.Context, e *entry, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now().UTC()
+	e.job.CompletedAt = &now
+	switch {
+	case err == nil:
+		e.job.State = StateCompleted
+	case ctx.Err() != nil && errors.Is(err, ctx.Err()):
+		e.job.State = StateCancelled
+	default:
+		e.job.State = StateFailed
+		e.job.Error = err.Error()
+		r.logger.Warn("job failed",
+			log.String("job_id", e.job.ID.String()),
+			log.String("kind", e.job.Kind),
+			log.Error(err),
+		)
+	}
+}
+
+// prune forgets jobs finished more than Retention before now. r.mu must be
+// held.
+func (r *Runner) prune(now time.Time) {
+	for key, e := range r.jobs {
+		if e.job.CompletedAt != nil && now.Sub(*e.job.CompletedAt) > Retention {
+			delete(r.jobs, key)
+		}
+	}
+}
+
+// Get returns a snapshot of a job, or ErrNotFound.
+func (r *Runner) Get(jobID id.ID) (*Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.jobs[jobID.String()]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	snapshot := e.job
+	return &snapshot, nil
+}
+
+// Cancel stops a running job. It returns ErrNotFound for unknown jobs and
+// ErrNotRunning for jobs that have already stopped. The job's state
+// becomes StateCancelled once its Func returns.
+func (r *Runner) Cancel(jobID id.ID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e, ok := r.jobs[jobID.String()]
+	if !ok {
+		return ErrNotFound
+	}
+	if e.job.Done() {
+		return ErrNotRunning
+	}
+	e.cancel()
+	return nil
+}
+
+// Stop cancels every running job and waits for them to return, or for ctx
+// to be done.
+func (r *Runner) Stop(ctx context.Context) {
+	r.mu.Lock()
+	for _, e := range r.jobs {
+		e.cancel()
+	}
+	r.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+}
+
+// Progress updates the counters of a running job.
+type Progress struct {
+	runner *Runner
+	entry  *entry
+}
+
+// SetTotal sets the number of items the job expects to process.
+func (p *Progress) SetTotal(n int64) {
+	p.update(func(j *Job) { j.Total = n })
+}
+
+// Add counts n processed items.
+func (p *Progress) Add(n int64) {
+	p.update(func(j *Job) { j.Processed += n })
+}
+
+// Skip counts n processed items that were left out.
+func (p *Progress) Skip(n int64) {
+	p.update(func(j *Job) {
+		j.Processed += n
+		j.Skipped += n
+	})
+}
+
+// Fail counts n processed items that could not be processed.
+func (p *Progress) Fail(n int64) {
+	p.update(func(j *Job) {
+		j.Processed += n
+		j.Failed += n
+	})
+}
+
+func (p *Progress) update(fn func(*Job)) {
+	p.runner.mu.Lock()
+	fn(&p.entry.job)
+	p.runner.mu.Unlock()
+}
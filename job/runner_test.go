@@ -0,0 +1,110 @@
+package job_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/xraph/relay/id"
+	"github.com/xraph/relay/job"
+)
+
+// wait polls a job until it stops.
+func wait(t *testing.T, r *job.Runner, jobID id.ID) *job.Job {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		j, err := r.Get(jobID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if j.Done() {
+			return j
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("job did not stop")
+	return nil
+}
+
+func TestRunnerCompletes(t *testing.T) {
+	r := job.NewRunner(nil)
+
+	j := r.Start(context.Background(), job.KindBackfill, "acme", nil, func(_ context.Context, p *job.Progress) error {
+		p.SetTotal(4)
+		p.Add(2)
+		p.Skip(1)
+		p.Fail(1)
+		return nil
+	})
+	if j.State != job.StateRunning || j.TenantID != "acme" {
+		t.Fatalf("unexpected initial snapshot %+v", j)
+	}
+
+	got := wait(t, r, j.ID)
+	if got.State != job.StateCompleted || got.CompletedAt == nil {
+		t.Fatalf("expected completed, got %s", got.State)
+	}
+	if got.Total != 4 || got.Processed != 4 || got.Skipped != 1 || got.Failed != 1 {
+		t.Fatalf("unexpected counters %+v", got)
+	}
+
+	if err := r.Cancel(j.ID); !errors.Is(err, job.ErrNotRunning) {
+		t.Fatalf("expected ErrNotRunning, got %v", err)
+	}
+}
+
+func TestRunnerFails(t *testing.T) {
+	r := job.NewRunner(nil)
+
+	j := r.Start(context.Background(), job.KindBackfill, "", nil, func(context.Context, *job.Progress) error {
+		return errors.New("boom")
+	})
+
+	got := wait(t, r, j.ID)
+	if got.State != job.StateFailed || got.Error != "boom" {
+		t.Fatalf("expected failed with error, got %s %q", got.State, got.Error)
+	}
+}
+
+func TestRunnerCancel(t *testing.T) {
+	r := job.NewRunner(nil)
+
+	// The job outlives the context it was started with.
+	ctx, cancel := context.WithCancel(context.Background())
+	j := r.Start(ctx, job.KindBackfill, "", nil, func(ctx context.Context, _ *job.Progress) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	cancel()
+	time.Sleep(10 * time.Millisecond)
+	if got, _ := r.Get(j.ID); got.Done() {
+		t.Fatal("job stopped with the context it was started from")
+	}
+
+	if err := r.Cancel(j.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got := wait(t, r, j.ID); got.State != job.StateCancelled {
+		t.Fatalf("expected cancelled, got %s", got.State)
+	}
+
+	if _, err := r.Get(id.NewJobID()); !errors.Is(err, job.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestRunnerStop(t *testing.T) {
+	r := job.NewRunner(nil)
+
+	j := r.Start(context.Background(), job.KindBackfill, "", nil, func(ctx context.Context, _ *job.Progress) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	r.Stop(context.Background())
+
+	if got, _ := r.Get(j.ID); got.State != job.StateCancelled {
+		t.Fatalf("expected cancelled after Stop, got %s", got.State)
+	}
+}
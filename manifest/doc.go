@@ -0,0 +1,4 @@
+// Package manifest loads event types and seed endpoints from YAML or JSON
+// files and reconciles them with the store, so the catalog can be kept in
+// version control instead of registered in code.
+package manifest
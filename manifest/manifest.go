@@ -0,0 +1,255 @@
+package manifest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/xraph/relay/catalog"
+)
+
+// Manifest is the desired state declared by definition files.
+type Manifest struct {
+	EventTypes []EventTypeSpec
+	Endpoints  []EndpointSpec
+}
+
+// EventTypeSpec declares one version of an event type. Declare several
+// specs with the same Name to keep several versions live.
+type EventTypeSpec struct {
+	Name          string            `yaml:"name"`
+	Description   string            `yaml:"description"`
+	Group         string            `yaml:"group"`
+	Version       string            `yaml:"version"`
+	SchemaVersion string            `yaml:"schema_version"`
+	ScopeAppID    string            `yaml:"scope_app_id"`
+	Metadata      map[string]string `yaml:"metadata"`
+
+	// Schema and Example are given inline or loaded from SchemaFile and
+	// ExampleFile, which are relative to the declaring file.
+	Schema  json.RawMessage `yaml:"-"`
+	Example json.RawMessage `yaml:"-"`
+
+	// Deprecated deprecates the type. SunsetAt is when it stops accepting
+	// events; it implies Deprecated, and a deprecated type without one is
+	// sunset immediately.
+	Deprecated bool       `yaml:"deprecated"`
+	SunsetAt   *time.Time `yaml:"sunset_at"`
+
+	// Source is the file the spec was declared in.
+	Source string `yaml:"-"`
+}
+
+// Definition returns the catalog definition the spec registers.
+func (s *EventTypeSpec) Definition() catalog.WebhookDefinition {
+	return catalog.WebhookDefinition{
+		Name:          s.Name,
+		Description:   s.Description,
+		Group:         s.Group,
+		Schema:        s.Schema,
+		SchemaVersion: s.SchemaVersion,
+		Version:       s.Version,
+		Example:       s.Example,
+	}
+}
+
+// Definitions returns the definitions of all declared event types.
+func (m *Manifest) Definitions() []catalog.WebhookDefinition {
+	defs := make([]catalog.WebhookDefinition, len(m.EventTypes))
+	for i := range m.EventTypes {
+		defs[i] = m.EventTypes[i].Definition()
+	}
+	return defs
+}
+
+// EndpointSpec declares a seed endpoint. Endpoints are identified by
+// TenantID and URL.
+type EndpointSpec struct {
+	TenantID    string            `yaml:"tenant_id"`
+	URL         string            `yaml:"url"`
+	Description string            `yaml:"description"`
+	EventTypes  []string          `yaml:"event_types"`
+	Headers     map[string]string `yaml:"headers"`
+	RateLimit   int               `yaml:"rate_limit"`
+	Version     string            `yaml:"version"`
+	Metadata    map[string]string `yaml:"metadata"`
+
+	// Secret is only used when the endpoint is created. Environment
+	// variables in it are expanded, so it can be written as "${HOOK_SECRET}".
+	Secret string `yaml:"secret"`
+
+	// Enabled, when set, enables or disables the endpoint.
+	Enabled *bool `yaml:"enabled"`
+
+	// Source is the file the spec was declared in.
+	Source string `yaml:"-"`
+}
+
+// file is the on-disk format of a definition file.
+type file struct {
+	EventTypes []eventTypeFile `yaml:"event_types"`
+	Endpoints  []EndpointSpec  `yaml:"endpoints"`
+}
+
+type eventTypeFile struct {
+	EventTypeSpec `yaml:",inline"`
+
+	Schema      any    `yaml:"schema"`
+	SchemaFile  string `yaml:"schema_file"`
+	Example     any    `yaml:"example"`
+	ExampleFile string `yaml:"example_file"`
+}
+
+// LoadDir reads every .yaml, .yml and .json file directly in dir.
+// Subdirectories are not scanned, so schema files can live in dir/schemas.
+func LoadDir(dir string) (*Manifest, error) {
+	return LoadFS(os.DirFS(dir))
+}
+
+// LoadFS is LoadDir for the root of fsys.
+func LoadFS(fsys fs.FS) (*Manifest, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("manifest: %w", err)
+	}
+
+	m := &Manifest{}
+	for _, e := range entries {
+		if e.IsDir() || !isDefinitionFile(e.Name()) {
+			continue
+		}
+		if err := m.load(fsys, e.Name()); err != nil {
+			return nil, err
+		}
+	}
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// LoadFile reads a single definition file. Schema files are resolved
+// relative to it.
+func LoadFile(name string) (*Manifest, error) {
+	m := &Manifest{}
+	if err := m.load(os.DirFS(filepath.Dir(name)), filepath.Base(name)); err != nil {
+		return nil, err
+	}
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func isDefinitionFile(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	default:
+		return false
+	}
+}
+
+// load parses one definition file into m. JSON files are parsed as YAML,
+// of which JSON is a subset.
+func (m *Manifest) load(fsys fs.FS, name string) error {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return fmt.Errorf("manifest: %w", err)
+	}
+
+	var f file
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return fmt.Errorf("manifest: %s: %w", name, err)
+	}
+
+	for i, et := range f.EventTypes {
+		spec := et.EventTypeSpec
+		spec.Source = name
+		if spec.Schema, err = document(fsys, name, et.Schema, et.SchemaFile); err != nil {
+			return fmt.Errorf("manifest: %s: event_types[%d] schema: %w", name, i, err)
+		}
+		if spec.Example, err = document(fsys, name, et.Example, et.ExampleFile); err != nil {
+			return fmt.Errorf("manifest: %s: event_types[%d] example: %w", name, i, err)
+		}
+		m.EventTypes = append(m.EventTypes, spec)
+	}
+
+	for _, ep := range f.Endpoints {
+		ep.Source = name
+		ep.Secret = os.ExpandEnv(ep.Secret)
+		m.Endpoints = append(m.Endpoints, ep)
+	}
+	return nil
+}
+
+// document returns an inline value or the contents of ref as JSON.
+func document(fsys fs.FS, from string, inline any, ref string) (json.RawMessage, error) {
+	if inline != nil && ref != "" {
+		return nil, errors.New("set either the value or the file, not both")
+	}
+	if ref != "" {
+		data, err := fs.ReadFile(fsys, path.Join(path.Dir(from), ref))
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.Unmarshal(data, &inline); err != nil {
+			return nil, fmt.Errorf("%s: %w", ref, err)
+		}
+	}
+	if inline == nil {
+		return nil, nil
+	}
+	return json.Marshal(inline)
+}
+
+// validate checks required fields and duplicates across files.
+func (m *Manifest) validate() error {
+	types := make(map[string]string)
+	for _, et := range m.EventTypes {
+		if et.Name == "" {
+			return fmt.Errorf("manifest: %s: event type name is required", et.Source)
+		}
+		key := et.Name + "@" + et.Version
+		if prev, ok := types[key]; ok {
+			return fmt.Errorf("manifest: %s: %s declared again (first in %s)", et.Source, key, prev)
+		}
+		types[key] = et.Source
+	}
+
+	endpoints := make(map[string]string)
+	for _, ep := range m.Endpoints {
+		if ep.TenantID == "" || ep.URL == "" {
+			return fmt.Errorf("manifest: %s: endpoint tenant_id and url are required", ep.Source)
+		}
+		if len(ep.EventTypes) == 0 {
+			return fmt.Errorf("manifest: %s: endpoint %s needs at least one event type", ep.Source, ep.URL)
+		}
+		key := endpointKey(ep.TenantID, ep.URL)
+		if prev, ok := endpoints[key]; ok {
+			return fmt.Errorf("manifest: %s: endpoint %s declared again (first in %s)", ep.Source, key, prev)
+		}
+		endpoints[key] = ep.Source
+	}
+
+	sort.SliceStable(m.EventTypes, func(i, j int) bool {
+		if m.EventTypes[i].Name != m.EventTypes[j].Name {
+			return m.EventTypes[i].Name < m.EventTypes[j].Name
+		}
+		return m.EventTypes[i].Version < m.EventTypes[j].Version
+	})
+	return nil
+}
+
+func endpointKey(tenantID, url string) string {
+	return tenantID + " " + url
+}
@@ -0,0 +1,238 @@
+package manifest_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/xraph/relay/catalog"
+	"github.com/xraph/relay/endpoint"
+	"github.com/xraph/relay/manifest"
+	"github.com/xraph/relay/store/memory"
+)
+
+func ctx() context.Context { return context.Background() }
+
+const billing = `
+event_types:
+  - name: invoice.created
+    description: An invoice was created
+    group: billing
+    version: 2025-01-01
+    schema_file: schemas/invoice.created.json
+    example:
+      total: 5
+  - name: invoice.voided
+    description: An invoice was voided
+    version: 2025-01-01
+    sunset_at: 2099-01-01T00:00:00Z
+
+endpoints:
+  - tenant_id: t1
+    url: https://example.com/hooks
+    event_types: ["invoice.*"]
+    secret: ${MANIFEST_TEST_SECRET}
+`
+
+func testFS(billingYAML string) fstest.MapFS {
+	return fstest.MapFS{
+		"billing.yaml":                      {Data: []byte(billingYAML)},
+		"users.json":                        {Data: []byte(`{"event_types":[{"name":"user.created","version":"2025-01-01"}]}`)},
+		"README.md":                         {Data: []byte("not a definition file")},
+		"schemas/invoice.created.json":      {Data: []byte(`{"type":"object","properties":{"total":{"type":"number"}}}`)},
+		"schemas/ignored-by-the-loader.yml": {Data: []byte("event_types: [{name: never.loaded}]")},
+	}
+}
+
+func TestLoadFS(t *testing.T) {
+	t.Setenv("MANIFEST_TEST_SECRET", "whsec_test")
+
+	m, err := manifest.LoadFS(testFS(billing))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.EventTypes) != 3 {
+		t.Fatalf("expected 3 event types, got %d", len(m.EventTypes))
+	}
+
+	et := m.EventTypes[0]
+	if et.Name != "invoice.created" || et.Version != "2025-01-01" || et.Source != "billing.yaml" {
+		t.Fatalf("unexpected spec: %+v", et)
+	}
+	if string(et.Schema) != `{"properties":{"total":{"type":"number"}},"type":"object"}` {
+		t.Fatalf("expected schema from file, got %s", et.Schema)
+	}
+	if string(et.Example) != `{"total":5}` {
+		t.Fatalf("expected inline example, got %s", et.Example)
+	}
+	if m.EventTypes[1].SunsetAt == nil {
+		t.Fatal("expected sunset_at to be parsed")
+	}
+	if len(m.Endpoints) != 1 || m.Endpoints[0].Secret != "whsec_test" {
+		t.Fatalf("expected endpoint with expanded secret, got %+v", m.Endpoints)
+	}
+
+	dup := testFS(billing)
+	dup["more.yaml"] = &fstest.MapFile{Data: []byte("event_types: [{name: user.created, version: 2025-01-01}]")}
+	if _, err := manifest.LoadFS(dup); err == nil || !strings.Contains(err.Error(), "declared again") {
+		t.Fatalf("expected duplicate error, got %v", err)
+	}
+}
+
+func TestSync(t *testing.T) {
+	s := memory.New()
+	cat := catalog.NewCatalog(s, catalog.Config{}, nil)
+	eps := endpoint.NewService(s, nil)
+	syncer := manifest.NewSyncer(cat, eps, nil)
+
+	m, err := manifest.LoadFS(testFS(billing))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Dry run plans without applying.
+	plan, err := syncer.Sync(ctx(), m, manifest.Options{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 3 creates, 1 deprecation and 1 endpoint.
+	if len(plan.Changes) != 5 {
+		t.Fatalf("expected 5 changes, got:\n%s", plan)
+	}
+	if types, _ := cat.ListTypes(ctx(), catalog.ListOpts{IncludeDeprecated: true}); len(types) != 0 {
+		t.Fatal("dry run must not register types")
+	}
+
+	if _, err := syncer.Sync(ctx(), m, manifest.Options{}); err != nil {
+		t.Fatal(err)
+	}
+	voided, err := cat.GetType(ctx(), "invoice.voided")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !voided.IsDeprecated || voided.SunsetAt == nil {
+		t.Fatalf("expected invoice.voided to be deprecated, got %+v", voided)
+	}
+	list, err := eps.List(ctx(), "t1", endpoint.ListOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 {
+		t.Fatalf("expected seeded endpoint, got %d", len(list))
+	}
+
+	// Applying again is a no-op.
+	plan, err = syncer.Plan(ctx(), m, manifest.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plan.Empty() {
+		t.Fatalf("expected no changes, got:\n%s", plan)
+	}
+
+	// Edits become updates with a compatibility report.
+	edited := strings.Replace(billing, "An invoice was created", "A new invoice", 1)
+	edited = strings.Replace(edited, `["invoice.*"]`, `["invoice.created"]`, 1)
+	fsys := testFS(edited)
+	fsys["schemas/invoice.created.json"] = &fstest.MapFile{Data: []byte(`{"type":"object"}`)}
+	m, err = manifest.LoadFS(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan, err = syncer.Plan(ctx(), m, manifest.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Changes) != 2 {
+		t.Fatalf("expected 2 updates, got:\n%s", plan)
+	}
+	typeChange := plan.Changes[0]
+	if typeChange.Action != manifest.ActionUpdate || strings.Join(typeChange.Fields, ",") != "description,schema" {
+		t.Fatalf("unexpected event type change: %+v", typeChange)
+	}
+	if typeChange.Compat == nil || typeChange.Compat.Compatibility != catalog.CompatBreaking {
+		t.Fatalf("expected breaking compat report, got %+v", typeChange.Compat)
+	}
+	if plan.Changes[1].Kind != manifest.KindEndpoint || plan.Changes[1].Fields[0] != "event_types" {
+		t.Fatalf("unexpected endpoint change: %+v", plan.Changes[1])
+	}
+	if err := syncer.Apply(ctx(), plan); err != nil {
+		t.Fatal(err)
+	}
+	created, err := cat.GetType(ctx(), "invoice.created")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Definition.Description != "A new invoice" {
+		t.Fatalf("expected updated description, got %q", created.Definition.Description)
+	}
+
+	// Undeclared types are only deprecated when pruning.
+	if _, err := cat.RegisterType(ctx(), catalog.WebhookDefinition{Name: "legacy.event", Version: "2024-01-01"}); err != nil {
+		t.Fatal(err)
+	}
+	plan, err = syncer.Plan(ctx(), m, manifest.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plan.Empty() {
+		t.Fatalf("expected no changes without prune, got:\n%s", plan)
+	}
+	plan, err = syncer.Sync(ctx(), m, manifest.Options{Prune: true, PruneGrace: time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Changes) != 1 || plan.Changes[0].Action != manifest.ActionDeprecate || plan.Changes[0].Name != "legacy.event" {
+		t.Fatalf("expected legacy.event to be pruned, got:\n%s", plan)
+	}
+	legacy, err := cat.GetType(ctx(), "legacy.event")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !legacy.IsDeprecated || legacy.IsSunset(time.Now()) {
+		t.Fatalf("expected pruned type to be deprecated with a grace period, got %+v", legacy)
+	}
+}
+
+func TestSyncOlderVersion(t *testing.T) {
+	s := memory.New()
+	cat := catalog.NewCatalog(s, catalog.Config{}, nil)
+	syncer := manifest.NewSyncer(cat, endpoint.NewService(s, nil), nil)
+
+	m, err := manifest.LoadFS(fstest.MapFS{"billing.yaml": {Data: []byte(`
+event_types:
+  - name: invoice.created
+    description: An invoice was created
+    group: billing
+    version: 2024-06-01
+  - name: invoice.created
+    description: An invoice was issued
+    group: invoicing
+    version: 2025-01-01
+`)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := syncer.Sync(ctx(), m, manifest.Options{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Each entry is compared with the version it names, not the latest.
+	plan, err := syncer.Plan(ctx(), m, manifest.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plan.Empty() {
+		t.Fatalf("expected no changes, got:\n%s", plan)
+	}
+
+	et, err := cat.GetType(ctx(), "invoice.created")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if et.Definition.Description != "An invoice was issued" || et.Definition.Group != "invoicing" {
+		t.Fatalf("expected the latest version's description and group, got %q, %q", et.Definition.Description, et.Definition.Group)
+	}
+}
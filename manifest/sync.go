@@ -0,0 +1,499 @@
+package manifest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	log "github.com/xraph/go-utils/log"
+
+	"github.com/xraph/relay/catalog"
+	"github.com/xraph/relay/endpoint"
+)
+
+// Action is what applying a Change does.
+type Action string
+
+const (
+	// ActionCreate registers a new event type, version or endpoint.
+	ActionCreate Action = "create"
+
+	// ActionUpdate changes an existing event type version or endpoint.
+	ActionUpdate Action = "update"
+
+	// ActionDeprecate deprecates an event type or moves its sunset date.
+	ActionDeprecate Action = "deprecate"
+
+	// ActionUndeprecate clears an event type's deprecation.
+	ActionUndeprecate Action = "undeprecate"
+)
+
+// Resource kinds reported in Change.Kind.
+const (
+	KindEventType = "event_type"
+	KindEndpoint  = "endpoint"
+)
+
+// Change is one step of a Plan.
+type Change struct {
+	Kind   string `json:"kind"`
+	Action Action `json:"action"`
+
+	// Name is the event type name, or "<tenant> <url>" for endpoints.
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+
+	// Fields lists the fields an update changes.
+	Fields []string `json:"fields,omitempty"`
+
+	// SunsetAt is the sunset date set by a deprecation.
+	SunsetAt *time.Time `json:"sunset_at,omitempty"`
+
+	// Compat classifies a schema change to an existing version.
+	Compat *catalog.CompatReport `json:"compat,omitempty"`
+
+	eventType *EventTypeSpec
+	endpoint  *EndpointSpec
+	target    *endpoint.Endpoint
+}
+
+// Plan is the list of changes that reconcile the store with a Manifest.
+type Plan struct {
+	Changes []Change `json:"changes"`
+}
+
+// Empty reports whether the store already matches the manifest.
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// String renders the plan as a diff, one change per line.
+func (p *Plan) String() string {
+	if p.Empty() {
+		return "No changes.\n"
+	}
+
+	var b strings.Builder
+	for _, c := range p.Changes {
+		sign := "~"
+		switch c.Action {
+		case ActionCreate:
+			sign = "+"
+		case ActionDeprecate:
+			sign = "-"
+		}
+
+		name := c.Name
+		if c.Version != "" {
+			name += "@" + c.Version
+		}
+		fmt.Fprintf(&b, "%s %s %s", sign, c.Kind, name)
+
+		var notes []string
+		if c.Action == ActionDeprecate || c.Action == ActionUndeprecate {
+			notes = append(notes, string(c.Action))
+		}
+		if c.SunsetAt != nil {
+			notes = append(notes, "sunset "+c.SunsetAt.UTC().Format(time.RFC3339))
+		}
+		notes = append(notes, c.Fields...)
+		if c.Compat != nil {
+			notes = append(notes, "schema change is "+string(c.Compat.Compatibility))
+		}
+		if len(notes) > 0 {
+			fmt.Fprintf(&b, " (%s)", strings.Join(notes, ", "))
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Options configures Plan and Sync.
+type Options struct {
+	// Prune deprecates registered event types that no manifest declares.
+	// Endpoints are never pruned: tenants create their own.
+	Prune bool
+
+	// PruneGrace is how long pruned event types keep accepting events.
+	// Zero sunsets them immediately.
+	PruneGrace time.Duration
+
+	// DryRun makes Sync return the plan without applying it.
+	DryRun bool
+}
+
+// Syncer reconciles the catalog and endpoints with a Manifest.
+type Syncer struct {
+	catalog   *catalog.Catalog
+	endpoints *endpoint.Service
+	logger    log.Logger
+}
+
+// NewSyncer creates a Syncer. With a Relay instance, pass r.Catalog() and
+// r.Endpoints().
+func NewSyncer(cat *catalog.Catalog, endpoints *endpoint.Service, logger log.Logger) *Syncer {
+	if logger == nil {
+		logger = log.NewNoopLogger()
+	}
+	return &Syncer{
+		catalog:   cat,
+		endpoints: endpoints,
+		logger:    logger,
+	}
+}
+
+// Sync plans the changes m needs and, unless opts.DryRun is set, applies
+// them. The plan is returned either way.
+func (s *Syncer) Sync(ctx context.Context, m *Manifest, opts Options) (*Plan, error) {
+	plan, err := s.Plan(ctx, m, opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.DryRun {
+		return plan, nil
+	}
+	return plan, s.Apply(ctx, plan)
+}
+
+// Plan compares m with the store without changing anything.
+func (s *Syncer) Plan(ctx context.Context, m *Manifest, opts Options) (*Plan, error) {
+	plan := &Plan{Changes: []Change{}}
+
+	if err := s.planEventTypes(ctx, m, opts, plan); err != nil {
+		return nil, err
+	}
+	if err := s.planEndpoints(ctx, m, plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+func (s *Syncer) planEventTypes(ctx context.Context, m *Manifest, opts Options, plan *Plan) error {
+	types, err := s.catalog.ListTypes(ctx, catalog.ListOpts{IncludeDeprecated: true})
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]*catalog.EventType, len(types))
+	for _, et := range types {
+		existing[et.Definition.Name] = et
+	}
+
+	declared := make(map[string]bool)
+	for i := range m.EventTypes {
+		spec := &m.EventTypes[i]
+		et := existing[spec.Name]
+
+		change := Change{Kind: KindEventType, Name: spec.Name, Version: spec.Version, eventType: spec}
+		switch {
+		case et == nil:
+			change.Action = ActionCreate
+		default:
+			ver, ok := et.LookupVersion(spec.Version)
+			if !ok {
+				change.Action = ActionCreate
+				break
+			}
+			change.Action = ActionUpdate
+			change.Fields = eventTypeDiff(et, ver, spec)
+			if slices.Contains(change.Fields, "schema") {
+				report, err := catalog.CompareSchemas(ver.Schema, spec.Schema)
+				if err != nil {
+					return fmt.Errorf("manifest: %s: %w", spec.Source, err)
+				}
+				change.Compat = report
+			}
+		}
+		if change.Action == ActionCreate || len(change.Fields) > 0 {
+			plan.Changes = append(plan.Changes, change)
+		}
+
+		if declared[spec.Name] {
+			continue
+		}
+		declared[spec.Name] = true
+		deprecated, sunsetAt := m.deprecation(spec.Name)
+		if c, ok := deprecationChange(spec.Name, et, deprecated, sunsetAt); ok {
+			plan.Changes = append(plan.Changes, c)
+		}
+	}
+
+	if !opts.Prune {
+		return nil
+	}
+	now := time.Now()
+	for _, et := range types {
+		if declared[et.Definition.Name] || et.IsDeprecated {
+			continue
+		}
+		sunset := now.Add(opts.PruneGrace).UTC()
+		plan.Changes = append(plan.Changes, Change{
+			Kind:     KindEventType,
+			Action:   ActionDeprecate,
+			Name:     et.Definition.Name,
+			SunsetAt: &sunset,
+		})
+	}
+	return nil
+}
+
+// deprecation returns the deprecation declared for the event type name by
+// any of its specs: whether it is deprecated, and the sunset date.
+func (m *Manifest) deprecation(name string) (bool, *time.Time) {
+	for _, spec := range m.EventTypes {
+		if spec.Name == name && (spec.Deprecated || spec.SunsetAt != nil) {
+			return true, spec.SunsetAt
+		}
+	}
+	return false, nil
+}
+
+// deprecationChange returns the change that brings et's deprecation in line
+// with the declared one. et is nil for types not registered yet.
+func deprecationChange(name string, et *catalog.EventType, deprecated bool, sunsetAt *time.Time) (Change, bool) {
+	isDeprecated := et != nil && et.IsDeprecated
+
+	switch {
+	case !deprecated && isDeprecated:
+		return Change{Kind: KindEventType, Action: ActionUndeprecate, Name: name}, true
+	case !deprecated:
+		return Change{}, false
+	}
+
+	if isDeprecated && sameTime(et.SunsetAt, sunsetAt) {
+		return Change{}, false
+	}
+	if isDeprecated && sunsetAt == nil && et.SunsetAt != nil && !et.SunsetAt.After(time.Now()) {
+		// Already sunset and none declared: nothing to move.
+		return Change{}, false
+	}
+	return Change{Kind: KindEventType, Action: ActionDeprecate, Name: name, SunsetAt: sunsetAt}, true
+}
+
+func eventTypeDiff(et *catalog.EventType, ver catalog.Version, spec *EventTypeSpec) []string {
+	var fields []string
+	if ver.Description != spec.Description {
+		fields = append(fields, "description")
+	}
+	if ver.Group != spec.Group {
+		fields = append(fields, "group")
+	}
+	if !jsonEqual(ver.Schema, spec.Schema) {
+		fields = append(fields, "schema")
+	}
+	if ver.SchemaVersion != spec.SchemaVersion {
+		fields = append(fields, "schema_version")
+	}
+	if !jsonEqual(ver.Example, spec.Example) {
+		fields = append(fields, "example")
+	}
+	if et.ScopeAppID != spec.ScopeAppID {
+		fields = append(fields, "scope_app_id")
+	}
+	if !mapsEqual(et.Metadata, spec.Metadata) {
+		fields = append(fields, "metadata")
+	}
+	return fields
+}
+
+func (s *Syncer) planEndpoints(ctx context.Context, m *Manifest, plan *Plan) error {
+	byTenant := make(map[string][]*endpoint.Endpoint)
+	for i := range m.Endpoints {
+		spec := &m.Endpoints[i]
+
+		eps, ok := byTenant[spec.TenantID]
+		if !ok {
+			var err error
+			eps, err = s.endpoints.List(ctx, spec.TenantID, endpoint.ListOpts{})
+			if err != nil {
+				return err
+			}
+			byTenant[spec.TenantID] = eps
+		}
+
+		change := Change{Kind: KindEndpoint, Name: endpointKey(spec.TenantID, spec.URL), endpoint: spec}
+		for _, ep := range eps {
+			if ep.URL == spec.URL {
+				change.target = ep
+				break
+			}
+		}
+
+		if change.target == nil {
+			change.Action = ActionCreate
+			plan.Changes = append(plan.Changes, change)
+			continue
+		}
+		change.Action = ActionUpdate
+		change.Fields = endpointDiff(change.target, spec)
+		if len(change.Fields) > 0 {
+			plan.Changes = append(plan.Changes, change)
+		}
+	}
+	return nil
+}
+
+// endpointDiff compares the fields endpoint.Service.Update can change.
+// Empty description, version, headers and metadata leave the stored
+// values alone, as they do in Update.
+func endpointDiff(ep *endpoint.Endpoint, spec *EndpointSpec) []string {
+	var fields []string
+	if spec.Description != "" && ep.Description != spec.Description {
+		fields = append(fields, "description")
+	}
+	if !slices.Equal(sorted(ep.EventTypes), sorted(spec.EventTypes)) {
+		fields = append(fields, "event_types")
+	}
+	if spec.Headers != nil && !mapsEqual(ep.Headers, spec.Headers) {
+		fields = append(fields, "headers")
+	}
+	if ep.RateLimit != spec.RateLimit {
+		fields = append(fields, "rate_limit")
+	}
+	if spec.Version != "" && ep.Version != spec.Version {
+		fields = append(fields, "version")
+	}
+	if spec.Metadata != nil && !mapsEqual(ep.Metadata, spec.Metadata) {
+		fields = append(fields, "metadata")
+	}
+	if spec.Enabled != nil && ep.Enabled != *spec.Enabled {
+		fields = append(fields, "enabled")
+	}
+	return fields
+}
+
+// Apply makes the changes in plan. Event types are registered before
+// deprecations and endpoints. Apply stops at the first error; since every
+// change is idempotent, fix the cause and sync again.
+func (s *Syncer) Apply(ctx context.Context, plan *Plan) error {
+	for _, c := range plan.Changes {
+		if c.Kind == KindEventType && (c.Action == ActionCreate || c.Action == ActionUpdate) {
+			if err := s.registerType(ctx, c.eventType); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, c := range plan.Changes {
+		if c.Kind != KindEventType {
+			continue
+		}
+		switch c.Action {
+		case ActionDeprecate:
+			sunset := time.Now()
+			if c.SunsetAt != nil {
+				sunset = *c.SunsetAt
+			}
+			if _, err := s.catalog.Deprecate(ctx, c.Name, sunset); err != nil {
+				return fmt.Errorf("manifest: deprecate %s: %w", c.Name, err)
+			}
+		case ActionUndeprecate:
+			if _, err := s.catalog.Undeprecate(ctx, c.Name); err != nil {
+				return fmt.Errorf("manifest: undeprecate %s: %w", c.Name, err)
+			}
+		}
+	}
+
+	for _, c := range plan.Changes {
+		if c.Kind == KindEndpoint {
+			if err := s.applyEndpoint(ctx, c); err != nil {
+				return fmt.Errorf("manifest: endpoint %s: %w", c.Name, err)
+			}
+		}
+	}
+
+	s.logger.Info("manifest applied", log.Int("changes", len(plan.Changes)))
+	return nil
+}
+
+func (s *Syncer) registerType(ctx context.Context, spec *EventTypeSpec) error {
+	def := spec.Definition()
+
+	var opts []catalog.RegisterOption
+	if spec.ScopeAppID != "" {
+		opts = append(opts, catalog.WithScopeAppID(spec.ScopeAppID))
+	}
+	if spec.Metadata != nil {
+		opts = append(opts, catalog.WithMetadata(spec.Metadata))
+	}
+
+	if _, err := s.catalog.RegisterType(ctx, def, opts...); err != nil {
+		return fmt.Errorf("manifest: %s: register %s@%s: %w", spec.Source, spec.Name, spec.Version, err)
+	}
+	return nil
+}
+
+func (s *Syncer) applyEndpoint(ctx context.Context, c Change) error {
+	spec := c.endpoint
+	in := endpoint.Input{
+		TenantID:    spec.TenantID,
+		URL:         spec.URL,
+		Description: spec.Description,
+		Secret:      spec.Secret,
+		EventTypes:  spec.EventTypes,
+		Headers:     spec.Headers,
+		RateLimit:   spec.RateLimit,
+		Metadata:    spec.Metadata,
+	}
+	if spec.Version != "" {
+		in.Version = &spec.Version
+	}
+
+	ep := c.target
+	if c.Action == ActionCreate {
+		created, err := s.endpoints.Create(ctx, in)
+		if err != nil {
+			return err
+		}
+		ep = created
+	} else if _, err := s.endpoints.Update(ctx, ep.ID, in); err != nil {
+		return err
+	}
+
+	if spec.Enabled != nil && ep.Enabled != *spec.Enabled {
+		return s.endpoints.SetEnabled(ctx, ep.ID, *spec.Enabled)
+	}
+	return nil
+}
+
+// jsonEqual compares two JSON documents by value, so formatting and key
+// order do not count as changes.
+func jsonEqual(a, b json.RawMessage) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return len(a) == len(b)
+	}
+	var av, bv any
+	if json.Unmarshal(a, &av) != nil || json.Unmarshal(b, &bv) != nil {
+		return string(a) == string(b)
+	}
+	return reflect.DeepEqual(av, bv)
+}
+
+// mapsEqual treats nil and empty maps as equal.
+func mapsEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if bv, ok := b[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
+
+func sorted(s []string) []string {
+	out := slices.Clone(s)
+	slices.Sort(out)
+	return out
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
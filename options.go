@@ -5,12 +5,16 @@ import (
 
 	log "github.com/xraph/go-utils/log"
 
+	"github.com/xraph/relay/auth"
 	"github.com/xraph/relay/catalog"
 	"github.com/xraph/relay/delivery"
 	"github.com/xraph/relay/dlq"
 	"github.com/xraph/relay/endpoint"
+	"github.com/xraph/relay/health"
+	"github.com/xraph/relay/job"
 	"github.com/xraph/relay/observability"
 	"github.com/xraph/relay/store"
+	"github.com/xraph/relay/tunnel"
 )
 
 // Relay is the root webhook delivery engine.
@@ -22,9 +26,15 @@ type Relay struct {
 	endpointSvc *endpoint.Service
 	engine      *delivery.Engine
 	dlqSvc      *dlq.Service
+	apiKeys     *auth.Service
+	jobs        *job.Runner
 	logger      log.Logger
 	metrics     *observability.Metrics
 	tracer      *observability.Tracer
+	tunnel      *tunnel.Hub
+	health      *health.Tracker
+	sysEvents   *systemEvents
+	hooks       hookChain
 
 	// wakeStop terminates the store wake listener (store.WakeNotifier);
 	// nil when the store has no push capability.
@@ -117,6 +127,15 @@ func WithMaxRetries(n int) Option {
 	}
 }
 
+// WithMaxReplays sets how many times a failed delivery can be replayed
+// from the DLQ. Zero or less removes the limit.
+func WithMaxReplays(n int) Option {
+	return func(r *Relay) error {
+		r.config.MaxReplays = n
+		return nil
+	}
+}
+
 // WithRetrySchedule sets the backoff intervals between retry attempts.
 func WithRetrySchedule(schedule []time.Duration) Option {
 	return func(r *Relay) error {
@@ -141,6 +160,15 @@ func WithCacheTTL(d time.Duration) Option {
 	}
 }
 
+// WithSchemaCompatibility sets the policy applied to breaking schema
+// changes on event type registration.
+func WithSchemaCompatibility(p catalog.CompatPolicy) Option {
+	return func(r *Relay) error {
+		r.config.SchemaCompatibility = p
+		return nil
+	}
+}
+
 // WithMetrics sets the Prometheus metrics recorder for the Relay instance.
 func WithMetrics(m *observability.Metrics) Option {
 	return func(r *Relay) error {
@@ -156,3 +184,65 @@ func WithTracer(t *observability.Tracer) Option {
 		return nil
 	}
 }
+
+// WithTunnel enables the development tunnel: endpoints with tunnel:// URLs
+// are delivered to a listener connected through the admin API (see
+// "relay listen") instead of over the network. Meant for development
+// instances only.
+func WithTunnel() Option {
+	return func(r *Relay) error {
+		r.config.Tunnel = true
+		return nil
+	}
+}
+
+// WithEndpointVerification makes new endpoints, and endpoints whose URL
+// changes, complete a signed challenge handshake before they are enabled
+// (see endpoint.HTTPVerifier). Until then they are pending_verification
+// and receive no deliveries.
+func WithEndpointVerification() Option {
+	return func(r *Relay) error {
+		r.config.VerifyEndpoints = true
+		return nil
+	}
+}
+
+// WithHealthPolicy automatically disables endpoints whose delivery health
+// breaches p, e.g. health.Policy{ConsecutiveFailures: 20, FailingFor:
+// time.Hour}. Disabled endpoints are probed every ProbeInterval and
+// re-enabled once they respond with a 2xx.
+func WithHealthPolicy(p health.Policy) Option {
+	return func(r *Relay) error {
+		r.config.HealthPolicy = p
+		return nil
+	}
+}
+
+// WithProbeInterval sets how often endpoints disabled by the health policy
+// are probed for recovery.
+func WithProbeInterval(d time.Duration) Option {
+	return func(r *Relay) error {
+		r.config.ProbeInterval = d
+		return nil
+	}
+}
+
+// WithSystemEvents makes Relay emit system events about endpoints and the
+// DLQ through its own pipeline (see EventTypeEndpointDisabled and friends).
+// Zero fields of cfg take their defaults.
+func WithSystemEvents(cfg SystemEventsConfig) Option {
+	return func(r *Relay) error {
+		cfg.Enabled = true
+		r.config.SystemEvents = cfg
+		return nil
+	}
+}
+
+// WithHooks registers lifecycle hooks. It may be given several times;
+// hooks are called in the order they were registered.
+func WithHooks(hooks ...Hooks) Option {
+	return func(r *Relay) error {
+		r.hooks.hooks = append(r.hooks.hooks, hooks...)
+		return nil
+	}
+}
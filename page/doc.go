@@ -0,0 +1,7 @@
+// Package page implements the cursor pagination of Relay's list APIs.
+//
+// Lists are ordered by ID. IDs are time-sortable, so this is creation
+// order, and the ID of a page's last item is the cursor of the next page:
+// stores list the items after it, which stays cheap on deep pages and does
+// not shift when items are added while a client pages through.
+package page
@@ -0,0 +1,39 @@
+package page
+
+import "github.com/xraph/relay/id"
+
+// DefaultLimit is the page size of list requests that do not set one.
+const DefaultLimit = 50
+
+// Page is one page of a list, as returned by the list APIs.
+type Page[T any] struct {
+	// Data holds the page's items.
+	Data []T `json:"data"`
+
+	// NextCursor is passed as the cursor to get the next page. It is empty
+	// on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+
+	// HasMore reports whether items follow this page.
+	HasMore bool `json:"has_more"`
+
+	// Total is the number of items in the list, across all pages. It is
+	// only set when requested, as counting costs a query of its own.
+	Total *int64 `json:"total,omitempty"`
+}
+
+// New returns a page of at most limit items. items must have been listed
+// with a limit of limit+1: an extra item shows that more follow. key
+// returns an item's ID.
+func New[T any](items []T, limit int, key func(T) id.ID) *Page[T] {
+	p := &Page[T]{Data: items}
+	if limit > 0 && len(items) > limit {
+		p.Data = items[:limit]
+		p.HasMore = true
+		p.NextCursor = key(p.Data[limit-1]).String()
+	}
+	if p.Data == nil {
+		p.Data = []T{}
+	}
+	return p
+}
@@ -0,0 +1,32 @@
+package page_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/xraph/relay/id"
+	"github.com/xraph/relay/page"
+)
+
+func TestNew(t *testing.T) {
+	ids := []id.ID{id.NewEventID(), id.NewEventID(), id.NewEventID()}
+	key := func(v id.ID) id.ID { return v }
+
+	p := page.New(ids, 2, key)
+	if len(p.Data) != 2 || !p.HasMore || p.NextCursor != ids[1].String() {
+		t.Fatalf("expected a full page with a cursor at the second item, got %+v", p)
+	}
+
+	p = page.New(ids, 3, key)
+	if len(p.Data) != 3 || p.HasMore || p.NextCursor != "" {
+		t.Fatalf("expected the last page, got %+v", p)
+	}
+
+	b, err := json.Marshal(page.New[id.ID](nil, 2, key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != `{"data":[],"has_more":false}` {
+		t.Fatalf("unexpected empty page %s", b)
+	}
+}
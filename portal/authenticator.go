@@ -0,0 +1,26 @@
+package portal
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/xraph/relay/auth"
+)
+
+// Authenticator authenticates requests whose bearer token is a portal
+// token verified by s. Other requests are auth.ErrNoCredentials, so it can
+// be chained with other authenticators.
+func Authenticator(s *Signer) auth.Authenticator {
+	return auth.AuthenticatorFunc(func(r *http.Request) (*auth.Principal, error) {
+		token := auth.BearerToken(r)
+		if !strings.HasPrefix(token, TokenPrefix) {
+			return nil, auth.ErrNoCredentials
+		}
+		c, err := s.Verify(token)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", auth.ErrUnauthenticated, err.Error())
+		}
+		return c.Principal(), nil
+	})
+}
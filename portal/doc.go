@@ -0,0 +1,10 @@
+// Package portal issues and checks the short-lived tokens of the tenant
+// portal: the part of the admin API a tenant uses to manage its own
+// webhooks (see api.NewPortalHandler).
+//
+// Your backend mints a token for a signed-in customer with Signer.Issue and
+// hands it to the browser. The token is signed with a secret only your
+// backend and Relay know, names one tenant, and expires; Relay needs no
+// state to check it. Requests carrying it act as a Principal bound to that
+// tenant with the scopes listed in Scopes.
+package portal
@@ -0,0 +1,164 @@
+package portal
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/xraph/relay/auth"
+)
+
+// TokenPrefix starts every portal token.
+const TokenPrefix = "rpt_"
+
+// DefaultTTL is how long a token lives when Issue is given no lifetime.
+const DefaultTTL = time.Hour
+
+// MinSecretLen is the shortest signing secret NewSigner accepts.
+const MinSecretLen = 32
+
+var (
+	// ErrInvalidToken is returned for tokens that are malformed or were
+	// not signed with the Signer's secret.
+	ErrInvalidToken = errors.New("portal: invalid token")
+
+	// ErrTokenExpired is returned for tokens past their expiry.
+	ErrTokenExpired = errors.New("portal: token expired")
+
+	// ErrWeakSecret is returned by NewSigner for secrets shorter than
+	// MinSecretLen.
+	ErrWeakSecret = errors.New("portal: secret must be at least 32 bytes")
+)
+
+// Scopes are the scopes of a portal principal: managing its endpoints,
+// reading the catalog, events and delivery logs, and replaying its failed
+// deliveries.
+var Scopes = []string{
+	auth.ScopeCatalogRead,
+	auth.ScopeEndpointsAdmin,
+	auth.ScopeEventsRead,
+	auth.ScopeDeliveriesRead,
+	auth.ScopeDLQRead,
+	auth.ScopeDLQReplay,
+}
+
+// Claims are the contents of a portal token.
+type Claims struct {
+	TenantID  string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// Principal returns the principal requests with the token act as.
+func (c *Claims) Principal() *auth.Principal {
+	return &auth.Principal{
+		Subject:  "portal:" + c.TenantID,
+		TenantID: c.TenantID,
+		Scopes:   Scopes,
+	}
+}
+
+// claims is the wire form of Claims.
+type claims struct {
+	TenantID  string `json:"tid"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// Signer issues and verifies portal tokens.
+type Signer struct {
+	secret   []byte
+	previous [][]byte
+}
+
+// NewSigner creates a Signer that signs with secret. Tokens signed with one
+// of previous are still accepted, so the secret can be rotated without
+// signing out open portals.
+func NewSigner(secret []byte, previous ...[]byte) (*Signer, error) {
+	for _, s := range append([][]byte{secret}, previous...) {
+		if len(s) < MinSecretLen {
+			return nil, ErrWeakSecret
+		}
+	}
+	return &Signer{secret: secret, previous: previous}, nil
+}
+
+// Issue returns a token for tenantID that expires after ttl, or after
+// DefaultTTL if ttl is not positive.
+func (s *Signer) Issue(tenantID string, ttl time.Duration) (string, error) {
+	if tenantID == "" {
+		return "", errors.New("portal: tenant ID is required")
+	}
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+
+	now := time.Now()
+	payload, err := json.Marshal(claims{
+		TenantID:  tenantID,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("portal: encode claims: %w", err)
+	}
+
+	body := TokenPrefix + base64.RawURLEncoding.EncodeToString(payload)
+	return body + "." + base64.RawURLEncoding.EncodeToString(sign(s.secret, body)), nil
+}
+
+// Verify checks token and returns its claims.
+func (s *Signer) Verify(token string) (*Claims, error) {
+	body, sig, ok := strings.Cut(token, ".")
+	if !ok || !strings.HasPrefix(body, TokenPrefix) {
+		return nil, ErrInvalidToken
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !s.signed(body, mac) {
+		return nil, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(body, TokenPrefix))
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var c claims
+	if err := json.Unmarshal(payload, &c); err != nil || c.TenantID == "" {
+		return nil, ErrInvalidToken
+	}
+
+	expiresAt := time.Unix(c.ExpiresAt, 0)
+	if !time.Now().Before(expiresAt) {
+		return nil, ErrTokenExpired
+	}
+	return &Claims{
+		TenantID:  c.TenantID,
+		IssuedAt:  time.Unix(c.IssuedAt, 0),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// signed reports whether mac is body's signature under the current or a
+// previous secret.
+func (s *Signer) signed(body string, mac []byte) bool {
+	if hmac.Equal(mac, sign(s.secret, body)) {
+		return true
+	}
+	for _, secret := range s.previous {
+		if hmac.Equal(mac, sign(secret, body)) {
+			return true
+		}
+	}
+	return false
+}
+
+func sign(secret []byte, body string) []byte {
+	h := hmac.New(sha256.New, secret)
+	h.Write([]byte(body))
+	return h.Sum(nil)
+}
@@ -0,0 +1,156 @@
+package portal_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xraph/relay/auth"
+	"github.com/xraph/relay/portal"
+)
+
+var (
+	secret  = []byte("0123456789abcdef0123456789abcdef")
+	rotated = []byte("fedcba9876543210fedcba9876543210")
+)
+
+func TestIssueAndVerify(t *testing.T) {
+	s, err := portal.NewSigner(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := s.Issue("tenant-1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(token, portal.TokenPrefix) {
+		t.Fatalf("expected prefix %q, got %q", portal.TokenPrefix, token)
+	}
+
+	c, err := s.Verify(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.TenantID != "tenant-1" {
+		t.Fatalf("expected tenant-1, got %q", c.TenantID)
+	}
+	if ttl := c.ExpiresAt.Sub(c.IssuedAt); ttl != portal.DefaultTTL {
+		t.Fatalf("expected the default TTL, got %v", ttl)
+	}
+
+	p := c.Principal()
+	if p.TenantID != "tenant-1" || !p.HasScope(auth.ScopeEndpointsAdmin) || p.HasScope(auth.ScopeEventsWrite) {
+		t.Fatalf("unexpected principal %+v", p)
+	}
+
+	if _, err := s.Issue("", time.Minute); err == nil {
+		t.Fatal("expected an error without a tenant")
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	s, err := portal.NewSigner(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := s.Issue("tenant-1", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := portal.NewSigner(rotated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged, err := other.Issue("tenant-1", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Swap the claims for another tenant's, keeping the signature.
+	body, sig, _ := strings.Cut(token, ".")
+	otherToken, err := s.Issue("tenant-2", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherBody, _, _ := strings.Cut(otherToken, ".")
+
+	for name, tok := range map[string]string{
+		"empty":          "",
+		"no signature":   body,
+		"other secret":   forged,
+		"swapped claims": otherBody + "." + sig,
+		"bad encoding":   body + ".!!",
+	} {
+		if _, err := s.Verify(tok); !errors.Is(err, portal.ErrInvalidToken) {
+			t.Errorf("%s: expected ErrInvalidToken, got %v", name, err)
+		}
+	}
+
+	expired, err := s.Issue("tenant-1", time.Nanosecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Verify(expired); !errors.Is(err, portal.ErrTokenExpired) {
+		t.Fatalf("expected ErrTokenExpired, got %v", err)
+	}
+}
+
+func TestSecretRotation(t *testing.T) {
+	old, err := portal.NewSigner(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := old.Issue("tenant-1", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := portal.NewSigner(rotated, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Verify(token); err != nil {
+		t.Fatalf("expected a token of the previous secret to verify, got %v", err)
+	}
+
+	if _, err := portal.NewSigner([]byte("short")); !errors.Is(err, portal.ErrWeakSecret) {
+		t.Fatalf("expected ErrWeakSecret, got %v", err)
+	}
+	if _, err := portal.NewSigner(rotated, []byte("short")); !errors.Is(err, portal.ErrWeakSecret) {
+		t.Fatalf("expected ErrWeakSecret for a previous secret, got %v", err)
+	}
+}
+
+func TestAuthenticator(t *testing.T) {
+	s, err := portal.NewSigner(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := s.Issue("tenant-1", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := portal.Authenticator(s)
+
+	request := func(header string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		if header != "" {
+			r.Header.Set("Authorization", header)
+		}
+		return r
+	}
+
+	if p, err := a.Authenticate(request("Bearer " + token)); err != nil || p.TenantID != "tenant-1" {
+		t.Fatalf("expected the token to authenticate, got %+v, %v", p, err)
+	}
+	if _, err := a.Authenticate(request("Bearer " + token + "x")); !errors.Is(err, auth.ErrUnauthenticated) {
+		t.Fatalf("expected ErrUnauthenticated, got %v", err)
+	}
+	if _, err := a.Authenticate(request("Bearer rk_abc")); !errors.Is(err, auth.ErrNoCredentials) {
+		t.Fatalf("expected ErrNoCredentials for other tokens, got %v", err)
+	}
+}
@@ -4,34 +4,81 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"strings"
 	"time"
 
 	log "github.com/xraph/go-utils/log"
 
+	"github.com/xraph/relay/auth"
 	"github.com/xraph/relay/catalog"
 	"github.com/xraph/relay/delivery"
 	"github.com/xraph/relay/dlq"
 	"github.com/xraph/relay/endpoint"
 	"github.com/xraph/relay/event"
+	"github.com/xraph/relay/health"
 	"github.com/xraph/relay/id"
 	"github.com/xraph/relay/internal/entity"
+	"github.com/xraph/relay/job"
 	"github.com/xraph/relay/scope"
 	"github.com/xraph/relay/store"
+	"github.com/xraph/relay/tunnel"
 )
 
 // wireServices initializes the internal services after options have been applied.
 func (r *Relay) wireServices() {
 	r.catalog = catalog.NewCatalog(r.store, catalog.Config{
-		CacheTTL: r.config.CacheTTL,
+		CacheTTL:     r.config.CacheTTL,
+		CompatPolicy: r.config.SchemaCompatibility,
 	}, r.logger)
 
 	r.validator = catalog.NewValidator()
 
-	r.endpointSvc = endpoint.NewService(r.store, r.logger)
+	var epOpts []endpoint.ServiceOption
+	if r.config.VerifyEndpoints {
+		epOpts = append(epOpts, endpoint.WithVerification(r.endpointVerifier()))
+	}
+	r.endpointSvc = endpoint.NewService(r.store, r.logger, epOpts...)
+
+	r.hooks.logger = r.logger
+	dlqOpts := []dlq.ServiceOption{
+		dlq.WithMaxAttempts(r.config.MaxRetries),
+		dlq.WithMaxReplays(r.config.MaxReplays),
+	}
+	if !r.hooks.empty() {
+		dlqOpts = append(dlqOpts, dlq.WithOnPush(r.hooks.onDLQ))
+	}
+	r.dlqSvc = dlq.NewService(r.store, r.logger, dlqOpts...)
+	r.apiKeys = auth.NewService(r.store, r.logger)
+	r.jobs = job.NewRunner(r.logger)
 
-	r.dlqSvc = dlq.NewService(r.store, r.logger)
+	var transport http.RoundTripper
+	if r.config.Tunnel {
+		r.tunnel = tunnel.NewHub(r.endpointSvc, r.logger)
+		transport = r.tunnel.Transport(nil)
+	}
+
+	var pusher delivery.DLQPusher = r.dlqSvc
+	var notifiers endpointNotifiers
+	var attemptHooks delivery.AttemptHooks
+	var failingAfter int
+	if r.config.SystemEvents.Enabled {
+		r.sysEvents = newSystemEvents(r, r.config.SystemEvents)
+		pusher = alertingDLQ{DLQPusher: r.dlqSvc, events: r.sysEvents}
+		notifiers = append(notifiers, r.sysEvents)
+		failingAfter = r.sysEvents.cfg.FailingAfter
+	}
+	if !r.hooks.empty() {
+		notifiers = append(notifiers, &r.hooks)
+		attemptHooks = &r.hooks
+	}
+	var notifier delivery.EndpointNotifier
+	if len(notifiers) > 0 {
+		notifier = notifiers
+	}
 
-	r.engine = delivery.NewEngine(r.store, r.dlqSvc, delivery.EngineConfig{
+	r.health = health.NewTracker(health.DefaultWindow)
+	r.engine = delivery.NewEngine(r.store, pusher, delivery.EngineConfig{
 		Concurrency:     r.config.Concurrency,
 		PollInterval:    r.config.PollInterval,
 		MaxPollInterval: r.config.MaxPollInterval,
@@ -40,14 +87,39 @@ func (r *Relay) wireServices() {
 		RetrySchedule:   r.config.RetrySchedule,
 		Metrics:         r.metrics,
 		Tracer:          r.tracer,
+		Converter:       versionConverter{catalog: r.catalog},
+		Deprecations:    r.catalog,
+		Transport:       transport,
+		Health:          r.health,
+		HealthPolicy:    r.config.HealthPolicy,
+		ProbeInterval:   r.config.ProbeInterval,
+		Notifier:        notifier,
+		FailingAfter:    failingAfter,
+		Hooks:           attemptHooks,
 	}, r.logger)
 }
 
+// endpointVerifier returns the verifier for WithEndpointVerification. Tunnel
+// endpoints are opened by their listener and skip the handshake.
+func (r *Relay) endpointVerifier() endpoint.Verifier {
+	v := &endpoint.HTTPVerifier{Client: &http.Client{Timeout: r.config.RequestTimeout}}
+	return endpoint.VerifierFunc(func(ctx context.Context, ep *endpoint.Endpoint) error {
+		if strings.HasPrefix(ep.URL, tunnel.Scheme+"://") {
+			return nil
+		}
+		return v.Verify(ctx, ep)
+	})
+}
+
 // Start begins the delivery engine. Stores that support push notifications
 // (store.WakeNotifier, e.g. Postgres LISTEN/NOTIFY) additionally wake the
 // engine on cross-instance enqueues so deliveries are picked up without
-// waiting out the idle poll backoff.
+// waiting out the idle poll backoff. With system events enabled, their
+// event types are registered in the catalog first.
 func (r *Relay) Start(ctx context.Context) {
+	if r.sysEvents != nil {
+		r.sysEvents.register(ctx)
+	}
 	r.engine.Start(ctx)
 
 	if wn, ok := r.store.(store.WakeNotifier); ok {
@@ -61,12 +133,14 @@ func (r *Relay) Start(ctx context.Context) {
 	}
 }
 
-// Stop gracefully shuts down the delivery engine.
+// Stop gracefully shuts down the delivery engine. Running background jobs
+// are cancelled.
 func (r *Relay) Stop(ctx context.Context) {
 	if r.wakeStop != nil {
 		r.wakeStop()
 		r.wakeStop = nil
 	}
+	r.jobs.Stop(ctx)
 	r.engine.Stop(ctx)
 }
 
@@ -79,57 +153,217 @@ func (r *Relay) RegisterEventType(ctx context.Context, def catalog.WebhookDefini
 //
 // The critical path:
 //  1. Look up event type from the catalog (reject unknown types).
-//  2. Check if the event type is deprecated (reject if so).
+//  2. Check if the event type is deprecated: accept it with a warning in
+//     SendResult.Warnings until its SunsetAt, then reject it with
+//     ErrEventTypeDeprecated (at once if it has no SunsetAt).
 //  3. Validate the event payload against the JSON Schema (if configured).
-//  4. Persist the event (idempotency key dedup is handled here).
-//  5. Resolve matching endpoints for this tenant + event type.
-//  6. Enqueue one delivery per matched endpoint.
+//  4. Resolve matching endpoints for this tenant + event type.
+//  5. Persist the event and one delivery per matched endpoint. Stores that
+//     implement store.Outbox write both in a single transaction; idempotency
+//     key dedup is handled here.
+//
+// A duplicate idempotency key is a no-op success. Use SendWithResult to
+// tell duplicates apart and to learn which deliveries were created.
 func (r *Relay) Send(ctx context.Context, evt *event.Event) error {
+	_, err := r.send(ctx, nil, evt, sendOptions{})
+	return err
+}
+
+// SendWithResult is like Send but reports what happened: the event ID, the
+// matched endpoints and the created deliveries, or the original event when
+// the idempotency key was a duplicate. Pass DryRun to validate and resolve
+// without persisting anything.
+func (r *Relay) SendWithResult(ctx context.Context, evt *event.Event, opts ...SendOption) (*SendResult, error) {
+	var o sendOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return r.send(ctx, nil, evt, o)
+}
+
+// SendTx is like Send but persists the event and its deliveries inside tx,
+// the application's own transaction, so that e.g. "order saved" and "webhook
+// queued" commit together. tx must be a transaction of the store's driver
+// (for the postgres store a *pgdriver.PgTx, for sqlite a *sqlitedriver.SqliteTx).
+// Deliveries only become visible to the engine once the caller commits.
+//
+// Returns ErrTxUnsupported if the store does not implement store.TxOutbox.
+func (r *Relay) SendTx(ctx context.Context, tx any, evt *event.Event) error {
+	if tx == nil {
+		return fmt.Errorf("%w: nil transaction", ErrTxUnsupported)
+	}
+	if _, ok := r.store.(store.TxOutbox); !ok {
+		return ErrTxUnsupported
+	}
+	_, err := r.send(ctx, tx, evt, sendOptions{})
+	return err
+}
+
+// send implements Send, SendWithResult and SendTx, running the send hooks
+// around enqueue. A nil tx means the store manages the transaction itself.
+func (r *Relay) send(ctx context.Context, tx any, evt *event.Event, o sendOptions) (*SendResult, error) {
+	if r.hooks.empty() || o.dryRun {
+		return r.enqueue(ctx, tx, evt, o)
+	}
+	if err := r.hooks.beforeSend(ctx, evt); err != nil {
+		return nil, err
+	}
+	res, err := r.enqueue(ctx, tx, evt, o)
+	r.hooks.afterSend(ctx, evt, res, err)
+	return res, err
+}
+
+// enqueue validates evt, resolves its endpoints and persists it with one
+// delivery per endpoint.
+func (r *Relay) enqueue(ctx context.Context, tx any, evt *event.Event, o sendOptions) (*SendResult, error) {
+	// A dry run leaves the caller's event as it was.
+	if o.dryRun {
+		cp := *evt
+		evt = &cp
+	}
+
+	// 1–3. Validate against the catalog; assign ID and scope.
+	warnings, err := r.prepare(ctx, evt)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Resolve matching endpoints.
+	endpoints, err := r.store.Resolve(ctx, evt.TenantID, evt.Type)
+	if err != nil {
+		return nil, fmt.Errorf("relay: resolve endpoints: %w", err)
+	}
+
+	res := &SendResult{
+		EndpointIDs: make([]id.ID, len(endpoints)),
+		DeliveryIDs: []id.ID{},
+		Warnings:    warnings,
+	}
+	for i, ep := range endpoints {
+		res.EndpointIDs[i] = ep.ID
+	}
+
+	if o.dryRun {
+		res.DryRun = true
+		if evt.IdempotencyKey != "" {
+			orig, lookupErr := r.store.GetEventByIdempotencyKey(ctx, evt.IdempotencyKey)
+			switch {
+			case lookupErr == nil:
+				res.Duplicate = true
+				res.OriginalEventID = orig.ID
+			case !errors.Is(lookupErr, ErrEventNotFound):
+				return nil, fmt.Errorf("relay: check idempotency key: %w", lookupErr)
+			}
+		}
+		return res, nil
+	}
+
+	// 5. Fan out: create one delivery per endpoint.
+	deliveries := r.fanOut(evt, endpoints, time.Now().UTC())
+
+	// Persist. Idempotency key conflicts return a no-op success.
+	if err := r.persist(ctx, tx, evt, deliveries); err != nil {
+		if errors.Is(err, ErrDuplicateIdempotencyKey) {
+			res.Duplicate = true
+			r.lookupOriginal(ctx, evt, res)
+			return res, nil // idempotent: already processed
+		}
+		return nil, err
+	}
+
+	res.EventID = evt.ID
+	for _, d := range deliveries {
+		res.DeliveryIDs = append(res.DeliveryIDs, d.ID)
+	}
+
+	if len(deliveries) == 0 {
+		return res, nil // no matching endpoints — nothing to deliver
+	}
+
+	// Nudge the delivery engine so in-process enqueues are picked up
+	// immediately instead of waiting out the idle poll backoff.
+	r.engine.Wake()
+
+	if r.metrics != nil {
+		r.metrics.EventsSentTotal.Inc()
+		r.metrics.PendingDeliveries.Add(float64(len(deliveries)))
+	}
+
+	r.logger.Debug("event sent",
+		log.String("event_id", evt.ID.String()),
+		log.String("type", evt.Type),
+		log.Int("endpoints", len(endpoints)),
+	)
+
+	return res, nil
+}
+
+// lookupOriginal fills in the ID of the event that already holds evt's
+// idempotency key. A failed lookup is logged rather than returned: the send
+// itself succeeded as a no-op.
+func (r *Relay) lookupOriginal(ctx context.Context, evt *event.Event, res *SendResult) {
+	orig, err := r.store.GetEventByIdempotencyKey(ctx, evt.IdempotencyKey)
+	if err != nil {
+		r.logger.Warn("duplicate event: original lookup failed",
+			log.String("idempotency_key", evt.IdempotencyKey),
+			log.Error(err),
+		)
+		return
+	}
+	res.OriginalEventID = orig.ID
+}
+
+// prepare validates an event against the catalog and assigns its ID,
+// timestamps and scope. It returns warnings for the producer, such as the
+// event type being deprecated.
+func (r *Relay) prepare(ctx context.Context, evt *event.Event) ([]string, error) {
 	// 1. Validate event type exists.
 	et, err := r.catalog.GetType(ctx, evt.Type)
 	if err != nil {
-		return fmt.Errorf("%w: %s", ErrEventTypeNotFound, evt.Type)
+		return nil, fmt.Errorf("%w: %s", ErrEventTypeNotFound, evt.Type)
 	}
 
-	// 2. Reject deprecated event types.
+	// 2. Reject event types past their sunset; warn about deprecated ones.
+	var warnings []string
+	if et.IsSunset(time.Now()) {
+		return nil, fmt.Errorf("%w: %s", ErrEventTypeDeprecated, evt.Type)
+	}
 	if et.IsDeprecated {
-		return fmt.Errorf("%w: %s", ErrEventTypeDeprecated, evt.Type)
+		warnings = append(warnings, fmt.Sprintf("event type %s is deprecated and will be rejected after %s",
+			evt.Type, et.SunsetAt.UTC().Format(time.RFC3339)))
 	}
 
-	// 3. Validate payload against schema (if defined).
-	if len(et.Definition.Schema) > 0 {
-		if validateErr := r.validator.Validate(et.Definition.Schema, evt.Data); validateErr != nil {
-			return fmt.Errorf("%w: %s", ErrPayloadValidationFailed, validateErr.Error())
+	// 3. Validate payload against the schema of its version (if defined).
+	if evt.Version == "" {
+		evt.Version = et.Definition.Version
+	}
+	ver, ok := et.LookupVersion(evt.Version)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s@%s", ErrEventVersionNotFound, evt.Type, evt.Version)
+	}
+	if len(ver.Schema) > 0 {
+		if validateErr := r.validator.Validate(ver.Schema, evt.Data); validateErr != nil {
+			return nil, fmt.Errorf("%w: %s", ErrPayloadValidationFailed, validateErr.Error())
 		}
 	}
 
-	// 4. Assign ID, capture scope, set entity timestamps.
+	// Assign ID, set entity timestamps and fill in the scope the caller
+	// left empty from ctx.
 	evt.Entity = entity.New()
 	evt.ID = id.NewEventID()
 	appID, orgID := scope.Capture(ctx)
-	evt.ScopeAppID = appID
-	evt.ScopeOrgID = orgID
-
-	// Persist the event. Idempotency key conflicts return a no-op success.
-	if createErr := r.store.CreateEvent(ctx, evt); createErr != nil {
-		if errors.Is(createErr, ErrDuplicateIdempotencyKey) {
-			return nil // idempotent: already processed
-		}
-		return fmt.Errorf("relay: persist event: %w", createErr)
+	if evt.ScopeAppID == "" {
+		evt.ScopeAppID = appID
 	}
-
-	// 5. Resolve matching endpoints.
-	endpoints, err := r.store.Resolve(ctx, evt.TenantID, evt.Type)
-	if err != nil {
-		return fmt.Errorf("relay: resolve endpoints: %w", err)
+	if evt.ScopeOrgID == "" {
+		evt.ScopeOrgID = orgID
 	}
 
-	if len(endpoints) == 0 {
-		return nil // no matching endpoints — nothing to deliver
-	}
+	return warnings, nil
+}
 
-	// 6. Fan out: create one delivery per endpoint.
-	now := time.Now().UTC()
+// fanOut builds one pending delivery per endpoint.
+func (r *Relay) fanOut(evt *event.Event, endpoints []*endpoint.Endpoint, now time.Time) []*delivery.Delivery {
 	deliveries := make([]*delivery.Delivery, 0, len(endpoints))
 	for _, ep := range endpoints {
 		d := &delivery.Delivery{
@@ -144,26 +378,35 @@ func (r *Relay) Send(ctx context.Context, evt *event.Event) error {
 		}
 		deliveries = append(deliveries, d)
 	}
+	return deliveries
+}
 
-	if err := r.store.EnqueueBatch(ctx, deliveries); err != nil {
-		return fmt.Errorf("relay: enqueue deliveries: %w", err)
+// persist writes an event and its deliveries, atomically when the store
+// supports it.
+func (r *Relay) persist(ctx context.Context, tx any, evt *event.Event, deliveries []*delivery.Delivery) error {
+	if tx != nil {
+		if err := r.store.(store.TxOutbox).CreateEventWithDeliveriesTx(ctx, tx, evt, deliveries); err != nil {
+			return fmt.Errorf("relay: persist event: %w", err)
+		}
+		return nil
 	}
 
-	// Nudge the delivery engine so in-process enqueues are picked up
-	// immediately instead of waiting out the idle poll backoff.
-	r.engine.Wake()
-
-	if r.metrics != nil {
-		r.metrics.EventsSentTotal.Inc()
-		r.metrics.PendingDeliveries.Add(float64(len(deliveries)))
+	if ob, ok := r.store.(store.Outbox); ok {
+		if err := ob.CreateEventWithDeliveries(ctx, evt, deliveries); err != nil {
+			return fmt.Errorf("relay: persist event: %w", err)
+		}
+		return nil
 	}
 
-	r.logger.Debug("event sent",
-		log.String("event_id", evt.ID.String()),
-		log.String("type", evt.Type),
-		log.Int("endpoints", len(endpoints)),
-	)
-
+	if err := r.store.CreateEvent(ctx, evt); err != nil {
+		return fmt.Errorf("relay: persist event: %w", err)
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+	if err := r.store.EnqueueBatch(ctx, deliveries); err != nil {
+		return fmt.Errorf("relay: enqueue deliveries: %w", err)
+	}
 	return nil
 }
 
@@ -191,3 +434,14 @@ func (r *Relay) Store() store.Store {
 func (r *Relay) DLQ() *dlq.Service {
 	return r.dlqSvc
 }
+
+// APIKeys returns the admin API key service.
+func (r *Relay) APIKeys() *auth.Service {
+	return r.apiKeys
+}
+
+// Tunnel returns the development tunnel hub, or nil unless the Relay was
+// created WithTunnel.
+func (r *Relay) Tunnel() *tunnel.Hub {
+	return r.tunnel
+}
@@ -4,13 +4,17 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/xraph/relay"
 	"github.com/xraph/relay/catalog"
 	"github.com/xraph/relay/delivery"
 	"github.com/xraph/relay/endpoint"
 	"github.com/xraph/relay/event"
+	"github.com/xraph/relay/scope"
 	"github.com/xraph/relay/store/memory"
 )
 
@@ -313,3 +317,285 @@ func TestSendTenantIsolation(t *testing.T) {
 		t.Fatalf("expected 1 delivery (tenant isolation), got %d", pending)
 	}
 }
+
+// txStore adds a fake caller-transaction capability to the memory store.
+type txStore struct {
+	*memory.Store
+	gotTx any
+}
+
+func (s *txStore) CreateEventWithDeliveriesTx(ctx context.Context, tx any, evt *event.Event, ds []*delivery.Delivery) error {
+	s.gotTx = tx
+	return s.CreateEventWithDeliveries(ctx, evt, ds)
+}
+
+func TestSendTxUnsupportedStore(t *testing.T) {
+	r, _ := setup(t)
+	registerType(t, r, "invoice.created")
+
+	evt := &event.Event{Type: "invoice.created", TenantID: "t1"}
+	if err := r.SendTx(ctx(), struct{}{}, evt); !errors.Is(err, relay.ErrTxUnsupported) {
+		t.Fatalf("expected ErrTxUnsupported, got %v", err)
+	}
+}
+
+func TestSendTxUsesCallerTransaction(t *testing.T) {
+	s := &txStore{Store: memory.New()}
+	r, err := relay.New(relay.WithStore(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	registerType(t, r, "invoice.created")
+	createEndpoint(t, r, "t1", []string{"*"})
+
+	tx := &struct{ name string }{"app-tx"}
+	evt := &event.Event{Type: "invoice.created", TenantID: "t1"}
+	if err := r.SendTx(ctx(), tx, evt); err != nil {
+		t.Fatal(err)
+	}
+
+	if s.gotTx != tx {
+		t.Fatalf("expected caller transaction to reach the store, got %v", s.gotTx)
+	}
+	deliveries, _ := s.ListByEvent(ctx(), evt.ID)
+	if len(deliveries) != 1 {
+		t.Fatalf("expected 1 delivery, got %d", len(deliveries))
+	}
+}
+
+func TestSendBatch(t *testing.T) {
+	r, s := setup(t)
+
+	registerType(t, r, "invoice.created")
+	createEndpoint(t, r, "t1", []string{"invoice.*"})
+	createEndpoint(t, r, "t1", []string{"*"})
+	createEndpoint(t, r, "t2", []string{"*"})
+
+	evts := []*event.Event{
+		{Type: "invoice.created", TenantID: "t1", IdempotencyKey: "k1"},
+		{Type: "unknown.type", TenantID: "t1"},
+		{Type: "invoice.created", TenantID: "t2"},
+		nil,
+		{Type: "invoice.created", TenantID: "t1", IdempotencyKey: "k1"}, // duplicate within the batch
+	}
+
+	results, err := r.SendBatch(ctx(), evts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != len(evts) {
+		t.Fatalf("expected %d results, got %d", len(evts), len(results))
+	}
+
+	if results[0].Err != nil || results[0].Duplicate || results[0].EventID != evts[0].ID {
+		t.Fatalf("result 0: unexpected %+v", results[0])
+	}
+	if !errors.Is(results[1].Err, relay.ErrEventTypeNotFound) {
+		t.Fatalf("result 1: expected ErrEventTypeNotFound, got %v", results[1].Err)
+	}
+	if results[2].Err != nil {
+		t.Fatalf("result 2: unexpected error %v", results[2].Err)
+	}
+	if results[3].Err == nil {
+		t.Fatal("result 3: expected error for nil event")
+	}
+	if !results[4].Duplicate {
+		t.Fatalf("result 4: expected duplicate, got %+v", results[4])
+	}
+
+	// 2 deliveries for the t1 event, 1 for the t2 event.
+	pending, _ := s.CountPending(ctx())
+	if pending != 3 {
+		t.Fatalf("expected 3 pending deliveries, got %d", pending)
+	}
+
+	// A later batch reusing the key is also a duplicate.
+	results, err = r.SendBatch(ctx(), []*event.Event{
+		{Type: "invoice.created", TenantID: "t1", IdempotencyKey: "k1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !results[0].Duplicate {
+		t.Fatalf("expected duplicate across batches, got %+v", results[0])
+	}
+}
+
+func TestSendWithResult(t *testing.T) {
+	r, _ := setup(t)
+
+	registerType(t, r, "invoice.created")
+	createEndpoint(t, r, "t1", []string{"invoice.*"})
+	createEndpoint(t, r, "t1", []string{"*"})
+
+	first, err := r.SendWithResult(ctx(), &event.Event{
+		Type:           "invoice.created",
+		TenantID:       "t1",
+		IdempotencyKey: "inv-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Duplicate || first.EventID.IsNil() {
+		t.Fatalf("unexpected first result %+v", first)
+	}
+	if len(first.EndpointIDs) != 2 || len(first.DeliveryIDs) != 2 {
+		t.Fatalf("expected 2 endpoints and deliveries, got %d and %d", len(first.EndpointIDs), len(first.DeliveryIDs))
+	}
+
+	second, err := r.SendWithResult(ctx(), &event.Event{
+		Type:           "invoice.created",
+		TenantID:       "t1",
+		IdempotencyKey: "inv-1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !second.Duplicate || second.OriginalEventID != first.EventID {
+		t.Fatalf("expected duplicate of %s, got %+v", first.EventID, second)
+	}
+	if !second.EventID.IsNil() || len(second.DeliveryIDs) != 0 {
+		t.Fatalf("duplicate must not create anything, got %+v", second)
+	}
+}
+
+func TestSendDryRun(t *testing.T) {
+	r, s := setup(t)
+
+	registerType(t, r, "invoice.created")
+	createEndpoint(t, r, "t1", []string{"invoice.*"})
+
+	evt := &event.Event{
+		Type:           "invoice.created",
+		TenantID:       "t1",
+		IdempotencyKey: "inv-1",
+	}
+	res, err := r.SendWithResult(ctx(), evt, relay.DryRun())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.DryRun || res.Duplicate || len(res.EndpointIDs) != 1 || len(res.DeliveryIDs) != 0 {
+		t.Fatalf("unexpected dry-run result %+v", res)
+	}
+	if !evt.ID.IsNil() || evt.Version != "" {
+		t.Fatalf("dry run changed the event: ID %q, version %q", evt.ID, evt.Version)
+	}
+
+	events, _ := s.ListEvents(ctx(), event.ListOpts{})
+	pending, _ := s.CountPending(ctx())
+	if len(events) != 0 || pending != 0 {
+		t.Fatalf("dry run persisted %d events and %d deliveries", len(events), pending)
+	}
+
+	// Validation still applies.
+	_, err = r.SendWithResult(ctx(), &event.Event{Type: "nope", TenantID: "t1"}, relay.DryRun())
+	if !errors.Is(err, relay.ErrEventTypeNotFound) {
+		t.Fatalf("expected ErrEventTypeNotFound, got %v", err)
+	}
+
+	// A dry run reports a would-be duplicate.
+	if err := r.Send(ctx(), &event.Event{Type: "invoice.created", TenantID: "t1", IdempotencyKey: "inv-1"}); err != nil {
+		t.Fatal(err)
+	}
+	res, err = r.SendWithResult(ctx(), &event.Event{
+		Type:           "invoice.created",
+		TenantID:       "t1",
+		IdempotencyKey: "inv-1",
+	}, relay.DryRun())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Duplicate || res.OriginalEventID.IsNil() {
+		t.Fatalf("expected dry run to report duplicate, got %+v", res)
+	}
+}
+
+// scopeHooks records the scope delivery attempts run in.
+type scopeHooks struct {
+	relay.NopHooks
+	seen chan [2]string
+}
+
+func (h scopeHooks) AfterAttempt(ctx context.Context, _ *delivery.Delivery, _ *endpoint.Endpoint, _ *event.Event, _ delivery.Result) {
+	appID, orgID := scope.Capture(ctx)
+	select {
+	case h.seen <- [2]string{appID, orgID}:
+	default:
+	}
+}
+
+func TestScopePropagation(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer srv.Close()
+
+	s := memory.New()
+	hooks := scopeHooks{seen: make(chan [2]string, 1)}
+	r, err := relay.New(relay.WithStore(s), relay.WithPollInterval(10*time.Millisecond), relay.WithHooks(hooks))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scoped := scope.Restore(ctx(), "app_1", "org_1")
+	et, err := r.RegisterEventType(scoped, catalog.WebhookDefinition{Name: "order.created"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if et.ScopeAppID != "app_1" {
+		t.Fatalf("expected the event type scoped to app_1, got %q", et.ScopeAppID)
+	}
+	ep, err := r.Endpoints().Create(scoped, endpoint.Input{TenantID: "acme", URL: srv.URL, EventTypes: []string{"order.*"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ep.ScopeAppID != "app_1" || ep.ScopeOrgID != "org_1" {
+		t.Fatalf("expected the endpoint scoped to app_1/org_1, got %q/%q", ep.ScopeAppID, ep.ScopeOrgID)
+	}
+
+	r.Start(ctx())
+	defer r.Stop(ctx())
+
+	if err := r.Send(scoped, &event.Event{Type: "order.created", TenantID: "acme", Data: mustJSON(map[string]any{})}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case got := <-hooks.seen:
+		if got != [2]string{"app_1", "org_1"} {
+			t.Fatalf("expected the delivery to run in app_1/org_1, got %v", got)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("event not delivered")
+	}
+
+	for _, tt := range []struct {
+		opts event.ListOpts
+		want int
+	}{
+		{event.ListOpts{ScopeAppID: "app_1", ScopeOrgID: "org_1"}, 1},
+		{event.ListOpts{ScopeAppID: "app_2"}, 0},
+	} {
+		evts, err := s.ListEvents(ctx(), tt.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(evts) != tt.want {
+			t.Fatalf("ListEvents(%+v) = %d events, want %d", tt.opts, len(evts), tt.want)
+		}
+	}
+	// A scope set on the event wins over the one in ctx.
+	preset := &event.Event{Type: "order.created", TenantID: "acme", Data: mustJSON(map[string]any{}), ScopeAppID: "app_2"}
+	if err := r.Send(scoped, preset); err != nil {
+		t.Fatal(err)
+	}
+	if preset.ScopeAppID != "app_2" || preset.ScopeOrgID != "org_1" {
+		t.Fatalf("expected the event scoped to app_2/org_1, got %q/%q", preset.ScopeAppID, preset.ScopeOrgID)
+	}
+
+	eps, err := r.Endpoints().List(ctx(), "acme", endpoint.ListOpts{ScopeOrgID: "org_2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(eps) != 0 {
+		t.Fatalf("expected no endpoints in org_2, got %d", len(eps))
+	}
+}
@@ -0,0 +1,216 @@
+package relay
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	log "github.com/xraph/go-utils/log"
+
+	"github.com/xraph/relay/catalog"
+	"github.com/xraph/relay/delivery"
+	"github.com/xraph/relay/endpoint"
+	"github.com/xraph/relay/event"
+	"github.com/xraph/relay/id"
+	"github.com/xraph/relay/job"
+	"github.com/xraph/relay/ratelimit"
+)
+
+// DefaultBackfillRate is the number of events per second a backfill
+// enqueues when BackfillOptions.Rate is not set.
+const DefaultBackfillRate = 50
+
+// backfillPageSize is the number of events a backfill lists at a time.
+const backfillPageSize = 100
+
+// Resend delivers a stored event again, as a new delivery per endpoint.
+// With no endpointIDs it goes to the endpoints currently subscribed to the
+// event's type; otherwise to the given endpoints, which must belong to the
+// event's tenant and be enabled but need not be subscribed. The event is
+// not validated again. Events recorded by SendTest return ErrTestEvent.
+func (r *Relay) Resend(ctx context.Context, evtID id.ID, endpointIDs ...id.ID) (*SendResult, error) {
+	evt, err := r.store.GetEvent(ctx, evtID)
+	if err != nil {
+		return nil, err
+	}
+	if evt.Test {
+		return nil, fmt.Errorf("%w: %s", ErrTestEvent, evt.ID)
+	}
+
+	var endpoints []*endpoint.Endpoint
+	if len(endpointIDs) == 0 {
+		endpoints, err = r.store.Resolve(ctx, evt.TenantID, evt.Type)
+		if err != nil {
+			return nil, fmt.Errorf("relay: resolve endpoints: %w", err)
+		}
+	}
+	for _, epID := range endpointIDs {
+		ep, getErr := r.store.GetEndpoint(ctx, epID)
+		if getErr != nil {
+			return nil, getErr
+		}
+		if ep.TenantID != evt.TenantID {
+			return nil, ErrEndpointNotFound
+		}
+		if !ep.Enabled {
+			return nil, fmt.Errorf("%w: %s", ErrEndpointDisabled, ep.ID)
+		}
+		endpoints = append(endpoints, ep)
+	}
+
+	res := &SendResult{
+		EventID:     evt.ID,
+		EndpointIDs: make([]id.ID, len(endpoints)),
+		DeliveryIDs: make([]id.ID, 0, len(endpoints)),
+	}
+	for i, ep := range endpoints {
+		res.EndpointIDs[i] = ep.ID
+	}
+
+	deliveries := r.fanOut(evt, endpoints, time.Now().UTC())
+	if err := r.enqueueDeliveries(ctx, deliveries); err != nil {
+		return nil, err
+	}
+	for _, d := range deliveries {
+		res.DeliveryIDs = append(res.DeliveryIDs, d.ID)
+	}
+
+	r.logger.Debug("event resent",
+		log.String("event_id", evt.ID.String()),
+		log.Int("endpoints", len(endpoints)),
+	)
+
+	return res, nil
+}
+
+// enqueueDeliveries enqueues deliveries of already stored events and wakes
+// the engine.
+func (r *Relay) enqueueDeliveries(ctx context.Context, deliveries []*delivery.Delivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	if err := r.store.EnqueueBatch(ctx, deliveries); err != nil {
+		return fmt.Errorf("relay: enqueue deliveries: %w", err)
+	}
+	r.engine.Wake()
+	if r.metrics != nil {
+		r.metrics.PendingDeliveries.Add(float64(len(deliveries)))
+	}
+	return nil
+}
+
+// BackfillOptions selects the events a backfill delivers.
+type BackfillOptions struct {
+	// From and To, when set, bound the events' creation time.
+	From *time.Time `json:"from,omitempty"`
+	To   *time.Time `json:"to,omitempty"`
+
+	// Type, when set, only backfills events of this type.
+	Type string `json:"type,omitempty"`
+
+	// Rate is the number of events enqueued per second,
+	// DefaultBackfillRate if not positive.
+	Rate int `json:"rate"`
+}
+
+// backfillParams are the params of a backfill job.
+type backfillParams struct {
+	EndpointID id.ID `json:"endpoint_id"`
+	BackfillOptions
+}
+
+// Backfill starts a job that delivers an endpoint's tenant's past events
+// to the endpoint, for example one that was just created. Events the
+// endpoint is not subscribed to are skipped; events recorded by SendTest
+// are left out. The endpoint receives the
+// events oldest first, so it can rebuild state from them in order.
+//
+// The job runs on this instance at opts.Rate; follow it with Jobs().Get.
+func (r *Relay) Backfill(ctx context.Context, epID id.ID, opts BackfillOptions) (*job.Job, error) {
+	ep, err := r.store.GetEndpoint(ctx, epID)
+	if err != nil {
+		return nil, err
+	}
+	if !ep.Enabled {
+		return nil, fmt.Errorf("%w: %s", ErrEndpointDisabled, ep.ID)
+	}
+	if opts.Rate <= 0 {
+		opts.Rate = DefaultBackfillRate
+	}
+
+	params := backfillParams{EndpointID: ep.ID, BackfillOptions: opts}
+	return r.jobs.Start(ctx, job.KindBackfill, ep.TenantID, params, func(ctx context.Context, p *job.Progress) error {
+		return r.backfill(ctx, p, ep, opts)
+	}), nil
+}
+
+// backfill runs a backfill job. It walks the events oldest first, up to
+// the newest one when it starts: the endpoint receives later events as
+// they are sent. The endpoint is read again before each page, so the job
+// stops once it is disabled or deleted.
+func (r *Relay) backfill(ctx context.Context, p *job.Progress, ep *endpoint.Endpoint, opts BackfillOptions) error {
+	listOpts := event.ListOpts{Type: opts.Type, From: opts.From, To: opts.To, ExcludeTest: true}
+	total, err := r.store.CountEventsByTenant(ctx, ep.TenantID, listOpts)
+	if err != nil {
+		return fmt.Errorf("relay: count events: %w", err)
+	}
+	p.SetTotal(total)
+
+	listOpts.Limit = 1
+	newest, err := r.store.ListEventsByTenant(ctx, ep.TenantID, listOpts)
+	if err != nil {
+		return fmt.Errorf("relay: list events: %w", err)
+	}
+	if len(newest) == 0 {
+		return nil
+	}
+	last := newest[0].ID.String()
+
+	limiter := ratelimit.New()
+	key := ep.ID.String()
+	listOpts.Limit = backfillPageSize
+	listOpts.Ascending = true
+	for {
+		ep, err = r.store.GetEndpoint(ctx, ep.ID)
+		if err != nil {
+			return fmt.Errorf("relay: get endpoint: %w", err)
+		}
+		if !ep.Enabled {
+			return fmt.Errorf("%w: %s", ErrEndpointDisabled, ep.ID)
+		}
+
+		evts, err := r.store.ListEventsByTenant(ctx, ep.TenantID, listOpts)
+		if err != nil {
+			return fmt.Errorf("relay: list events: %w", err)
+		}
+
+		for _, evt := range evts {
+			if evt.ID.String() > last {
+				return nil
+			}
+			if !slices.ContainsFunc(ep.EventTypes, func(pattern string) bool { return catalog.Match(pattern, evt.Type) }) {
+				p.Skip(1)
+				continue
+			}
+			if err := limiter.Wait(ctx, key, opts.Rate); err != nil {
+				return err
+			}
+			if err := r.enqueueDeliveries(ctx, r.fanOut(evt, []*endpoint.Endpoint{ep}, time.Now().UTC())); err != nil {
+				return err
+			}
+			p.Add(1)
+		}
+
+		if len(evts) < backfillPageSize {
+			return nil
+		}
+		listOpts.Cursor = evts[len(evts)-1].ID
+	}
+}
+
+// Jobs returns the runner of this instance's background jobs, such as
+// backfills.
+func (r *Relay) Jobs() *job.Runner {
+	return r.jobs
+}
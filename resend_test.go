@@ -0,0 +1,273 @@
+package relay_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/xraph/relay"
+	"github.com/xraph/relay/delivery"
+	"github.com/xraph/relay/endpoint"
+	"github.com/xraph/relay/event"
+	"github.com/xraph/relay/id"
+	"github.com/xraph/relay/job"
+)
+
+func TestResend(t *testing.T) {
+	r, s := setup(t)
+	registerType(t, r, "order.created")
+	createEndpoint(t, r, "acme", []string{"order.*"})
+
+	evt := &event.Event{Type: "order.created", TenantID: "acme", Data: mustJSON(map[string]any{"id": 1})}
+	if err := r.Send(ctx(), evt); err != nil {
+		t.Fatal(err)
+	}
+
+	// An endpoint created after the send receives the event on resend.
+	late, err := r.Endpoints().Create(ctx(), endpoint.Input{
+		TenantID:   "acme",
+		URL:        "https://example.com/late",
+		EventTypes: []string{"*"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := r.Resend(ctx(), evt.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.DeliveryIDs) != 2 {
+		t.Fatalf("expected a delivery per subscribed endpoint, got %d", len(res.DeliveryIDs))
+	}
+
+	res, err = r.Resend(ctx(), evt.ID, late.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.DeliveryIDs) != 1 || res.EndpointIDs[0] != late.ID {
+		t.Fatalf("expected one delivery to the chosen endpoint, got %+v", res)
+	}
+
+	all, _ := s.ListByEvent(ctx(), evt.ID)
+	if len(all) != 4 {
+		t.Fatalf("expected 4 deliveries of the event, got %d", len(all))
+	}
+
+	other, err := r.Endpoints().Create(ctx(), endpoint.Input{
+		TenantID:   "globex",
+		URL:        "https://example.com/other",
+		EventTypes: []string{"*"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Resend(ctx(), evt.ID, other.ID); !errors.Is(err, relay.ErrEndpointNotFound) {
+		t.Fatalf("expected ErrEndpointNotFound for another tenant's endpoint, got %v", err)
+	}
+	if _, err := r.Resend(ctx(), id.NewEventID()); !errors.Is(err, relay.ErrEventNotFound) {
+		t.Fatalf("expected ErrEventNotFound, got %v", err)
+	}
+}
+
+func TestBackfill(t *testing.T) {
+	r, s := setup(t)
+	registerType(t, r, "order.created")
+	registerType(t, r, "invoice.paid")
+
+	for _, typ := range []string{"order.created", "order.created", "invoice.paid"} {
+		if err := r.Send(ctx(), &event.Event{Type: typ, TenantID: "acme", Data: mustJSON(map[string]any{})}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := r.Send(ctx(), &event.Event{Type: "order.created", TenantID: "globex", Data: mustJSON(map[string]any{})}); err != nil {
+		t.Fatal(err)
+	}
+
+	ep, err := r.Endpoints().Create(ctx(), endpoint.Input{
+		TenantID:   "acme",
+		URL:        "https://example.com/new",
+		EventTypes: []string{"order.*"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	j, err := r.Backfill(ctx(), ep.ID, relay.BackfillOptions{Rate: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if j.Kind != job.KindBackfill || j.TenantID != "acme" {
+		t.Fatalf("unexpected job %+v", j)
+	}
+
+	j = waitJob(t, r, j)
+	if j.State != job.StateCompleted {
+		t.Fatalf("expected completed, got %s (%s)", j.State, j.Error)
+	}
+	if j.Total != 3 || j.Processed != 3 || j.Skipped != 1 {
+		t.Fatalf("expected 3 events with the invoice skipped, got %+v", j)
+	}
+
+	got, _ := s.ListByEndpoint(ctx(), ep.ID, delivery.ListOpts{})
+	if len(got) != 2 {
+		t.Fatalf("expected 2 backfilled deliveries, got %d", len(got))
+	}
+}
+
+func TestBackfillOldestFirst(t *testing.T) {
+	r, s := setup(t)
+	registerType(t, r, "order.created")
+
+	// More than one page of events.
+	var sent []string
+	for range 250 {
+		evt := &event.Event{Type: "order.created", TenantID: "acme", Data: mustJSON(map[string]any{})}
+		if err := r.Send(ctx(), evt); err != nil {
+			t.Fatal(err)
+		}
+		sent = append(sent, evt.ID.String())
+	}
+
+	ep, err := r.Endpoints().Create(ctx(), endpoint.Input{
+		TenantID:   "acme",
+		URL:        "https://example.com/new",
+		EventTypes: []string{"order.*"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	j, err := r.Backfill(ctx(), ep.ID, relay.BackfillOptions{Rate: 10000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if j = waitJob(t, r, j); j.State != job.StateCompleted || j.Processed != 250 {
+		t.Fatalf("expected 250 events processed, got %+v", j)
+	}
+
+	got, err := s.ListByEndpoint(ctx(), ep.ID, delivery.ListOpts{Limit: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(sent) {
+		t.Fatalf("expected %d deliveries, got %d", len(sent), len(got))
+	}
+	enqueued := make(map[string]time.Time, len(got))
+	for _, d := range got {
+		enqueued[d.EventID.String()] = d.NextAttemptAt
+	}
+
+	// Events are stored in ID order; each must be enqueued no earlier than
+	// the one before it.
+	slices.Sort(sent)
+	for i := 1; i < len(sent); i++ {
+		if enqueued[sent[i]].Before(enqueued[sent[i-1]]) {
+			t.Fatalf("event %s was enqueued before the older event %s", sent[i], sent[i-1])
+		}
+	}
+}
+
+func TestBackfillStopsWhenEndpointDisabled(t *testing.T) {
+	r, s := setup(t)
+	registerType(t, r, "order.created")
+
+	for range 250 {
+		if err := r.Send(ctx(), &event.Event{Type: "order.created", TenantID: "acme", Data: mustJSON(map[string]any{})}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ep, err := r.Endpoints().Create(ctx(), endpoint.Input{
+		TenantID:   "acme",
+		URL:        "https://example.com/new",
+		EventTypes: []string{"order.*"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// At 500 events per second the first page takes a fifth of a second,
+	// long enough to disable the endpoint before the next one.
+	j, err := r.Backfill(ctx(), ep.ID, relay.BackfillOptions{Rate: 500})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Endpoints().SetEnabled(ctx(), ep.ID, false); err != nil {
+		t.Fatal(err)
+	}
+
+	j = waitJob(t, r, j)
+	if j.State != job.StateFailed || j.Processed >= 250 {
+		t.Fatalf("expected the backfill to stop early, got %+v", j)
+	}
+
+	got, err := s.ListByEndpoint(ctx(), ep.ID, delivery.ListOpts{Limit: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != int(j.Processed) {
+		t.Fatalf("expected %d deliveries, got %d", j.Processed, len(got))
+	}
+}
+
+func TestBackfillSkipsTestEvents(t *testing.T) {
+	r, s := setup(t)
+	registerType(t, r, "order.created")
+
+	if err := r.Send(ctx(), &event.Event{Type: "order.created", TenantID: "acme", Data: mustJSON(map[string]any{})}); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	tested, err := r.Endpoints().Create(ctx(), endpoint.Input{
+		TenantID:   "acme",
+		URL:        srv.URL,
+		EventTypes: []string{"order.*"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.SendTest(ctx(), tested.ID, "order.created", mustJSON(map[string]any{"id": "example"})); err != nil {
+		t.Fatal(err)
+	}
+
+	ep, err := r.Endpoints().Create(ctx(), endpoint.Input{
+		TenantID:   "acme",
+		URL:        "https://example.com/new",
+		EventTypes: []string{"order.*"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	j, err := r.Backfill(ctx(), ep.ID, relay.BackfillOptions{Rate: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if j = waitJob(t, r, j); j.State != job.StateCompleted || j.Total != 1 || j.Processed != 1 {
+		t.Fatalf("expected only the real event backfilled, got %+v", j)
+	}
+
+	got, err := s.ListByEndpoint(ctx(), ep.ID, delivery.ListOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected 1 backfilled delivery, got %d", len(got))
+	}
+	evt, err := s.GetEvent(ctx(), got[0].EventID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if evt.Test {
+		t.Fatal("backfilled the test event")
+	}
+}
@@ -0,0 +1,46 @@
+package relay
+
+import "github.com/xraph/relay/id"
+
+// SendResult describes what a send did.
+type SendResult struct {
+	// EventID is the ID of the persisted event. It is id.Nil for duplicates
+	// and dry runs, where nothing was written.
+	EventID id.ID `json:"event_id,omitzero"`
+
+	// Duplicate reports that the event's idempotency key had already been
+	// used. OriginalEventID then holds the ID of the event stored under it.
+	Duplicate       bool  `json:"duplicate,omitempty"`
+	OriginalEventID id.ID `json:"original_event_id,omitzero"`
+
+	// EndpointIDs lists the endpoints the event was resolved to, in
+	// resolution order.
+	EndpointIDs []id.ID `json:"endpoint_ids"`
+
+	// DeliveryIDs lists the deliveries created, one per endpoint. It is
+	// empty for duplicates and dry runs.
+	DeliveryIDs []id.ID `json:"delivery_ids"`
+
+	// DryRun reports that the send was a dry run.
+	DryRun bool `json:"dry_run,omitempty"`
+
+	// Warnings are non-fatal problems the producer should act on, such as
+	// the event type being deprecated.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// SendOption configures SendWithResult.
+type SendOption func(*sendOptions)
+
+type sendOptions struct {
+	dryRun bool
+}
+
+// DryRun runs catalog validation, schema validation and endpoint resolution
+// without persisting the event or creating deliveries. Idempotency keys are
+// still checked, so a dry run reports whether the real send would be a
+// duplicate. The send hooks are not run and the event passed in is left
+// unchanged.
+func DryRun() SendOption {
+	return func(o *sendOptions) { o.dryRun = true }
+}
@@ -1,2 +1,8 @@
-// Package scope provides Forge scope capture and restore helpers.
+// Package scope carries the app and organization a call acts for through a
+// context.Context.
+//
+// Relay stamps the scope it finds in the context on the events, endpoints
+// and event types it creates, and restores an event's scope into the
+// context of each of its deliveries. In a Forge application the api
+// package's ForgeScopeMiddleware copies the request's forge.Scope in.
 package scope
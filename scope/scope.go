@@ -1,18 +1,33 @@
-// Package scope captures and restores multi-tenant scope from context.
-// In standalone mode, scope is a no-op. When used with Forge, it extracts
-// app and organization IDs from the context.
 package scope
 
 import "context"
 
-// Capture extracts the current app and org scope from the context.
-// Returns empty strings when not running inside Forge.
-func Capture(_ context.Context) (appID, orgID string) {
-	return "", ""
+// ctxKey is the context key of the carried scope.
+type ctxKey struct{}
+
+type values struct {
+	appID string
+	orgID string
+}
+
+// Capture returns the app and org scope carried by ctx, or empty strings
+// when there is none.
+func Capture(ctx context.Context) (appID, orgID string) {
+	v, _ := ctx.Value(ctxKey{}).(values)
+	return v.appID, v.orgID
+}
+
+// Restore returns a context carrying appID and orgID in place of any scope
+// ctx carried. It returns ctx itself when that scope is unchanged.
+func Restore(ctx context.Context, appID, orgID string) context.Context {
+	if curApp, curOrg := Capture(ctx); curApp == appID && curOrg == orgID {
+		return ctx
+	}
+	return context.WithValue(ctx, ctxKey{}, values{appID: appID, orgID: orgID})
 }
 
-// Restore injects app and org scope into the context.
-// Returns the context unchanged when scope values are empty.
-func Restore(ctx context.Context, _, _ string) context.Context {
-	return ctx
+// Matches reports whether something scoped to appID and orgID passes a
+// filter on wantApp and wantOrg. An empty filter field matches anything.
+func Matches(appID, orgID, wantApp, wantOrg string) bool {
+	return (wantApp == "" || appID == wantApp) && (wantOrg == "" || orgID == wantOrg)
 }
@@ -0,0 +1,52 @@
+package scope_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/xraph/relay/scope"
+)
+
+func TestCaptureRestore(t *testing.T) {
+	ctx := context.Background()
+	if app, org := scope.Capture(ctx); app != "" || org != "" {
+		t.Fatalf("expected no scope, got %q/%q", app, org)
+	}
+
+	ctx = scope.Restore(ctx, "app_1", "org_1")
+	if app, org := scope.Capture(ctx); app != "app_1" || org != "org_1" {
+		t.Fatalf("expected app_1/org_1, got %q/%q", app, org)
+	}
+	if same := scope.Restore(ctx, "app_1", "org_1"); same != ctx {
+		t.Fatal("restoring the same scope should return ctx")
+	}
+
+	// Restoring replaces the scope, including with an empty one.
+	ctx = scope.Restore(ctx, "app_2", "")
+	if app, org := scope.Capture(ctx); app != "app_2" || org != "" {
+		t.Fatalf("expected app_2 without org, got %q/%q", app, org)
+	}
+	ctx = scope.Restore(ctx, "", "")
+	if app, org := scope.Capture(ctx); app != "" || org != "" {
+		t.Fatalf("expected the scope cleared, got %q/%q", app, org)
+	}
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		appID, orgID, wantApp, wantOrg string
+		want                           bool
+	}{
+		{"app_1", "org_1", "", "", true},
+		{"app_1", "org_1", "app_1", "", true},
+		{"app_1", "org_1", "app_1", "org_1", true},
+		{"app_1", "org_1", "app_2", "", false},
+		{"app_1", "org_1", "app_1", "org_2", false},
+		{"", "", "app_1", "", false},
+	}
+	for _, tt := range tests {
+		if got := scope.Matches(tt.appID, tt.orgID, tt.wantApp, tt.wantOrg); got != tt.want {
+			t.Errorf("Matches(%q, %q, %q, %q) = %v, want %v", tt.appID, tt.orgID, tt.wantApp, tt.wantOrg, got, tt.want)
+		}
+	}
+}
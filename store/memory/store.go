@@ -4,21 +4,28 @@ package memory
 import (
 	"context"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/xraph/relay"
+	"github.com/xraph/relay/auth"
 	"github.com/xraph/relay/catalog"
 	"github.com/xraph/relay/delivery"
 	"github.com/xraph/relay/dlq"
 	"github.com/xraph/relay/endpoint"
 	"github.com/xraph/relay/event"
 	"github.com/xraph/relay/id"
+	"github.com/xraph/relay/scope"
 	relaystore "github.com/xraph/relay/store"
 )
 
-// compile-time interface check.
-var _ relaystore.Store = (*Store)(nil)
+// compile-time interface checks.
+var (
+	_ relaystore.Store       = (*Store)(nil)
+	_ relaystore.Outbox      = (*Store)(nil)
+	_ relaystore.BatchOutbox = (*Store)(nil)
+)
 
 // Store is an in-memory implementation of store.Store for testing.
 type Store struct {
@@ -32,13 +39,16 @@ type Store struct {
 	deliveries      map[string]*delivery.Delivery // keyed by ID string
 	locked          map[string]bool               // simulates SKIP LOCKED
 	dlqEntries      map[string]*dlq.Entry         // keyed by ID string
+	apiKeys         map[string]*auth.APIKey       // keyed by ID string
+
+	index *endpoint.Index // subscription index backing Resolve
 
 	closed bool
 }
 
 // New creates a new in-memory store.
 func New() *Store {
-	return &Store{
+	s := &Store{
 		eventTypes:      make(map[string]*catalog.EventType),
 		eventTypesByID:  make(map[string]*catalog.EventType),
 		endpoints:       make(map[string]*endpoint.Endpoint),
@@ -47,7 +57,10 @@ func New() *Store {
 		deliveries:      make(map[string]*delivery.Delivery),
 		locked:          make(map[string]bool),
 		dlqEntries:      make(map[string]*dlq.Entry),
+		apiKeys:         make(map[string]*auth.APIKey),
 	}
+	s.index = endpoint.NewIndex(s.tenantEndpoints, 0)
+	return s
 }
 
 // ──────────────────────────────────────────────────
@@ -86,6 +99,7 @@ func (s *Store) RegisterType(_ context.Context, et *catalog.EventType) error {
 
 	if existing, ok := s.eventTypes[et.Definition.Name]; ok {
 		existing.Definition = et.Definition
+		existing.Versions = et.Versions
 		existing.UpdatedAt = time.Now().UTC()
 		existing.Metadata = et.Metadata
 		et.ID = existing.ID
@@ -128,23 +142,30 @@ func (s *Store) ListTypes(_ context.Context, opts catalog.ListOpts) ([]*catalog.
 
 	result := make([]*catalog.EventType, 0, len(s.eventTypes))
 	for _, et := range s.eventTypes {
-		if !opts.IncludeDeprecated && et.IsDeprecated {
-			continue
-		}
-		if opts.Group != "" && et.Definition.Group != opts.Group {
-			continue
+		if matchTypeOpts(et, opts) && afterCursor(et.ID, opts.Cursor, false) {
+			result = append(result, et)
 		}
-		result = append(result, et)
 	}
 
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].Definition.Name < result[j].Definition.Name
-	})
-
+	sortByID(result, func(et *catalog.EventType) id.ID { return et.ID }, false)
 	result = applyPagination(result, opts.Offset, opts.Limit)
 	return result, nil
 }
 
+// CountTypes returns the number of event types ListTypes lists for opts.
+func (s *Store) CountTypes(_ context.Context, opts catalog.ListOpts) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var count int64
+	for _, et := range s.eventTypes {
+		if matchTypeOpts(et, opts) {
+			count++
+		}
+	}
+	return count, nil
+}
+
 // DeleteType soft-deletes (deprecates) an event type.
 func (s *Store) DeleteType(_ context.Context, name string) error {
 	s.mu.Lock()
@@ -156,12 +177,55 @@ func (s *Store) DeleteType(_ context.Context, name string) error {
 	}
 
 	now := time.Now().UTC()
+	if et.IsSunset(now) {
+		return relay.ErrEventTypeNotFound
+	}
+	if !et.IsDeprecated {
+		et.DeprecatedAt = &now
+	}
 	et.IsDeprecated = true
-	et.DeprecatedAt = &now
+	et.SunsetAt = &now
 	et.UpdatedAt = now
 	return nil
 }
 
+// DeprecateType deprecates an event type until sunsetAt.
+func (s *Store) DeprecateType(_ context.Context, name string, sunsetAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	et, ok := s.eventTypes[name]
+	if !ok {
+		return relay.ErrEventTypeNotFound
+	}
+
+	now := time.Now().UTC()
+	if !et.IsDeprecated {
+		et.DeprecatedAt = &now
+	}
+	et.IsDeprecated = true
+	et.SunsetAt = &sunsetAt
+	et.UpdatedAt = now
+	return nil
+}
+
+// UndeprecateType clears an event type's deprecation.
+func (s *Store) UndeprecateType(_ context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	et, ok := s.eventTypes[name]
+	if !ok {
+		return relay.ErrEventTypeNotFound
+	}
+
+	et.IsDeprecated = false
+	et.DeprecatedAt = nil
+	et.SunsetAt = nil
+	et.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
 // MatchTypes returns event types matching a glob pattern.
 func (s *Store) MatchTypes(_ context.Context, pattern string) ([]*catalog.EventType, error) {
 	s.mu.RLock()
@@ -189,6 +253,7 @@ func (s *Store) CreateEndpoint(_ context.Context, ep *endpoint.Endpoint) error {
 	defer s.mu.Unlock()
 
 	s.endpoints[ep.ID.String()] = ep
+	s.index.Invalidate(ep.TenantID)
 	return nil
 }
 
@@ -209,11 +274,14 @@ func (s *Store) UpdateEndpoint(_ context.Context, ep *endpoint.Endpoint) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if _, ok := s.endpoints[ep.ID.String()]; !ok {
+	prev, ok := s.endpoints[ep.ID.String()]
+	if !ok {
 		return relay.ErrEndpointNotFound
 	}
 	ep.UpdatedAt = time.Now().UTC()
 	s.endpoints[ep.ID.String()] = ep
+	s.index.Invalidate(prev.TenantID)
+	s.index.Invalidate(ep.TenantID)
 	return nil
 }
 
@@ -222,10 +290,12 @@ func (s *Store) DeleteEndpoint(_ context.Context, epID id.ID) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if _, ok := s.endpoints[epID.String()]; !ok {
+	ep, ok := s.endpoints[epID.String()]
+	if !ok {
 		return relay.ErrEndpointNotFound
 	}
 	delete(s.endpoints, epID.String())
+	s.index.Invalidate(ep.TenantID)
 	return nil
 }
 
@@ -236,33 +306,37 @@ func (s *Store) ListEndpoints(_ context.Context, tenantID string, opts endpoint.
 
 	result := make([]*endpoint.Endpoint, 0, len(s.endpoints))
 	for _, ep := range s.endpoints {
-		if ep.TenantID != tenantID {
-			continue
+		if matchEndpointOpts(ep, tenantID, opts) && afterCursor(ep.ID, opts.Cursor, false) {
+			result = append(result, ep)
 		}
-		if opts.Enabled != nil && ep.Enabled != *opts.Enabled {
-			continue
-		}
-		result = append(result, ep)
 	}
 
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].CreatedAt.Before(result[j].CreatedAt)
-	})
-
+	sortByID(result, func(ep *endpoint.Endpoint) id.ID { return ep.ID }, false)
 	result = applyPagination(result, opts.Offset, opts.Limit)
 	return result, nil
 }
 
-// Resolve finds all active endpoints matching an event type for a tenant.
-func (s *Store) Resolve(_ context.Context, tenantID, eventType string) ([]*endpoint.Endpoint, error) {
+// CountEndpoints returns the number of endpoints ListEndpoints lists.
+func (s *Store) CountEndpoints(_ context.Context, tenantID string, opts endpoint.ListOpts) (int64, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	var result []*endpoint.Endpoint
+	var count int64
 	for _, ep := range s.endpoints {
-		if ep.TenantID != tenantID || !ep.Enabled {
-			continue
+		if matchEndpointOpts(ep, tenantID, opts) {
+			count++
 		}
+	}
+	return count, nil
+}
+
+// ListSubscribers returns endpoints of all tenants subscribed to eventType.
+func (s *Store) ListSubscribers(_ context.Context, eventType string) ([]*endpoint.Endpoint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []*endpoint.Endpoint
+	for _, ep := range s.endpoints {
 		for _, pattern := range ep.EventTypes {
 			if catalog.Match(pattern, eventType) {
 				result = append(result, ep)
@@ -270,10 +344,57 @@ func (s *Store) Resolve(_ context.Context, tenantID, eventType string) ([]*endpo
 			}
 		}
 	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
 	return result, nil
 }
 
-// SetEnabled enables or disables an endpoint.
+// ListAutoDisabled returns the endpoints of all tenants disabled by the
+// delivery engine.
+func (s *Store) ListAutoDisabled(_ context.Context) ([]*endpoint.Endpoint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []*endpoint.Endpoint
+	for _, ep := range s.endpoints {
+		if !ep.Enabled && ep.DisabledReason != "" {
+			result = append(result, ep)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+	return result, nil
+}
+
+// Resolve finds all active endpoints matching an event type for a tenant.
+func (s *Store) Resolve(ctx context.Context, tenantID, eventType string) ([]*endpoint.Endpoint, error) {
+	return s.index.Resolve(ctx, tenantID, eventType)
+}
+
+// tenantEndpoints loads a tenant's endpoints in creation order for the
+// subscription index.
+func (s *Store) tenantEndpoints(_ context.Context, tenantID string) ([]*endpoint.Endpoint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var result []*endpoint.Endpoint
+	for _, ep := range s.endpoints {
+		if ep.TenantID == tenantID {
+			result = append(result, ep)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+	return result, nil
+}
+
+// SetEnabled enables or disables an endpoint and clears why the delivery
+// engine disabled it.
 func (s *Store) SetEnabled(_ context.Context, epID id.ID, enabled bool) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -283,7 +404,27 @@ func (s *Store) SetEnabled(_ context.Context, epID id.ID, enabled bool) error {
 		return relay.ErrEndpointNotFound
 	}
 	ep.Enabled = enabled
+	ep.DisabledReason = ""
+	ep.DisabledAt = nil
+	ep.UpdatedAt = time.Now().UTC()
+	s.index.Invalidate(ep.TenantID)
+	return nil
+}
+
+// DisableEndpoint disables an endpoint on behalf of the delivery engine.
+func (s *Store) DisableEndpoint(_ context.Context, epID id.ID, reason string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ep, ok := s.endpoints[epID.String()]
+	if !ok {
+		return relay.ErrEndpointNotFound
+	}
+	ep.Enabled = false
+	ep.DisabledReason = reason
+	ep.DisabledAt = &at
 	ep.UpdatedAt = time.Now().UTC()
+	s.index.Invalidate(ep.TenantID)
 	return nil
 }
 
@@ -307,6 +448,54 @@ func (s *Store) CreateEvent(_ context.Context, evt *event.Event) error {
 	return nil
 }
 
+// CreateEventWithDeliveries persists an event and its deliveries under a
+// single lock, so no reader ever observes one without the other.
+func (s *Store) CreateEventWithDeliveries(_ context.Context, evt *event.Event, ds []*delivery.Delivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if evt.IdempotencyKey != "" {
+		if _, ok := s.eventsByIdemKey[evt.IdempotencyKey]; ok {
+			return relay.ErrDuplicateIdempotencyKey
+		}
+		s.eventsByIdemKey[evt.IdempotencyKey] = evt
+	}
+
+	s.events[evt.ID.String()] = evt
+	for _, d := range ds {
+		s.deliveries[d.ID.String()] = d
+	}
+	return nil
+}
+
+// CreateEventBatch persists events and their deliveries under a single lock,
+// skipping events whose idempotency key is already taken.
+func (s *Store) CreateEventBatch(_ context.Context, evts []*event.Event, ds []*delivery.Delivery) ([]id.ID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var duplicates []id.ID
+	skipped := make(map[string]bool)
+	for _, evt := range evts {
+		if evt.IdempotencyKey != "" {
+			if _, ok := s.eventsByIdemKey[evt.IdempotencyKey]; ok {
+				duplicates = append(duplicates, evt.ID)
+				skipped[evt.ID.String()] = true
+				continue
+			}
+			s.eventsByIdemKey[evt.IdempotencyKey] = evt
+		}
+		s.events[evt.ID.String()] = evt
+	}
+
+	for _, d := range ds {
+		if !skipped[d.EventID.String()] {
+			s.deliveries[d.ID.String()] = d
+		}
+	}
+	return duplicates, nil
+}
+
 // GetEvent returns an event by ID.
 func (s *Store) GetEvent(_ context.Context, evtID id.ID) (*event.Event, error) {
 	s.mu.RLock()
@@ -319,6 +508,18 @@ func (s *Store) GetEvent(_ context.Context, evtID id.ID) (*event.Event, error) {
 	return evt, nil
 }
 
+// GetEventByIdempotencyKey returns the event stored under an idempotency key.
+func (s *Store) GetEventByIdempotencyKey(_ context.Context, key string) (*event.Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	evt, ok := s.eventsByIdemKey[key]
+	if !ok {
+		return nil, relay.ErrEventNotFound
+	}
+	return evt, nil
+}
+
 // ListEvents returns events, optionally filtered.
 func (s *Store) ListEvents(_ context.Context, opts event.ListOpts) ([]*event.Event, error) {
 	s.mu.RLock()
@@ -326,20 +527,30 @@ func (s *Store) ListEvents(_ context.Context, opts event.ListOpts) ([]*event.Eve
 
 	result := make([]*event.Event, 0, len(s.events))
 	for _, evt := range s.events {
-		if !matchEventOpts(evt, opts) {
-			continue
+		if matchEventOpts(evt, opts) && afterCursor(evt.ID, opts.Cursor, !opts.Ascending) {
+			result = append(result, evt)
 		}
-		result = append(result, evt)
 	}
 
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].CreatedAt.After(result[j].CreatedAt)
-	})
-
+	sortByID(result, func(evt *event.Event) id.ID { return evt.ID }, !opts.Ascending)
 	result = applyPagination(result, opts.Offset, opts.Limit)
 	return result, nil
 }
 
+// CountEvents returns the number of events ListEvents lists for opts.
+func (s *Store) CountEvents(_ context.Context, opts event.ListOpts) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var count int64
+	for _, evt := range s.events {
+		if matchEventOpts(evt, opts) {
+			count++
+		}
+	}
+	return count, nil
+}
+
 // ListEventsByTenant returns events for a specific tenant.
 func (s *Store) ListEventsByTenant(_ context.Context, tenantID string, opts event.ListOpts) ([]*event.Event, error) {
 	s.mu.RLock()
@@ -347,23 +558,30 @@ func (s *Store) ListEventsByTenant(_ context.Context, tenantID string, opts even
 
 	result := make([]*event.Event, 0, len(s.events))
 	for _, evt := range s.events {
-		if evt.TenantID != tenantID {
-			continue
-		}
-		if !matchEventOpts(evt, opts) {
-			continue
+		if evt.TenantID == tenantID && matchEventOpts(evt, opts) && afterCursor(evt.ID, opts.Cursor, !opts.Ascending) {
+			result = append(result, evt)
 		}
-		result = append(result, evt)
 	}
 
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].CreatedAt.After(result[j].CreatedAt)
-	})
-
+	sortByID(result, func(evt *event.Event) id.ID { return evt.ID }, !opts.Ascending)
 	result = applyPagination(result, opts.Offset, opts.Limit)
 	return result, nil
 }
 
+// CountEventsByTenant returns the number of events ListEventsByTenant lists.
+func (s *Store) CountEventsByTenant(_ context.Context, tenantID string, opts event.ListOpts) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var count int64
+	for _, evt := range s.events {
+		if evt.TenantID == tenantID && matchEventOpts(evt, opts) {
+			count++
+		}
+	}
+	return count, nil
+}
+
 // ──────────────────────────────────────────────────
 // delivery.Store
 // ──────────────────────────────────────────────────
@@ -459,30 +677,96 @@ func (s *Store) GetDelivery(_ context.Context, delID id.ID) (*delivery.Delivery,
 	return copyDelivery(d), nil
 }
 
-// ListByEndpoint returns delivery history for an endpoint.
-func (s *Store) ListByEndpoint(_ context.Context, epID id.ID, opts delivery.ListOpts) ([]*delivery.Delivery, error) {
+// RetryDelivery makes a pending delivery due now.
+func (s *Store) RetryDelivery(_ context.Context, delID id.ID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, err := s.pendingDelivery(delID)
+	if err != nil {
+		return err
+	}
+	cp := copyDelivery(d)
+	cp.NextAttemptAt = time.Now().UTC()
+	cp.UpdatedAt = cp.NextAttemptAt
+	s.deliveries[delID.String()] = cp
+	return nil
+}
+
+// CancelDelivery moves a pending delivery to StateCancelled.
+func (s *Store) CancelDelivery(_ context.Context, delID id.ID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, err := s.pendingDelivery(delID)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	cp := copyDelivery(d)
+	cp.State = delivery.StateCancelled
+	cp.CompletedAt = &now
+	cp.UpdatedAt = now
+	s.deliveries[delID.String()] = cp
+	return nil
+}
+
+// pendingDelivery returns a delivery that is pending and not claimed by
+// Dequeue. The caller must hold the write lock.
+func (s *Store) pendingDelivery(delID id.ID) (*delivery.Delivery, error) {
+	d, ok := s.deliveries[delID.String()]
+	if !ok {
+		return nil, relay.ErrDeliveryNotFound
+	}
+	if d.State != delivery.StatePending || s.locked[delID.String()] {
+		return nil, relay.ErrDeliveryNotPending
+	}
+	return d, nil
+}
+
+// ListDeliveries returns deliveries matching opts.
+func (s *Store) ListDeliveries(_ context.Context, opts delivery.ListOpts) ([]*delivery.Delivery, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	result := make([]*delivery.Delivery, 0, len(s.deliveries))
 	for _, d := range s.deliveries {
-		if d.EndpointID.String() != epID.String() {
-			continue
-		}
-		if opts.State != nil && d.State != *opts.State {
-			continue
+		if s.matchDeliveryOpts(d, opts) && afterCursor(d.ID, opts.Cursor, true) {
+			result = append(result, d)
 		}
-		result = append(result, d)
 	}
 
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].CreatedAt.After(result[j].CreatedAt)
-	})
-
+	sortByID(result, func(d *delivery.Delivery) id.ID { return d.ID }, true)
 	result = applyPagination(result, opts.Offset, opts.Limit)
 	return result, nil
 }
 
+// CountDeliveries returns the number of deliveries ListDeliveries lists.
+func (s *Store) CountDeliveries(_ context.Context, opts delivery.ListOpts) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var count int64
+	for _, d := range s.deliveries {
+		if s.matchDeliveryOpts(d, opts) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// ListByEndpoint returns delivery history for an endpoint.
+func (s *Store) ListByEndpoint(ctx context.Context, epID id.ID, opts delivery.ListOpts) ([]*delivery.Delivery, error) {
+	opts.EndpointID = &epID
+	return s.ListDeliveries(ctx, opts)
+}
+
+// CountByEndpoint returns the number of deliveries ListByEndpoint lists.
+func (s *Store) CountByEndpoint(ctx context.Context, epID id.ID, opts delivery.ListOpts) (int64, error) {
+	opts.EndpointID = &epID
+	return s.CountDeliveries(ctx, opts)
+}
+
 // ListByEvent returns all deliveries for a specific event.
 func (s *Store) ListByEvent(_ context.Context, evtID id.ID) ([]*delivery.Delivery, error) {
 	s.mu.RLock()
@@ -532,25 +816,12 @@ func (s *Store) ListDLQ(_ context.Context, opts dlq.ListOpts) ([]*dlq.Entry, err
 
 	result := make([]*dlq.Entry, 0, len(s.dlqEntries))
 	for _, e := range s.dlqEntries {
-		if opts.TenantID != "" && e.TenantID != opts.TenantID {
-			continue
-		}
-		if opts.EndpointID != nil && e.EndpointID.String() != opts.EndpointID.String() {
-			continue
+		if matchDLQOpts(e, opts) && afterCursor(e.ID, opts.Cursor, true) {
+			result = append(result, e)
 		}
-		if opts.From != nil && e.FailedAt.Before(*opts.From) {
-			continue
-		}
-		if opts.To != nil && e.FailedAt.After(*opts.To) {
-			continue
-		}
-		result = append(result, e)
 	}
 
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].FailedAt.After(result[j].FailedAt)
-	})
-
+	sortByID(result, func(e *dlq.Entry) id.ID { return e.ID }, true)
 	result = applyPagination(result, opts.Offset, opts.Limit)
 	return result, nil
 }
@@ -567,8 +838,8 @@ func (s *Store) GetDLQ(_ context.Context, dlqID id.ID) (*dlq.Entry, error) {
 	return e, nil
 }
 
-// Replay marks a DLQ entry for redelivery and re-enqueues the delivery.
-func (s *Store) Replay(_ context.Context, dlqID id.ID) error {
+// Replay enqueues d and marks the DLQ entry replayed.
+func (s *Store) Replay(_ context.Context, dlqID id.ID, d *delivery.Delivery) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -576,85 +847,206 @@ func (s *Store) Replay(_ context.Context, dlqID id.ID) error {
 	if !ok {
 		return relay.ErrDLQNotFound
 	}
+	if e.ReplayedAt != nil {
+		return dlq.ErrAlreadyReplayed
+	}
+
+	s.deliveries[d.ID.String()] = d
 
 	now := time.Now().UTC()
+	replayID := d.ID
 	e.ReplayedAt = &now
-
-	d := &delivery.Delivery{
-		Entity:        relay.NewEntity(),
-		ID:            id.NewDeliveryID(),
-		EventID:       e.EventID,
-		EndpointID:    e.EndpointID,
-		State:         delivery.StatePending,
-		AttemptCount:  0,
-		MaxAttempts:   5,
-		NextAttemptAt: now,
-	}
-	s.deliveries[d.ID.String()] = d
+	e.ReplayDeliveryID = &replayID
+	e.UpdatedAt = now
 	return nil
 }
 
-// ReplayBulk replays all DLQ entries in a time window.
-func (s *Store) ReplayBulk(_ context.Context, from, to time.Time) (int64, error) {
+// Purge deletes DLQ entries older than a threshold.
+func (s *Store) Purge(_ context.Context, before time.Time) (int64, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	now := time.Now().UTC()
 	var count int64
-
-	for _, e := range s.dlqEntries {
-		if e.FailedAt.Before(from) || e.FailedAt.After(to) {
-			continue
-		}
-		if e.ReplayedAt != nil {
-			continue
+	for k, e := range s.dlqEntries {
+		if e.CreatedAt.Before(before) {
+			delete(s.dlqEntries, k)
+			count++
 		}
+	}
+	return count, nil
+}
 
-		e.ReplayedAt = &now
+// CountDLQ returns the number of DLQ entries ListDLQ lists for opts.
+func (s *Store) CountDLQ(_ context.Context, opts dlq.ListOpts) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
-		d := &delivery.Delivery{
-			Entity:        relay.NewEntity(),
-			ID:            id.NewDeliveryID(),
-			EventID:       e.EventID,
-			EndpointID:    e.EndpointID,
-			State:         delivery.StatePending,
-			AttemptCount:  0,
-			MaxAttempts:   5,
-			NextAttemptAt: now,
+	var count int64
+	for _, e := range s.dlqEntries {
+		if matchDLQOpts(e, opts) {
+			count++
 		}
-		s.deliveries[d.ID.String()] = d
-		count++
 	}
 	return count, nil
 }
 
-// Purge deletes DLQ entries older than a threshold.
-func (s *Store) Purge(_ context.Context, before time.Time) (int64, error) {
+// ──────────────────────────────────────────────────
+// auth.Store
+// ──────────────────────────────────────────────────
+
+// CreateAPIKey persists a new API key.
+func (s *Store) CreateAPIKey(_ context.Context, key *auth.APIKey) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	s.apiKeys[key.ID.String()] = key
+	return nil
+}
+
+// GetAPIKey returns an API key by ID.
+func (s *Store) GetAPIKey(_ context.Context, keyID id.ID) (*auth.APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	key, ok := s.apiKeys[keyID.String()]
+	if !ok {
+		return nil, relay.ErrAPIKeyNotFound
+	}
+	return key, nil
+}
+
+// GetAPIKeyByHash returns the API key with the given hash.
+func (s *Store) GetAPIKeyByHash(_ context.Context, hash string) (*auth.APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, key := range s.apiKeys {
+		if key.Hash == hash {
+			return key, nil
+		}
+	}
+	return nil, relay.ErrAPIKeyNotFound
+}
+
+// ListAPIKeys returns API keys, optionally filtered by tenant.
+func (s *Store) ListAPIKeys(_ context.Context, opts auth.ListOpts) ([]*auth.APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]*auth.APIKey, 0, len(s.apiKeys))
+	for _, key := range s.apiKeys {
+		if (opts.TenantID == "" || key.TenantID == opts.TenantID) && afterCursor(key.ID, opts.Cursor, false) {
+			result = append(result, key)
+		}
+	}
+
+	sortByID(result, func(key *auth.APIKey) id.ID { return key.ID }, false)
+	result = applyPagination(result, opts.Offset, opts.Limit)
+	return result, nil
+}
+
+// CountAPIKeys returns the number of API keys ListAPIKeys lists for opts.
+func (s *Store) CountAPIKeys(_ context.Context, opts auth.ListOpts) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	var count int64
-	for k, e := range s.dlqEntries {
-		if e.CreatedAt.Before(before) {
-			delete(s.dlqEntries, k)
+	for _, key := range s.apiKeys {
+		if opts.TenantID == "" || key.TenantID == opts.TenantID {
 			count++
 		}
 	}
 	return count, nil
 }
 
-// CountDLQ returns the total number of DLQ entries.
-func (s *Store) CountDLQ(_ context.Context) (int64, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+// DeleteAPIKey removes an API key.
+func (s *Store) DeleteAPIKey(_ context.Context, keyID id.ID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	return int64(len(s.dlqEntries)), nil
+	if _, ok := s.apiKeys[keyID.String()]; !ok {
+		return relay.ErrAPIKeyNotFound
+	}
+	delete(s.apiKeys, keyID.String())
+	return nil
 }
 
 // ──────────────────────────────────────────────────
 // Helpers
 // ──────────────────────────────────────────────────
 
+func matchTypeOpts(et *catalog.EventType, opts catalog.ListOpts) bool {
+	if !opts.IncludeDeprecated && et.IsDeprecated {
+		return false
+	}
+	if opts.Group != "" && et.Definition.Group != opts.Group {
+		return false
+	}
+	return scope.Matches(et.ScopeAppID, "", opts.ScopeAppID, "")
+}
+
+func matchEndpointOpts(ep *endpoint.Endpoint, tenantID string, opts endpoint.ListOpts) bool {
+	if ep.TenantID != tenantID {
+		return false
+	}
+	if opts.Enabled != nil && ep.Enabled != *opts.Enabled {
+		return false
+	}
+	return scope.Matches(ep.ScopeAppID, ep.ScopeOrgID, opts.ScopeAppID, opts.ScopeOrgID)
+}
+
+// matchDeliveryOpts reports whether d passes the filters of opts. The
+// caller must hold the lock, as the tenant is that of d's endpoint.
+func (s *Store) matchDeliveryOpts(d *delivery.Delivery, opts delivery.ListOpts) bool {
+	if opts.EndpointID != nil && d.EndpointID.String() != opts.EndpointID.String() {
+		return false
+	}
+	if opts.EventID != nil && d.EventID.String() != opts.EventID.String() {
+		return false
+	}
+	if opts.State != nil && d.State != *opts.State {
+		return false
+	}
+	if opts.From != nil && d.CreatedAt.Before(*opts.From) {
+		return false
+	}
+	if opts.To != nil && d.CreatedAt.After(*opts.To) {
+		return false
+	}
+	if opts.TenantID != "" {
+		ep, ok := s.endpoints[d.EndpointID.String()]
+		if !ok || ep.TenantID != opts.TenantID {
+			return false
+		}
+	}
+	return true
+}
+
+func matchDLQOpts(e *dlq.Entry, opts dlq.ListOpts) bool {
+	if opts.TenantID != "" && e.TenantID != opts.TenantID {
+		return false
+	}
+	if opts.EndpointID != nil && e.EndpointID.String() != opts.EndpointID.String() {
+		return false
+	}
+	if opts.From != nil && e.FailedAt.Before(*opts.From) {
+		return false
+	}
+	if opts.To != nil && e.FailedAt.After(*opts.To) {
+		return false
+	}
+	if opts.EventType != "" && e.EventType != opts.EventType {
+		return false
+	}
+	if opts.StatusCode != 0 && e.LastStatusCode != opts.StatusCode {
+		return false
+	}
+	if opts.ErrorContains != "" && !strings.Contains(e.Error, opts.ErrorContains) {
+		return false
+	}
+	return !opts.Unreplayed || e.ReplayedAt == nil
+}
+
 func matchEventOpts(evt *event.Event, opts event.ListOpts) bool {
 	if opts.Type != "" && evt.Type != opts.Type {
 		return false
@@ -665,7 +1057,32 @@ func matchEventOpts(evt *event.Event, opts event.ListOpts) bool {
 	if opts.To != nil && evt.CreatedAt.After(*opts.To) {
 		return false
 	}
-	return true
+	if opts.ExcludeTest && evt.Test {
+		return false
+	}
+	return scope.Matches(evt.ScopeAppID, evt.ScopeOrgID, opts.ScopeAppID, opts.ScopeOrgID)
+}
+
+// afterCursor reports whether itemID follows cursor in a list ordered by
+// ID, descending if desc. Every item follows a nil cursor.
+func afterCursor(itemID, cursor id.ID, desc bool) bool {
+	if cursor.IsNil() {
+		return true
+	}
+	if desc {
+		return itemID.String() < cursor.String()
+	}
+	return itemID.String() > cursor.String()
+}
+
+// sortByID orders items by the ID key returns, descending if desc.
+func sortByID[T any](items []*T, key func(*T) id.ID, desc bool) {
+	sort.Slice(items, func(i, j int) bool {
+		if desc {
+			return key(items[i]).String() > key(items[j]).String()
+		}
+		return key(items[i]).String() < key(items[j]).String()
+	})
 }
 
 func applyPagination[T any](items []*T, offset, limit int) []*T {
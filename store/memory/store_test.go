@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/xraph/relay"
+	"github.com/xraph/relay/auth"
 	"github.com/xraph/relay/catalog"
 	"github.com/xraph/relay/delivery"
 	"github.com/xraph/relay/dlq"
@@ -14,6 +15,7 @@ import (
 	"github.com/xraph/relay/event"
 	"github.com/xraph/relay/id"
 	"github.com/xraph/relay/internal/entity"
+	"github.com/xraph/relay/internal/storetest"
 )
 
 func ctx() context.Context { return context.Background() }
@@ -140,6 +142,56 @@ func TestCatalogCRUD(t *testing.T) {
 	}
 }
 
+func TestCatalogDeprecateType(t *testing.T) {
+	s := New()
+
+	et := &catalog.EventType{
+		Entity:     entity.New(),
+		ID:         id.NewEventTypeID(),
+		Definition: catalog.WebhookDefinition{Name: "invoice.created"},
+	}
+	if err := s.RegisterType(ctx(), et); err != nil {
+		t.Fatal(err)
+	}
+
+	sunset := time.Now().Add(time.Hour).UTC()
+	if err := s.DeprecateType(ctx(), "invoice.created", sunset); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := s.GetType(ctx(), "invoice.created")
+	if !got.IsDeprecated || got.SunsetAt == nil || !got.SunsetAt.Equal(sunset) {
+		t.Fatalf("expected deprecation with sunset %v, got %+v", sunset, got)
+	}
+	if got.IsSunset(time.Now()) {
+		t.Fatal("expected type to be usable before its sunset")
+	}
+
+	// Deleting a type in its warning period sunsets it immediately.
+	if err := s.DeleteType(ctx(), "invoice.created"); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = s.GetType(ctx(), "invoice.created")
+	if !got.IsSunset(time.Now()) {
+		t.Fatal("expected deleted type to be sunset")
+	}
+
+	if err := s.UndeprecateType(ctx(), "invoice.created"); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = s.GetType(ctx(), "invoice.created")
+	if got.IsDeprecated || got.DeprecatedAt != nil || got.SunsetAt != nil {
+		t.Fatalf("expected deprecation cleared, got %+v", got)
+	}
+
+	if err := s.DeprecateType(ctx(), "does.not.exist", sunset); !errors.Is(err, relay.ErrEventTypeNotFound) {
+		t.Fatalf("expected ErrEventTypeNotFound, got %v", err)
+	}
+}
+
+func TestCatalogRegisterKeepsDeprecation(t *testing.T) {
+	storetest.RegisterKeepsDeprecation(t, New())
+}
+
 func TestCatalogListWithGroupFilter(t *testing.T) {
 	s := New()
 
@@ -311,6 +363,44 @@ func TestEndpointSetEnabled(t *testing.T) {
 	}
 }
 
+func TestDisableEndpoint(t *testing.T) {
+	s := New()
+
+	ep := newEndpoint("t1", []string{"*"})
+	_ = s.CreateEndpoint(ctx(), ep)
+
+	// An edit made after the engine loaded the endpoint survives the disable.
+	edited := *ep
+	edited.URL = "https://example.com/moved"
+	if err := s.UpdateEndpoint(ctx(), &edited); err != nil {
+		t.Fatal(err)
+	}
+	at := time.Now().UTC()
+	if err := s.DisableEndpoint(ctx(), ep.ID, "endpoint answered 410 Gone", at); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := s.GetEndpoint(ctx(), ep.ID)
+	if got.Enabled || got.DisabledReason == "" || got.DisabledAt == nil {
+		t.Fatalf("expected a disabled endpoint with a reason, got %+v", got)
+	}
+	if got.URL != edited.URL {
+		t.Fatalf("expected URL %q to be kept, got %q", edited.URL, got.URL)
+	}
+
+	// Re-enabling clears the reason.
+	if err := s.SetEnabled(ctx(), ep.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	got, _ = s.GetEndpoint(ctx(), ep.ID)
+	if !got.Enabled || got.DisabledReason != "" || got.DisabledAt != nil {
+		t.Fatalf("expected the reason to be cleared, got %+v", got)
+	}
+
+	if err := s.DisableEndpoint(ctx(), id.NewEndpointID(), "gone", at); !errors.Is(err, relay.ErrEndpointNotFound) {
+		t.Fatalf("expected ErrEndpointNotFound, got %v", err)
+	}
+}
+
 func TestEndpointResolve(t *testing.T) {
 	s := New()
 
@@ -335,6 +425,65 @@ func TestEndpointResolve(t *testing.T) {
 	}
 }
 
+func TestEndpointListSubscribers(t *testing.T) {
+	s := New()
+
+	ep1 := newEndpoint("t1", []string{"invoice.*"})
+	ep2 := newEndpoint("t1", []string{"user.*"})
+	epDisabled := newEndpoint("t1", []string{"invoice.**"})
+	epDisabled.Enabled = false
+	epOtherTenant := newEndpoint("t2", []string{"*"})
+
+	for _, ep := range []*endpoint.Endpoint{ep1, ep2, epDisabled, epOtherTenant} {
+		_ = s.CreateEndpoint(ctx(), ep)
+	}
+
+	// Subscribers span tenants and include disabled endpoints.
+	result, err := s.ListSubscribers(ctx(), "invoice.created")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 3 {
+		t.Fatalf("expected 3 subscribers, got %d", len(result))
+	}
+}
+
+func TestEndpointResolveInvalidation(t *testing.T) {
+	s := New()
+
+	ep := newEndpoint("t1", []string{"invoice.*"})
+	_ = s.CreateEndpoint(ctx(), ep)
+
+	// Prime the index.
+	if result, _ := s.Resolve(ctx(), "t1", "invoice.created"); len(result) != 1 {
+		t.Fatalf("expected 1 resolved, got %d", len(result))
+	}
+
+	// A new endpoint must be visible immediately.
+	ep2 := newEndpoint("t1", []string{"invoice.**"})
+	_ = s.CreateEndpoint(ctx(), ep2)
+	if result, _ := s.Resolve(ctx(), "t1", "invoice.created"); len(result) != 2 {
+		t.Fatalf("expected 2 resolved after create, got %d", len(result))
+	}
+
+	// Disabling, updating and deleting must all be reflected.
+	_ = s.SetEnabled(ctx(), ep.ID, false)
+	if result, _ := s.Resolve(ctx(), "t1", "invoice.created"); len(result) != 1 {
+		t.Fatalf("expected 1 resolved after disable, got %d", len(result))
+	}
+
+	ep2.EventTypes = []string{"user.*"}
+	_ = s.UpdateEndpoint(ctx(), ep2)
+	if result, _ := s.Resolve(ctx(), "t1", "invoice.created"); len(result) != 0 {
+		t.Fatalf("expected 0 resolved after update, got %d", len(result))
+	}
+
+	_ = s.DeleteEndpoint(ctx(), ep2.ID)
+	if result, _ := s.Resolve(ctx(), "t1", "user.created"); len(result) != 0 {
+		t.Fatalf("expected 0 resolved after delete, got %d", len(result))
+	}
+}
+
 func TestEndpointListFilters(t *testing.T) {
 	s := New()
 
@@ -351,10 +500,52 @@ func TestEndpointListFilters(t *testing.T) {
 	}
 }
 
+func TestEndpointListScopeFilters(t *testing.T) {
+	s := New()
+
+	ep1 := newEndpoint("t1", []string{"*"})
+	ep1.ScopeAppID, ep1.ScopeOrgID = "app1", "org1"
+	ep2 := newEndpoint("t1", []string{"*"})
+	ep2.ScopeAppID, ep2.ScopeOrgID = "app1", "org2"
+	_ = s.CreateEndpoint(ctx(), ep1)
+	_ = s.CreateEndpoint(ctx(), ep2)
+
+	list, _ := s.ListEndpoints(ctx(), "t1", endpoint.ListOpts{ScopeAppID: "app1"})
+	if len(list) != 2 {
+		t.Fatalf("expected 2 in app1, got %d", len(list))
+	}
+	list, _ = s.ListEndpoints(ctx(), "t1", endpoint.ListOpts{ScopeAppID: "app1", ScopeOrgID: "org2"})
+	if len(list) != 1 || list[0].ID != ep2.ID {
+		t.Fatalf("expected only ep2 in org2, got %d", len(list))
+	}
+}
+
 // ──────────────────────────────────────────────────
 // event.Store
 // ──────────────────────────────────────────────────
 
+func TestListAutoDisabled(t *testing.T) {
+	s := New()
+
+	active := newEndpoint("t1", []string{"order.*"})
+	byHand := newEndpoint("t1", []string{"order.*"})
+	byHand.Enabled = false
+	auto := newEndpoint("t2", []string{"order.*"})
+	auto.Enabled = false
+	auto.DisabledReason = "endpoint answered 410 Gone"
+	for _, ep := range []*endpoint.Endpoint{active, byHand, auto} {
+		_ = s.CreateEndpoint(ctx(), ep)
+	}
+
+	got, err := s.ListAutoDisabled(ctx())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].ID.String() != auto.ID.String() {
+		t.Fatalf("expected only the auto-disabled endpoint, got %d", len(got))
+	}
+}
+
 func newEvent(tenantID, eventType string) *event.Event {
 	return &event.Event{
 		Entity:   entity.New(),
@@ -449,6 +640,64 @@ func TestEventListByTenant(t *testing.T) {
 	}
 }
 
+func TestEventListScopeFilters(t *testing.T) {
+	s := New()
+
+	evt1 := newEvent("t1", "a")
+	evt1.ScopeAppID, evt1.ScopeOrgID = "app1", "org1"
+	evt2 := newEvent("t1", "b")
+	evt2.ScopeAppID = "app2"
+	_ = s.CreateEvent(ctx(), evt1)
+	_ = s.CreateEvent(ctx(), evt2)
+
+	list, _ := s.ListEvents(ctx(), event.ListOpts{ScopeAppID: "app1"})
+	if len(list) != 1 || list[0].ID != evt1.ID {
+		t.Fatalf("expected only evt1 in app1, got %d", len(list))
+	}
+	list, _ = s.ListEventsByTenant(ctx(), "t1", event.ListOpts{ScopeOrgID: "org2"})
+	if len(list) != 0 {
+		t.Fatalf("expected none in org2, got %d", len(list))
+	}
+}
+
+func TestEventListCursor(t *testing.T) {
+	s := New()
+
+	for _, typ := range []string{"a", "b", "c"} {
+		_ = s.CreateEvent(ctx(), newEvent("t1", typ))
+	}
+	_ = s.CreateEvent(ctx(), newEvent("t2", "d"))
+
+	// Events list newest first; the cursor continues after its ID.
+	first, _ := s.ListEventsByTenant(ctx(), "t1", event.ListOpts{Limit: 2})
+	if len(first) != 2 || first[0].ID.String() < first[1].ID.String() {
+		t.Fatalf("expected 2 events newest first, got %d", len(first))
+	}
+	rest, _ := s.ListEventsByTenant(ctx(), "t1", event.ListOpts{Cursor: first[1].ID})
+	if len(rest) != 1 || rest[0].ID.String() >= first[1].ID.String() {
+		t.Fatalf("expected the one event after the cursor, got %d", len(rest))
+	}
+
+	// Ascending lists oldest first and continues past the cursor upwards.
+	oldest, _ := s.ListEventsByTenant(ctx(), "t1", event.ListOpts{Limit: 2, Ascending: true})
+	if len(oldest) != 2 || oldest[0].ID.String() > oldest[1].ID.String() || oldest[0].ID != rest[0].ID {
+		t.Fatalf("expected 2 events oldest first, got %d", len(oldest))
+	}
+	rest, _ = s.ListEventsByTenant(ctx(), "t1", event.ListOpts{Cursor: oldest[1].ID, Ascending: true})
+	if len(rest) != 1 || rest[0].ID != first[0].ID {
+		t.Fatalf("expected the newest event after the cursor, got %d", len(rest))
+	}
+
+	count, _ := s.CountEventsByTenant(ctx(), "t1", event.ListOpts{})
+	if count != 3 {
+		t.Fatalf("expected 3, got %d", count)
+	}
+	count, _ = s.CountEvents(ctx(), event.ListOpts{Type: "d"})
+	if count != 1 {
+		t.Fatalf("expected 1, got %d", count)
+	}
+}
+
 func TestEventListTimeFilter(t *testing.T) {
 	s := New()
 
@@ -656,6 +905,75 @@ func TestDeliveryCountPending(t *testing.T) {
 	}
 }
 
+func TestDeliveryRetryAndCancel(t *testing.T) {
+	s := New()
+
+	d := newDelivery(id.NewEventID(), id.NewEndpointID())
+	d.NextAttemptAt = time.Now().Add(time.Hour)
+	_ = s.Enqueue(ctx(), d)
+
+	// Retry makes the delivery due now.
+	if err := s.RetryDelivery(ctx(), d.ID); err != nil {
+		t.Fatal(err)
+	}
+	batch, _ := s.Dequeue(ctx(), 10)
+	if len(batch) != 1 {
+		t.Fatalf("expected retried delivery to be dequeued, got %d", len(batch))
+	}
+
+	// A claimed delivery can be neither retried nor cancelled.
+	if err := s.CancelDelivery(ctx(), d.ID); !errors.Is(err, relay.ErrDeliveryNotPending) {
+		t.Fatalf("expected ErrDeliveryNotPending, got %v", err)
+	}
+
+	d2 := newDelivery(id.NewEventID(), id.NewEndpointID())
+	_ = s.Enqueue(ctx(), d2)
+	if err := s.CancelDelivery(ctx(), d2.ID); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := s.GetDelivery(ctx(), d2.ID)
+	if got.State != delivery.StateCancelled || got.CompletedAt == nil {
+		t.Fatalf("expected cancelled with completed_at, got %s", got.State)
+	}
+	if err := s.RetryDelivery(ctx(), d2.ID); !errors.Is(err, relay.ErrDeliveryNotPending) {
+		t.Fatalf("expected ErrDeliveryNotPending, got %v", err)
+	}
+
+	if err := s.RetryDelivery(ctx(), id.NewDeliveryID()); !errors.Is(err, relay.ErrDeliveryNotFound) {
+		t.Fatalf("expected ErrDeliveryNotFound, got %v", err)
+	}
+}
+
+func TestDeliveryListFilters(t *testing.T) {
+	s := New()
+
+	ep1 := newEndpoint("tenant-1", []string{"*"})
+	ep2 := newEndpoint("tenant-2", []string{"*"})
+	_ = s.CreateEndpoint(ctx(), ep1)
+	_ = s.CreateEndpoint(ctx(), ep2)
+
+	evtID := id.NewEventID()
+	_ = s.Enqueue(ctx(), newDelivery(evtID, ep1.ID))
+	_ = s.Enqueue(ctx(), newDelivery(id.NewEventID(), ep1.ID))
+	_ = s.Enqueue(ctx(), newDelivery(evtID, ep2.ID))
+
+	list, _ := s.ListDeliveries(ctx(), delivery.ListOpts{TenantID: "tenant-1"})
+	if len(list) != 2 {
+		t.Fatalf("expected 2 for tenant-1, got %d", len(list))
+	}
+
+	count, _ := s.CountDeliveries(ctx(), delivery.ListOpts{EventID: &evtID})
+	if count != 2 {
+		t.Fatalf("expected 2 for event, got %d", count)
+	}
+
+	future := time.Now().Add(time.Hour)
+	list, _ = s.ListDeliveries(ctx(), delivery.ListOpts{From: &future})
+	if len(list) != 0 {
+		t.Fatalf("expected none created after from, got %d", len(list))
+	}
+}
+
 // ──────────────────────────────────────────────────
 // dlq.Store
 // ──────────────────────────────────────────────────
@@ -703,7 +1021,7 @@ func TestDLQCRUD(t *testing.T) {
 	}
 
 	// Count
-	count, _ := s.CountDLQ(ctx())
+	count, _ := s.CountDLQ(ctx(), dlq.ListOpts{})
 	if count != 1 {
 		t.Fatalf("expected 1, got %d", count)
 	}
@@ -727,6 +1045,55 @@ func TestDLQList(t *testing.T) {
 	if len(list) != 1 {
 		t.Fatalf("expected 1, got %d", len(list))
 	}
+
+	count, _ := s.CountDLQ(ctx(), dlq.ListOpts{EndpointID: &epID})
+	if count != 1 {
+		t.Fatalf("expected count 1, got %d", count)
+	}
+}
+
+func TestDLQListFilters(t *testing.T) {
+	s := New()
+
+	timeout := newDLQEntry(id.NewEventID(), id.NewEndpointID())
+	timeout.EventType = "order.created"
+	timeout.Error = "context deadline exceeded"
+	timeout.LastStatusCode = 0
+	gone := newDLQEntry(id.NewEventID(), id.NewEndpointID())
+	gone.EventType = "order.created"
+	gone.LastStatusCode = 410
+	other := newDLQEntry(id.NewEventID(), id.NewEndpointID())
+	other.EventType = "invoice.paid"
+	for _, e := range []*dlq.Entry{timeout, gone, other} {
+		_ = s.Push(ctx(), e)
+	}
+
+	tests := []struct {
+		name string
+		opts dlq.ListOpts
+		want int
+	}{
+		{"event type", dlq.ListOpts{EventType: "order.created"}, 2},
+		{"status code", dlq.ListOpts{StatusCode: 410}, 1},
+		{"error substring", dlq.ListOpts{ErrorContains: "deadline"}, 1},
+		{"error is case-sensitive", dlq.ListOpts{ErrorContains: "Deadline"}, 0},
+		{"combined", dlq.ListOpts{EventType: "order.created", ErrorContains: "refused"}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, _ := s.ListDLQ(ctx(), tt.opts)
+			count, _ := s.CountDLQ(ctx(), tt.opts)
+			if len(list) != tt.want || count != int64(tt.want) {
+				t.Fatalf("expected %d, listed %d and counted %d", tt.want, len(list), count)
+			}
+		})
+	}
+
+	_ = s.Replay(ctx(), gone.ID, newDelivery(gone.EventID, gone.EndpointID))
+	count, _ := s.CountDLQ(ctx(), dlq.ListOpts{Unreplayed: true})
+	if count != 2 {
+		t.Fatalf("expected 2 unreplayed entries, got %d", count)
+	}
 }
 
 func TestDLQReplay(t *testing.T) {
@@ -742,7 +1109,8 @@ func TestDLQReplay(t *testing.T) {
 	}
 
 	// Replay
-	if err := s.Replay(ctx(), entry.ID); err != nil {
+	d := newDelivery(entry.EventID, entry.EndpointID)
+	if err := s.Replay(ctx(), entry.ID, d); err != nil {
 		t.Fatal(err)
 	}
 
@@ -752,39 +1120,30 @@ func TestDLQReplay(t *testing.T) {
 		t.Fatalf("expected 1 pending, got %d", count)
 	}
 
-	// Entry should have ReplayedAt set
-	got, _ := s.GetDLQ(ctx(), entry.ID)
+	// Entry is kept, marked replayed and linked to the new delivery
+	got, err := s.GetDLQ(ctx(), entry.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
 	if got.ReplayedAt == nil {
 		t.Fatal("expected ReplayedAt to be set")
 	}
-
-	// Replay not found
-	if err := s.Replay(ctx(), id.NewDLQID()); !errors.Is(err, relay.ErrDLQNotFound) {
-		t.Fatalf("expected ErrDLQNotFound, got %v", err)
+	if got.ReplayDeliveryID == nil || got.ReplayDeliveryID.String() != d.ID.String() {
+		t.Fatalf("expected ReplayDeliveryID %s, got %v", d.ID, got.ReplayDeliveryID)
 	}
-}
-
-func TestDLQReplayBulk(t *testing.T) {
-	s := New()
-
-	_ = s.Push(ctx(), newDLQEntry(id.NewEventID(), id.NewEndpointID()))
-	_ = s.Push(ctx(), newDLQEntry(id.NewEventID(), id.NewEndpointID()))
-
-	from := time.Now().Add(-time.Hour)
-	to := time.Now().Add(time.Hour)
 
-	count, err := s.ReplayBulk(ctx(), from, to)
-	if err != nil {
-		t.Fatal(err)
+	// Replaying again enqueues nothing
+	again := newDelivery(entry.EventID, entry.EndpointID)
+	if err := s.Replay(ctx(), entry.ID, again); !errors.Is(err, dlq.ErrAlreadyReplayed) {
+		t.Fatalf("expected ErrAlreadyReplayed, got %v", err)
 	}
-	if count != 2 {
-		t.Fatalf("expected 2, got %d", count)
+	if _, err := s.GetDelivery(ctx(), again.ID); !errors.Is(err, relay.ErrDeliveryNotFound) {
+		t.Fatalf("expected no second delivery, got %v", err)
 	}
 
-	// Replaying again should return 0 (already replayed)
-	count, _ = s.ReplayBulk(ctx(), from, to)
-	if count != 0 {
-		t.Fatalf("expected 0 on second replay, got %d", count)
+	// Replay not found
+	if err := s.Replay(ctx(), id.NewDLQID(), newDelivery(entry.EventID, entry.EndpointID)); !errors.Is(err, relay.ErrDLQNotFound) {
+		t.Fatalf("expected ErrDLQNotFound, got %v", err)
 	}
 }
 
@@ -800,8 +1159,62 @@ func TestDLQPurge(t *testing.T) {
 		t.Fatalf("expected 2 purged, got %d", count)
 	}
 
-	remaining, _ := s.CountDLQ(ctx())
+	remaining, _ := s.CountDLQ(ctx(), dlq.ListOpts{})
 	if remaining != 0 {
 		t.Fatalf("expected 0, got %d", remaining)
 	}
 }
+
+// ──────────────────────────────────────────────────
+// auth.Store
+// ──────────────────────────────────────────────────
+
+func newAPIKey(tenantID, hash string) *auth.APIKey {
+	return &auth.APIKey{
+		Entity:   entity.New(),
+		ID:       id.NewAPIKeyID(),
+		Name:     "key",
+		Hash:     hash,
+		TenantID: tenantID,
+		Scopes:   []string{auth.ScopeEventsWrite},
+	}
+}
+
+func TestAPIKeyCRUD(t *testing.T) {
+	s := New()
+
+	k1 := newAPIKey("t1", "hash1")
+	k2 := newAPIKey("t2", "hash2")
+	for _, k := range []*auth.APIKey{k1, k2} {
+		if err := s.CreateAPIKey(ctx(), k); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := s.GetAPIKeyByHash(ctx(), "hash2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != k2.ID {
+		t.Fatalf("expected %s, got %s", k2.ID, got.ID)
+	}
+
+	list, _ := s.ListAPIKeys(ctx(), auth.ListOpts{TenantID: "t1"})
+	if len(list) != 1 || list[0].ID != k1.ID {
+		t.Fatalf("expected only k1 for t1, got %d", len(list))
+	}
+	list, _ = s.ListAPIKeys(ctx(), auth.ListOpts{})
+	if len(list) != 2 {
+		t.Fatalf("expected 2 keys, got %d", len(list))
+	}
+
+	if err := s.DeleteAPIKey(ctx(), k1.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GetAPIKey(ctx(), k1.ID); !errors.Is(err, relay.ErrAPIKeyNotFound) {
+		t.Fatalf("expected ErrAPIKeyNotFound, got %v", err)
+	}
+	if err := s.DeleteAPIKey(ctx(), k1.ID); !errors.Is(err, relay.ErrAPIKeyNotFound) {
+		t.Fatalf("expected ErrAPIKeyNotFound on second delete, got %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+
+	relay "github.com/xraph/relay"
+	"github.com/xraph/relay/auth"
+	"github.com/xraph/relay/id"
+)
+
+// CreateAPIKey persists a new API key.
+func (s *Store) CreateAPIKey(ctx context.Context, key *auth.APIKey) error {
+	m := toAPIKeyModel(key)
+
+	_, err := s.mdb.NewInsert(m).Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("relay/mongo: create api key: %w", err)
+	}
+
+	return nil
+}
+
+// GetAPIKey returns an API key by ID.
+func (s *Store) GetAPIKey(ctx context.Context, keyID id.ID) (*auth.APIKey, error) {
+	return s.findAPIKey(ctx, bson.M{"_id": keyID.String()})
+}
+
+// GetAPIKeyByHash returns the API key with the given hash.
+func (s *Store) GetAPIKeyByHash(ctx context.Context, hash string) (*auth.APIKey, error) {
+	return s.findAPIKey(ctx, bson.M{"hash": hash})
+}
+
+func (s *Store) findAPIKey(ctx context.Context, filter bson.M) (*auth.APIKey, error) {
+	var m apiKeyModel
+
+	err := s.mdb.NewFind(&m).
+		Filter(filter).
+		Scan(ctx)
+	if err != nil {
+		if isNoDocuments(err) {
+			return nil, relay.ErrAPIKeyNotFound
+		}
+
+		return nil, fmt.Errorf("relay/mongo: get api key: %w", err)
+	}
+
+	return fromAPIKeyModel(&m)
+}
+
+// ListAPIKeys returns API keys, optionally filtered by tenant.
+func (s *Store) ListAPIKeys(ctx context.Context, opts auth.ListOpts) ([]*auth.APIKey, error) {
+	var models []apiKeyModel
+
+	filter := afterID(apiKeyFilter(opts), opts.Cursor, false)
+
+	q := s.mdb.NewFind(&models).
+		Filter(filter).
+		Sort(bson.D{{Key: "_id", Value: 1}})
+
+	if opts.Limit > 0 {
+		q = q.Limit(int64(opts.Limit))
+	}
+
+	if opts.Offset > 0 {
+		q = q.Skip(int64(opts.Offset))
+	}
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("relay/mongo: list api keys: %w", err)
+	}
+
+	result := make([]*auth.APIKey, 0, len(models))
+
+	for i := range models {
+		key, err := fromAPIKeyModel(&models[i])
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, key)
+	}
+
+	return result, nil
+}
+
+// CountAPIKeys returns the number of API keys matching opts.
+func (s *Store) CountAPIKeys(ctx context.Context, opts auth.ListOpts) (int64, error) {
+	count, err := s.mdb.NewFind((*apiKeyModel)(nil)).
+		Filter(apiKeyFilter(opts)).
+		Count(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("relay/mongo: count api keys: %w", err)
+	}
+
+	return count, nil
+}
+
+func apiKeyFilter(opts auth.ListOpts) bson.M {
+	filter := bson.M{}
+	if opts.TenantID != "" {
+		filter["tenant_id"] = opts.TenantID
+	}
+
+	return filter
+}
+
+// DeleteAPIKey removes an API key.
+func (s *Store) DeleteAPIKey(ctx context.Context, keyID id.ID) error {
+	res, err := s.mdb.NewDelete((*apiKeyModel)(nil)).
+		Filter(bson.M{"_id": keyID.String()}).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("relay/mongo: delete api key: %w", err)
+	}
+
+	if res.DeletedCount() == 0 {
+		return relay.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
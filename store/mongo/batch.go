@@ -0,0 +1,112 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	mongod "go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+
+	"github.com/xraph/relay/delivery"
+	"github.com/xraph/relay/event"
+	"github.com/xraph/relay/id"
+	"github.com/xraph/relay/store"
+)
+
+var _ store.BatchOutbox = (*Store)(nil)
+
+// CreateEventBatch bulk-inserts events and their deliveries. The events go
+// in as one unordered insert, so an idempotency key that is already stored
+// fails only its own event; it is reported as a duplicate and skipped
+// together with its deliveries, as is a key repeated within the batch.
+// Deliveries are inserted only for the events actually written, and any
+// other failure removes what the batch wrote before returning.
+func (s *Store) CreateEventBatch(ctx context.Context, evts []*event.Event, ds []*delivery.Delivery) ([]id.ID, error) {
+	if len(evts) == 0 {
+		return nil, nil
+	}
+
+	var duplicates []id.ID
+	seen := make(map[string]bool)
+	ids := make([]id.ID, 0, len(evts))
+	events := make([]eventModel, 0, len(evts))
+	for _, evt := range evts {
+		if evt.IdempotencyKey != "" {
+			if seen[evt.IdempotencyKey] {
+				duplicates = append(duplicates, evt.ID)
+				continue
+			}
+			seen[evt.IdempotencyKey] = true
+		}
+		ids = append(ids, evt.ID)
+		events = append(events, *toEventModel(evt))
+	}
+
+	written := make(map[id.ID]bool, len(ids))
+	for _, evtID := range ids {
+		written[evtID] = true
+	}
+
+	_, err := s.mdb.Collection(colEvents).InsertMany(ctx, events, options.InsertMany().SetOrdered(false))
+	if err != nil {
+		var bwe mongod.BulkWriteException
+		if !errors.As(err, &bwe) || bwe.WriteConcernError != nil {
+			return nil, s.discardEventBatch(ctx, ids, fmt.Errorf("relay/mongo: create event batch: %w", err))
+		}
+		for _, we := range bwe.WriteErrors {
+			if !isDuplicateKey(we.WriteError) {
+				return nil, s.discardEventBatch(ctx, ids, fmt.Errorf("relay/mongo: create event batch: %w", err))
+			}
+			duplicates = append(duplicates, ids[we.Index])
+			delete(written, ids[we.Index])
+		}
+	}
+
+	deliveries := make([]deliveryModel, 0, len(ds))
+	for _, d := range ds {
+		if written[d.EventID] {
+			deliveries = append(deliveries, *toDeliveryModel(d))
+		}
+	}
+	if len(deliveries) > 0 {
+		if _, err := s.mdb.NewInsert(&deliveries).Exec(ctx); err != nil {
+			return nil, s.discardEventBatch(ctx, ids, fmt.Errorf("relay/mongo: enqueue event batch: %w", err))
+		}
+	}
+
+	return duplicates, nil
+}
+
+// isDuplicateKey reports whether a write failed on a unique index.
+func isDuplicateKey(we mongod.WriteError) bool {
+	return we.HasErrorCode(11000) || we.HasErrorCode(11001)
+}
+
+// discardEventBatch removes the events a failed CreateEventBatch may have
+// written, and their deliveries, so none is left without the other. It
+// returns cause, joined with any error from the cleanup.
+func (s *Store) discardEventBatch(ctx context.Context, ids []id.ID, cause error) error {
+	if len(ids) == 0 {
+		return cause
+	}
+	keys := make([]string, len(ids))
+	for i, evtID := range ids {
+		keys[i] = evtID.String()
+	}
+
+	if _, err := s.mdb.NewDelete((*deliveryModel)(nil)).
+		Many().
+		Filter(bson.M{"event_id": bson.M{"$in": keys}}).
+		Exec(ctx); err != nil {
+		return errors.Join(cause, fmt.Errorf("relay/mongo: discard event batch deliveries: %w", err))
+	}
+	if _, err := s.mdb.NewDelete((*eventModel)(nil)).
+		Many().
+		Filter(bson.M{"_id": bson.M{"$in": keys}}).
+		Exec(ctx); err != nil {
+		return errors.Join(cause, fmt.Errorf("relay/mongo: discard event batch: %w", err))
+	}
+	return cause
+}
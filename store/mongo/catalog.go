@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
@@ -19,22 +20,28 @@ func (s *Store) RegisterType(ctx context.Context, et *catalog.EventType) error {
 
 	_, err := s.mdb.NewUpdate(m).
 		Filter(bson.M{"name": m.Name}).
-		SetUpdate(bson.M{"$setOnInsert": bson.M{
-			"_id":            m.ID,
-			"name":           m.Name,
-			"description":    m.Description,
-			"group_name":     m.GroupName,
-			"schema":         m.Schema,
-			"schema_version": m.SchemaVersion,
-			"version":        m.Version,
-			"example":        m.Example,
-			"is_deprecated":  m.IsDeprecated,
-			"deprecated_at":  m.DeprecatedAt,
-			"scope_app_id":   m.ScopeAppID,
-			"metadata":       m.Metadata,
-			"created_at":     m.CreatedAt,
-			"updated_at":     m.UpdatedAt,
-		}}).
+		SetUpdate(bson.M{
+			"$setOnInsert": bson.M{
+				"_id":           m.ID,
+				"name":          m.Name,
+				"created_at":    m.CreatedAt,
+				"is_deprecated": m.IsDeprecated,
+				"deprecated_at": m.DeprecatedAt,
+				"sunset_at":     m.SunsetAt,
+			},
+			"$set": bson.M{
+				"description":    m.Description,
+				"group_name":     m.GroupName,
+				"schema":         m.Schema,
+				"schema_version": m.SchemaVersion,
+				"version":        m.Version,
+				"example":        m.Example,
+				"versions":       m.Versions,
+				"scope_app_id":   m.ScopeAppID,
+				"metadata":       m.Metadata,
+				"updated_at":     m.UpdatedAt,
+			},
+		}).
 		Upsert().
 		Exec(ctx)
 	if err != nil {
@@ -86,17 +93,10 @@ func (s *Store) ListTypes(ctx context.Context, opts catalog.ListOpts) ([]*catalo
 
 	q := s.mdb.NewFind(&models)
 
-	filter := bson.M{}
-	if !opts.IncludeDeprecated {
-		filter["is_deprecated"] = false
-	}
-
-	if opts.Group != "" {
-		filter["group_name"] = opts.Group
-	}
+	filter := afterID(typeFilter(opts), opts.Cursor, false)
 
 	q = q.Filter(filter).
-		Sort(bson.D{{Key: "created_at", Value: -1}})
+		Sort(bson.D{{Key: "_id", Value: 1}})
 
 	if opts.Limit > 0 {
 		q = q.Limit(int64(opts.Limit))
@@ -124,18 +124,72 @@ func (s *Store) ListTypes(ctx context.Context, opts catalog.ListOpts) ([]*catalo
 	return result, nil
 }
 
-// DeleteType soft-deletes (deprecates) an event type.
+// CountTypes returns the number of event types matching opts.
+func (s *Store) CountTypes(ctx context.Context, opts catalog.ListOpts) (int64, error) {
+	count, err := s.mdb.NewFind((*eventTypeModel)(nil)).
+		Filter(typeFilter(opts)).
+		Count(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("relay/mongo: count types: %w", err)
+	}
+
+	return count, nil
+}
+
+func typeFilter(opts catalog.ListOpts) bson.M {
+	filter := bson.M{}
+	if !opts.IncludeDeprecated {
+		filter["is_deprecated"] = false
+	}
+
+	if opts.Group != "" {
+		filter["group_name"] = opts.Group
+	}
+
+	if opts.ScopeAppID != "" {
+		filter["scope_app_id"] = opts.ScopeAppID
+	}
+
+	return filter
+}
+
+// DeleteType soft-deletes an event type by deprecating it with an immediate
+// sunset.
 func (s *Store) DeleteType(ctx context.Context, name string) error {
 	t := now()
 
+	m, err := s.findDeprecatable(ctx, name)
+	if err != nil {
+		return err
+	}
+	if m.IsDeprecated && (m.SunsetAt == nil || !t.Before(*m.SunsetAt)) {
+		return relay.ErrEventTypeNotFound
+	}
+
+	return s.setDeprecation(ctx, m, t, t)
+}
+
+// DeprecateType deprecates an event type until sunsetAt.
+func (s *Store) DeprecateType(ctx context.Context, name string, sunsetAt time.Time) error {
+	m, err := s.findDeprecatable(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	return s.setDeprecation(ctx, m, sunsetAt, now())
+}
+
+// UndeprecateType clears an event type's deprecation.
+func (s *Store) UndeprecateType(ctx context.Context, name string) error {
 	res, err := s.mdb.NewUpdate((*eventTypeModel)(nil)).
 		Filter(bson.M{"name": name}).
-		Set("is_deprecated", true).
-		Set("deprecated_at", t).
-		Set("updated_at", t).
+		Set("is_deprecated", false).
+		Set("deprecated_at", nil).
+		Set("sunset_at", nil).
+		Set("updated_at", now()).
 		Exec(ctx)
 	if err != nil {
-		return fmt.Errorf("relay/mongo: delete type: %w", err)
+		return fmt.Errorf("relay/mongo: undeprecate type: %w", err)
 	}
 
 	if res.MatchedCount() == 0 {
@@ -145,6 +199,45 @@ func (s *Store) DeleteType(ctx context.Context, name string) error {
 	return nil
 }
 
+// findDeprecatable loads the event type a deprecation applies to.
+func (s *Store) findDeprecatable(ctx context.Context, name string) (*eventTypeModel, error) {
+	var m eventTypeModel
+
+	err := s.mdb.NewFind(&m).
+		Filter(bson.M{"name": name}).
+		Scan(ctx)
+	if err != nil {
+		if isNoDocuments(err) {
+			return nil, relay.ErrEventTypeNotFound
+		}
+		return nil, fmt.Errorf("relay/mongo: deprecate type: %w", err)
+	}
+
+	return &m, nil
+}
+
+// setDeprecation marks m deprecated until sunsetAt, keeping the original
+// deprecation time when m was already deprecated.
+func (s *Store) setDeprecation(ctx context.Context, m *eventTypeModel, sunsetAt, t time.Time) error {
+	deprecatedAt := t
+	if m.IsDeprecated && m.DeprecatedAt != nil {
+		deprecatedAt = *m.DeprecatedAt
+	}
+
+	_, err := s.mdb.NewUpdate((*eventTypeModel)(nil)).
+		Filter(bson.M{"_id": m.ID}).
+		Set("is_deprecated", true).
+		Set("deprecated_at", deprecatedAt).
+		Set("sunset_at", sunsetAt).
+		Set("updated_at", t).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("relay/mongo: deprecate type: %w", err)
+	}
+
+	return nil
+}
+
 // MatchTypes returns non-deprecated event types matching a glob pattern.
 func (s *Store) MatchTypes(ctx context.Context, pattern string) ([]*catalog.EventType, error) {
 	var models []eventTypeModel
@@ -0,0 +1,11 @@
+package mongo_test
+
+import (
+	"testing"
+
+	"github.com/xraph/relay/internal/storetest"
+)
+
+func TestRegisterKeepsDeprecation(t *testing.T) {
+	storetest.RegisterKeepsDeprecation(t, openStore(t, startMongo(t)))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 	mongod "go.mongodb.org/mongo-driver/v2/mongo"
@@ -145,18 +146,69 @@ func (s *Store) GetDelivery(ctx context.Context, delID id.ID) (*delivery.Deliver
 	return fromDeliveryModel(&m)
 }
 
-// ListByEndpoint returns delivery history for an endpoint.
-func (s *Store) ListByEndpoint(ctx context.Context, epID id.ID, opts delivery.ListOpts) ([]*delivery.Delivery, error) {
+// RetryDelivery makes a pending delivery due now.
+func (s *Store) RetryDelivery(ctx context.Context, delID id.ID) error {
+	t := now()
+
+	res, err := s.mdb.NewUpdate((*deliveryModel)(nil)).
+		Filter(bson.M{"_id": delID.String(), "state": string(delivery.StatePending)}).
+		Set("next_attempt_at", t).
+		Set("updated_at", t).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("relay/mongo: retry delivery: %w", err)
+	}
+
+	if res.MatchedCount() == 0 {
+		return s.notPending(ctx, delID)
+	}
+
+	return nil
+}
+
+// CancelDelivery moves a pending delivery to StateCancelled.
+func (s *Store) CancelDelivery(ctx context.Context, delID id.ID) error {
+	t := now()
+
+	res, err := s.mdb.NewUpdate((*deliveryModel)(nil)).
+		Filter(bson.M{"_id": delID.String(), "state": string(delivery.StatePending)}).
+		Set("state", string(delivery.StateCancelled)).
+		Set("completed_at", t).
+		Set("updated_at", t).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("relay/mongo: cancel delivery: %w", err)
+	}
+
+	if res.MatchedCount() == 0 {
+		return s.notPending(ctx, delID)
+	}
+
+	return nil
+}
+
+// notPending tells why a conditional update of a pending delivery matched
+// nothing: the delivery is missing or no longer pending.
+func (s *Store) notPending(ctx context.Context, delID id.ID) error {
+	if _, err := s.GetDelivery(ctx, delID); err != nil {
+		return err
+	}
+
+	return relay.ErrDeliveryNotPending
+}
+
+// ListDeliveries returns deliveries matching opts.
+func (s *Store) ListDeliveries(ctx context.Context, opts delivery.ListOpts) ([]*delivery.Delivery, error) {
 	var models []deliveryModel
 
-	filter := bson.M{"endpoint_id": epID.String()}
-	if opts.State != nil {
-		filter["state"] = string(*opts.State)
+	filter, err := s.deliveryFilter(ctx, opts)
+	if err != nil {
+		return nil, err
 	}
 
 	q := s.mdb.NewFind(&models).
-		Filter(filter).
-		Sort(bson.D{{Key: "created_at", Value: -1}})
+		Filter(afterID(filter, opts.Cursor, true)).
+		Sort(bson.D{{Key: "_id", Value: -1}})
 
 	if opts.Limit > 0 {
 		q = q.Limit(int64(opts.Limit))
@@ -167,7 +219,7 @@ func (s *Store) ListByEndpoint(ctx context.Context, epID id.ID, opts delivery.Li
 	}
 
 	if err := q.Scan(ctx); err != nil {
-		return nil, fmt.Errorf("relay/mongo: list by endpoint: %w", err)
+		return nil, fmt.Errorf("relay/mongo: list deliveries: %w", err)
 	}
 
 	result := make([]*delivery.Delivery, 0, len(models))
@@ -184,6 +236,85 @@ func (s *Store) ListByEndpoint(ctx context.Context, epID id.ID, opts delivery.Li
 	return result, nil
 }
 
+// CountDeliveries returns the number of deliveries matching opts.
+func (s *Store) CountDeliveries(ctx context.Context, opts delivery.ListOpts) (int64, error) {
+	filter, err := s.deliveryFilter(ctx, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := s.mdb.NewFind((*deliveryModel)(nil)).
+		Filter(filter).
+		Count(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("relay/mongo: count deliveries: %w", err)
+	}
+
+	return count, nil
+}
+
+// ListByEndpoint returns delivery history for an endpoint.
+func (s *Store) ListByEndpoint(ctx context.Context, epID id.ID, opts delivery.ListOpts) ([]*delivery.Delivery, error) {
+	opts.EndpointID = &epID
+	return s.ListDeliveries(ctx, opts)
+}
+
+// CountByEndpoint returns the number of an endpoint's deliveries matching opts.
+func (s *Store) CountByEndpoint(ctx context.Context, epID id.ID, opts delivery.ListOpts) (int64, error) {
+	opts.EndpointID = &epID
+	return s.CountDeliveries(ctx, opts)
+}
+
+// deliveryFilter returns the filter of opts. Deliveries do not record
+// their tenant, so a tenant filter looks up the tenant's endpoints first.
+func (s *Store) deliveryFilter(ctx context.Context, opts delivery.ListOpts) (bson.M, error) {
+	filter := bson.M{}
+	if opts.EndpointID != nil {
+		filter["endpoint_id"] = opts.EndpointID.String()
+	}
+
+	if opts.EventID != nil {
+		filter["event_id"] = opts.EventID.String()
+	}
+
+	if opts.State != nil {
+		filter["state"] = string(*opts.State)
+	}
+
+	if opts.From != nil || opts.To != nil {
+		dateFilter := bson.M{}
+		if opts.From != nil {
+			dateFilter["$gte"] = *opts.From
+		}
+
+		if opts.To != nil {
+			dateFilter["$lte"] = *opts.To
+		}
+
+		filter["created_at"] = dateFilter
+	}
+
+	if opts.TenantID != "" {
+		var eps []endpointModel
+		if err := s.mdb.NewFind(&eps).Filter(bson.M{"tenant_id": opts.TenantID}).Scan(ctx); err != nil {
+			return nil, fmt.Errorf("relay/mongo: list tenant endpoints: %w", err)
+		}
+
+		epIDs := make([]string, len(eps))
+		for i := range eps {
+			epIDs[i] = eps[i].ID
+		}
+
+		if opts.EndpointID != nil {
+			epIDs = slices.DeleteFunc(epIDs, func(epID string) bool { return epID != opts.EndpointID.String() })
+		}
+
+		filter["endpoint_id"] = bson.M{"$in": epIDs}
+	}
+
+	return filter, nil
+}
+
 // ListByEvent returns all deliveries for a specific event.
 func (s *Store) ListByEvent(ctx context.Context, evtID id.ID) ([]*delivery.Delivery, error) {
 	var models []deliveryModel
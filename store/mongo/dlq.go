@@ -2,7 +2,9 @@ package mongo
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
 	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
@@ -11,7 +13,6 @@ import (
 	"github.com/xraph/relay/delivery"
 	"github.com/xraph/relay/dlq"
 	"github.com/xraph/relay/id"
-	"github.com/xraph/relay/internal/entity"
 )
 
 // Push moves a permanently failed delivery into the DLQ.
@@ -30,6 +31,39 @@ func (s *Store) Push(ctx context.Context, entry *dlq.Entry) error {
 func (s *Store) ListDLQ(ctx context.Context, opts dlq.ListOpts) ([]*dlq.Entry, error) {
 	var models []dlqEntryModel
 
+	filter := afterID(dlqFilter(opts), opts.Cursor, true)
+
+	q := s.mdb.NewFind(&models).
+		Filter(filter).
+		Sort(bson.D{{Key: "_id", Value: -1}})
+
+	if opts.Limit > 0 {
+		q = q.Limit(int64(opts.Limit))
+	}
+
+	if opts.Offset > 0 {
+		q = q.Skip(int64(opts.Offset))
+	}
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, fmt.Errorf("relay/mongo: list dlq: %w", err)
+	}
+
+	result := make([]*dlq.Entry, 0, len(models))
+
+	for i := range models {
+		entry, err := fromDLQEntryModel(&models[i])
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, entry)
+	}
+
+	return result, nil
+}
+
+func dlqFilter(opts dlq.ListOpts) bson.M {
 	filter := bson.M{}
 	if opts.TenantID != "" {
 		filter["tenant_id"] = opts.TenantID
@@ -52,34 +86,23 @@ func (s *Store) ListDLQ(ctx context.Context, opts dlq.ListOpts) ([]*dlq.Entry, e
 		filter["failed_at"] = dateFilter
 	}
 
-	q := s.mdb.NewFind(&models).
-		Filter(filter).
-		Sort(bson.D{{Key: "failed_at", Value: -1}})
-
-	if opts.Limit > 0 {
-		q = q.Limit(int64(opts.Limit))
+	if opts.EventType != "" {
+		filter["event_type"] = opts.EventType
 	}
 
-	if opts.Offset > 0 {
-		q = q.Skip(int64(opts.Offset))
+	if opts.StatusCode != 0 {
+		filter["last_status_code"] = opts.StatusCode
 	}
 
-	if err := q.Scan(ctx); err != nil {
-		return nil, fmt.Errorf("relay/mongo: list dlq: %w", err)
+	if opts.ErrorContains != "" {
+		filter["error"] = bson.M{"$regex": regexp.QuoteMeta(opts.ErrorContains)}
 	}
 
-	result := make([]*dlq.Entry, 0, len(models))
-
-	for i := range models {
-		entry, err := fromDLQEntryModel(&models[i])
-		if err != nil {
-			return nil, err
-		}
-
-		result = append(result, entry)
+	if opts.Unreplayed {
+		filter["replayed_at"] = nil
 	}
 
-	return result, nil
+	return filter
 }
 
 // GetDLQ returns a DLQ entry by ID.
@@ -100,87 +123,43 @@ func (s *Store) GetDLQ(ctx context.Context, dlqID id.ID) (*dlq.Entry, error) {
 	return fromDLQEntryModel(&m)
 }
 
-// Replay marks a DLQ entry for redelivery (re-enqueues the delivery).
-func (s *Store) Replay(ctx context.Context, dlqID id.ID) error {
-	entry, err := s.GetDLQ(ctx, dlqID)
-	if err != nil {
-		return err
-	}
-
+// Replay enqueues d and marks the DLQ entry replayed.
+func (s *Store) Replay(ctx context.Context, dlqID id.ID, d *delivery.Delivery) error {
+	// Claim the entry first so concurrent replays enqueue one delivery.
 	t := now()
 
-	d := &delivery.Delivery{
-		Entity:        entity.New(),
-		ID:            id.NewDeliveryID(),
-		EventID:       entry.EventID,
-		EndpointID:    entry.EndpointID,
-		State:         delivery.StatePending,
-		MaxAttempts:   entry.AttemptCount,
-		NextAttemptAt: t,
-	}
-
-	if enqErr := s.Enqueue(ctx, d); enqErr != nil {
-		return fmt.Errorf("relay/mongo: replay enqueue: %w", enqErr)
-	}
-
-	_, err = s.mdb.NewDelete((*dlqEntryModel)(nil)).
-		Filter(bson.M{"_id": dlqID.String()}).
+	res, err := s.mdb.NewUpdate((*dlqEntryModel)(nil)).
+		Filter(bson.M{"_id": dlqID.String(), "replayed_at": nil}).
+		Set("replayed_at", t).
+		Set("replay_delivery_id", d.ID.String()).
+		Set("updated_at", t).
 		Exec(ctx)
 	if err != nil {
-		return fmt.Errorf("relay/mongo: replay delete dlq: %w", err)
+		return fmt.Errorf("relay/mongo: replay claim dlq: %w", err)
 	}
 
-	return nil
-}
-
-// ReplayBulk replays all DLQ entries in a time window.
-func (s *Store) ReplayBulk(ctx context.Context, from, to time.Time) (int64, error) {
-	var models []dlqEntryModel
-
-	if err := s.mdb.NewFind(&models).
-		Filter(bson.M{
-			"failed_at": bson.M{
-				"$gte": from,
-				"$lte": to,
-			},
-		}).
-		Scan(ctx); err != nil {
-		return 0, fmt.Errorf("relay/mongo: replay bulk find: %w", err)
-	}
-
-	var count int64
-	t := now()
-
-	for i := range models {
-		entry, err := fromDLQEntryModel(&models[i])
-		if err != nil {
-			return count, err
-		}
-
-		d := &delivery.Delivery{
-			Entity:        entity.New(),
-			ID:            id.NewDeliveryID(),
-			EventID:       entry.EventID,
-			EndpointID:    entry.EndpointID,
-			State:         delivery.StatePending,
-			MaxAttempts:   entry.AttemptCount,
-			NextAttemptAt: t,
+	if res.MatchedCount() == 0 {
+		if _, err := s.GetDLQ(ctx, dlqID); err != nil {
+			return err
 		}
 
-		if err := s.Enqueue(ctx, d); err != nil {
-			return count, fmt.Errorf("relay/mongo: replay bulk enqueue: %w", err)
-		}
+		return dlq.ErrAlreadyReplayed
+	}
 
-		if _, err := s.mdb.NewDelete((*dlqEntryModel)(nil)).
-			Filter(bson.M{"_id": models[i].ID}).
-			Exec(ctx); err != nil {
-			return count, fmt.Errorf("relay/mongo: replay bulk delete: %w", err)
+	if enqErr := s.Enqueue(ctx, d); enqErr != nil {
+		// Release the claim so the entry can be replayed again.
+		if _, undoErr := s.mdb.NewUpdate((*dlqEntryModel)(nil)).
+			Filter(bson.M{"_id": dlqID.String()}).
+			Set("replayed_at", nil).
+			Set("replay_delivery_id", "").
+			Exec(ctx); undoErr != nil {
+			enqErr = errors.Join(enqErr, undoErr)
 		}
 
-		count++
+		return fmt.Errorf("relay/mongo: replay enqueue: %w", enqErr)
 	}
 
-	return count, nil
+	return nil
 }
 
 // Purge deletes DLQ entries older than a threshold.
@@ -196,9 +175,10 @@ func (s *Store) Purge(ctx context.Context, before time.Time) (int64, error) {
 	return res.DeletedCount(), nil
 }
 
-// CountDLQ returns the total number of DLQ entries.
-func (s *Store) CountDLQ(ctx context.Context) (int64, error) {
+// CountDLQ returns the number of DLQ entries matching opts.
+func (s *Store) CountDLQ(ctx context.Context, opts dlq.ListOpts) (int64, error) {
 	count, err := s.mdb.NewFind((*dlqEntryModel)(nil)).
+		Filter(dlqFilter(opts)).
 		Count(ctx)
 	if err != nil {
 		return 0, fmt.Errorf("relay/mongo: count dlq: %w", err)
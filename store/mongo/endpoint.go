@@ -3,6 +3,7 @@ package mongo
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"go.mongodb.org/mongo-driver/v2/bson"
 
@@ -21,6 +22,8 @@ func (s *Store) CreateEndpoint(ctx context.Context, ep *endpoint.Endpoint) error
 		return fmt.Errorf("relay/mongo: create endpoint: %w", err)
 	}
 
+	s.index.Invalidate(ep.TenantID)
+
 	return nil
 }
 
@@ -58,6 +61,9 @@ func (s *Store) UpdateEndpoint(ctx context.Context, ep *endpoint.Endpoint) error
 		return relay.ErrEndpointNotFound
 	}
 
+	s.index.InvalidateEndpoint(m.ID)
+	s.index.Invalidate(ep.TenantID)
+
 	return nil
 }
 
@@ -74,6 +80,8 @@ func (s *Store) DeleteEndpoint(ctx context.Context, epID id.ID) error {
 		return relay.ErrEndpointNotFound
 	}
 
+	s.index.InvalidateEndpoint(epID.String())
+
 	return nil
 }
 
@@ -81,14 +89,11 @@ func (s *Store) DeleteEndpoint(ctx context.Context, epID id.ID) error {
 func (s *Store) ListEndpoints(ctx context.Context, tenantID string, opts endpoint.ListOpts) ([]*endpoint.Endpoint, error) {
 	var models []endpointModel
 
-	filter := bson.M{"tenant_id": tenantID}
-	if opts.Enabled != nil {
-		filter["enabled"] = *opts.Enabled
-	}
+	filter := afterID(endpointFilter(tenantID, opts), opts.Cursor, false)
 
 	q := s.mdb.NewFind(&models).
 		Filter(filter).
-		Sort(bson.D{{Key: "created_at", Value: -1}})
+		Sort(bson.D{{Key: "_id", Value: 1}})
 
 	if opts.Limit > 0 {
 		q = q.Limit(int64(opts.Limit))
@@ -116,17 +121,44 @@ func (s *Store) ListEndpoints(ctx context.Context, tenantID string, opts endpoin
 	return result, nil
 }
 
-// Resolve finds all active endpoints matching an event type for a tenant.
-func (s *Store) Resolve(ctx context.Context, tenantID, eventType string) ([]*endpoint.Endpoint, error) {
+// CountEndpoints returns the number of a tenant's endpoints matching opts.
+func (s *Store) CountEndpoints(ctx context.Context, tenantID string, opts endpoint.ListOpts) (int64, error) {
+	count, err := s.mdb.NewFind((*endpointModel)(nil)).
+		Filter(endpointFilter(tenantID, opts)).
+		Count(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("relay/mongo: count endpoints: %w", err)
+	}
+
+	return count, nil
+}
+
+func endpointFilter(tenantID string, opts endpoint.ListOpts) bson.M {
+	filter := bson.M{"tenant_id": tenantID}
+	if opts.Enabled != nil {
+		filter["enabled"] = *opts.Enabled
+	}
+
+	if opts.ScopeAppID != "" {
+		filter["scope_app_id"] = opts.ScopeAppID
+	}
+
+	if opts.ScopeOrgID != "" {
+		filter["scope_org_id"] = opts.ScopeOrgID
+	}
+
+	return filter
+}
+
+// ListSubscribers returns endpoints of all tenants subscribed to eventType.
+func (s *Store) ListSubscribers(ctx context.Context, eventType string) ([]*endpoint.Endpoint, error) {
 	var models []endpointModel
 
 	if err := s.mdb.NewFind(&models).
-		Filter(bson.M{
-			"tenant_id": tenantID,
-			"enabled":   true,
-		}).
+		Filter(bson.M{}).
+		Sort(bson.D{{Key: "created_at", Value: 1}}).
 		Scan(ctx); err != nil {
-		return nil, fmt.Errorf("relay/mongo: resolve: %w", err)
+		return nil, fmt.Errorf("relay/mongo: list subscribers: %w", err)
 	}
 
 	var result []*endpoint.Endpoint
@@ -149,11 +181,75 @@ func (s *Store) Resolve(ctx context.Context, tenantID, eventType string) ([]*end
 	return result, nil
 }
 
-// SetEnabled enables or disables an endpoint.
+// ListAutoDisabled returns the endpoints of all tenants disabled by the
+// delivery engine.
+func (s *Store) ListAutoDisabled(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	var models []endpointModel
+
+	if err := s.mdb.NewFind(&models).
+		Filter(bson.M{
+			"enabled":         false,
+			"disabled_reason": bson.M{"$exists": true, "$ne": ""},
+		}).
+		Sort(bson.D{{Key: "created_at", Value: 1}}).
+		Scan(ctx); err != nil {
+		return nil, fmt.Errorf("relay/mongo: list auto-disabled endpoints: %w", err)
+	}
+
+	result := make([]*endpoint.Endpoint, 0, len(models))
+
+	for i := range models {
+		ep, err := fromEndpointModel(&models[i])
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, ep)
+	}
+
+	return result, nil
+}
+
+// Resolve finds all active endpoints matching an event type for a tenant,
+// using the subscription index.
+func (s *Store) Resolve(ctx context.Context, tenantID, eventType string) ([]*endpoint.Endpoint, error) {
+	return s.index.Resolve(ctx, tenantID, eventType)
+}
+
+// tenantEndpoints loads every endpoint of a tenant for the subscription
+// index, oldest first.
+func (s *Store) tenantEndpoints(ctx context.Context, tenantID string) ([]*endpoint.Endpoint, error) {
+	var models []endpointModel
+
+	if err := s.mdb.NewFind(&models).
+		Filter(bson.M{"tenant_id": tenantID}).
+		Sort(bson.D{{Key: "created_at", Value: 1}}).
+		Scan(ctx); err != nil {
+		return nil, fmt.Errorf("relay/mongo: load tenant endpoints: %w", err)
+	}
+
+	result := make([]*endpoint.Endpoint, len(models))
+
+	for i := range models {
+		ep, err := fromEndpointModel(&models[i])
+		if err != nil {
+			return nil, err
+		}
+
+		result[i] = ep
+	}
+
+	return result, nil
+}
+
+// SetEnabled enables or disables an endpoint and clears why the delivery
+// engine disabled it.
 func (s *Store) SetEnabled(ctx context.Context, epID id.ID, enabled bool) error {
 	res, err := s.mdb.NewUpdate((*endpointModel)(nil)).
 		Filter(bson.M{"_id": epID.String()}).
 		Set("enabled", enabled).
+		Set("disabled_reason", "").
+		Set("disabled_at", nil).
 		Set("updated_at", now()).
 		Exec(ctx)
 	if err != nil {
@@ -164,5 +260,29 @@ func (s *Store) SetEnabled(ctx context.Context, epID id.ID, enabled bool) error
 		return relay.ErrEndpointNotFound
 	}
 
+	s.index.InvalidateEndpoint(epID.String())
+
+	return nil
+}
+
+// DisableEndpoint disables an endpoint on behalf of the delivery engine.
+func (s *Store) DisableEndpoint(ctx context.Context, epID id.ID, reason string, at time.Time) error {
+	res, err := s.mdb.NewUpdate((*endpointModel)(nil)).
+		Filter(bson.M{"_id": epID.String()}).
+		Set("enabled", false).
+		Set("disabled_reason", reason).
+		Set("disabled_at", at).
+		Set("updated_at", now()).
+		Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("relay/mongo: disable endpoint: %w", err)
+	}
+
+	if res.MatchedCount() == 0 {
+		return relay.ErrEndpointNotFound
+	}
+
+	s.index.InvalidateEndpoint(epID.String())
+
 	return nil
 }
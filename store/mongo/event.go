@@ -46,31 +46,33 @@ func (s *Store) GetEvent(ctx context.Context, evtID id.ID) (*event.Event, error)
 	return fromEventModel(&m)
 }
 
-// ListEvents returns events, optionally filtered by type, tenant, or time range.
-func (s *Store) ListEvents(ctx context.Context, opts event.ListOpts) ([]*event.Event, error) {
-	var models []eventModel
+// GetEventByIdempotencyKey returns the event stored under an idempotency key.
+func (s *Store) GetEventByIdempotencyKey(ctx context.Context, key string) (*event.Event, error) {
+	var m eventModel
 
-	filter := bson.M{}
-	if opts.Type != "" {
-		filter["type"] = opts.Type
+	err := s.mdb.NewFind(&m).
+		Filter(bson.M{"idempotency_key": key}).
+		Scan(ctx)
+	if err != nil {
+		if isNoDocuments(err) {
+			return nil, relay.ErrEventNotFound
+		}
+
+		return nil, fmt.Errorf("relay/mongo: get event by idempotency key: %w", err)
 	}
 
-	if opts.From != nil || opts.To != nil {
-		dateFilter := bson.M{}
-		if opts.From != nil {
-			dateFilter["$gte"] = *opts.From
-		}
+	return fromEventModel(&m)
+}
 
-		if opts.To != nil {
-			dateFilter["$lte"] = *opts.To
-		}
+// ListEvents returns events, optionally filtered by type, tenant, or time range.
+func (s *Store) ListEvents(ctx context.Context, opts event.ListOpts) ([]*event.Event, error) {
+	var models []eventModel
 
-		filter["created_at"] = dateFilter
-	}
+	filter := afterID(eventFilter(bson.M{}, opts), opts.Cursor, !opts.Ascending)
 
 	q := s.mdb.NewFind(&models).
 		Filter(filter).
-		Sort(bson.D{{Key: "created_at", Value: -1}})
+		Sort(bson.D{{Key: "_id", Value: idOrder(!opts.Ascending)}})
 
 	if opts.Limit > 0 {
 		q = q.Limit(int64(opts.Limit))
@@ -98,11 +100,20 @@ func (s *Store) ListEvents(ctx context.Context, opts event.ListOpts) ([]*event.E
 	return result, nil
 }
 
-// ListEventsByTenant returns events for a specific tenant.
-func (s *Store) ListEventsByTenant(ctx context.Context, tenantID string, opts event.ListOpts) ([]*event.Event, error) {
-	var models []eventModel
+// CountEvents returns the number of events matching opts.
+func (s *Store) CountEvents(ctx context.Context, opts event.ListOpts) (int64, error) {
+	count, err := s.mdb.NewFind((*eventModel)(nil)).
+		Filter(eventFilter(bson.M{}, opts)).
+		Count(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("relay/mongo: count events: %w", err)
+	}
 
-	filter := bson.M{"tenant_id": tenantID}
+	return count, nil
+}
+
+// eventFilter adds the conditions of opts to filter.
+func eventFilter(filter bson.M, opts event.ListOpts) bson.M {
 	if opts.Type != "" {
 		filter["type"] = opts.Type
 	}
@@ -120,9 +131,30 @@ func (s *Store) ListEventsByTenant(ctx context.Context, tenantID string, opts ev
 		filter["created_at"] = dateFilter
 	}
 
+	if opts.ScopeAppID != "" {
+		filter["scope_app_id"] = opts.ScopeAppID
+	}
+
+	if opts.ScopeOrgID != "" {
+		filter["scope_org_id"] = opts.ScopeOrgID
+	}
+
+	if opts.ExcludeTest {
+		filter["test"] = bson.M{"$ne": true}
+	}
+
+	return filter
+}
+
+// ListEventsByTenant returns events for a specific tenant.
+func (s *Store) ListEventsByTenant(ctx context.Context, tenantID string, opts event.ListOpts) ([]*event.Event, error) {
+	var models []eventModel
+
+	filter := afterID(eventFilter(bson.M{"tenant_id": tenantID}, opts), opts.Cursor, !opts.Ascending)
+
 	q := s.mdb.NewFind(&models).
 		Filter(filter).
-		Sort(bson.D{{Key: "created_at", Value: -1}})
+		Sort(bson.D{{Key: "_id", Value: idOrder(!opts.Ascending)}})
 
 	if opts.Limit > 0 {
 		q = q.Limit(int64(opts.Limit))
@@ -149,3 +181,15 @@ func (s *Store) ListEventsByTenant(ctx context.Context, tenantID string, opts ev
 
 	return result, nil
 }
+
+// CountEventsByTenant returns the number of a tenant's events matching opts.
+func (s *Store) CountEventsByTenant(ctx context.Context, tenantID string, opts event.ListOpts) (int64, error) {
+	count, err := s.mdb.NewFind((*eventModel)(nil)).
+		Filter(eventFilter(bson.M{"tenant_id": tenantID}, opts)).
+		Count(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("relay/mongo: count events by tenant: %w", err)
+	}
+
+	return count, nil
+}
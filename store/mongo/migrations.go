@@ -157,5 +157,34 @@ func init() {
 				return mexec.DropCollection(ctx, (*dlqEntryModel)(nil))
 			},
 		},
+		&migrate.Migration{
+			Name:    "create_relay_api_keys",
+			Version: "20240101000006",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				mexec, ok := exec.(*mongomigrate.Executor)
+				if !ok {
+					return fmt.Errorf("expected mongomigrate executor, got %T", exec)
+				}
+
+				if err := mexec.CreateCollection(ctx, (*apiKeyModel)(nil)); err != nil {
+					return err
+				}
+
+				return mexec.CreateIndexes(ctx, colAPIKeys, []mongo.IndexModel{
+					{
+						Keys:    bson.D{{Key: "hash", Value: 1}},
+						Options: options.Index().SetUnique(true),
+					},
+					{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "created_at", Value: 1}}},
+				})
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				mexec, ok := exec.(*mongomigrate.Executor)
+				if !ok {
+					return fmt.Errorf("expected mongomigrate executor, got %T", exec)
+				}
+				return mexec.DropCollection(ctx, (*apiKeyModel)(nil))
+			},
+		},
 	)
 }
@@ -7,6 +7,7 @@ import (
 
 	"github.com/xraph/grove"
 
+	"github.com/xraph/relay/auth"
 	"github.com/xraph/relay/catalog"
 	"github.com/xraph/relay/delivery"
 	"github.com/xraph/relay/dlq"
@@ -29,8 +30,10 @@ type eventTypeModel struct {
 	SchemaVersion string            `grove:"schema_version"  bson:"schema_version"`
 	Version       string            `grove:"version"         bson:"version"`
 	Example       json.RawMessage   `grove:"example"         bson:"example,omitempty"`
+	Versions      []catalog.Version `grove:"versions"        bson:"versions,omitempty"`
 	IsDeprecated  bool              `grove:"is_deprecated"   bson:"is_deprecated"`
 	DeprecatedAt  *time.Time        `grove:"deprecated_at"   bson:"deprecated_at,omitempty"`
+	SunsetAt      *time.Time        `grove:"sunset_at"       bson:"sunset_at,omitempty"`
 	ScopeAppID    string            `grove:"scope_app_id"    bson:"scope_app_id"`
 	Metadata      map[string]string `grove:"metadata"        bson:"metadata,omitempty"`
 	CreatedAt     time.Time         `grove:"created_at"      bson:"created_at"`
@@ -47,8 +50,10 @@ func toEventTypeModel(et *catalog.EventType) *eventTypeModel {
 		SchemaVersion: et.Definition.SchemaVersion,
 		Version:       et.Definition.Version,
 		Example:       et.Definition.Example,
+		Versions:      et.Versions,
 		IsDeprecated:  et.IsDeprecated,
 		DeprecatedAt:  et.DeprecatedAt,
+		SunsetAt:      et.SunsetAt,
 		ScopeAppID:    et.ScopeAppID,
 		Metadata:      et.Metadata,
 		CreatedAt:     et.CreatedAt,
@@ -77,8 +82,10 @@ func fromEventTypeModel(m *eventTypeModel) (*catalog.EventType, error) {
 			Version:       m.Version,
 			Example:       m.Example,
 		},
+		Versions:     m.Versions,
 		IsDeprecated: m.IsDeprecated,
 		DeprecatedAt: m.DeprecatedAt,
+		SunsetAt:     m.SunsetAt,
 		ScopeAppID:   m.ScopeAppID,
 		Metadata:     m.Metadata,
 	}, nil
@@ -89,34 +96,50 @@ func fromEventTypeModel(m *eventTypeModel) (*catalog.EventType, error) {
 type endpointModel struct {
 	grove.BaseModel `grove:"table:relay_endpoints"`
 
-	ID          string            `grove:"id,pk"       bson:"_id"`
-	TenantID    string            `grove:"tenant_id"   bson:"tenant_id"`
-	URL         string            `grove:"url"         bson:"url"`
-	Description string            `grove:"description" bson:"description"`
-	Secret      string            `grove:"secret"      bson:"secret"`
-	EventTypes  []string          `grove:"event_types" bson:"event_types"`
-	Headers     map[string]string `grove:"headers"     bson:"headers,omitempty"`
-	Enabled     bool              `grove:"enabled"     bson:"enabled"`
-	RateLimit   int               `grove:"rate_limit"  bson:"rate_limit"`
-	Metadata    map[string]string `grove:"metadata"    bson:"metadata,omitempty"`
-	CreatedAt   time.Time         `grove:"created_at"  bson:"created_at"`
-	UpdatedAt   time.Time         `grove:"updated_at"  bson:"updated_at"`
+	ID             string            `grove:"id,pk"       bson:"_id"`
+	TenantID       string            `grove:"tenant_id"   bson:"tenant_id"`
+	URL            string            `grove:"url"         bson:"url"`
+	Description    string            `grove:"description" bson:"description"`
+	Secret         string            `grove:"secret"      bson:"secret"`
+	EventTypes     []string          `grove:"event_types" bson:"event_types"`
+	Headers        map[string]string `grove:"headers"     bson:"headers,omitempty"`
+	Enabled        bool              `grove:"enabled"     bson:"enabled"`
+	RateLimit      int               `grove:"rate_limit"  bson:"rate_limit"`
+	Version        string            `grove:"version"     bson:"version,omitempty"`
+	Status         string            `grove:"status"      bson:"status,omitempty"`
+	VerifiedAt     *time.Time        `grove:"verified_at" bson:"verified_at,omitempty"`
+	KeepDisabled   bool              `grove:"keep_disabled" bson:"keep_disabled,omitempty"`
+	DisabledReason string            `grove:"disabled_reason" bson:"disabled_reason,omitempty"`
+	DisabledAt     *time.Time        `grove:"disabled_at" bson:"disabled_at,omitempty"`
+	ScopeAppID     string            `grove:"scope_app_id" bson:"scope_app_id,omitempty"`
+	ScopeOrgID     string            `grove:"scope_org_id" bson:"scope_org_id,omitempty"`
+	Metadata       map[string]string `grove:"metadata"    bson:"metadata,omitempty"`
+	CreatedAt      time.Time         `grove:"created_at"  bson:"created_at"`
+	UpdatedAt      time.Time         `grove:"updated_at"  bson:"updated_at"`
 }
 
 func toEndpointModel(ep *endpoint.Endpoint) *endpointModel {
 	return &endpointModel{
-		ID:          ep.ID.String(),
-		TenantID:    ep.TenantID,
-		URL:         ep.URL,
-		Description: ep.Description,
-		Secret:      ep.Secret,
-		EventTypes:  ep.EventTypes,
-		Headers:     ep.Headers,
-		Enabled:     ep.Enabled,
-		RateLimit:   ep.RateLimit,
-		Metadata:    ep.Metadata,
-		CreatedAt:   ep.CreatedAt,
-		UpdatedAt:   ep.UpdatedAt,
+		ID:             ep.ID.String(),
+		TenantID:       ep.TenantID,
+		URL:            ep.URL,
+		Description:    ep.Description,
+		Secret:         ep.Secret,
+		EventTypes:     ep.EventTypes,
+		Headers:        ep.Headers,
+		Enabled:        ep.Enabled,
+		RateLimit:      ep.RateLimit,
+		Version:        ep.Version,
+		Status:         string(ep.Status),
+		VerifiedAt:     ep.VerifiedAt,
+		KeepDisabled:   ep.KeepDisabled,
+		DisabledReason: ep.DisabledReason,
+		DisabledAt:     ep.DisabledAt,
+		ScopeAppID:     ep.ScopeAppID,
+		ScopeOrgID:     ep.ScopeOrgID,
+		Metadata:       ep.Metadata,
+		CreatedAt:      ep.CreatedAt,
+		UpdatedAt:      ep.UpdatedAt,
 	}
 }
 
@@ -131,16 +154,24 @@ func fromEndpointModel(m *endpointModel) (*endpoint.Endpoint, error) {
 			CreatedAt: m.CreatedAt,
 			UpdatedAt: m.UpdatedAt,
 		},
-		ID:          epID,
-		TenantID:    m.TenantID,
-		URL:         m.URL,
-		Description: m.Description,
-		Secret:      m.Secret,
-		EventTypes:  m.EventTypes,
-		Headers:     m.Headers,
-		Enabled:     m.Enabled,
-		RateLimit:   m.RateLimit,
-		Metadata:    m.Metadata,
+		ID:             epID,
+		TenantID:       m.TenantID,
+		URL:            m.URL,
+		Description:    m.Description,
+		Secret:         m.Secret,
+		EventTypes:     m.EventTypes,
+		Headers:        m.Headers,
+		Enabled:        m.Enabled,
+		RateLimit:      m.RateLimit,
+		Version:        m.Version,
+		Status:         endpoint.Status(m.Status),
+		VerifiedAt:     m.VerifiedAt,
+		KeepDisabled:   m.KeepDisabled,
+		DisabledReason: m.DisabledReason,
+		DisabledAt:     m.DisabledAt,
+		ScopeAppID:     m.ScopeAppID,
+		ScopeOrgID:     m.ScopeOrgID,
+		Metadata:       m.Metadata,
 	}, nil
 }
 
@@ -153,7 +184,9 @@ type eventModel struct {
 	Type           string    `grove:"type"            bson:"type"`
 	TenantID       string    `grove:"tenant_id"       bson:"tenant_id"`
 	Data           any       `grove:"data"            bson:"data,omitempty"`
+	Version        string    `grove:"version"         bson:"version,omitempty"`
 	IdempotencyKey string    `grove:"idempotency_key" bson:"idempotency_key,omitempty"`
+	Test           bool      `grove:"test"            bson:"test,omitempty"`
 	ScopeAppID     string    `grove:"scope_app_id"    bson:"scope_app_id"`
 	ScopeOrgID     string    `grove:"scope_org_id"    bson:"scope_org_id"`
 	CreatedAt      time.Time `grove:"created_at"      bson:"created_at"`
@@ -166,7 +199,9 @@ func toEventModel(evt *event.Event) *eventModel {
 		Type:           evt.Type,
 		TenantID:       evt.TenantID,
 		Data:           evt.Data,
+		Version:        evt.Version,
 		IdempotencyKey: evt.IdempotencyKey,
+		Test:           evt.Test,
 		ScopeAppID:     evt.ScopeAppID,
 		ScopeOrgID:     evt.ScopeOrgID,
 		CreatedAt:      evt.CreatedAt,
@@ -189,7 +224,9 @@ func fromEventModel(m *eventModel) (*event.Event, error) {
 		Type:           m.Type,
 		TenantID:       m.TenantID,
 		Data:           m.Data,
+		Version:        m.Version,
 		IdempotencyKey: m.IdempotencyKey,
+		Test:           m.Test,
 		ScopeAppID:     m.ScopeAppID,
 		ScopeOrgID:     m.ScopeOrgID,
 	}, nil
@@ -200,38 +237,44 @@ func fromEventModel(m *eventModel) (*event.Event, error) {
 type deliveryModel struct {
 	grove.BaseModel `grove:"table:relay_deliveries"`
 
-	ID             string     `grove:"id,pk"            bson:"_id"`
-	EventID        string     `grove:"event_id"         bson:"event_id"`
-	EndpointID     string     `grove:"endpoint_id"      bson:"endpoint_id"`
-	State          string     `grove:"state"            bson:"state"`
-	AttemptCount   int        `grove:"attempt_count"    bson:"attempt_count"`
-	MaxAttempts    int        `grove:"max_attempts"     bson:"max_attempts"`
-	NextAttemptAt  time.Time  `grove:"next_attempt_at"  bson:"next_attempt_at"`
-	LastError      string     `grove:"last_error"       bson:"last_error"`
-	LastStatusCode int        `grove:"last_status_code" bson:"last_status_code"`
-	LastResponse   string     `grove:"last_response"    bson:"last_response"`
-	LastLatencyMs  int        `grove:"last_latency_ms"  bson:"last_latency_ms"`
-	CompletedAt    *time.Time `grove:"completed_at"     bson:"completed_at,omitempty"`
-	CreatedAt      time.Time  `grove:"created_at"       bson:"created_at"`
-	UpdatedAt      time.Time  `grove:"updated_at"       bson:"updated_at"`
+	ID                 string     `grove:"id,pk"            bson:"_id"`
+	EventID            string     `grove:"event_id"         bson:"event_id"`
+	EndpointID         string     `grove:"endpoint_id"      bson:"endpoint_id"`
+	State              string     `grove:"state"            bson:"state"`
+	AttemptCount       int        `grove:"attempt_count"    bson:"attempt_count"`
+	MaxAttempts        int        `grove:"max_attempts"     bson:"max_attempts"`
+	NextAttemptAt      time.Time  `grove:"next_attempt_at"  bson:"next_attempt_at"`
+	LastError          string     `grove:"last_error"       bson:"last_error"`
+	LastStatusCode     int        `grove:"last_status_code" bson:"last_status_code"`
+	LastResponse       string     `grove:"last_response"    bson:"last_response"`
+	LastLatencyMs      int        `grove:"last_latency_ms"  bson:"last_latency_ms"`
+	CompletedAt        *time.Time `grove:"completed_at"     bson:"completed_at,omitempty"`
+	Test               bool       `grove:"test"             bson:"test,omitempty"`
+	OriginalDeliveryID string     `grove:"original_delivery_id" bson:"original_delivery_id,omitempty"`
+	ReplayCount        int        `grove:"replay_count" bson:"replay_count,omitempty"`
+	CreatedAt          time.Time  `grove:"created_at"       bson:"created_at"`
+	UpdatedAt          time.Time  `grove:"updated_at"       bson:"updated_at"`
 }
 
 func toDeliveryModel(d *delivery.Delivery) *deliveryModel {
 	return &deliveryModel{
-		ID:             d.ID.String(),
-		EventID:        d.EventID.String(),
-		EndpointID:     d.EndpointID.String(),
-		State:          string(d.State),
-		AttemptCount:   d.AttemptCount,
-		MaxAttempts:    d.MaxAttempts,
-		NextAttemptAt:  d.NextAttemptAt,
-		LastError:      d.LastError,
-		LastStatusCode: d.LastStatusCode,
-		LastResponse:   d.LastResponse,
-		LastLatencyMs:  d.LastLatencyMs,
-		CompletedAt:    d.CompletedAt,
-		CreatedAt:      d.CreatedAt,
-		UpdatedAt:      d.UpdatedAt,
+		ID:                 d.ID.String(),
+		EventID:            d.EventID.String(),
+		EndpointID:         d.EndpointID.String(),
+		State:              string(d.State),
+		AttemptCount:       d.AttemptCount,
+		MaxAttempts:        d.MaxAttempts,
+		NextAttemptAt:      d.NextAttemptAt,
+		LastError:          d.LastError,
+		LastStatusCode:     d.LastStatusCode,
+		LastResponse:       d.LastResponse,
+		LastLatencyMs:      d.LastLatencyMs,
+		CompletedAt:        d.CompletedAt,
+		Test:               d.Test,
+		OriginalDeliveryID: optionalID(d.OriginalDeliveryID),
+		ReplayCount:        d.ReplayCount,
+		CreatedAt:          d.CreatedAt,
+		UpdatedAt:          d.UpdatedAt,
 	}
 }
 
@@ -251,23 +294,31 @@ func fromDeliveryModel(m *deliveryModel) (*delivery.Delivery, error) {
 		return nil, fmt.Errorf("parse endpoint ID %q: %w", m.EndpointID, err)
 	}
 
+	origID, err := parseOptionalID(m.OriginalDeliveryID, id.ParseDeliveryID)
+	if err != nil {
+		return nil, fmt.Errorf("parse original delivery ID %q: %w", m.OriginalDeliveryID, err)
+	}
+
 	return &delivery.Delivery{
 		Entity: entity.Entity{
 			CreatedAt: m.CreatedAt,
 			UpdatedAt: m.UpdatedAt,
 		},
-		ID:             delID,
-		EventID:        evtID,
-		EndpointID:     epID,
-		State:          delivery.State(m.State),
-		AttemptCount:   m.AttemptCount,
-		MaxAttempts:    m.MaxAttempts,
-		NextAttemptAt:  m.NextAttemptAt,
-		LastError:      m.LastError,
-		LastStatusCode: m.LastStatusCode,
-		LastResponse:   m.LastResponse,
-		LastLatencyMs:  m.LastLatencyMs,
-		CompletedAt:    m.CompletedAt,
+		ID:                 delID,
+		EventID:            evtID,
+		EndpointID:         epID,
+		State:              delivery.State(m.State),
+		AttemptCount:       m.AttemptCount,
+		MaxAttempts:        m.MaxAttempts,
+		NextAttemptAt:      m.NextAttemptAt,
+		LastError:          m.LastError,
+		LastStatusCode:     m.LastStatusCode,
+		LastResponse:       m.LastResponse,
+		LastLatencyMs:      m.LastLatencyMs,
+		CompletedAt:        m.CompletedAt,
+		Test:               m.Test,
+		OriginalDeliveryID: origID,
+		ReplayCount:        m.ReplayCount,
 	}, nil
 }
 
@@ -276,40 +327,44 @@ func fromDeliveryModel(m *deliveryModel) (*delivery.Delivery, error) {
 type dlqEntryModel struct {
 	grove.BaseModel `grove:"table:relay_dlq"`
 
-	ID             string     `grove:"id,pk"            bson:"_id"`
-	DeliveryID     string     `grove:"delivery_id"      bson:"delivery_id"`
-	EventID        string     `grove:"event_id"         bson:"event_id"`
-	EndpointID     string     `grove:"endpoint_id"      bson:"endpoint_id"`
-	TenantID       string     `grove:"tenant_id"        bson:"tenant_id"`
-	EventType      string     `grove:"event_type"       bson:"event_type"`
-	URL            string     `grove:"url"              bson:"url"`
-	Payload        any        `grove:"payload"          bson:"payload,omitempty"`
-	Error          string     `grove:"error"            bson:"error"`
-	AttemptCount   int        `grove:"attempt_count"    bson:"attempt_count"`
-	LastStatusCode int        `grove:"last_status_code" bson:"last_status_code"`
-	ReplayedAt     *time.Time `grove:"replayed_at"      bson:"replayed_at,omitempty"`
-	FailedAt       time.Time  `grove:"failed_at"        bson:"failed_at"`
-	CreatedAt      time.Time  `grove:"created_at"       bson:"created_at"`
-	UpdatedAt      time.Time  `grove:"updated_at"       bson:"updated_at"`
+	ID               string     `grove:"id,pk"            bson:"_id"`
+	DeliveryID       string     `grove:"delivery_id"      bson:"delivery_id"`
+	EventID          string     `grove:"event_id"         bson:"event_id"`
+	EndpointID       string     `grove:"endpoint_id"      bson:"endpoint_id"`
+	TenantID         string     `grove:"tenant_id"        bson:"tenant_id"`
+	EventType        string     `grove:"event_type"       bson:"event_type"`
+	URL              string     `grove:"url"              bson:"url"`
+	Payload          any        `grove:"payload"          bson:"payload,omitempty"`
+	Error            string     `grove:"error"            bson:"error"`
+	AttemptCount     int        `grove:"attempt_count"    bson:"attempt_count"`
+	LastStatusCode   int        `grove:"last_status_code" bson:"last_status_code"`
+	ReplayCount      int        `grove:"replay_count" bson:"replay_count,omitempty"`
+	ReplayedAt       *time.Time `grove:"replayed_at"      bson:"replayed_at,omitempty"`
+	ReplayDeliveryID string     `grove:"replay_delivery_id" bson:"replay_delivery_id,omitempty"`
+	FailedAt         time.Time  `grove:"failed_at"        bson:"failed_at"`
+	CreatedAt        time.Time  `grove:"created_at"       bson:"created_at"`
+	UpdatedAt        time.Time  `grove:"updated_at"       bson:"updated_at"`
 }
 
 func toDLQEntryModel(e *dlq.Entry) *dlqEntryModel {
 	return &dlqEntryModel{
-		ID:             e.ID.String(),
-		DeliveryID:     e.DeliveryID.String(),
-		EventID:        e.EventID.String(),
-		EndpointID:     e.EndpointID.String(),
-		TenantID:       e.TenantID,
-		EventType:      e.EventType,
-		URL:            e.URL,
-		Payload:        e.Payload,
-		Error:          e.Error,
-		AttemptCount:   e.AttemptCount,
-		LastStatusCode: e.LastStatusCode,
-		ReplayedAt:     e.ReplayedAt,
-		FailedAt:       e.FailedAt,
-		CreatedAt:      e.CreatedAt,
-		UpdatedAt:      e.UpdatedAt,
+		ID:               e.ID.String(),
+		DeliveryID:       e.DeliveryID.String(),
+		EventID:          e.EventID.String(),
+		EndpointID:       e.EndpointID.String(),
+		TenantID:         e.TenantID,
+		EventType:        e.EventType,
+		URL:              e.URL,
+		Payload:          e.Payload,
+		Error:            e.Error,
+		AttemptCount:     e.AttemptCount,
+		LastStatusCode:   e.LastStatusCode,
+		ReplayCount:      e.ReplayCount,
+		ReplayedAt:       e.ReplayedAt,
+		ReplayDeliveryID: optionalID(e.ReplayDeliveryID),
+		FailedAt:         e.FailedAt,
+		CreatedAt:        e.CreatedAt,
+		UpdatedAt:        e.UpdatedAt,
 	}
 }
 
@@ -334,23 +389,106 @@ func fromDLQEntryModel(m *dlqEntryModel) (*dlq.Entry, error) {
 		return nil, fmt.Errorf("parse endpoint ID %q: %w", m.EndpointID, err)
 	}
 
+	replayID, err := parseOptionalID(m.ReplayDeliveryID, id.ParseDeliveryID)
+	if err != nil {
+		return nil, fmt.Errorf("parse replay delivery ID %q: %w", m.ReplayDeliveryID, err)
+	}
+
 	return &dlq.Entry{
 		Entity: entity.Entity{
 			CreatedAt: m.CreatedAt,
 			UpdatedAt: m.UpdatedAt,
 		},
-		ID:             dlqID,
-		DeliveryID:     delID,
-		EventID:        evtID,
-		EndpointID:     epID,
-		TenantID:       m.TenantID,
-		EventType:      m.EventType,
-		URL:            m.URL,
-		Payload:        m.Payload,
-		Error:          m.Error,
-		AttemptCount:   m.AttemptCount,
-		LastStatusCode: m.LastStatusCode,
-		ReplayedAt:     m.ReplayedAt,
-		FailedAt:       m.FailedAt,
+		ID:               dlqID,
+		DeliveryID:       delID,
+		EventID:          evtID,
+		EndpointID:       epID,
+		TenantID:         m.TenantID,
+		EventType:        m.EventType,
+		URL:              m.URL,
+		Payload:          m.Payload,
+		Error:            m.Error,
+		AttemptCount:     m.AttemptCount,
+		LastStatusCode:   m.LastStatusCode,
+		ReplayCount:      m.ReplayCount,
+		ReplayedAt:       m.ReplayedAt,
+		ReplayDeliveryID: replayID,
+		FailedAt:         m.FailedAt,
 	}, nil
 }
+
+// --- API key models ---
+
+type apiKeyModel struct {
+	grove.BaseModel `grove:"table:relay_api_keys"`
+
+	ID        string     `grove:"id,pk"      bson:"_id"`
+	Name      string     `grove:"name"       bson:"name"`
+	Prefix    string     `grove:"prefix"     bson:"prefix"`
+	Hash      string     `grove:"hash"       bson:"hash"`
+	TenantID  string     `grove:"tenant_id"  bson:"tenant_id"`
+	Scopes    []string   `grove:"scopes"     bson:"scopes"`
+	ExpiresAt *time.Time `grove:"expires_at" bson:"expires_at,omitempty"`
+	CreatedAt time.Time  `grove:"created_at" bson:"created_at"`
+	UpdatedAt time.Time  `grove:"updated_at" bson:"updated_at"`
+}
+
+func toAPIKeyModel(k *auth.APIKey) *apiKeyModel {
+	return &apiKeyModel{
+		ID:        k.ID.String(),
+		Name:      k.Name,
+		Prefix:    k.Prefix,
+		Hash:      k.Hash,
+		TenantID:  k.TenantID,
+		Scopes:    k.Scopes,
+		ExpiresAt: k.ExpiresAt,
+		CreatedAt: k.CreatedAt,
+		UpdatedAt: k.UpdatedAt,
+	}
+}
+
+func fromAPIKeyModel(m *apiKeyModel) (*auth.APIKey, error) {
+	keyID, err := id.ParseAPIKeyID(m.ID)
+	if err != nil {
+		return nil, fmt.Errorf("parse API key ID %q: %w", m.ID, err)
+	}
+
+	return &auth.APIKey{
+		Entity: entity.Entity{
+			CreatedAt: m.CreatedAt,
+			UpdatedAt: m.UpdatedAt,
+		},
+		ID:        keyID,
+		Name:      m.Name,
+		Prefix:    m.Prefix,
+		Hash:      m.Hash,
+		TenantID:  m.TenantID,
+		Scopes:    m.Scopes,
+		ExpiresAt: m.ExpiresAt,
+	}, nil
+}
+
+// --- Optional IDs ---
+
+// optionalID returns the stored form of an optional ID: empty for nil.
+func optionalID(i *id.ID) string {
+	if i == nil {
+		return ""
+	}
+
+	return i.String()
+}
+
+// parseOptionalID parses an optional ID stored by optionalID.
+func parseOptionalID(s string, parse func(string) (id.ID, error)) (*id.ID, error) {
+	if s == "" {
+		return nil, nil //nolint:nilnil // no ID stored
+	}
+
+	i, err := parse(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return &i, nil
+}
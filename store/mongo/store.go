@@ -12,6 +12,8 @@ import (
 	"github.com/xraph/grove"
 	"github.com/xraph/grove/drivers/mongodriver"
 
+	"github.com/xraph/relay/endpoint"
+	"github.com/xraph/relay/id"
 	"github.com/xraph/relay/store"
 )
 
@@ -22,6 +24,7 @@ const (
 	colEvents     = "relay_events"
 	colDeliveries = "relay_deliveries"
 	colDLQ        = "relay_dlq"
+	colAPIKeys    = "relay_api_keys"
 )
 
 // Compile-time interface check.
@@ -31,14 +34,19 @@ var _ store.Store = (*Store)(nil)
 type Store struct {
 	db  *grove.DB
 	mdb *mongodriver.MongoDB
+
+	index *endpoint.Index // subscription index backing Resolve
 }
 
 // New creates a new MongoDB store backed by Grove ORM.
 func New(db *grove.DB) *Store {
-	return &Store{
+	s := &Store{
 		db:  db,
 		mdb: mongodriver.Unwrap(db),
 	}
+	s.index = endpoint.NewIndex(s.tenantEndpoints, endpoint.DefaultIndexTTL)
+
+	return s
 }
 
 // DB returns the underlying grove database for direct access.
@@ -108,5 +116,37 @@ func migrationIndexes() map[string][]mongo.IndexModel {
 			{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "failed_at", Value: -1}}},
 			{Keys: bson.D{{Key: "endpoint_id", Value: 1}}},
 		},
+		colAPIKeys: {
+			{
+				Keys:    bson.D{{Key: "hash", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+			{Keys: bson.D{{Key: "tenant_id", Value: 1}, {Key: "created_at", Value: 1}}},
+		},
+	}
+}
+
+// afterID narrows filter to the documents past cursor in ID order.
+func afterID(filter bson.M, cursor id.ID, desc bool) bson.M {
+	if cursor.IsNil() {
+		return filter
 	}
+
+	op := "$gt"
+	if desc {
+		op = "$lt"
+	}
+
+	filter["_id"] = bson.M{op: cursor.String()}
+
+	return filter
+}
+
+// idOrder returns the _id sort direction matching afterID.
+func idOrder(desc bool) int {
+	if desc {
+		return -1
+	}
+
+	return 1
 }
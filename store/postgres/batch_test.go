@@ -0,0 +1,59 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/xraph/relay"
+	"github.com/xraph/relay/catalog"
+	"github.com/xraph/relay/endpoint"
+	"github.com/xraph/relay/event"
+)
+
+// TestSendBatchManyEndpoints sends a full chunk of events to enough
+// endpoints that the deliveries need more bind parameters than one
+// Postgres statement takes.
+func TestSendBatchManyEndpoints(t *testing.T) {
+	s := openPgStore(t, startPostgres(t))
+	ctx := context.Background()
+
+	r, err := relay.New(relay.WithStore(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.RegisterEventType(ctx, catalog.WebhookDefinition{Name: "order.created"}); err != nil {
+		t.Fatal(err)
+	}
+	const endpoints = 20
+	for range endpoints {
+		if _, err := r.Endpoints().Create(ctx, endpoint.Input{
+			TenantID:   "acme",
+			URL:        "https://example.com/hooks",
+			EventTypes: []string{"order.*"},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	evts := make([]*event.Event, 500)
+	for i := range evts {
+		evts[i] = &event.Event{Type: "order.created", TenantID: "acme"}
+	}
+	results, err := r.SendBatch(ctx, evts)
+	if err != nil {
+		t.Fatalf("send batch: %v", err)
+	}
+	for i, res := range results {
+		if res.Err != nil {
+			t.Fatalf("event %d: %v", i, res.Err)
+		}
+	}
+
+	pending, err := s.CountPending(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending != int64(len(evts)*endpoints) {
+		t.Fatalf("expected %d pending deliveries, got %d", len(evts)*endpoints, pending)
+	}
+}
@@ -0,0 +1,11 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/xraph/relay/internal/storetest"
+)
+
+func TestRegisterKeepsDeprecation(t *testing.T) {
+	storetest.RegisterKeepsDeprecation(t, openPgStore(t, startPostgres(t)))
+}
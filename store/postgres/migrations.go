@@ -164,5 +164,215 @@ CREATE INDEX IF NOT EXISTS idx_relay_dlq_tenant ON relay_dlq (tenant_id);
 				return err
 			},
 		},
+		&migrate.Migration{
+			Name:    "add_relay_versioning",
+			Version: "20240101000006",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+ALTER TABLE relay_event_types ADD COLUMN IF NOT EXISTS versions JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE relay_endpoints ADD COLUMN IF NOT EXISTS version TEXT NOT NULL DEFAULT '';
+ALTER TABLE relay_events ADD COLUMN IF NOT EXISTS version TEXT NOT NULL DEFAULT '';
+`)
+				return err
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+ALTER TABLE relay_event_types DROP COLUMN IF EXISTS versions;
+ALTER TABLE relay_endpoints DROP COLUMN IF EXISTS version;
+ALTER TABLE relay_events DROP COLUMN IF EXISTS version;
+`)
+				return err
+			},
+		},
+		&migrate.Migration{
+			Name:    "add_relay_sunset",
+			Version: "20240101000007",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+ALTER TABLE relay_event_types ADD COLUMN IF NOT EXISTS sunset_at TIMESTAMPTZ;
+`)
+				return err
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+ALTER TABLE relay_event_types DROP COLUMN IF EXISTS sunset_at;
+`)
+				return err
+			},
+		},
+		&migrate.Migration{
+			Name:    "add_relay_delivery_test",
+			Version: "20240101000008",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+ALTER TABLE relay_deliveries ADD COLUMN IF NOT EXISTS test BOOLEAN NOT NULL DEFAULT FALSE;
+`)
+				return err
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+ALTER TABLE relay_deliveries DROP COLUMN IF EXISTS test;
+`)
+				return err
+			},
+		},
+		&migrate.Migration{
+			Name:    "add_relay_endpoint_verification",
+			Version: "20240101000009",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+ALTER TABLE relay_endpoints ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'active';
+ALTER TABLE relay_endpoints ADD COLUMN IF NOT EXISTS verified_at TIMESTAMPTZ;
+`)
+				return err
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+ALTER TABLE relay_endpoints DROP COLUMN IF EXISTS verified_at;
+ALTER TABLE relay_endpoints DROP COLUMN IF EXISTS status;
+`)
+				return err
+			},
+		},
+		&migrate.Migration{
+			Name:    "add_relay_endpoint_disabled_reason",
+			Version: "20240101000010",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+ALTER TABLE relay_endpoints ADD COLUMN IF NOT EXISTS disabled_reason TEXT NOT NULL DEFAULT '';
+ALTER TABLE relay_endpoints ADD COLUMN IF NOT EXISTS disabled_at TIMESTAMPTZ;
+`)
+				return err
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+ALTER TABLE relay_endpoints DROP COLUMN IF EXISTS disabled_at;
+ALTER TABLE relay_endpoints DROP COLUMN IF EXISTS disabled_reason;
+`)
+				return err
+			},
+		},
+		&migrate.Migration{
+			Name:    "add_relay_endpoint_scope",
+			Version: "20240101000011",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+ALTER TABLE relay_endpoints ADD COLUMN IF NOT EXISTS scope_app_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE relay_endpoints ADD COLUMN IF NOT EXISTS scope_org_id TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_relay_endpoints_scope ON relay_endpoints (scope_app_id, scope_org_id);
+CREATE INDEX IF NOT EXISTS idx_relay_events_scope ON relay_events (scope_app_id, scope_org_id);
+`)
+				return err
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+DROP INDEX IF EXISTS idx_relay_events_scope;
+DROP INDEX IF EXISTS idx_relay_endpoints_scope;
+ALTER TABLE relay_endpoints DROP COLUMN IF EXISTS scope_org_id;
+ALTER TABLE relay_endpoints DROP COLUMN IF EXISTS scope_app_id;
+`)
+				return err
+			},
+		},
+		&migrate.Migration{
+			Name:    "create_relay_api_keys",
+			Version: "20240101000012",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+CREATE TABLE IF NOT EXISTS relay_api_keys (
+    id         TEXT PRIMARY KEY,
+    name       TEXT NOT NULL DEFAULT '',
+    prefix     TEXT NOT NULL DEFAULT '',
+    hash       TEXT NOT NULL UNIQUE,
+    tenant_id  TEXT NOT NULL DEFAULT '',
+    scopes     TEXT[] NOT NULL DEFAULT '{}',
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_relay_api_keys_tenant ON relay_api_keys (tenant_id);
+`)
+				return err
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `DROP TABLE IF EXISTS relay_api_keys`)
+				return err
+			},
+		},
+		&migrate.Migration{
+			Name:    "add_relay_cursor_indexes",
+			Version: "20240101000013",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+CREATE INDEX IF NOT EXISTS idx_relay_events_tenant_id ON relay_events (tenant_id, id);
+CREATE INDEX IF NOT EXISTS idx_relay_deliveries_endpoint_id ON relay_deliveries (endpoint_id, id);
+CREATE INDEX IF NOT EXISTS idx_relay_dlq_tenant_id ON relay_dlq (tenant_id, id);
+`)
+				return err
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+DROP INDEX IF EXISTS idx_relay_dlq_tenant_id;
+DROP INDEX IF EXISTS idx_relay_deliveries_endpoint_id;
+DROP INDEX IF EXISTS idx_relay_events_tenant_id;
+`)
+				return err
+			},
+		},
+		&migrate.Migration{
+			Name:    "add_relay_replay_lineage",
+			Version: "20240101000014",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+ALTER TABLE relay_deliveries ADD COLUMN IF NOT EXISTS original_delivery_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE relay_deliveries ADD COLUMN IF NOT EXISTS replay_count INT NOT NULL DEFAULT 0;
+ALTER TABLE relay_dlq ADD COLUMN IF NOT EXISTS replay_count INT NOT NULL DEFAULT 0;
+ALTER TABLE relay_dlq ADD COLUMN IF NOT EXISTS replay_delivery_id TEXT NOT NULL DEFAULT '';
+`)
+				return err
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+ALTER TABLE relay_dlq DROP COLUMN IF EXISTS replay_delivery_id;
+ALTER TABLE relay_dlq DROP COLUMN IF EXISTS replay_count;
+ALTER TABLE relay_deliveries DROP COLUMN IF EXISTS replay_count;
+ALTER TABLE relay_deliveries DROP COLUMN IF EXISTS original_delivery_id;
+`)
+				return err
+			},
+		},
+		&migrate.Migration{
+			Name:    "add_relay_endpoint_keep_disabled",
+			Version: "20240101000015",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+ALTER TABLE relay_endpoints ADD COLUMN IF NOT EXISTS keep_disabled BOOLEAN NOT NULL DEFAULT FALSE;
+`)
+				return err
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+ALTER TABLE relay_endpoints DROP COLUMN IF EXISTS keep_disabled;
+`)
+				return err
+			},
+		},
+		&migrate.Migration{
+			Name:    "add_relay_event_test",
+			Version: "20240101000016",
+			Up: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+ALTER TABLE relay_events ADD COLUMN IF NOT EXISTS test BOOLEAN NOT NULL DEFAULT FALSE;
+`)
+				return err
+			},
+			Down: func(ctx context.Context, exec migrate.Executor) error {
+				_, err := exec.Exec(ctx, `
+ALTER TABLE relay_events DROP COLUMN IF EXISTS test;
+`)
+				return err
+			},
+		},
 	)
 }
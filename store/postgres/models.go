@@ -7,6 +7,7 @@ import (
 
 	"github.com/xraph/grove"
 
+	"github.com/xraph/relay/auth"
 	"github.com/xraph/relay/catalog"
 	"github.com/xraph/relay/delivery"
 	"github.com/xraph/relay/dlq"
@@ -29,8 +30,10 @@ type eventTypeModel struct {
 	SchemaVersion string            `grove:"schema_version"`
 	Version       string            `grove:"version"`
 	Example       json.RawMessage   `grove:"example,type:jsonb"`
+	Versions      []catalog.Version `grove:"versions,type:jsonb"`
 	IsDeprecated  bool              `grove:"is_deprecated"`
 	DeprecatedAt  *time.Time        `grove:"deprecated_at"`
+	SunsetAt      *time.Time        `grove:"sunset_at"`
 	ScopeAppID    string            `grove:"scope_app_id"`
 	Metadata      map[string]string `grove:"metadata,type:jsonb"`
 	CreatedAt     time.Time         `grove:"created_at"`
@@ -42,6 +45,10 @@ func toEventTypeModel(et *catalog.EventType) *eventTypeModel {
 	if md == nil {
 		md = map[string]string{}
 	}
+	versions := et.Versions
+	if versions == nil {
+		versions = []catalog.Version{}
+	}
 	return &eventTypeModel{
 		ID:            et.ID.String(),
 		Name:          et.Definition.Name,
@@ -51,8 +58,10 @@ func toEventTypeModel(et *catalog.EventType) *eventTypeModel {
 		SchemaVersion: et.Definition.SchemaVersion,
 		Version:       et.Definition.Version,
 		Example:       et.Definition.Example,
+		Versions:      versions,
 		IsDeprecated:  et.IsDeprecated,
 		DeprecatedAt:  et.DeprecatedAt,
+		SunsetAt:      et.SunsetAt,
 		ScopeAppID:    et.ScopeAppID,
 		Metadata:      md,
 		CreatedAt:     et.CreatedAt,
@@ -80,8 +89,10 @@ func fromEventTypeModel(m *eventTypeModel) (*catalog.EventType, error) {
 			Version:       m.Version,
 			Example:       m.Example,
 		},
+		Versions:     m.Versions,
 		IsDeprecated: m.IsDeprecated,
 		DeprecatedAt: m.DeprecatedAt,
+		SunsetAt:     m.SunsetAt,
 		ScopeAppID:   m.ScopeAppID,
 		Metadata:     m.Metadata,
 	}, nil
@@ -92,18 +103,26 @@ func fromEventTypeModel(m *eventTypeModel) (*catalog.EventType, error) {
 type endpointModel struct {
 	grove.BaseModel `grove:"table:relay_endpoints"`
 
-	ID          string            `grove:"id,pk"`
-	TenantID    string            `grove:"tenant_id"`
-	URL         string            `grove:"url"`
-	Description string            `grove:"description"`
-	Secret      string            `grove:"secret"`
-	EventTypes  []string          `grove:"event_types,array"`
-	Headers     map[string]string `grove:"headers,type:jsonb"`
-	Enabled     bool              `grove:"enabled"`
-	RateLimit   int               `grove:"rate_limit"`
-	Metadata    map[string]string `grove:"metadata,type:jsonb"`
-	CreatedAt   time.Time         `grove:"created_at"`
-	UpdatedAt   time.Time         `grove:"updated_at"`
+	ID             string            `grove:"id,pk"`
+	TenantID       string            `grove:"tenant_id"`
+	URL            string            `grove:"url"`
+	Description    string            `grove:"description"`
+	Secret         string            `grove:"secret"`
+	EventTypes     []string          `grove:"event_types,array"`
+	Headers        map[string]string `grove:"headers,type:jsonb"`
+	Enabled        bool              `grove:"enabled"`
+	RateLimit      int               `grove:"rate_limit"`
+	Version        string            `grove:"version"`
+	Status         string            `grove:"status"`
+	VerifiedAt     *time.Time        `grove:"verified_at"`
+	KeepDisabled   bool              `grove:"keep_disabled"`
+	DisabledReason string            `grove:"disabled_reason"`
+	DisabledAt     *time.Time        `grove:"disabled_at"`
+	ScopeAppID     string            `grove:"scope_app_id"`
+	ScopeOrgID     string            `grove:"scope_org_id"`
+	Metadata       map[string]string `grove:"metadata,type:jsonb"`
+	CreatedAt      time.Time         `grove:"created_at"`
+	UpdatedAt      time.Time         `grove:"updated_at"`
 }
 
 func toEndpointModel(ep *endpoint.Endpoint) *endpointModel {
@@ -116,18 +135,26 @@ func toEndpointModel(ep *endpoint.Endpoint) *endpointModel {
 		md = map[string]string{}
 	}
 	return &endpointModel{
-		ID:          ep.ID.String(),
-		TenantID:    ep.TenantID,
-		URL:         ep.URL,
-		Description: ep.Description,
-		Secret:      ep.Secret,
-		EventTypes:  ep.EventTypes,
-		Headers:     headers,
-		Enabled:     ep.Enabled,
-		RateLimit:   ep.RateLimit,
-		Metadata:    md,
-		CreatedAt:   ep.CreatedAt,
-		UpdatedAt:   ep.UpdatedAt,
+		ID:             ep.ID.String(),
+		TenantID:       ep.TenantID,
+		URL:            ep.URL,
+		Description:    ep.Description,
+		Secret:         ep.Secret,
+		EventTypes:     ep.EventTypes,
+		Headers:        headers,
+		Enabled:        ep.Enabled,
+		RateLimit:      ep.RateLimit,
+		Version:        ep.Version,
+		Status:         string(ep.Status),
+		VerifiedAt:     ep.VerifiedAt,
+		KeepDisabled:   ep.KeepDisabled,
+		DisabledReason: ep.DisabledReason,
+		DisabledAt:     ep.DisabledAt,
+		ScopeAppID:     ep.ScopeAppID,
+		ScopeOrgID:     ep.ScopeOrgID,
+		Metadata:       md,
+		CreatedAt:      ep.CreatedAt,
+		UpdatedAt:      ep.UpdatedAt,
 	}
 }
 
@@ -141,16 +168,24 @@ func fromEndpointModel(m *endpointModel) (*endpoint.Endpoint, error) {
 			CreatedAt: m.CreatedAt,
 			UpdatedAt: m.UpdatedAt,
 		},
-		ID:          epID,
-		TenantID:    m.TenantID,
-		URL:         m.URL,
-		Description: m.Description,
-		Secret:      m.Secret,
-		EventTypes:  m.EventTypes,
-		Headers:     m.Headers,
-		Enabled:     m.Enabled,
-		RateLimit:   m.RateLimit,
-		Metadata:    m.Metadata,
+		ID:             epID,
+		TenantID:       m.TenantID,
+		URL:            m.URL,
+		Description:    m.Description,
+		Secret:         m.Secret,
+		EventTypes:     m.EventTypes,
+		Headers:        m.Headers,
+		Enabled:        m.Enabled,
+		RateLimit:      m.RateLimit,
+		Version:        m.Version,
+		Status:         endpoint.Status(m.Status),
+		VerifiedAt:     m.VerifiedAt,
+		KeepDisabled:   m.KeepDisabled,
+		DisabledReason: m.DisabledReason,
+		DisabledAt:     m.DisabledAt,
+		ScopeAppID:     m.ScopeAppID,
+		ScopeOrgID:     m.ScopeOrgID,
+		Metadata:       m.Metadata,
 	}, nil
 }
 
@@ -163,7 +198,9 @@ type eventModel struct {
 	Type           string          `grove:"type"`
 	TenantID       string          `grove:"tenant_id"`
 	Data           json.RawMessage `grove:"data,type:jsonb"`
+	Version        string          `grove:"version"`
 	IdempotencyKey string          `grove:"idempotency_key"`
+	Test           bool            `grove:"test"`
 	ScopeAppID     string          `grove:"scope_app_id"`
 	ScopeOrgID     string          `grove:"scope_org_id"`
 	CreatedAt      time.Time       `grove:"created_at"`
@@ -177,7 +214,9 @@ func toEventModel(evt *event.Event) *eventModel {
 		Type:           evt.Type,
 		TenantID:       evt.TenantID,
 		Data:           data,
+		Version:        evt.Version,
 		IdempotencyKey: evt.IdempotencyKey,
+		Test:           evt.Test,
 		ScopeAppID:     evt.ScopeAppID,
 		ScopeOrgID:     evt.ScopeOrgID,
 		CreatedAt:      evt.CreatedAt,
@@ -200,7 +239,9 @@ func fromEventModel(m *eventModel) (*event.Event, error) {
 		Type:           m.Type,
 		TenantID:       m.TenantID,
 		Data:           data,
+		Version:        m.Version,
 		IdempotencyKey: m.IdempotencyKey,
+		Test:           m.Test,
 		ScopeAppID:     m.ScopeAppID,
 		ScopeOrgID:     m.ScopeOrgID,
 	}, nil
@@ -211,38 +252,44 @@ func fromEventModel(m *eventModel) (*event.Event, error) {
 type deliveryModel struct {
 	grove.BaseModel `grove:"table:relay_deliveries"`
 
-	ID             string     `grove:"id,pk"`
-	EventID        string     `grove:"event_id"`
-	EndpointID     string     `grove:"endpoint_id"`
-	State          string     `grove:"state"`
-	AttemptCount   int        `grove:"attempt_count"`
-	MaxAttempts    int        `grove:"max_attempts"`
-	NextAttemptAt  time.Time  `grove:"next_attempt_at"`
-	LastError      string     `grove:"last_error"`
-	LastStatusCode int        `grove:"last_status_code"`
-	LastResponse   string     `grove:"last_response"`
-	LastLatencyMs  int        `grove:"last_latency_ms"`
-	CompletedAt    *time.Time `grove:"completed_at"`
-	CreatedAt      time.Time  `grove:"created_at"`
-	UpdatedAt      time.Time  `grove:"updated_at"`
+	ID                 string     `grove:"id,pk"`
+	EventID            string     `grove:"event_id"`
+	EndpointID         string     `grove:"endpoint_id"`
+	State              string     `grove:"state"`
+	AttemptCount       int        `grove:"attempt_count"`
+	MaxAttempts        int        `grove:"max_attempts"`
+	NextAttemptAt      time.Time  `grove:"next_attempt_at"`
+	LastError          string     `grove:"last_error"`
+	LastStatusCode     int        `grove:"last_status_code"`
+	LastResponse       string     `grove:"last_response"`
+	LastLatencyMs      int        `grove:"last_latency_ms"`
+	CompletedAt        *time.Time `grove:"completed_at"`
+	Test               bool       `grove:"test"`
+	OriginalDeliveryID string     `grove:"original_delivery_id"`
+	ReplayCount        int        `grove:"replay_count"`
+	CreatedAt          time.Time  `grove:"created_at"`
+	UpdatedAt          time.Time  `grove:"updated_at"`
 }
 
 func toDeliveryModel(d *delivery.Delivery) *deliveryModel {
 	return &deliveryModel{
-		ID:             d.ID.String(),
-		EventID:        d.EventID.String(),
-		EndpointID:     d.EndpointID.String(),
-		State:          string(d.State),
-		AttemptCount:   d.AttemptCount,
-		MaxAttempts:    d.MaxAttempts,
-		NextAttemptAt:  d.NextAttemptAt,
-		LastError:      d.LastError,
-		LastStatusCode: d.LastStatusCode,
-		LastResponse:   d.LastResponse,
-		LastLatencyMs:  d.LastLatencyMs,
-		CompletedAt:    d.CompletedAt,
-		CreatedAt:      d.CreatedAt,
-		UpdatedAt:      d.UpdatedAt,
+		ID:                 d.ID.String(),
+		EventID:            d.EventID.String(),
+		EndpointID:         d.EndpointID.String(),
+		State:              string(d.State),
+		AttemptCount:       d.AttemptCount,
+		MaxAttempts:        d.MaxAttempts,
+		NextAttemptAt:      d.NextAttemptAt,
+		LastError:          d.LastError,
+		LastStatusCode:     d.LastStatusCode,
+		LastResponse:       d.LastResponse,
+		LastLatencyMs:      d.LastLatencyMs,
+		CompletedAt:        d.CompletedAt,
+		Test:               d.Test,
+		OriginalDeliveryID: optionalID(d.OriginalDeliveryID),
+		ReplayCount:        d.ReplayCount,
+		CreatedAt:          d.CreatedAt,
+		UpdatedAt:          d.UpdatedAt,
 	}
 }
 
@@ -259,23 +306,30 @@ func fromDeliveryModel(m *deliveryModel) (*delivery.Delivery, error) {
 	if err != nil {
 		return nil, fmt.Errorf("parse endpoint ID %q: %w", m.EndpointID, err)
 	}
+	origID, err := parseOptionalID(m.OriginalDeliveryID, id.ParseDeliveryID)
+	if err != nil {
+		return nil, fmt.Errorf("parse original delivery ID %q: %w", m.OriginalDeliveryID, err)
+	}
 	return &delivery.Delivery{
 		Entity: entity.Entity{
 			CreatedAt: m.CreatedAt,
 			UpdatedAt: m.UpdatedAt,
 		},
-		ID:             delID,
-		EventID:        evtID,
-		EndpointID:     epID,
-		State:          delivery.State(m.State),
-		AttemptCount:   m.AttemptCount,
-		MaxAttempts:    m.MaxAttempts,
-		NextAttemptAt:  m.NextAttemptAt,
-		LastError:      m.LastError,
-		LastStatusCode: m.LastStatusCode,
-		LastResponse:   m.LastResponse,
-		LastLatencyMs:  m.LastLatencyMs,
-		CompletedAt:    m.CompletedAt,
+		ID:                 delID,
+		EventID:            evtID,
+		EndpointID:         epID,
+		State:              delivery.State(m.State),
+		AttemptCount:       m.AttemptCount,
+		MaxAttempts:        m.MaxAttempts,
+		NextAttemptAt:      m.NextAttemptAt,
+		LastError:          m.LastError,
+		LastStatusCode:     m.LastStatusCode,
+		LastResponse:       m.LastResponse,
+		LastLatencyMs:      m.LastLatencyMs,
+		CompletedAt:        m.CompletedAt,
+		Test:               m.Test,
+		OriginalDeliveryID: origID,
+		ReplayCount:        m.ReplayCount,
 	}, nil
 }
 
@@ -284,41 +338,45 @@ func fromDeliveryModel(m *deliveryModel) (*delivery.Delivery, error) {
 type dlqEntryModel struct {
 	grove.BaseModel `grove:"table:relay_dlq"`
 
-	ID             string     `grove:"id,pk"`
-	DeliveryID     string     `grove:"delivery_id"`
-	EventID        string     `grove:"event_id"`
-	EndpointID     string     `grove:"endpoint_id"`
-	TenantID       string     `grove:"tenant_id"`
-	EventType      string     `grove:"event_type"`
-	URL            string     `grove:"url"`
-	Payload        []byte     `grove:"payload,type:jsonb"`
-	Error          string     `grove:"error"`
-	AttemptCount   int        `grove:"attempt_count"`
-	LastStatusCode int        `grove:"last_status_code"`
-	ReplayedAt     *time.Time `grove:"replayed_at"`
-	FailedAt       time.Time  `grove:"failed_at"`
-	CreatedAt      time.Time  `grove:"created_at"`
-	UpdatedAt      time.Time  `grove:"updated_at"`
+	ID               string     `grove:"id,pk"`
+	DeliveryID       string     `grove:"delivery_id"`
+	EventID          string     `grove:"event_id"`
+	EndpointID       string     `grove:"endpoint_id"`
+	TenantID         string     `grove:"tenant_id"`
+	EventType        string     `grove:"event_type"`
+	URL              string     `grove:"url"`
+	Payload          []byte     `grove:"payload,type:jsonb"`
+	Error            string     `grove:"error"`
+	AttemptCount     int        `grove:"attempt_count"`
+	LastStatusCode   int        `grove:"last_status_code"`
+	ReplayCount      int        `grove:"replay_count"`
+	ReplayedAt       *time.Time `grove:"replayed_at"`
+	ReplayDeliveryID string     `grove:"replay_delivery_id"`
+	FailedAt         time.Time  `grove:"failed_at"`
+	CreatedAt        time.Time  `grove:"created_at"`
+	UpdatedAt        time.Time  `grove:"updated_at"`
 }
 
 func toDLQEntryModel(e *dlq.Entry) *dlqEntryModel {
 	payload, _ := json.Marshal(e.Payload) //nolint:errcheck // best-effort serialization
 	return &dlqEntryModel{
-		ID:             e.ID.String(),
-		DeliveryID:     e.DeliveryID.String(),
-		EventID:        e.EventID.String(),
-		EndpointID:     e.EndpointID.String(),
-		TenantID:       e.TenantID,
-		EventType:      e.EventType,
-		URL:            e.URL,
-		Payload:        payload,
-		Error:          e.Error,
-		AttemptCount:   e.AttemptCount,
-		LastStatusCode: e.LastStatusCode,
-		ReplayedAt:     e.ReplayedAt,
-		FailedAt:       e.FailedAt,
-		CreatedAt:      e.CreatedAt,
-		UpdatedAt:      e.UpdatedAt,
+		ID:               e.ID.String(),
+		DeliveryID:       e.DeliveryID.String(),
+		EventID:          e.EventID.String(),
+		EndpointID:       e.EndpointID.String(),
+		TenantID:         e.TenantID,
+		EventType:        e.EventType,
+		URL:              e.URL,
+		Payload:          payload,
+		Error:            e.Error,
+		AttemptCount:     e.AttemptCount,
+		LastStatusCode:   e.LastStatusCode,
+		ReplayCount:      e.ReplayCount,
+		ReplayedAt:       e.ReplayedAt,
+		ReplayDeliveryID: optionalID(e.ReplayDeliveryID),
+		FailedAt:         e.FailedAt,
+		CreatedAt:        e.CreatedAt,
+		UpdatedAt:        e.UpdatedAt,
 	}
 }
 
@@ -339,24 +397,102 @@ func fromDLQEntryModel(m *dlqEntryModel) (*dlq.Entry, error) {
 	if err != nil {
 		return nil, fmt.Errorf("parse endpoint ID %q: %w", m.EndpointID, err)
 	}
+	replayID, err := parseOptionalID(m.ReplayDeliveryID, id.ParseDeliveryID)
+	if err != nil {
+		return nil, fmt.Errorf("parse replay delivery ID %q: %w", m.ReplayDeliveryID, err)
+	}
 	var payload any = json.RawMessage(m.Payload)
 	return &dlq.Entry{
 		Entity: entity.Entity{
 			CreatedAt: m.CreatedAt,
 			UpdatedAt: m.UpdatedAt,
 		},
-		ID:             dlqID,
-		DeliveryID:     delID,
-		EventID:        evtID,
-		EndpointID:     epID,
-		TenantID:       m.TenantID,
-		EventType:      m.EventType,
-		URL:            m.URL,
-		Payload:        payload,
-		Error:          m.Error,
-		AttemptCount:   m.AttemptCount,
-		LastStatusCode: m.LastStatusCode,
-		ReplayedAt:     m.ReplayedAt,
-		FailedAt:       m.FailedAt,
+		ID:               dlqID,
+		DeliveryID:       delID,
+		EventID:          evtID,
+		EndpointID:       epID,
+		TenantID:         m.TenantID,
+		EventType:        m.EventType,
+		URL:              m.URL,
+		Payload:          payload,
+		Error:            m.Error,
+		AttemptCount:     m.AttemptCount,
+		LastStatusCode:   m.LastStatusCode,
+		ReplayCount:      m.ReplayCount,
+		ReplayedAt:       m.ReplayedAt,
+		ReplayDeliveryID: replayID,
+		FailedAt:         m.FailedAt,
+	}, nil
+}
+
+// --- API key models ---
+
+type apiKeyModel struct {
+	grove.BaseModel `grove:"table:relay_api_keys"`
+
+	ID        string     `grove:"id,pk"`
+	Name      string     `grove:"name"`
+	Prefix    string     `grove:"prefix"`
+	Hash      string     `grove:"hash,unique"`
+	TenantID  string     `grove:"tenant_id"`
+	Scopes    []string   `grove:"scopes,array"`
+	ExpiresAt *time.Time `grove:"expires_at"`
+	CreatedAt time.Time  `grove:"created_at"`
+	UpdatedAt time.Time  `grove:"updated_at"`
+}
+
+func toAPIKeyModel(k *auth.APIKey) *apiKeyModel {
+	return &apiKeyModel{
+		ID:        k.ID.String(),
+		Name:      k.Name,
+		Prefix:    k.Prefix,
+		Hash:      k.Hash,
+		TenantID:  k.TenantID,
+		Scopes:    k.Scopes,
+		ExpiresAt: k.ExpiresAt,
+		CreatedAt: k.CreatedAt,
+		UpdatedAt: k.UpdatedAt,
+	}
+}
+
+func fromAPIKeyModel(m *apiKeyModel) (*auth.APIKey, error) {
+	keyID, err := id.ParseAPIKeyID(m.ID)
+	if err != nil {
+		return nil, fmt.Errorf("parse API key ID %q: %w", m.ID, err)
+	}
+	return &auth.APIKey{
+		Entity: entity.Entity{
+			CreatedAt: m.CreatedAt,
+			UpdatedAt: m.UpdatedAt,
+		},
+		ID:        keyID,
+		Name:      m.Name,
+		Prefix:    m.Prefix,
+		Hash:      m.Hash,
+		TenantID:  m.TenantID,
+		Scopes:    m.Scopes,
+		ExpiresAt: m.ExpiresAt,
 	}, nil
 }
+
+// --- Optional IDs ---
+
+// optionalID returns the stored form of an optional ID: empty for nil.
+func optionalID(i *id.ID) string {
+	if i == nil {
+		return ""
+	}
+	return i.String()
+}
+
+// parseOptionalID parses an optional ID stored by optionalID.
+func parseOptionalID(s string, parse func(string) (id.ID, error)) (*id.ID, error) {
+	if s == "" {
+		return nil, nil //nolint:nilnil // no ID stored
+	}
+	i, err := parse(s)
+	if err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/xraph/grove/migrate"
 
 	relay "github.com/xraph/relay"
+	"github.com/xraph/relay/auth"
 	"github.com/xraph/relay/catalog"
 	"github.com/xraph/relay/delivery"
 	"github.com/xraph/relay/dlq"
@@ -28,14 +29,18 @@ var _ relaystore.Store = (*Store)(nil)
 type Store struct {
 	db *grove.DB
 	pg *pgdriver.PgDB
+
+	index *endpoint.Index // subscription index backing Resolve
 }
 
 // New creates a new PostgreSQL store backed by Grove ORM.
 func New(db *grove.DB) *Store {
-	return &Store{
+	s := &Store{
 		db: db,
 		pg: pgdriver.Unwrap(db),
 	}
+	s.index = endpoint.NewIndex(s.tenantEndpoints, endpoint.DefaultIndexTTL)
+	return s
 }
 
 // DB returns the underlying grove database for direct access.
@@ -76,10 +81,9 @@ func (s *Store) RegisterType(ctx context.Context, et *catalog.EventType) error {
 		Set("schema_version = EXCLUDED.schema_version").
 		Set("version = EXCLUDED.version").
 		Set("example = EXCLUDED.example").
+		Set("versions = EXCLUDED.versions").
 		Set("scope_app_id = EXCLUDED.scope_app_id").
 		Set("metadata = EXCLUDED.metadata").
-		Set("is_deprecated = false").
-		Set("deprecated_at = NULL").
 		Set("updated_at = EXCLUDED.updated_at").
 		Exec(ctx)
 	return err
@@ -116,14 +120,8 @@ func (s *Store) GetTypeByID(ctx context.Context, etID id.ID) (*catalog.EventType
 func (s *Store) ListTypes(ctx context.Context, opts catalog.ListOpts) ([]*catalog.EventType, error) {
 	var models []eventTypeModel
 	q := s.pg.NewSelect(&models)
-
-	argIdx := 0
-	if opts.Group != "" {
-		argIdx++
-		q = q.Where(fmt.Sprintf("group_name = $%d", argIdx), opts.Group)
-	}
-	if !opts.IncludeDeprecated {
-		q = q.Where("is_deprecated = false")
+	for _, c := range typeFilter(opts).after(opts.Cursor, false) {
+		q = q.Where(c.expr, c.args...)
 	}
 	if opts.Limit > 0 {
 		q = q.Limit(opts.Limit)
@@ -131,7 +129,7 @@ func (s *Store) ListTypes(ctx context.Context, opts catalog.ListOpts) ([]*catalo
 	if opts.Offset > 0 {
 		q = q.Offset(opts.Offset)
 	}
-	q = q.OrderExpr("created_at ASC")
+	q = q.OrderExpr("id ASC")
 
 	if err := q.Scan(ctx); err != nil {
 		return nil, err
@@ -148,14 +146,80 @@ func (s *Store) ListTypes(ctx context.Context, opts catalog.ListOpts) ([]*catalo
 	return result, nil
 }
 
+func (s *Store) CountTypes(ctx context.Context, opts catalog.ListOpts) (int64, error) {
+	q := s.pg.NewSelect((*eventTypeModel)(nil))
+	for _, c := range typeFilter(opts) {
+		q = q.Where(c.expr, c.args...)
+	}
+	return q.Count(ctx)
+}
+
+func typeFilter(opts catalog.ListOpts) filter {
+	var f filter
+	if opts.Group != "" {
+		f.add("group_name = $%d", opts.Group)
+	}
+	if opts.ScopeAppID != "" {
+		f.add("scope_app_id = $%d", opts.ScopeAppID)
+	}
+	if !opts.IncludeDeprecated {
+		f.raw("is_deprecated = false")
+	}
+	return f
+}
+
 func (s *Store) DeleteType(ctx context.Context, name string) error {
 	now := time.Now().UTC()
 	res, err := s.pg.NewUpdate((*eventTypeModel)(nil)).
 		Set("is_deprecated = true").
-		Set("deprecated_at = $1", now).
-		Set("updated_at = $2", now).
-		Where("name = $3", name).
-		Where("is_deprecated = false").
+		Set("deprecated_at = COALESCE(deprecated_at, $1)", now).
+		Set("sunset_at = $2", now).
+		Set("updated_at = $3", now).
+		Where("name = $4", name).
+		Where("(is_deprecated = false OR sunset_at > $5)", now).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return relay.ErrEventTypeNotFound
+	}
+	return nil
+}
+
+func (s *Store) DeprecateType(ctx context.Context, name string, sunsetAt time.Time) error {
+	now := time.Now().UTC()
+	res, err := s.pg.NewUpdate((*eventTypeModel)(nil)).
+		Set("is_deprecated = true").
+		Set("deprecated_at = COALESCE(deprecated_at, $1)", now).
+		Set("sunset_at = $2", sunsetAt).
+		Set("updated_at = $3", now).
+		Where("name = $4", name).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return relay.ErrEventTypeNotFound
+	}
+	return nil
+}
+
+func (s *Store) UndeprecateType(ctx context.Context, name string) error {
+	res, err := s.pg.NewUpdate((*eventTypeModel)(nil)).
+		Set("is_deprecated = false").
+		Set("deprecated_at = NULL").
+		Set("sunset_at = NULL").
+		Set("updated_at = $1", time.Now().UTC()).
+		Where("name = $2", name).
 		Exec(ctx)
 	if err != nil {
 		return err
@@ -195,8 +259,11 @@ func (s *Store) MatchTypes(ctx context.Context, pattern string) ([]*catalog.Even
 
 func (s *Store) CreateEndpoint(ctx context.Context, ep *endpoint.Endpoint) error {
 	m := toEndpointModel(ep)
-	_, err := s.pg.NewInsert(m).Exec(ctx)
-	return err
+	if _, err := s.pg.NewInsert(m).Exec(ctx); err != nil {
+		return err
+	}
+	s.index.Invalidate(ep.TenantID)
+	return nil
 }
 
 func (s *Store) GetEndpoint(ctx context.Context, epID id.ID) (*endpoint.Endpoint, error) {
@@ -229,6 +296,8 @@ func (s *Store) UpdateEndpoint(ctx context.Context, ep *endpoint.Endpoint) error
 	if rows == 0 {
 		return relay.ErrEndpointNotFound
 	}
+	s.index.InvalidateEndpoint(m.ID)
+	s.index.Invalidate(ep.TenantID)
 	return nil
 }
 
@@ -246,19 +315,23 @@ func (s *Store) DeleteEndpoint(ctx context.Context, epID id.ID) error {
 	if rows == 0 {
 		return relay.ErrEndpointNotFound
 	}
+	s.index.InvalidateEndpoint(epID.String())
 	return nil
 }
 
 func (s *Store) ListEndpoints(ctx context.Context, tenantID string, opts endpoint.ListOpts) ([]*endpoint.Endpoint, error) {
 	var models []endpointModel
-	q := s.pg.NewSelect(&models).Where("tenant_id = $1", tenantID)
+	q := s.pg.NewSelect(&models)
+	for _, c := range endpointFilter(tenantID, opts).after(opts.Cursor, false) {
+		q = q.Where(c.expr, c.args...)
+	}
 	if opts.Limit > 0 {
 		q = q.Limit(opts.Limit)
 	}
 	if opts.Offset > 0 {
 		q = q.Offset(opts.Offset)
 	}
-	q = q.OrderExpr("created_at ASC")
+	q = q.OrderExpr("id ASC")
 
 	if err := q.Scan(ctx); err != nil {
 		return nil, err
@@ -275,11 +348,33 @@ func (s *Store) ListEndpoints(ctx context.Context, tenantID string, opts endpoin
 	return result, nil
 }
 
-func (s *Store) Resolve(ctx context.Context, tenantID, eventType string) ([]*endpoint.Endpoint, error) {
+func (s *Store) CountEndpoints(ctx context.Context, tenantID string, opts endpoint.ListOpts) (int64, error) {
+	q := s.pg.NewSelect((*endpointModel)(nil))
+	for _, c := range endpointFilter(tenantID, opts) {
+		q = q.Where(c.expr, c.args...)
+	}
+	return q.Count(ctx)
+}
+
+func endpointFilter(tenantID string, opts endpoint.ListOpts) filter {
+	var f filter
+	f.add("tenant_id = $%d", tenantID)
+	if opts.Enabled != nil {
+		f.add("enabled = $%d", *opts.Enabled)
+	}
+	if opts.ScopeAppID != "" {
+		f.add("scope_app_id = $%d", opts.ScopeAppID)
+	}
+	if opts.ScopeOrgID != "" {
+		f.add("scope_org_id = $%d", opts.ScopeOrgID)
+	}
+	return f
+}
+
+func (s *Store) ListSubscribers(ctx context.Context, eventType string) ([]*endpoint.Endpoint, error) {
 	var models []endpointModel
 	if err := s.pg.NewSelect(&models).
-		Where("tenant_id = $1", tenantID).
-		Where("enabled = true").
+		OrderExpr("created_at ASC").
 		Scan(ctx); err != nil {
 		return nil, err
 	}
@@ -300,10 +395,58 @@ func (s *Store) Resolve(ctx context.Context, tenantID, eventType string) ([]*end
 	return result, nil
 }
 
+func (s *Store) ListAutoDisabled(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	var models []endpointModel
+	if err := s.pg.NewSelect(&models).
+		Where("enabled = FALSE").
+		Where("disabled_reason != ''").
+		OrderExpr("created_at ASC").
+		Scan(ctx); err != nil {
+		return nil, err
+	}
+
+	result := make([]*endpoint.Endpoint, 0, len(models))
+	for i := range models {
+		ep, err := fromEndpointModel(&models[i])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, ep)
+	}
+	return result, nil
+}
+
+func (s *Store) Resolve(ctx context.Context, tenantID, eventType string) ([]*endpoint.Endpoint, error) {
+	return s.index.Resolve(ctx, tenantID, eventType)
+}
+
+// tenantEndpoints loads every endpoint of a tenant for the subscription index.
+func (s *Store) tenantEndpoints(ctx context.Context, tenantID string) ([]*endpoint.Endpoint, error) {
+	var models []endpointModel
+	if err := s.pg.NewSelect(&models).
+		Where("tenant_id = $1", tenantID).
+		OrderExpr("created_at ASC").
+		Scan(ctx); err != nil {
+		return nil, err
+	}
+
+	result := make([]*endpoint.Endpoint, len(models))
+	for i := range models {
+		ep, err := fromEndpointModel(&models[i])
+		if err != nil {
+			return nil, err
+		}
+		result[i] = ep
+	}
+	return result, nil
+}
+
 func (s *Store) SetEnabled(ctx context.Context, epID id.ID, enabled bool) error {
 	now := time.Now().UTC()
 	res, err := s.pg.NewUpdate((*endpointModel)(nil)).
 		Set("enabled = $1", enabled).
+		Set("disabled_reason = ''").
+		Set("disabled_at = NULL").
 		Set("updated_at = $2", now).
 		Where("id = $3", epID.String()).
 		Exec(ctx)
@@ -317,6 +460,29 @@ func (s *Store) SetEnabled(ctx context.Context, epID id.ID, enabled bool) error
 	if rows == 0 {
 		return relay.ErrEndpointNotFound
 	}
+	s.index.InvalidateEndpoint(epID.String())
+	return nil
+}
+
+func (s *Store) DisableEndpoint(ctx context.Context, epID id.ID, reason string, at time.Time) error {
+	res, err := s.pg.NewUpdate((*endpointModel)(nil)).
+		Set("enabled = false").
+		Set("disabled_reason = $1", reason).
+		Set("disabled_at = $2", at).
+		Set("updated_at = $3", time.Now().UTC()).
+		Where("id = $4", epID.String()).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return relay.ErrEndpointNotFound
+	}
+	s.index.InvalidateEndpoint(epID.String())
 	return nil
 }
 
@@ -361,22 +527,25 @@ func (s *Store) GetEvent(ctx context.Context, evtID id.ID) (*event.Event, error)
 	return fromEventModel(m)
 }
 
+func (s *Store) GetEventByIdempotencyKey(ctx context.Context, key string) (*event.Event, error) {
+	m := new(eventModel)
+	err := s.pg.NewSelect(m).
+		Where("idempotency_key = $1", key).
+		Scan(ctx)
+	if err != nil {
+		if isNoRows(err) {
+			return nil, relay.ErrEventNotFound
+		}
+		return nil, err
+	}
+	return fromEventModel(m)
+}
+
 func (s *Store) ListEvents(ctx context.Context, opts event.ListOpts) ([]*event.Event, error) {
 	var models []eventModel
 	q := s.pg.NewSelect(&models)
-
-	argIdx := 0
-	if opts.Type != "" {
-		argIdx++
-		q = q.Where(fmt.Sprintf("type = $%d", argIdx), opts.Type)
-	}
-	if opts.From != nil {
-		argIdx++
-		q = q.Where(fmt.Sprintf("created_at >= $%d", argIdx), *opts.From)
-	}
-	if opts.To != nil {
-		argIdx++
-		q = q.Where(fmt.Sprintf("created_at <= $%d", argIdx), *opts.To)
+	for _, c := range eventFilter(opts).after(opts.Cursor, !opts.Ascending) {
+		q = q.Where(c.expr, c.args...)
 	}
 	if opts.Limit > 0 {
 		q = q.Limit(opts.Limit)
@@ -384,7 +553,7 @@ func (s *Store) ListEvents(ctx context.Context, opts event.ListOpts) ([]*event.E
 	if opts.Offset > 0 {
 		q = q.Offset(opts.Offset)
 	}
-	q = q.OrderExpr("created_at DESC")
+	q = q.OrderExpr(orderByID(!opts.Ascending))
 
 	if err := q.Scan(ctx); err != nil {
 		return nil, err
@@ -398,18 +567,22 @@ func (s *Store) ListEvents(ctx context.Context, opts event.ListOpts) ([]*event.E
 		}
 		result[i] = evt
 	}
-
 	return result, nil
 }
 
+func (s *Store) CountEvents(ctx context.Context, opts event.ListOpts) (int64, error) {
+	q := s.pg.NewSelect((*eventModel)(nil))
+	for _, c := range eventFilter(opts) {
+		q = q.Where(c.expr, c.args...)
+	}
+	return q.Count(ctx)
+}
+
 func (s *Store) ListEventsByTenant(ctx context.Context, tenantID string, opts event.ListOpts) ([]*event.Event, error) {
 	var models []eventModel
-	q := s.pg.NewSelect(&models).Where("tenant_id = $1", tenantID)
-
-	argIdx := 1
-	if opts.Type != "" {
-		argIdx++
-		q = q.Where(fmt.Sprintf("type = $%d", argIdx), opts.Type)
+	q := s.pg.NewSelect(&models)
+	for _, c := range eventFilter(opts, tenantID).after(opts.Cursor, !opts.Ascending) {
+		q = q.Where(c.expr, c.args...)
 	}
 	if opts.Limit > 0 {
 		q = q.Limit(opts.Limit)
@@ -417,7 +590,7 @@ func (s *Store) ListEventsByTenant(ctx context.Context, tenantID string, opts ev
 	if opts.Offset > 0 {
 		q = q.Offset(opts.Offset)
 	}
-	q = q.OrderExpr("created_at DESC")
+	q = q.OrderExpr(orderByID(!opts.Ascending))
 
 	if err := q.Scan(ctx); err != nil {
 		return nil, err
@@ -431,10 +604,45 @@ func (s *Store) ListEventsByTenant(ctx context.Context, tenantID string, opts ev
 		}
 		result[i] = evt
 	}
-
 	return result, nil
 }
 
+func (s *Store) CountEventsByTenant(ctx context.Context, tenantID string, opts event.ListOpts) (int64, error) {
+	q := s.pg.NewSelect((*eventModel)(nil))
+	for _, c := range eventFilter(opts, tenantID) {
+		q = q.Where(c.expr, c.args...)
+	}
+	return q.Count(ctx)
+}
+
+// eventFilter returns the conditions of an event list, of one tenant if
+// tenantID is given.
+func eventFilter(opts event.ListOpts, tenantID ...string) filter {
+	var f filter
+	for _, t := range tenantID {
+		f.add("tenant_id = $%d", t)
+	}
+	if opts.Type != "" {
+		f.add("type = $%d", opts.Type)
+	}
+	if opts.From != nil {
+		f.add("created_at >= $%d", *opts.From)
+	}
+	if opts.To != nil {
+		f.add("created_at <= $%d", *opts.To)
+	}
+	if opts.ScopeAppID != "" {
+		f.add("scope_app_id = $%d", opts.ScopeAppID)
+	}
+	if opts.ScopeOrgID != "" {
+		f.add("scope_org_id = $%d", opts.ScopeOrgID)
+	}
+	if opts.ExcludeTest {
+		f.raw("test = false")
+	}
+	return f
+}
+
 // ==================== Delivery Store ====================
 
 func (s *Store) Enqueue(ctx context.Context, d *delivery.Delivery) error {
@@ -513,12 +721,63 @@ func (s *Store) GetDelivery(ctx context.Context, delID id.ID) (*delivery.Deliver
 	return fromDeliveryModel(m)
 }
 
-func (s *Store) ListByEndpoint(ctx context.Context, epID id.ID, opts delivery.ListOpts) ([]*delivery.Delivery, error) {
-	var models []deliveryModel
-	q := s.pg.NewSelect(&models).Where("endpoint_id = $1", epID.String())
+func (s *Store) RetryDelivery(ctx context.Context, delID id.ID) error {
+	t := time.Now().UTC()
+	res, err := s.pg.NewUpdate((*deliveryModel)(nil)).
+		Set("next_attempt_at = $1", t).
+		Set("updated_at = $2", t).
+		Where("id = $3", delID.String()).
+		Where("state = 'pending'").
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return s.notPending(ctx, delID)
+	}
+	return nil
+}
 
-	if opts.State != nil {
-		q = q.Where("state = $2", string(*opts.State))
+func (s *Store) CancelDelivery(ctx context.Context, delID id.ID) error {
+	t := time.Now().UTC()
+	res, err := s.pg.NewUpdate((*deliveryModel)(nil)).
+		Set("state = $1", string(delivery.StateCancelled)).
+		Set("completed_at = $2", t).
+		Set("updated_at = $3", t).
+		Where("id = $4", delID.String()).
+		Where("state = 'pending'").
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return s.notPending(ctx, delID)
+	}
+	return nil
+}
+
+// notPending tells why a conditional update of a pending delivery changed
+// no rows: the delivery is missing or no longer pending.
+func (s *Store) notPending(ctx context.Context, delID id.ID) error {
+	if _, err := s.GetDelivery(ctx, delID); err != nil {
+		return err
+	}
+	return relay.ErrDeliveryNotPending
+}
+
+func (s *Store) ListDeliveries(ctx context.Context, opts delivery.ListOpts) ([]*delivery.Delivery, error) {
+	var models []deliveryModel
+	q := s.pg.NewSelect(&models)
+	for _, c := range deliveryFilter(opts).after(opts.Cursor, true) {
+		q = q.Where(c.expr, c.args...)
 	}
 	if opts.Limit > 0 {
 		q = q.Limit(opts.Limit)
@@ -526,7 +785,7 @@ func (s *Store) ListByEndpoint(ctx context.Context, epID id.ID, opts delivery.Li
 	if opts.Offset > 0 {
 		q = q.Offset(opts.Offset)
 	}
-	q = q.OrderExpr("created_at DESC")
+	q = q.OrderExpr("id DESC")
 
 	if err := q.Scan(ctx); err != nil {
 		return nil, err
@@ -540,10 +799,50 @@ func (s *Store) ListByEndpoint(ctx context.Context, epID id.ID, opts delivery.Li
 		}
 		result[i] = d
 	}
-
 	return result, nil
 }
 
+func (s *Store) CountDeliveries(ctx context.Context, opts delivery.ListOpts) (int64, error) {
+	q := s.pg.NewSelect((*deliveryModel)(nil))
+	for _, c := range deliveryFilter(opts) {
+		q = q.Where(c.expr, c.args...)
+	}
+	return q.Count(ctx)
+}
+
+func (s *Store) ListByEndpoint(ctx context.Context, epID id.ID, opts delivery.ListOpts) ([]*delivery.Delivery, error) {
+	opts.EndpointID = &epID
+	return s.ListDeliveries(ctx, opts)
+}
+
+func (s *Store) CountByEndpoint(ctx context.Context, epID id.ID, opts delivery.ListOpts) (int64, error) {
+	opts.EndpointID = &epID
+	return s.CountDeliveries(ctx, opts)
+}
+
+func deliveryFilter(opts delivery.ListOpts) filter {
+	var f filter
+	if opts.EndpointID != nil {
+		f.add("endpoint_id = $%d", opts.EndpointID.String())
+	}
+	if opts.EventID != nil {
+		f.add("event_id = $%d", opts.EventID.String())
+	}
+	if opts.TenantID != "" {
+		f.add("endpoint_id IN (SELECT id FROM relay_endpoints WHERE tenant_id = $%d)", opts.TenantID)
+	}
+	if opts.State != nil {
+		f.add("state = $%d", string(*opts.State))
+	}
+	if opts.From != nil {
+		f.add("created_at >= $%d", *opts.From)
+	}
+	if opts.To != nil {
+		f.add("created_at <= $%d", *opts.To)
+	}
+	return f
+}
+
 func (s *Store) ListByEvent(ctx context.Context, evtID id.ID) ([]*delivery.Delivery, error) {
 	var models []deliveryModel
 	if err := s.pg.NewSelect(&models).
@@ -582,23 +881,8 @@ func (s *Store) Push(ctx context.Context, entry *dlq.Entry) error {
 func (s *Store) ListDLQ(ctx context.Context, opts dlq.ListOpts) ([]*dlq.Entry, error) {
 	var models []dlqEntryModel
 	q := s.pg.NewSelect(&models)
-
-	argIdx := 0
-	if opts.TenantID != "" {
-		argIdx++
-		q = q.Where(fmt.Sprintf("tenant_id = $%d", argIdx), opts.TenantID)
-	}
-	if opts.EndpointID != nil {
-		argIdx++
-		q = q.Where(fmt.Sprintf("endpoint_id = $%d", argIdx), opts.EndpointID.String())
-	}
-	if opts.From != nil {
-		argIdx++
-		q = q.Where(fmt.Sprintf("failed_at >= $%d", argIdx), *opts.From)
-	}
-	if opts.To != nil {
-		argIdx++
-		q = q.Where(fmt.Sprintf("failed_at <= $%d", argIdx), *opts.To)
+	for _, c := range dlqFilter(opts).after(opts.Cursor, true) {
+		q = q.Where(c.expr, c.args...)
 	}
 	if opts.Limit > 0 {
 		q = q.Limit(opts.Limit)
@@ -606,7 +890,7 @@ func (s *Store) ListDLQ(ctx context.Context, opts dlq.ListOpts) ([]*dlq.Entry, e
 	if opts.Offset > 0 {
 		q = q.Offset(opts.Offset)
 	}
-	q = q.OrderExpr("failed_at DESC")
+	q = q.OrderExpr("id DESC")
 
 	if err := q.Scan(ctx); err != nil {
 		return nil, err
@@ -623,6 +907,35 @@ func (s *Store) ListDLQ(ctx context.Context, opts dlq.ListOpts) ([]*dlq.Entry, e
 	return result, nil
 }
 
+func dlqFilter(opts dlq.ListOpts) filter {
+	var f filter
+	if opts.TenantID != "" {
+		f.add("tenant_id = $%d", opts.TenantID)
+	}
+	if opts.EndpointID != nil {
+		f.add("endpoint_id = $%d", opts.EndpointID.String())
+	}
+	if opts.From != nil {
+		f.add("failed_at >= $%d", *opts.From)
+	}
+	if opts.To != nil {
+		f.add("failed_at <= $%d", *opts.To)
+	}
+	if opts.EventType != "" {
+		f.add("event_type = $%d", opts.EventType)
+	}
+	if opts.StatusCode != 0 {
+		f.add("last_status_code = $%d", opts.StatusCode)
+	}
+	if opts.ErrorContains != "" {
+		f.add("strpos(error, $%d) > 0", opts.ErrorContains)
+	}
+	if opts.Unreplayed {
+		f.raw("replayed_at IS NULL")
+	}
+	return f
+}
+
 func (s *Store) GetDLQ(ctx context.Context, dlqID id.ID) (*dlq.Entry, error) {
 	m := new(dlqEntryModel)
 	err := s.pg.NewSelect(m).
@@ -637,73 +950,58 @@ func (s *Store) GetDLQ(ctx context.Context, dlqID id.ID) (*dlq.Entry, error) {
 	return fromDLQEntryModel(m)
 }
 
-func (s *Store) Replay(ctx context.Context, dlqID id.ID) error {
-	// Get the DLQ entry.
-	entry, err := s.GetDLQ(ctx, dlqID)
+func (s *Store) Replay(ctx context.Context, dlqID id.ID, d *delivery.Delivery) error {
+	// Claim the entry and enqueue in one transaction, so concurrent replays
+	// enqueue one delivery and a failure leaves the entry replayable.
+	tx, err := s.pg.BeginTx(ctx, nil)
 	if err != nil {
-		return err
+		return fmt.Errorf("relay/postgres: begin replay tx: %w", err)
 	}
 
-	// Re-enqueue a new delivery.
-	d := &delivery.Delivery{
-		ID:            id.NewDeliveryID(),
-		EventID:       entry.EventID,
-		EndpointID:    entry.EndpointID,
-		State:         delivery.StatePending,
-		NextAttemptAt: time.Now().UTC(),
+	claimed, err := claimDLQEntry(ctx, tx, dlqID, d.ID)
+	if err != nil {
+		_ = tx.Rollback() //nolint:errcheck // the claim error is the one worth reporting
+		return err
+	}
+	if !claimed {
+		_ = tx.Rollback() //nolint:errcheck // nothing was written
+		if _, err := s.GetDLQ(ctx, dlqID); err != nil {
+			return err
+		}
+		return dlq.ErrAlreadyReplayed
 	}
-	d.CreatedAt = time.Now().UTC()
-	d.UpdatedAt = d.CreatedAt
 
-	if enqueueErr := s.Enqueue(ctx, d); enqueueErr != nil {
-		return enqueueErr
+	if _, err := tx.NewInsert(toDeliveryModel(d)).Exec(ctx); err != nil {
+		_ = tx.Rollback() //nolint:errcheck // the insert error is the one worth reporting
+		return err
 	}
 
-	// Remove from DLQ.
-	_, err = s.pg.NewDelete((*dlqEntryModel)(nil)).
-		Where("id = $1", dlqID.String()).
-		Exec(ctx)
-	return err
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("relay/postgres: commit replay tx: %w", err)
+	}
+	s.notifyWake(ctx)
+	return nil
 }
 
-func (s *Store) ReplayBulk(ctx context.Context, from, to time.Time) (int64, error) {
-	var models []dlqEntryModel
-	if err := s.pg.NewSelect(&models).
-		Where("failed_at >= $1", from).
-		Where("failed_at <= $2", to).
-		Scan(ctx); err != nil {
-		return 0, err
+// claimDLQEntry marks an entry replayed as replayID, reporting false if it
+// is unknown or was already replayed.
+func claimDLQEntry(ctx context.Context, tx *pgdriver.PgTx, dlqID, replayID id.ID) (bool, error) {
+	t := time.Now().UTC()
+	res, err := tx.NewUpdate((*dlqEntryModel)(nil)).
+		Set("replayed_at = $1", t).
+		Set("replay_delivery_id = $2", replayID.String()).
+		Set("updated_at = $3", t).
+		Where("id = $4", dlqID.String()).
+		Where("replayed_at IS NULL").
+		Exec(ctx)
+	if err != nil {
+		return false, err
 	}
-
-	var count int64
-	for i := range models {
-		entry, err := fromDLQEntryModel(&models[i])
-		if err != nil {
-			return count, err
-		}
-		d := &delivery.Delivery{
-			ID:            id.NewDeliveryID(),
-			EventID:       entry.EventID,
-			EndpointID:    entry.EndpointID,
-			State:         delivery.StatePending,
-			NextAttemptAt: time.Now().UTC(),
-		}
-		d.CreatedAt = time.Now().UTC()
-		d.UpdatedAt = d.CreatedAt
-
-		if err := s.Enqueue(ctx, d); err != nil {
-			return count, err
-		}
-
-		if _, err := s.pg.NewDelete((*dlqEntryModel)(nil)).
-			Where("id = $1", models[i].ID).
-			Exec(ctx); err != nil {
-			return count, err
-		}
-		count++
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
 	}
-
-	return count, nil
+	return rows > 0, nil
 }
 
 func (s *Store) Purge(ctx context.Context, before time.Time) (int64, error) {
@@ -722,10 +1020,104 @@ func (s *Store) Purge(ctx context.Context, before time.Time) (int64, error) {
 	return rows, nil
 }
 
-func (s *Store) CountDLQ(ctx context.Context) (int64, error) {
-	count, err := s.pg.NewSelect((*dlqEntryModel)(nil)).
-		Count(ctx)
-	return count, err
+func (s *Store) CountDLQ(ctx context.Context, opts dlq.ListOpts) (int64, error) {
+	q := s.pg.NewSelect((*dlqEntryModel)(nil))
+	for _, c := range dlqFilter(opts) {
+		q = q.Where(c.expr, c.args...)
+	}
+	return q.Count(ctx)
+}
+
+// ==================== API Key Store ====================
+
+func (s *Store) CreateAPIKey(ctx context.Context, key *auth.APIKey) error {
+	m := toAPIKeyModel(key)
+	_, err := s.pg.NewInsert(m).Exec(ctx)
+	return err
+}
+
+func (s *Store) GetAPIKey(ctx context.Context, keyID id.ID) (*auth.APIKey, error) {
+	return s.getAPIKey(ctx, "id = $1", keyID.String())
+}
+
+func (s *Store) GetAPIKeyByHash(ctx context.Context, hash string) (*auth.APIKey, error) {
+	return s.getAPIKey(ctx, "hash = $1", hash)
+}
+
+func (s *Store) getAPIKey(ctx context.Context, where string, arg any) (*auth.APIKey, error) {
+	m := new(apiKeyModel)
+	err := s.pg.NewSelect(m).
+		Where(where, arg).
+		Scan(ctx)
+	if err != nil {
+		if isNoRows(err) {
+			return nil, relay.ErrAPIKeyNotFound
+		}
+		return nil, err
+	}
+	return fromAPIKeyModel(m)
+}
+
+func (s *Store) ListAPIKeys(ctx context.Context, opts auth.ListOpts) ([]*auth.APIKey, error) {
+	var models []apiKeyModel
+	q := s.pg.NewSelect(&models)
+	for _, c := range apiKeyFilter(opts).after(opts.Cursor, false) {
+		q = q.Where(c.expr, c.args...)
+	}
+	if opts.Limit > 0 {
+		q = q.Limit(opts.Limit)
+	}
+	if opts.Offset > 0 {
+		q = q.Offset(opts.Offset)
+	}
+	q = q.OrderExpr("id ASC")
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, err
+	}
+
+	result := make([]*auth.APIKey, len(models))
+	for i := range models {
+		key, err := fromAPIKeyModel(&models[i])
+		if err != nil {
+			return nil, err
+		}
+		result[i] = key
+	}
+	return result, nil
+}
+
+func (s *Store) CountAPIKeys(ctx context.Context, opts auth.ListOpts) (int64, error) {
+	q := s.pg.NewSelect((*apiKeyModel)(nil))
+	for _, c := range apiKeyFilter(opts) {
+		q = q.Where(c.expr, c.args...)
+	}
+	return q.Count(ctx)
+}
+
+func apiKeyFilter(opts auth.ListOpts) filter {
+	var f filter
+	if opts.TenantID != "" {
+		f.add("tenant_id = $%d", opts.TenantID)
+	}
+	return f
+}
+
+func (s *Store) DeleteAPIKey(ctx context.Context, keyID id.ID) error {
+	res, err := s.pg.NewDelete((*apiKeyModel)(nil)).
+		Where("id = $1", keyID.String()).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return relay.ErrAPIKeyNotFound
+	}
+	return nil
 }
 
 // isNoRows checks for the standard sql.ErrNoRows sentinel.
@@ -0,0 +1,177 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	relay "github.com/xraph/relay"
+	"github.com/xraph/relay/auth"
+	"github.com/xraph/relay/id"
+	"github.com/xraph/relay/internal/entity"
+)
+
+// apiKeyModel is the JSON representation stored in Redis.
+type apiKeyModel struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Prefix    string     `json:"prefix"`
+	Hash      string     `json:"hash"`
+	TenantID  string     `json:"tenant_id"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+func toAPIKeyModel(k *auth.APIKey) *apiKeyModel {
+	return &apiKeyModel{
+		ID:        k.ID.String(),
+		Name:      k.Name,
+		Prefix:    k.Prefix,
+		Hash:      k.Hash,
+		TenantID:  k.TenantID,
+		Scopes:    k.Scopes,
+		ExpiresAt: k.ExpiresAt,
+		CreatedAt: k.CreatedAt,
+		UpdatedAt: k.UpdatedAt,
+	}
+}
+
+func fromAPIKeyModel(m *apiKeyModel) (*auth.APIKey, error) {
+	keyID, err := id.ParseAPIKeyID(m.ID)
+	if err != nil {
+		return nil, fmt.Errorf("parse API key ID %q: %w", m.ID, err)
+	}
+	return &auth.APIKey{
+		Entity: entity.Entity{
+			CreatedAt: m.CreatedAt,
+			UpdatedAt: m.UpdatedAt,
+		},
+		ID:        keyID,
+		Name:      m.Name,
+		Prefix:    m.Prefix,
+		Hash:      m.Hash,
+		TenantID:  m.TenantID,
+		Scopes:    m.Scopes,
+		ExpiresAt: m.ExpiresAt,
+	}, nil
+}
+
+func (s *Store) CreateAPIKey(ctx context.Context, key *auth.APIKey) error {
+	m := toAPIKeyModel(key)
+
+	if err := s.setEntity(ctx, entityKey(prefixAPIKey, m.ID), m); err != nil {
+		return fmt.Errorf("relay/redis: create api key: %w", err)
+	}
+
+	score := scoreFromTime(m.CreatedAt)
+	pipe := s.rdb.Pipeline()
+	pipe.Set(ctx, uniqueAPIKeyHash+m.Hash, m.ID, 0)
+	pipe.ZAdd(ctx, zAPIKeyAll, goredis.Z{Score: score, Member: m.ID})
+	if m.TenantID != "" {
+		pipe.ZAdd(ctx, zAPIKeyTenant+m.TenantID, goredis.Z{Score: score, Member: m.ID})
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("relay/redis: create api key indexes: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) GetAPIKey(ctx context.Context, keyID id.ID) (*auth.APIKey, error) {
+	var m apiKeyModel
+	if err := s.getEntity(ctx, entityKey(prefixAPIKey, keyID.String()), &m); err != nil {
+		if isNotFound(err) {
+			return nil, relay.ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("relay/redis: get api key: %w", err)
+	}
+	return fromAPIKeyModel(&m)
+}
+
+func (s *Store) GetAPIKeyByHash(ctx context.Context, hash string) (*auth.APIKey, error) {
+	keyID, err := s.rdb.Get(ctx, uniqueAPIKeyHash+hash).Result()
+	if err != nil {
+		if isRedisNil(err) {
+			return nil, relay.ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("relay/redis: get api key by hash: %w", err)
+	}
+
+	var m apiKeyModel
+	if err := s.getEntity(ctx, entityKey(prefixAPIKey, keyID), &m); err != nil {
+		if isNotFound(err) {
+			return nil, relay.ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("relay/redis: get api key by hash: %w", err)
+	}
+	return fromAPIKeyModel(&m)
+}
+
+func (s *Store) ListAPIKeys(ctx context.Context, opts auth.ListOpts) ([]*auth.APIKey, error) {
+	result, err := seekPage(ctx, s, apiKeyIndex(opts), math.Inf(-1), math.Inf(1), opts.Cursor, false, opts.Offset, opts.Limit, s.apiKeyLoader(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("relay/redis: list api keys: %w", err)
+	}
+	return result, nil
+}
+
+func (s *Store) CountAPIKeys(ctx context.Context, opts auth.ListOpts) (int64, error) {
+	count, err := seekCount(ctx, s, apiKeyIndex(opts), math.Inf(-1), math.Inf(1), false, s.apiKeyLoader(ctx))
+	if err != nil {
+		return 0, fmt.Errorf("relay/redis: count api keys: %w", err)
+	}
+	return count, nil
+}
+
+// apiKeyIndex returns the sorted set of the API keys opts may match.
+func apiKeyIndex(opts auth.ListOpts) string {
+	if opts.TenantID != "" {
+		return zAPIKeyTenant + opts.TenantID
+	}
+	return zAPIKeyAll
+}
+
+// apiKeyLoader returns a seekPage loader that fetches an API key.
+func (s *Store) apiKeyLoader(ctx context.Context) func(string) (*auth.APIKey, error) {
+	return func(keyID string) (*auth.APIKey, error) {
+		var m apiKeyModel
+		if err := s.getEntity(ctx, entityKey(prefixAPIKey, keyID), &m); err != nil {
+			if isNotFound(err) {
+				return nil, nil //nolint:nilnil // skipped: the key is gone
+			}
+			return nil, err
+		}
+		return fromAPIKeyModel(&m)
+	}
+}
+
+func (s *Store) DeleteAPIKey(ctx context.Context, keyID id.ID) error {
+	key := entityKey(prefixAPIKey, keyID.String())
+
+	var m apiKeyModel
+	if err := s.getEntity(ctx, key, &m); err != nil {
+		if isNotFound(err) {
+			return relay.ErrAPIKeyNotFound
+		}
+		return fmt.Errorf("relay/redis: delete api key get: %w", err)
+	}
+
+	if err := s.kv.Delete(ctx, key); err != nil {
+		return fmt.Errorf("relay/redis: delete api key: %w", err)
+	}
+
+	pipe := s.rdb.Pipeline()
+	pipe.Del(ctx, uniqueAPIKeyHash+m.Hash)
+	pipe.ZRem(ctx, zAPIKeyAll, m.ID)
+	if m.TenantID != "" {
+		pipe.ZRem(ctx, zAPIKeyTenant+m.TenantID, m.ID)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("relay/redis: delete api key indexes: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	goredis "github.com/redis/go-redis/v9"
+
+	"github.com/xraph/relay/delivery"
+	"github.com/xraph/relay/event"
+	"github.com/xraph/relay/id"
+	relaystore "github.com/xraph/relay/store"
+)
+
+var _ relaystore.BatchOutbox = (*Store)(nil)
+
+// CreateEventBatch writes events and their deliveries in two pipelines: one
+// claiming idempotency keys, one writing entities and indexes. Events whose
+// key was already claimed, including earlier in the same batch, are skipped
+// together with their deliveries.
+func (s *Store) CreateEventBatch(ctx context.Context, evts []*event.Event, ds []*delivery.Delivery) ([]id.ID, error) {
+	if len(evts) == 0 {
+		return nil, nil
+	}
+
+	claims := s.rdb.Pipeline()
+	claimCmds := make([]*goredis.BoolCmd, len(evts))
+	for i, evt := range evts {
+		if evt.IdempotencyKey != "" {
+			claimCmds[i] = claims.SetNX(ctx, uniqueEventIdem+evt.IdempotencyKey, evt.ID.String(), 0)
+		}
+	}
+	if claims.Len() > 0 {
+		if _, err := claims.Exec(ctx); err != nil {
+			return nil, fmt.Errorf("relay/redis: create event batch idem check: %w", err)
+		}
+	}
+
+	var duplicates []id.ID
+	skipped := make(map[id.ID]bool)
+	pipe := s.rdb.Pipeline()
+	for i, evt := range evts {
+		if claimCmds[i] != nil && !claimCmds[i].Val() {
+			duplicates = append(duplicates, evt.ID)
+			skipped[evt.ID] = true
+			continue
+		}
+
+		m := toEventModel(evt)
+		raw, err := json.Marshal(m)
+		if err != nil {
+			return nil, fmt.Errorf("relay/redis: create event batch marshal: %w", err)
+		}
+		pipe.Set(ctx, entityKey(prefixEvent, m.ID), raw, 0)
+		pipe.ZAdd(ctx, zEventAll, goredis.Z{Score: scoreFromTime(m.CreatedAt), Member: m.ID})
+		if m.TenantID != "" {
+			pipe.ZAdd(ctx, zEventTenant+m.TenantID, goredis.Z{Score: scoreFromTime(m.CreatedAt), Member: m.ID})
+		}
+	}
+
+	enqueued := 0
+	for _, d := range ds {
+		if skipped[d.EventID] {
+			continue
+		}
+
+		m := toDeliveryModel(d)
+		raw, err := json.Marshal(m)
+		if err != nil {
+			return nil, fmt.Errorf("relay/redis: create event batch marshal: %w", err)
+		}
+		pipe.Set(ctx, entityKey(prefixDelivery, m.ID), raw, 0)
+		pipe.ZAdd(ctx, zDeliveryPend, goredis.Z{Score: scoreFromTime(m.NextAttemptAt), Member: m.ID})
+		pipe.ZAdd(ctx, zDeliveryEP+m.EndpointID, goredis.Z{Score: scoreFromTime(m.CreatedAt), Member: m.ID})
+		pipe.ZAdd(ctx, zDeliveryEvt+m.EventID, goredis.Z{Score: scoreFromTime(m.CreatedAt), Member: m.ID})
+		enqueued++
+	}
+
+	if pipe.Len() > 0 {
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, fmt.Errorf("relay/redis: create event batch: %w", err)
+		}
+	}
+	if enqueued > 0 {
+		s.notifyWake(ctx)
+	}
+
+	return duplicates, nil
+}
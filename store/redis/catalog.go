@@ -3,6 +3,7 @@ package redis
 import (
 	"context"
 	"fmt"
+	"math"
 	"time"
 
 	goredis "github.com/redis/go-redis/v9"
@@ -11,6 +12,7 @@ import (
 	"github.com/xraph/relay/catalog"
 	"github.com/xraph/relay/id"
 	"github.com/xraph/relay/internal/entity"
+	"github.com/xraph/relay/scope"
 )
 
 // catalogModel is the JSON representation stored in Redis.
@@ -23,8 +25,10 @@ type catalogModel struct {
 	SchemaVersion string            `json:"schema_version"`
 	Version       string            `json:"version"`
 	Example       []byte            `json:"example,omitempty"`
+	Versions      []catalog.Version `json:"versions,omitempty"`
 	IsDeprecated  bool              `json:"is_deprecated"`
 	DeprecatedAt  *time.Time        `json:"deprecated_at,omitempty"`
+	SunsetAt      *time.Time        `json:"sunset_at,omitempty"`
 	ScopeAppID    string            `json:"scope_app_id"`
 	Metadata      map[string]string `json:"metadata,omitempty"`
 	CreatedAt     time.Time         `json:"created_at"`
@@ -41,8 +45,10 @@ func toCatalogModel(et *catalog.EventType) *catalogModel {
 		SchemaVersion: et.Definition.SchemaVersion,
 		Version:       et.Definition.Version,
 		Example:       et.Definition.Example,
+		Versions:      et.Versions,
 		IsDeprecated:  et.IsDeprecated,
 		DeprecatedAt:  et.DeprecatedAt,
+		SunsetAt:      et.SunsetAt,
 		ScopeAppID:    et.ScopeAppID,
 		Metadata:      et.Metadata,
 		CreatedAt:     et.CreatedAt,
@@ -70,8 +76,10 @@ func fromCatalogModel(m *catalogModel) (*catalog.EventType, error) {
 			Version:       m.Version,
 			Example:       m.Example,
 		},
+		Versions:     m.Versions,
 		IsDeprecated: m.IsDeprecated,
 		DeprecatedAt: m.DeprecatedAt,
+		SunsetAt:     m.SunsetAt,
 		ScopeAppID:   m.ScopeAppID,
 		Metadata:     m.Metadata,
 	}, nil
@@ -83,8 +91,9 @@ func (s *Store) RegisterType(ctx context.Context, et *catalog.EventType) error {
 
 	// Check if a type with this name already exists (upsert).
 	existingID, lookupErr := s.rdb.Get(ctx, uniqueEventTypeName+m.Name).Result()
-	if lookupErr == nil && existingID != "" && existingID != m.ID {
-		// Name already registered with a different ID — update existing.
+	if lookupErr == nil && existingID != "" {
+		// Name already registered — update the existing entry, keeping its
+		// deprecation.
 		var existing catalogModel
 		if getErr := s.getEntity(ctx, entityKey(prefixEventType, existingID), &existing); getErr == nil {
 			existing.Description = m.Description
@@ -93,10 +102,9 @@ func (s *Store) RegisterType(ctx context.Context, et *catalog.EventType) error {
 			existing.SchemaVersion = m.SchemaVersion
 			existing.Version = m.Version
 			existing.Example = m.Example
+			existing.Versions = m.Versions
 			existing.ScopeAppID = m.ScopeAppID
 			existing.Metadata = m.Metadata
-			existing.IsDeprecated = false
-			existing.DeprecatedAt = nil
 			existing.UpdatedAt = now()
 			return s.setEntity(ctx, entityKey(prefixEventType, existingID), &existing)
 		}
@@ -150,66 +158,133 @@ func (s *Store) GetTypeByID(ctx context.Context, etID id.ID) (*catalog.EventType
 }
 
 func (s *Store) ListTypes(ctx context.Context, opts catalog.ListOpts) ([]*catalog.EventType, error) {
-	zKey := zEventTypeAll
-	if opts.Group != "" {
-		zKey = zEventTypeGroup + opts.Group
+	result, err := seekPage(ctx, s, typeIndex(opts), math.Inf(-1), math.Inf(1), opts.Cursor, false, opts.Offset, opts.Limit, s.typeLoader(ctx, opts))
+	if err != nil {
+		return nil, fmt.Errorf("relay/redis: list types: %w", err)
 	}
+	return result, nil
+}
 
-	ids, err := s.rdb.ZRange(ctx, zKey, 0, -1).Result()
+func (s *Store) CountTypes(ctx context.Context, opts catalog.ListOpts) (int64, error) {
+	filtered := !opts.IncludeDeprecated || opts.ScopeAppID != ""
+	count, err := seekCount(ctx, s, typeIndex(opts), math.Inf(-1), math.Inf(1), filtered, s.typeLoader(ctx, opts))
 	if err != nil {
-		return nil, fmt.Errorf("relay/redis: list types: %w", err)
+		return 0, fmt.Errorf("relay/redis: count types: %w", err)
 	}
+	return count, nil
+}
 
-	result := make([]*catalog.EventType, 0, len(ids))
-	for _, entryID := range ids {
+// typeIndex returns the sorted set of the event types opts may match.
+func typeIndex(opts catalog.ListOpts) string {
+	if opts.Group != "" {
+		return zEventTypeGroup + opts.Group
+	}
+	return zEventTypeAll
+}
+
+// typeLoader returns a seekPage loader that fetches an event type and
+// keeps it only if it matches opts.
+func (s *Store) typeLoader(ctx context.Context, opts catalog.ListOpts) func(string) (*catalog.EventType, error) {
+	return func(etID string) (*catalog.EventType, error) {
 		var m catalogModel
-		if err := s.getEntity(ctx, entityKey(prefixEventType, entryID), &m); err != nil {
+		if err := s.getEntity(ctx, entityKey(prefixEventType, etID), &m); err != nil {
 			if isNotFound(err) {
-				continue
+				return nil, nil //nolint:nilnil // skipped: the event type is gone
 			}
 			return nil, err
 		}
 		if !opts.IncludeDeprecated && m.IsDeprecated {
-			continue
+			return nil, nil //nolint:nilnil // filtered out
 		}
-		et, err := fromCatalogModel(&m)
-		if err != nil {
-			return nil, err
+		if !scope.Matches(m.ScopeAppID, "", opts.ScopeAppID, "") {
+			return nil, nil //nolint:nilnil // filtered out
 		}
-		result = append(result, et)
+		return fromCatalogModel(&m)
 	}
-
-	return applyPagination(result, opts.Offset, opts.Limit), nil
 }
 
 func (s *Store) DeleteType(ctx context.Context, name string) error {
+	key, m, err := s.getTypeModel(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	t := now()
+	if m.IsDeprecated && (m.SunsetAt == nil || !t.Before(*m.SunsetAt)) {
+		return relay.ErrEventTypeNotFound
+	}
+	if !m.IsDeprecated {
+		m.DeprecatedAt = &t
+	}
+	m.IsDeprecated = true
+	m.SunsetAt = &t
+	m.UpdatedAt = t
+
+	if err := s.setEntity(ctx, key, m); err != nil {
+		return fmt.Errorf("relay/redis: delete type update: %w", err)
+	}
+	s.rdb.SRem(ctx, sEventTypeActive, m.ID)
+	return nil
+}
+
+func (s *Store) DeprecateType(ctx context.Context, name string, sunsetAt time.Time) error {
+	key, m, err := s.getTypeModel(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	t := now()
+	if !m.IsDeprecated {
+		m.DeprecatedAt = &t
+	}
+	m.IsDeprecated = true
+	m.SunsetAt = &sunsetAt
+	m.UpdatedAt = t
+
+	if err := s.setEntity(ctx, key, m); err != nil {
+		return fmt.Errorf("relay/redis: deprecate type: %w", err)
+	}
+	s.rdb.SRem(ctx, sEventTypeActive, m.ID)
+	return nil
+}
+
+func (s *Store) UndeprecateType(ctx context.Context, name string) error {
+	key, m, err := s.getTypeModel(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	m.IsDeprecated = false
+	m.DeprecatedAt = nil
+	m.SunsetAt = nil
+	m.UpdatedAt = now()
+
+	if err := s.setEntity(ctx, key, m); err != nil {
+		return fmt.Errorf("relay/redis: undeprecate type: %w", err)
+	}
+	s.rdb.SAdd(ctx, sEventTypeActive, m.ID)
+	return nil
+}
+
+// getTypeModel loads an event type model and its key by name.
+func (s *Store) getTypeModel(ctx context.Context, name string) (string, *catalogModel, error) {
 	entryID, err := s.rdb.Get(ctx, uniqueEventTypeName+name).Result()
 	if err != nil {
 		if isRedisNil(err) {
-			return relay.ErrEventTypeNotFound
+			return "", nil, relay.ErrEventTypeNotFound
 		}
-		return fmt.Errorf("relay/redis: delete type lookup: %w", err)
+		return "", nil, fmt.Errorf("relay/redis: get type lookup: %w", err)
 	}
 
 	key := entityKey(prefixEventType, entryID)
 	var m catalogModel
 	if err := s.getEntity(ctx, key, &m); err != nil {
 		if isNotFound(err) {
-			return relay.ErrEventTypeNotFound
+			return "", nil, relay.ErrEventTypeNotFound
 		}
-		return fmt.Errorf("relay/redis: delete type get: %w", err)
+		return "", nil, fmt.Errorf("relay/redis: get type: %w", err)
 	}
-
-	t := now()
-	m.IsDeprecated = true
-	m.DeprecatedAt = &t
-	m.UpdatedAt = t
-
-	if err := s.setEntity(ctx, key, &m); err != nil {
-		return fmt.Errorf("relay/redis: delete type update: %w", err)
-	}
-	s.rdb.SRem(ctx, sEventTypeActive, entryID)
-	return nil
+	return key, &m, nil
 }
 
 func (s *Store) MatchTypes(ctx context.Context, pattern string) ([]*catalog.EventType, error) {
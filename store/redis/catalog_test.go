@@ -0,0 +1,11 @@
+package redis_test
+
+import (
+	"testing"
+
+	"github.com/xraph/relay/internal/storetest"
+)
+
+func TestRegisterKeepsDeprecation(t *testing.T) {
+	storetest.RegisterKeepsDeprecation(t, openRedisStore(t, startRedis(t)))
+}
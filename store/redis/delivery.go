@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
+	"sort"
+	"strings"
 	"time"
 
 	goredis "github.com/redis/go-redis/v9"
@@ -16,38 +19,44 @@ import (
 
 // deliveryModel is the JSON representation stored in Redis.
 type deliveryModel struct {
-	ID             string     `json:"id"`
-	EventID        string     `json:"event_id"`
-	EndpointID     string     `json:"endpoint_id"`
-	State          string     `json:"state"`
-	AttemptCount   int        `json:"attempt_count"`
-	MaxAttempts    int        `json:"max_attempts"`
-	NextAttemptAt  time.Time  `json:"next_attempt_at"`
-	LastError      string     `json:"last_error"`
-	LastStatusCode int        `json:"last_status_code"`
-	LastResponse   string     `json:"last_response"`
-	LastLatencyMs  int        `json:"last_latency_ms"`
-	CompletedAt    *time.Time `json:"completed_at,omitempty"`
-	CreatedAt      time.Time  `json:"created_at"`
-	UpdatedAt      time.Time  `json:"updated_at"`
+	ID                 string     `json:"id"`
+	EventID            string     `json:"event_id"`
+	EndpointID         string     `json:"endpoint_id"`
+	State              string     `json:"state"`
+	AttemptCount       int        `json:"attempt_count"`
+	MaxAttempts        int        `json:"max_attempts"`
+	NextAttemptAt      time.Time  `json:"next_attempt_at"`
+	LastError          string     `json:"last_error"`
+	LastStatusCode     int        `json:"last_status_code"`
+	LastResponse       string     `json:"last_response"`
+	LastLatencyMs      int        `json:"last_latency_ms"`
+	CompletedAt        *time.Time `json:"completed_at,omitempty"`
+	Test               bool       `json:"test,omitempty"`
+	OriginalDeliveryID string     `json:"original_delivery_id,omitempty"`
+	ReplayCount        int        `json:"replay_count,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
 }
 
 func toDeliveryModel(d *delivery.Delivery) *deliveryModel {
 	return &deliveryModel{
-		ID:             d.ID.String(),
-		EventID:        d.EventID.String(),
-		EndpointID:     d.EndpointID.String(),
-		State:          string(d.State),
-		AttemptCount:   d.AttemptCount,
-		MaxAttempts:    d.MaxAttempts,
-		NextAttemptAt:  d.NextAttemptAt,
-		LastError:      d.LastError,
-		LastStatusCode: d.LastStatusCode,
-		LastResponse:   d.LastResponse,
-		LastLatencyMs:  d.LastLatencyMs,
-		CompletedAt:    d.CompletedAt,
-		CreatedAt:      d.CreatedAt,
-		UpdatedAt:      d.UpdatedAt,
+		ID:                 d.ID.String(),
+		EventID:            d.EventID.String(),
+		EndpointID:         d.EndpointID.String(),
+		State:              string(d.State),
+		AttemptCount:       d.AttemptCount,
+		MaxAttempts:        d.MaxAttempts,
+		NextAttemptAt:      d.NextAttemptAt,
+		LastError:          d.LastError,
+		LastStatusCode:     d.LastStatusCode,
+		LastResponse:       d.LastResponse,
+		LastLatencyMs:      d.LastLatencyMs,
+		CompletedAt:        d.CompletedAt,
+		Test:               d.Test,
+		OriginalDeliveryID: optionalID(d.OriginalDeliveryID),
+		ReplayCount:        d.ReplayCount,
+		CreatedAt:          d.CreatedAt,
+		UpdatedAt:          d.UpdatedAt,
 	}
 }
 
@@ -64,23 +73,30 @@ func fromDeliveryModel(m *deliveryModel) (*delivery.Delivery, error) {
 	if err != nil {
 		return nil, fmt.Errorf("parse endpoint ID %q: %w", m.EndpointID, err)
 	}
+	origID, err := parseOptionalID(m.OriginalDeliveryID, id.ParseDeliveryID)
+	if err != nil {
+		return nil, fmt.Errorf("parse original delivery ID %q: %w", m.OriginalDeliveryID, err)
+	}
 	return &delivery.Delivery{
 		Entity: entity.Entity{
 			CreatedAt: m.CreatedAt,
 			UpdatedAt: m.UpdatedAt,
 		},
-		ID:             delID,
-		EventID:        evtID,
-		EndpointID:     epID,
-		State:          delivery.State(m.State),
-		AttemptCount:   m.AttemptCount,
-		MaxAttempts:    m.MaxAttempts,
-		NextAttemptAt:  m.NextAttemptAt,
-		LastError:      m.LastError,
-		LastStatusCode: m.LastStatusCode,
-		LastResponse:   m.LastResponse,
-		LastLatencyMs:  m.LastLatencyMs,
-		CompletedAt:    m.CompletedAt,
+		ID:                 delID,
+		EventID:            evtID,
+		EndpointID:         epID,
+		State:              delivery.State(m.State),
+		AttemptCount:       m.AttemptCount,
+		MaxAttempts:        m.MaxAttempts,
+		NextAttemptAt:      m.NextAttemptAt,
+		LastError:          m.LastError,
+		LastStatusCode:     m.LastStatusCode,
+		LastResponse:       m.LastResponse,
+		LastLatencyMs:      m.LastLatencyMs,
+		CompletedAt:        m.CompletedAt,
+		Test:               m.Test,
+		OriginalDeliveryID: origID,
+		ReplayCount:        m.ReplayCount,
 	}, nil
 }
 
@@ -106,7 +122,9 @@ func (s *Store) Enqueue(ctx context.Context, d *delivery.Delivery) error {
 	}
 
 	pipe := s.rdb.Pipeline()
-	pipe.ZAdd(ctx, zDeliveryPend, goredis.Z{Score: scoreFromTime(m.NextAttemptAt), Member: m.ID})
+	if d.State == delivery.StatePending {
+		pipe.ZAdd(ctx, zDeliveryPend, goredis.Z{Score: scoreFromTime(m.NextAttemptAt), Member: m.ID})
+	}
 	pipe.ZAdd(ctx, zDeliveryEP+m.EndpointID, goredis.Z{Score: scoreFromTime(m.CreatedAt), Member: m.ID})
 	pipe.ZAdd(ctx, zDeliveryEvt+m.EventID, goredis.Z{Score: scoreFromTime(m.CreatedAt), Member: m.ID})
 	_, err := pipe.Exec(ctx)
@@ -214,32 +232,293 @@ func (s *Store) GetDelivery(ctx context.Context, delID id.ID) (*delivery.Deliver
 	return fromDeliveryModel(&m)
 }
 
+// RetryDelivery makes a pending delivery due now. Membership of the
+// pending set is what Dequeue claims, so only members are rescheduled.
+func (s *Store) RetryDelivery(ctx context.Context, delID id.ID) error {
+	t := now()
+	changed, err := s.rdb.ZAddArgs(ctx, zDeliveryPend, goredis.ZAddArgs{
+		XX:      true,
+		Ch:      true,
+		Members: []goredis.Z{{Score: scoreFromTime(t), Member: delID.String()}},
+	}).Result()
+	if err != nil {
+		return fmt.Errorf("relay/redis: retry delivery: %w", err)
+	}
+	if changed == 0 {
+		return s.notPending(ctx, delID)
+	}
+
+	key := entityKey(prefixDelivery, delID.String())
+	var m deliveryModel
+	if err := s.getEntity(ctx, key, &m); err != nil {
+		return fmt.Errorf("relay/redis: retry delivery: %w", err)
+	}
+	m.NextAttemptAt = t
+	m.UpdatedAt = t
+	if err := s.setEntity(ctx, key, &m); err != nil {
+		return fmt.Errorf("relay/redis: retry delivery: %w", err)
+	}
+	s.notifyWake(ctx)
+	return nil
+}
+
+// CancelDelivery moves a pending delivery to StateCancelled. Removing it
+// from the pending set first keeps Dequeue from claiming it meanwhile.
+func (s *Store) CancelDelivery(ctx context.Context, delID id.ID) error {
+	removed, err := s.rdb.ZRem(ctx, zDeliveryPend, delID.String()).Result()
+	if err != nil {
+		return fmt.Errorf("relay/redis: cancel delivery: %w", err)
+	}
+	if removed == 0 {
+		return s.notPending(ctx, delID)
+	}
+
+	key := entityKey(prefixDelivery, delID.String())
+	var m deliveryModel
+	if err := s.getEntity(ctx, key, &m); err != nil {
+		return fmt.Errorf("relay/redis: cancel delivery: %w", err)
+	}
+	t := now()
+	m.State = string(delivery.StateCancelled)
+	m.CompletedAt = &t
+	m.UpdatedAt = t
+	if err := s.setEntity(ctx, key, &m); err != nil {
+		return fmt.Errorf("relay/redis: cancel delivery: %w", err)
+	}
+	return nil
+}
+
+// notPending tells why a delivery was not in the pending set: it is
+// missing or no longer pending.
+func (s *Store) notPending(ctx context.Context, delID id.ID) error {
+	if _, err := s.GetDelivery(ctx, delID); err != nil {
+		return err
+	}
+	return relay.ErrDeliveryNotPending
+}
+
+func (s *Store) ListDeliveries(ctx context.Context, opts delivery.ListOpts) ([]*delivery.Delivery, error) {
+	result, err := s.pageDeliveries(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("relay/redis: list deliveries: %w", err)
+	}
+	return result, nil
+}
+
+func (s *Store) CountDeliveries(ctx context.Context, opts delivery.ListOpts) (int64, error) {
+	count, err := s.countDeliveries(ctx, opts)
+	if err != nil {
+		return 0, fmt.Errorf("relay/redis: count deliveries: %w", err)
+	}
+	return count, nil
+}
+
 func (s *Store) ListByEndpoint(ctx context.Context, epID id.ID, opts delivery.ListOpts) ([]*delivery.Delivery, error) {
-	ids, err := s.rdb.ZRange(ctx, zDeliveryEP+epID.String(), 0, -1).Result()
+	opts.EndpointID = &epID
+	return s.ListDeliveries(ctx, opts)
+}
+
+func (s *Store) CountByEndpoint(ctx context.Context, epID id.ID, opts delivery.ListOpts) (int64, error) {
+	opts.EndpointID = &epID
+	return s.CountDeliveries(ctx, opts)
+}
+
+// pageDeliveries returns the page of deliveries matching opts, seeking the
+// narrowest index it can use. Deliveries do not record their tenant, so a
+// tenant's deliveries are those of its endpoints.
+func (s *Store) pageDeliveries(ctx context.Context, opts delivery.ListOpts) ([]*delivery.Delivery, error) {
+	lo, hi := deliveryScores(opts)
+	switch {
+	case opts.EventID != nil || opts.EndpointID != nil:
+		key, endpoints, err := s.deliveryIndex(ctx, opts)
+		if err != nil || key == "" {
+			return nil, err
+		}
+		return seekPage(ctx, s, key, lo, hi, opts.Cursor, true, opts.Offset, opts.Limit, s.deliveryLoader(ctx, opts, endpoints))
+	case opts.TenantID != "":
+		return s.pageTenantDeliveries(ctx, opts, lo, hi)
+	}
+	return s.scanDeliveries(ctx, opts)
+}
+
+// countDeliveries counts the deliveries matching opts from the same index
+// pageDeliveries seeks.
+func (s *Store) countDeliveries(ctx context.Context, opts delivery.ListOpts) (int64, error) {
+	lo, hi := deliveryScores(opts)
+	switch {
+	case opts.EventID != nil || opts.EndpointID != nil:
+		key, endpoints, err := s.deliveryIndex(ctx, opts)
+		if err != nil || key == "" {
+			return 0, err
+		}
+		filtered := opts.State != nil || endpoints != nil ||
+			(opts.EventID != nil && opts.EndpointID != nil)
+		return seekCount(ctx, s, key, lo, hi, filtered, s.deliveryLoader(ctx, opts, endpoints))
+	case opts.TenantID != "":
+		epIDs, err := s.rdb.ZRange(ctx, zEndpointTenant+opts.TenantID, 0, -1).Result()
+		if err != nil {
+			return 0, err
+		}
+		var total int64
+		for _, epID := range epIDs {
+			n, err := seekCount(ctx, s, zDeliveryEP+epID, lo, hi, opts.State != nil, s.deliveryLoader(ctx, opts, nil))
+			if err != nil {
+				return 0, err
+			}
+			total += n
+		}
+		return total, nil
+	}
+	result, err := s.scanDeliveries(ctx, delivery.ListOpts{State: opts.State, From: opts.From, To: opts.To})
 	if err != nil {
-		return nil, fmt.Errorf("relay/redis: list by endpoint: %w", err)
+		return 0, err
 	}
+	return int64(len(result)), nil
+}
 
-	result := make([]*delivery.Delivery, 0, len(ids))
-	for i := len(ids) - 1; i >= 0; i-- { // reverse for DESC order
-		var m deliveryModel
-		if err := s.getEntity(ctx, entityKey(prefixDelivery, ids[i]), &m); err != nil {
-			if isNotFound(err) {
-				continue
+// deliveryIndex returns the sorted set to seek for opts with an event or
+// endpoint filter, and the tenant's endpoint IDs when the set may hold
+// deliveries to other tenants. It returns an empty key when the endpoint
+// is not the tenant's.
+func (s *Store) deliveryIndex(ctx context.Context, opts delivery.ListOpts) (string, map[string]bool, error) {
+	if opts.EventID == nil {
+		if opts.TenantID != "" {
+			_, err := s.rdb.ZScore(ctx, zEndpointTenant+opts.TenantID, opts.EndpointID.String()).Result()
+			if isRedisNil(err) {
+				return "", nil, nil
+			}
+			if err != nil {
+				return "", nil, err
 			}
+		}
+		return zDeliveryEP + opts.EndpointID.String(), nil, nil
+	}
+
+	key := zDeliveryEvt + opts.EventID.String()
+	if opts.TenantID == "" {
+		return key, nil, nil
+	}
+	epIDs, err := s.rdb.ZRange(ctx, zEndpointTenant+opts.TenantID, 0, -1).Result()
+	if err != nil {
+		return "", nil, err
+	}
+	endpoints := make(map[string]bool, len(epIDs))
+	for _, epID := range epIDs {
+		endpoints[epID] = true
+	}
+	return key, endpoints, nil
+}
+
+// pageTenantDeliveries returns the page of a tenant's deliveries matching
+// opts. Each endpoint's index holds at most one page of the result, so it
+// seeks that much from each and merges them.
+func (s *Store) pageTenantDeliveries(ctx context.Context, opts delivery.ListOpts, lo, hi float64) ([]*delivery.Delivery, error) {
+	epIDs, err := s.rdb.ZRange(ctx, zEndpointTenant+opts.TenantID, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	after, err := s.deliveryCursor(ctx, opts.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	n := 0
+	if opts.Limit > 0 {
+		n = opts.Offset + opts.Limit
+	}
+	var result []*delivery.Delivery
+	for _, epID := range epIDs {
+		page, err := seekPageAfter(ctx, s, zDeliveryEP+epID, lo, hi, after, true, 0, n, s.deliveryLoader(ctx, opts, nil))
+		if err != nil {
 			return nil, err
 		}
-		if opts.State != nil && delivery.State(m.State) != *opts.State {
-			continue
+		result = append(result, page...)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		si, sj := scoreFromTime(result[i].CreatedAt), scoreFromTime(result[j].CreatedAt)
+		if si != sj {
+			return si > sj
 		}
-		d, err := fromDeliveryModel(&m)
+		return result[i].ID.String() > result[j].ID.String()
+	})
+	return applyPagination(result, opts.Offset, opts.Limit), nil
+}
+
+// deliveryCursor returns the position of cursor in the delivery indexes,
+// which score a delivery by its creation time in every set.
+func (s *Store) deliveryCursor(ctx context.Context, cursor id.ID) (*seekCursor, error) {
+	if cursor.IsNil() {
+		return nil, nil //nolint:nilnil // walk from the start
+	}
+	var m deliveryModel
+	err := s.getEntity(ctx, entityKey(prefixDelivery, cursor.String()), &m)
+	switch {
+	case err == nil:
+		return &seekCursor{member: cursor.String(), score: scoreFromTime(m.CreatedAt)}, nil
+	case isNotFound(err):
+		return &seekCursor{member: cursor.String(), score: scoreFromTime(cursor.Time())}, nil
+	}
+	return nil, err
+}
+
+// scanDeliveries returns the page of deliveries matching opts when no
+// index covers them, scanning every delivery.
+func (s *Store) scanDeliveries(ctx context.Context, opts delivery.ListOpts) ([]*delivery.Delivery, error) {
+	load := s.deliveryLoader(ctx, opts, nil)
+	var result []*delivery.Delivery
+	iter := s.rdb.Scan(ctx, 0, prefixDelivery+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		d, err := load(strings.TrimPrefix(iter.Val(), prefixDelivery))
 		if err != nil {
 			return nil, err
 		}
-		result = append(result, d)
+		if d != nil {
+			result = append(result, d)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
 	}
+	return pageByID(result, func(d *delivery.Delivery) id.ID { return d.ID }, opts.Cursor, true, opts.Offset, opts.Limit), nil
+}
 
-	return applyPagination(result, opts.Offset, opts.Limit), nil
+// deliveryScores returns the score range of the delivery indexes, which
+// are scored by creation time, that opts covers.
+func deliveryScores(opts delivery.ListOpts) (lo, hi float64) {
+	lo, hi = math.Inf(-1), math.Inf(1)
+	if opts.From != nil {
+		lo = scoreFromTime(*opts.From)
+	}
+	if opts.To != nil {
+		hi = scoreFromTime(*opts.To)
+	}
+	return lo, hi
+}
+
+// deliveryLoader returns a seekPage loader that fetches a delivery and
+// keeps it only if it matches opts and, when endpoints is set, goes to
+// one of them.
+func (s *Store) deliveryLoader(ctx context.Context, opts delivery.ListOpts, endpoints map[string]bool) func(string) (*delivery.Delivery, error) {
+	return func(delID string) (*delivery.Delivery, error) {
+		var m deliveryModel
+		if err := s.getEntity(ctx, entityKey(prefixDelivery, delID), &m); err != nil {
+			if isNotFound(err) {
+				return nil, nil //nolint:nilnil // skipped: the delivery is gone
+			}
+			return nil, err
+		}
+		switch {
+		case opts.EndpointID != nil && m.EndpointID != opts.EndpointID.String(),
+			opts.EventID != nil && m.EventID != opts.EventID.String(),
+			opts.State != nil && delivery.State(m.State) != *opts.State,
+			opts.From != nil && m.CreatedAt.Before(*opts.From),
+			opts.To != nil && m.CreatedAt.After(*opts.To),
+			endpoints != nil && !endpoints[m.EndpointID]:
+			return nil, nil //nolint:nilnil // filtered out
+		}
+		return fromDeliveryModel(&m)
+	}
 }
 
 func (s *Store) ListByEvent(ctx context.Context, evtID id.ID) ([]*delivery.Delivery, error) {
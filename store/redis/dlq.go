@@ -2,8 +2,10 @@ package redis
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
+	"strings"
 	"time"
 
 	goredis "github.com/redis/go-redis/v9"
@@ -17,40 +19,44 @@ import (
 
 // dlqEntryModel is the JSON representation stored in Redis.
 type dlqEntryModel struct {
-	ID             string     `json:"id"`
-	DeliveryID     string     `json:"delivery_id"`
-	EventID        string     `json:"event_id"`
-	EndpointID     string     `json:"endpoint_id"`
-	TenantID       string     `json:"tenant_id"`
-	EventType      string     `json:"event_type"`
-	URL            string     `json:"url"`
-	Payload        any        `json:"payload,omitempty"`
-	Error          string     `json:"error"`
-	AttemptCount   int        `json:"attempt_count"`
-	LastStatusCode int        `json:"last_status_code"`
-	ReplayedAt     *time.Time `json:"replayed_at,omitempty"`
-	FailedAt       time.Time  `json:"failed_at"`
-	CreatedAt      time.Time  `json:"created_at"`
-	UpdatedAt      time.Time  `json:"updated_at"`
+	ID               string     `json:"id"`
+	DeliveryID       string     `json:"delivery_id"`
+	EventID          string     `json:"event_id"`
+	EndpointID       string     `json:"endpoint_id"`
+	TenantID         string     `json:"tenant_id"`
+	EventType        string     `json:"event_type"`
+	URL              string     `json:"url"`
+	Payload          any        `json:"payload,omitempty"`
+	Error            string     `json:"error"`
+	AttemptCount     int        `json:"attempt_count"`
+	LastStatusCode   int        `json:"last_status_code"`
+	ReplayCount      int        `json:"replay_count,omitempty"`
+	ReplayedAt       *time.Time `json:"replayed_at,omitempty"`
+	ReplayDeliveryID string     `json:"replay_delivery_id,omitempty"`
+	FailedAt         time.Time  `json:"failed_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
 }
 
 func toDLQEntryModel(e *dlq.Entry) *dlqEntryModel {
 	return &dlqEntryModel{
-		ID:             e.ID.String(),
-		DeliveryID:     e.DeliveryID.String(),
-		EventID:        e.EventID.String(),
-		EndpointID:     e.EndpointID.String(),
-		TenantID:       e.TenantID,
-		EventType:      e.EventType,
-		URL:            e.URL,
-		Payload:        e.Payload,
-		Error:          e.Error,
-		AttemptCount:   e.AttemptCount,
-		LastStatusCode: e.LastStatusCode,
-		ReplayedAt:     e.ReplayedAt,
-		FailedAt:       e.FailedAt,
-		CreatedAt:      e.CreatedAt,
-		UpdatedAt:      e.UpdatedAt,
+		ID:               e.ID.String(),
+		DeliveryID:       e.DeliveryID.String(),
+		EventID:          e.EventID.String(),
+		EndpointID:       e.EndpointID.String(),
+		TenantID:         e.TenantID,
+		EventType:        e.EventType,
+		URL:              e.URL,
+		Payload:          e.Payload,
+		Error:            e.Error,
+		AttemptCount:     e.AttemptCount,
+		LastStatusCode:   e.LastStatusCode,
+		ReplayCount:      e.ReplayCount,
+		ReplayedAt:       e.ReplayedAt,
+		ReplayDeliveryID: optionalID(e.ReplayDeliveryID),
+		FailedAt:         e.FailedAt,
+		CreatedAt:        e.CreatedAt,
+		UpdatedAt:        e.UpdatedAt,
 	}
 }
 
@@ -71,24 +77,30 @@ func fromDLQEntryModel(m *dlqEntryModel) (*dlq.Entry, error) {
 	if err != nil {
 		return nil, fmt.Errorf("parse endpoint ID %q: %w", m.EndpointID, err)
 	}
+	replayID, err := parseOptionalID(m.ReplayDeliveryID, id.ParseDeliveryID)
+	if err != nil {
+		return nil, fmt.Errorf("parse replay delivery ID %q: %w", m.ReplayDeliveryID, err)
+	}
 	return &dlq.Entry{
 		Entity: entity.Entity{
 			CreatedAt: m.CreatedAt,
 			UpdatedAt: m.UpdatedAt,
 		},
-		ID:             dlqID,
-		DeliveryID:     delID,
-		EventID:        evtID,
-		EndpointID:     epID,
-		TenantID:       m.TenantID,
-		EventType:      m.EventType,
-		URL:            m.URL,
-		Payload:        m.Payload,
-		Error:          m.Error,
-		AttemptCount:   m.AttemptCount,
-		LastStatusCode: m.LastStatusCode,
-		ReplayedAt:     m.ReplayedAt,
-		FailedAt:       m.FailedAt,
+		ID:               dlqID,
+		DeliveryID:       delID,
+		EventID:          evtID,
+		EndpointID:       epID,
+		TenantID:         m.TenantID,
+		EventType:        m.EventType,
+		URL:              m.URL,
+		Payload:          m.Payload,
+		Error:            m.Error,
+		AttemptCount:     m.AttemptCount,
+		LastStatusCode:   m.LastStatusCode,
+		ReplayCount:      m.ReplayCount,
+		ReplayedAt:       m.ReplayedAt,
+		ReplayDeliveryID: replayID,
+		FailedAt:         m.FailedAt,
 	}, nil
 }
 
@@ -116,7 +128,18 @@ func (s *Store) Push(ctx context.Context, entry *dlq.Entry) error {
 }
 
 func (s *Store) ListDLQ(ctx context.Context, opts dlq.ListOpts) ([]*dlq.Entry, error) {
-	zKey := zDLQAll
+	zKey, lo, hi := dlqIndex(opts)
+	result, err := seekPage(ctx, s, zKey, lo, hi, opts.Cursor, true, opts.Offset, opts.Limit, s.dlqLoader(ctx, opts))
+	if err != nil {
+		return nil, fmt.Errorf("relay/redis: list dlq: %w", err)
+	}
+	return result, nil
+}
+
+// dlqIndex returns the narrowest sorted set for opts and the score range,
+// by failure time, it covers.
+func dlqIndex(opts dlq.ListOpts) (zKey string, lo, hi float64) {
+	zKey = zDLQAll
 	if opts.TenantID != "" {
 		zKey = zDLQTenant + opts.TenantID
 	}
@@ -124,37 +147,48 @@ func (s *Store) ListDLQ(ctx context.Context, opts dlq.ListOpts) ([]*dlq.Entry, e
 		zKey = zDLQEndpoint + opts.EndpointID.String()
 	}
 
-	minScore := math.Inf(-1)
-	maxScore := math.Inf(1)
+	lo, hi = math.Inf(-1), math.Inf(1)
 	if opts.From != nil {
-		minScore = scoreFromTime(*opts.From)
+		lo = scoreFromTime(*opts.From)
 	}
 	if opts.To != nil {
-		maxScore = scoreFromTime(*opts.To)
-	}
-
-	ids, err := s.zRangeByScoreIDs(ctx, zKey, minScore, maxScore)
-	if err != nil {
-		return nil, fmt.Errorf("relay/redis: list dlq: %w", err)
+		hi = scoreFromTime(*opts.To)
 	}
+	return zKey, lo, hi
+}
 
-	result := make([]*dlq.Entry, 0, len(ids))
-	for i := len(ids) - 1; i >= 0; i-- { // reverse for DESC order
+// dlqLoader returns a seekPage loader that fetches a DLQ entry and keeps
+// it only if it matches opts.
+func (s *Store) dlqLoader(ctx context.Context, opts dlq.ListOpts) func(string) (*dlq.Entry, error) {
+	return func(entryID string) (*dlq.Entry, error) {
 		var m dlqEntryModel
-		if err := s.getEntity(ctx, entityKey(prefixDLQ, ids[i]), &m); err != nil {
+		if err := s.getEntity(ctx, entityKey(prefixDLQ, entryID), &m); err != nil {
 			if isNotFound(err) {
-				continue
+				return nil, nil //nolint:nilnil // skipped: the entry is gone
 			}
 			return nil, err
 		}
-		entry, err := fromDLQEntryModel(&m)
-		if err != nil {
-			return nil, err
+		if !matchDLQModel(&m, opts) {
+			return nil, nil //nolint:nilnil // filtered out
 		}
-		result = append(result, entry)
+		return fromDLQEntryModel(&m)
 	}
+}
 
-	return applyPagination(result, opts.Offset, opts.Limit), nil
+// matchDLQModel reports whether m matches the filters of opts that its
+// sorted set does not apply.
+func matchDLQModel(m *dlqEntryModel, opts dlq.ListOpts) bool {
+	switch {
+	case opts.TenantID != "" && m.TenantID != opts.TenantID:
+		return false
+	case opts.EventType != "" && m.EventType != opts.EventType:
+		return false
+	case opts.StatusCode != 0 && m.LastStatusCode != opts.StatusCode:
+		return false
+	case opts.ErrorContains != "" && !strings.Contains(m.Error, opts.ErrorContains):
+		return false
+	}
+	return !opts.Unreplayed || m.ReplayedAt == nil
 }
 
 func (s *Store) GetDLQ(ctx context.Context, dlqID id.ID) (*dlq.Entry, error) {
@@ -168,76 +202,43 @@ func (s *Store) GetDLQ(ctx context.Context, dlqID id.ID) (*dlq.Entry, error) {
 	return fromDLQEntryModel(&m)
 }
 
-func (s *Store) Replay(ctx context.Context, dlqID id.ID) error {
-	entry, err := s.GetDLQ(ctx, dlqID)
-	if err != nil {
-		return err
-	}
-
-	// Re-enqueue a new delivery.
-	d := &delivery.Delivery{
-		ID:            id.NewDeliveryID(),
-		EventID:       entry.EventID,
-		EndpointID:    entry.EndpointID,
-		State:         delivery.StatePending,
-		NextAttemptAt: now(),
-	}
-	d.CreatedAt = now()
-	d.UpdatedAt = d.CreatedAt
-
-	if enqueueErr := s.Enqueue(ctx, d); enqueueErr != nil {
-		return enqueueErr
+// Replay enqueues d and marks the DLQ entry replayed. A unique key claims
+// the entry first so concurrent replays enqueue one delivery.
+func (s *Store) Replay(ctx context.Context, dlqID id.ID, d *delivery.Delivery) error {
+	key := entityKey(prefixDLQ, dlqID.String())
+	var m dlqEntryModel
+	if err := s.getEntity(ctx, key, &m); err != nil {
+		if isNotFound(err) {
+			return relay.ErrDLQNotFound
+		}
+		return fmt.Errorf("relay/redis: replay dlq: %w", err)
 	}
 
-	// Remove from DLQ.
-	return s.deleteDLQEntry(ctx, dlqID.String(), entry.TenantID, entry.EndpointID.String())
-}
-
-func (s *Store) ReplayBulk(ctx context.Context, from, to time.Time) (int64, error) {
-	minScore := scoreFromTime(from)
-	maxScore := scoreFromTime(to)
-
-	ids, err := s.zRangeByScoreIDs(ctx, zDLQAll, minScore, maxScore)
+	claimKey := uniqueDLQReplay + m.ID
+	claimed, err := s.rdb.SetNX(ctx, claimKey, d.ID.String(), 0).Result()
 	if err != nil {
-		return 0, fmt.Errorf("relay/redis: replay bulk list: %w", err)
+		return fmt.Errorf("relay/redis: replay claim dlq: %w", err)
+	}
+	if !claimed || m.ReplayedAt != nil {
+		return dlq.ErrAlreadyReplayed
 	}
 
-	var count int64
-	for _, entryID := range ids {
-		var m dlqEntryModel
-		if err := s.getEntity(ctx, entityKey(prefixDLQ, entryID), &m); err != nil {
-			if isNotFound(err) {
-				continue
-			}
-			return count, err
-		}
-
-		entry, err := fromDLQEntryModel(&m)
-		if err != nil {
-			return count, err
-		}
-
-		d := &delivery.Delivery{
-			ID:            id.NewDeliveryID(),
-			EventID:       entry.EventID,
-			EndpointID:    entry.EndpointID,
-			State:         delivery.StatePending,
-			NextAttemptAt: now(),
-		}
-		d.CreatedAt = now()
-		d.UpdatedAt = d.CreatedAt
-
-		if enqueueErr := s.Enqueue(ctx, d); enqueueErr != nil {
-			return count, enqueueErr
+	if err := s.Enqueue(ctx, d); err != nil {
+		// Release the claim so the entry can be replayed again.
+		if delErr := s.rdb.Del(ctx, claimKey).Err(); delErr != nil {
+			return errors.Join(err, delErr)
 		}
-
-		if err := s.deleteDLQEntry(ctx, entryID, m.TenantID, m.EndpointID); err != nil {
-			return count, err
-		}
-		count++
+		return err
 	}
 
-	return count, nil
+	t := now()
+	m.ReplayedAt = &t
+	m.ReplayDeliveryID = d.ID.String()
+	m.UpdatedAt = t
+	if err := s.setEntity(ctx, key, &m); err != nil {
+		return fmt.Errorf("relay/redis: replay dlq: %w", err)
+	}
+	return nil
 }
 
 func (s *Store) Purge(ctx context.Context, before time.Time) (int64, error) {
@@ -266,8 +267,12 @@ func (s *Store) Purge(ctx context.Context, before time.Time) (int64, error) {
 	return count, nil
 }
 
-func (s *Store) CountDLQ(ctx context.Context) (int64, error) {
-	count, err := s.rdb.ZCard(ctx, zDLQAll).Result()
+func (s *Store) CountDLQ(ctx context.Context, opts dlq.ListOpts) (int64, error) {
+	zKey, lo, hi := dlqIndex(opts)
+	// With an endpoint index, the tenant is one more filter to apply.
+	filtered := (opts.TenantID != "" && opts.EndpointID != nil) || opts.EventType != "" ||
+		opts.StatusCode != 0 || opts.ErrorContains != "" || opts.Unreplayed
+	count, err := seekCount(ctx, s, zKey, lo, hi, filtered, s.dlqLoader(ctx, opts))
 	if err != nil {
 		return 0, fmt.Errorf("relay/redis: count dlq: %w", err)
 	}
@@ -277,7 +282,7 @@ func (s *Store) CountDLQ(ctx context.Context) (int64, error) {
 // deleteDLQEntry removes a DLQ entry and its index entries.
 func (s *Store) deleteDLQEntry(ctx context.Context, entryID, tenantID, endpointID string) error {
 	pipe := s.rdb.Pipeline()
-	pipe.Del(ctx, entityKey(prefixDLQ, entryID))
+	pipe.Del(ctx, entityKey(prefixDLQ, entryID), uniqueDLQReplay+entryID)
 	pipe.ZRem(ctx, zDLQAll, entryID)
 	if tenantID != "" {
 		pipe.ZRem(ctx, zDLQTenant+tenantID, entryID)
@@ -3,6 +3,8 @@ package redis
 import (
 	"context"
 	"fmt"
+	"math"
+	"sort"
 	"time"
 
 	goredis "github.com/redis/go-redis/v9"
@@ -12,38 +14,55 @@ import (
 	"github.com/xraph/relay/endpoint"
 	"github.com/xraph/relay/id"
 	"github.com/xraph/relay/internal/entity"
+	"github.com/xraph/relay/scope"
 )
 
 // endpointModel is the JSON representation stored in Redis.
 type endpointModel struct {
-	ID          string            `json:"id"`
-	TenantID    string            `json:"tenant_id"`
-	URL         string            `json:"url"`
-	Description string            `json:"description"`
-	Secret      string            `json:"secret"`
-	EventTypes  []string          `json:"event_types"`
-	Headers     map[string]string `json:"headers,omitempty"`
-	Enabled     bool              `json:"enabled"`
-	RateLimit   int               `json:"rate_limit"`
-	Metadata    map[string]string `json:"metadata,omitempty"`
-	CreatedAt   time.Time         `json:"created_at"`
-	UpdatedAt   time.Time         `json:"updated_at"`
+	ID             string            `json:"id"`
+	TenantID       string            `json:"tenant_id"`
+	URL            string            `json:"url"`
+	Description    string            `json:"description"`
+	Secret         string            `json:"secret"`
+	EventTypes     []string          `json:"event_types"`
+	Headers        map[string]string `json:"headers,omitempty"`
+	Enabled        bool              `json:"enabled"`
+	RateLimit      int               `json:"rate_limit"`
+	Version        string            `json:"version,omitempty"`
+	Status         string            `json:"status,omitempty"`
+	VerifiedAt     *time.Time        `json:"verified_at,omitempty"`
+	KeepDisabled   bool              `json:"keep_disabled,omitempty"`
+	DisabledReason string            `json:"disabled_reason,omitempty"`
+	DisabledAt     *time.Time        `json:"disabled_at,omitempty"`
+	ScopeAppID     string            `json:"scope_app_id,omitempty"`
+	ScopeOrgID     string            `json:"scope_org_id,omitempty"`
+	Metadata       map[string]string `json:"metadata,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
 }
 
 func toEndpointModel(ep *endpoint.Endpoint) *endpointModel {
 	return &endpointModel{
-		ID:          ep.ID.String(),
-		TenantID:    ep.TenantID,
-		URL:         ep.URL,
-		Description: ep.Description,
-		Secret:      ep.Secret,
-		EventTypes:  ep.EventTypes,
-		Headers:     ep.Headers,
-		Enabled:     ep.Enabled,
-		RateLimit:   ep.RateLimit,
-		Metadata:    ep.Metadata,
-		CreatedAt:   ep.CreatedAt,
-		UpdatedAt:   ep.UpdatedAt,
+		ID:             ep.ID.String(),
+		TenantID:       ep.TenantID,
+		URL:            ep.URL,
+		Description:    ep.Description,
+		Secret:         ep.Secret,
+		EventTypes:     ep.EventTypes,
+		Headers:        ep.Headers,
+		Enabled:        ep.Enabled,
+		RateLimit:      ep.RateLimit,
+		Version:        ep.Version,
+		Status:         string(ep.Status),
+		VerifiedAt:     ep.VerifiedAt,
+		KeepDisabled:   ep.KeepDisabled,
+		DisabledReason: ep.DisabledReason,
+		DisabledAt:     ep.DisabledAt,
+		ScopeAppID:     ep.ScopeAppID,
+		ScopeOrgID:     ep.ScopeOrgID,
+		Metadata:       ep.Metadata,
+		CreatedAt:      ep.CreatedAt,
+		UpdatedAt:      ep.UpdatedAt,
 	}
 }
 
@@ -57,16 +76,24 @@ func fromEndpointModel(m *endpointModel) (*endpoint.Endpoint, error) {
 			CreatedAt: m.CreatedAt,
 			UpdatedAt: m.UpdatedAt,
 		},
-		ID:          epID,
-		TenantID:    m.TenantID,
-		URL:         m.URL,
-		Description: m.Description,
-		Secret:      m.Secret,
-		EventTypes:  m.EventTypes,
-		Headers:     m.Headers,
-		Enabled:     m.Enabled,
-		RateLimit:   m.RateLimit,
-		Metadata:    m.Metadata,
+		ID:             epID,
+		TenantID:       m.TenantID,
+		URL:            m.URL,
+		Description:    m.Description,
+		Secret:         m.Secret,
+		EventTypes:     m.EventTypes,
+		Headers:        m.Headers,
+		Enabled:        m.Enabled,
+		RateLimit:      m.RateLimit,
+		Version:        m.Version,
+		Status:         endpoint.Status(m.Status),
+		VerifiedAt:     m.VerifiedAt,
+		KeepDisabled:   m.KeepDisabled,
+		DisabledReason: m.DisabledReason,
+		DisabledAt:     m.DisabledAt,
+		ScopeAppID:     m.ScopeAppID,
+		ScopeOrgID:     m.ScopeOrgID,
+		Metadata:       m.Metadata,
 	}, nil
 }
 
@@ -87,6 +114,7 @@ func (s *Store) CreateEndpoint(ctx context.Context, ep *endpoint.Endpoint) error
 	if err != nil {
 		return fmt.Errorf("relay/redis: create endpoint indexes: %w", err)
 	}
+	s.index.Invalidate(m.TenantID)
 	return nil
 }
 
@@ -126,6 +154,8 @@ func (s *Store) UpdateEndpoint(ctx context.Context, ep *endpoint.Endpoint) error
 	} else {
 		s.rdb.SRem(ctx, enabledSetKey(m.TenantID), m.ID)
 	}
+	s.index.InvalidateEndpoint(m.ID)
+	s.index.Invalidate(m.TenantID)
 	return nil
 }
 
@@ -150,47 +180,54 @@ func (s *Store) DeleteEndpoint(ctx context.Context, epID id.ID) error {
 	if _, err := pipe.Exec(ctx); err != nil {
 		return fmt.Errorf("relay/redis: delete endpoint indexes: %w", err)
 	}
+	s.index.InvalidateEndpoint(m.ID)
 	return nil
 }
 
 func (s *Store) ListEndpoints(ctx context.Context, tenantID string, opts endpoint.ListOpts) ([]*endpoint.Endpoint, error) {
-	ids, err := s.rdb.ZRange(ctx, zEndpointTenant+tenantID, 0, -1).Result()
+	result, err := seekPage(ctx, s, zEndpointTenant+tenantID, math.Inf(-1), math.Inf(1), opts.Cursor, false, opts.Offset, opts.Limit, s.endpointLoader(ctx, opts))
 	if err != nil {
 		return nil, fmt.Errorf("relay/redis: list endpoints: %w", err)
 	}
+	return result, nil
+}
+
+func (s *Store) CountEndpoints(ctx context.Context, tenantID string, opts endpoint.ListOpts) (int64, error) {
+	filtered := opts.Enabled != nil || opts.ScopeAppID != "" || opts.ScopeOrgID != ""
+	count, err := seekCount(ctx, s, zEndpointTenant+tenantID, math.Inf(-1), math.Inf(1), filtered, s.endpointLoader(ctx, opts))
+	if err != nil {
+		return 0, fmt.Errorf("relay/redis: count endpoints: %w", err)
+	}
+	return count, nil
+}
 
-	result := make([]*endpoint.Endpoint, 0, len(ids))
-	for _, entryID := range ids {
+// endpointLoader returns a seekPage loader that fetches an endpoint and
+// keeps it only if it matches opts.
+func (s *Store) endpointLoader(ctx context.Context, opts endpoint.ListOpts) func(string) (*endpoint.Endpoint, error) {
+	return func(epID string) (*endpoint.Endpoint, error) {
 		var m endpointModel
-		if err := s.getEntity(ctx, entityKey(prefixEndpoint, entryID), &m); err != nil {
+		if err := s.getEntity(ctx, entityKey(prefixEndpoint, epID), &m); err != nil {
 			if isNotFound(err) {
-				continue
+				return nil, nil //nolint:nilnil // skipped: the endpoint is gone
 			}
 			return nil, err
 		}
 		if opts.Enabled != nil && m.Enabled != *opts.Enabled {
-			continue
+			return nil, nil //nolint:nilnil // filtered out
 		}
-		ep, err := fromEndpointModel(&m)
-		if err != nil {
-			return nil, err
+		if !scope.Matches(m.ScopeAppID, m.ScopeOrgID, opts.ScopeAppID, opts.ScopeOrgID) {
+			return nil, nil //nolint:nilnil // filtered out
 		}
-		result = append(result, ep)
+		return fromEndpointModel(&m)
 	}
-
-	return applyPagination(result, opts.Offset, opts.Limit), nil
 }
 
-func (s *Store) Resolve(ctx context.Context, tenantID, eventType string) ([]*endpoint.Endpoint, error) {
-	ids, err := s.rdb.SMembers(ctx, enabledSetKey(tenantID)).Result()
-	if err != nil {
-		return nil, fmt.Errorf("relay/redis: resolve: %w", err)
-	}
-
+func (s *Store) ListSubscribers(ctx context.Context, eventType string) ([]*endpoint.Endpoint, error) {
 	var result []*endpoint.Endpoint
-	for _, entryID := range ids {
+	iter := s.rdb.Scan(ctx, 0, prefixEndpoint+"*", 100).Iterator()
+	for iter.Next(ctx) {
 		var m endpointModel
-		if err := s.getEntity(ctx, entityKey(prefixEndpoint, entryID), &m); err != nil {
+		if err := s.getEntity(ctx, iter.Val(), &m); err != nil {
 			if isNotFound(err) {
 				continue
 			}
@@ -207,9 +244,56 @@ func (s *Store) Resolve(ctx context.Context, tenantID, eventType string) ([]*end
 			}
 		}
 	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("relay/redis: list subscribers: %w", err)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
 	return result, nil
 }
 
+func (s *Store) ListAutoDisabled(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	var result []*endpoint.Endpoint
+	iter := s.rdb.Scan(ctx, 0, prefixEndpoint+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		var m endpointModel
+		if err := s.getEntity(ctx, iter.Val(), &m); err != nil {
+			if isNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		if m.Enabled || m.DisabledReason == "" {
+			continue
+		}
+		ep, err := fromEndpointModel(&m)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, ep)
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("relay/redis: list auto-disabled endpoints: %w", err)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+	return result, nil
+}
+
+func (s *Store) Resolve(ctx context.Context, tenantID, eventType string) ([]*endpoint.Endpoint, error) {
+	return s.index.Resolve(ctx, tenantID, eventType)
+}
+
+// tenantEndpoints loads every endpoint of a tenant for the subscription
+// index, oldest first.
+func (s *Store) tenantEndpoints(ctx context.Context, tenantID string) ([]*endpoint.Endpoint, error) {
+	return s.ListEndpoints(ctx, tenantID, endpoint.ListOpts{})
+}
+
 func (s *Store) SetEnabled(ctx context.Context, epID id.ID, enabled bool) error {
 	key := entityKey(prefixEndpoint, epID.String())
 
@@ -222,6 +306,8 @@ func (s *Store) SetEnabled(ctx context.Context, epID id.ID, enabled bool) error
 	}
 
 	m.Enabled = enabled
+	m.DisabledReason = ""
+	m.DisabledAt = nil
 	m.UpdatedAt = now()
 
 	if err := s.setEntity(ctx, key, &m); err != nil {
@@ -233,5 +319,31 @@ func (s *Store) SetEnabled(ctx context.Context, epID id.ID, enabled bool) error
 	} else {
 		s.rdb.SRem(ctx, enabledSetKey(m.TenantID), m.ID)
 	}
+	s.index.InvalidateEndpoint(m.ID)
+	return nil
+}
+
+func (s *Store) DisableEndpoint(ctx context.Context, epID id.ID, reason string, at time.Time) error {
+	key := entityKey(prefixEndpoint, epID.String())
+
+	var m endpointModel
+	if err := s.getEntity(ctx, key, &m); err != nil {
+		if isNotFound(err) {
+			return relay.ErrEndpointNotFound
+		}
+		return fmt.Errorf("relay/redis: disable endpoint get: %w", err)
+	}
+
+	m.Enabled = false
+	m.DisabledReason = reason
+	m.DisabledAt = &at
+	m.UpdatedAt = now()
+
+	if err := s.setEntity(ctx, key, &m); err != nil {
+		return fmt.Errorf("relay/redis: disable endpoint: %w", err)
+	}
+
+	s.rdb.SRem(ctx, enabledSetKey(m.TenantID), m.ID)
+	s.index.InvalidateEndpoint(m.ID)
 	return nil
 }
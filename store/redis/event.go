@@ -12,6 +12,7 @@ import (
 	"github.com/xraph/relay/event"
 	"github.com/xraph/relay/id"
 	"github.com/xraph/relay/internal/entity"
+	"github.com/xraph/relay/scope"
 )
 
 // eventModel is the JSON representation stored in Redis.
@@ -20,7 +21,9 @@ type eventModel struct {
 	Type           string    `json:"type"`
 	TenantID       string    `json:"tenant_id"`
 	Data           any       `json:"data,omitempty"`
+	Version        string    `json:"version,omitempty"`
 	IdempotencyKey string    `json:"idempotency_key,omitempty"`
+	Test           bool      `json:"test,omitempty"`
 	ScopeAppID     string    `json:"scope_app_id"`
 	ScopeOrgID     string    `json:"scope_org_id"`
 	CreatedAt      time.Time `json:"created_at"`
@@ -33,7 +36,9 @@ func toEventModel(evt *event.Event) *eventModel {
 		Type:           evt.Type,
 		TenantID:       evt.TenantID,
 		Data:           evt.Data,
+		Version:        evt.Version,
 		IdempotencyKey: evt.IdempotencyKey,
+		Test:           evt.Test,
 		ScopeAppID:     evt.ScopeAppID,
 		ScopeOrgID:     evt.ScopeOrgID,
 		CreatedAt:      evt.CreatedAt,
@@ -55,7 +60,9 @@ func fromEventModel(m *eventModel) (*event.Event, error) {
 		Type:           m.Type,
 		TenantID:       m.TenantID,
 		Data:           m.Data,
+		Version:        m.Version,
 		IdempotencyKey: m.IdempotencyKey,
+		Test:           m.Test,
 		ScopeAppID:     m.ScopeAppID,
 		ScopeOrgID:     m.ScopeOrgID,
 	}, nil
@@ -103,67 +110,100 @@ func (s *Store) GetEvent(ctx context.Context, evtID id.ID) (*event.Event, error)
 	return fromEventModel(&m)
 }
 
-func (s *Store) ListEvents(ctx context.Context, opts event.ListOpts) ([]*event.Event, error) {
-	minScore := math.Inf(-1)
-	maxScore := math.Inf(1)
-	if opts.From != nil {
-		minScore = scoreFromTime(*opts.From)
+func (s *Store) GetEventByIdempotencyKey(ctx context.Context, key string) (*event.Event, error) {
+	evtID, err := s.rdb.Get(ctx, uniqueEventIdem+key).Result()
+	if err != nil {
+		if isRedisNil(err) {
+			return nil, relay.ErrEventNotFound
+		}
+		return nil, fmt.Errorf("relay/redis: get event by idempotency key: %w", err)
 	}
-	if opts.To != nil {
-		maxScore = scoreFromTime(*opts.To)
+
+	var m eventModel
+	if err := s.getEntity(ctx, entityKey(prefixEvent, evtID), &m); err != nil {
+		if isNotFound(err) {
+			return nil, relay.ErrEventNotFound
+		}
+		return nil, fmt.Errorf("relay/redis: get event: %w", err)
 	}
+	return fromEventModel(&m)
+}
 
-	ids, err := s.zRangeByScoreIDs(ctx, zEventAll, minScore, maxScore)
+func (s *Store) ListEvents(ctx context.Context, opts event.ListOpts) ([]*event.Event, error) {
+	lo, hi := eventScores(opts)
+	result, err := seekPage(ctx, s, zEventAll, lo, hi, opts.Cursor, !opts.Ascending, opts.Offset, opts.Limit, s.eventLoader(ctx, opts))
 	if err != nil {
 		return nil, fmt.Errorf("relay/redis: list events: %w", err)
 	}
+	return result, nil
+}
 
-	result := make([]*event.Event, 0, len(ids))
-	for i := len(ids) - 1; i >= 0; i-- { // reverse for DESC order
-		var m eventModel
-		if err := s.getEntity(ctx, entityKey(prefixEvent, ids[i]), &m); err != nil {
-			if isNotFound(err) {
-				continue
-			}
-			return nil, err
-		}
-		if opts.Type != "" && m.Type != opts.Type {
-			continue
-		}
-		evt, err := fromEventModel(&m)
-		if err != nil {
-			return nil, err
-		}
-		result = append(result, evt)
+func (s *Store) CountEvents(ctx context.Context, opts event.ListOpts) (int64, error) {
+	lo, hi := eventScores(opts)
+	count, err := seekCount(ctx, s, zEventAll, lo, hi, eventFiltered(opts), s.eventLoader(ctx, opts))
+	if err != nil {
+		return 0, fmt.Errorf("relay/redis: count events: %w", err)
 	}
-
-	return applyPagination(result, opts.Offset, opts.Limit), nil
+	return count, nil
 }
 
 func (s *Store) ListEventsByTenant(ctx context.Context, tenantID string, opts event.ListOpts) ([]*event.Event, error) {
-	ids, err := s.rdb.ZRange(ctx, zEventTenant+tenantID, 0, -1).Result()
+	lo, hi := eventScores(opts)
+	result, err := seekPage(ctx, s, zEventTenant+tenantID, lo, hi, opts.Cursor, !opts.Ascending, opts.Offset, opts.Limit, s.eventLoader(ctx, opts))
 	if err != nil {
 		return nil, fmt.Errorf("relay/redis: list events by tenant: %w", err)
 	}
+	return result, nil
+}
+
+func (s *Store) CountEventsByTenant(ctx context.Context, tenantID string, opts event.ListOpts) (int64, error) {
+	lo, hi := eventScores(opts)
+	count, err := seekCount(ctx, s, zEventTenant+tenantID, lo, hi, eventFiltered(opts), s.eventLoader(ctx, opts))
+	if err != nil {
+		return 0, fmt.Errorf("relay/redis: count events by tenant: %w", err)
+	}
+	return count, nil
+}
+
+// eventScores returns the score range of the event indexes, which are
+// scored by creation time, that opts covers.
+func eventScores(opts event.ListOpts) (lo, hi float64) {
+	lo, hi = math.Inf(-1), math.Inf(1)
+	if opts.From != nil {
+		lo = scoreFromTime(*opts.From)
+	}
+	if opts.To != nil {
+		hi = scoreFromTime(*opts.To)
+	}
+	return lo, hi
+}
 
-	result := make([]*event.Event, 0, len(ids))
-	for i := len(ids) - 1; i >= 0; i-- { // reverse for DESC order
+// eventFiltered reports whether opts filters events on more than the
+// score range, so counting them needs each event loaded.
+func eventFiltered(opts event.ListOpts) bool {
+	return opts.Type != "" || opts.ExcludeTest || opts.ScopeAppID != "" || opts.ScopeOrgID != ""
+}
+
+// eventLoader returns a seekPage loader that fetches an event and keeps it
+// only if it matches opts.
+func (s *Store) eventLoader(ctx context.Context, opts event.ListOpts) func(string) (*event.Event, error) {
+	return func(evtID string) (*event.Event, error) {
 		var m eventModel
-		if err := s.getEntity(ctx, entityKey(prefixEvent, ids[i]), &m); err != nil {
+		if err := s.getEntity(ctx, entityKey(prefixEvent, evtID), &m); err != nil {
 			if isNotFound(err) {
-				continue
+				return nil, nil //nolint:nilnil // skipped: the event is gone
 			}
 			return nil, err
 		}
 		if opts.Type != "" && m.Type != opts.Type {
-			continue
+			return nil, nil //nolint:nilnil // filtered out
 		}
-		evt, err := fromEventModel(&m)
-		if err != nil {
-			return nil, err
+		if opts.ExcludeTest && m.Test {
+			return nil, nil //nolint:nilnil // filtered out
 		}
-		result = append(result, evt)
+		if !scope.Matches(m.ScopeAppID, m.ScopeOrgID, opts.ScopeAppID, opts.ScopeOrgID) {
+			return nil, nil //nolint:nilnil // filtered out
+		}
+		return fromEventModel(&m)
 	}
-
-	return applyPagination(result, opts.Offset, opts.Limit), nil
 }
@@ -7,12 +7,15 @@ const (
 	prefixEvent     = "relay:evt:"
 	prefixDelivery  = "relay:del:"
 	prefixDLQ       = "relay:dlq:"
+	prefixAPIKey    = "relay:key:"
 )
 
 // Key prefixes for unique indexes.
 const (
 	uniqueEventTypeName = "relay:u:evtype:name:"
 	uniqueEventIdem     = "relay:u:evt:idem:"
+	uniqueAPIKeyHash    = "relay:u:key:hash:"
+	uniqueDLQReplay     = "relay:u:dlq:replay:" // + DLQ entry ID; claimed by Replay
 )
 
 // Key prefixes for sorted set indexes.
@@ -28,6 +31,8 @@ const (
 	zDLQAll         = "relay:z:dlq:all"
 	zDLQTenant      = "relay:z:dlq:tenant:" // + tenant ID
 	zDLQEndpoint    = "relay:z:dlq:ep:"     // + endpoint ID
+	zAPIKeyAll      = "relay:z:key:all"
+	zAPIKeyTenant   = "relay:z:key:tenant:" // + tenant ID
 )
 
 // Key prefixes for set indexes.
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"sort"
 	"strconv"
 	"time"
 
@@ -14,6 +15,8 @@ import (
 	"github.com/xraph/grove/kv"
 	"github.com/xraph/grove/kv/drivers/redisdriver"
 
+	"github.com/xraph/relay/endpoint"
+	"github.com/xraph/relay/id"
 	relaystore "github.com/xraph/relay/store"
 )
 
@@ -24,14 +27,18 @@ var _ relaystore.Store = (*Store)(nil)
 type Store struct {
 	kv  *kv.Store
 	rdb goredis.UniversalClient
+
+	index *endpoint.Index // subscription index backing Resolve
 }
 
 // New creates a new Redis store backed by Grove KV.
 func New(store *kv.Store) *Store {
-	return &Store{
+	s := &Store{
 		kv:  store,
 		rdb: redisdriver.UnwrapClient(store),
 	}
+	s.index = endpoint.NewIndex(s.tenantEndpoints, endpoint.DefaultIndexTTL)
+	return s
 }
 
 // Migrate is a no-op for Redis (no schema migrations needed).
@@ -87,22 +94,174 @@ func (s *Store) setEntity(ctx context.Context, key string, value any) error {
 	return s.kv.SetRaw(ctx, key, raw)
 }
 
+// scoreArg formats a sorted set score bound for ZRANGEBYSCORE.
+func scoreArg(f float64) string {
+	switch {
+	case math.IsInf(f, -1):
+		return "-inf"
+	case math.IsInf(f, 1):
+		return "+inf"
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
 // zRangeByScoreIDs returns all member IDs from a sorted set within a score range.
 func (s *Store) zRangeByScoreIDs(ctx context.Context, key string, lo, hi float64) ([]string, error) {
-	minStr := "-inf"
-	maxStr := "+inf"
-	if !math.IsInf(lo, -1) {
-		minStr = strconv.FormatFloat(lo, 'f', -1, 64)
-	}
-	if !math.IsInf(hi, 1) {
-		maxStr = strconv.FormatFloat(hi, 'f', -1, 64)
-	}
 	return s.rdb.ZRangeByScore(ctx, key, &goredis.ZRangeBy{
-		Min: minStr,
-		Max: maxStr,
+		Min: scoreArg(lo),
+		Max: scoreArg(hi),
 	}).Result()
 }
 
+// seekBatch is how many members seekIDs reads per call when the page size
+// does not bound the walk.
+const seekBatch = 100
+
+// seekCursor is the position in a sorted set a walk starts after.
+type seekCursor struct {
+	member string
+	score  float64
+}
+
+// cursorIn returns the position of cursor in the sorted set key: its own
+// score, or the time in its ID once it has left the set. A nil cursor has
+// no position.
+func (s *Store) cursorIn(ctx context.Context, key string, cursor id.ID) (*seekCursor, error) {
+	if cursor.IsNil() {
+		return nil, nil //nolint:nilnil // walk from the start
+	}
+	score, err := s.rdb.ZScore(ctx, key, cursor.String()).Result()
+	if isRedisNil(err) {
+		return &seekCursor{member: cursor.String(), score: scoreFromTime(cursor.Time())}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &seekCursor{member: cursor.String(), score: score}, nil
+}
+
+// seekIDs walks the members of the sorted set key scored within [lo, hi]
+// in (score, member) order, descending if desc, starting after the after
+// position. The sets are scored by creation time, so this is the order of
+// the time-sortable IDs. It reads batch members per ZRANGEBYSCORE ...
+// LIMIT call, seeking on from the last score read, and calls fn with each
+// member until fn returns false, so a walk only costs the members it reads.
+func (s *Store) seekIDs(ctx context.Context, key string, lo, hi float64, after *seekCursor, desc bool, batch int, fn func(member string) (bool, error)) error {
+	if after != nil {
+		if desc {
+			hi = math.Min(hi, after.score)
+		} else {
+			lo = math.Max(lo, after.score)
+		}
+	}
+	if batch <= 0 {
+		batch = seekBatch
+	}
+
+	var offset int64 // members already read at the current bound score
+	for {
+		zs, err := s.rdb.ZRangeArgsWithScores(ctx, goredis.ZRangeArgs{
+			Key:     key,
+			Start:   scoreArg(lo),
+			Stop:    scoreArg(hi),
+			ByScore: true,
+			Rev:     desc,
+			Offset:  offset,
+			Count:   int64(batch),
+		}).Result()
+		if err != nil {
+			return err
+		}
+		for _, z := range zs {
+			member, _ := z.Member.(string) //nolint:errcheck // members are always strings
+			// Members tied with the start position's score sort by
+			// member; skip it and those before it.
+			if after != nil && z.Score == after.score &&
+				(member == after.member || (member < after.member) != desc) {
+				continue
+			}
+			more, err := fn(member)
+			if err != nil || !more {
+				return err
+			}
+		}
+		if len(zs) < batch {
+			return nil
+		}
+
+		last := zs[len(zs)-1].Score
+		if (desc && last == hi) || (!desc && last == lo) {
+			offset += int64(len(zs))
+			continue
+		}
+		offset = 0
+		for i := len(zs) - 1; i >= 0 && zs[i].Score == last; i-- {
+			offset++
+		}
+		if desc {
+			hi = last
+		} else {
+			lo = last
+		}
+	}
+}
+
+// seekPage returns the page of the entities indexed by the sorted set key
+// that follow cursor, seeking the set as seekIDs does. load fetches the
+// entity for a member and returns nil for one that is gone or filtered
+// out; the first offset loaded entities are skipped and the walk stops
+// once limit are collected.
+func seekPage[T any](ctx context.Context, s *Store, key string, lo, hi float64, cursor id.ID, desc bool, offset, limit int, load func(member string) (*T, error)) ([]*T, error) {
+	after, err := s.cursorIn(ctx, key, cursor)
+	if err != nil {
+		return nil, err
+	}
+	return seekPageAfter(ctx, s, key, lo, hi, after, desc, offset, limit, load)
+}
+
+// seekPageAfter is seekPage starting after a resolved position.
+func seekPageAfter[T any](ctx context.Context, s *Store, key string, lo, hi float64, after *seekCursor, desc bool, offset, limit int, load func(member string) (*T, error)) ([]*T, error) {
+	var items []*T
+	batch := 0
+	if limit > 0 {
+		batch = offset + limit
+	}
+	err := s.seekIDs(ctx, key, lo, hi, after, desc, batch, func(member string) (bool, error) {
+		item, err := load(member)
+		if err != nil || item == nil {
+			return true, err
+		}
+		if offset > 0 {
+			offset--
+			return true, nil
+		}
+		items = append(items, item)
+		return limit <= 0 || len(items) < limit, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// seekCount counts the entities indexed by the sorted set key scored
+// within [lo, hi] that load returns. Without a filter to apply it only
+// takes a ZCOUNT.
+func seekCount[T any](ctx context.Context, s *Store, key string, lo, hi float64, filtered bool, load func(member string) (*T, error)) (int64, error) {
+	if !filtered {
+		return s.rdb.ZCount(ctx, key, scoreArg(lo), scoreArg(hi)).Result()
+	}
+	var n int64
+	err := s.seekIDs(ctx, key, lo, hi, nil, false, 0, func(member string) (bool, error) {
+		item, err := load(member)
+		if item != nil {
+			n++
+		}
+		return true, err
+	})
+	return n, err
+}
+
 // applyPagination applies offset and limit to a slice.
 func applyPagination[T any](items []*T, offset, limit int) []*T {
 	if offset > 0 && offset < len(items) {
@@ -115,3 +274,45 @@ func applyPagination[T any](items []*T, offset, limit int) []*T {
 	}
 	return items
 }
+
+// pageByID orders items by the ID key returns, descending if desc, and
+// returns the page after cursor with offset and limit applied.
+func pageByID[T any](items []*T, key func(*T) id.ID, cursor id.ID, desc bool, offset, limit int) []*T {
+	sort.Slice(items, func(i, j int) bool {
+		if desc {
+			return key(items[i]).String() > key(items[j]).String()
+		}
+		return key(items[i]).String() < key(items[j]).String()
+	})
+	if !cursor.IsNil() {
+		c := cursor.String()
+		n := sort.Search(len(items), func(i int) bool {
+			if desc {
+				return key(items[i]).String() < c
+			}
+			return key(items[i]).String() > c
+		})
+		items = items[n:]
+	}
+	return applyPagination(items, offset, limit)
+}
+
+// optionalID returns the stored form of an optional ID: empty for nil.
+func optionalID(i *id.ID) string {
+	if i == nil {
+		return ""
+	}
+	return i.String()
+}
+
+// parseOptionalID parses an optional ID stored by optionalID.
+func parseOptionalID(s string, parse func(string) (id.ID, error)) (*id.ID, error) {
+	if s == "" {
+		return nil, nil //nolint:nilnil // no ID stored
+	}
+	i, err := parse(s)
+	if err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
@@ -0,0 +1,11 @@
+package sqlite_test
+
+import (
+	"testing"
+
+	"github.com/xraph/relay/internal/storetest"
+)
+
+func TestRegisterKeepsDeprecation(t *testing.T) {
+	storetest.RegisterKeepsDeprecation(t, openSqliteStore(t))
+}
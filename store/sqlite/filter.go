@@ -0,0 +1,45 @@
+package sqlite
+
+import "github.com/xraph/relay/id"
+
+// condition is one WHERE clause with its arguments.
+type condition struct {
+	expr string
+	args []any
+}
+
+// filter collects the WHERE clauses of a list query so the same conditions
+// can back both the list and its count.
+type filter []condition
+
+// add appends a condition taking one ? argument.
+func (f *filter) add(expr string, arg any) {
+	*f = append(*f, condition{expr: expr, args: []any{arg}})
+}
+
+// raw appends a condition without arguments.
+func (f *filter) raw(expr string) {
+	*f = append(*f, condition{expr: expr})
+}
+
+// after returns the filter narrowed to the rows past cursor in ID order.
+func (f filter) after(cursor id.ID, desc bool) filter {
+	if cursor.IsNil() {
+		return f
+	}
+	op := ">"
+	if desc {
+		op = "<"
+	}
+	f = append(filter(nil), f...)
+	f.add("id "+op+" ?", cursor.String())
+	return f
+}
+
+// orderByID returns the ORDER BY expression matching after.
+func orderByID(desc bool) string {
+	if desc {
+		return "id DESC"
+	}
+	return "id ASC"
+}
@@ -0,0 +1,218 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/xraph/grove/drivers/sqlitedriver"
+
+	relay "github.com/xraph/relay"
+	"github.com/xraph/relay/delivery"
+	"github.com/xraph/relay/event"
+	"github.com/xraph/relay/id"
+	relaystore "github.com/xraph/relay/store"
+)
+
+var (
+	_ relaystore.TxOutbox    = (*Store)(nil)
+	_ relaystore.BatchOutbox = (*Store)(nil)
+)
+
+// CreateEventWithDeliveries persists an event and its deliveries in one
+// transaction. A duplicate idempotency key rolls the whole write back.
+func (s *Store) CreateEventWithDeliveries(ctx context.Context, evt *event.Event, ds []*delivery.Delivery) error {
+	tx, err := s.sdb.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("relay/sqlite: begin outbox tx: %w", err)
+	}
+
+	if err := writeOutbox(ctx, tx, evt, ds); err != nil {
+		_ = tx.Rollback() //nolint:errcheck // the write error is the one worth reporting
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("relay/sqlite: commit outbox tx: %w", err)
+	}
+
+	return nil
+}
+
+// CreateEventWithDeliveriesTx persists an event and its deliveries inside
+// the caller's *sqlitedriver.SqliteTx.
+func (s *Store) CreateEventWithDeliveriesTx(ctx context.Context, tx any, evt *event.Event, ds []*delivery.Delivery) error {
+	stx, ok := tx.(*sqlitedriver.SqliteTx)
+	if !ok {
+		return fmt.Errorf("%w: sqlite store needs *sqlitedriver.SqliteTx, got %T", relay.ErrTxUnsupported, tx)
+	}
+
+	return writeOutbox(ctx, stx, evt, ds)
+}
+
+// writeOutbox inserts the event and, unless its idempotency key was already
+// used, its deliveries.
+func writeOutbox(ctx context.Context, tx *sqlitedriver.SqliteTx, evt *event.Event, ds []*delivery.Delivery) error {
+	m := toEventModel(evt)
+
+	if evt.IdempotencyKey != "" {
+		res, err := tx.NewInsert(m).
+			OnConflict("(idempotency_key) WHERE idempotency_key != '' DO NOTHING").
+			Exec(ctx)
+		if err != nil {
+			return err
+		}
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return relay.ErrDuplicateIdempotencyKey
+		}
+	} else if _, err := tx.NewInsert(m).Exec(ctx); err != nil {
+		return err
+	}
+
+	models := make([]deliveryModel, len(ds))
+	for i, d := range ds {
+		models[i] = *toDeliveryModel(d)
+	}
+	for chunk := range slices.Chunk(models, rowsPerInsert[deliveryModel]()) {
+		if _, err := tx.NewInsert(&chunk).Exec(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateEventBatch persists events and their deliveries in one transaction
+// using multi-row inserts, split to stay under maxBindParams. Events
+// whose idempotency key is already stored, or repeated within the batch,
+// are skipped.
+func (s *Store) CreateEventBatch(ctx context.Context, evts []*event.Event, ds []*delivery.Delivery) ([]id.ID, error) {
+	if len(evts) == 0 {
+		return nil, nil
+	}
+
+	tx, err := s.sdb.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("relay/sqlite: begin batch tx: %w", err)
+	}
+
+	duplicates, err := writeEventBatch(ctx, tx, evts, ds)
+	if err != nil {
+		_ = tx.Rollback() //nolint:errcheck // the write error is the one worth reporting
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("relay/sqlite: commit batch tx: %w", err)
+	}
+	return duplicates, nil
+}
+
+// writeEventBatch inserts the events and the deliveries of those whose
+// idempotency key is new. Keys taken by stored events, including ones
+// committed concurrently, are left to the unique index: the conflicting
+// rows are skipped rather than failing the batch.
+func writeEventBatch(ctx context.Context, tx *sqlitedriver.SqliteTx, evts []*event.Event, ds []*delivery.Delivery) ([]id.ID, error) {
+	skipped := make(map[string]bool)
+	seen := make(map[string]bool)
+	events := make([]eventModel, 0, len(evts))
+	for _, evt := range evts {
+		if evt.IdempotencyKey != "" {
+			if seen[evt.IdempotencyKey] {
+				skipped[evt.ID.String()] = true
+				continue
+			}
+			seen[evt.IdempotencyKey] = true
+		}
+		events = append(events, *toEventModel(evt))
+	}
+
+	for chunk := range slices.Chunk(events, rowsPerInsert[eventModel]()) {
+		res, err := tx.NewInsert(&chunk).
+			OnConflict("(idempotency_key) WHERE idempotency_key != '' DO NOTHING").
+			Exec(ctx)
+		if err != nil {
+			return nil, err
+		}
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		if rows < int64(len(chunk)) {
+			if err := skipConflicts(ctx, tx, chunk, skipped); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var duplicates []id.ID
+	for _, evt := range evts {
+		if skipped[evt.ID.String()] {
+			duplicates = append(duplicates, evt.ID)
+		}
+	}
+
+	deliveries := make([]deliveryModel, 0, len(ds))
+	for _, d := range ds {
+		if !skipped[d.EventID.String()] {
+			deliveries = append(deliveries, *toDeliveryModel(d))
+		}
+	}
+	for chunk := range slices.Chunk(deliveries, rowsPerInsert[deliveryModel]()) {
+		if _, err := tx.NewInsert(&chunk).Exec(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return duplicates, nil
+}
+
+// skipConflicts adds to skipped the events the insert left out because
+// their idempotency key was already stored.
+func skipConflicts(ctx context.Context, tx *sqlitedriver.SqliteTx, events []eventModel, skipped map[string]bool) error {
+	args := make([]any, len(events))
+	for i := range events {
+		args[i] = events[i].ID
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(args)), ",")
+
+	var stored []eventModel
+	if err := tx.NewSelect(&stored).
+		Where("id IN ("+placeholders+")", args...).
+		Scan(ctx); err != nil {
+		return err
+	}
+
+	inserted := make(map[string]bool, len(stored))
+	for i := range stored {
+		inserted[stored[i].ID] = true
+	}
+	for i := range events {
+		if !inserted[events[i].ID] {
+			skipped[events[i].ID] = true
+		}
+	}
+	return nil
+}
+
+// maxBindParams is SQLite's limit on the parameters of one statement.
+const maxBindParams = 32766
+
+// rowsPerInsert returns how many rows of model T fit in one multi-row
+// insert without exceeding maxBindParams.
+func rowsPerInsert[T any]() int {
+	t := reflect.TypeFor[T]()
+	columns := 0
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("grove"), ",")
+		if name != "" && name != "-" && !strings.HasPrefix(name, "table:") {
+			columns++
+		}
+	}
+	return maxBindParams / max(columns, 1)
+}
@@ -0,0 +1,161 @@
+package sqlite_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/xraph/grove/drivers/sqlitedriver"
+
+	"github.com/xraph/relay"
+	"github.com/xraph/relay/catalog"
+	"github.com/xraph/relay/delivery"
+	"github.com/xraph/relay/endpoint"
+	"github.com/xraph/relay/event"
+	"github.com/xraph/relay/id"
+	"github.com/xraph/relay/internal/entity"
+	sqlitestore "github.com/xraph/relay/store/sqlite"
+)
+
+func outboxEvent(key string) *event.Event {
+	return &event.Event{
+		Entity:         entity.New(),
+		ID:             id.NewEventID(),
+		Type:           "order.created",
+		TenantID:       "acme",
+		Data:           map[string]any{"id": "ord_1"},
+		IdempotencyKey: key,
+	}
+}
+
+func outboxDelivery(evtID id.ID) *delivery.Delivery {
+	return &delivery.Delivery{
+		Entity:        entity.New(),
+		ID:            id.NewDeliveryID(),
+		EventID:       evtID,
+		EndpointID:    id.NewEndpointID(),
+		State:         delivery.StatePending,
+		MaxAttempts:   5,
+		NextAttemptAt: time.Now().UTC(),
+	}
+}
+
+// assertNotStored fails unless neither the event nor any delivery for it
+// was written.
+func assertNotStored(t *testing.T, s *sqlitestore.Store, evtID id.ID) {
+	t.Helper()
+	ctx := context.Background()
+
+	if _, err := s.GetEvent(ctx, evtID); !errors.Is(err, relay.ErrEventNotFound) {
+		t.Fatalf("expected event %s not to be stored, got %v", evtID, err)
+	}
+	ds, err := s.ListByEvent(ctx, evtID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ds) != 0 {
+		t.Fatalf("expected no deliveries for event %s, got %d", evtID, len(ds))
+	}
+}
+
+func TestCreateEventWithDeliveriesRollsBackOnDeliveryFailure(t *testing.T) {
+	s := openSqliteStore(t)
+	ctx := context.Background()
+
+	evt := outboxEvent("")
+	d := outboxDelivery(evt.ID)
+	dup := outboxDelivery(evt.ID)
+	dup.ID = d.ID // fails the delivery insert on the primary key
+
+	if err := s.CreateEventWithDeliveries(ctx, evt, []*delivery.Delivery{d, dup}); err == nil {
+		t.Fatal("expected the delivery insert to fail")
+	}
+	assertNotStored(t, s, evt.ID)
+}
+
+func TestCreateEventWithDeliveriesDuplicateKey(t *testing.T) {
+	s := openSqliteStore(t)
+	ctx := context.Background()
+
+	first := outboxEvent("order-1")
+	if err := s.CreateEventWithDeliveries(ctx, first, []*delivery.Delivery{outboxDelivery(first.ID)}); err != nil {
+		t.Fatal(err)
+	}
+
+	second := outboxEvent("order-1")
+	err := s.CreateEventWithDeliveries(ctx, second, []*delivery.Delivery{outboxDelivery(second.ID)})
+	if !errors.Is(err, relay.ErrDuplicateIdempotencyKey) {
+		t.Fatalf("expected ErrDuplicateIdempotencyKey, got %v", err)
+	}
+	assertNotStored(t, s, second.ID)
+
+	ds, err := s.ListByEvent(ctx, first.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ds) != 1 {
+		t.Fatalf("expected the first event to keep its delivery, got %d", len(ds))
+	}
+}
+
+func TestSendTxJoinsCallerTransaction(t *testing.T) {
+	db, s := openSqliteDB(t)
+	ctx := context.Background()
+
+	r, err := relay.New(relay.WithStore(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.RegisterEventType(ctx, catalog.WebhookDefinition{Name: "order.created"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Endpoints().Create(ctx, endpoint.Input{
+		TenantID:   "acme",
+		URL:        "https://example.com/hooks",
+		EventTypes: []string{"order.*"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// sendTx sends an event in a new transaction, then commits or rolls
+	// it back.
+	sendTx := func(commit bool) *event.Event {
+		t.Helper()
+		tx, err := sqlitedriver.Unwrap(db).BeginTx(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		evt := &event.Event{Type: "order.created", TenantID: "acme"}
+		if err := r.SendTx(ctx, tx, evt); err != nil {
+			_ = tx.Rollback() //nolint:errcheck // the send error is the one worth reporting
+			t.Fatalf("send tx: %v", err)
+		}
+		if commit {
+			err = tx.Commit()
+		} else {
+			err = tx.Rollback()
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		return evt
+	}
+
+	// A rolled-back caller transaction leaves neither the event nor its
+	// deliveries.
+	assertNotStored(t, s, sendTx(false).ID)
+
+	// A committed one stores both.
+	evt := sendTx(true)
+	if _, err := s.GetEvent(ctx, evt.ID); err != nil {
+		t.Fatalf("expected the committed event to be stored, got %v", err)
+	}
+	ds, err := s.ListByEvent(ctx, evt.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ds) != 1 {
+		t.Fatalf("expected 1 delivery, got %d", len(ds))
+	}
+}
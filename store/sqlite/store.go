@@ -12,13 +12,13 @@ import (
 	"github.com/xraph/grove/migrate"
 
 	relay "github.com/xraph/relay"
+	"github.com/xraph/relay/auth"
 	"github.com/xraph/relay/catalog"
 	"github.com/xraph/relay/delivery"
 	"github.com/xraph/relay/dlq"
 	"github.com/xraph/relay/endpoint"
 	"github.com/xraph/relay/event"
 	"github.com/xraph/relay/id"
-	"github.com/xraph/relay/internal/entity"
 	relaystore "github.com/xraph/relay/store"
 )
 
@@ -29,14 +29,18 @@ var _ relaystore.Store = (*Store)(nil)
 type Store struct {
 	db  *grove.DB
 	sdb *sqlitedriver.SqliteDB
+
+	index *endpoint.Index // subscription index backing Resolve
 }
 
 // New creates a new SQLite store backed by Grove ORM.
 func New(db *grove.DB) *Store {
-	return &Store{
+	s := &Store{
 		db:  db,
 		sdb: sqlitedriver.Unwrap(db),
 	}
+	s.index = endpoint.NewIndex(s.tenantEndpoints, endpoint.DefaultIndexTTL)
+	return s
 }
 
 // DB returns the underlying grove database for direct access.
@@ -77,10 +81,9 @@ func (s *Store) RegisterType(ctx context.Context, et *catalog.EventType) error {
 		Set("schema_version = EXCLUDED.schema_version").
 		Set("version = EXCLUDED.version").
 		Set("example = EXCLUDED.example").
+		Set("versions = EXCLUDED.versions").
 		Set("scope_app_id = EXCLUDED.scope_app_id").
 		Set("metadata = EXCLUDED.metadata").
-		Set("is_deprecated = 0").
-		Set("deprecated_at = NULL").
 		Set("updated_at = EXCLUDED.updated_at").
 		Exec(ctx)
 	return err
@@ -117,12 +120,8 @@ func (s *Store) GetTypeByID(ctx context.Context, etID id.ID) (*catalog.EventType
 func (s *Store) ListTypes(ctx context.Context, opts catalog.ListOpts) ([]*catalog.EventType, error) {
 	var models []eventTypeModel
 	q := s.sdb.NewSelect(&models)
-
-	if opts.Group != "" {
-		q = q.Where("group_name = ?", opts.Group)
-	}
-	if !opts.IncludeDeprecated {
-		q = q.Where("is_deprecated = 0")
+	for _, c := range typeFilter(opts).after(opts.Cursor, false) {
+		q = q.Where(c.expr, c.args...)
 	}
 	if opts.Limit > 0 {
 		q = q.Limit(opts.Limit)
@@ -130,7 +129,7 @@ func (s *Store) ListTypes(ctx context.Context, opts catalog.ListOpts) ([]*catalo
 	if opts.Offset > 0 {
 		q = q.Offset(opts.Offset)
 	}
-	q = q.OrderExpr("created_at ASC")
+	q = q.OrderExpr("id ASC")
 
 	if err := q.Scan(ctx); err != nil {
 		return nil, err
@@ -147,14 +146,80 @@ func (s *Store) ListTypes(ctx context.Context, opts catalog.ListOpts) ([]*catalo
 	return result, nil
 }
 
+func (s *Store) CountTypes(ctx context.Context, opts catalog.ListOpts) (int64, error) {
+	q := s.sdb.NewSelect((*eventTypeModel)(nil))
+	for _, c := range typeFilter(opts) {
+		q = q.Where(c.expr, c.args...)
+	}
+	return q.Count(ctx)
+}
+
+func typeFilter(opts catalog.ListOpts) filter {
+	var f filter
+	if opts.Group != "" {
+		f.add("group_name = ?", opts.Group)
+	}
+	if opts.ScopeAppID != "" {
+		f.add("scope_app_id = ?", opts.ScopeAppID)
+	}
+	if !opts.IncludeDeprecated {
+		f.raw("is_deprecated = 0")
+	}
+	return f
+}
+
 func (s *Store) DeleteType(ctx context.Context, name string) error {
 	t := now()
 	res, err := s.sdb.NewUpdate((*eventTypeModel)(nil)).
 		Set("is_deprecated = ?", true).
-		Set("deprecated_at = ?", t).
+		Set("deprecated_at = COALESCE(deprecated_at, ?)", t).
+		Set("sunset_at = ?", t).
 		Set("updated_at = ?", t).
 		Where("name = ?", name).
-		Where("is_deprecated = 0").
+		Where("(is_deprecated = 0 OR sunset_at > ?)", t).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return relay.ErrEventTypeNotFound
+	}
+	return nil
+}
+
+func (s *Store) DeprecateType(ctx context.Context, name string, sunsetAt time.Time) error {
+	t := now()
+	res, err := s.sdb.NewUpdate((*eventTypeModel)(nil)).
+		Set("is_deprecated = ?", true).
+		Set("deprecated_at = COALESCE(deprecated_at, ?)", t).
+		Set("sunset_at = ?", sunsetAt).
+		Set("updated_at = ?", t).
+		Where("name = ?", name).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return relay.ErrEventTypeNotFound
+	}
+	return nil
+}
+
+func (s *Store) UndeprecateType(ctx context.Context, name string) error {
+	res, err := s.sdb.NewUpdate((*eventTypeModel)(nil)).
+		Set("is_deprecated = ?", false).
+		Set("deprecated_at = NULL").
+		Set("sunset_at = NULL").
+		Set("updated_at = ?", now()).
+		Where("name = ?", name).
 		Exec(ctx)
 	if err != nil {
 		return err
@@ -194,8 +259,11 @@ func (s *Store) MatchTypes(ctx context.Context, pattern string) ([]*catalog.Even
 
 func (s *Store) CreateEndpoint(ctx context.Context, ep *endpoint.Endpoint) error {
 	m := toEndpointModel(ep)
-	_, err := s.sdb.NewInsert(m).Exec(ctx)
-	return err
+	if _, err := s.sdb.NewInsert(m).Exec(ctx); err != nil {
+		return err
+	}
+	s.index.Invalidate(ep.TenantID)
+	return nil
 }
 
 func (s *Store) GetEndpoint(ctx context.Context, epID id.ID) (*endpoint.Endpoint, error) {
@@ -228,6 +296,8 @@ func (s *Store) UpdateEndpoint(ctx context.Context, ep *endpoint.Endpoint) error
 	if rows == 0 {
 		return relay.ErrEndpointNotFound
 	}
+	s.index.InvalidateEndpoint(m.ID)
+	s.index.Invalidate(ep.TenantID)
 	return nil
 }
 
@@ -245,19 +315,23 @@ func (s *Store) DeleteEndpoint(ctx context.Context, epID id.ID) error {
 	if rows == 0 {
 		return relay.ErrEndpointNotFound
 	}
+	s.index.InvalidateEndpoint(epID.String())
 	return nil
 }
 
 func (s *Store) ListEndpoints(ctx context.Context, tenantID string, opts endpoint.ListOpts) ([]*endpoint.Endpoint, error) {
 	var models []endpointModel
-	q := s.sdb.NewSelect(&models).Where("tenant_id = ?", tenantID)
+	q := s.sdb.NewSelect(&models)
+	for _, c := range endpointFilter(tenantID, opts).after(opts.Cursor, false) {
+		q = q.Where(c.expr, c.args...)
+	}
 	if opts.Limit > 0 {
 		q = q.Limit(opts.Limit)
 	}
 	if opts.Offset > 0 {
 		q = q.Offset(opts.Offset)
 	}
-	q = q.OrderExpr("created_at ASC")
+	q = q.OrderExpr("id ASC")
 
 	if err := q.Scan(ctx); err != nil {
 		return nil, err
@@ -274,18 +348,40 @@ func (s *Store) ListEndpoints(ctx context.Context, tenantID string, opts endpoin
 	return result, nil
 }
 
-func (s *Store) Resolve(ctx context.Context, tenantID, eventType string) ([]*endpoint.Endpoint, error) {
+func (s *Store) CountEndpoints(ctx context.Context, tenantID string, opts endpoint.ListOpts) (int64, error) {
+	q := s.sdb.NewSelect((*endpointModel)(nil))
+	for _, c := range endpointFilter(tenantID, opts) {
+		q = q.Where(c.expr, c.args...)
+	}
+	return q.Count(ctx)
+}
+
+func endpointFilter(tenantID string, opts endpoint.ListOpts) filter {
+	var f filter
+	f.add("tenant_id = ?", tenantID)
+	if opts.Enabled != nil {
+		f.add("enabled = ?", *opts.Enabled)
+	}
+	if opts.ScopeAppID != "" {
+		f.add("scope_app_id = ?", opts.ScopeAppID)
+	}
+	if opts.ScopeOrgID != "" {
+		f.add("scope_org_id = ?", opts.ScopeOrgID)
+	}
+	return f
+}
+
+func (s *Store) ListSubscribers(ctx context.Context, eventType string) ([]*endpoint.Endpoint, error) {
 	var models []endpointModel
 	if err := s.sdb.NewSelect(&models).
-		Where("tenant_id = ?", tenantID).
-		Where("enabled = 1").
+		OrderExpr("created_at ASC").
 		Scan(ctx); err != nil {
 		return nil, err
 	}
 
 	var result []*endpoint.Endpoint
 	for i := range models {
-		for _, pattern := range models[i].eventTypes() {
+		for _, pattern := range models[i].EventTypes {
 			if catalog.Match(pattern, eventType) {
 				ep, err := fromEndpointModel(&models[i])
 				if err != nil {
@@ -299,10 +395,58 @@ func (s *Store) Resolve(ctx context.Context, tenantID, eventType string) ([]*end
 	return result, nil
 }
 
+func (s *Store) ListAutoDisabled(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	var models []endpointModel
+	if err := s.sdb.NewSelect(&models).
+		Where("enabled = 0").
+		Where("disabled_reason != ''").
+		OrderExpr("created_at ASC").
+		Scan(ctx); err != nil {
+		return nil, err
+	}
+
+	result := make([]*endpoint.Endpoint, 0, len(models))
+	for i := range models {
+		ep, err := fromEndpointModel(&models[i])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, ep)
+	}
+	return result, nil
+}
+
+func (s *Store) Resolve(ctx context.Context, tenantID, eventType string) ([]*endpoint.Endpoint, error) {
+	return s.index.Resolve(ctx, tenantID, eventType)
+}
+
+// tenantEndpoints loads every endpoint of a tenant for the subscription index.
+func (s *Store) tenantEndpoints(ctx context.Context, tenantID string) ([]*endpoint.Endpoint, error) {
+	var models []endpointModel
+	if err := s.sdb.NewSelect(&models).
+		Where("tenant_id = ?", tenantID).
+		OrderExpr("created_at ASC").
+		Scan(ctx); err != nil {
+		return nil, err
+	}
+
+	result := make([]*endpoint.Endpoint, len(models))
+	for i := range models {
+		ep, err := fromEndpointModel(&models[i])
+		if err != nil {
+			return nil, err
+		}
+		result[i] = ep
+	}
+	return result, nil
+}
+
 func (s *Store) SetEnabled(ctx context.Context, epID id.ID, enabled bool) error {
 	t := now()
 	res, err := s.sdb.NewUpdate((*endpointModel)(nil)).
 		Set("enabled = ?", enabled).
+		Set("disabled_reason = ''").
+		Set("disabled_at = NULL").
 		Set("updated_at = ?", t).
 		Where("id = ?", epID.String()).
 		Exec(ctx)
@@ -316,6 +460,29 @@ func (s *Store) SetEnabled(ctx context.Context, epID id.ID, enabled bool) error
 	if rows == 0 {
 		return relay.ErrEndpointNotFound
 	}
+	s.index.InvalidateEndpoint(epID.String())
+	return nil
+}
+
+func (s *Store) DisableEndpoint(ctx context.Context, epID id.ID, reason string, at time.Time) error {
+	res, err := s.sdb.NewUpdate((*endpointModel)(nil)).
+		Set("enabled = ?", false).
+		Set("disabled_reason = ?", reason).
+		Set("disabled_at = ?", at).
+		Set("updated_at = ?", now()).
+		Where("id = ?", epID.String()).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return relay.ErrEndpointNotFound
+	}
+	s.index.InvalidateEndpoint(epID.String())
 	return nil
 }
 
@@ -359,18 +526,25 @@ func (s *Store) GetEvent(ctx context.Context, evtID id.ID) (*event.Event, error)
 	return fromEventModel(m)
 }
 
+func (s *Store) GetEventByIdempotencyKey(ctx context.Context, key string) (*event.Event, error) {
+	m := new(eventModel)
+	err := s.sdb.NewSelect(m).
+		Where("idempotency_key = ?", key).
+		Scan(ctx)
+	if err != nil {
+		if isNoRows(err) {
+			return nil, relay.ErrEventNotFound
+		}
+		return nil, err
+	}
+	return fromEventModel(m)
+}
+
 func (s *Store) ListEvents(ctx context.Context, opts event.ListOpts) ([]*event.Event, error) {
 	var models []eventModel
 	q := s.sdb.NewSelect(&models)
-
-	if opts.Type != "" {
-		q = q.Where("type = ?", opts.Type)
-	}
-	if opts.From != nil {
-		q = q.Where("created_at >= ?", *opts.From)
-	}
-	if opts.To != nil {
-		q = q.Where("created_at <= ?", *opts.To)
+	for _, c := range eventFilter(opts).after(opts.Cursor, !opts.Ascending) {
+		q = q.Where(c.expr, c.args...)
 	}
 	if opts.Limit > 0 {
 		q = q.Limit(opts.Limit)
@@ -378,7 +552,7 @@ func (s *Store) ListEvents(ctx context.Context, opts event.ListOpts) ([]*event.E
 	if opts.Offset > 0 {
 		q = q.Offset(opts.Offset)
 	}
-	q = q.OrderExpr("created_at DESC")
+	q = q.OrderExpr(orderByID(!opts.Ascending))
 
 	if err := q.Scan(ctx); err != nil {
 		return nil, err
@@ -395,12 +569,19 @@ func (s *Store) ListEvents(ctx context.Context, opts event.ListOpts) ([]*event.E
 	return result, nil
 }
 
+func (s *Store) CountEvents(ctx context.Context, opts event.ListOpts) (int64, error) {
+	q := s.sdb.NewSelect((*eventModel)(nil))
+	for _, c := range eventFilter(opts) {
+		q = q.Where(c.expr, c.args...)
+	}
+	return q.Count(ctx)
+}
+
 func (s *Store) ListEventsByTenant(ctx context.Context, tenantID string, opts event.ListOpts) ([]*event.Event, error) {
 	var models []eventModel
-	q := s.sdb.NewSelect(&models).Where("tenant_id = ?", tenantID)
-
-	if opts.Type != "" {
-		q = q.Where("type = ?", opts.Type)
+	q := s.sdb.NewSelect(&models)
+	for _, c := range eventFilter(opts, tenantID).after(opts.Cursor, !opts.Ascending) {
+		q = q.Where(c.expr, c.args...)
 	}
 	if opts.Limit > 0 {
 		q = q.Limit(opts.Limit)
@@ -408,7 +589,7 @@ func (s *Store) ListEventsByTenant(ctx context.Context, tenantID string, opts ev
 	if opts.Offset > 0 {
 		q = q.Offset(opts.Offset)
 	}
-	q = q.OrderExpr("created_at DESC")
+	q = q.OrderExpr(orderByID(!opts.Ascending))
 
 	if err := q.Scan(ctx); err != nil {
 		return nil, err
@@ -425,6 +606,42 @@ func (s *Store) ListEventsByTenant(ctx context.Context, tenantID string, opts ev
 	return result, nil
 }
 
+func (s *Store) CountEventsByTenant(ctx context.Context, tenantID string, opts event.ListOpts) (int64, error) {
+	q := s.sdb.NewSelect((*eventModel)(nil))
+	for _, c := range eventFilter(opts, tenantID) {
+		q = q.Where(c.expr, c.args...)
+	}
+	return q.Count(ctx)
+}
+
+// eventFilter returns the conditions of an event list, of one tenant if
+// tenantID is given.
+func eventFilter(opts event.ListOpts, tenantID ...string) filter {
+	var f filter
+	for _, t := range tenantID {
+		f.add("tenant_id = ?", t)
+	}
+	if opts.Type != "" {
+		f.add("type = ?", opts.Type)
+	}
+	if opts.From != nil {
+		f.add("created_at >= ?", *opts.From)
+	}
+	if opts.To != nil {
+		f.add("created_at <= ?", *opts.To)
+	}
+	if opts.ScopeAppID != "" {
+		f.add("scope_app_id = ?", opts.ScopeAppID)
+	}
+	if opts.ScopeOrgID != "" {
+		f.add("scope_org_id = ?", opts.ScopeOrgID)
+	}
+	if opts.ExcludeTest {
+		f.raw("test = 0")
+	}
+	return f
+}
+
 // ==================== Delivery Store ====================
 
 func (s *Store) Enqueue(ctx context.Context, d *delivery.Delivery) error {
@@ -495,12 +712,63 @@ func (s *Store) GetDelivery(ctx context.Context, delID id.ID) (*delivery.Deliver
 	return fromDeliveryModel(m)
 }
 
-func (s *Store) ListByEndpoint(ctx context.Context, epID id.ID, opts delivery.ListOpts) ([]*delivery.Delivery, error) {
-	var models []deliveryModel
-	q := s.sdb.NewSelect(&models).Where("endpoint_id = ?", epID.String())
+func (s *Store) RetryDelivery(ctx context.Context, delID id.ID) error {
+	t := now()
+	res, err := s.sdb.NewUpdate((*deliveryModel)(nil)).
+		Set("next_attempt_at = ?", t).
+		Set("updated_at = ?", t).
+		Where("id = ?", delID.String()).
+		Where("state = 'pending'").
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return s.notPending(ctx, delID)
+	}
+	return nil
+}
 
-	if opts.State != nil {
-		q = q.Where("state = ?", string(*opts.State))
+func (s *Store) CancelDelivery(ctx context.Context, delID id.ID) error {
+	t := now()
+	res, err := s.sdb.NewUpdate((*deliveryModel)(nil)).
+		Set("state = ?", string(delivery.StateCancelled)).
+		Set("completed_at = ?", t).
+		Set("updated_at = ?", t).
+		Where("id = ?", delID.String()).
+		Where("state = 'pending'").
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return s.notPending(ctx, delID)
+	}
+	return nil
+}
+
+// notPending tells why a conditional update of a pending delivery changed
+// no rows: the delivery is missing or no longer pending.
+func (s *Store) notPending(ctx context.Context, delID id.ID) error {
+	if _, err := s.GetDelivery(ctx, delID); err != nil {
+		return err
+	}
+	return relay.ErrDeliveryNotPending
+}
+
+func (s *Store) ListDeliveries(ctx context.Context, opts delivery.ListOpts) ([]*delivery.Delivery, error) {
+	var models []deliveryModel
+	q := s.sdb.NewSelect(&models)
+	for _, c := range deliveryFilter(opts).after(opts.Cursor, true) {
+		q = q.Where(c.expr, c.args...)
 	}
 	if opts.Limit > 0 {
 		q = q.Limit(opts.Limit)
@@ -508,7 +776,7 @@ func (s *Store) ListByEndpoint(ctx context.Context, epID id.ID, opts delivery.Li
 	if opts.Offset > 0 {
 		q = q.Offset(opts.Offset)
 	}
-	q = q.OrderExpr("created_at DESC")
+	q = q.OrderExpr("id DESC")
 
 	if err := q.Scan(ctx); err != nil {
 		return nil, err
@@ -525,6 +793,47 @@ func (s *Store) ListByEndpoint(ctx context.Context, epID id.ID, opts delivery.Li
 	return result, nil
 }
 
+func (s *Store) CountDeliveries(ctx context.Context, opts delivery.ListOpts) (int64, error) {
+	q := s.sdb.NewSelect((*deliveryModel)(nil))
+	for _, c := range deliveryFilter(opts) {
+		q = q.Where(c.expr, c.args...)
+	}
+	return q.Count(ctx)
+}
+
+func (s *Store) ListByEndpoint(ctx context.Context, epID id.ID, opts delivery.ListOpts) ([]*delivery.Delivery, error) {
+	opts.EndpointID = &epID
+	return s.ListDeliveries(ctx, opts)
+}
+
+func (s *Store) CountByEndpoint(ctx context.Context, epID id.ID, opts delivery.ListOpts) (int64, error) {
+	opts.EndpointID = &epID
+	return s.CountDeliveries(ctx, opts)
+}
+
+func deliveryFilter(opts delivery.ListOpts) filter {
+	var f filter
+	if opts.EndpointID != nil {
+		f.add("endpoint_id = ?", opts.EndpointID.String())
+	}
+	if opts.EventID != nil {
+		f.add("event_id = ?", opts.EventID.String())
+	}
+	if opts.TenantID != "" {
+		f.add("endpoint_id IN (SELECT id FROM relay_endpoints WHERE tenant_id = ?)", opts.TenantID)
+	}
+	if opts.State != nil {
+		f.add("state = ?", string(*opts.State))
+	}
+	if opts.From != nil {
+		f.add("created_at >= ?", *opts.From)
+	}
+	if opts.To != nil {
+		f.add("created_at <= ?", *opts.To)
+	}
+	return f
+}
+
 func (s *Store) ListByEvent(ctx context.Context, evtID id.ID) ([]*delivery.Delivery, error) {
 	var models []deliveryModel
 	if err := s.sdb.NewSelect(&models).
@@ -563,18 +872,8 @@ func (s *Store) Push(ctx context.Context, entry *dlq.Entry) error {
 func (s *Store) ListDLQ(ctx context.Context, opts dlq.ListOpts) ([]*dlq.Entry, error) {
 	var models []dlqEntryModel
 	q := s.sdb.NewSelect(&models)
-
-	if opts.TenantID != "" {
-		q = q.Where("tenant_id = ?", opts.TenantID)
-	}
-	if opts.EndpointID != nil {
-		q = q.Where("endpoint_id = ?", opts.EndpointID.String())
-	}
-	if opts.From != nil {
-		q = q.Where("failed_at >= ?", *opts.From)
-	}
-	if opts.To != nil {
-		q = q.Where("failed_at <= ?", *opts.To)
+	for _, c := range dlqFilter(opts).after(opts.Cursor, true) {
+		q = q.Where(c.expr, c.args...)
 	}
 	if opts.Limit > 0 {
 		q = q.Limit(opts.Limit)
@@ -582,7 +881,7 @@ func (s *Store) ListDLQ(ctx context.Context, opts dlq.ListOpts) ([]*dlq.Entry, e
 	if opts.Offset > 0 {
 		q = q.Offset(opts.Offset)
 	}
-	q = q.OrderExpr("failed_at DESC")
+	q = q.OrderExpr("id DESC")
 
 	if err := q.Scan(ctx); err != nil {
 		return nil, err
@@ -599,6 +898,35 @@ func (s *Store) ListDLQ(ctx context.Context, opts dlq.ListOpts) ([]*dlq.Entry, e
 	return result, nil
 }
 
+func dlqFilter(opts dlq.ListOpts) filter {
+	var f filter
+	if opts.TenantID != "" {
+		f.add("tenant_id = ?", opts.TenantID)
+	}
+	if opts.EndpointID != nil {
+		f.add("endpoint_id = ?", opts.EndpointID.String())
+	}
+	if opts.From != nil {
+		f.add("failed_at >= ?", *opts.From)
+	}
+	if opts.To != nil {
+		f.add("failed_at <= ?", *opts.To)
+	}
+	if opts.EventType != "" {
+		f.add("event_type = ?", opts.EventType)
+	}
+	if opts.StatusCode != 0 {
+		f.add("last_status_code = ?", opts.StatusCode)
+	}
+	if opts.ErrorContains != "" {
+		f.add("instr(error, ?) > 0", opts.ErrorContains)
+	}
+	if opts.Unreplayed {
+		f.raw("replayed_at IS NULL")
+	}
+	return f
+}
+
 func (s *Store) GetDLQ(ctx context.Context, dlqID id.ID) (*dlq.Entry, error) {
 	m := new(dlqEntryModel)
 	err := s.sdb.NewSelect(m).
@@ -613,67 +941,57 @@ func (s *Store) GetDLQ(ctx context.Context, dlqID id.ID) (*dlq.Entry, error) {
 	return fromDLQEntryModel(m)
 }
 
-func (s *Store) Replay(ctx context.Context, dlqID id.ID) error {
-	entry, err := s.GetDLQ(ctx, dlqID)
+func (s *Store) Replay(ctx context.Context, dlqID id.ID, d *delivery.Delivery) error {
+	// Claim the entry and enqueue in one transaction, so concurrent replays
+	// enqueue one delivery and a failure leaves the entry replayable.
+	tx, err := s.sdb.BeginTx(ctx, nil)
 	if err != nil {
+		return fmt.Errorf("relay/sqlite: begin replay tx: %w", err)
+	}
+
+	claimed, err := claimDLQEntry(ctx, tx, dlqID, d.ID)
+	if err != nil {
+		_ = tx.Rollback() //nolint:errcheck // the claim error is the one worth reporting
 		return err
 	}
+	if !claimed {
+		_ = tx.Rollback() //nolint:errcheck // nothing was written
+		if _, err := s.GetDLQ(ctx, dlqID); err != nil {
+			return err
+		}
+		return dlq.ErrAlreadyReplayed
+	}
 
-	d := &delivery.Delivery{
-		Entity:        entity.New(),
-		ID:            id.NewDeliveryID(),
-		EventID:       entry.EventID,
-		EndpointID:    entry.EndpointID,
-		State:         delivery.StatePending,
-		NextAttemptAt: now(),
+	if _, err := tx.NewInsert(toDeliveryModel(d)).Exec(ctx); err != nil {
+		_ = tx.Rollback() //nolint:errcheck // the insert error is the one worth reporting
+		return err
 	}
 
-	if enqueueErr := s.Enqueue(ctx, d); enqueueErr != nil {
-		return enqueueErr
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("relay/sqlite: commit replay tx: %w", err)
 	}
+	return nil
+}
 
-	_, err = s.sdb.NewDelete((*dlqEntryModel)(nil)).
+// claimDLQEntry marks an entry replayed as replayID, reporting false if it
+// is unknown or was already replayed.
+func claimDLQEntry(ctx context.Context, tx *sqlitedriver.SqliteTx, dlqID, replayID id.ID) (bool, error) {
+	t := now()
+	res, err := tx.NewUpdate((*dlqEntryModel)(nil)).
+		Set("replayed_at = ?", t).
+		Set("replay_delivery_id = ?", replayID.String()).
+		Set("updated_at = ?", t).
 		Where("id = ?", dlqID.String()).
+		Where("replayed_at IS NULL").
 		Exec(ctx)
-	return err
-}
-
-func (s *Store) ReplayBulk(ctx context.Context, from, to time.Time) (int64, error) {
-	var models []dlqEntryModel
-	if err := s.sdb.NewSelect(&models).
-		Where("failed_at >= ?", from).
-		Where("failed_at <= ?", to).
-		Scan(ctx); err != nil {
-		return 0, err
+	if err != nil {
+		return false, err
 	}
-
-	var count int64
-	for i := range models {
-		entry, err := fromDLQEntryModel(&models[i])
-		if err != nil {
-			return count, err
-		}
-		d := &delivery.Delivery{
-			Entity:        entity.New(),
-			ID:            id.NewDeliveryID(),
-			EventID:       entry.EventID,
-			EndpointID:    entry.EndpointID,
-			State:         delivery.StatePending,
-			NextAttemptAt: now(),
-		}
-
-		if err := s.Enqueue(ctx, d); err != nil {
-			return count, err
-		}
-
-		if _, err := s.sdb.NewDelete((*dlqEntryModel)(nil)).
-			Where("id = ?", models[i].ID).
-			Exec(ctx); err != nil {
-			return count, err
-		}
-		count++
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, err
 	}
-	return count, nil
+	return rows > 0, nil
 }
 
 func (s *Store) Purge(ctx context.Context, before time.Time) (int64, error) {
@@ -691,10 +1009,104 @@ func (s *Store) Purge(ctx context.Context, before time.Time) (int64, error) {
 	return rows, nil
 }
 
-func (s *Store) CountDLQ(ctx context.Context) (int64, error) {
-	count, err := s.sdb.NewSelect((*dlqEntryModel)(nil)).
-		Count(ctx)
-	return count, err
+func (s *Store) CountDLQ(ctx context.Context, opts dlq.ListOpts) (int64, error) {
+	q := s.sdb.NewSelect((*dlqEntryModel)(nil))
+	for _, c := range dlqFilter(opts) {
+		q = q.Where(c.expr, c.args...)
+	}
+	return q.Count(ctx)
+}
+
+// ==================== API Key Store ====================
+
+func (s *Store) CreateAPIKey(ctx context.Context, key *auth.APIKey) error {
+	m := toAPIKeyModel(key)
+	_, err := s.sdb.NewInsert(m).Exec(ctx)
+	return err
+}
+
+func (s *Store) GetAPIKey(ctx context.Context, keyID id.ID) (*auth.APIKey, error) {
+	return s.getAPIKey(ctx, "id = ?", keyID.String())
+}
+
+func (s *Store) GetAPIKeyByHash(ctx context.Context, hash string) (*auth.APIKey, error) {
+	return s.getAPIKey(ctx, "hash = ?", hash)
+}
+
+func (s *Store) getAPIKey(ctx context.Context, where string, arg any) (*auth.APIKey, error) {
+	m := new(apiKeyModel)
+	err := s.sdb.NewSelect(m).
+		Where(where, arg).
+		Scan(ctx)
+	if err != nil {
+		if isNoRows(err) {
+			return nil, relay.ErrAPIKeyNotFound
+		}
+		return nil, err
+	}
+	return fromAPIKeyModel(m)
+}
+
+func (s *Store) ListAPIKeys(ctx context.Context, opts auth.ListOpts) ([]*auth.APIKey, error) {
+	var models []apiKeyModel
+	q := s.sdb.NewSelect(&models)
+	for _, c := range apiKeyFilter(opts).after(opts.Cursor, false) {
+		q = q.Where(c.expr, c.args...)
+	}
+	if opts.Limit > 0 {
+		q = q.Limit(opts.Limit)
+	}
+	if opts.Offset > 0 {
+		q = q.Offset(opts.Offset)
+	}
+	q = q.OrderExpr("id ASC")
+
+	if err := q.Scan(ctx); err != nil {
+		return nil, err
+	}
+
+	result := make([]*auth.APIKey, len(models))
+	for i := range models {
+		key, err := fromAPIKeyModel(&models[i])
+		if err != nil {
+			return nil, err
+		}
+		result[i] = key
+	}
+	return result, nil
+}
+
+func (s *Store) CountAPIKeys(ctx context.Context, opts auth.ListOpts) (int64, error) {
+	q := s.sdb.NewSelect((*apiKeyModel)(nil))
+	for _, c := range apiKeyFilter(opts) {
+		q = q.Where(c.expr, c.args...)
+	}
+	return q.Count(ctx)
+}
+
+func apiKeyFilter(opts auth.ListOpts) filter {
+	var f filter
+	if opts.TenantID != "" {
+		f.add("tenant_id = ?", opts.TenantID)
+	}
+	return f
+}
+
+func (s *Store) DeleteAPIKey(ctx context.Context, keyID id.ID) error {
+	res, err := s.sdb.NewDelete((*apiKeyModel)(nil)).
+		Where("id = ?", keyID.String()).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return relay.ErrAPIKeyNotFound
+	}
+	return nil
 }
 
 // now returns the current UTC time.
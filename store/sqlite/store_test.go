@@ -0,0 +1,94 @@
+package sqlite_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/xraph/grove"
+	"github.com/xraph/grove/drivers/sqlitedriver"
+	_ "github.com/xraph/grove/drivers/sqlitedriver/sqlitemigrate" // registers the sqlite migrate executor
+
+	"github.com/xraph/relay"
+	"github.com/xraph/relay/catalog"
+	"github.com/xraph/relay/endpoint"
+	"github.com/xraph/relay/event"
+	sqlitestore "github.com/xraph/relay/store/sqlite"
+)
+
+func openSqliteStore(t *testing.T) *sqlitestore.Store {
+	t.Helper()
+	_, s := openSqliteDB(t)
+	return s
+}
+
+// openSqliteDB opens a migrated store and returns it with its database, for
+// tests that begin their own transactions.
+func openSqliteDB(t *testing.T) (*grove.DB, *sqlitestore.Store) {
+	t.Helper()
+	ctx := context.Background()
+
+	drv := sqlitedriver.New()
+	if err := drv.Open(ctx, filepath.Join(t.TempDir(), "relay.db")); err != nil {
+		t.Fatalf("open sqlitedriver: %v", err)
+	}
+	db, err := grove.Open(drv)
+	if err != nil {
+		t.Fatalf("grove open: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	s := sqlitestore.New(db)
+	if err := s.Migrate(ctx); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db, s
+}
+
+// TestSendBatchManyEndpoints sends a full chunk of events to enough
+// endpoints that the deliveries need more bind parameters than one SQLite
+// statement takes.
+func TestSendBatchManyEndpoints(t *testing.T) {
+	s := openSqliteStore(t)
+	ctx := context.Background()
+
+	r, err := relay.New(relay.WithStore(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.RegisterEventType(ctx, catalog.WebhookDefinition{Name: "order.created"}); err != nil {
+		t.Fatal(err)
+	}
+	const endpoints = 20
+	for range endpoints {
+		if _, err := r.Endpoints().Create(ctx, endpoint.Input{
+			TenantID:   "acme",
+			URL:        "https://example.com/hooks",
+			EventTypes: []string{"order.*"},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	evts := make([]*event.Event, 500)
+	for i := range evts {
+		evts[i] = &event.Event{Type: "order.created", TenantID: "acme"}
+	}
+	results, err := r.SendBatch(ctx, evts)
+	if err != nil {
+		t.Fatalf("send batch: %v", err)
+	}
+	for i, res := range results {
+		if res.Err != nil {
+			t.Fatalf("event %d: %v", i, res.Err)
+		}
+	}
+
+	pending, err := s.CountPending(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending != int64(len(evts)*endpoints) {
+		t.Fatalf("expected %d pending deliveries, got %d", len(evts)*endpoints, pending)
+	}
+}
@@ -7,11 +7,13 @@ package store
 import (
 	"context"
 
+	"github.com/xraph/relay/auth"
 	"github.com/xraph/relay/catalog"
 	"github.com/xraph/relay/delivery"
 	"github.com/xraph/relay/dlq"
 	"github.com/xraph/relay/endpoint"
 	"github.com/xraph/relay/event"
+	"github.com/xraph/relay/id"
 )
 
 // WakeNotifier is an optional store capability: backends that can push
@@ -26,6 +28,41 @@ type WakeNotifier interface {
 	StartWakeListener(ctx context.Context, wake func()) (stop func(), err error)
 }
 
+// Outbox is an optional store capability: backends that can write an event
+// and its fan-out deliveries in one transaction implement it, so a crash
+// between the two writes can never leave an event without deliveries.
+// Relay.Send uses it when available and falls back to CreateEvent followed
+// by EnqueueBatch otherwise.
+type Outbox interface {
+	// CreateEventWithDeliveries atomically persists evt and ds. It returns
+	// relay.ErrDuplicateIdempotencyKey, and writes nothing, when the event's
+	// idempotency key has been seen before.
+	CreateEventWithDeliveries(ctx context.Context, evt *event.Event, ds []*delivery.Delivery) error
+}
+
+// TxOutbox is implemented by Outbox backends that can also write inside a
+// transaction owned by the application, so application state and queued
+// webhooks commit or roll back together.
+type TxOutbox interface {
+	Outbox
+
+	// CreateEventWithDeliveriesTx behaves like CreateEventWithDeliveries but
+	// runs on tx, which must be a transaction of the store's own driver.
+	// Nothing is visible to the delivery engine until the caller commits.
+	CreateEventWithDeliveriesTx(ctx context.Context, tx any, evt *event.Event, ds []*delivery.Delivery) error
+}
+
+// BatchOutbox is an optional store capability for bulk ingestion: backends
+// implement it to insert many events and their deliveries with a handful of
+// round trips instead of two per event. Relay.SendBatch falls back to one
+// write per event for stores without it.
+type BatchOutbox interface {
+	// CreateEventBatch persists evts and ds. Events whose idempotency key
+	// already exists (in the store or earlier in evts) are skipped together
+	// with their deliveries and reported in duplicates.
+	CreateEventBatch(ctx context.Context, evts []*event.Event, ds []*delivery.Delivery) (duplicates []id.ID, err error)
+}
+
 // Store is the aggregate persistence interface.
 // Each subsystem store is a composable interface — same pattern as ControlPlane.
 type Store interface {
@@ -34,6 +71,7 @@ type Store interface {
 	event.Store
 	delivery.Store
 	dlq.Store
+	auth.Store
 
 	// Migrate runs all schema migrations.
 	Migrate(ctx context.Context) error
@@ -0,0 +1,286 @@
+package relay
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/xraph/go-utils/log"
+
+	"github.com/xraph/relay/catalog"
+	"github.com/xraph/relay/delivery"
+	"github.com/xraph/relay/dlq"
+	"github.com/xraph/relay/endpoint"
+	"github.com/xraph/relay/event"
+	"github.com/xraph/relay/health"
+	"github.com/xraph/relay/id"
+	"github.com/xraph/relay/scope"
+)
+
+// System event types. With WithSystemEvents, Relay sends them through its
+// own pipeline to the endpoints of the tenant concerned, so a tenant can
+// subscribe an ops endpoint to "relay.**" and be alerted about its others.
+const (
+	// EventTypeEndpointDisabled is sent when the delivery engine disables
+	// an endpoint, after a 410 Gone or by the health policy.
+	EventTypeEndpointDisabled = "relay.endpoint.disabled"
+
+	// EventTypeEndpointFailing is sent when an endpoint's run of
+	// consecutive failures reaches SystemEventsConfig.FailingAfter.
+	EventTypeEndpointFailing = "relay.endpoint.failing"
+
+	// EventTypeDLQThresholdExceeded is sent when a tenant's DLQ holds
+	// SystemEventsConfig.DLQThreshold or more entries that were not
+	// replayed.
+	EventTypeDLQThresholdExceeded = "relay.dlq.threshold_exceeded"
+)
+
+// SystemEventsConfig configures the system events Relay emits.
+type SystemEventsConfig struct {
+	// Enabled turns system events on. WithSystemEvents sets it.
+	Enabled bool
+
+	// FailingAfter is the run of consecutive failed deliveries that sends
+	// relay.endpoint.failing, once per run. Defaults to 10.
+	FailingAfter int
+
+	// DLQThreshold is the number of unreplayed DLQ entries a tenant may
+	// accumulate before relay.dlq.threshold_exceeded is sent. Defaults to 100.
+	DLQThreshold int
+
+	// DLQAlertInterval is the minimum time between two
+	// relay.dlq.threshold_exceeded events for the same tenant. Defaults
+	// to 1h.
+	DLQAlertInterval time.Duration
+}
+
+func (c SystemEventsConfig) withDefaults() SystemEventsConfig {
+	if c.FailingAfter <= 0 {
+		c.FailingAfter = 10
+	}
+	if c.DLQThreshold <= 0 {
+		c.DLQThreshold = 100
+	}
+	if c.DLQAlertInterval <= 0 {
+		c.DLQAlertInterval = time.Hour
+	}
+	return c
+}
+
+// EndpointDisabledData is the payload of relay.endpoint.disabled.
+type EndpointDisabledData struct {
+	EndpointID id.ID     `json:"endpoint_id"`
+	URL        string    `json:"url"`
+	Reason     string    `json:"reason"`
+	DisabledAt time.Time `json:"disabled_at"`
+}
+
+// EndpointFailingData is the payload of relay.endpoint.failing.
+type EndpointFailingData struct {
+	EndpointID          id.ID      `json:"endpoint_id"`
+	URL                 string     `json:"url"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	SuccessRate         float64    `json:"success_rate"`
+	LastSuccessAt       *time.Time `json:"last_success_at,omitempty"`
+	LastStatusCode      int        `json:"last_status_code,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+}
+
+// DLQThresholdData is the payload of relay.dlq.threshold_exceeded.
+type DLQThresholdData struct {
+	TenantID  string `json:"tenant_id"`
+	Threshold int    `json:"threshold"`
+
+	// EndpointID is the endpoint of the entry that crossed the threshold.
+	EndpointID id.ID `json:"endpoint_id"`
+}
+
+const systemEventVersion = "2025-01-01"
+
+// systemEventDefinitions are the catalog entries of the system event types.
+var systemEventDefinitions = []catalog.WebhookDefinition{
+	{
+		Name:        EventTypeEndpointDisabled,
+		Description: "An endpoint was disabled automatically and no longer receives deliveries.",
+		Group:       "relay",
+		Version:     systemEventVersion,
+		Schema: json.RawMessage(`{
+			"type": "object",
+			"required": ["endpoint_id", "url", "reason", "disabled_at"],
+			"properties": {
+				"endpoint_id": {"type": "string"},
+				"url": {"type": "string"},
+				"reason": {"type": "string"},
+				"disabled_at": {"type": "string", "format": "date-time"}
+			}
+		}`),
+		Example: json.RawMessage(`{"endpoint_id":"ep_01h455vb4pex5vsknk084sn02q","url":"https://example.com/webhooks","reason":"endpoint answered 410 Gone","disabled_at":"2025-01-01T12:00:00Z"}`),
+	},
+	{
+		Name:        EventTypeEndpointFailing,
+		Description: "An endpoint failed a run of consecutive deliveries.",
+		Group:       "relay",
+		Version:     systemEventVersion,
+		Schema: json.RawMessage(`{
+			"type": "object",
+			"required": ["endpoint_id", "url", "consecutive_failures", "success_rate"],
+			"properties": {
+				"endpoint_id": {"type": "string"},
+				"url": {"type": "string"},
+				"consecutive_failures": {"type": "integer"},
+				"success_rate": {"type": "number"},
+				"last_success_at": {"type": "string", "format": "date-time"},
+				"last_status_code": {"type": "integer"},
+				"last_error": {"type": "string"}
+			}
+		}`),
+		Example: json.RawMessage(`{"endpoint_id":"ep_01h455vb4pex5vsknk084sn02q","url":"https://example.com/webhooks","consecutive_failures":10,"success_rate":0.4,"last_status_code":503}`),
+	},
+	{
+		Name:        EventTypeDLQThresholdExceeded,
+		Description: "A tenant's dead letter queue grew past the alert threshold.",
+		Group:       "relay",
+		Version:     systemEventVersion,
+		Schema: json.RawMessage(`{
+			"type": "object",
+			"required": ["tenant_id", "threshold", "endpoint_id"],
+			"properties": {
+				"tenant_id": {"type": "string"},
+				"threshold": {"type": "integer"},
+				"endpoint_id": {"type": "string"}
+			}
+		}`),
+		Example: json.RawMessage(`{"tenant_id":"acme","threshold":100,"endpoint_id":"ep_01h455vb4pex5vsknk084sn02q"}`),
+	},
+}
+
+// systemEvents emits system events. It implements delivery.EndpointNotifier
+// and wraps the DLQ pusher to watch DLQ sizes.
+type systemEvents struct {
+	r   *Relay
+	cfg SystemEventsConfig
+
+	mu           sync.Mutex
+	lastDLQAlert map[string]time.Time
+}
+
+func newSystemEvents(r *Relay, cfg SystemEventsConfig) *systemEvents {
+	return &systemEvents{
+		r:            r,
+		cfg:          cfg.withDefaults(),
+		lastDLQAlert: make(map[string]time.Time),
+	}
+}
+
+// register adds the system event types to the catalog.
+func (s *systemEvents) register(ctx context.Context) {
+	for _, def := range systemEventDefinitions {
+		if _, err := s.r.catalog.RegisterType(ctx, def); err != nil {
+			s.r.logger.Error("register system event type failed",
+				log.String("type", def.Name), log.Any("error", err))
+		}
+	}
+}
+
+// EndpointDisabled implements delivery.EndpointNotifier.
+func (s *systemEvents) EndpointDisabled(ctx context.Context, ep *endpoint.Endpoint, reason string) {
+	disabledAt := time.Now().UTC()
+	if ep.DisabledAt != nil {
+		disabledAt = *ep.DisabledAt
+	}
+	s.emit(ctx, ep, EventTypeEndpointDisabled,
+		EventTypeEndpointDisabled+":"+ep.ID.String()+":"+strconv.FormatInt(disabledAt.UnixNano(), 10),
+		EndpointDisabledData{
+			EndpointID: ep.ID,
+			URL:        ep.URL,
+			Reason:     reason,
+			DisabledAt: disabledAt,
+		})
+}
+
+// EndpointFailing implements delivery.EndpointNotifier.
+func (s *systemEvents) EndpointFailing(ctx context.Context, ep *endpoint.Endpoint, h health.Health, last delivery.Result) {
+	s.emit(ctx, ep, EventTypeEndpointFailing, "", EndpointFailingData{
+		EndpointID:          ep.ID,
+		URL:                 ep.URL,
+		ConsecutiveFailures: h.ConsecutiveFailures,
+		SuccessRate:         h.SuccessRate,
+		LastSuccessAt:       h.LastSuccessAt,
+		LastStatusCode:      last.StatusCode,
+		LastError:           last.Error,
+	})
+}
+
+// checkDLQ sends relay.dlq.threshold_exceeded when ep's tenant has reached
+// the threshold, at most once per DLQAlertInterval.
+func (s *systemEvents) checkDLQ(ctx context.Context, ep *endpoint.Endpoint) {
+	s.mu.Lock()
+	last, alerted := s.lastDLQAlert[ep.TenantID]
+	s.mu.Unlock()
+	if alerted && time.Since(last) < s.cfg.DLQAlertInterval {
+		return
+	}
+
+	count, err := s.r.store.CountDLQ(ctx, dlq.ListOpts{TenantID: ep.TenantID, Unreplayed: true})
+	if err != nil {
+		s.r.logger.Warn("system events: count tenant DLQ failed",
+			log.String("tenant_id", ep.TenantID), log.Any("error", err))
+		return
+	}
+	if count < int64(s.cfg.DLQThreshold) {
+		return
+	}
+
+	s.mu.Lock()
+	if last, alerted := s.lastDLQAlert[ep.TenantID]; alerted && time.Since(last) < s.cfg.DLQAlertInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastDLQAlert[ep.TenantID] = time.Now()
+	s.mu.Unlock()
+
+	s.emit(ctx, ep, EventTypeDLQThresholdExceeded, "", DLQThresholdData{
+		TenantID:   ep.TenantID,
+		Threshold:  s.cfg.DLQThreshold,
+		EndpointID: ep.ID,
+	})
+}
+
+// emit sends a system event to ep's tenant. Failures are logged: alerts
+// must never fail the delivery that triggered them.
+func (s *systemEvents) emit(ctx context.Context, ep *endpoint.Endpoint, eventType, idempotencyKey string, data any) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		s.r.logger.Error("system events: marshal payload failed",
+			log.String("type", eventType), log.Any("error", err))
+		return
+	}
+
+	ctx = scope.Restore(ctx, ep.ScopeAppID, ep.ScopeOrgID)
+	evt := &event.Event{
+		Type:           eventType,
+		TenantID:       ep.TenantID,
+		Data:           json.RawMessage(payload),
+		IdempotencyKey: idempotencyKey,
+	}
+	if err := s.r.Send(ctx, evt); err != nil {
+		s.r.logger.Error("system events: send failed",
+			log.String("type", eventType), log.String("endpoint_id", ep.ID.String()), log.Any("error", err))
+	}
+}
+
+// alertingDLQ pushes to the DLQ and then checks the tenant's DLQ size.
+type alertingDLQ struct {
+	delivery.DLQPusher
+	events *systemEvents
+}
+
+func (a alertingDLQ) PushFailed(ctx context.Context, d *delivery.Delivery, ep *endpoint.Endpoint, evt *event.Event, lastError string, lastStatusCode int) error {
+	if err := a.DLQPusher.PushFailed(ctx, d, ep, evt, lastError, lastStatusCode); err != nil {
+		return err
+	}
+	a.events.checkDLQ(ctx, ep)
+	return nil
+}
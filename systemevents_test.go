@@ -0,0 +1,126 @@
+package relay_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xraph/relay"
+	"github.com/xraph/relay/endpoint"
+	"github.com/xraph/relay/event"
+	"github.com/xraph/relay/store/memory"
+)
+
+// alertSink records the system events delivered to an ops endpoint.
+type alertSink struct {
+	mu     sync.Mutex
+	alerts map[string][]json.RawMessage
+}
+
+func (a *alertSink) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	a.mu.Lock()
+	a.alerts[r.Header.Get("X-Relay-Event-Type")] = append(a.alerts[r.Header.Get("X-Relay-Event-Type")], body)
+	a.mu.Unlock()
+}
+
+func (a *alertSink) wait(t *testing.T, eventType string) json.RawMessage {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		a.mu.Lock()
+		got := a.alerts[eventType]
+		a.mu.Unlock()
+		if len(got) > 0 {
+			return got[0]
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("no %s alert received", eventType)
+	return nil
+}
+
+func TestSystemEvents(t *testing.T) {
+	s := memory.New()
+	r, err := relay.New(
+		relay.WithStore(s),
+		relay.WithPollInterval(10*time.Millisecond),
+		relay.WithMaxRetries(3),
+		relay.WithRetrySchedule([]time.Duration{10 * time.Millisecond, 10 * time.Millisecond}),
+		relay.WithSystemEvents(relay.SystemEventsConfig{FailingAfter: 2, DLQThreshold: 1}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	registerType(t, r, "order.created")
+
+	sink := &alertSink{alerts: map[string][]json.RawMessage{}}
+	ops := httptest.NewServer(sink)
+	defer ops.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+	gone := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer gone.Close()
+
+	for _, in := range []endpoint.Input{
+		{TenantID: "acme", URL: ops.URL, EventTypes: []string{"relay.**"}},
+		{TenantID: "acme", URL: failing.URL, EventTypes: []string{"order.*"}},
+		{TenantID: "acme", URL: gone.URL, EventTypes: []string{"order.*"}},
+	} {
+		if _, err := r.Endpoints().Create(ctx(), in); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r.Start(ctx())
+	defer r.Stop(ctx())
+
+	if err := r.Send(ctx(), &event.Event{
+		Type:     "order.created",
+		TenantID: "acme",
+		Data:     mustJSON(map[string]any{"id": 1}),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var disabled relay.EndpointDisabledData
+	if err := json.Unmarshal(sink.wait(t, relay.EventTypeEndpointDisabled), &disabled); err != nil {
+		t.Fatal(err)
+	}
+	if disabled.URL != gone.URL || disabled.Reason == "" {
+		t.Fatalf("unexpected disabled alert %+v", disabled)
+	}
+
+	var failingAlert relay.EndpointFailingData
+	if err := json.Unmarshal(sink.wait(t, relay.EventTypeEndpointFailing), &failingAlert); err != nil {
+		t.Fatal(err)
+	}
+	if failingAlert.URL != failing.URL || failingAlert.ConsecutiveFailures != 2 || failingAlert.LastStatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("unexpected failing alert %+v", failingAlert)
+	}
+
+	var dlqAlert relay.DLQThresholdData
+	if err := json.Unmarshal(sink.wait(t, relay.EventTypeDLQThresholdExceeded), &dlqAlert); err != nil {
+		t.Fatal(err)
+	}
+	if dlqAlert.TenantID != "acme" || dlqAlert.Threshold != 1 {
+		t.Fatalf("unexpected DLQ alert %+v", dlqAlert)
+	}
+
+	// One DLQ alert per interval, however many entries follow.
+	time.Sleep(100 * time.Millisecond)
+	sink.mu.Lock()
+	n := len(sink.alerts[relay.EventTypeDLQThresholdExceeded])
+	sink.mu.Unlock()
+	if n != 1 {
+		t.Fatalf("expected a single DLQ alert, got %d", n)
+	}
+}
@@ -0,0 +1,97 @@
+package relay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	log "github.com/xraph/go-utils/log"
+
+	"github.com/xraph/relay/delivery"
+	"github.com/xraph/relay/event"
+	"github.com/xraph/relay/id"
+	"github.com/xraph/relay/internal/entity"
+)
+
+// TestResult is the outcome of SendTest.
+type TestResult struct {
+	EventID    id.ID `json:"event_id"`
+	DeliveryID id.ID `json:"delivery_id"`
+
+	// Success reports a 2xx response from the endpoint.
+	Success bool `json:"success"`
+
+	StatusCode int    `json:"status_code,omitempty"`
+	Response   string `json:"response,omitempty"`
+	Error      string `json:"error,omitempty"`
+	LatencyMs  int    `json:"latency_ms"`
+}
+
+// SendTest sends a synthetic event of eventType to a single endpoint and
+// waits for the response. The payload is data, or the event type's
+// example when data is empty. It goes through the same validation,
+// conversion and signing as Send, even to a disabled endpoint or one not
+// subscribed to eventType.
+//
+// The event and its delivery are recorded, both marked Test. The attempt
+// is never retried or dead-lettered and does not count towards metrics or
+// stats, and the event is never resent or backfilled.
+func (r *Relay) SendTest(ctx context.Context, endpointID id.ID, eventType string, data json.RawMessage) (*TestResult, error) {
+	ep, err := r.store.GetEndpoint(ctx, endpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	et, err := r.catalog.GetType(ctx, eventType)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrEventTypeNotFound, eventType)
+	}
+	if len(data) == 0 {
+		data = et.Definition.Example
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoTestPayload, eventType)
+	}
+
+	evt := &event.Event{
+		Type:     eventType,
+		TenantID: ep.TenantID,
+		Data:     data,
+		Test:     true,
+	}
+	if _, err := r.prepare(ctx, evt); err != nil {
+		return nil, err
+	}
+
+	d := &delivery.Delivery{
+		Entity:        entity.New(),
+		ID:            id.NewDeliveryID(),
+		EventID:       evt.ID,
+		EndpointID:    ep.ID,
+		State:         delivery.StatePending,
+		MaxAttempts:   1,
+		NextAttemptAt: time.Now().UTC(),
+	}
+	res := r.engine.SendTest(ctx, ep, evt, d)
+
+	if err := r.persist(ctx, nil, evt, []*delivery.Delivery{d}); err != nil {
+		return nil, err
+	}
+
+	r.logger.Debug("test event sent",
+		log.String("event_id", evt.ID.String()),
+		log.String("endpoint_id", ep.ID.String()),
+		log.Int("status", res.StatusCode),
+	)
+
+	return &TestResult{
+		EventID:    evt.ID,
+		DeliveryID: d.ID,
+		Success:    d.State == delivery.StateDelivered,
+		StatusCode: res.StatusCode,
+		Response:   res.Response,
+		Error:      res.Error,
+		LatencyMs:  res.LatencyMs,
+	}, nil
+}
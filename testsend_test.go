@@ -0,0 +1,104 @@
+package relay_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/xraph/relay"
+	"github.com/xraph/relay/catalog"
+	"github.com/xraph/relay/delivery"
+	"github.com/xraph/relay/dlq"
+	"github.com/xraph/relay/endpoint"
+	"github.com/xraph/relay/signature"
+)
+
+func TestSendTest(t *testing.T) {
+	r, s := setup(t)
+	if _, err := r.RegisterEventType(ctx(), catalog.WebhookDefinition{
+		Name:    "invoice.created",
+		Example: mustJSON(map[string]any{"total": 5}),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	registerType(t, r, "invoice.paid")
+
+	var body []byte
+	var header http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ = io.ReadAll(req.Body)
+		header = req.Header.Clone()
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, "nope") //nolint:errcheck // test server
+	}))
+	defer srv.Close()
+
+	ep, err := r.Endpoints().Create(ctx(), endpoint.Input{
+		TenantID:   "acme",
+		URL:        srv.URL,
+		EventTypes: []string{"invoice.*"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := r.SendTest(ctx(), ep.ID, "invoice.created", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Success || res.StatusCode != http.StatusBadRequest || res.Response != "nope" {
+		t.Fatalf("expected the endpoint's rejection, got %+v", res)
+	}
+
+	ts, err := strconv.ParseInt(header.Get("X-Relay-Timestamp"), 10, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !signature.Verify(body, ep.Secret, ts, header.Get("X-Relay-Signature")) {
+		t.Fatalf("test send is not signed with the endpoint secret: %s", body)
+	}
+
+	// Recorded as a completed test delivery, outside the queue and the DLQ.
+	d, err := s.GetDelivery(ctx(), res.DeliveryID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.Test || d.State != delivery.StateFailed || d.CompletedAt == nil {
+		t.Fatalf("expected a completed test delivery, got %+v", d)
+	}
+	if n, _ := s.CountPending(ctx()); n != 0 {
+		t.Fatalf("expected no pending deliveries, got %d", n)
+	}
+	if n, _ := s.CountDLQ(ctx(), dlq.ListOpts{}); n != 0 {
+		t.Fatalf("expected an empty DLQ, got %d", n)
+	}
+
+	// The event is marked as a test and cannot be resent.
+	evt, err := s.GetEvent(ctx(), res.EventID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !evt.Test {
+		t.Fatalf("expected a test event, got %+v", evt)
+	}
+	if _, err := r.Resend(ctx(), evt.ID); !errors.Is(err, relay.ErrTestEvent) {
+		t.Fatalf("expected ErrTestEvent, got %v", err)
+	}
+
+	// No example and no payload.
+	if _, err := r.SendTest(ctx(), ep.ID, "invoice.paid", nil); !errors.Is(err, relay.ErrNoTestPayload) {
+		t.Fatalf("expected ErrNoTestPayload, got %v", err)
+	}
+
+	// An explicit payload is used instead.
+	if _, err := r.SendTest(ctx(), ep.ID, "invoice.paid", mustJSON(map[string]any{"paid": true})); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(body, []byte(`"paid":true`)) {
+		t.Fatalf("unexpected payload: %s", body)
+	}
+}
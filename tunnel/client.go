@@ -0,0 +1,296 @@
+package tunnel
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	maxForwardedBody = 64 << 10 // cap on local response bodies sent back
+	maxEventSize     = 16 << 20 // cap on a single stream event
+	maxReconnectWait = 10 * time.Second
+)
+
+// Forwarded describes one delivery forwarded to the local server.
+type Forwarded struct {
+	Request  Request
+	Response Response
+	Latency  time.Duration
+}
+
+// Client connects to the tunnel API of a relay admin API and forwards a
+// session's deliveries to a local URL.
+type Client struct {
+	// BaseURL is where the admin API is mounted, e.g.
+	// https://app.example.com/webhooks.
+	BaseURL string
+
+	// Header is sent with every admin API request, e.g. for authentication.
+	Header http.Header
+
+	// HTTPClient talks to the admin API. Its Timeout is not applied to the
+	// event stream. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+
+	// Forward is the local URL deliveries are POSTed to.
+	Forward string
+
+	// ForwardClient POSTs to Forward. Defaults to a client with a 30
+	// second timeout.
+	ForwardClient *http.Client
+
+	// OnForward, when set, is called after every forwarded delivery. It
+	// may be called concurrently.
+	OnForward func(Forwarded)
+
+	// OnConnect, when set, is called each time the event stream
+	// (re)connects and deliveries start flowing.
+	OnConnect func(Session)
+}
+
+// Open opens a session for tenantID subscribed to eventTypes.
+func (c *Client) Open(ctx context.Context, tenantID string, eventTypes []string) (*Session, error) {
+	var s Session
+	body := map[string]any{"tenant_id": tenantID, "event_types": eventTypes}
+	if err := c.do(ctx, http.MethodPost, "/tunnel/sessions", body, &s); err != nil {
+		if e := (*apiError)(nil); errors.As(err, &e) && e.status == http.StatusNotFound {
+			return nil, errors.New("tunnel: tunnels are not enabled on this relay")
+		}
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Close closes a session and deletes its endpoint.
+func (c *Client) Close(ctx context.Context, sessionID string) error {
+	return c.do(ctx, http.MethodDelete, "/tunnel/sessions/"+url.PathEscape(sessionID), nil, nil)
+}
+
+// Listen forwards the session's deliveries until ctx is done, reconnecting
+// when the stream drops. It returns nil when ctx is done, and
+// ErrSessionNotFound or ErrSessionClosed when the session ends on the
+// relay's side.
+func (c *Client) Listen(ctx context.Context, sessionID string) error {
+	wait := time.Second
+	for {
+		connected, err := c.stream(ctx, sessionID)
+		if ctx.Err() != nil {
+			return nil //nolint:nilerr // cancellation ends listening
+		}
+		if errors.Is(err, ErrSessionNotFound) || errors.Is(err, ErrSessionClosed) {
+			return err
+		}
+		if connected {
+			wait = time.Second
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(wait):
+		}
+		wait = min(wait*2, maxReconnectWait)
+	}
+}
+
+// stream reads one connection of the event stream. connected reports
+// whether the relay accepted the connection.
+func (c *Client) stream(ctx context.Context, sessionID string) (connected bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url("/tunnel/sessions/"+url.PathEscape(sessionID)+"/stream"), nil)
+	if err != nil {
+		return false, err
+	}
+	c.setHeader(req)
+	req.Header.Set("Accept", "text/event-stream")
+
+	// The stream is long-lived: keep the transport, drop the timeout.
+	resp, err := (&http.Client{Transport: c.httpClient().Transport}).Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return false, ErrSessionNotFound
+	}
+	if err := checkResponse(resp); err != nil {
+		return false, err
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64<<10), maxEventSize)
+
+	var event, data string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			switch event {
+			case "closed":
+				return true, ErrSessionClosed
+			case "ready":
+				var ready Session
+				if err := json.Unmarshal([]byte(data), &ready); err != nil {
+					return true, fmt.Errorf("tunnel: decode session: %w", err)
+				}
+				if c.OnConnect != nil {
+					c.OnConnect(ready)
+				}
+			case "delivery":
+				var fwd Request
+				if err := json.Unmarshal([]byte(data), &fwd); err != nil {
+					return true, fmt.Errorf("tunnel: decode delivery: %w", err)
+				}
+				go c.forward(ctx, sessionID, fwd)
+			}
+			event, data = "", ""
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data += strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " ")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return true, err
+	}
+	return true, io.ErrUnexpectedEOF
+}
+
+// forward POSTs a delivery to the local server and reports the result.
+func (c *Client) forward(ctx context.Context, sessionID string, fwd Request) {
+	start := time.Now()
+	resp := c.post(ctx, fwd)
+	latency := time.Since(start)
+
+	path := "/tunnel/sessions/" + url.PathEscape(sessionID) + "/responses"
+	if err := c.do(ctx, http.MethodPost, path, resp, nil); err != nil && ctx.Err() == nil {
+		// The attempt has timed out on the relay, which retries it.
+		resp.Error = fmt.Sprintf("report result: %v", err)
+	}
+
+	if c.OnForward != nil {
+		c.OnForward(Forwarded{Request: fwd, Response: resp, Latency: latency})
+	}
+}
+
+func (c *Client) post(ctx context.Context, fwd Request) Response {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.Forward, strings.NewReader(fwd.Body))
+	if err != nil {
+		return Response{ID: fwd.ID, Error: err.Error()}
+	}
+	for k, v := range fwd.Header {
+		req.Header[k] = v
+	}
+
+	client := c.ForwardClient
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Response{ID: fwd.ID, Error: err.Error()}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxForwardedBody))
+	if err != nil {
+		return Response{ID: fwd.ID, StatusCode: resp.StatusCode, Error: fmt.Sprintf("read response: %v", err)}
+	}
+	return Response{
+		ID:         fwd.ID,
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       string(body),
+	}
+}
+
+// do sends an admin API request and decodes a JSON response into out, if
+// non-nil.
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.url(path), reader)
+	if err != nil {
+		return err
+	}
+	c.setHeader(req)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp); err != nil {
+		return err
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (c *Client) url(path string) string {
+	return strings.TrimRight(c.BaseURL, "/") + path
+}
+
+func (c *Client) setHeader(req *http.Request) {
+	for k, v := range c.Header {
+		req.Header[k] = v
+	}
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// apiError is a non-2xx admin API response.
+type apiError struct {
+	status  int
+	message string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("tunnel: admin API: %d %s", e.status, e.message)
+}
+
+// checkResponse turns a non-2xx admin API response into an *apiError.
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode < 300 {
+		return nil
+	}
+	var e struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+	msg := strings.TrimSpace(string(data))
+	if json.Unmarshal(data, &e) == nil {
+		if e.Error != "" {
+			msg = e.Error
+		} else if e.Message != "" {
+			msg = e.Message
+		}
+	}
+	return &apiError{status: resp.StatusCode, message: msg}
+}
@@ -0,0 +1,425 @@
+package tunnel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	log "github.com/xraph/go-utils/log"
+
+	"github.com/xraph/relay/endpoint"
+	"github.com/xraph/relay/id"
+)
+
+// DefaultIdleTimeout is how long a session survives without a connected
+// listener before it is closed and its endpoint deleted.
+const DefaultIdleTimeout = 2 * time.Minute
+
+const (
+	heartbeatInterval = 15 * time.Second
+	queueSize         = 64
+
+	// metadataKey marks tunnel endpoints with their session ID.
+	metadataKey = "relay_tunnel_session"
+)
+
+// Hub holds the tunnel sessions of an instance and carries their
+// deliveries. It is safe for concurrent use.
+type Hub struct {
+	endpoints   *endpoint.Service
+	logger      log.Logger
+	idleTimeout time.Duration
+
+	seq atomic.Uint64
+
+	mu       sync.Mutex
+	sessions map[string]*session
+}
+
+type session struct {
+	Session
+	endpointID id.ID
+
+	queue     chan *Request
+	pending   map[string]chan Response
+	listeners int
+	idle      *time.Timer
+	done      chan struct{}
+}
+
+// NewHub creates a Hub that registers session endpoints through endpoints.
+func NewHub(endpoints *endpoint.Service, logger log.Logger) *Hub {
+	if logger == nil {
+		logger = log.NewNoopLogger()
+	}
+	return &Hub{
+		endpoints:   endpoints,
+		logger:      logger,
+		idleTimeout: DefaultIdleTimeout,
+		sessions:    make(map[string]*session),
+	}
+}
+
+// Open registers a temporary endpoint for tenantID subscribed to
+// eventTypes and returns the session, including the endpoint's signing
+// secret. The session is closed if no listener connects within the idle
+// timeout.
+func (h *Hub) Open(ctx context.Context, tenantID string, eventTypes []string) (*Session, error) {
+	h.removeStale(ctx, tenantID)
+
+	sid := id.NewTunnelID().String()
+	ep, err := h.endpoints.Create(ctx, endpoint.Input{
+		TenantID:    tenantID,
+		URL:         Scheme + "://" + sid,
+		Description: "Development tunnel",
+		EventTypes:  eventTypes,
+		Metadata:    map[string]string{metadataKey: sid},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s := &session{
+		Session: Session{
+			ID:         sid,
+			EndpointID: ep.ID.String(),
+			TenantID:   ep.TenantID,
+			EventTypes: ep.EventTypes,
+			CreatedAt:  ep.CreatedAt,
+			Secret:     ep.Secret,
+		},
+		endpointID: ep.ID,
+		queue:      make(chan *Request, queueSize),
+		pending:    make(map[string]chan Response),
+		done:       make(chan struct{}),
+	}
+
+	h.mu.Lock()
+	s.idle = time.AfterFunc(h.idleTimeout, func() { h.expire(sid) })
+	h.sessions[sid] = s
+	h.mu.Unlock()
+
+	h.logger.Info("tunnel session opened",
+		log.String("session_id", sid),
+		log.String("tenant_id", tenantID),
+		log.String("endpoint_id", s.EndpointID),
+	)
+
+	out := s.Session
+	return &out, nil
+}
+
+// Tenant returns the tenant a session was opened for.
+func (h *Hub) Tenant(sessionID string) (string, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.sessions[sessionID]
+	if !ok {
+		return "", ErrSessionNotFound
+	}
+	return s.TenantID, nil
+}
+
+// Close ends a session and deletes its endpoint. Pending deliveries fail
+// and are retried per the endpoint's schedule until the endpoint is gone.
+func (h *Hub) Close(ctx context.Context, sessionID string) error {
+	h.mu.Lock()
+	s, ok := h.sessions[sessionID]
+	if ok {
+		delete(h.sessions, sessionID)
+		s.idle.Stop()
+		close(s.done)
+	}
+	h.mu.Unlock()
+	if !ok {
+		return ErrSessionNotFound
+	}
+
+	h.logger.Info("tunnel session closed", log.String("session_id", sessionID))
+
+	if err := h.endpoints.Delete(ctx, s.endpointID); err != nil {
+		return fmt.Errorf("tunnel: delete endpoint %s: %w", s.EndpointID, err)
+	}
+	return nil
+}
+
+// expire closes a session that has had no listener for the idle timeout.
+func (h *Hub) expire(sessionID string) {
+	h.mu.Lock()
+	s, ok := h.sessions[sessionID]
+	idle := ok && s.listeners == 0
+	h.mu.Unlock()
+	if !idle {
+		return
+	}
+
+	if err := h.Close(context.Background(), sessionID); err != nil && !errors.Is(err, ErrSessionNotFound) {
+		h.logger.Warn("tunnel: close idle session",
+			log.String("session_id", sessionID),
+			log.Any("error", err),
+		)
+	}
+}
+
+// removeStale deletes the tenant's tunnel endpoints left behind by
+// sessions that no longer exist. Deliveries to those endpoints are
+// answered with 410 Gone, so the engine disables them; enabled ones may
+// belong to another instance and are left alone.
+func (h *Hub) removeStale(ctx context.Context, tenantID string) {
+	disabled := false
+	eps, err := h.endpoints.List(ctx, tenantID, endpoint.ListOpts{Enabled: &disabled})
+	if err != nil {
+		h.logger.Warn("tunnel: list stale endpoints", log.Any("error", err))
+		return
+	}
+
+	for _, ep := range eps {
+		sid, ok := sessionID(ep.URL)
+		if !ok {
+			continue
+		}
+		h.mu.Lock()
+		_, live := h.sessions[sid]
+		h.mu.Unlock()
+		if live {
+			continue
+		}
+		if err := h.endpoints.Delete(ctx, ep.ID); err != nil {
+			h.logger.Warn("tunnel: delete stale endpoint",
+				log.String("endpoint_id", ep.ID.String()),
+				log.Any("error", err),
+			)
+		}
+	}
+}
+
+// Stream sends the session's deliveries to w as server-sent events until
+// ctx is done or the session is closed. It writes a "ready" event with the
+// session once connected, a "delivery" event with a Request for every
+// delivery attempt, and a "closed" event if the session is closed.
+func (h *Hub) Stream(ctx context.Context, sessionID string, w http.ResponseWriter) error {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return errors.New("tunnel: response writer does not support streaming")
+	}
+
+	h.mu.Lock()
+	s, ok := h.sessions[sessionID]
+	if !ok {
+		h.mu.Unlock()
+		return ErrSessionNotFound
+	}
+	s.listeners++
+	s.idle.Stop()
+	h.mu.Unlock()
+
+	defer func() {
+		h.mu.Lock()
+		s.listeners--
+		if s.listeners == 0 {
+			s.idle.Reset(h.idleTimeout)
+		}
+		h.mu.Unlock()
+	}()
+
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	ready := s.Session
+	ready.Secret = ""
+	if err := writeEvent(w, "ready", ready); err != nil {
+		return err
+	}
+	flusher.Flush()
+
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case <-s.done:
+			err := writeEvent(w, "closed", struct{}{})
+			flusher.Flush()
+			return err
+
+		case <-ticker.C:
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return err
+			}
+			flusher.Flush()
+
+		case req := <-s.queue:
+			if !h.waiting(s, req.ID) {
+				continue // the engine gave up on this attempt
+			}
+			if err := writeEvent(w, "delivery", req); err != nil {
+				h.resolve(s, Response{ID: req.ID, Error: "tunnel listener disconnected"})
+				return err
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// Respond reports the listener's result for a forwarded Request. It
+// returns ErrUnknownRequest if the attempt already timed out.
+func (h *Hub) Respond(sessionID string, resp Response) error {
+	h.mu.Lock()
+	s, ok := h.sessions[sessionID]
+	h.mu.Unlock()
+	if !ok {
+		return ErrSessionNotFound
+	}
+	if !h.resolve(s, resp) {
+		return ErrUnknownRequest
+	}
+	return nil
+}
+
+func (h *Hub) resolve(s *session, resp Response) bool {
+	h.mu.Lock()
+	ch, ok := s.pending[resp.ID]
+	delete(s.pending, resp.ID)
+	h.mu.Unlock()
+	if ok {
+		ch <- resp // buffered; only ever sent once
+	}
+	return ok
+}
+
+func (h *Hub) waiting(s *session, reqID string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, ok := s.pending[reqID]
+	return ok
+}
+
+// Transport returns a RoundTripper that carries tunnel:// requests through
+// the hub and everything else through next (http.DefaultTransport if nil).
+// Use it as delivery.EngineConfig.Transport.
+func (h *Hub) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &transport{hub: h, next: next}
+}
+
+type transport struct {
+	hub  *Hub
+	next http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme != Scheme {
+		return t.next.RoundTrip(req)
+	}
+	return t.hub.roundTrip(req)
+}
+
+// roundTrip hands req to the session's listener and waits for its result.
+// A missing session answers 410 Gone so the engine disables the endpoint;
+// a missing listener fails the attempt so it is retried.
+func (h *Hub) roundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	h.mu.Lock()
+	s, ok := h.sessions[req.URL.Host]
+	if !ok {
+		h.mu.Unlock()
+		return newResponse(req, http.StatusGone, http.Header{"Content-Type": {"text/plain; charset=utf-8"}}, "tunnel session not found"), nil
+	}
+	if s.listeners == 0 {
+		h.mu.Unlock()
+		return nil, ErrNotConnected
+	}
+	fwd := &Request{
+		ID:     strconv.FormatUint(h.seq.Add(1), 10),
+		Header: req.Header.Clone(),
+		Body:   string(body),
+	}
+	ch := make(chan Response, 1)
+	s.pending[fwd.ID] = ch
+	h.mu.Unlock()
+
+	defer func() {
+		h.mu.Lock()
+		delete(s.pending, fwd.ID)
+		h.mu.Unlock()
+	}()
+
+	ctx := req.Context()
+	select {
+	case s.queue <- fwd:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-s.done:
+		return nil, ErrSessionClosed
+	}
+
+	select {
+	case resp := <-ch:
+		if resp.Error != "" {
+			return nil, fmt.Errorf("tunnel: %s", resp.Error)
+		}
+		return newResponse(req, resp.StatusCode, resp.Header, resp.Body), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-s.done:
+		return nil, ErrSessionClosed
+	}
+}
+
+func newResponse(req *http.Request, status int, header http.Header, body string) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// sessionID returns the session of a tunnel endpoint URL.
+func sessionID(rawURL string) (string, bool) {
+	rest, ok := strings.CutPrefix(rawURL, Scheme+"://")
+	return rest, ok && rest != ""
+}
+
+func writeEvent(w io.Writer, event string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+	return err
+}
@@ -0,0 +1,63 @@
+// Package tunnel forwards webhook deliveries to a developer's machine.
+//
+// A tunnel session owns a temporary endpoint whose URL is
+// tunnel://<session-id>. The delivery engine signs and sends to it like any
+// other endpoint, but the Hub's transport hands the request to a listener
+// connected over a long-lived event stream instead of dialing out. The
+// listener (Client, or "relay listen") re-POSTs the request to a local URL
+// and reports the local response back, which becomes the delivery result,
+// so retries, the DLQ and the delivery log behave as in production.
+//
+// Sessions live in the memory of the instance that opened them: the
+// instance serving the tunnel API must also run the delivery engine. Do not
+// enable tunnels on production instances.
+package tunnel
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+// Scheme is the URL scheme of tunnel endpoints.
+const Scheme = "tunnel"
+
+// Errors returned by the Hub and the Client.
+var (
+	ErrSessionNotFound = errors.New("tunnel: session not found")
+	ErrNotConnected    = errors.New("tunnel: no listener connected")
+	ErrUnknownRequest  = errors.New("tunnel: unknown or expired request")
+	ErrSessionClosed   = errors.New("tunnel: session closed")
+)
+
+// Session is an open tunnel.
+type Session struct {
+	ID         string    `json:"id"`
+	EndpointID string    `json:"endpoint_id"`
+	TenantID   string    `json:"tenant_id"`
+	EventTypes []string  `json:"event_types"`
+	CreatedAt  time.Time `json:"created_at"`
+
+	// Secret signs the session's deliveries. It is only returned when the
+	// session is opened.
+	Secret string `json:"secret,omitempty"`
+}
+
+// Request is a delivery forwarded to the listener. Header carries the
+// signed X-Relay-* headers and Body the exact signed payload.
+type Request struct {
+	ID     string      `json:"id"`
+	Header http.Header `json:"header"`
+	Body   string      `json:"body"`
+}
+
+// Response is the listener's result for a Request. Error reports that the
+// local server could not be reached; the attempt then fails like a
+// connection error and is retried.
+type Response struct {
+	ID         string      `json:"id"`
+	StatusCode int         `json:"status_code,omitempty"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	Error      string      `json:"error,omitempty"`
+}
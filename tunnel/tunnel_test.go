@@ -0,0 +1,221 @@
+package tunnel_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/xraph/relay"
+	"github.com/xraph/relay/api"
+	"github.com/xraph/relay/catalog"
+	"github.com/xraph/relay/delivery"
+	"github.com/xraph/relay/dlq"
+	"github.com/xraph/relay/endpoint"
+	"github.com/xraph/relay/event"
+	"github.com/xraph/relay/id"
+	"github.com/xraph/relay/signature"
+	"github.com/xraph/relay/store/memory"
+	"github.com/xraph/relay/tunnel"
+)
+
+// received is a request seen by the local server.
+type received struct {
+	header http.Header
+	body   []byte
+}
+
+// setup starts a relay with the tunnel enabled, its admin API and a local
+// server answering with status, and returns a client forwarding to it.
+func setup(t *testing.T, status int) (*relay.Relay, *tunnel.Client, <-chan received) {
+	t.Helper()
+
+	s := memory.New()
+	r, err := relay.New(relay.WithStore(s), relay.WithTunnel(), relay.WithPollInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.RegisterEventType(context.Background(), catalog.WebhookDefinition{Name: "invoice.created"}); err != nil {
+		t.Fatal(err)
+	}
+
+	adminAPI := httptest.NewServer(api.NewHandler(s, r.Catalog(), r.Endpoints(), r.DLQ(), nil, api.WithRelay(r)))
+	t.Cleanup(adminAPI.Close)
+
+	got := make(chan received, 10)
+	local := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		got <- received{header: req.Header.Clone(), body: body}
+		w.WriteHeader(status)
+		io.WriteString(w, "local says hi") //nolint:errcheck // test server
+	}))
+	t.Cleanup(local.Close)
+
+	return r, &tunnel.Client{BaseURL: adminAPI.URL, Forward: local.URL + "/hooks"}, got
+}
+
+// listen opens a session and forwards its deliveries until the test ends.
+func listen(t *testing.T, r *relay.Relay, c *tunnel.Client) *tunnel.Session {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	session, err := c.Open(ctx, "acme", []string{"invoice.*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	connected := make(chan struct{}, 1)
+	c.OnConnect = func(tunnel.Session) { connected <- struct{}{} }
+
+	done := make(chan error, 1)
+	go func() { done <- c.Listen(ctx, session.ID) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Listen: %v", err)
+		}
+	})
+
+	// Wait for the stream so the first attempt finds a listener.
+	select {
+	case <-connected:
+	case <-time.After(3 * time.Second):
+		t.Fatal("listener did not connect")
+	}
+
+	r.Start(context.Background())
+	t.Cleanup(func() { r.Stop(context.Background()) })
+	return session
+}
+
+func send(t *testing.T, r *relay.Relay) {
+	t.Helper()
+	if err := r.Send(context.Background(), &event.Event{
+		Type:     "invoice.created",
+		TenantID: "acme",
+		Data:     map[string]any{"total": 5},
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func waitReceived(t *testing.T, got <-chan received) received {
+	t.Helper()
+	select {
+	case rec := <-got:
+		return rec
+	case <-time.After(3 * time.Second):
+		t.Fatal("delivery not forwarded")
+		return received{}
+	}
+}
+
+func waitDelivery(t *testing.T, r *relay.Relay, epID string, done func(*delivery.Delivery) bool) *delivery.Delivery {
+	t.Helper()
+	eid, err := id.ParseEndpointID(epID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		ds, err := r.Store().ListByEndpoint(context.Background(), eid, delivery.ListOpts{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ds) == 1 && done(ds[0]) {
+			return ds[0]
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("delivery did not complete: %+v", ds)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestTunnelDelivers(t *testing.T) {
+	r, c, got := setup(t, http.StatusOK)
+	session := listen(t, r, c)
+	if session.Secret == "" || session.EndpointID == "" {
+		t.Fatalf("expected endpoint and secret, got %+v", session)
+	}
+
+	send(t, r)
+	rec := waitReceived(t, got)
+
+	ts, err := strconv.ParseInt(rec.header.Get("X-Relay-Timestamp"), 10, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !signature.Verify(rec.body, session.Secret, ts, rec.header.Get("X-Relay-Signature")) {
+		t.Fatalf("signature does not verify with the session secret: %s", rec.body)
+	}
+	if rec.header.Get("X-Relay-Event-Type") != "invoice.created" {
+		t.Fatalf("unexpected headers: %v", rec.header)
+	}
+
+	d := waitDelivery(t, r, session.EndpointID, func(d *delivery.Delivery) bool { return d.State == delivery.StateDelivered })
+	if d.LastStatusCode != http.StatusOK || d.LastResponse != "local says hi" {
+		t.Fatalf("expected the local response as the result, got %d %q", d.LastStatusCode, d.LastResponse)
+	}
+}
+
+func TestTunnelFailureGoesToDLQ(t *testing.T) {
+	r, c, got := setup(t, http.StatusBadRequest)
+	session := listen(t, r, c)
+
+	send(t, r)
+	waitReceived(t, got)
+
+	waitDelivery(t, r, session.EndpointID, func(d *delivery.Delivery) bool { return d.State == delivery.StateFailed })
+	n, err := r.Store().CountDLQ(context.Background(), dlq.ListOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("expected the rejected delivery in the DLQ, got %d entries", n)
+	}
+}
+
+func TestTunnelTransport(t *testing.T) {
+	r, _, _ := setup(t, http.StatusOK)
+	hub := r.Tunnel()
+	rt := hub.Transport(nil)
+
+	session, err := hub.Open(context.Background(), "acme", []string{"*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, "tunnel://"+session.ID, nil)
+	if _, err := rt.RoundTrip(req); !errors.Is(err, tunnel.ErrNotConnected) {
+		t.Fatalf("expected ErrNotConnected without a listener, got %v", err)
+	}
+
+	if err := hub.Close(context.Background(), session.ID); err != nil {
+		t.Fatal(err)
+	}
+	eps, err := r.Endpoints().List(context.Background(), "acme", endpoint.ListOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(eps) != 0 {
+		t.Fatalf("expected the session endpoint to be deleted, got %d endpoints", len(eps))
+	}
+
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusGone {
+		t.Fatalf("expected 410 for a closed session, got %d", resp.StatusCode)
+	}
+
+	if err := hub.Respond(session.ID, tunnel.Response{ID: "1"}); !errors.Is(err, tunnel.ErrSessionNotFound) {
+		t.Fatalf("expected ErrSessionNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package relay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/xraph/relay/catalog"
+	"github.com/xraph/relay/endpoint"
+	"github.com/xraph/relay/event"
+)
+
+// RegisterConverter registers a function converting eventType payloads from
+// version from to version to. Endpoints pinned to a version other than the
+// one an event was sent in receive the payload converted through a chain of
+// registered converters.
+func (r *Relay) RegisterConverter(eventType, from, to string, fn catalog.ConverterFunc) {
+	r.catalog.RegisterConverter(eventType, from, to, fn)
+}
+
+// versionConverter implements delivery.PayloadConverter on top of the
+// catalog.
+type versionConverter struct {
+	catalog *catalog.Catalog
+}
+
+func (c versionConverter) ConvertFor(ctx context.Context, evt *event.Event, ep *endpoint.Endpoint) (*event.Event, error) {
+	if evt.Version == "" {
+		return evt, nil
+	}
+
+	et, err := c.catalog.GetType(ctx, evt.Type)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrEventTypeNotFound, evt.Type)
+	}
+
+	target := et.ResolveVersion(ep.Version)
+	if target == evt.Version {
+		return evt, nil
+	}
+
+	raw, err := json.Marshal(evt.Data)
+	if err != nil {
+		return nil, fmt.Errorf("marshal payload: %w", err)
+	}
+	converted, err := c.catalog.Convert(ctx, evt.Type, raw, evt.Version, target)
+	if err != nil {
+		return nil, err
+	}
+
+	out := *evt
+	out.Data = json.RawMessage(converted)
+	out.Version = target
+	return &out, nil
+}
@@ -0,0 +1,111 @@
+package relay_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/xraph/relay"
+	"github.com/xraph/relay/catalog"
+	"github.com/xraph/relay/endpoint"
+	"github.com/xraph/relay/event"
+	"github.com/xraph/relay/store/memory"
+)
+
+func registerVersions(t *testing.T, r *relay.Relay) {
+	t.Helper()
+	for _, def := range []catalog.WebhookDefinition{
+		{Name: "invoice.created", Version: "2024-06-01", Schema: json.RawMessage(`{"type":"object","required":["amount"]}`)},
+		{Name: "invoice.created", Version: "2025-01-01", Schema: json.RawMessage(`{"type":"object","required":["total"]}`)},
+	} {
+		if _, err := r.RegisterEventType(ctx(), def); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSendVersionValidation(t *testing.T) {
+	r, _ := setup(t)
+	registerVersions(t, r)
+
+	// Defaults to the latest version and validates against its schema.
+	evt := &event.Event{Type: "invoice.created", TenantID: "t1", Data: map[string]any{"total": 5}}
+	if err := r.Send(ctx(), evt); err != nil {
+		t.Fatal(err)
+	}
+	if evt.Version != "2025-01-01" {
+		t.Fatalf("expected latest version, got %q", evt.Version)
+	}
+
+	// An explicit older version uses that version's schema.
+	err := r.Send(ctx(), &event.Event{Type: "invoice.created", TenantID: "t1", Version: "2024-06-01", Data: map[string]any{"total": 5}})
+	if !errors.Is(err, relay.ErrPayloadValidationFailed) {
+		t.Fatalf("expected validation failure against old schema, got %v", err)
+	}
+
+	err = r.Send(ctx(), &event.Event{Type: "invoice.created", TenantID: "t1", Version: "1999-01-01", Data: map[string]any{}})
+	if !errors.Is(err, relay.ErrEventVersionNotFound) {
+		t.Fatalf("expected ErrEventVersionNotFound, got %v", err)
+	}
+}
+
+func TestDeliveryConvertsToPinnedVersion(t *testing.T) {
+	type received struct {
+		version string
+		body    string
+	}
+	got := make(chan received, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		got <- received{req.Header.Get("X-Relay-Event-Version"), string(body)}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	r, err := relay.New(relay.WithStore(memory.New()), relay.WithPollInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	registerVersions(t, r)
+	r.RegisterConverter("invoice.created", "2025-01-01", "2024-06-01", func(_ context.Context, data json.RawMessage) (json.RawMessage, error) {
+		var m map[string]any
+		if err := json.Unmarshal(data, &m); err != nil {
+			return nil, err
+		}
+		return json.Marshal(map[string]any{"amount": m["total"]})
+	})
+
+	pinned := "2024-12-31"
+	if _, err := r.Endpoints().Create(ctx(), endpoint.Input{
+		TenantID:   "t1",
+		URL:        srv.URL,
+		EventTypes: []string{"invoice.*"},
+		Version:    &pinned,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	r.Start(ctx())
+	defer r.Stop(ctx())
+
+	if err := r.Send(ctx(), &event.Event{Type: "invoice.created", TenantID: "t1", Data: map[string]any{"total": 5}}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case rcv := <-got:
+		if rcv.version != "2024-06-01" {
+			t.Fatalf("expected version header 2024-06-01, got %q", rcv.version)
+		}
+		if rcv.body != `{"amount":5}` {
+			t.Fatalf("expected converted payload, got %s", rcv.body)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("delivery not received")
+	}
+}